@@ -21,6 +21,7 @@ import (
 
 	"github.com/GoogleContainerTools/kpt/internal/cmdrepoget"
 	"github.com/GoogleContainerTools/kpt/internal/cmdreporeg"
+	"github.com/GoogleContainerTools/kpt/internal/cmdreposync"
 	"github.com/GoogleContainerTools/kpt/internal/cmdrepounreg"
 	"github.com/GoogleContainerTools/kpt/internal/util/porch"
 	"github.com/spf13/cobra"
@@ -58,11 +59,22 @@ func NewRepoCommand(ctx context.Context, version string) *cobra.Command {
 	}
 
 	pf.AddGoFlagSet(flag.CommandLine)
+	porch.AddOutputFlag(pf)
+
+	// Resolve namespace (and repository) not given by flags from the
+	// configuration file and environment variables.
+	repo.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := porch.ValidateOutput(cmd); err != nil {
+			return err
+		}
+		return porch.ApplyDefaults(cmd, kubeflags)
+	}
 
 	repo.AddCommand(
 		cmdreporeg.NewCommand(ctx, kubeflags),
 		cmdrepoget.NewCommand(ctx, kubeflags),
 		cmdrepounreg.NewCommand(ctx, kubeflags),
+		cmdreposync.NewCommand(ctx, kubeflags),
 	)
 
 	return repo
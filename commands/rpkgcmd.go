@@ -24,10 +24,12 @@ import (
 	"github.com/GoogleContainerTools/kpt/internal/cmdrpkgdel"
 	"github.com/GoogleContainerTools/kpt/internal/cmdrpkgget"
 	"github.com/GoogleContainerTools/kpt/internal/cmdrpkginit"
+	"github.com/GoogleContainerTools/kpt/internal/cmdrpkglist"
 	"github.com/GoogleContainerTools/kpt/internal/cmdrpkgpropose"
 	"github.com/GoogleContainerTools/kpt/internal/cmdrpkgpull"
 	"github.com/GoogleContainerTools/kpt/internal/cmdrpkgpush"
 	"github.com/GoogleContainerTools/kpt/internal/cmdrpkgreject"
+	"github.com/GoogleContainerTools/kpt/internal/cmdrpkgwatch"
 	"github.com/GoogleContainerTools/kpt/internal/util/porch"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -64,9 +66,21 @@ func NewRpkgCommand(ctx context.Context, version string) *cobra.Command {
 	}
 
 	pf.AddGoFlagSet(flag.CommandLine)
+	porch.AddOutputFlag(pf)
+	pf.String(porch.RepositoryFlag, "", "Repository of the packages to operate on. Defaults to the value from the configuration file or the "+porch.RepositoryEnv+" environment variable.")
+
+	// Resolve namespace (and repository) not given by flags from the
+	// configuration file and environment variables.
+	repo.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := porch.ValidateOutput(cmd); err != nil {
+			return err
+		}
+		return porch.ApplyDefaults(cmd, kubeflags)
+	}
 
 	repo.AddCommand(
 		cmdrpkgget.NewCommand(ctx, kubeflags),
+		cmdrpkglist.NewCommand(ctx, kubeflags),
 		cmdrpkgpull.NewCommand(ctx, kubeflags),
 		cmdrpkgpush.NewCommand(ctx, kubeflags),
 		cmdrpkgclone.NewCommand(ctx, kubeflags),
@@ -75,6 +89,7 @@ func NewRpkgCommand(ctx context.Context, version string) *cobra.Command {
 		cmdrpkgapprove.NewCommand(ctx, kubeflags),
 		cmdrpkgreject.NewCommand(ctx, kubeflags),
 		cmdrpkgdel.NewCommand(ctx, kubeflags),
+		cmdrpkgwatch.NewCommand(ctx, kubeflags),
 	)
 
 	return repo
@@ -58,6 +58,12 @@ func NewSyncCommand(ctx context.Context, version string) *cobra.Command {
 
 	pf.AddGoFlagSet(flag.CommandLine)
 
+	// Resolve namespace (and repository) not given by flags from the
+	// configuration file and environment variables.
+	sync.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return porch.ApplyDefaults(cmd, kubeflags)
+	}
+
 	sync.AddCommand(
 		create.NewCommand(ctx, kubeflags),
 		get.NewCommand(ctx, kubeflags),
@@ -0,0 +1,219 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdfnrun
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
+	"github.com/GoogleContainerTools/kpt/internal/printer"
+	"github.com/GoogleContainerTools/kpt/internal/types"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	fnresult "github.com/GoogleContainerTools/kpt/pkg/api/fnresult/v1"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	command = "cmdfnrun"
+	longMsg = `
+kpt alpha function run IMAGE --input-dir DIR --output-dir DIR [--config KEY=VALUE...]
+
+Runs a single KRM function against a local package directory, without a Package Orchestrator.
+
+Args:
+
+IMAGE:
+  Container image of the function to run.
+
+Flags:
+
+--input-dir
+  Directory containing the package resources to pass to the function.
+
+--output-dir
+  Directory to write the resources returned by the function to.
+
+--config
+  Key-value pair to include in the function config ConfigMap. Can be repeated.
+
+--runtime
+  Container runtime used to run the function, one of docker or podman.
+
+`
+)
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	return newRunner(ctx).Command
+}
+
+func newRunner(ctx context.Context) *runner {
+	r := &runner{
+		ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:     "run IMAGE",
+		Short:   "Runs a KRM function against a local package.",
+		Long:    longMsg,
+		Example: "kpt alpha function run gcr.io/kpt-fn/set-namespace:v0.2.0 --input-dir ./package --output-dir ./out --config namespace=staging",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+		Hidden:  porch.HidePorchCommands,
+	}
+	r.Command = c
+	c.ValidArgsFunction = porch.FirstArg(porch.CompleteFunctionImages(ctx))
+
+	c.Flags().StringVar(&r.inputDir, "input-dir", "", "directory containing the input package.")
+	c.Flags().StringVar(&r.outputDir, "output-dir", "", "directory to write the function output to.")
+	c.Flags().StringArrayVar(&r.config, "config", []string{}, "key=value pair to include in the function config.")
+	c.Flags().StringVar(&r.runtime, "runtime", "docker", "container runtime to run the function with; one of docker, podman.")
+
+	return r
+}
+
+type runner struct {
+	ctx     context.Context
+	Command *cobra.Command
+
+	inputDir  string
+	outputDir string
+	config    []string
+	runtime   string
+}
+
+func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".preRunE"
+
+	if r.inputDir == "" {
+		return errors.E(op, "--input-dir is required")
+	}
+	if r.outputDir == "" {
+		return errors.E(op, "--output-dir is required")
+	}
+	switch r.runtime {
+	case "docker", "podman":
+	default:
+		return errors.E(op, fmt.Errorf("unsupported container runtime %q; must be one of docker, podman", r.runtime))
+	}
+	return nil
+}
+
+func (r *runner) runE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+
+	image := fnruntime.AddDefaultImagePathPrefix(args[0])
+
+	functionConfig, err := buildFunctionConfig(r.config)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	input := &bytes.Buffer{}
+	if err := (kio.Pipeline{
+		Inputs: []kio.Reader{
+			kio.LocalPackageReader{
+				PackagePath:       r.inputDir,
+				PreserveSeqIndent: true,
+				WrapBareSeqNode:   true,
+			},
+		},
+		Outputs: []kio.Writer{
+			kio.ByteWriter{
+				Writer:                input,
+				KeepReaderAnnotations: true,
+				WrappingKind:          kio.ResourceListKind,
+				WrappingAPIVersion:    kio.ResourceListAPIVersion,
+				FunctionConfig:        functionConfig,
+			},
+		},
+	}).Execute(); err != nil {
+		return errors.E(op, fmt.Errorf("cannot read package %q: %w", r.inputDir, err))
+	}
+
+	fn := &fnruntime.ContainerFn{
+		Ctx:      r.ctx,
+		Path:     types.UniquePath(r.inputDir),
+		Image:    image,
+		Runtime:  r.runtime,
+		FnResult: &fnresult.Result{Image: image},
+	}
+
+	output := &bytes.Buffer{}
+	if err := fn.Run(input, output); err != nil {
+		return errors.E(op, err)
+	}
+	if fn.FnResult.Stderr != "" {
+		printer.FromContextOrDie(r.ctx).Printf("%s\n", fn.FnResult.Stderr)
+	}
+
+	if err := os.MkdirAll(r.outputDir, 0755); err != nil {
+		return errors.E(op, err)
+	}
+
+	if err := (kio.Pipeline{
+		Inputs: []kio.Reader{
+			&kio.ByteReader{
+				Reader:            output,
+				PreserveSeqIndent: true,
+				WrapBareSeqNode:   true,
+			},
+		},
+		Outputs: []kio.Writer{
+			kio.LocalPackageWriter{
+				PackagePath: r.outputDir,
+			},
+		},
+	}).Execute(); err != nil {
+		return errors.E(op, fmt.Errorf("cannot write function output to %q: %w", r.outputDir, err))
+	}
+	return nil
+}
+
+// buildFunctionConfig creates a ConfigMap function config from the key=value
+// pairs provided on the command line.
+func buildFunctionConfig(config []string) (*yaml.RNode, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+
+	cm := yaml.MustParse(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: function-input
+data: {}
+`)
+	data, err := cm.Pipe(yaml.Lookup("data"))
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range config {
+		kv := strings.SplitN(c, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid --config value %q; must be in the KEY=VALUE format", c)
+		}
+		if err := data.PipeE(yaml.SetField(kv[0], yaml.NewStringRNode(kv[1]))); err != nil {
+			return nil, err
+		}
+	}
+	return cm, nil
+}
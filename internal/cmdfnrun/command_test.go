@@ -0,0 +1,103 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdfnrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuildFunctionConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		config  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "no config",
+		},
+		{
+			name:   "key-value pairs",
+			config: []string{"namespace=staging", "name=app"},
+			want:   map[string]string{"namespace": "staging", "name": "app"},
+		},
+		{
+			name:   "value containing separator",
+			config: []string{"selector=app=web"},
+			want:   map[string]string{"selector": "app=web"},
+		},
+		{
+			name:   "empty value",
+			config: []string{"namespace="},
+			want:   map[string]string{"namespace": ""},
+		},
+		{
+			name:    "missing separator",
+			config:  []string{"namespace"},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cm, err := buildFunctionConfig(tc.config)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("buildFunctionConfig succeeded; want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildFunctionConfig failed: %v", err)
+			}
+			if tc.want == nil {
+				if cm != nil {
+					t.Errorf("buildFunctionConfig returned %s; want no function config", cm.MustString())
+				}
+				return
+			}
+			if got, want := cm.GetKind(), "ConfigMap"; got != want {
+				t.Errorf("function config kind: got %q, want %q", got, want)
+			}
+			if diff := cmp.Diff(tc.want, cm.GetDataMap()); diff != "" {
+				t.Errorf("unexpected function config data (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCommandArgs(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "image", args: []string{"gcr.io/kpt-fn/set-namespace:v0.2.0"}},
+		{name: "no image", wantErr: true},
+		{name: "extra argument", args: []string{"gcr.io/kpt-fn/set-namespace:v0.2.0", "extra"}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewCommand(context.Background())
+			err := c.Args(c, tc.args)
+			if tc.wantErr && err == nil {
+				t.Errorf("Args(%q) succeeded; want error", tc.args)
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("Args(%q) failed: %v", tc.args, err)
+			}
+		})
+	}
+}
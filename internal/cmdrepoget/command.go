@@ -58,6 +58,7 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:  porch.HidePorchCommands,
 	}
 	r.Command = c
+	c.ValidArgsFunction = porch.FirstArg(porch.CompleteRepositories(ctx, rcg))
 
 	// Create flags
 	r.printFlags.AddFlags(c)
@@ -195,7 +195,7 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if err := apply(r.ctx, r.client, &configapi.Repository{
+	repo := &configapi.Repository{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Repository",
 			APIVersion: configapi.GroupVersion.Identifier(),
@@ -213,10 +213,16 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 			Git:         git,
 			Oci:         oci,
 		},
-	}); err != nil {
+	}
+	if err := apply(r.ctx, r.client, repo); err != nil {
 		return errors.E(op, err)
 	}
 
+	if porch.JSONOutput(cmd) {
+		if err := porch.PrintJSON(cmd.OutOrStdout(), repo); err != nil {
+			return errors.E(op, err)
+		}
+	}
 	return nil
 }
 
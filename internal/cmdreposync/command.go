@@ -0,0 +1,210 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdreposync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	command = "cmdreposync"
+	longMsg = `
+kpt alpha repo sync REPOSITORY [flags]
+
+Requests Package Orchestrator to synchronize a registered repository
+immediately, rather than at the next polling interval.
+
+Args:
+
+REPOSITORY:
+  Name of the registered repository resource to synchronize.
+
+Flags:
+
+--wait
+  Wait until the repository is synchronized.
+
+--timeout
+  How long to wait for the repository to be synchronized. Defaults to 60s.
+`
+)
+
+// pollInterval is the interval at which the repository is checked when waiting
+// for the sync to complete.
+const pollInterval = time.Second
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx: ctx,
+		cfg: rcg,
+	}
+	c := &cobra.Command{
+		Use:     "sync REPOSITORY [flags]",
+		Short:   "Synchronizes a registered package repository immediately.",
+		Long:    longMsg,
+		Example: "kpt alpha repo sync registered-repository --wait --timeout 2m",
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+		Hidden:  porch.HidePorchCommands,
+	}
+	r.Command = c
+	c.ValidArgsFunction = porch.FirstArg(porch.CompleteRepositories(ctx, rcg))
+
+	c.Flags().BoolVar(&r.wait, "wait", false, "Wait until the repository is synchronized.")
+	c.Flags().DurationVar(&r.timeout, "timeout", 60*time.Second, "How long to wait for the repository to be synchronized.")
+
+	return r
+}
+
+type runner struct {
+	ctx     context.Context
+	cfg     *genericclioptions.ConfigFlags
+	client  client.Client
+	Command *cobra.Command
+
+	// Flags
+	wait    bool
+	timeout time.Duration
+}
+
+func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".preRunE"
+	client, err := porch.CreateClient(r.cfg)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.client = client
+	return nil
+}
+
+func (r *runner) runE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+
+	if len(args) == 0 {
+		return errors.E(op, fmt.Errorf("REPOSITORY is a required positional argument"))
+	}
+
+	key := client.ObjectKey{
+		Namespace: *r.cfg.Namespace,
+		Name:      args[0],
+	}
+
+	var repo configapi.Repository
+	if err := r.client.Get(r.ctx, key, &repo); err != nil {
+		return errors.E(op, err)
+	}
+
+	// Each request writes a unique token, which porch records in the status
+	// of the repository once it handled the request.
+	requested := string(uuid.NewUUID())
+	patch := client.MergeFrom(repo.DeepCopy())
+	if repo.Annotations == nil {
+		repo.Annotations = map[string]string{}
+	}
+	repo.Annotations[configapi.AnnotationSyncRequested] = requested
+	if err := r.client.Patch(r.ctx, &repo, patch); err != nil {
+		return errors.E(op, err)
+	}
+
+	if !r.wait {
+		if porch.JSONOutput(cmd) {
+			return r.printRepository(cmd, key)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s sync requested\n", key.Name)
+		return nil
+	}
+
+	if err := r.waitForSync(key, requested); err != nil {
+		return errors.E(op, err)
+	}
+	if porch.JSONOutput(cmd) {
+		return r.printRepository(cmd, key)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s synced\n", key.Name)
+	return nil
+}
+
+// printRepository prints the current state of the repository as JSON.
+func (r *runner) printRepository(cmd *cobra.Command, key client.ObjectKey) error {
+	const op errors.Op = command + ".printRepository"
+
+	var repo configapi.Repository
+	if err := r.client.Get(r.ctx, key, &repo); err != nil {
+		return errors.E(op, err)
+	}
+	if err := porch.PrintJSON(cmd.OutOrStdout(), &repo); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// waitForSync polls the repository until porch handled the sync request, or
+// the timeout expires.
+func (r *runner) waitForSync(key client.ObjectKey, requested string) error {
+	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		var repo configapi.Repository
+		if err := r.client.Get(ctx, key, &repo); err != nil {
+			return err
+		}
+		if done, err := synced(&repo, requested); done {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for repository %s to sync", key.Name)
+		}
+	}
+}
+
+// synced returns true if porch handled the sync request, along with the
+// error if the sync failed.
+func synced(repo *configapi.Repository, requested string) (bool, error) {
+	if repo.Status.LastSyncRequest != requested {
+		return false, nil
+	}
+	condition := meta.FindStatusCondition(repo.Status.Conditions, configapi.RepositoryConditionUpstreamSynced)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		message := "unknown error"
+		if condition != nil {
+			message = condition.Message
+		}
+		return true, fmt.Errorf("repository %s failed to sync: %s", repo.Name, message)
+	}
+	return true, nil
+}
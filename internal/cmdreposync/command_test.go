@@ -0,0 +1,85 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdreposync
+
+import (
+	"testing"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSynced(t *testing.T) {
+	const requested = "3b241101-e2bb-4255-8caf-4136c566a962"
+
+	for _, tc := range []struct {
+		name        string
+		lastRequest string
+		conditions  []metav1.Condition
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name: "no request handled",
+			want: false,
+		},
+		{
+			name:        "previous request handled",
+			lastRequest: "previous",
+			conditions: []metav1.Condition{{
+				Type:   configapi.RepositoryConditionUpstreamSynced,
+				Status: metav1.ConditionTrue,
+			}},
+			want: false,
+		},
+		{
+			name:        "synced",
+			lastRequest: requested,
+			conditions: []metav1.Condition{{
+				Type:   configapi.RepositoryConditionUpstreamSynced,
+				Status: metav1.ConditionTrue,
+			}},
+			want: true,
+		},
+		{
+			name:        "sync failed",
+			lastRequest: requested,
+			conditions: []metav1.Condition{{
+				Type:    configapi.RepositoryConditionUpstreamSynced,
+				Status:  metav1.ConditionFalse,
+				Reason:  "SyncFailed",
+				Message: "authentication required",
+			}},
+			want:    true,
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &configapi.Repository{
+				Status: configapi.RepositoryStatus{
+					Conditions:      tc.conditions,
+					LastSyncRequest: tc.lastRequest,
+				},
+			}
+			got, err := synced(repo, requested)
+			if got != tc.want {
+				t.Errorf("synced() = %t, want %t", got, tc.want)
+			}
+			if (err != nil) != tc.wantErr {
+				t.Errorf("synced() error = %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -67,6 +67,7 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:  porch.HidePorchCommands,
 	}
 	r.Command = c
+	c.ValidArgsFunction = porch.FirstArg(porch.CompleteRepositories(ctx, rcg))
 
 	c.Flags().BoolVar(&r.keepSecret, "keep-auth-secret", false, "Keep the auth secret associated with the repository registration, if any")
 
@@ -122,6 +123,12 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 		return errors.E(op, err)
 	}
 
+	if porch.JSONOutput(cmd) {
+		if err := porch.PrintJSON(cmd.OutOrStdout(), porch.DeletedStatus("Repository", repo.Namespace, repo.Name)); err != nil {
+			return errors.E(op, err)
+		}
+	}
+
 	if r.keepSecret {
 		return nil
 	}
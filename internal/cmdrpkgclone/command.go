@@ -31,7 +31,7 @@ import (
 const (
 	command = "cmdrpkgclone"
 	longMsg = `
-kpt alpha rpkg clone SOURCE_PACKAGE TARGET
+kpt alpha rpkg clone SOURCE_PACKAGE [TARGET]
 
 Creates a clone of a source package in the target repository.
 
@@ -46,6 +46,7 @@ SOURCE_PACKAGE:
 TARGET:
   Target package name in the format: REPOSITORY[:PACKAGE[:REVISION]]
   Example: package-repository:package-name:v1
+  Can be omitted in favor of the --repository, --name and --revision flags.
 
 Flags:
 
@@ -57,7 +58,23 @@ Flags:
 
 --strategy
   Update strategy that should be used when updating this package; one of: resource-merge, fast-forward, force-delete-replace
+
+--repository
+  Repository of the new package, if TARGET is not specified.
+
+--name
+  Name of the new package, if TARGET is not specified. Defaults to the name of the source package.
+
+--revision
+  Revision of the new package, if TARGET is not specified. Defaults to v1.
+
+--parameters
+  Setter values applied to the cloned package, in the format key=value. Can be repeated.
 `
+
+	// applySettersImage is the function which applies the --parameters to
+	// the cloned package.
+	applySettersImage = "gcr.io/kpt-fn/apply-setters:v0.2.0"
 )
 
 var (
@@ -78,7 +95,7 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		cfg: rcg,
 	}
 	c := &cobra.Command{
-		Use:     "clone SOURCE_PACKAGE TARGET",
+		Use:     "clone SOURCE_PACKAGE [TARGET]",
 		Short:   "Creates a clone of a source package in the target repository.",
 		Long:    longMsg,
 		Example: "kpt alpha rpkg clone git-repository:source-package:v2 target-repository:target-package-name:v1",
@@ -87,11 +104,27 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:  porch.HidePorchCommands,
 	}
 	r.Command = c
+	completeSource := porch.CompletePackageRevisions(ctx, rcg)
+	completeTarget := porch.CompleteRepositoryPrefix(ctx, rcg)
+	c.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return completeSource(cmd, args, toComplete)
+		case 1:
+			return completeTarget(cmd, args, toComplete)
+		default:
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
 
 	c.Flags().StringVar(&r.strategy, "strategy", string(porchapi.ResourceMerge),
 		"update strategy that should be used when updating this package; one of: "+strings.Join(strategies, ","))
 	c.Flags().StringVar(&r.directory, "directory", "/", "Directory within the repository where the upstream package is located.")
 	c.Flags().StringVar(&r.ref, "ref", "main", "Branch in the repository where the upstream package is located.")
+	c.Flags().StringVar(&r.repository, "repository", "", "Repository of the new package, if TARGET is not specified.")
+	c.Flags().StringVar(&r.name, "name", "", "Name of the new package, if TARGET is not specified.")
+	c.Flags().StringVar(&r.revision, "revision", "", "Revision of the new package, if TARGET is not specified.")
+	c.Flags().StringArrayVar(&r.parameters, "parameters", []string{}, "Setter values applied to the cloned package, in the format key=value.")
 
 	return r
 }
@@ -102,13 +135,18 @@ type runner struct {
 	client  client.Client
 	Command *cobra.Command
 
-	clone  porchapi.PackageCloneTaskSpec
-	target porch.PackageName
+	clone   porchapi.PackageCloneTaskSpec
+	target  porch.PackageName
+	setters map[string]string
 
 	// Flags
-	strategy  string
-	directory string
-	ref       string
+	strategy   string
+	directory  string
+	ref        string
+	repository string
+	name       string
+	revision   string
+	parameters []string
 }
 
 func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
@@ -125,18 +163,21 @@ func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
 	}
 	r.clone.Strategy = mergeStrategy
 
-	if len(args) < 2 {
-		return errors.E(op, fmt.Errorf("SOURCE_PACKAGE and TARGET are required positional arguments; %d provided", len(args)))
+	if len(args) < 1 {
+		return errors.E(op, fmt.Errorf("SOURCE_PACKAGE is a required positional argument"))
 	}
 
 	source := args[0]
-	target := args[1]
+	targetPackageName, err := r.targetPackageName(args[1:])
+	if err != nil {
+		return errors.E(op, err)
+	}
 
-	// Source is a KRM package name?
-	targetPackageName, nameParts := porch.ParsePartialPackageName(target)
-	if nameParts < 1 || nameParts > 3 {
-		return errors.E(op, fmt.Errorf("invalid target name: %q", target))
+	setters, err := parseParameters(r.parameters)
+	if err != nil {
+		return errors.E(op, err)
 	}
+	r.setters = setters
 
 	switch {
 	case strings.HasPrefix(source, "oci://"):
@@ -147,7 +188,7 @@ func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
 
 		// TODO: Infer target package name from source
 		if targetPackageName.Package == "" {
-			return errors.E(op, fmt.Errorf("missing target package name (%q)", target))
+			return errors.E(op, fmt.Errorf("missing target package name (%q)", targetPackageName.Original))
 		}
 
 	case strings.Contains(source, "/"):
@@ -191,10 +232,53 @@ func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// targetPackageName returns the name of the new package from the TARGET
+// argument, or from the --repository, --name and --revision flags if there is
+// no TARGET.
+func (r *runner) targetPackageName(args []string) (porch.PackageName, error) {
+	if len(args) == 0 {
+		if r.repository == "" {
+			return porch.PackageName{}, fmt.Errorf("either TARGET or --repository must be specified")
+		}
+		return porch.PackageName{
+			Original:   r.repository + ":" + r.name,
+			Repository: r.repository,
+			Package:    r.name,
+			Revision:   r.revision,
+		}, nil
+	}
+
+	if r.repository != "" || r.name != "" || r.revision != "" {
+		return porch.PackageName{}, fmt.Errorf("--repository, --name and --revision cannot be used with TARGET %q", args[0])
+	}
+	target, nameParts := porch.ParsePartialPackageName(args[0])
+	if nameParts < 1 || nameParts > 3 {
+		return porch.PackageName{}, fmt.Errorf("invalid target name: %q", args[0])
+	}
+	return target, nil
+}
+
+// parseParameters parses the key=value --parameters into setter values.
+func parseParameters(parameters []string) (map[string]string, error) {
+	setters := map[string]string{}
+	for _, p := range parameters {
+		parts := strings.SplitN(p, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid parameter %q; must be in the format key=value", p)
+		}
+		key, value := parts[0], parts[1]
+		if _, exists := setters[key]; exists {
+			return nil, fmt.Errorf("parameter %q is specified more than once", key)
+		}
+		setters[key] = value
+	}
+	return setters, nil
+}
+
 func (r *runner) runE(cmd *cobra.Command, args []string) error {
 	const op errors.Op = command + ".runE"
 
-	if err := r.client.Create(r.ctx, &porchapi.PackageRevision{
+	pr := &porchapi.PackageRevision{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PackageRevision",
 			APIVersion: porchapi.SchemeGroupVersion.Identifier(),
@@ -214,9 +298,26 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 				},
 			},
 		},
-	}); err != nil {
+	}
+	if len(r.setters) > 0 {
+		pr.Spec.Tasks = append(pr.Spec.Tasks, porchapi.Task{
+			Type: porchapi.TaskTypeEval,
+			Eval: &porchapi.FunctionEvalTaskSpec{
+				Image:     applySettersImage,
+				ConfigMap: r.setters,
+			},
+		})
+	}
+	if err := r.client.Create(r.ctx, pr); err != nil {
 		return errors.E(op, err)
 	}
+	if porch.JSONOutput(cmd) {
+		if err := porch.PrintJSON(cmd.OutOrStdout(), pr); err != nil {
+			return errors.E(op, err)
+		}
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s created\n", pr.Name)
 	return nil
 }
 
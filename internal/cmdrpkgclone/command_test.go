@@ -0,0 +1,103 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdrpkgclone
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTargetPackageName(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		args       []string
+		repository string
+		pkg        string
+		revision   string
+		want       porch.PackageName
+		wantErr    bool
+	}{
+		{
+			name: "target argument",
+			args: []string{"deployments:bucket:v2"},
+			want: porch.PackageName{Original: "deployments:bucket:v2", Repository: "deployments", Package: "bucket", Revision: "v2"},
+		},
+		{
+			name:       "flags",
+			repository: "deployments",
+			pkg:        "bucket",
+			revision:   "v2",
+			want:       porch.PackageName{Original: "deployments:bucket", Repository: "deployments", Package: "bucket", Revision: "v2"},
+		},
+		{
+			name:       "repository flag only",
+			repository: "deployments",
+			want:       porch.PackageName{Original: "deployments:", Repository: "deployments"},
+		},
+		{
+			name:    "no target",
+			wantErr: true,
+		},
+		{
+			name:    "target argument and flags",
+			args:    []string{"deployments:bucket:v2"},
+			pkg:     "bucket",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &runner{repository: tc.repository, name: tc.pkg, revision: tc.revision}
+			got, err := r.targetPackageName(tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("targetPackageName succeeded with %+v; want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("targetPackageName failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected target (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestParseParameters(t *testing.T) {
+	got, err := parseParameters([]string{"env=prod", "url=https://example.com/?a=b", "empty="})
+	if err != nil {
+		t.Fatalf("parseParameters failed: %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{
+		"env":   "prod",
+		"url":   "https://example.com/?a=b",
+		"empty": "",
+	}, got); diff != "" {
+		t.Errorf("unexpected setters (-want, +got): %s", diff)
+	}
+
+	for _, invalid := range [][]string{
+		{"env"},
+		{"=prod"},
+		{"env=prod", "env=dev"},
+	} {
+		if _, err := parseParameters(invalid); err == nil {
+			t.Errorf("parseParameters(%q) succeeded; want error", invalid)
+		}
+	}
+}
@@ -38,6 +38,12 @@ Args:
 
 PACKAGE:
   One or more ames of the package revisions to delete.
+
+Flags:
+
+--force
+  Force the deletion of published package revisions, which are otherwise
+  protected from deletion, by deleting with a zero grace period.
 `
 )
 
@@ -58,8 +64,10 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:     porch.HidePorchCommands,
 	}
 	r.Command = c
+	c.ValidArgsFunction = porch.CompletePackageRevisions(ctx, rcg)
 
 	// Create flags
+	c.Flags().BoolVar(&r.force, "force", false, "Force the deletion of published package revisions.")
 
 	return r
 }
@@ -73,6 +81,9 @@ type runner struct {
 	cfg     *genericclioptions.ConfigFlags
 	client  client.Client
 	Command *cobra.Command
+
+	// Flags
+	force bool
 }
 
 func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
@@ -102,10 +113,21 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 			},
 		}
 
-		if err := r.client.Delete(r.ctx, pr); err != nil {
+		var opts []client.DeleteOption
+		if r.force {
+			// Published package revisions are only deleted without a grace period.
+			opts = append(opts, client.GracePeriodSeconds(0))
+		}
+
+		switch err := r.client.Delete(r.ctx, pr, opts...); {
+		case err != nil:
 			messages = append(messages, err.Error())
 			fmt.Fprintf(r.Command.ErrOrStderr(), "%s failed (%s)\n", pkg, err)
-		} else {
+		case porch.JSONOutput(cmd):
+			if err := porch.PrintJSON(cmd.OutOrStdout(), porch.DeletedStatus("PackageRevision", pr.Namespace, pkg)); err != nil {
+				return errors.E(op, err)
+			}
+		default:
 			fmt.Fprintf(r.Command.OutOrStderr(), "%s deleted\n", pkg)
 		}
 	}
@@ -44,6 +44,10 @@ Flags:
 --name
   Name of the packages to get. Any package whose name contains this value will be included in the results.
 
+--repository
+  Repository of the packages to get. Defaults to the defaultRepository of
+  ~/.config/porchctl/config.yaml or the PORCH_REPO environment variable.
+
 `
 )
 
@@ -55,7 +59,6 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 	}
 	c := &cobra.Command{
 		Use:        "get",
-		Aliases:    []string{"list"},
 		SuggestFor: []string{},
 		Short:      "Gets or lists packages in registered repositories.",
 		Long:       longMsg,
@@ -65,6 +68,7 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:     porch.HidePorchCommands,
 	}
 	r.Command = c
+	c.ValidArgsFunction = porch.CompletePackageRevisions(ctx, rcg)
 
 	// Create flags
 	c.Flags().StringVar(&r.name, "name", "", "Name of the packages to get. Any package whose name contains this value will be included in the results.")
@@ -84,6 +88,7 @@ type runner struct {
 
 	// Flags
 	name       string
+	repository string
 	printFlags *get.PrintFlags
 }
 
@@ -95,6 +100,12 @@ func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
 		return errors.E(op, err)
 	}
 	r.client = client
+
+	repository, err := cmd.Flags().GetString(porch.RepositoryFlag)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.repository = repository
 	return nil
 }
 
@@ -133,7 +144,7 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 			pr.APIVersion = porchapi.SchemeGroupVersion.Identifier()
 		}
 
-		if r.name == "" {
+		if r.name == "" && r.repository == "" {
 			objs = append(objs, &list)
 		} else {
 			for i := range list.Items {
@@ -166,5 +177,8 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 }
 
 func (r *runner) match(pr *porchapi.PackageRevision) bool {
+	if r.repository != "" && pr.Spec.RepositoryName != r.repository {
+		return false
+	}
 	return strings.Contains(pr.Name, r.name)
 }
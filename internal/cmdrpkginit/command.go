@@ -17,6 +17,7 @@ package cmdrpkginit
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/GoogleContainerTools/kpt/internal/errors"
 	"github.com/GoogleContainerTools/kpt/internal/util/porch"
@@ -39,6 +40,7 @@ Args:
 PACKAGE:
   Target package name in the format: REPOSITORY:PACKAGE:REVISION
   Example: package-repository:package-name:v1
+  If only the package name is given, the repository defaults to --repository.
 
 
 Flags:
@@ -74,6 +76,7 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:  porch.HidePorchCommands,
 	}
 	r.Command = c
+	c.ValidArgsFunction = porch.FirstArg(porch.CompleteRepositoryPrefix(ctx, rcg))
 
 	c.Flags().StringVar(&r.Description, "description", "sample description", "short description of the package.")
 	c.Flags().StringSliceVar(&r.Keywords, "keywords", []string{}, "list of keywords for the package.")
@@ -111,6 +114,15 @@ func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
 
 	target := args[0]
 
+	repository, err := cmd.Flags().GetString(porch.RepositoryFlag)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if repository != "" && !strings.Contains(target, ":") {
+		// Only the package name was given; use the default repository.
+		target = repository + ":" + target
+	}
+
 	targetPackageName, nameParts := porch.ParsePartialPackageName(target)
 	if nameParts < 2 || nameParts > 3 {
 		return errors.E(op, fmt.Errorf("invalid package name: %q", target))
@@ -125,7 +137,7 @@ func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
 func (r *runner) runE(cmd *cobra.Command, args []string) error {
 	const op errors.Op = command + ".runE"
 
-	if err := r.client.Create(r.ctx, &porchapi.PackageRevision{
+	pr := &porchapi.PackageRevision{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PackageRevision",
 			APIVersion: porchapi.SchemeGroupVersion.Identifier(),
@@ -150,8 +162,14 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 			},
 		},
 		Status: porchapi.PackageRevisionStatus{},
-	}); err != nil {
+	}
+	if err := r.client.Create(r.ctx, pr); err != nil {
 		return errors.E(op, err)
 	}
+	if porch.JSONOutput(cmd) {
+		if err := porch.PrintJSON(cmd.OutOrStdout(), pr); err != nil {
+			return errors.E(op, err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,262 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdrpkglist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	command = "cmdrpkglist"
+	longMsg = `
+kpt alpha rpkg list [flags]
+
+Lists package revisions in registered repositories as a table or as
+newline-delimited JSON.
+
+Flags:
+
+--repository
+  Repository of the packages to list. Defaults to the defaultRepository of
+  ~/.config/porchctl/config.yaml or the PORCH_REPO environment variable.
+
+--lifecycle
+  Lifecycle of the package revisions to list: Draft, Proposed or Published.
+  If unspecified, package revisions of all lifecycles are listed.
+
+--output, -o
+  Output format: table (default) or json. The json format prints one
+  package revision per line.
+
+--sort-by
+  Comma-separated list of columns to sort by, for example lifecycle,name.
+  Supported columns are name, package, repository, revision, lifecycle and
+  last-rendered. Defaults to name.
+
+`
+	outputTable = "table"
+	outputJSON  = "json"
+
+	timestampFormat = "2006-01-02 15:04:05"
+)
+
+// sortKeys compares two package revisions by a column.
+var sortKeys = map[string]func(a, b *porchapi.PackageRevision) int{
+	"name": func(a, b *porchapi.PackageRevision) int {
+		return strings.Compare(a.Name, b.Name)
+	},
+	"package": func(a, b *porchapi.PackageRevision) int {
+		return strings.Compare(a.Spec.PackageName, b.Spec.PackageName)
+	},
+	"repository": func(a, b *porchapi.PackageRevision) int {
+		return strings.Compare(a.Spec.RepositoryName, b.Spec.RepositoryName)
+	},
+	"revision": func(a, b *porchapi.PackageRevision) int {
+		return strings.Compare(a.Spec.Revision, b.Spec.Revision)
+	},
+	"lifecycle": func(a, b *porchapi.PackageRevision) int {
+		return strings.Compare(string(a.Spec.Lifecycle), string(b.Spec.Lifecycle))
+	},
+	"last-rendered": func(a, b *porchapi.PackageRevision) int {
+		switch ta, tb := a.Status.LastRenderedAt.Time, b.Status.LastRenderedAt.Time; {
+		case ta.Before(tb):
+			return -1
+		case tb.Before(ta):
+			return 1
+		default:
+			return 0
+		}
+	},
+}
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx: ctx,
+		cfg: rcg,
+	}
+	c := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Lists package revisions in registered repositories.",
+		Long:    longMsg,
+		Example: "kpt alpha rpkg list --namespace=default --lifecycle=Published --sort-by=lifecycle,name",
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+		Hidden:  porch.HidePorchCommands,
+	}
+	r.Command = c
+
+	c.Flags().StringVar(&r.lifecycle, "lifecycle", "", "Lifecycle of the package revisions to list: Draft, Proposed or Published.")
+	c.Flags().StringVarP(&r.output, "output", "o", outputTable, "Output format: table or json.")
+	c.Flags().StringVar(&r.sortBy, "sort-by", "name", "Comma-separated list of columns to sort by, for example lifecycle,name.")
+	return r
+}
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+type runner struct {
+	ctx     context.Context
+	cfg     *genericclioptions.ConfigFlags
+	client  client.Client
+	Command *cobra.Command
+
+	// Flags
+	repository string
+	lifecycle  string
+	output     string
+	sortBy     string
+}
+
+func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".preRunE"
+
+	switch r.output {
+	case outputTable, outputJSON:
+	default:
+		return errors.E(op, fmt.Errorf("unsupported --output %q; must be %q or %q", r.output, outputTable, outputJSON))
+	}
+	switch porchapi.PackageRevisionLifecycle(r.lifecycle) {
+	case "", porchapi.PackageRevisionLifecycleDraft, porchapi.PackageRevisionLifecycleProposed, porchapi.PackageRevisionLifecyclePublished:
+	default:
+		return errors.E(op, fmt.Errorf("unsupported --lifecycle %q; must be %s, %s or %s", r.lifecycle,
+			porchapi.PackageRevisionLifecycleDraft, porchapi.PackageRevisionLifecycleProposed, porchapi.PackageRevisionLifecyclePublished))
+	}
+	for _, key := range strings.Split(r.sortBy, ",") {
+		if _, found := sortKeys[key]; !found {
+			return errors.E(op, fmt.Errorf("unsupported --sort-by column %q", key))
+		}
+	}
+
+	client, err := porch.CreateClient(r.cfg)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.client = client
+
+	repository, err := cmd.Flags().GetString(porch.RepositoryFlag)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.repository = repository
+	return nil
+}
+
+func (r *runner) runE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+
+	var list porchapi.PackageRevisionList
+	if err := r.client.List(r.ctx, &list, client.InNamespace(*r.cfg.Namespace)); err != nil {
+		return errors.E(op, err)
+	}
+
+	// Porch doesn't support field selectors on package revisions, so the
+	// listing is filtered here.
+	var revisions []*porchapi.PackageRevision
+	for i := range list.Items {
+		pr := &list.Items[i]
+		if r.repository != "" && pr.Spec.RepositoryName != r.repository {
+			continue
+		}
+		if r.lifecycle != "" && string(pr.Spec.Lifecycle) != r.lifecycle {
+			continue
+		}
+		revisions = append(revisions, pr)
+	}
+	sortRevisions(revisions, strings.Split(r.sortBy, ","))
+
+	var err error
+	if r.output == outputJSON {
+		err = printJSON(cmd.OutOrStdout(), revisions)
+	} else {
+		err = printTable(cmd.OutOrStdout(), revisions)
+	}
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// sortRevisions sorts the package revisions by the columns, in order of precedence.
+func sortRevisions(revisions []*porchapi.PackageRevision, columns []string) {
+	sort.SliceStable(revisions, func(i, j int) bool {
+		for _, column := range columns {
+			if c := sortKeys[column](revisions[i], revisions[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+func printTable(out io.Writer, revisions []*porchapi.PackageRevision) error {
+	w := printers.GetNewTabWriter(out)
+	fmt.Fprintln(w, "NAME\tREPOSITORY\tREVISION\tLIFECYCLE\tLAST-RENDERED\tUPSTREAM-REVISION")
+	for _, pr := range revisions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", pr.Name, pr.Spec.RepositoryName, pr.Spec.Revision,
+			pr.Spec.Lifecycle, lastRendered(pr), upstreamRevision(pr))
+	}
+	return w.Flush()
+}
+
+// printJSON prints the package revisions as newline-delimited JSON.
+func printJSON(out io.Writer, revisions []*porchapi.PackageRevision) error {
+	encoder := json.NewEncoder(out)
+	for _, pr := range revisions {
+		pr.Kind = "PackageRevision"
+		pr.APIVersion = porchapi.SchemeGroupVersion.Identifier()
+		if err := encoder.Encode(pr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lastRendered(pr *porchapi.PackageRevision) string {
+	if pr.Status.LastRenderedAt.IsZero() {
+		return "<none>"
+	}
+	return pr.Status.LastRenderedAt.Time.UTC().Format(timestampFormat)
+}
+
+// upstreamRevision returns the upstream ref the package revision was cloned
+// from, or the locked commit if the ref is unknown.
+func upstreamRevision(pr *porchapi.PackageRevision) string {
+	lock := pr.Status.UpstreamLock
+	if lock == nil || lock.Git == nil {
+		return "<none>"
+	}
+	if lock.Git.Ref != "" {
+		return lock.Git.Ref
+	}
+	if lock.Git.Commit != "" {
+		return lock.Git.Commit
+	}
+	return "<none>"
+}
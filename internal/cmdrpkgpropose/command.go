@@ -62,6 +62,7 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:  porch.HidePorchCommands,
 	}
 	r.Command = c
+	c.ValidArgsFunction = porch.CompletePackageRevisions(ctx, rcg)
 
 	return r
 }
@@ -104,7 +105,13 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 		case v1alpha1.PackageRevisionLifecycleDraft:
 			// ok
 		case v1alpha1.PackageRevisionLifecycleProposed:
-			fmt.Fprintf(r.Command.OutOrStderr(), "%s is already proposed\n", name)
+			if porch.JSONOutput(cmd) {
+				if err := porch.PrintJSON(cmd.OutOrStdout(), pr); err != nil {
+					return errors.E(op, err)
+				}
+			} else {
+				fmt.Fprintf(r.Command.OutOrStderr(), "%s is already proposed\n", name)
+			}
 			continue
 		default:
 			msg := fmt.Sprintf("cannot propose %s package", pr.Spec.Lifecycle)
@@ -114,10 +121,15 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 		}
 
 		pr.Spec.Lifecycle = v1alpha1.PackageRevisionLifecycleProposed
-		if err := r.client.Update(r.ctx, pr); err != nil {
+		switch err := r.client.Update(r.ctx, pr); {
+		case err != nil:
 			messages = append(messages, err.Error())
 			fmt.Fprintf(r.Command.ErrOrStderr(), "%s failed (%s)\n", name, err)
-		} else {
+		case porch.JSONOutput(cmd):
+			if err := porch.PrintJSON(cmd.OutOrStdout(), pr); err != nil {
+				return errors.E(op, err)
+			}
+		default:
 			fmt.Fprintf(r.Command.OutOrStderr(), "%s proposed\n", name)
 		}
 	}
@@ -75,6 +75,7 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:     porch.HidePorchCommands,
 	}
 	r.Command = c
+	c.ValidArgsFunction = porch.FirstArg(porch.CompletePackageRevisions(ctx, rcg))
 	return r
 }
 
@@ -133,11 +134,16 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 		if err := writeToDir(resources.Spec.Resources, args[1]); err != nil {
 			return errors.E(op, err)
 		}
-	} else {
+	} else if !porch.JSONOutput(cmd) {
 		if err := writeToWriter(resources.Spec.Resources, r.printer.OutStream()); err != nil {
 			return errors.E(op, err)
 		}
 	}
+	if porch.JSONOutput(cmd) {
+		if err := porch.PrintJSON(cmd.OutOrStdout(), &resources); err != nil {
+			return errors.E(op, err)
+		}
+	}
 	return nil
 }
 
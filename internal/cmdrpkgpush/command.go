@@ -76,6 +76,7 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:     porch.HidePorchCommands,
 	}
 	r.Command = c
+	c.ValidArgsFunction = porch.FirstArg(porch.CompletePackageRevisions(ctx, rcg))
 	return r
 }
 
@@ -133,7 +134,7 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 		return errors.E(op, err)
 	}
 
-	if err := r.client.Update(r.ctx, &porchapi.PackageRevisionResources{
+	prr := &porchapi.PackageRevisionResources{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PackageRevisionResources",
 			APIVersion: porchapi.SchemeGroupVersion.Identifier(),
@@ -145,9 +146,15 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 		Spec: porchapi.PackageRevisionResourcesSpec{
 			Resources: resources,
 		},
-	}); err != nil {
+	}
+	if err := r.client.Update(r.ctx, prr); err != nil {
 		return errors.E(op, err)
 	}
+	if porch.JSONOutput(cmd) {
+		if err := porch.PrintJSON(cmd.OutOrStdout(), prr); err != nil {
+			return errors.E(op, err)
+		}
+	}
 	return nil
 }
 
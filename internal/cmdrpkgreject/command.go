@@ -63,6 +63,7 @@ func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner
 		Hidden:  porch.HidePorchCommands,
 	}
 	r.Command = c
+	c.ValidArgsFunction = porch.CompletePackageRevisions(ctx, rcg)
 
 	return r
 }
@@ -98,13 +99,19 @@ func (r *runner) runE(cmd *cobra.Command, args []string) error {
 	namespace := *r.cfg.Namespace
 
 	for _, name := range args {
-		if err := porch.UpdatePackageRevisionApproval(r.ctx, r.client, client.ObjectKey{
+		pr, err := porch.UpdatePackageRevisionApproval(r.ctx, r.client, client.ObjectKey{
 			Namespace: namespace,
 			Name:      name,
-		}, v1alpha1.PackageRevisionLifecycleDraft); err != nil {
+		}, v1alpha1.PackageRevisionLifecycleDraft)
+		switch {
+		case err != nil:
 			messages = append(messages, err.Error())
 			fmt.Fprintf(r.Command.ErrOrStderr(), "%s failed (%s)\n", name, err)
-		} else {
+		case porch.JSONOutput(cmd):
+			if err := porch.PrintJSON(cmd.OutOrStdout(), pr); err != nil {
+				return errors.E(op, err)
+			}
+		default:
 			fmt.Fprintf(r.Command.OutOrStderr(), "%s rejected\n", name)
 		}
 	}
@@ -0,0 +1,270 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdrpkgwatch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	command = "cmdrpkgwatch"
+	longMsg = `
+kpt alpha rpkg watch [flags]
+
+Prints package revision lifecycle transitions as they occur, until interrupted.
+
+Flags:
+
+--package
+  Name of the package to watch. If unspecified, all packages are watched.
+
+--since
+  Print package revisions updated since the given time, either RFC3339 timestamp
+  (2022-01-15T10:23:45Z) or duration (1h), before watching for new transitions.
+
+--poll-interval
+  Interval at which package revisions are polled. Defaults to 5s.
+
+`
+	timestampFormat = "2006-01-02 15:04:05"
+	// lifecycleDeleted is displayed for package revisions which no longer exist.
+	lifecycleDeleted = "Deleted"
+)
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx: ctx,
+		cfg: rcg,
+	}
+	c := &cobra.Command{
+		Use:     "watch",
+		Short:   "Streams package revision lifecycle transitions.",
+		Long:    longMsg,
+		Example: "kpt alpha rpkg watch --namespace=default --package=my-package --since=1h",
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+		Hidden:  porch.HidePorchCommands,
+	}
+	r.Command = c
+
+	c.Flags().StringVar(&r.pkg, "package", "", "Name of the package to watch. If unspecified, all packages are watched.")
+	_ = c.RegisterFlagCompletionFunc("package", porch.CompletePackages(ctx, rcg))
+	c.Flags().StringVar(&r.since, "since", "", "Print package revisions updated since the given RFC3339 timestamp or duration (for example 1h).")
+	c.Flags().DurationVar(&r.pollInterval, "poll-interval", 5*time.Second, "Interval at which package revisions are polled.")
+	return r
+}
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+type runner struct {
+	ctx     context.Context
+	cfg     *genericclioptions.ConfigFlags
+	client  client.Client
+	Command *cobra.Command
+
+	// Flags
+	pkg          string
+	since        string
+	pollInterval time.Duration
+
+	sinceTime time.Time
+}
+
+func (r *runner) preRunE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".preRunE"
+
+	if r.pollInterval <= 0 {
+		return errors.E(op, fmt.Errorf("--poll-interval must be positive"))
+	}
+	if r.since != "" {
+		since, err := parseSince(r.since, time.Now())
+		if err != nil {
+			return errors.E(op, err)
+		}
+		r.sinceTime = since
+	}
+
+	client, err := porch.CreateClient(r.cfg)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.client = client
+	return nil
+}
+
+func (r *runner) runE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+
+	ctx, stop := signal.NotifyContext(r.ctx, os.Interrupt)
+	defer stop()
+
+	printer := textEventPrinter(cmd.OutOrStdout())
+	if porch.JSONOutput(cmd) {
+		printer = jsonEventPrinter(cmd.OutOrStdout())
+	}
+
+	known, err := r.list(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if !r.sinceTime.IsZero() {
+		r.replay(printer, known)
+	}
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			current, err := r.list(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return errors.E(op, err)
+			}
+			printTransitions(printer, time.Now(), known, current)
+			known = current
+		}
+	}
+}
+
+// list returns the package revisions in the namespace, keyed by name.
+func (r *runner) list(ctx context.Context) (map[string]*porchapi.PackageRevision, error) {
+	var list porchapi.PackageRevisionList
+	if err := r.client.List(ctx, &list, client.InNamespace(*r.cfg.Namespace)); err != nil {
+		return nil, err
+	}
+
+	result := map[string]*porchapi.PackageRevision{}
+	for i := range list.Items {
+		pr := &list.Items[i]
+		if r.pkg != "" && pr.Spec.PackageName != r.pkg {
+			continue
+		}
+		result[pr.Name] = pr
+	}
+	return result, nil
+}
+
+// replay prints the package revisions updated since the --since time.
+// Porch does not keep a history of lifecycle transitions, so only the current
+// lifecycle of each package revision is shown.
+func (r *runner) replay(printer eventPrinter, revisions map[string]*porchapi.PackageRevision) {
+	var updated []*porchapi.PackageRevision
+	for _, pr := range revisions {
+		if !pr.CreationTimestamp.Time.Before(r.sinceTime) {
+			updated = append(updated, pr)
+		}
+	}
+	sort.Slice(updated, func(i, j int) bool {
+		return updated[i].CreationTimestamp.Time.Before(updated[j].CreationTimestamp.Time)
+	})
+	for _, pr := range updated {
+		printer(pr.CreationTimestamp.Time, pr, "", string(pr.Spec.Lifecycle))
+	}
+}
+
+// printTransitions prints lifecycle changes between two consecutive listings.
+func printTransitions(printer eventPrinter, now time.Time, before, after map[string]*porchapi.PackageRevision) {
+	for _, name := range sortedNames(after) {
+		pr := after[name]
+		if old, found := before[name]; !found {
+			printer(now, pr, "", string(pr.Spec.Lifecycle))
+		} else if old.Spec.Lifecycle != pr.Spec.Lifecycle {
+			printer(now, pr, string(old.Spec.Lifecycle), string(pr.Spec.Lifecycle))
+		}
+	}
+	for _, name := range sortedNames(before) {
+		if _, found := after[name]; !found {
+			pr := before[name]
+			printer(now, pr, string(pr.Spec.Lifecycle), lifecycleDeleted)
+		}
+	}
+}
+
+// eventPrinter prints a lifecycle transition of a package revision. from is
+// empty for package revisions seen for the first time.
+type eventPrinter func(when time.Time, pr *porchapi.PackageRevision, from, to string)
+
+func textEventPrinter(out io.Writer) eventPrinter {
+	return func(when time.Time, pr *porchapi.PackageRevision, from, to string) {
+		transition := to
+		if from != "" {
+			transition = from + " → " + to
+		}
+		fmt.Fprintf(out, "[%s] %s/%s: %s\n", when.Format(timestampFormat), pr.Spec.PackageName, pr.Spec.Revision, transition)
+	}
+}
+
+// event is a lifecycle transition printed with --output json.
+type event struct {
+	Time            time.Time                 `json:"time"`
+	From            string                    `json:"from,omitempty"`
+	To              string                    `json:"to"`
+	PackageRevision *porchapi.PackageRevision `json:"packageRevision"`
+}
+
+func jsonEventPrinter(out io.Writer) eventPrinter {
+	return func(when time.Time, pr *porchapi.PackageRevision, from, to string) {
+		pr.SetGroupVersionKind(porchapi.SchemeGroupVersion.WithKind("PackageRevision"))
+		if err := porch.PrintJSON(out, &event{
+			Time:            when.UTC(),
+			From:            from,
+			To:              to,
+			PackageRevision: pr,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to print event for %s: %v\n", pr.Name, err)
+		}
+	}
+}
+
+func sortedNames(revisions map[string]*porchapi.PackageRevision) []string {
+	names := make([]string, 0, len(revisions))
+	for name := range revisions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since value %q; expected RFC3339 timestamp or duration", value)
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdrpkgwatch
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPackageRevision(pkg, revision string, lifecycle porchapi.PackageRevisionLifecycle) *porchapi.PackageRevision {
+	return &porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo-" + pkg + "-" + revision},
+		Spec: porchapi.PackageRevisionSpec{
+			RepositoryName: "repo",
+			PackageName:    pkg,
+			Revision:       revision,
+			Lifecycle:      lifecycle,
+		},
+	}
+}
+
+func revisionMap(revisions ...*porchapi.PackageRevision) map[string]*porchapi.PackageRevision {
+	result := map[string]*porchapi.PackageRevision{}
+	for _, pr := range revisions {
+		result[pr.Name] = pr
+	}
+	return result
+}
+
+func TestPrintTransitions(t *testing.T) {
+	now := time.Date(2022, 1, 15, 10, 23, 45, 0, time.UTC)
+	bucketDraft := newPackageRevision("bucket", "v1", porchapi.PackageRevisionLifecycleDraft)
+	bucketProposed := newPackageRevision("bucket", "v1", porchapi.PackageRevisionLifecycleProposed)
+	app := newPackageRevision("app", "v2", porchapi.PackageRevisionLifecyclePublished)
+
+	for _, tc := range []struct {
+		name   string
+		before map[string]*porchapi.PackageRevision
+		after  map[string]*porchapi.PackageRevision
+		want   string
+	}{
+		{
+			name: "no package revisions",
+		},
+		{
+			name:   "unchanged",
+			before: revisionMap(bucketDraft, app),
+			after:  revisionMap(bucketDraft, app),
+		},
+		{
+			name:  "created",
+			after: revisionMap(bucketDraft, app),
+			want: "[2022-01-15 10:23:45] app/v2: Published\n" +
+				"[2022-01-15 10:23:45] bucket/v1: Draft\n",
+		},
+		{
+			name:   "lifecycle changed",
+			before: revisionMap(bucketDraft, app),
+			after:  revisionMap(bucketProposed, app),
+			want:   "[2022-01-15 10:23:45] bucket/v1: Draft → Proposed\n",
+		},
+		{
+			name:   "deleted",
+			before: revisionMap(bucketProposed, app),
+			after:  revisionMap(app),
+			want:   "[2022-01-15 10:23:45] bucket/v1: Proposed → Deleted\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printTransitions(textEventPrinter(&out), now, tc.before, tc.after)
+			if diff := cmp.Diff(tc.want, out.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2022, 1, 15, 10, 23, 45, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "timestamp",
+			value: "2022-01-14T08:00:00Z",
+			want:  time.Date(2022, 1, 14, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "duration",
+			value: "1h30m",
+			want:  time.Date(2022, 1, 15, 8, 53, 45, 0, time.UTC),
+		},
+		{
+			name:    "date without time",
+			value:   "2022-01-14",
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			value:   "yesterday",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseSince(tc.value, now)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("parseSince(%q) succeeded with %v; want error", tc.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSince(%q) failed: %v", tc.value, err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("parseSince(%q): got %v, want %v", tc.value, got, tc.want)
+			}
+		})
+	}
+}
@@ -76,6 +76,9 @@ type ContainerFn struct {
 	StorageMounts []runtimeutil.StorageMount
 	// Env is a slice of env string that will be exposed to container
 	Env []string
+	// Runtime is the container runtime CLI used to run the function.
+	// If it's empty, "docker" will be used.
+	Runtime string
 	// FnResult is used to store the information about the result from
 	// the function.
 	FnResult *fnresult.Result
@@ -151,7 +154,11 @@ func (f *ContainerFn) getDockerCmd() (*exec.Cmd, context.CancelFunc) {
 		timeout = f.Timeout
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	return exec.CommandContext(ctx, dockerBin, args...), cancel
+	bin := dockerBin
+	if f.Runtime != "" {
+		bin = f.Runtime
+	}
+	return exec.CommandContext(ctx, bin, args...), cancel
 }
 
 // NewContainerEnvFromStringSlice returns a new ContainerEnv pointer with parsing
@@ -69,6 +69,11 @@ func NewRunner(
 		// Pkg: string(pkgPath),
 	}
 
+	timeout, err := f.GetTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("function %q has an invalid timeout: %w", f.Image, err)
+	}
+
 	fltr := &runtimeutil.FunctionFilter{FunctionConfig: config}
 
 	if runtime != nil {
@@ -91,6 +96,7 @@ func NewRunner(
 					ImagePullPolicy: imagePullPolicy,
 					Ctx:             ctx,
 					FnResult:        fnResult,
+					Timeout:         timeout,
 				}
 				fltr.Run = cfn.Run
 			case f.Exec != "":
@@ -111,6 +117,7 @@ func NewRunner(
 					Path:     execPath,
 					Args:     execArgs,
 					FnResult: fnResult,
+					Timeout:  timeout,
 				}
 				fltr.Run = eFn.Run
 			default:
@@ -11,10 +11,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func UpdatePackageRevisionApproval(ctx context.Context, client rest.Interface, key client.ObjectKey, new v1alpha1.PackageRevisionLifecycle) error {
+func UpdatePackageRevisionApproval(ctx context.Context, client rest.Interface, key client.ObjectKey, new v1alpha1.PackageRevisionLifecycle) (*v1alpha1.PackageRevision, error) {
 	scheme := runtime.NewScheme()
 	if err := v1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
-		return err
+		return nil, err
 	}
 
 	codec := runtime.NewParameterCodec(scheme)
@@ -26,7 +26,7 @@ func UpdatePackageRevisionApproval(ctx context.Context, client rest.Interface, k
 		VersionedParams(&metav1.GetOptions{}, codec).
 		Do(ctx).
 		Into(&pr); err != nil {
-		return err
+		return nil, err
 	}
 
 	switch lifecycle := pr.Spec.Lifecycle; lifecycle {
@@ -34,9 +34,9 @@ func UpdatePackageRevisionApproval(ctx context.Context, client rest.Interface, k
 		// ok
 	case new:
 		// already correct value
-		return nil
+		return &pr, nil
 	default:
-		return fmt.Errorf("cannot change approval from %s to %s", lifecycle, new)
+		return nil, fmt.Errorf("cannot change approval from %s to %s", lifecycle, new)
 	}
 
 	// Approve - change the package revision kind to "final".
@@ -53,7 +53,7 @@ func UpdatePackageRevisionApproval(ctx context.Context, client rest.Interface, k
 		Body(&pr).
 		Do(ctx).
 		Into(result); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return result, nil
 }
@@ -0,0 +1,143 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CompletionFunc completes command arguments or flag values.
+type CompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// CompleteRepositories completes names of the registered repositories.
+func CompleteRepositories(ctx context.Context, rcg *genericclioptions.ConfigFlags) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		c, err := CreateClient(rcg)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var repositories configapi.RepositoryList
+		if err := c.List(ctx, &repositories, listOptions(rcg)...); err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var names []string
+		for _, repo := range repositories.Items {
+			names = append(names, repo.Name)
+		}
+		return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// CompleteRepositoryPrefix completes the repository part of a package
+// revision name (REPOSITORY:PACKAGE:REVISION) for commands creating packages.
+func CompleteRepositoryPrefix(ctx context.Context, rcg *genericclioptions.ConfigFlags) CompletionFunc {
+	completeRepositories := CompleteRepositories(ctx, rcg)
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if strings.Contains(toComplete, ":") {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, directive := completeRepositories(cmd, args, toComplete)
+		if directive == cobra.ShellCompDirectiveError {
+			return nil, directive
+		}
+		for i := range names {
+			names[i] += ":"
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// CompletePackageRevisions completes names of the package revisions.
+func CompletePackageRevisions(ctx context.Context, rcg *genericclioptions.ConfigFlags) CompletionFunc {
+	return completePackageRevisions(ctx, rcg, func(pr *porchapi.PackageRevision) string {
+		return pr.Name
+	})
+}
+
+// CompletePackages completes names of the packages.
+func CompletePackages(ctx context.Context, rcg *genericclioptions.ConfigFlags) CompletionFunc {
+	return completePackageRevisions(ctx, rcg, func(pr *porchapi.PackageRevision) string {
+		return pr.Spec.PackageName
+	})
+}
+
+func completePackageRevisions(ctx context.Context, rcg *genericclioptions.ConfigFlags, name func(pr *porchapi.PackageRevision) string) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		c, err := CreateClient(rcg)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var revisions porchapi.PackageRevisionList
+		if err := c.List(ctx, &revisions, listOptions(rcg)...); err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var names []string
+		for i := range revisions.Items {
+			names = append(names, name(&revisions.Items[i]))
+		}
+		return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// CompleteFunctionImages completes images of the functions available in the
+// function repositories registered with the Package Orchestrator.
+func CompleteFunctionImages(ctx context.Context) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var images []string
+		for _, fn := range (FunctionGetter{ctx: ctx}).Get() {
+			images = append(images, fn.Spec.Image)
+		}
+		return filterCompletions(images, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// FirstArg applies the completion to the first positional argument only.
+func FirstArg(complete CompletionFunc) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+func listOptions(rcg *genericclioptions.ConfigFlags) []client.ListOption {
+	if rcg.Namespace != nil && *rcg.Namespace != "" {
+		return []client.ListOption{client.InNamespace(*rcg.Namespace)}
+	}
+	return nil
+}
+
+// filterCompletions returns the sorted unique values with the given prefix.
+func filterCompletions(values []string, prefix string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) && !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
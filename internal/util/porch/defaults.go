@@ -0,0 +1,113 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// NamespaceEnv is the environment variable providing the default namespace.
+	NamespaceEnv = "PORCH_NAMESPACE"
+	// RepositoryEnv is the environment variable providing the default repository.
+	RepositoryEnv = "PORCH_REPO"
+
+	// RepositoryFlag is the name of the flag selecting the repository to operate on.
+	RepositoryFlag = "repository"
+	namespaceFlag  = "namespace"
+)
+
+// Config is the content of the porch commands configuration file
+// (~/.config/porchctl/config.yaml).
+type Config struct {
+	// DefaultNamespace is used when --namespace is not specified.
+	DefaultNamespace string `yaml:"defaultNamespace,omitempty"`
+	// DefaultRepository is used when --repository is not specified.
+	DefaultRepository string `yaml:"defaultRepository,omitempty"`
+}
+
+// ConfigPath returns the location of the porch commands configuration file.
+func ConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "porchctl", "config.yaml"), nil
+}
+
+// LoadConfig reads the configuration file at path. A missing file yields
+// an empty configuration.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.Unmarshal(b, &config); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// ApplyDefaults sets the namespace and repository used by the command when
+// they are not specified by flags. Flags take precedence over the
+// configuration file, which takes precedence over the PORCH_NAMESPACE and
+// PORCH_REPO environment variables.
+func ApplyDefaults(cmd *cobra.Command, flags *genericclioptions.ConfigFlags) error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	return applyDefaults(cmd.Flags(), flags, config, os.Getenv)
+}
+
+func applyDefaults(fs *pflag.FlagSet, flags *genericclioptions.ConfigFlags, config *Config, getenv func(string) string) error {
+	if !fs.Changed(namespaceFlag) && flags.Namespace != nil {
+		if ns := firstNonEmpty(config.DefaultNamespace, getenv(NamespaceEnv)); ns != "" {
+			*flags.Namespace = ns
+		}
+	}
+	if f := fs.Lookup(RepositoryFlag); f != nil && !f.Changed {
+		if repo := firstNonEmpty(config.DefaultRepository, getenv(RepositoryEnv)); repo != "" {
+			if err := f.Value.Set(repo); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
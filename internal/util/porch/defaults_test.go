@@ -0,0 +1,120 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	env := map[string]string{
+		NamespaceEnv:  "env-namespace",
+		RepositoryEnv: "env-repo",
+	}
+
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		config        Config
+		env           map[string]string
+		wantNamespace string
+		wantRepo      string
+	}{
+		{
+			name:          "nothing specified",
+			wantNamespace: "",
+			wantRepo:      "",
+		},
+		{
+			name:          "environment",
+			env:           env,
+			wantNamespace: "env-namespace",
+			wantRepo:      "env-repo",
+		},
+		{
+			name:          "config file over environment",
+			config:        Config{DefaultNamespace: "config-namespace", DefaultRepository: "config-repo"},
+			env:           env,
+			wantNamespace: "config-namespace",
+			wantRepo:      "config-repo",
+		},
+		{
+			name:          "flags over config file",
+			args:          []string{"--namespace=flag-namespace", "--repository=flag-repo"},
+			config:        Config{DefaultNamespace: "config-namespace", DefaultRepository: "config-repo"},
+			env:           env,
+			wantNamespace: "flag-namespace",
+			wantRepo:      "flag-repo",
+		},
+		{
+			name:          "partial config file",
+			config:        Config{DefaultRepository: "config-repo"},
+			env:           env,
+			wantNamespace: "env-namespace",
+			wantRepo:      "config-repo",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := pflag.NewFlagSet(tc.name, pflag.ContinueOnError)
+			flags := genericclioptions.NewConfigFlags(true)
+			flags.AddFlags(fs)
+			repo := fs.String(RepositoryFlag, "", "")
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			getenv := func(key string) string { return tc.env[key] }
+			if err := applyDefaults(fs, flags, &tc.config, getenv); err != nil {
+				t.Fatalf("applyDefaults failed: %v", err)
+			}
+
+			if got, want := *flags.Namespace, tc.wantNamespace; got != want {
+				t.Errorf("namespace: got %q, want %q", got, want)
+			}
+			if got, want := *repo, tc.wantRepo; got != want {
+				t.Errorf("repository: got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	missing, err := LoadConfig(filepath.Join(dir, "missing.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfig of missing file failed: %v", err)
+	}
+	if *missing != (Config{}) {
+		t.Errorf("LoadConfig of missing file returned %+v; want empty config", *missing)
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("defaultNamespace: porch-system\ndefaultRepository: blueprints\n"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if want := (Config{DefaultNamespace: "porch-system", DefaultRepository: "blueprints"}); *config != want {
+		t.Errorf("LoadConfig returned %+v; want %+v", *config, want)
+	}
+}
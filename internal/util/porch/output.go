@@ -0,0 +1,161 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/itchyny/gojq"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// OutputFlag is the name of the flag selecting the output format of the
+	// porch commands.
+	OutputFlag = "output"
+	// OutputJSON selects newline-delimited JSON output.
+	OutputJSON = "json"
+	// JQFlag is the name of the flag filtering the JSON output of the porch
+	// commands.
+	JQFlag = "jq"
+)
+
+// AddOutputFlag adds the --output and --jq flags to the persistent flags of a
+// porch command group. Commands with their own --output flag shadow it.
+func AddOutputFlag(fs *pflag.FlagSet) {
+	fs.StringP(OutputFlag, "o", "", "Output format. json prints every object the command operates on as a line of JSON.")
+	fs.String(JQFlag, "", "Filter the JSON output with a jq expression, printing each result as a line of JSON, or as is if it is a string. Implies --output json.")
+}
+
+// ValidateOutput rejects unsupported values of the --output flag inherited
+// from the command group, and compiles the --jq filter. The output of the
+// command is wrapped to filter the JSON printed by PrintJSON.
+func ValidateOutput(cmd *cobra.Command) error {
+	if f := cmd.InheritedFlags().Lookup(OutputFlag); f != nil {
+		switch v := f.Value.String(); v {
+		case "", OutputJSON:
+		default:
+			return fmt.Errorf("unsupported --%s %q; must be %q", OutputFlag, v, OutputJSON)
+		}
+	}
+
+	f := cmd.InheritedFlags().Lookup(JQFlag)
+	if f == nil || f.Value.String() == "" {
+		return nil
+	}
+	query, err := gojq.Parse(f.Value.String())
+	if err != nil {
+		return fmt.Errorf("invalid --%s filter: %w", JQFlag, err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return fmt.Errorf("invalid --%s filter: %w", JQFlag, err)
+	}
+	cmd.SetOut(&jqWriter{Writer: cmd.OutOrStdout(), code: code})
+	return nil
+}
+
+// JSONOutput returns true if the command prints newline-delimited JSON.
+func JSONOutput(cmd *cobra.Command) bool {
+	if f := cmd.Flags().Lookup(JQFlag); f != nil && f.Value.String() != "" {
+		return true
+	}
+	f := cmd.Flags().Lookup(OutputFlag)
+	return f != nil && f.Value.String() == OutputJSON
+}
+
+// jqWriter is the output of commands run with --jq. The JSON printed to it by
+// PrintJSON is filtered; any other output is written as is.
+type jqWriter struct {
+	io.Writer
+	code *gojq.Code
+}
+
+// print prints the results of the filter applied to obj. Strings are printed
+// as is, so they can be used by scripts without unquoting them.
+func (w *jqWriter) print(obj interface{}) error {
+	// gojq operates on the generic representation of JSON values.
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w.Writer)
+	iter := w.code.Run(v)
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		switch result := result.(type) {
+		case error:
+			return fmt.Errorf("--%s filter failed: %w", JQFlag, result)
+		case string:
+			if _, err := fmt.Fprintln(w.Writer, result); err != nil {
+				return err
+			}
+		default:
+			if err := enc.Encode(result); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// PrintJSON prints obj as a single line of JSON, or the results of the --jq
+// filter applied to it. The kind and apiVersion of API objects read by
+// clients, which leave them empty, are filled in.
+func PrintJSON(out io.Writer, obj interface{}) error {
+	if o, ok := obj.(runtime.Object); ok && o.GetObjectKind().GroupVersionKind().Empty() {
+		scheme, err := createScheme()
+		if err != nil {
+			return err
+		}
+		if gvk, err := apiutil.GVKForObject(o, scheme); err == nil {
+			o.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+	}
+	if w, ok := out.(*jqWriter); ok {
+		return w.print(obj)
+	}
+	return json.NewEncoder(out).Encode(obj)
+}
+
+// DeletedStatus returns the status printed as JSON for an object deleted by
+// a command.
+func DeletedStatus(kind, namespace, name string) *metav1.Status {
+	return &metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status: metav1.StatusSuccess,
+		Details: &metav1.StatusDetails{
+			Kind: kind,
+			Name: name,
+		},
+		Message: fmt.Sprintf("%s %s/%s deleted", kind, namespace, name),
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newOutputCommand(t *testing.T, args ...string) *cobra.Command {
+	parent := &cobra.Command{Use: "parent"}
+	AddOutputFlag(parent.PersistentFlags())
+	child := &cobra.Command{Use: "child", RunE: func(*cobra.Command, []string) error { return nil }}
+	parent.AddCommand(child)
+	if err := child.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	return child
+}
+
+func TestValidateOutput(t *testing.T) {
+	for _, tc := range []struct {
+		args     []string
+		wantErr  bool
+		wantJSON bool
+	}{
+		{args: nil},
+		{args: []string{"--output=json"}, wantJSON: true},
+		{args: []string{"-o", "json"}, wantJSON: true},
+		{args: []string{"--output=yaml"}, wantErr: true},
+		{args: []string{"--jq", ".metadata.name"}, wantJSON: true},
+		{args: []string{"--jq", ".metadata.name |"}, wantErr: true, wantJSON: true},
+	} {
+		cmd := newOutputCommand(t, tc.args...)
+		if err := ValidateOutput(cmd); (err != nil) != tc.wantErr {
+			t.Errorf("ValidateOutput(%v) returned %v; want error: %t", tc.args, err, tc.wantErr)
+		}
+		if got := JSONOutput(cmd); got != tc.wantJSON {
+			t.Errorf("JSONOutput(%v) = %t; want %t", tc.args, got, tc.wantJSON)
+		}
+	}
+}
+
+func TestPrintJSON(t *testing.T) {
+	var out bytes.Buffer
+	for _, obj := range []interface{}{
+		&porchapi.PackageRevision{ObjectMeta: metav1.ObjectMeta{Name: "first"}},
+		DeletedStatus("PackageRevision", "default", "second"),
+	} {
+		if err := PrintJSON(&out, obj); err != nil {
+			t.Fatalf("PrintJSON failed: %v", err)
+		}
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	if got, want := len(lines), 2; got != want {
+		t.Fatalf("PrintJSON printed %d lines; want %d:\n%s", got, want, out.String())
+	}
+
+	var pr porchapi.PackageRevision
+	if err := json.Unmarshal(lines[0], &pr); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", lines[0], err)
+	}
+	if got, want := pr.Kind, "PackageRevision"; got != want {
+		t.Errorf("kind = %q; want %q", got, want)
+	}
+	if got, want := pr.APIVersion, porchapi.SchemeGroupVersion.Identifier(); got != want {
+		t.Errorf("apiVersion = %q; want %q", got, want)
+	}
+
+	var status metav1.Status
+	if err := json.Unmarshal(lines[1], &status); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", lines[1], err)
+	}
+	if got, want := status.Details.Name, "second"; got != want {
+		t.Errorf("deleted name = %q; want %q", got, want)
+	}
+}
+
+func TestPrintJSONWithJQ(t *testing.T) {
+	for _, tc := range []struct {
+		filter string
+		want   string
+	}{
+		{filter: ".metadata.name", want: "first\n"},
+		{filter: "{name: .metadata.name, kind}", want: `{"kind":"PackageRevision","name":"first"}` + "\n"},
+		{filter: ".metadata.labels[]", want: "platform\ngold\n"},
+		{filter: "select(.kind == \"Repository\")", want: ""},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := newOutputCommand(t, "--jq", tc.filter)
+			cmd.SetOut(&out)
+			if err := ValidateOutput(cmd); err != nil {
+				t.Fatalf("ValidateOutput failed: %v", err)
+			}
+
+			if err := PrintJSON(cmd.OutOrStdout(), &porchapi.PackageRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "first",
+					Labels: map[string]string{"team": "platform", "tier": "gold"},
+				},
+			}); err != nil {
+				t.Fatalf("PrintJSON failed: %v", err)
+			}
+			if got := out.String(); got != tc.want {
+				t.Errorf("PrintJSON printed %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/GoogleContainerTools/kpt/internal/errors"
 	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
@@ -399,6 +400,10 @@ func (pn *pkgNode) runMutators(ctx context.Context, hctx *hydrationContext, inpu
 		return input, nil
 	}
 
+	if pl.Parallelism == kptfilev1.Parallel {
+		return pn.runMutatorsInParallel(ctx, hctx, pl, input)
+	}
+
 	mutators, err := fnChain(ctx, hctx, pn.pkg.UniquePath, pl.Mutators)
 	if err != nil {
 		return nil, err
@@ -414,41 +419,226 @@ func (pn *pkgNode) runMutators(ctx context.Context, hctx *hydrationContext, inpu
 				return nil, err
 			}
 		}
-		// select the resources on which function should be applied
-		selectedInput, err := fnruntime.SelectInput(input, selectors, &fnruntime.SelectionContext{RootPackagePath: hctx.root.pkg.UniquePath})
-		if err != nil {
-			return nil, err
-		}
-		output := &kio.PackageBuffer{}
-		// create a kio pipeline from kyaml library to execute the function chains
-		mutation := kio.Pipeline{
-			Inputs: []kio.Reader{
-				&kio.PackageBuffer{Nodes: selectedInput},
-			},
-			Filters: []kio.Filter{mutator},
-			Outputs: []kio.Writer{output},
-		}
-		err = mutation.Execute()
+		input, err = runMutator(hctx, mutator, selectors, input)
 		if err != nil {
 			return nil, err
 		}
 		hctx.executedFunctionCnt += 1
 
 		if len(selectors) > 0 {
-			// merge the output resources with input resources
-			input = fnruntime.MergeWithInput(output.Nodes, selectedInput, input)
 			// delete the kpt-resource-id annotation on each resource
 			err = fnruntime.DeleteResourceIds(input)
 			if err != nil {
 				return nil, err
 			}
-		} else {
-			input = output.Nodes
 		}
 	}
 	return input, nil
 }
 
+// runMutatorsInParallel runs the mutators of the pipeline layer by layer.
+// The mutators of a layer don't depend on each other, so they run
+// concurrently, each on its own copy of the output of the previous layer.
+// Their outputs are then merged in declaration order, which keeps the result
+// independent of the order in which the functions complete.
+func (pn *pkgNode) runMutatorsInParallel(ctx context.Context, hctx *hydrationContext, pl *kptfilev1.Pipeline, input []*yaml.RNode) ([]*yaml.RNode, error) {
+	layers, err := pl.MutatorLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, layer := range layers {
+		fns := make([]kptfilev1.Function, len(layer))
+		mutators := make([]kio.Filter, len(layer))
+		// every function records its results in its own list, the lists
+		// are appended to the pipeline results once the layer is done.
+		results := make([]*fnresult.ResultList, len(layer))
+		for i, idx := range layer {
+			fns[i] = pl.Mutators[idx]
+			results[i] = fnresult.NewResultList()
+			mutators[i], err = newFnRunner(ctx, hctx, pn.pkg.UniquePath, fns[i], results[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// resource ids are used to match the outputs of the functions
+		// against the layer input when merging.
+		if err := fnruntime.SetResourceIds(input); err != nil {
+			return nil, err
+		}
+		inputs := make([][]*yaml.RNode, len(layer))
+		for i := range layer {
+			inputs[i] = cloneResources(input)
+		}
+
+		outputs := make([][]*yaml.RNode, len(layer))
+		errs := make([]error, len(layer))
+		var wg sync.WaitGroup
+		for i := range layer {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				outputs[i], errs[i] = runMutator(hctx, mutators[i], fns[i].Selectors, inputs[i])
+			}(i)
+		}
+		wg.Wait()
+
+		for i := range layer {
+			hctx.fnResults.Items = append(hctx.fnResults.Items, results[i].Items...)
+			if results[i].ExitCode != 0 {
+				hctx.fnResults.ExitCode = results[i].ExitCode
+			}
+		}
+		for i := range layer {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			hctx.executedFunctionCnt++
+		}
+
+		input, err = mergeLayerOutputs(input, fns, outputs)
+		if err != nil {
+			return nil, err
+		}
+		if err := fnruntime.DeleteResourceIds(input); err != nil {
+			return nil, err
+		}
+	}
+	return input, nil
+}
+
+// runMutator runs the mutator on the resources of input matching the
+// selectors, and returns input with the changes made by the mutator.
+// The resources must have the kpt-resource-id annotation if selectors are
+// specified.
+func runMutator(hctx *hydrationContext, mutator kio.Filter, selectors []kptfilev1.Selector, input []*yaml.RNode) ([]*yaml.RNode, error) {
+	// select the resources on which function should be applied
+	selectedInput, err := fnruntime.SelectInput(input, selectors, &fnruntime.SelectionContext{RootPackagePath: hctx.root.pkg.UniquePath})
+	if err != nil {
+		return nil, err
+	}
+	output := &kio.PackageBuffer{}
+	// create a kio pipeline from kyaml library to execute the function chains
+	mutation := kio.Pipeline{
+		Inputs: []kio.Reader{
+			&kio.PackageBuffer{Nodes: selectedInput},
+		},
+		Filters: []kio.Filter{mutator},
+		Outputs: []kio.Writer{output},
+	}
+	if err := mutation.Execute(); err != nil {
+		return nil, err
+	}
+	if len(selectors) > 0 {
+		// merge the output resources with input resources
+		return fnruntime.MergeWithInput(output.Nodes, selectedInput, input), nil
+	}
+	return output.Nodes, nil
+}
+
+// mergeLayerOutputs merges the outputs of the functions of a parallel layer
+// into a single list of resources. The resources of input must have the
+// kpt-resource-id annotation, outputs[i] is the output of fns[i] when run on
+// a copy of input.
+//
+// Resources modified or deleted by a function replace or drop the
+// corresponding input resource in place, and resources generated by the
+// functions are appended in declaration order. It is an error for two
+// functions of the same layer to modify or delete the same resource.
+func mergeLayerOutputs(input []*yaml.RNode, fns []kptfilev1.Function, outputs [][]*yaml.RNode) ([]*yaml.RNode, error) {
+	original := make(map[string]string, len(input))
+	for _, node := range input {
+		str, err := node.String()
+		if err != nil {
+			return nil, err
+		}
+		original[node.GetAnnotations()[fnruntime.ResourceIDAnnotation]] = str
+	}
+
+	// changes maps a resource id to the resource that replaces it, nil
+	// means the resource was deleted. changedBy tracks which function made
+	// the change.
+	changes := make(map[string]*yaml.RNode)
+	changedBy := make(map[string]int)
+	var generated []*yaml.RNode
+	for i, output := range outputs {
+		seen := sets.String{}
+		var changed []string
+		for _, node := range output {
+			id := node.GetAnnotations()[fnruntime.ResourceIDAnnotation]
+			str, found := original[id]
+			if !found || seen.Has(id) {
+				generated = append(generated, node)
+				continue
+			}
+			seen.Insert(id)
+			nodeStr, err := node.String()
+			if err != nil {
+				return nil, err
+			}
+			if nodeStr != str {
+				changes[id] = node
+				changed = append(changed, id)
+			}
+		}
+		for _, node := range input {
+			id := node.GetAnnotations()[fnruntime.ResourceIDAnnotation]
+			if !seen.Has(id) {
+				changed = append(changed, id)
+			}
+		}
+		for _, id := range changed {
+			if j, found := changedBy[id]; found {
+				node := nodeWithResourceID(input, id)
+				return nil, fmt.Errorf("functions %q and %q both modify resource %s/%s; "+
+					"use `dependsOn` to run one of them after the other",
+					fnDisplayName(fns[j]), fnDisplayName(fns[i]), node.GetKind(), node.GetName())
+			}
+			changedBy[id] = i
+			if !seen.Has(id) {
+				changes[id] = nil
+			}
+		}
+	}
+
+	var merged []*yaml.RNode
+	for _, node := range input {
+		id := node.GetAnnotations()[fnruntime.ResourceIDAnnotation]
+		change, found := changes[id]
+		switch {
+		case !found:
+			merged = append(merged, node)
+		case change != nil:
+			merged = append(merged, change)
+		}
+	}
+	return append(merged, generated...), nil
+}
+
+// nodeWithResourceID returns the resource of input with the given
+// kpt-resource-id annotation.
+func nodeWithResourceID(input []*yaml.RNode, id string) *yaml.RNode {
+	for _, node := range input {
+		if node.GetAnnotations()[fnruntime.ResourceIDAnnotation] == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// fnDisplayName returns the name used to refer to the function in messages.
+func fnDisplayName(f kptfilev1.Function) string {
+	switch {
+	case f.Name != "":
+		return f.Name
+	case f.Image != "":
+		return f.Image
+	default:
+		return f.Exec
+	}
+}
+
 // runValidators runs a set of validator functions on input resources.
 // We bail out on first validation failure today, but the logic can be
 // improved to report multiple failures. Reporting multiple failures
@@ -586,27 +776,7 @@ func pathRelToRoot(rootPkgPath, subPkgPath, resourcePath string) (relativePath s
 func fnChain(ctx context.Context, hctx *hydrationContext, pkgPath types.UniquePath, fns []kptfilev1.Function) ([]kio.Filter, error) {
 	var runners []kio.Filter
 	for i := range fns {
-		var err error
-		var runner kio.Filter
-		function := fns[i]
-		displayResourceCount := false
-		if len(function.Selectors) > 0 {
-			displayResourceCount = true
-		}
-		if function.Exec != "" && !hctx.allowExec {
-			return nil, errAllowedExecNotSpecified
-		}
-		if function.Image != "" && !hctx.dockerCheckDone {
-			if hctx.runtime == nil {
-				// Check for Docker when using standard runner.
-				err := cmdutil.DockerCmdAvailable()
-				if err != nil {
-					return nil, err
-				}
-			}
-			hctx.dockerCheckDone = true
-		}
-		runner, err = fnruntime.NewRunner(ctx, hctx.fileSystem, &function, pkgPath, hctx.fnResults, hctx.imagePullPolicy, true, displayResourceCount, hctx.runtime)
+		runner, err := newFnRunner(ctx, hctx, pkgPath, fns[i], hctx.fnResults)
 		if err != nil {
 			return nil, err
 		}
@@ -615,6 +785,29 @@ func fnChain(ctx context.Context, hctx *hydrationContext, pkgPath types.UniquePa
 	return runners, nil
 }
 
+// newFnRunner returns a function runner for the given function which records
+// its results in fnResults.
+func newFnRunner(ctx context.Context, hctx *hydrationContext, pkgPath types.UniquePath, function kptfilev1.Function, fnResults *fnresult.ResultList) (kio.Filter, error) {
+	displayResourceCount := false
+	if len(function.Selectors) > 0 {
+		displayResourceCount = true
+	}
+	if function.Exec != "" && !hctx.allowExec {
+		return nil, errAllowedExecNotSpecified
+	}
+	if function.Image != "" && !hctx.dockerCheckDone {
+		if hctx.runtime == nil {
+			// Check for Docker when using standard runner.
+			err := cmdutil.DockerCmdAvailable()
+			if err != nil {
+				return nil, err
+			}
+		}
+		hctx.dockerCheckDone = true
+	}
+	return fnruntime.NewRunner(ctx, hctx.fileSystem, &function, pkgPath, fnResults, hctx.imagePullPolicy, true, displayResourceCount, hctx.runtime)
+}
+
 // trackInputFiles records file paths of input resources in the hydration context.
 func trackInputFiles(hctx *hydrationContext, relPath string, input []*yaml.RNode) error {
 	if hctx.inputFiles == nil {
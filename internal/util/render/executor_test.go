@@ -16,11 +16,14 @@ package render
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/stretchr/testify/assert"
 	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 func TestPathRelToRoot(t *testing.T) {
@@ -227,3 +230,168 @@ metadata:
 		})
 	}
 }
+
+func TestMergeLayerOutputs(t *testing.T) {
+	input := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-0
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "0"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-1
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "1"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-2
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "2"
+`
+	fns := []kptfilev1.Function{
+		{Name: "set-labels"},
+		{Image: "gcr.io/kpt-fn/set-namespace:v0.1"},
+	}
+
+	tests := []struct {
+		name     string
+		outputs  []string
+		expected string
+		errMsg   string
+	}{
+		{
+			name:     "no changes",
+			outputs:  []string{input, input},
+			expected: input,
+		},
+		{
+			name: "changes, deletion and generation are merged",
+			outputs: []string{`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-0
+  labels:
+    app: foo
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "0"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-1
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "1"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-2
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "2"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: generated-0
+`, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: generated-1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-0
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "0"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-2
+  namespace: staging
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "2"
+`},
+			expected: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-0
+  labels:
+    app: foo
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "0"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-2
+  namespace: staging
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "2"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: generated-0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: generated-1
+`,
+		},
+		{
+			name: "conflicting changes",
+			outputs: []string{`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-0
+  labels:
+    app: foo
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "0"
+`, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-0
+  namespace: staging
+  annotations:
+    internal.config.k8s.io/kpt-resource-id: "0"
+`},
+			errMsg: `functions "set-labels" and "gcr.io/kpt-fn/set-namespace:v0.1" both modify resource ConfigMap/cm-0`,
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+		t.Run(tc.name, func(t *testing.T) {
+			// Parse without reader annotations, as the position of a
+			// resource in the output of a function doesn't modify it.
+			in, err := (&kio.ByteReader{Reader: strings.NewReader(input), OmitReaderAnnotations: true}).Read()
+			assert.NoError(t, err)
+			var outputs [][]*yaml.RNode
+			for _, o := range tc.outputs {
+				output, err := (&kio.ByteReader{Reader: strings.NewReader(o), OmitReaderAnnotations: true}).Read()
+				assert.NoError(t, err)
+				outputs = append(outputs, output)
+			}
+			result, err := mergeLayerOutputs(in, fns, outputs)
+			if tc.errMsg != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.errMsg)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			actual, err := kio.StringAll(result)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
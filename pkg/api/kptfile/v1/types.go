@@ -233,8 +233,25 @@ type Pipeline struct {
 	// Validators defines a list of KRM functions that validate resources.
 	// Validators are not permitted to mutate resources.
 	Validators []Function `yaml:"validators,omitempty" json:"validators,omitempty"`
+
+	// Parallelism controls how mutators are executed. With `Sequential`
+	// (the default) mutators run one after another in declaration order.
+	// With `Parallel` mutators are grouped into layers using their
+	// `dependsOn` relationships, and the mutators of a layer run
+	// concurrently on the output of the previous layer.
+	Parallelism Parallelism `yaml:"parallelism,omitempty" json:"parallelism,omitempty"`
 }
 
+// Parallelism specifies how the functions of a pipeline are executed.
+type Parallelism string
+
+const (
+	// Sequential runs the mutators one after another in declaration order.
+	Sequential Parallelism = "Sequential"
+	// Parallel runs independent mutators concurrently.
+	Parallel Parallelism = "Parallel"
+)
+
 // String returns the string representation of Pipeline struct
 // The string returned is the struct content in Go default format.
 func (p *Pipeline) String() string {
@@ -291,6 +308,17 @@ type Function struct {
 	// `Selectors` are used to specify resources on which the function should be executed
 	// if not specified, all resources are selected
 	Selectors []Selector `yaml:"selectors,omitempty" json:"selectors,omitempty"`
+
+	// `Timeout` is the maximum duration the function is allowed to run for,
+	// specified as a Go duration string such as `30s` or `2m`. If not
+	// specified, the default timeout of the function runtime applies.
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// `DependsOn` lists the names of the mutators in the same pipeline whose
+	// output this function consumes. It is only used when the pipeline
+	// parallelism is `Parallel`, where a function runs in a later layer than
+	// all the functions it depends on.
+	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
 }
 
 // Selector specifies the selection criteria
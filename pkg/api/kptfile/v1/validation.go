@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/GoogleContainerTools/kpt/internal/types"
 	"sigs.k8s.io/kustomize/api/konfig"
@@ -49,6 +50,26 @@ func (p *Pipeline) validate(fsys filesys.FileSystem, pkgPath types.UniquePath) e
 	if p == nil {
 		return nil
 	}
+	switch p.Parallelism {
+	case "", Sequential, Parallel:
+	default:
+		return &ValidateError{
+			Field:  "pipeline.parallelism",
+			Value:  string(p.Parallelism),
+			Reason: fmt.Sprintf("must be one of %q or %q", Sequential, Parallel),
+		}
+	}
+	if _, err := p.MutatorLayers(); err != nil {
+		return err
+	}
+	for i := range p.Validators {
+		if len(p.Validators[i].DependsOn) != 0 {
+			return &ValidateError{
+				Field:  fmt.Sprintf("pipeline.validators[%d].dependsOn", i),
+				Reason: "`dependsOn` is only supported for mutators",
+			}
+		}
+	}
 	for i := range p.Mutators {
 		f := p.Mutators[i]
 		err := f.validate(fsys, "mutators", i, pkgPath)
@@ -91,6 +112,16 @@ func (f *Function) validate(fsys filesys.FileSystem, fnType string, idx int, pkg
 	}
 	// TODO(droot): validate the exec
 
+	if f.Timeout != "" {
+		if _, err := f.GetTimeout(); err != nil {
+			return &ValidateError{
+				Field:  fmt.Sprintf("pipeline.%s[%d].timeout", fnType, idx),
+				Value:  f.Timeout,
+				Reason: err.Error(),
+			}
+		}
+	}
+
 	if len(f.ConfigMap) != 0 && f.ConfigPath != "" {
 		return &ValidateError{
 			Field:  fmt.Sprintf("pipeline.%s[%d]", fnType, idx),
@@ -117,6 +148,109 @@ func (f *Function) validate(fsys filesys.FileSystem, fnType string, idx int, pkg
 	return nil
 }
 
+// GetTimeout parses the function timeout. It returns zero if the function
+// does not specify a timeout.
+func (f *Function) GetTimeout() (time.Duration, error) {
+	if f.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(f.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("timeout must be a valid duration such as `30s`: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout must be positive")
+	}
+	return d, nil
+}
+
+// MutatorLayers partitions the mutators of the pipeline into layers using
+// their `dependsOn` relationships. Each layer holds the indexes of mutators
+// that only depend on mutators of earlier layers, in declaration order.
+// An error is returned if a dependency refers to an unknown mutator or the
+// dependencies form a cycle.
+func (p *Pipeline) MutatorLayers() ([][]int, error) {
+	if p == nil {
+		return nil, nil
+	}
+	index := make(map[string]int)
+	duplicates := make(map[string]bool)
+	for i, f := range p.Mutators {
+		if f.Name == "" {
+			continue
+		}
+		if _, found := index[f.Name]; found {
+			duplicates[f.Name] = true
+		}
+		index[f.Name] = i
+	}
+
+	deps := make([][]int, len(p.Mutators))
+	for i, f := range p.Mutators {
+		for _, name := range f.DependsOn {
+			j, found := index[name]
+			if !found {
+				return nil, &ValidateError{
+					Field:  fmt.Sprintf("pipeline.mutators[%d].dependsOn", i),
+					Value:  name,
+					Reason: "must refer to the name of a mutator in the pipeline",
+				}
+			}
+			if duplicates[name] {
+				return nil, &ValidateError{
+					Field:  fmt.Sprintf("pipeline.mutators[%d].dependsOn", i),
+					Value:  name,
+					Reason: "refers to a name shared by more than one mutator",
+				}
+			}
+			if j == i {
+				return nil, &ValidateError{
+					Field:  fmt.Sprintf("pipeline.mutators[%d].dependsOn", i),
+					Value:  name,
+					Reason: "function must not depend on itself",
+				}
+			}
+			deps[i] = append(deps[i], j)
+		}
+	}
+
+	var layers [][]int
+	layerOf := make([]int, len(p.Mutators))
+	for i := range layerOf {
+		layerOf[i] = -1
+	}
+	for placed := 0; placed < len(p.Mutators); {
+		var layer []int
+		for i := range p.Mutators {
+			if layerOf[i] != -1 {
+				continue
+			}
+			ready := true
+			for _, j := range deps[i] {
+				if layerOf[j] == -1 {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				layer = append(layer, i)
+			}
+		}
+		if len(layer) == 0 {
+			return nil, &ValidateError{
+				Field:  "pipeline.mutators",
+				Reason: "`dependsOn` relationships must not form a cycle",
+			}
+		}
+		for _, i := range layer {
+			layerOf[i] = len(layers)
+		}
+		layers = append(layers, layer)
+		placed += len(layer)
+	}
+	return layers, nil
+}
+
 // ValidateFunctionImageURL validates the function name.
 // According to Docker implementation
 // https://github.com/docker/distribution/blob/master/reference/reference.go. A valid
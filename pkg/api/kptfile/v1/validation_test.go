@@ -76,6 +76,101 @@ func TestKptfileValidate(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			name: "pipeline: valid timeout",
+			kptfile: KptFile{
+				Pipeline: &Pipeline{
+					Mutators: []Function{
+						{
+							Image:   "image",
+							Timeout: "30s",
+						},
+					},
+				},
+			},
+			valid: true,
+		},
+		{
+			name: "pipeline: invalid timeout",
+			kptfile: KptFile{
+				Pipeline: &Pipeline{
+					Mutators: []Function{
+						{
+							Image:   "image",
+							Timeout: "thirty seconds",
+						},
+					},
+				},
+			},
+			valid: false,
+		},
+		{
+			name: "pipeline: negative timeout",
+			kptfile: KptFile{
+				Pipeline: &Pipeline{
+					Validators: []Function{
+						{
+							Image:   "image",
+							Timeout: "-5s",
+						},
+					},
+				},
+			},
+			valid: false,
+		},
+		{
+			name: "pipeline: parallel with dependencies",
+			kptfile: KptFile{
+				Pipeline: &Pipeline{
+					Parallelism: Parallel,
+					Mutators: []Function{
+						{
+							Image: "set-labels",
+							Name:  "labels",
+						},
+						{
+							Image:     "set-namespace",
+							DependsOn: []string{"labels"},
+						},
+					},
+				},
+			},
+			valid: true,
+		},
+		{
+			name: "pipeline: invalid parallelism",
+			kptfile: KptFile{
+				Pipeline: &Pipeline{
+					Parallelism: "Concurrent",
+					Mutators: []Function{
+						{
+							Image: "image",
+						},
+					},
+				},
+			},
+			valid: false,
+		},
+		{
+			name: "pipeline: validator with dependencies",
+			kptfile: KptFile{
+				Pipeline: &Pipeline{
+					Mutators: []Function{
+						{
+							Image: "image",
+							Name:  "mutator",
+						},
+					},
+					Validators: []Function{
+						{
+							Image:     "image",
+							DependsOn: []string{"mutator"},
+						},
+					},
+				},
+			},
+			valid: false,
+		},
 		{
 			name: "pipeline: more than 1 config",
 			kptfile: KptFile{
@@ -166,6 +261,85 @@ func TestKptfileValidate(t *testing.T) {
 	}
 }
 
+func TestMutatorLayers(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mutators []Function
+		layers   [][]int
+		valid    bool
+	}{
+		{
+			name: "no dependencies",
+			mutators: []Function{
+				{Image: "a", Name: "a"},
+				{Image: "b"},
+				{Image: "c", Name: "c"},
+			},
+			layers: [][]int{{0, 1, 2}},
+			valid:  true,
+		},
+		{
+			name: "chain and independent functions",
+			mutators: []Function{
+				{Image: "c", Name: "c", DependsOn: []string{"b"}},
+				{Image: "a", Name: "a"},
+				{Image: "b", Name: "b", DependsOn: []string{"a"}},
+				{Image: "d", Name: "d"},
+				{Image: "e", Name: "e", DependsOn: []string{"a", "d"}},
+			},
+			layers: [][]int{{1, 3}, {2, 4}, {0}},
+			valid:  true,
+		},
+		{
+			name: "unknown dependency",
+			mutators: []Function{
+				{Image: "a", Name: "a", DependsOn: []string{"b"}},
+			},
+			valid: false,
+		},
+		{
+			name: "self dependency",
+			mutators: []Function{
+				{Image: "a", Name: "a", DependsOn: []string{"a"}},
+			},
+			valid: false,
+		},
+		{
+			name: "ambiguous dependency",
+			mutators: []Function{
+				{Image: "a", Name: "a"},
+				{Image: "a", Name: "a"},
+				{Image: "b", Name: "b", DependsOn: []string{"a"}},
+			},
+			valid: false,
+		},
+		{
+			name: "cycle",
+			mutators: []Function{
+				{Image: "a", Name: "a", DependsOn: []string{"c"}},
+				{Image: "b", Name: "b", DependsOn: []string{"a"}},
+				{Image: "c", Name: "c", DependsOn: []string{"b"}},
+			},
+			valid: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Pipeline{Mutators: tc.mutators, Parallelism: Parallel}
+			layers, err := p.MutatorLayers()
+			if !tc.valid {
+				assert.Error(t, err)
+				assert.Error(t, p.validate(filesys.FileSystemOrOnDisk{}, ""))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.layers, layers)
+		})
+	}
+}
+
 func TestValidateFunctionName(t *testing.T) {
 	type input struct {
 		Name  string
@@ -0,0 +1,297 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diffutil formats readable diffs of YAML resources for test failure
+// messages.
+package diffutil
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+var noColor = flag.Bool("no-color", false, "Print diffs in test failures without ANSI colors.")
+
+const (
+	// contextLines is the number of unchanged lines shown around changes.
+	contextLines = 3
+
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorBold  = "\x1b[1m"
+	colorReset = "\x1b[0m"
+)
+
+// PrettyDiff returns the diff from a to b, or an empty string if they are
+// equal. Removed lines are marked with `- ` and added lines with `+ `. The
+// changes of multi-document YAML are grouped by resource, identified by its
+// file name or else by its kind and name, and the diff ends with a summary of
+// the changed resources. The diff is colored unless the -no-color flag is set.
+func PrettyDiff(a, b string) string {
+	return prettyDiff(a, b, !*noColor)
+}
+
+func prettyDiff(a, b string, color bool) string {
+	if a == b {
+		return ""
+	}
+	p := &printer{color: color}
+
+	docsA, docsB := splitDocuments(a), splitDocuments(b)
+	if len(docsA) <= 1 && len(docsB) <= 1 {
+		p.lines(diffLines(splitLines(a), splitLines(b)))
+		p.summary(1, 0, 0)
+		return p.String()
+	}
+
+	resourcesA, resourcesB := keyDocuments(docsA), keyDocuments(docsB)
+	keys := map[string]bool{}
+	for k := range resourcesA {
+		keys[k] = true
+	}
+	for k := range resourcesB {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var modified, added, removed int
+	for _, k := range sorted {
+		docA, inA := resourcesA[k]
+		docB, inB := resourcesB[k]
+		switch {
+		case !inA:
+			added++
+			p.header(k, "added")
+		case !inB:
+			removed++
+			p.header(k, "removed")
+		case docA == docB:
+			continue
+		default:
+			modified++
+			p.header(k, "modified")
+		}
+		p.lines(diffLines(splitLines(docA), splitLines(docB)))
+	}
+	if modified+added+removed == 0 {
+		// Only the order of the documents differs.
+		p.lines(diffLines(splitLines(a), splitLines(b)))
+	}
+	p.summary(modified, added, removed)
+	return p.String()
+}
+
+// splitDocuments splits multi-document YAML at the `---` separators, dropping
+// empty documents.
+func splitDocuments(s string) []string {
+	var docs []string
+	var current []string
+	flush := func() {
+		doc := strings.TrimRight(strings.Join(current, "\n"), "\n")
+		if strings.TrimSpace(doc) != "" {
+			docs = append(docs, doc+"\n")
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimRight(line, " ") == "---" {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return docs
+}
+
+// keyDocuments returns the documents keyed by resourceKey. Repeated keys are
+// numbered in the order of the documents.
+func keyDocuments(docs []string) map[string]string {
+	result := make(map[string]string, len(docs))
+	for i, doc := range docs {
+		key := resourceKey(doc, i)
+		unique := key
+		for n := 2; ; n++ {
+			if _, found := result[unique]; !found {
+				break
+			}
+			unique = fmt.Sprintf("%s (%d)", key, n)
+		}
+		result[unique] = doc
+	}
+	return result
+}
+
+// resourceKey identifies the resource in the document by its file name, or
+// else by its kind, namespace and name.
+func resourceKey(doc string, index int) string {
+	node, err := yaml.Parse(doc)
+	if err != nil {
+		return fmt.Sprintf("document %d", index+1)
+	}
+	annotations := node.GetAnnotations()
+	if path := annotations[kioutil.PathAnnotation]; path != "" {
+		return path
+	}
+	if path := annotations[kioutil.LegacyPathAnnotation]; path != "" { // nolint:staticcheck
+		return path
+	}
+	name := node.GetName()
+	if ns := node.GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+	if kind := node.GetKind(); kind != "" {
+		return kind + " " + name
+	}
+	return fmt.Sprintf("document %d", index+1)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opRemove
+	opAdd
+)
+
+type lineOp struct {
+	kind opKind
+	line string
+}
+
+// diffLines returns the edit script from a to b, based on their longest
+// common subsequence.
+func diffLines(a, b []string) []lineOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []lineOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, lineOp{kind: opEqual, line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, lineOp{kind: opRemove, line: a[i]})
+			i++
+		default:
+			ops = append(ops, lineOp{kind: opAdd, line: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, lineOp{kind: opRemove, line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, lineOp{kind: opAdd, line: b[j]})
+	}
+	return ops
+}
+
+type printer struct {
+	strings.Builder
+	color bool
+}
+
+func (p *printer) colored(color, s string) {
+	if p.color {
+		p.WriteString(color + s + colorReset + "\n")
+	} else {
+		p.WriteString(s + "\n")
+	}
+}
+
+func (p *printer) header(key, change string) {
+	p.colored(colorBold, fmt.Sprintf("=== %s (%s)", key, change))
+}
+
+// lines prints the changed lines and up to contextLines unchanged lines
+// around them. Omitted unchanged lines are marked with `...`.
+func (p *printer) lines(ops []lineOp) {
+	show := make([]bool, len(ops))
+	for i, op := range ops {
+		if op.kind == opEqual {
+			continue
+		}
+		for j := i - contextLines; j <= i+contextLines; j++ {
+			if j >= 0 && j < len(ops) {
+				show[j] = true
+			}
+		}
+	}
+
+	skipped := false
+	for i, op := range ops {
+		if !show[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			p.WriteString("  ...\n")
+			skipped = false
+		}
+		switch op.kind {
+		case opEqual:
+			p.WriteString("  " + op.line + "\n")
+		case opRemove:
+			p.colored(colorRed, "- "+op.line)
+		case opAdd:
+			p.colored(colorGreen, "+ "+op.line)
+		}
+	}
+	if skipped {
+		p.WriteString("  ...\n")
+	}
+}
+
+func (p *printer) summary(modified, added, removed int) {
+	changed := modified + added + removed
+	noun := "resources"
+	if changed == 1 {
+		noun = "resource"
+	}
+	p.colored(colorBold, fmt.Sprintf("%d %s changed (%d modified, %d added, %d removed)", changed, noun, modified, added, removed))
+}
@@ -0,0 +1,170 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diffutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: example
+data:
+  color: red
+`
+
+const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: example
+spec:
+  replicas: 1
+`
+
+func TestPrettyDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "equal",
+			a:    configMap,
+			b:    configMap,
+			want: "",
+		},
+		{
+			name: "changed field",
+			a:    configMap,
+			b:    strings.Replace(configMap, "color: red", "color: blue", 1),
+			want: `  ...
+    name: config
+    namespace: example
+  data:
+-   color: red
++   color: blue
+1 resource changed (1 modified, 0 added, 0 removed)
+`,
+		},
+		{
+			name: "added field",
+			a:    configMap,
+			b:    configMap + "  size: large\n",
+			want: `  ...
+    namespace: example
+  data:
+    color: red
++   size: large
+1 resource changed (1 modified, 0 added, 0 removed)
+`,
+		},
+		{
+			name: "modified resource",
+			a:    configMap + "---\n" + deployment,
+			b:    configMap + "---\n" + strings.Replace(deployment, "replicas: 1", "replicas: 3", 1),
+			want: `=== Deployment example/app (modified)
+  ...
+    name: app
+    namespace: example
+  spec:
+-   replicas: 1
++   replicas: 3
+1 resource changed (1 modified, 0 added, 0 removed)
+`,
+		},
+		{
+			name: "added and removed resources",
+			a:    configMap + "---\n" + deployment,
+			b:    "---\n" + deployment + "---\n" + strings.Replace(configMap, "name: config", "name: other", 1),
+			want: `=== ConfigMap example/config (removed)
+- apiVersion: v1
+- kind: ConfigMap
+- metadata:
+-   name: config
+-   namespace: example
+- data:
+-   color: red
+=== ConfigMap example/other (added)
++ apiVersion: v1
++ kind: ConfigMap
++ metadata:
++   name: other
++   namespace: example
++ data:
++   color: red
+2 resources changed (0 modified, 1 added, 1 removed)
+`,
+		},
+		{
+			name: "grouped by file name",
+			a: `metadata:
+  name: first
+  annotations:
+    internal.config.kubernetes.io/path: first.yaml
+value: 1
+---
+metadata:
+  name: second
+  annotations:
+    internal.config.kubernetes.io/path: second.yaml
+value: 2
+`,
+			b: `metadata:
+  name: first
+  annotations:
+    internal.config.kubernetes.io/path: first.yaml
+value: 1
+---
+metadata:
+  name: second
+  annotations:
+    internal.config.kubernetes.io/path: second.yaml
+value: 20
+`,
+			want: `=== second.yaml (modified)
+  ...
+    name: second
+    annotations:
+      internal.config.kubernetes.io/path: second.yaml
+- value: 2
++ value: 20
+1 resource changed (1 modified, 0 added, 0 removed)
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := prettyDiff(tc.a, tc.b, false)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected diff (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestPrettyDiffColor(t *testing.T) {
+	got := prettyDiff("value: 1\n", "value: 2\n", true)
+	want := colorRed + "- value: 1" + colorReset + "\n" +
+		colorGreen + "+ value: 2" + colorReset + "\n" +
+		colorBold + "1 resource changed (1 modified, 0 added, 0 removed)" + colorReset + "\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected diff (-want, +got): %s", diff)
+	}
+}
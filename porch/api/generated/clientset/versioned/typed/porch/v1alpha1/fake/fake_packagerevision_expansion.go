@@ -0,0 +1,34 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testing "k8s.io/client-go/testing"
+)
+
+// Diff takes the name of the packageRevision and of the base package revision, and returns the diff between them, and an error, if there is any.
+func (c *FakePackageRevisions) Diff(ctx context.Context, name, base string, opts v1.GetOptions) (result *v1alpha1.PackageRevisionDiff, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetSubresourceAction(packagerevisionsResource, c.ns, "diff", name), &v1alpha1.PackageRevisionDiff{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PackageRevisionDiff), err
+}
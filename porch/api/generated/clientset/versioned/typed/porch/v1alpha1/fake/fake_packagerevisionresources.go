@@ -126,3 +126,14 @@ func (c *FakePackageRevisionResources) Patch(ctx context.Context, name string, p
 	}
 	return obj.(*v1alpha1.PackageRevisionResources), err
 }
+
+// ResolveConflict takes the representation of a conflictResolution and updates it. Returns the server's representation of the conflictResolution, and an error, if there is any.
+func (c *FakePackageRevisionResources) ResolveConflict(ctx context.Context, packageRevisionResourcesName string, conflictResolution *v1alpha1.ConflictResolution, opts v1.UpdateOptions) (result *v1alpha1.ConflictResolution, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(packagerevisionresourcesResource, "resolveconflict", c.ns, conflictResolution), &v1alpha1.ConflictResolution{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ConflictResolution), err
+}
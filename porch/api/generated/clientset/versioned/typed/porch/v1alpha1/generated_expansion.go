@@ -18,6 +18,4 @@ package v1alpha1
 
 type FunctionExpansion interface{}
 
-type PackageRevisionExpansion interface{}
-
 type PackageRevisionResourcesExpansion interface{}
@@ -46,6 +46,7 @@ type PackageRevisionInterface interface {
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PackageRevision, err error)
 	UpdateApproval(ctx context.Context, packageRevisionName string, packageRevision *v1alpha1.PackageRevision, opts v1.UpdateOptions) (*v1alpha1.PackageRevision, error)
+	RejectApproval(ctx context.Context, packageRevisionName string, packageRevision *v1alpha1.PackageRevision, opts v1.UpdateOptions) (*v1alpha1.PackageRevision, error)
 
 	PackageRevisionExpansion
 }
@@ -208,3 +209,18 @@ func (c *packageRevisions) UpdateApproval(ctx context.Context, packageRevisionNa
 		Into(result)
 	return
 }
+
+// RejectApproval takes the top resource name and the representation of a packageRevision and updates it. Returns the server's representation of the packageRevision, and an error, if there is any.
+func (c *packageRevisions) RejectApproval(ctx context.Context, packageRevisionName string, packageRevision *v1alpha1.PackageRevision, opts v1.UpdateOptions) (result *v1alpha1.PackageRevision, err error) {
+	result = &v1alpha1.PackageRevision{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("packagerevisions").
+		Name(packageRevisionName).
+		SubResource("rejection").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(packageRevision).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+
+	scheme "github.com/GoogleContainerTools/kpt/porch/api/generated/clientset/versioned/scheme"
+	v1alpha1 "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PackageRevisionExpansion has the methods of PackageRevisionInterface which
+// are not generated.
+type PackageRevisionExpansion interface {
+	// Diff returns the files of the named package revision which differ from
+	// the base package revision. An empty base compares to the previous
+	// published revision of the package.
+	Diff(ctx context.Context, name, base string, opts v1.GetOptions) (*v1alpha1.PackageRevisionDiff, error)
+}
+
+// Diff takes the name of the packageRevision and of the base package revision, and returns the diff between them, and an error, if there is any.
+func (c *packageRevisions) Diff(ctx context.Context, name, base string, opts v1.GetOptions) (result *v1alpha1.PackageRevisionDiff, err error) {
+	result = &v1alpha1.PackageRevisionDiff{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("packagerevisions").
+		Name(name).
+		SubResource("diff").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		VersionedParams(&v1alpha1.PackageRevisionDiffOptions{Base: base}, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
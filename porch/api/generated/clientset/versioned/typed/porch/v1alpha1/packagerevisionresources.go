@@ -44,6 +44,8 @@ type PackageRevisionResourcesInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.PackageRevisionResourcesList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PackageRevisionResources, err error)
+	ResolveConflict(ctx context.Context, packageRevisionResourcesName string, conflictResolution *v1alpha1.ConflictResolution, opts v1.UpdateOptions) (*v1alpha1.ConflictResolution, error)
+
 	PackageRevisionResourcesExpansion
 }
 
@@ -174,3 +176,18 @@ func (c *packageRevisionResources) Patch(ctx context.Context, name string, pt ty
 		Into(result)
 	return
 }
+
+// ResolveConflict takes the top resource name and the representation of a conflictResolution and updates it. Returns the server's representation of the conflictResolution, and an error, if there is any.
+func (c *packageRevisionResources) ResolveConflict(ctx context.Context, packageRevisionResourcesName string, conflictResolution *v1alpha1.ConflictResolution, opts v1.UpdateOptions) (result *v1alpha1.ConflictResolution, err error) {
+	result = &v1alpha1.ConflictResolution{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("packagerevisionresources").
+		Name(packageRevisionResourcesName).
+		SubResource("resolveconflict").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(conflictResolution).
+		Do(ctx).
+		Into(result)
+	return
+}
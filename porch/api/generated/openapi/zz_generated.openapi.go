@@ -29,91 +29,128 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Function":                     schema_porch_api_porch_v1alpha1_Function(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionConfig":               schema_porch_api_porch_v1alpha1_FunctionConfig(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionEvalTaskSpec":         schema_porch_api_porch_v1alpha1_FunctionEvalTaskSpec(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionList":                 schema_porch_api_porch_v1alpha1_FunctionList(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionRef":                  schema_porch_api_porch_v1alpha1_FunctionRef(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionSpec":                 schema_porch_api_porch_v1alpha1_FunctionSpec(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionStatus":               schema_porch_api_porch_v1alpha1_FunctionStatus(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.GitPackage":                   schema_porch_api_porch_v1alpha1_GitPackage(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.OciPackage":                   schema_porch_api_porch_v1alpha1_OciPackage(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageCloneTaskSpec":         schema_porch_api_porch_v1alpha1_PackageCloneTaskSpec(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageInitTaskSpec":          schema_porch_api_porch_v1alpha1_PackageInitTaskSpec(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackagePatchTaskSpec":         schema_porch_api_porch_v1alpha1_PackagePatchTaskSpec(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision":              schema_porch_api_porch_v1alpha1_PackageRevision(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionList":          schema_porch_api_porch_v1alpha1_PackageRevisionList(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionRef":           schema_porch_api_porch_v1alpha1_PackageRevisionRef(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResources":     schema_porch_api_porch_v1alpha1_PackageRevisionResources(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesList": schema_porch_api_porch_v1alpha1_PackageRevisionResourcesList(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesSpec": schema_porch_api_porch_v1alpha1_PackageRevisionResourcesSpec(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionSpec":          schema_porch_api_porch_v1alpha1_PackageRevisionSpec(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionStatus":        schema_porch_api_porch_v1alpha1_PackageRevisionStatus(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RepositoryRef":                schema_porch_api_porch_v1alpha1_RepositoryRef(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.SecretRef":                    schema_porch_api_porch_v1alpha1_SecretRef(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Selector":                     schema_porch_api_porch_v1alpha1_Selector(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Task":                         schema_porch_api_porch_v1alpha1_Task(ref),
-		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.UpstreamPackage":              schema_porch_api_porch_v1alpha1_UpstreamPackage(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                             schema_pkg_apis_meta_v1_APIGroup(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroupList":                                         schema_pkg_apis_meta_v1_APIGroupList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResource":                                          schema_pkg_apis_meta_v1_APIResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResourceList":                                      schema_pkg_apis_meta_v1_APIResourceList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIVersions":                                          schema_pkg_apis_meta_v1_APIVersions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ApplyOptions":                                         schema_pkg_apis_meta_v1_ApplyOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Condition":                                            schema_pkg_apis_meta_v1_Condition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.CreateOptions":                                        schema_pkg_apis_meta_v1_CreateOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.DeleteOptions":                                        schema_pkg_apis_meta_v1_DeleteOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                                             schema_pkg_apis_meta_v1_Duration(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldsV1":                                             schema_pkg_apis_meta_v1_FieldsV1(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GetOptions":                                           schema_pkg_apis_meta_v1_GetOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind":                                            schema_pkg_apis_meta_v1_GroupKind(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupResource":                                        schema_pkg_apis_meta_v1_GroupResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersion":                                         schema_pkg_apis_meta_v1_GroupVersion(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionForDiscovery":                             schema_pkg_apis_meta_v1_GroupVersionForDiscovery(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionKind":                                     schema_pkg_apis_meta_v1_GroupVersionKind(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionResource":                                 schema_pkg_apis_meta_v1_GroupVersionResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.InternalEvent":                                        schema_pkg_apis_meta_v1_InternalEvent(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector":                                        schema_pkg_apis_meta_v1_LabelSelector(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelectorRequirement":                             schema_pkg_apis_meta_v1_LabelSelectorRequirement(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.List":                                                 schema_pkg_apis_meta_v1_List(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta":                                             schema_pkg_apis_meta_v1_ListMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ListOptions":                                          schema_pkg_apis_meta_v1_ListOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ManagedFieldsEntry":                                   schema_pkg_apis_meta_v1_ManagedFieldsEntry(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                                            schema_pkg_apis_meta_v1_MicroTime(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                                           schema_pkg_apis_meta_v1_ObjectMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.OwnerReference":                                       schema_pkg_apis_meta_v1_OwnerReference(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadata":                                schema_pkg_apis_meta_v1_PartialObjectMetadata(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadataList":                            schema_pkg_apis_meta_v1_PartialObjectMetadataList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Patch":                                                schema_pkg_apis_meta_v1_Patch(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PatchOptions":                                         schema_pkg_apis_meta_v1_PatchOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Preconditions":                                        schema_pkg_apis_meta_v1_Preconditions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.RootPaths":                                            schema_pkg_apis_meta_v1_RootPaths(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ServerAddressByClientCIDR":                            schema_pkg_apis_meta_v1_ServerAddressByClientCIDR(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Status":                                               schema_pkg_apis_meta_v1_Status(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusCause":                                          schema_pkg_apis_meta_v1_StatusCause(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusDetails":                                        schema_pkg_apis_meta_v1_StatusDetails(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Table":                                                schema_pkg_apis_meta_v1_Table(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableColumnDefinition":                                schema_pkg_apis_meta_v1_TableColumnDefinition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableOptions":                                         schema_pkg_apis_meta_v1_TableOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRow":                                             schema_pkg_apis_meta_v1_TableRow(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRowCondition":                                    schema_pkg_apis_meta_v1_TableRowCondition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                                                 schema_pkg_apis_meta_v1_Time(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp":                                            schema_pkg_apis_meta_v1_Timestamp(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":                                             schema_pkg_apis_meta_v1_TypeMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.UpdateOptions":                                        schema_pkg_apis_meta_v1_UpdateOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.WatchEvent":                                           schema_pkg_apis_meta_v1_WatchEvent(ref),
-		"k8s.io/apimachinery/pkg/runtime.RawExtension":                                              schema_k8sio_apimachinery_pkg_runtime_RawExtension(ref),
-		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                                  schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
-		"k8s.io/apimachinery/pkg/runtime.Unknown":                                                   schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
-		"k8s.io/apimachinery/pkg/version.Info":                                                      schema_k8sio_apimachinery_pkg_version_Info(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConflictResolution":                        schema_porch_api_porch_v1alpha1_ConflictResolution(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConflictResolutionSpec":                    schema_porch_api_porch_v1alpha1_ConflictResolutionSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConflictResolutionStatus":                  schema_porch_api_porch_v1alpha1_ConflictResolutionStatus(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConnectionTestResult":                      schema_porch_api_porch_v1alpha1_ConnectionTestResult(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.CostEstimate":                              schema_porch_api_porch_v1alpha1_CostEstimate(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.DependencyGraphEdge":                       schema_porch_api_porch_v1alpha1_DependencyGraphEdge(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FileDiff":                                  schema_porch_api_porch_v1alpha1_FileDiff(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FileMetadata":                              schema_porch_api_porch_v1alpha1_FileMetadata(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FileUpdate":                                schema_porch_api_porch_v1alpha1_FileUpdate(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Function":                                  schema_porch_api_porch_v1alpha1_Function(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionConfig":                            schema_porch_api_porch_v1alpha1_FunctionConfig(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionEvalTaskSpec":                      schema_porch_api_porch_v1alpha1_FunctionEvalTaskSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionList":                              schema_porch_api_porch_v1alpha1_FunctionList(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionRef":                               schema_porch_api_porch_v1alpha1_FunctionRef(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionSpec":                              schema_porch_api_porch_v1alpha1_FunctionSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionStatus":                            schema_porch_api_porch_v1alpha1_FunctionStatus(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.GitLock":                                   schema_porch_api_porch_v1alpha1_GitLock(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.GitPackage":                                schema_porch_api_porch_v1alpha1_GitPackage(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.LintFinding":                               schema_porch_api_porch_v1alpha1_LintFinding(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.LintReport":                                schema_porch_api_porch_v1alpha1_LintReport(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.OciPackage":                                schema_porch_api_porch_v1alpha1_OciPackage(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageCloneTaskSpec":                      schema_porch_api_porch_v1alpha1_PackageCloneTaskSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageInitTaskSpec":                       schema_porch_api_porch_v1alpha1_PackageInitTaskSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackagePatchTaskSpec":                      schema_porch_api_porch_v1alpha1_PackagePatchTaskSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceMatch":                      schema_porch_api_porch_v1alpha1_PackageResourceMatch(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceSearch":                     schema_porch_api_porch_v1alpha1_PackageResourceSearch(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceSearchResult":               schema_porch_api_porch_v1alpha1_PackageResourceSearchResult(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceSearchSpec":                 schema_porch_api_porch_v1alpha1_PackageResourceSearchSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision":                           schema_porch_api_porch_v1alpha1_PackageRevision(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionDiff":                       schema_porch_api_porch_v1alpha1_PackageRevisionDiff(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionDiffOptions":                schema_porch_api_porch_v1alpha1_PackageRevisionDiffOptions(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionLineage":                    schema_porch_api_porch_v1alpha1_PackageRevisionLineage(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionLineageEntry":               schema_porch_api_porch_v1alpha1_PackageRevisionLineageEntry(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionList":                       schema_porch_api_porch_v1alpha1_PackageRevisionList(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionRef":                        schema_porch_api_porch_v1alpha1_PackageRevisionRef(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResources":                  schema_porch_api_porch_v1alpha1_PackageRevisionResources(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesBatchUpdate":       schema_porch_api_porch_v1alpha1_PackageRevisionResourcesBatchUpdate(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesBatchUpdateSpec":   schema_porch_api_porch_v1alpha1_PackageRevisionResourcesBatchUpdateSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesBatchUpdateStatus": schema_porch_api_porch_v1alpha1_PackageRevisionResourcesBatchUpdateStatus(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesList":              schema_porch_api_porch_v1alpha1_PackageRevisionResourcesList(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesSpec":              schema_porch_api_porch_v1alpha1_PackageRevisionResourcesSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesStatus":            schema_porch_api_porch_v1alpha1_PackageRevisionResourcesStatus(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionSchemaDiff":                 schema_porch_api_porch_v1alpha1_PackageRevisionSchemaDiff(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionSchemaDiffOptions":          schema_porch_api_porch_v1alpha1_PackageRevisionSchemaDiffOptions(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionSpec":                       schema_porch_api_porch_v1alpha1_PackageRevisionSpec(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionStatus":                     schema_porch_api_porch_v1alpha1_PackageRevisionStatus(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionTarget":                     schema_porch_api_porch_v1alpha1_PackageRevisionTarget(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionValidationReport":           schema_porch_api_porch_v1alpha1_PackageRevisionValidationReport(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RepositoryConnectionTest":                  schema_porch_api_porch_v1alpha1_RepositoryConnectionTest(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RepositoryDependencyGraph":                 schema_porch_api_porch_v1alpha1_RepositoryDependencyGraph(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RepositoryDependencyGraphOptions":          schema_porch_api_porch_v1alpha1_RepositoryDependencyGraphOptions(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RepositoryRef":                             schema_porch_api_porch_v1alpha1_RepositoryRef(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RequiredSecret":                            schema_porch_api_porch_v1alpha1_RequiredSecret(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ResourceCostEstimate":                      schema_porch_api_porch_v1alpha1_ResourceCostEstimate(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ResourceValidationError":                   schema_porch_api_porch_v1alpha1_ResourceValidationError(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.SecretRef":                                 schema_porch_api_porch_v1alpha1_SecretRef(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Selector":                                  schema_porch_api_porch_v1alpha1_Selector(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Task":                                      schema_porch_api_porch_v1alpha1_Task(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.TemplateExpansionRequest":                  schema_porch_api_porch_v1alpha1_TemplateExpansionRequest(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.TransferRequest":                           schema_porch_api_porch_v1alpha1_TransferRequest(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.UpstreamLock":                              schema_porch_api_porch_v1alpha1_UpstreamLock(ref),
+		"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.UpstreamPackage":                           schema_porch_api_porch_v1alpha1_UpstreamPackage(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                                          schema_pkg_apis_meta_v1_APIGroup(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroupList":                                                      schema_pkg_apis_meta_v1_APIGroupList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResource":                                                       schema_pkg_apis_meta_v1_APIResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResourceList":                                                   schema_pkg_apis_meta_v1_APIResourceList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIVersions":                                                       schema_pkg_apis_meta_v1_APIVersions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ApplyOptions":                                                      schema_pkg_apis_meta_v1_ApplyOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Condition":                                                         schema_pkg_apis_meta_v1_Condition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.CreateOptions":                                                     schema_pkg_apis_meta_v1_CreateOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.DeleteOptions":                                                     schema_pkg_apis_meta_v1_DeleteOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                                                          schema_pkg_apis_meta_v1_Duration(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldsV1":                                                          schema_pkg_apis_meta_v1_FieldsV1(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GetOptions":                                                        schema_pkg_apis_meta_v1_GetOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind":                                                         schema_pkg_apis_meta_v1_GroupKind(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupResource":                                                     schema_pkg_apis_meta_v1_GroupResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersion":                                                      schema_pkg_apis_meta_v1_GroupVersion(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionForDiscovery":                                          schema_pkg_apis_meta_v1_GroupVersionForDiscovery(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionKind":                                                  schema_pkg_apis_meta_v1_GroupVersionKind(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionResource":                                              schema_pkg_apis_meta_v1_GroupVersionResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.InternalEvent":                                                     schema_pkg_apis_meta_v1_InternalEvent(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector":                                                     schema_pkg_apis_meta_v1_LabelSelector(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelectorRequirement":                                          schema_pkg_apis_meta_v1_LabelSelectorRequirement(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.List":                                                              schema_pkg_apis_meta_v1_List(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta":                                                          schema_pkg_apis_meta_v1_ListMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ListOptions":                                                       schema_pkg_apis_meta_v1_ListOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ManagedFieldsEntry":                                                schema_pkg_apis_meta_v1_ManagedFieldsEntry(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                                                         schema_pkg_apis_meta_v1_MicroTime(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                                                        schema_pkg_apis_meta_v1_ObjectMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.OwnerReference":                                                    schema_pkg_apis_meta_v1_OwnerReference(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadata":                                             schema_pkg_apis_meta_v1_PartialObjectMetadata(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadataList":                                         schema_pkg_apis_meta_v1_PartialObjectMetadataList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Patch":                                                             schema_pkg_apis_meta_v1_Patch(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PatchOptions":                                                      schema_pkg_apis_meta_v1_PatchOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Preconditions":                                                     schema_pkg_apis_meta_v1_Preconditions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.RootPaths":                                                         schema_pkg_apis_meta_v1_RootPaths(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ServerAddressByClientCIDR":                                         schema_pkg_apis_meta_v1_ServerAddressByClientCIDR(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Status":                                                            schema_pkg_apis_meta_v1_Status(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusCause":                                                       schema_pkg_apis_meta_v1_StatusCause(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusDetails":                                                     schema_pkg_apis_meta_v1_StatusDetails(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Table":                                                             schema_pkg_apis_meta_v1_Table(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableColumnDefinition":                                             schema_pkg_apis_meta_v1_TableColumnDefinition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableOptions":                                                      schema_pkg_apis_meta_v1_TableOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRow":                                                          schema_pkg_apis_meta_v1_TableRow(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRowCondition":                                                 schema_pkg_apis_meta_v1_TableRowCondition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                                                              schema_pkg_apis_meta_v1_Time(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp":                                                         schema_pkg_apis_meta_v1_Timestamp(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":                                                          schema_pkg_apis_meta_v1_TypeMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.UpdateOptions":                                                     schema_pkg_apis_meta_v1_UpdateOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.WatchEvent":                                                        schema_pkg_apis_meta_v1_WatchEvent(ref),
+		"k8s.io/apimachinery/pkg/runtime.RawExtension":                                                           schema_k8sio_apimachinery_pkg_runtime_RawExtension(ref),
+		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                                               schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
+		"k8s.io/apimachinery/pkg/runtime.Unknown":                                                                schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
+		"k8s.io/apimachinery/pkg/version.Info":                                                                   schema_k8sio_apimachinery_pkg_version_Info(ref),
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_Function(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_ConflictResolution(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Function represents a kpt function discovered in a repository Function resources are created automatically by discovery in a registered Repository. Function resource names will be computed as <Repository Name>:<Function Name> to ensure uniqueness of names, and will follow formatting of [DNS Subdomain Names](https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#dns-subdomain-names).",
+				Description: "ConflictResolution is put to the `resolveconflict` subresource of PackageRevisionResources to replace a file of a draft package revision left with merge conflicts by its resolved content. The name of the ConflictResolution is the name of the package revision. The response reports the files still in conflict.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -139,47 +176,63 @@ func schema_porch_api_porch_v1alpha1_Function(ref common.ReferenceCallback) comm
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionSpec"),
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConflictResolutionSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionStatus"),
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConflictResolutionStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionSpec", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConflictResolutionSpec", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConflictResolutionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_FunctionConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_ConflictResolutionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "FunctionConfig specifies all the valid types of the function config for this function. If unspecified, defaults to v1/ConfigMap. For example, function `set-namespace` accepts both `ConfigMap` and `SetNamespace`",
+				Description: "ConflictResolutionSpec is the resolution of the conflicts of a file.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"filename": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Description: "Filename is the path of the file in conflict, relative to the package.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"resolvedContent": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "ResolvedContent is the content of the file with its conflicts resolved. It must not contain conflict markers.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"requiredFields": {
+				},
+				Required: []string{"filename", "resolvedContent"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_ConflictResolutionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConflictResolutionStatus reports the result of a conflict resolution.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"remainingConflicts": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Experimental: requiredFields tells necessary fields and is aimed to help users write the FunctionConfig. Otherwise, users can get the required fields info from the function evaluation error message.",
+							Description: "RemainingConflicts are the files of the package revision still in conflict. The package revision is free of conflicts if it is empty.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -198,82 +251,55 @@ func schema_porch_api_porch_v1alpha1_FunctionConfig(ref common.ReferenceCallback
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_FunctionEvalTaskSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_ConnectionTestResult(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "ConnectionTestResult is the result of a repository connection test.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"subpackage": {
-						SchemaProps: spec.SchemaProps{
-							Description: "`Subpackage` is a directory path to a subpackage in which to evaluate the function.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"image": {
+					"success": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`Image` specifies the function image, such as `gcr.io/kpt-fn/gatekeeper:v0.2`. Use of `Image` is mutually exclusive with `FunctionRef`.",
-							Type:        []string{"string"},
+							Description: "Success is true if porch could list the contents of the repository.",
+							Default:     false,
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"functionRef": {
-						SchemaProps: spec.SchemaProps{
-							Description: "`FunctionRef` specifies the function by reference to a Function resource. Mutually exclusive with `Image`.",
-							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionRef"),
-						},
-					},
-					"configMap": {
+					"latencyMs": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`ConfigMap` specifies the function config (https://kpt.dev/reference/cli/fn/eval/). Mutually exclusive with Config.",
-							Type:        []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "LatencyMs is the duration of the test in milliseconds.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"includeMetaResources": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If enabled, meta resources (i.e. `Kptfile` and `functionConfig`) are included in the input to the function. By default it is disabled.",
-							Type:        []string{"boolean"},
+							Description: "Error describes why the test failed.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"enableNetwork": {
+					"authenticatedAs": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`EnableNetwork` controls whether the function has access to network. Defaults to `false`.",
-							Type:        []string{"boolean"},
+							Description: "AuthenticatedAs is the user name of the credentials of the repository, if it has any.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"match": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Match specifies the selection criteria for the function evaluation. Corresponds to `kpt fn eval --match-???` flgs (https://kpt.dev/reference/cli/fn/eval/).",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Selector"),
-						},
-					},
 				},
+				Required: []string{"success", "latencyMs"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionRef", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Selector"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_FunctionList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_CostEstimate(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "FunctionList",
+				Description: "CostEstimate is the approximate hourly cost of deploying the resources of a package revision. It is served by the `cost-estimate` subresource of PackageRevision; the posted object selects the unit prices.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -293,308 +319,1833 @@ func schema_porch_api_porch_v1alpha1_FunctionList(ref common.ReferenceCallback)
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"items": {
+					"pricingConfig": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "PricingConfig is the name of the PricingConfig, in the namespace of the package revision, providing the unit prices. Defaults to `default`.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources are the estimates for the resource types with a cost.",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Function"),
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ResourceCostEstimate"),
 									},
 								},
 							},
 						},
 					},
+					"hourlyCostUSD": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HourlyCostUSD is the estimated total hourly cost in US dollars.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
-				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Function", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ResourceCostEstimate", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_FunctionRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_DependencyGraphEdge(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "FunctionRef is a reference to a Function resource.",
+				Description: "DependencyGraphEdge records that the downstream package was cloned from the upstream package.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"upstream": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name is the name of the Function resource referenced. The resource is expected to be within the same namespace.",
+							Description: "Upstream is the package which was cloned.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"downstream": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Downstream is the package created by the clone.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"upstream", "downstream"},
 			},
 		},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_FunctionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_FileDiff(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "FunctionSpec defines the desired state of a Function",
+				Description: "FileDiff is the difference of a file between two package revisions.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"image": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Image specifies the function image, such as 'gcr.io/kpt-fn/gatekeeper:v0.2'.",
+							Description: "Name is the path of the file, relative to the package.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"repositoryRef": {
-						SchemaProps: spec.SchemaProps{
-							Description: "RepositoryRef references the repository in which the function is located.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RepositoryRef"),
-						},
-					},
-					"functionTypes": {
-						SchemaProps: spec.SchemaProps{
-							Description: "FunctionType specifies the function types (mutator, validator or/and others).",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
-						},
-					},
-					"functionConfigs": {
-						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionConfig"),
-									},
-								},
-							},
-						},
-					},
-					"keywords": {
+					"oldContent": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Keywords are used as filters to provide correlation in function discovery.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "OldContent is the content of the file in the base package revision, empty if the file was added.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"description": {
+					"newContent": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Description is a short description of the function.",
-							Default:     "",
+							Description: "NewContent is the content of the file in the package revision, empty if the file was removed.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"documentationUrl": {
+					"unifiedDiff": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`DocumentationUrl specifies the URL of comprehensive function documentation`",
+							Description: "UnifiedDiff is the unified diff from the old to the new content.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"image", "repositoryRef", "description"},
+				Required: []string{"name", "unifiedDiff"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionConfig", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RepositoryRef"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_FunctionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_FileMetadata(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "FunctionStatus defines the observed state of Function",
+				Description: "FileMetadata describes the content of a single package file.",
 				Type:        []string{"object"},
-			},
-		},
-	}
-}
-
-func schema_porch_api_porch_v1alpha1_GitPackage(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"repo": {
+					"filename": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Address of the Git repository, for example:\n  `https://github.com/GoogleCloudPlatform/blueprints.git`",
+							Description: "Filename is the path of the file within the package.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"ref": {
+					"contentType": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`Ref` is the git ref containing the package. Ref can be a branch, tag, or commit SHA.",
-							Default:     "",
+							Description: "ContentType is the detected media type of the file, such as `application/yaml`.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"directory": {
+					"encoding": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Directory within the Git repository where the packages are stored. A subdirectory of this directory containing a Kptfile is considered a package.",
-							Default:     "",
+							Description: "Encoding is the character encoding of the file, `utf-8` or `binary`.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"secretRef": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Reference to secret containing authentication credentials. Optional.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.SecretRef"),
-						},
-					},
 				},
-				Required: []string{"repo", "ref", "directory"},
+				Required: []string{"filename"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.SecretRef"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_OciPackage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_FileUpdate(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "OciPackage describes a repository compatible with the Open Coutainer Registry standard.",
+				Description: "FileUpdate is a change to a single file of a package.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"image": {
+					"filename": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Image is the address of an OCI image.",
+							Description: "Filename is the path of the file, relative to the package.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-				},
-				Required: []string{"image"},
-			},
-		},
-	}
-}
-
-func schema_porch_api_porch_v1alpha1_PackageCloneTaskSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"upstreamRef": {
+					"content": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`Upstream` is the reference to the upstream package to clone.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.UpstreamPackage"),
+							Description: "Content is the new content of the file. Ignored for deletions.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"strategy": {
+					"operation": {
 						SchemaProps: spec.SchemaProps{
-							Description: "\n\tDefines which strategy should be used to update the package. It defaults to 'resource-merge'.\n * resource-merge: Perform a structural comparison of the original /\n   updated resources, and merge the changes into the local package.\n * fast-forward: Fail without updating if the local package was modified\n   since it was fetched.\n * force-delete-replace: Wipe all the local changes to the package and replace\n   it with the remote version.",
+							Description: "Operation is the change made to the file.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"filename", "operation"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.UpstreamPackage"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_PackageInitTaskSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_Function(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PackageInitTaskSpec defines the package initialization task.",
+				Description: "Function represents a kpt function discovered in a repository Function resources are created automatically by discovery in a registered Repository. Function resource names will be computed as <Repository Name>:<Function Name> to ensure uniqueness of names, and will follow formatting of [DNS Subdomain Names](https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#dns-subdomain-names).",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"subpackage": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`Subpackage` is a directory path to a subpackage to initialize. If unspecified, the main package will be initialized.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"description": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`Description` is a short description of the package.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"keywords": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`Keywords` is a list of keywords describing the package.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionSpec", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_FunctionConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FunctionConfig specifies all the valid types of the function config for this function. If unspecified, defaults to v1/ConfigMap. For example, function `set-namespace` accepts both `ConfigMap` and `SetNamespace`",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requiredFields": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Experimental: requiredFields tells necessary fields and is aimed to help users write the FunctionConfig. Otherwise, users can get the required fields info from the function evaluation error message.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_FunctionEvalTaskSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"subpackage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`Subpackage` is a directory path to a subpackage in which to evaluate the function.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`Image` specifies the function image, such as `gcr.io/kpt-fn/gatekeeper:v0.2`. Use of `Image` is mutually exclusive with `FunctionRef`.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"functionRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`FunctionRef` specifies the function by reference to a Function resource. Mutually exclusive with `Image`.",
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionRef"),
+						},
+					},
+					"configMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`ConfigMap` specifies the function config (https://kpt.dev/reference/cli/fn/eval/). Mutually exclusive with Config.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"includeMetaResources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If enabled, meta resources (i.e. `Kptfile` and `functionConfig`) are included in the input to the function. By default it is disabled.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"enableNetwork": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`EnableNetwork` controls whether the function has access to network. Defaults to `false`.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"match": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Match specifies the selection criteria for the function evaluation. Corresponds to `kpt fn eval --match-???` flgs (https://kpt.dev/reference/cli/fn/eval/).",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Selector"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionRef", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Selector"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_FunctionList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FunctionList",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Function"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Function", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_FunctionRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FunctionRef is a reference to a Function resource.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the Function resource referenced. The resource is expected to be within the same namespace.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_FunctionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FunctionSpec defines the desired state of a Function",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image specifies the function image, such as 'gcr.io/kpt-fn/gatekeeper:v0.2'.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"repositoryRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RepositoryRef references the repository in which the function is located.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RepositoryRef"),
+						},
+					},
+					"functionTypes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FunctionType specifies the function types (mutator, validator or/and others).",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"functionConfigs": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionConfig"),
+									},
+								},
+							},
+						},
+					},
+					"keywords": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Keywords are used as filters to provide correlation in function discovery.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description is a short description of the function.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"documentationUrl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`DocumentationUrl specifies the URL of comprehensive function documentation`",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"image", "repositoryRef", "description"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FunctionConfig", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RepositoryRef"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_FunctionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FunctionStatus defines the observed state of Function",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_GitLock(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GitLock identifies the commit of an upstream package in a Git repository.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"repo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Address of the Git repository.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"directory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Directory of the package within the Git repository.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ref": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ref is the git ref the package was fetched from.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"commit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Commit is the commit the ref resolved to when the package was fetched.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_GitPackage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"repo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Address of the Git repository, for example:\n  `https://github.com/GoogleCloudPlatform/blueprints.git`",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ref": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`Ref` is the git ref containing the package. Ref can be a branch, tag, or commit SHA.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"directory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Directory within the Git repository where the packages are stored. A subdirectory of this directory containing a Kptfile is considered a package.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reference to secret containing authentication credentials. Optional.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.SecretRef"),
+						},
+					},
+				},
+				Required: []string{"repo", "ref", "directory"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.SecretRef"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_LintFinding(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LintFinding describes a best-practice violation found in a package resource.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"linter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Linter is the name of the linter reporting the finding, such as `NoLatestTag`.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity is the severity of the finding: `warning` or `error`.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"filename": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Filename is the path of the file containing the resource, relative to the package.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resource identifies the resource as `Kind/name`.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message describes the problem.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"linter", "severity", "filename", "message"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_LintReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LintReport is the result of running the built-in best-practice linters against the resources of a package revision. It is served by the `lint` subresource of PackageRevision; the posted object selects the LintPolicy.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"policy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Policy is the name of the LintPolicy, in the namespace of the package revision, configuring the severity of the linters. If unspecified, the `default` LintPolicy is used if it exists, and the built-in severities otherwise.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"passed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Passed is true if no linter reported a finding with severity `error`.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"findings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Findings lists the problems reported by the linters.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.LintFinding"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"passed"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.LintFinding", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_OciPackage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OciPackage describes a repository compatible with the Open Coutainer Registry standard.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image is the address of an OCI image.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"image"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageCloneTaskSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"upstreamRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`Upstream` is the reference to the upstream package to clone.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.UpstreamPackage"),
+						},
+					},
+					"strategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "\n\tDefines which strategy should be used to update the package. It defaults to 'resource-merge'.\n * resource-merge: Perform a structural comparison of the original /\n   updated resources, and merge the changes into the local package.\n * fast-forward: Fail without updating if the local package was modified\n   since it was fetched.\n * force-delete-replace: Wipe all the local changes to the package and replace\n   it with the remote version.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.UpstreamPackage"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageInitTaskSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageInitTaskSpec defines the package initialization task.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"subpackage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`Subpackage` is a directory path to a subpackage to initialize. If unspecified, the main package will be initialized.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`Description` is a short description of the package.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"keywords": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`Keywords` is a list of keywords describing the package.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"site": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`Site is a link to page with information about the package.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackagePatchTaskSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"patches": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageResourceMatch(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageResourceMatch is a resource matching a search.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"packageRevisionName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PackageRevisionName is the name of the package revision containing the resource.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"filename": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Filename is the path of the file containing the resource, relative to the package.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"matchedResource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MatchedResource identifies the resource, as `<kind>/<name>`.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"packageRevisionName", "filename", "matchedResource"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageResourceSearch(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageResourceSearch searches the resources of the package revisions in its namespace. It is create-only: the posted object carries the query and the returned object lists the matching resources.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceSearchSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceSearchResult"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceSearchResult", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceSearchSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageResourceSearchResult(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageResourceSearchResult lists the resources matching a search.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"matches": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Matches lists up to 100 matching resources.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceMatch"),
+									},
+								},
+							},
+						},
+					},
+					"nextPageToken": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NextPageToken is set if there are more matches; pass it as the pageToken of the next search to retrieve them.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageResourceMatch"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageResourceSearchSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageResourceSearchSpec defines the query and the package revisions to search.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"query": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Query is a JSONPath expression evaluated against each resource, for example `{.spec.template.spec.containers[?(@.image==\"nginx:1.21\")]}`. The braces may be omitted. A resource matches if the expression yields a non-empty value.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"repositories": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Repositories limits the search to the named repositories. If unspecified, all repositories in the namespace are searched.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"pageToken": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PageToken is the nextPageToken returned by a previous search with the same query, from which to continue.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"query"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevision(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevision",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionSpec", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionDiff(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionDiff lists the files of a package revision which differ from a base package revision, with a unified diff of each. It is served by the `diff` subresource of PackageRevision.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"base": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Base is the name of the package revision the files are compared to. It is empty if the package revision has no previous revision, in which case all files are reported as added.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"files": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Files are the files which differ, sorted by name.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FileDiff"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FileDiff", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionDiffOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionDiffOptions are the query parameters of the `diff` subresource of PackageRevision.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"base": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Base is the name of the package revision to compare to. Defaults to the previous published revision of the package.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionLineage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionLineage describes the clone ancestry of a package revision. It is served by the `lineage` subresource of PackageRevision.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"depth": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Depth is the number of clone operations between the package revision and the original package it descends from.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"omitted": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Omitted is the number of the oldest ancestors which are not listed in `ancestors` because the recorded ancestry is limited.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"ancestors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ancestors lists the ancestors of the package revision, starting with the oldest recorded ancestor and ending with the direct upstream. Each ancestor was cloned from the one preceding it.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionLineageEntry"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"depth"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionLineageEntry", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionLineageEntry(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionLineageEntry identifies an ancestor of a package revision.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name identifies the ancestor package as `repository/package`, or the Git repository URL and package directory for packages cloned directly from Git.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"revision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Revision of the ancestor package.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionList",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionRef is a reference to a package revision.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`Name` is the name of the referenced PackageRevision resource.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionResources(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionResources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesSpec", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionResourcesBatchUpdate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionResourcesBatchUpdate is posted to the `batchupdate` subresource of PackageRevisionResources to change several files of a draft package revision atomically: either all updates are committed together, or none are. The response reports the resulting commit.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesBatchUpdateSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesBatchUpdateStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesBatchUpdateSpec", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesBatchUpdateStatus"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionResourcesBatchUpdateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionResourcesBatchUpdateSpec lists the file updates to apply.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resourceVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceVersion is the resource version of the PackageRevisionResources the updates were made against. The batch is rejected with a conflict if the package revision has changed since.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"updates": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Updates are the changes to make to the files of the package.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FileUpdate"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"resourceVersion"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FileUpdate"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionResourcesBatchUpdateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionResourcesBatchUpdateStatus reports the result of a batch update.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"commit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Commit is the SHA of the commit containing the updates.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionResourcesList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionResourcesList",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResources"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResources", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionResourcesSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionResourcesSpec represents resources (as ResourceList serialized as yaml string) of the PackageRevision.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionResourcesStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionResourcesStatus represents state of the rendered package resources.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"files": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Files contains metadata about each file in `spec.resources`, keyed by file name.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FileMetadata"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.FileMetadata"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionSchemaDiff(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionSchemaDiff summarizes, by API kind, how the resources of a package revision differ from the resources of a base package revision. It is served by the `schemadiff` subresource of PackageRevisionResources. Kinds are identified as `apiVersion/kind`, such as `apps/v1/Deployment`.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"base": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Base is the name of the package revision the resources are compared to.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"addedKinds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AddedKinds lists kinds with resources in the package revision but not in the base.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"removedKinds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RemovedKinds lists kinds with resources in the base but not in the package revision.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"modifiedKinds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ModifiedKinds maps kinds present in both revisions whose resources differ to the paths of the changed fields, such as `spec.replicas`.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type: []string{"array"},
+										Items: &spec.SchemaOrArray{
+											Schema: &spec.Schema{
+												SchemaProps: spec.SchemaProps{
+													Default: "",
+													Type:    []string{"string"},
+													Format:  "",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"base"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionSchemaDiffOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionSchemaDiffOptions are the query parameters of the `schemadiff` subresource of PackageRevisionResources.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"base": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Base is the name of the package revision to compare to.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionSpec defines the desired state of PackageRevision",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"packageName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"revision": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"repository": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lifecycle": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"tasks": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Task"),
 									},
 								},
 							},
 						},
 					},
-					"site": {
+					"changelogEntry": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`Site is a link to page with information about the package.",
+							Description: "ChangelogEntry is a human-readable release note recorded when the package revision is published.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"renderPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RenderPolicy controls when the package revision is rendered. One of OnPropose, OnApprove or Manual; defaults to OnPropose.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requiredSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequiredSecrets lists the secrets which must exist in the target cluster before the package revision can be approved.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RequiredSecret"),
+									},
+								},
+							},
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target describes the cluster the package revision is deployed to.",
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionTarget"),
+						},
+					},
+					"imageLocks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageLocks maps the function images of the package, as referenced in its Kptfile, to the digests they are pinned to when the package is rendered. Once the map has an entry, the digests of functions without a lock, or with an empty one, are recorded when the package is rendered.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionTarget", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.RequiredSecret", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Task"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_PackagePatchTaskSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_PackageRevisionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "PackageRevisionStatus defines the observed state of PackageRevision",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"patches": {
+					"observedGeneration": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
+							Description: "ObservedGeneration is the most recent generation of the package revision that was reconciled.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"lastRenderedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastRenderedAt is the time of the last successful render of the package revision.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastRenderedFunctionDigests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastRenderedFunctionDigests maps the images of the functions evaluated by the last successful render to their digests.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: "",
@@ -605,17 +2156,74 @@ func schema_porch_api_porch_v1alpha1_PackagePatchTaskSpec(ref common.ReferenceCa
 							},
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions describes the current state of the package revision.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"upstreamLock": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpstreamLock identifies the upstream package the package revision was cloned from, as recorded in its Kptfile.",
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.UpstreamLock"),
+						},
+					},
+					"upstreamUpdateAvailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpstreamUpdateAvailable is true if the upstream ref of the package revision points to a newer commit than the locked commit.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"rejectionReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RejectionReason is the reason given for rejecting the package revision if its lifecycle is Rejected.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.UpstreamLock", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_PackageRevision(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_PackageRevisionTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PackageRevision",
+				Description: "PackageRevisionTarget describes the cluster a package revision is deployed to.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kubeconfig": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kubeconfig references a secret holding the kubeconfig of the target cluster under the `kubeconfig` key. If not set, the cluster porch runs in is the target.",
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.SecretRef"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.SecretRef"},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_PackageRevisionValidationReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PackageRevisionValidationReport is the result of validating the resources of a package revision against the OpenAPI schemas of their kinds. It is returned by the `validate` subresource of PackageRevision.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -638,31 +2246,42 @@ func schema_porch_api_porch_v1alpha1_PackageRevision(ref common.ReferenceCallbac
 							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"spec": {
+					"valid": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionSpec"),
+							Description: "Valid is true if all resources of the package revision are well-formed.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"status": {
+					"errors": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionStatus"),
+							Description: "Errors lists the problems found in the resources of the package revision.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ResourceValidationError"),
+									},
+								},
+							},
 						},
 					},
 				},
+				Required: []string{"valid"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionSpec", "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ResourceValidationError", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_PackageRevisionList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_RepositoryConnectionTest(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PackageRevisionList",
+				Description: "RepositoryConnectionTest tests the connectivity of porch to the git or OCI server backing the Repository of the same name and namespace. It is create-only: the posted object names the repository and the returned object carries the result. The test is read-only and works for repositories which failed to sync.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -682,58 +2301,110 @@ func schema_porch_api_porch_v1alpha1_PackageRevisionList(ref common.ReferenceCal
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"items": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision"),
-									},
-								},
-							},
+							Description: "Status is the result of the connection test.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConnectionTestResult"),
 						},
 					},
 				},
-				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConnectionTestResult", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_PackageRevisionRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_RepositoryDependencyGraph(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PackageRevisionRef is a reference to a package revision.",
+				Description: "RepositoryDependencyGraph is the clone dependency graph of the packages in the Repository of the same name and namespace. Nodes are packages, named `repo/pkg`, and edges lead from the upstream package to the package cloned from it. The graph includes the upstream packages of the packages in the repository, as recorded in their clone ancestry. It is read-only.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"format": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Format is the format of the diagram, `dot` or `mermaid`.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"nodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Nodes are the packages in the graph, sorted by name.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"edges": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Edges are the clone relationships between the packages, sorted.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.DependencyGraphEdge"),
+									},
+								},
+							},
+						},
+					},
+					"diagram": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`Name` is the name of the referenced PackageRevision resource.",
+							Description: "Diagram is the graph rendered in the format.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"format", "diagram"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.DependencyGraphEdge", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_PackageRevisionResources(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_RepositoryDependencyGraphOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PackageRevisionResources",
+				Description: "RepositoryDependencyGraphOptions are the query parameters of RepositoryDependencyGraph.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -750,87 +2421,68 @@ func schema_porch_api_porch_v1alpha1_PackageRevisionResources(ref common.Referen
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
+					"format": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesSpec"),
+							Description: "Format is the format of the diagram, `dot` (the default) or `mermaid`.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResourcesSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_PackageRevisionResourcesList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_RepositoryRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PackageRevisionResourcesList",
+				Description: "RepositoryRef identifies a reference to a Repository resource.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"apiVersion": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "Name of the Repository resource referenced.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
-						},
-					},
-					"items": {
-						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResources"),
-									},
-								},
-							},
-						},
-					},
 				},
-				Required: []string{"items"},
+				Required: []string{"name"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevisionResources", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_PackageRevisionResourcesSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_RequiredSecret(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PackageRevisionResourcesSpec represents resources (as ResourceList serialized as yaml string) of the PackageRevision.",
+				Description: "RequiredSecret declares a secret which must exist in the target cluster before a package revision can be approved.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"resources": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
+							Description: "Name of the secret.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the secret. Defaults to the namespace of the package revision.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"keys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Keys which the secret must contain.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: "",
@@ -842,91 +2494,103 @@ func schema_porch_api_porch_v1alpha1_PackageRevisionResourcesSpec(ref common.Ref
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_PackageRevisionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_ResourceCostEstimate(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PackageRevisionSpec defines the desired state of PackageRevision",
+				Description: "ResourceCostEstimate is the approximate hourly cost of the resources of one type in a package revision.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"packageName": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Kind of the resources: Deployment, PersistentVolumeClaim, or Service.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"revision": {
+					"count": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Count is the number of resources with a cost. Only Services of type LoadBalancer are counted.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"repository": {
+					"cpu": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "CPU is the CPU requested by all replicas of the Deployments.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"lifecycle": {
+					"memory": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Memory is the memory requested by all replicas of the Deployments.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"tasks": {
+					"storage": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Task"),
-									},
-								},
-							},
+							Description: "Storage is the storage requested by the PersistentVolumeClaims.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"hourlyCostUSD": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HourlyCostUSD is the estimated hourly cost of the resources in US dollars.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-			},
-		},
-		Dependencies: []string{
-			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.Task"},
-	}
-}
-
-func schema_porch_api_porch_v1alpha1_PackageRevisionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "PackageRevisionStatus defines the observed state of PackageRevision",
-				Type:        []string{"object"},
+				Required: []string{"kind", "count", "hourlyCostUSD"},
 			},
 		},
 	}
 }
 
-func schema_porch_api_porch_v1alpha1_RepositoryRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_porch_api_porch_v1alpha1_ResourceValidationError(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "RepositoryRef identifies a reference to a Repository resource.",
+				Description: "ResourceValidationError describes a problem found in a package resource.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"filename": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the Repository resource referenced.",
+							Description: "Filename is the path of the file containing the resource, relative to the package.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the dot-separated path of the offending field within the resource, such as `spec.replicas`. Empty if the problem concerns the whole file or resource.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message describes the problem.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"filename", "message"},
 			},
 		},
 	}
@@ -1036,6 +2700,121 @@ func schema_porch_api_porch_v1alpha1_Task(ref common.ReferenceCallback) common.O
 	}
 }
 
+func schema_porch_api_porch_v1alpha1_TemplateExpansionRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TemplateExpansionRequest is posted to the `expand` subresource of PackageRevisionResources to expand the templates in the package resources. The response is the PackageRevisionResources with each template replaced by the YAML resources it expands to.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"engine": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Engine is the template language. Only `cue` is supported; templates are the `*.cue` files of the package.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"values": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Values are unified with the `values` field of each template.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"object"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"engine"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_TransferRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TransferRequest is posted to the `transfer` subresource of PackageRevision to move the package revision to another repository. The response is the package revision created in the target repository.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"targetRepository": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TargetRepository is the name of the repository, in the namespace of the package revision, to transfer the package revision to.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"targetRepository"},
+			},
+		},
+	}
+}
+
+func schema_porch_api_porch_v1alpha1_UpstreamLock(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UpstreamLock identifies the upstream package a package revision was cloned from, resolved to an exact version.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the upstream repository (i.e. git, OCI).",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"git": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Git locks the upstream package to a commit. Set if `type` is `git`.",
+							Ref:         ref("github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.GitLock"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.GitLock"},
+	}
+}
+
 func schema_porch_api_porch_v1alpha1_UpstreamPackage(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -15,9 +15,26 @@
 package fuzzer
 
 import (
+	"github.com/GoogleContainerTools/kpt/porch/api/porch"
+	fuzz "github.com/google/gofuzz"
 	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 )
 
 var Funcs = func(codecs runtimeserializer.CodecFactory) []interface{} {
-	return []interface{}{}
+	return []interface{}{
+		// gofuzz can't fill interface{} values; fill the template values
+		// with strings, which survive the JSON round trip unchanged.
+		func(r *porch.TemplateExpansionRequest, c fuzz.Continue) {
+			c.Fuzz(&r.Engine)
+			r.Values = nil
+			var values map[string]string
+			c.Fuzz(&values)
+			if len(values) > 0 {
+				r.Values = map[string]interface{}{}
+				for k, v := range values {
+					r.Values[k] = v
+				}
+			}
+		},
+	}
 }
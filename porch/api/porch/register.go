@@ -42,6 +42,22 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&PackageRevisionList{},
 		&PackageRevisionResources{},
 		&PackageRevisionResourcesList{},
+		&PackageRevisionLineage{},
+		&PackageRevisionSchemaDiff{},
+		&PackageRevisionSchemaDiffOptions{},
+		&PackageRevisionDiff{},
+		&PackageRevisionDiffOptions{},
+		&PackageRevisionValidationReport{},
+		&TemplateExpansionRequest{},
+		&PackageRevisionResourcesBatchUpdate{},
+		&ConflictResolution{},
+		&TransferRequest{},
+		&CostEstimate{},
+		&LintReport{},
+		&RepositoryConnectionTest{},
+		&RepositoryDependencyGraph{},
+		&RepositoryDependencyGraphOptions{},
+		&PackageResourceSearch{},
 		&Function{},
 		&FunctionList{},
 	)
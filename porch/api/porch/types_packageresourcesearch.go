@@ -0,0 +1,66 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageResourceSearch searches the resources of the package revisions in its
+// namespace. It is create-only: the posted object carries the query and the
+// returned object lists the matching resources.
+// +k8s:openapi-gen=true
+type PackageResourceSearch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PackageResourceSearchSpec   `json:"spec,omitempty"`
+	Status PackageResourceSearchResult `json:"status,omitempty"`
+}
+
+// PackageResourceSearchSpec defines the query and the package revisions to search.
+type PackageResourceSearchSpec struct {
+	// Query is a JSONPath expression evaluated against each resource, for example
+	// `{.spec.template.spec.containers[?(@.image=="nginx:1.21")]}`. The braces may be
+	// omitted. A resource matches if the expression yields a non-empty value.
+	Query string `json:"query"`
+	// Repositories limits the search to the named repositories. If unspecified, all
+	// repositories in the namespace are searched.
+	Repositories []string `json:"repositories,omitempty"`
+	// PageToken is the nextPageToken returned by a previous search with the same query,
+	// from which to continue.
+	PageToken string `json:"pageToken,omitempty"`
+}
+
+// PackageResourceSearchResult lists the resources matching a search.
+type PackageResourceSearchResult struct {
+	// Matches lists up to 100 matching resources.
+	Matches []PackageResourceMatch `json:"matches,omitempty"`
+	// NextPageToken is set if there are more matches; pass it as the pageToken of the
+	// next search to retrieve them.
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// PackageResourceMatch is a resource matching a search.
+type PackageResourceMatch struct {
+	// PackageRevisionName is the name of the package revision containing the resource.
+	PackageRevisionName string `json:"packageRevisionName"`
+	// Filename is the path of the file containing the resource, relative to the package.
+	Filename string `json:"filename"`
+	// MatchedResource identifies the resource, as `<kind>/<name>`.
+	MatchedResource string `json:"matchedResource"`
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageRevisionLineage describes the clone ancestry of a package revision.
+// It is served by the `lineage` subresource of PackageRevision.
+// +k8s:openapi-gen=true
+type PackageRevisionLineage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Depth is the number of clone operations between the package revision
+	// and the original package it descends from.
+	Depth int `json:"depth"`
+	// Omitted is the number of the oldest ancestors which are not listed in
+	// `ancestors` because the recorded ancestry is limited.
+	Omitted int `json:"omitted,omitempty"`
+	// Ancestors lists the ancestors of the package revision, starting with the
+	// oldest recorded ancestor and ending with the direct upstream. Each
+	// ancestor was cloned from the one preceding it.
+	Ancestors []PackageRevisionLineageEntry `json:"ancestors,omitempty"`
+}
+
+// PackageRevisionLineageEntry identifies an ancestor of a package revision.
+type PackageRevisionLineageEntry struct {
+	// Name identifies the ancestor package as `repository/package`, or the
+	// Git repository URL and package directory for packages cloned directly
+	// from Git.
+	Name string `json:"name"`
+	// Revision of the ancestor package.
+	Revision string `json:"revision,omitempty"`
+}
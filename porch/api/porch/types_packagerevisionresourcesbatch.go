@@ -0,0 +1,71 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FileOperation is the change made to a file by a FileUpdate.
+type FileOperation string
+
+const (
+	// FileOperationAdd adds a file which does not exist in the package.
+	FileOperationAdd FileOperation = "add"
+	// FileOperationUpdate replaces the content of a file of the package.
+	FileOperationUpdate FileOperation = "update"
+	// FileOperationDelete removes a file from the package.
+	FileOperationDelete FileOperation = "delete"
+)
+
+// FileUpdate is a change to a single file of a package.
+type FileUpdate struct {
+	// Filename is the path of the file, relative to the package.
+	Filename string `json:"filename"`
+	// Content is the new content of the file. Ignored for deletions.
+	Content string `json:"content,omitempty"`
+	// Operation is the change made to the file.
+	Operation FileOperation `json:"operation"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageRevisionResourcesBatchUpdate is posted to the `batchupdate`
+// subresource of PackageRevisionResources to change several files of a draft
+// package revision atomically: either all updates are committed together, or
+// none are. The response reports the resulting commit.
+// +k8s:openapi-gen=true
+type PackageRevisionResourcesBatchUpdate struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Spec   PackageRevisionResourcesBatchUpdateSpec   `json:"spec,omitempty"`
+	Status PackageRevisionResourcesBatchUpdateStatus `json:"status,omitempty"`
+}
+
+// PackageRevisionResourcesBatchUpdateSpec lists the file updates to apply.
+type PackageRevisionResourcesBatchUpdateSpec struct {
+	// ResourceVersion is the resource version of the PackageRevisionResources
+	// the updates were made against. The batch is rejected with a conflict if
+	// the package revision has changed since.
+	ResourceVersion string `json:"resourceVersion"`
+	// Updates are the changes to make to the files of the package.
+	Updates []FileUpdate `json:"updates,omitempty"`
+}
+
+// PackageRevisionResourcesBatchUpdateStatus reports the result of a batch update.
+type PackageRevisionResourcesBatchUpdateStatus struct {
+	// Commit is the SHA of the commit containing the updates.
+	Commit string `json:"commit,omitempty"`
+}
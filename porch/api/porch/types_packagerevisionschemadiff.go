@@ -0,0 +1,53 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageRevisionSchemaDiff summarizes, by API kind, how the resources of a
+// package revision differ from the resources of a base package revision.
+// It is served by the `schemadiff` subresource of PackageRevisionResources.
+// Kinds are identified as `apiVersion/kind`, such as `apps/v1/Deployment`.
+// +k8s:openapi-gen=true
+type PackageRevisionSchemaDiff struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Base is the name of the package revision the resources are compared to.
+	Base string `json:"base"`
+	// AddedKinds lists kinds with resources in the package revision but not in the base.
+	AddedKinds []string `json:"addedKinds,omitempty"`
+	// RemovedKinds lists kinds with resources in the base but not in the package revision.
+	RemovedKinds []string `json:"removedKinds,omitempty"`
+	// ModifiedKinds maps kinds present in both revisions whose resources
+	// differ to the paths of the changed fields, such as `spec.replicas`.
+	ModifiedKinds map[string][]string `json:"modifiedKinds,omitempty"`
+}
+
+// +k8s:conversion-gen:explicit-from=net/url.Values
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageRevisionSchemaDiffOptions are the query parameters of the
+// `schemadiff` subresource of PackageRevisionResources.
+type PackageRevisionSchemaDiffOptions struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Base is the name of the package revision to compare to.
+	Base string `json:"base,omitempty"`
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// TemplateEngine identifies the language of package templates.
+type TemplateEngine string
+
+const (
+	// TemplateEngineCUE expands templates written in CUE (https://cuelang.org).
+	TemplateEngineCUE TemplateEngine = "cue"
+)
+
+// TemplateExpansionRequest is posted to the `expand` subresource of
+// PackageRevisionResources to expand the templates in the package resources.
+// The response is the PackageRevisionResources with each template replaced by
+// the YAML resources it expands to.
+//
+// The deepcopy functions are written by hand because deepcopy-gen doesn't
+// support interface{} values.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=false
+type TemplateExpansionRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Engine is the template language. Only `cue` is supported; templates are
+	// the `*.cue` files of the package.
+	Engine TemplateEngine `json:"engine"`
+	// Values are unified with the `values` field of each template.
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// DeepCopyInto copies the receiver, writing into out. in must be non-nil.
+func (in *TemplateExpansionRequest) DeepCopyInto(out *TemplateExpansionRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Values != nil {
+		out.Values = runtime.DeepCopyJSON(in.Values)
+	}
+}
+
+// DeepCopy copies the receiver, creating a new TemplateExpansionRequest.
+func (in *TemplateExpansionRequest) DeepCopy() *TemplateExpansionRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateExpansionRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject copies the receiver, creating a new runtime.Object.
+func (in *TemplateExpansionRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -15,6 +15,8 @@
 package v1alpha1
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -33,7 +35,7 @@ var (
 )
 
 func init() {
-	localSchemeBuilder.Register(addKnownTypes)
+	localSchemeBuilder.Register(addKnownTypes, addFieldLabelConversionFuncs)
 }
 
 // Adds the list of known types to the given scheme.
@@ -45,6 +47,22 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&PackageRevisionList{},
 		&PackageRevisionResources{},
 		&PackageRevisionResourcesList{},
+		&PackageRevisionLineage{},
+		&PackageRevisionSchemaDiff{},
+		&PackageRevisionSchemaDiffOptions{},
+		&PackageRevisionDiff{},
+		&PackageRevisionDiffOptions{},
+		&PackageRevisionValidationReport{},
+		&TemplateExpansionRequest{},
+		&PackageRevisionResourcesBatchUpdate{},
+		&ConflictResolution{},
+		&TransferRequest{},
+		&CostEstimate{},
+		&LintReport{},
+		&RepositoryConnectionTest{},
+		&RepositoryDependencyGraph{},
+		&RepositoryDependencyGraphOptions{},
+		&PackageResourceSearch{},
 		&Function{},
 		&FunctionList{},
 	)
@@ -53,6 +71,19 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	return nil
 }
 
+// addFieldLabelConversionFuncs registers the fields package revisions can be
+// selected by in list requests.
+func addFieldLabelConversionFuncs(scheme *runtime.Scheme) error {
+	return scheme.AddFieldLabelConversionFunc(SchemeGroupVersion.WithKind("PackageRevision"), func(label, value string) (string, string, error) {
+		switch label {
+		case "metadata.name", "metadata.namespace", "spec.lifecycle", "spec.packageName":
+			return label, value, nil
+		default:
+			return "", "", fmt.Errorf("field label not supported: %s", label)
+		}
+	})
+}
+
 // Resource takes an unqualified resource and returns a Group qualified GroupResource
 func Resource(resource string) schema.GroupResource {
 	return SchemeGroupVersion.WithResource(resource).GroupResource()
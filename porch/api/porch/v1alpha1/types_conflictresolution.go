@@ -0,0 +1,56 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MergeConflictAnnotation marks the resources of a package file left with
+// merge conflicts by a three-way merge. Files containing conflict markers are
+// in conflict as well.
+const MergeConflictAnnotation = "kpt.dev/merge-conflict"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ConflictResolution is put to the `resolveconflict` subresource of
+// PackageRevisionResources to replace a file of a draft package revision left
+// with merge conflicts by its resolved content. The name of the
+// ConflictResolution is the name of the package revision. The response
+// reports the files still in conflict.
+// +k8s:openapi-gen=true
+type ConflictResolution struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ConflictResolutionSpec   `json:"spec,omitempty"`
+	Status ConflictResolutionStatus `json:"status,omitempty"`
+}
+
+// ConflictResolutionSpec is the resolution of the conflicts of a file.
+type ConflictResolutionSpec struct {
+	// Filename is the path of the file in conflict, relative to the package.
+	Filename string `json:"filename"`
+	// ResolvedContent is the content of the file with its conflicts
+	// resolved. It must not contain conflict markers.
+	ResolvedContent string `json:"resolvedContent"`
+}
+
+// ConflictResolutionStatus reports the result of a conflict resolution.
+type ConflictResolutionStatus struct {
+	// RemainingConflicts are the files of the package revision still in
+	// conflict. The package revision is free of conflicts if it is empty.
+	RemainingConflicts []string `json:"remainingConflicts,omitempty"`
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CostEstimate is the approximate hourly cost of deploying the resources of
+// a package revision. It is served by the `cost-estimate` subresource of
+// PackageRevision; the posted object selects the unit prices.
+// +k8s:openapi-gen=true
+type CostEstimate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// PricingConfig is the name of the PricingConfig, in the namespace of the
+	// package revision, providing the unit prices. Defaults to `default`.
+	PricingConfig string `json:"pricingConfig,omitempty"`
+	// Resources are the estimates for the resource types with a cost.
+	Resources []ResourceCostEstimate `json:"resources,omitempty"`
+	// HourlyCostUSD is the estimated total hourly cost in US dollars.
+	HourlyCostUSD string `json:"hourlyCostUSD,omitempty"`
+}
+
+// ResourceCostEstimate is the approximate hourly cost of the resources of one
+// type in a package revision.
+type ResourceCostEstimate struct {
+	// Kind of the resources: Deployment, PersistentVolumeClaim, or Service.
+	Kind string `json:"kind"`
+	// Count is the number of resources with a cost. Only Services of type
+	// LoadBalancer are counted.
+	Count int `json:"count"`
+	// CPU is the CPU requested by all replicas of the Deployments.
+	CPU string `json:"cpu,omitempty"`
+	// Memory is the memory requested by all replicas of the Deployments.
+	Memory string `json:"memory,omitempty"`
+	// Storage is the storage requested by the PersistentVolumeClaims.
+	Storage string `json:"storage,omitempty"`
+	// HourlyCostUSD is the estimated hourly cost of the resources in US dollars.
+	HourlyCostUSD string `json:"hourlyCostUSD"`
+}
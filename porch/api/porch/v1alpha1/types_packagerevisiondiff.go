@@ -0,0 +1,64 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageRevisionDiff lists the files of a package revision which differ from
+// a base package revision, with a unified diff of each. It is served by the
+// `diff` subresource of PackageRevision.
+// +k8s:openapi-gen=true
+type PackageRevisionDiff struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Base is the name of the package revision the files are compared to.
+	// It is empty if the package revision has no previous revision, in which
+	// case all files are reported as added.
+	Base string `json:"base,omitempty"`
+	// Files are the files which differ, sorted by name.
+	Files []FileDiff `json:"files,omitempty"`
+}
+
+// FileDiff is the difference of a file between two package revisions.
+type FileDiff struct {
+	// Name is the path of the file, relative to the package.
+	Name string `json:"name"`
+	// OldContent is the content of the file in the base package revision,
+	// empty if the file was added.
+	OldContent string `json:"oldContent,omitempty"`
+	// NewContent is the content of the file in the package revision, empty
+	// if the file was removed.
+	NewContent string `json:"newContent,omitempty"`
+	// UnifiedDiff is the unified diff from the old to the new content.
+	UnifiedDiff string `json:"unifiedDiff"`
+}
+
+// +k8s:conversion-gen:explicit-from=net/url.Values
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageRevisionDiffOptions are the query parameters of the `diff`
+// subresource of PackageRevision.
+type PackageRevisionDiffOptions struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Base is the name of the package revision to compare to. Defaults to
+	// the previous published revision of the package.
+	Base string `json:"base,omitempty"`
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LintReport is the result of running the built-in best-practice linters
+// against the resources of a package revision. It is served by the `lint`
+// subresource of PackageRevision; the posted object selects the LintPolicy.
+// +k8s:openapi-gen=true
+type LintReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Policy is the name of the LintPolicy, in the namespace of the package
+	// revision, configuring the severity of the linters. If unspecified, the
+	// `default` LintPolicy is used if it exists, and the built-in severities
+	// otherwise.
+	Policy string `json:"policy,omitempty"`
+	// Passed is true if no linter reported a finding with severity `error`.
+	Passed bool `json:"passed"`
+	// Findings lists the problems reported by the linters.
+	Findings []LintFinding `json:"findings,omitempty"`
+}
+
+// LintFinding describes a best-practice violation found in a package resource.
+type LintFinding struct {
+	// Linter is the name of the linter reporting the finding, such as `NoLatestTag`.
+	Linter string `json:"linter"`
+	// Severity is the severity of the finding: `warning` or `error`.
+	Severity string `json:"severity"`
+	// Filename is the path of the file containing the resource, relative to the package.
+	Filename string `json:"filename"`
+	// Resource identifies the resource as `Kind/name`.
+	Resource string `json:"resource,omitempty"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
@@ -19,6 +19,7 @@ import (
 )
 
 // +genclient
+// +genclient:method=ResolveConflict,verb=update,subresource=resolveconflict,input=github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConflictResolution,result=github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.ConflictResolution
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // PackageRevisionResources
@@ -27,8 +28,8 @@ type PackageRevisionResources struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec PackageRevisionResourcesSpec `json:"spec,omitempty"`
-	// Status PackageRevisionResourcesStatuc `json:"status,omitempty"`
+	Spec   PackageRevisionResourcesSpec   `json:"spec,omitempty"`
+	Status PackageRevisionResourcesStatus `json:"status,omitempty"`
 }
 
 // PackageRevisionResourcesList
@@ -44,3 +45,19 @@ type PackageRevisionResourcesList struct {
 type PackageRevisionResourcesSpec struct {
 	Resources map[string]string `json:"resources,omitempty"`
 }
+
+// PackageRevisionResourcesStatus represents state of the rendered package resources.
+type PackageRevisionResourcesStatus struct {
+	// Files contains metadata about each file in `spec.resources`, keyed by file name.
+	Files map[string]FileMetadata `json:"files,omitempty"`
+}
+
+// FileMetadata describes the content of a single package file.
+type FileMetadata struct {
+	// Filename is the path of the file within the package.
+	Filename string `json:"filename"`
+	// ContentType is the detected media type of the file, such as `application/yaml`.
+	ContentType string `json:"contentType,omitempty"`
+	// Encoding is the character encoding of the file, `utf-8` or `binary`.
+	Encoding string `json:"encoding,omitempty"`
+}
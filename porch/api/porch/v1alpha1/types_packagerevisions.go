@@ -20,6 +20,7 @@ import (
 
 // +genclient
 // +genclient:method=UpdateApproval,verb=update,subresource=approval,input=github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision,result=github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision
+// +genclient:method=RejectApproval,verb=update,subresource=rejection,input=github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision,result=github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1.PackageRevision
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // PackageRevision
@@ -47,8 +48,85 @@ const (
 	PackageRevisionLifecycleDraft     PackageRevisionLifecycle = "Draft"
 	PackageRevisionLifecycleProposed  PackageRevisionLifecycle = "Proposed"
 	PackageRevisionLifecyclePublished PackageRevisionLifecycle = "Published"
+	// PackageRevisionLifecycleRejected is the lifecycle of a proposed
+	// package revision which was rejected by a reviewer or policy check. It
+	// must return to Draft before it can be proposed again.
+	PackageRevisionLifecycleRejected PackageRevisionLifecycle = "Rejected"
 )
 
+// RenderPolicy controls when porch renders a package revision.
+type RenderPolicy string
+
+const (
+	// RenderPolicyOnPropose renders the package revision whenever its draft
+	// changes, so it is rendered by the time it is proposed. This is the
+	// default.
+	RenderPolicyOnPropose RenderPolicy = "OnPropose"
+	// RenderPolicyOnApprove renders the package revision when it is approved,
+	// before it is published. Changes to the draft are not rendered.
+	RenderPolicyOnApprove RenderPolicy = "OnApprove"
+	// RenderPolicyManual never renders the package revision automatically.
+	RenderPolicyManual RenderPolicy = "Manual"
+)
+
+// RenderPolicyAnnotation records the render policy of a package revision in
+// the annotations of its Kptfile.
+const RenderPolicyAnnotation = "porch.kpt.dev/render-policy"
+
+// ForceDeleteAnnotation, set to "true", allows a published package revision
+// to be deleted. Porch sets it on the package revision being deleted when the
+// deletion is forced with a zero grace period.
+const ForceDeleteAnnotation = "porch.kpt.dev/force-delete"
+
+// ChangelogAnnotationPrefix prefixes the annotation holding the changelog
+// entry of a published package revision. The annotation key is suffixed with
+// the revision.
+const ChangelogAnnotationPrefix = "kpt.dev/changelog/"
+
+// DryRunAnnotation is set to "true" on the package revisions returned by
+// dry-run create and update requests, which are not persisted.
+const DryRunAnnotation = "kpt.dev/dry-run"
+
+// RequiredSecretsAnnotation records the required secrets of a package
+// revision, as a JSON list, in the annotations of its Kptfile.
+const RequiredSecretsAnnotation = "porch.kpt.dev/required-secrets"
+
+// TargetKubeconfigAnnotation records the name of the secret holding the
+// kubeconfig of the target cluster of a package revision in the annotations
+// of its Kptfile.
+const TargetKubeconfigAnnotation = "porch.kpt.dev/target-kubeconfig"
+
+// TargetKubeconfigKey is the key of the kubeconfig in the secret referenced
+// by PackageRevisionTarget.Kubeconfig.
+const TargetKubeconfigKey = "kubeconfig"
+
+// ImageLocksAnnotation records the function image locks of a package
+// revision, as a JSON object, in the annotations of its Kptfile.
+const ImageLocksAnnotation = "porch.kpt.dev/image-locks"
+
+// RequiredSecret declares a secret which must exist in the target cluster
+// before a package revision can be approved.
+type RequiredSecret struct {
+	// Name of the secret.
+	Name string `json:"name"`
+
+	// Namespace of the secret. Defaults to the namespace of the package
+	// revision.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Keys which the secret must contain.
+	Keys []string `json:"keys,omitempty"`
+}
+
+// PackageRevisionTarget describes the cluster a package revision is
+// deployed to.
+type PackageRevisionTarget struct {
+	// Kubeconfig references a secret holding the kubeconfig of the target
+	// cluster under the `kubeconfig` key. If not set, the cluster porch runs
+	// in is the target.
+	Kubeconfig *SecretRef `json:"kubeconfig,omitempty"`
+}
+
 // PackageRevisionSpec defines the desired state of PackageRevision
 type PackageRevisionSpec struct {
 	PackageName    string `json:"packageName,omitempty"`
@@ -58,10 +136,56 @@ type PackageRevisionSpec struct {
 	Lifecycle PackageRevisionLifecycle `json:"lifecycle,omitempty"`
 
 	Tasks []Task `json:"tasks,omitempty"`
+
+	// ChangelogEntry is a human-readable release note recorded when the
+	// package revision is published.
+	ChangelogEntry string `json:"changelogEntry,omitempty"`
+
+	// RenderPolicy controls when the package revision is rendered. One of
+	// OnPropose, OnApprove or Manual; defaults to OnPropose.
+	RenderPolicy RenderPolicy `json:"renderPolicy,omitempty"`
+
+	// RequiredSecrets lists the secrets which must exist in the target
+	// cluster before the package revision can be approved.
+	RequiredSecrets []RequiredSecret `json:"requiredSecrets,omitempty"`
+
+	// Target describes the cluster the package revision is deployed to.
+	Target *PackageRevisionTarget `json:"target,omitempty"`
+
+	// ImageLocks maps the function images of the package, as referenced in
+	// its Kptfile, to the digests they are pinned to when the package is
+	// rendered. Once the map has an entry, the digests of functions without
+	// a lock, or with an empty one, are recorded when the package is rendered.
+	ImageLocks map[string]string `json:"imageLocks,omitempty"`
 }
 
 // PackageRevisionStatus defines the observed state of PackageRevision
 type PackageRevisionStatus struct {
+	// ObservedGeneration is the most recent generation of the package
+	// revision that was reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastRenderedAt is the time of the last successful render of the package revision.
+	LastRenderedAt metav1.Time `json:"lastRenderedAt,omitempty"`
+
+	// LastRenderedFunctionDigests maps the images of the functions evaluated by
+	// the last successful render to their digests.
+	LastRenderedFunctionDigests map[string]string `json:"lastRenderedFunctionDigests,omitempty"`
+
+	// Conditions describes the current state of the package revision.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// UpstreamLock identifies the upstream package the package revision was
+	// cloned from, as recorded in its Kptfile.
+	UpstreamLock *UpstreamLock `json:"upstreamLock,omitempty"`
+
+	// UpstreamUpdateAvailable is true if the upstream ref of the package
+	// revision points to a newer commit than the locked commit.
+	UpstreamUpdateAvailable bool `json:"upstreamUpdateAvailable,omitempty"`
+
+	// RejectionReason is the reason given for rejecting the package revision
+	// if its lifecycle is Rejected.
+	RejectionReason string `json:"rejectionReason,omitempty"`
 }
 
 type TaskType string
@@ -160,6 +284,31 @@ type GitPackage struct {
 	SecretRef SecretRef `json:"secretRef,omitempty"`
 }
 
+// UpstreamLock identifies the upstream package a package revision was cloned
+// from, resolved to an exact version.
+type UpstreamLock struct {
+	// Type of the upstream repository (i.e. git, OCI).
+	Type RepositoryType `json:"type,omitempty"`
+
+	// Git locks the upstream package to a commit. Set if `type` is `git`.
+	Git *GitLock `json:"git,omitempty"`
+}
+
+// GitLock identifies the commit of an upstream package in a Git repository.
+type GitLock struct {
+	// Address of the Git repository.
+	Repo string `json:"repo,omitempty"`
+
+	// Directory of the package within the Git repository.
+	Directory string `json:"directory,omitempty"`
+
+	// Ref is the git ref the package was fetched from.
+	Ref string `json:"ref,omitempty"`
+
+	// Commit is the commit the ref resolved to when the package was fetched.
+	Commit string `json:"commit,omitempty"`
+}
+
 type SecretRef struct {
 	// Name of the secret. The secret is expected to be located in the same namespace as the resource containing the reference.
 	Name string `json:"name"`
@@ -0,0 +1,37 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TransferredFromAnnotation records the name of the package revision a
+// package revision was transferred from.
+const TransferredFromAnnotation = "kpt.dev/transferred-from"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TransferRequest is posted to the `transfer` subresource of PackageRevision
+// to move the package revision to another repository. The response is the
+// package revision created in the target repository.
+// +k8s:openapi-gen=true
+type TransferRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// TargetRepository is the name of the repository, in the namespace of the
+	// package revision, to transfer the package revision to.
+	TargetRepository string `json:"targetRepository"`
+}
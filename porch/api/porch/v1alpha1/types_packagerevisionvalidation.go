@@ -0,0 +1,46 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageRevisionValidationReport is the result of validating the resources of
+// a package revision against the OpenAPI schemas of their kinds. It is returned
+// by the `validate` subresource of PackageRevision.
+// +k8s:openapi-gen=true
+type PackageRevisionValidationReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Valid is true if all resources of the package revision are well-formed.
+	Valid bool `json:"valid"`
+	// Errors lists the problems found in the resources of the package revision.
+	Errors []ResourceValidationError `json:"errors,omitempty"`
+}
+
+// ResourceValidationError describes a problem found in a package resource.
+type ResourceValidationError struct {
+	// Filename is the path of the file containing the resource, relative to the package.
+	Filename string `json:"filename"`
+	// Path is the dot-separated path of the offending field within the resource,
+	// such as `spec.replicas`. Empty if the problem concerns the whole file or resource.
+	Path string `json:"path,omitempty"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RepositoryConnectionTest tests the connectivity of porch to the git or OCI
+// server backing the Repository of the same name and namespace. It is
+// create-only: the posted object names the repository and the returned object
+// carries the result. The test is read-only and works for repositories which
+// failed to sync.
+// +k8s:openapi-gen=true
+type RepositoryConnectionTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Status is the result of the connection test.
+	Status ConnectionTestResult `json:"status,omitempty"`
+}
+
+// ConnectionTestResult is the result of a repository connection test.
+type ConnectionTestResult struct {
+	// Success is true if porch could list the contents of the repository.
+	Success bool `json:"success"`
+	// LatencyMs is the duration of the test in milliseconds.
+	LatencyMs int64 `json:"latencyMs"`
+	// Error describes why the test failed.
+	Error string `json:"error,omitempty"`
+	// AuthenticatedAs is the user name of the credentials of the repository,
+	// if it has any.
+	AuthenticatedAs string `json:"authenticatedAs,omitempty"`
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DependencyGraphFormatDot renders the dependency graph in the Graphviz DOT language.
+	DependencyGraphFormatDot = "dot"
+	// DependencyGraphFormatMermaid renders the dependency graph as a Mermaid flowchart.
+	DependencyGraphFormatMermaid = "mermaid"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RepositoryDependencyGraph is the clone dependency graph of the packages in
+// the Repository of the same name and namespace. Nodes are packages, named
+// `repo/pkg`, and edges lead from the upstream package to the package cloned
+// from it. The graph includes the upstream packages of the packages in the
+// repository, as recorded in their clone ancestry. It is read-only.
+// +k8s:openapi-gen=true
+type RepositoryDependencyGraph struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Format is the format of the diagram, `dot` or `mermaid`.
+	Format string `json:"format"`
+	// Nodes are the packages in the graph, sorted by name.
+	Nodes []string `json:"nodes,omitempty"`
+	// Edges are the clone relationships between the packages, sorted.
+	Edges []DependencyGraphEdge `json:"edges,omitempty"`
+	// Diagram is the graph rendered in the format.
+	Diagram string `json:"diagram"`
+}
+
+// DependencyGraphEdge records that the downstream package was cloned from the
+// upstream package.
+type DependencyGraphEdge struct {
+	// Upstream is the package which was cloned.
+	Upstream string `json:"upstream"`
+	// Downstream is the package created by the clone.
+	Downstream string `json:"downstream"`
+}
+
+// +k8s:conversion-gen:explicit-from=net/url.Values
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RepositoryDependencyGraphOptions are the query parameters of
+// RepositoryDependencyGraph.
+type RepositoryDependencyGraphOptions struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Format is the format of the diagram, `dot` (the default) or `mermaid`.
+	Format string `json:"format,omitempty"`
+}
@@ -20,9 +20,11 @@
 package v1alpha1
 
 import (
+	url "net/url"
 	unsafe "unsafe"
 
 	porch "github.com/GoogleContainerTools/kpt/porch/api/porch"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -34,6 +36,96 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*ConflictResolution)(nil), (*porch.ConflictResolution)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ConflictResolution_To_porch_ConflictResolution(a.(*ConflictResolution), b.(*porch.ConflictResolution), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.ConflictResolution)(nil), (*ConflictResolution)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_ConflictResolution_To_v1alpha1_ConflictResolution(a.(*porch.ConflictResolution), b.(*ConflictResolution), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ConflictResolutionSpec)(nil), (*porch.ConflictResolutionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ConflictResolutionSpec_To_porch_ConflictResolutionSpec(a.(*ConflictResolutionSpec), b.(*porch.ConflictResolutionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.ConflictResolutionSpec)(nil), (*ConflictResolutionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_ConflictResolutionSpec_To_v1alpha1_ConflictResolutionSpec(a.(*porch.ConflictResolutionSpec), b.(*ConflictResolutionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ConflictResolutionStatus)(nil), (*porch.ConflictResolutionStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ConflictResolutionStatus_To_porch_ConflictResolutionStatus(a.(*ConflictResolutionStatus), b.(*porch.ConflictResolutionStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.ConflictResolutionStatus)(nil), (*ConflictResolutionStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_ConflictResolutionStatus_To_v1alpha1_ConflictResolutionStatus(a.(*porch.ConflictResolutionStatus), b.(*ConflictResolutionStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ConnectionTestResult)(nil), (*porch.ConnectionTestResult)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ConnectionTestResult_To_porch_ConnectionTestResult(a.(*ConnectionTestResult), b.(*porch.ConnectionTestResult), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.ConnectionTestResult)(nil), (*ConnectionTestResult)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_ConnectionTestResult_To_v1alpha1_ConnectionTestResult(a.(*porch.ConnectionTestResult), b.(*ConnectionTestResult), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CostEstimate)(nil), (*porch.CostEstimate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CostEstimate_To_porch_CostEstimate(a.(*CostEstimate), b.(*porch.CostEstimate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.CostEstimate)(nil), (*CostEstimate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_CostEstimate_To_v1alpha1_CostEstimate(a.(*porch.CostEstimate), b.(*CostEstimate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DependencyGraphEdge)(nil), (*porch.DependencyGraphEdge)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DependencyGraphEdge_To_porch_DependencyGraphEdge(a.(*DependencyGraphEdge), b.(*porch.DependencyGraphEdge), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.DependencyGraphEdge)(nil), (*DependencyGraphEdge)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_DependencyGraphEdge_To_v1alpha1_DependencyGraphEdge(a.(*porch.DependencyGraphEdge), b.(*DependencyGraphEdge), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FileDiff)(nil), (*porch.FileDiff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FileDiff_To_porch_FileDiff(a.(*FileDiff), b.(*porch.FileDiff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.FileDiff)(nil), (*FileDiff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_FileDiff_To_v1alpha1_FileDiff(a.(*porch.FileDiff), b.(*FileDiff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FileMetadata)(nil), (*porch.FileMetadata)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FileMetadata_To_porch_FileMetadata(a.(*FileMetadata), b.(*porch.FileMetadata), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.FileMetadata)(nil), (*FileMetadata)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_FileMetadata_To_v1alpha1_FileMetadata(a.(*porch.FileMetadata), b.(*FileMetadata), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FileUpdate)(nil), (*porch.FileUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FileUpdate_To_porch_FileUpdate(a.(*FileUpdate), b.(*porch.FileUpdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.FileUpdate)(nil), (*FileUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_FileUpdate_To_v1alpha1_FileUpdate(a.(*porch.FileUpdate), b.(*FileUpdate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Function)(nil), (*porch.Function)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Function_To_porch_Function(a.(*Function), b.(*porch.Function), scope)
 	}); err != nil {
@@ -104,6 +196,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*GitLock)(nil), (*porch.GitLock)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_GitLock_To_porch_GitLock(a.(*GitLock), b.(*porch.GitLock), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.GitLock)(nil), (*GitLock)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_GitLock_To_v1alpha1_GitLock(a.(*porch.GitLock), b.(*GitLock), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*GitPackage)(nil), (*porch.GitPackage)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_GitPackage_To_porch_GitPackage(a.(*GitPackage), b.(*porch.GitPackage), scope)
 	}); err != nil {
@@ -114,6 +216,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LintFinding)(nil), (*porch.LintFinding)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_LintFinding_To_porch_LintFinding(a.(*LintFinding), b.(*porch.LintFinding), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.LintFinding)(nil), (*LintFinding)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_LintFinding_To_v1alpha1_LintFinding(a.(*porch.LintFinding), b.(*LintFinding), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LintReport)(nil), (*porch.LintReport)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_LintReport_To_porch_LintReport(a.(*LintReport), b.(*porch.LintReport), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.LintReport)(nil), (*LintReport)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_LintReport_To_v1alpha1_LintReport(a.(*porch.LintReport), b.(*LintReport), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OciPackage)(nil), (*porch.OciPackage)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_OciPackage_To_porch_OciPackage(a.(*OciPackage), b.(*porch.OciPackage), scope)
 	}); err != nil {
@@ -154,6 +276,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PackageResourceMatch)(nil), (*porch.PackageResourceMatch)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageResourceMatch_To_porch_PackageResourceMatch(a.(*PackageResourceMatch), b.(*porch.PackageResourceMatch), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageResourceMatch)(nil), (*PackageResourceMatch)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageResourceMatch_To_v1alpha1_PackageResourceMatch(a.(*porch.PackageResourceMatch), b.(*PackageResourceMatch), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageResourceSearch)(nil), (*porch.PackageResourceSearch)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageResourceSearch_To_porch_PackageResourceSearch(a.(*PackageResourceSearch), b.(*porch.PackageResourceSearch), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageResourceSearch)(nil), (*PackageResourceSearch)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageResourceSearch_To_v1alpha1_PackageResourceSearch(a.(*porch.PackageResourceSearch), b.(*PackageResourceSearch), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageResourceSearchResult)(nil), (*porch.PackageResourceSearchResult)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageResourceSearchResult_To_porch_PackageResourceSearchResult(a.(*PackageResourceSearchResult), b.(*porch.PackageResourceSearchResult), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageResourceSearchResult)(nil), (*PackageResourceSearchResult)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageResourceSearchResult_To_v1alpha1_PackageResourceSearchResult(a.(*porch.PackageResourceSearchResult), b.(*PackageResourceSearchResult), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageResourceSearchSpec)(nil), (*porch.PackageResourceSearchSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageResourceSearchSpec_To_porch_PackageResourceSearchSpec(a.(*PackageResourceSearchSpec), b.(*porch.PackageResourceSearchSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageResourceSearchSpec)(nil), (*PackageResourceSearchSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageResourceSearchSpec_To_v1alpha1_PackageResourceSearchSpec(a.(*porch.PackageResourceSearchSpec), b.(*PackageResourceSearchSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PackageRevision)(nil), (*porch.PackageRevision)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PackageRevision_To_porch_PackageRevision(a.(*PackageRevision), b.(*porch.PackageRevision), scope)
 	}); err != nil {
@@ -164,6 +326,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionDiff)(nil), (*porch.PackageRevisionDiff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionDiff_To_porch_PackageRevisionDiff(a.(*PackageRevisionDiff), b.(*porch.PackageRevisionDiff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionDiff)(nil), (*PackageRevisionDiff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionDiff_To_v1alpha1_PackageRevisionDiff(a.(*porch.PackageRevisionDiff), b.(*PackageRevisionDiff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionDiffOptions)(nil), (*porch.PackageRevisionDiffOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionDiffOptions_To_porch_PackageRevisionDiffOptions(a.(*PackageRevisionDiffOptions), b.(*porch.PackageRevisionDiffOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionDiffOptions)(nil), (*PackageRevisionDiffOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionDiffOptions_To_v1alpha1_PackageRevisionDiffOptions(a.(*porch.PackageRevisionDiffOptions), b.(*PackageRevisionDiffOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionLineage)(nil), (*porch.PackageRevisionLineage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionLineage_To_porch_PackageRevisionLineage(a.(*PackageRevisionLineage), b.(*porch.PackageRevisionLineage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionLineage)(nil), (*PackageRevisionLineage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionLineage_To_v1alpha1_PackageRevisionLineage(a.(*porch.PackageRevisionLineage), b.(*PackageRevisionLineage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionLineageEntry)(nil), (*porch.PackageRevisionLineageEntry)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionLineageEntry_To_porch_PackageRevisionLineageEntry(a.(*PackageRevisionLineageEntry), b.(*porch.PackageRevisionLineageEntry), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionLineageEntry)(nil), (*PackageRevisionLineageEntry)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionLineageEntry_To_v1alpha1_PackageRevisionLineageEntry(a.(*porch.PackageRevisionLineageEntry), b.(*PackageRevisionLineageEntry), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PackageRevisionList)(nil), (*porch.PackageRevisionList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PackageRevisionList_To_porch_PackageRevisionList(a.(*PackageRevisionList), b.(*porch.PackageRevisionList), scope)
 	}); err != nil {
@@ -194,6 +396,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionResourcesBatchUpdate)(nil), (*porch.PackageRevisionResourcesBatchUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionResourcesBatchUpdate_To_porch_PackageRevisionResourcesBatchUpdate(a.(*PackageRevisionResourcesBatchUpdate), b.(*porch.PackageRevisionResourcesBatchUpdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionResourcesBatchUpdate)(nil), (*PackageRevisionResourcesBatchUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionResourcesBatchUpdate_To_v1alpha1_PackageRevisionResourcesBatchUpdate(a.(*porch.PackageRevisionResourcesBatchUpdate), b.(*PackageRevisionResourcesBatchUpdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionResourcesBatchUpdateSpec)(nil), (*porch.PackageRevisionResourcesBatchUpdateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionResourcesBatchUpdateSpec_To_porch_PackageRevisionResourcesBatchUpdateSpec(a.(*PackageRevisionResourcesBatchUpdateSpec), b.(*porch.PackageRevisionResourcesBatchUpdateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionResourcesBatchUpdateSpec)(nil), (*PackageRevisionResourcesBatchUpdateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionResourcesBatchUpdateSpec_To_v1alpha1_PackageRevisionResourcesBatchUpdateSpec(a.(*porch.PackageRevisionResourcesBatchUpdateSpec), b.(*PackageRevisionResourcesBatchUpdateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionResourcesBatchUpdateStatus)(nil), (*porch.PackageRevisionResourcesBatchUpdateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionResourcesBatchUpdateStatus_To_porch_PackageRevisionResourcesBatchUpdateStatus(a.(*PackageRevisionResourcesBatchUpdateStatus), b.(*porch.PackageRevisionResourcesBatchUpdateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionResourcesBatchUpdateStatus)(nil), (*PackageRevisionResourcesBatchUpdateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionResourcesBatchUpdateStatus_To_v1alpha1_PackageRevisionResourcesBatchUpdateStatus(a.(*porch.PackageRevisionResourcesBatchUpdateStatus), b.(*PackageRevisionResourcesBatchUpdateStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PackageRevisionResourcesList)(nil), (*porch.PackageRevisionResourcesList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PackageRevisionResourcesList_To_porch_PackageRevisionResourcesList(a.(*PackageRevisionResourcesList), b.(*porch.PackageRevisionResourcesList), scope)
 	}); err != nil {
@@ -214,6 +446,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionResourcesStatus)(nil), (*porch.PackageRevisionResourcesStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionResourcesStatus_To_porch_PackageRevisionResourcesStatus(a.(*PackageRevisionResourcesStatus), b.(*porch.PackageRevisionResourcesStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionResourcesStatus)(nil), (*PackageRevisionResourcesStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionResourcesStatus_To_v1alpha1_PackageRevisionResourcesStatus(a.(*porch.PackageRevisionResourcesStatus), b.(*PackageRevisionResourcesStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionSchemaDiff)(nil), (*porch.PackageRevisionSchemaDiff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionSchemaDiff_To_porch_PackageRevisionSchemaDiff(a.(*PackageRevisionSchemaDiff), b.(*porch.PackageRevisionSchemaDiff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionSchemaDiff)(nil), (*PackageRevisionSchemaDiff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionSchemaDiff_To_v1alpha1_PackageRevisionSchemaDiff(a.(*porch.PackageRevisionSchemaDiff), b.(*PackageRevisionSchemaDiff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionSchemaDiffOptions)(nil), (*porch.PackageRevisionSchemaDiffOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionSchemaDiffOptions_To_porch_PackageRevisionSchemaDiffOptions(a.(*PackageRevisionSchemaDiffOptions), b.(*porch.PackageRevisionSchemaDiffOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionSchemaDiffOptions)(nil), (*PackageRevisionSchemaDiffOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionSchemaDiffOptions_To_v1alpha1_PackageRevisionSchemaDiffOptions(a.(*porch.PackageRevisionSchemaDiffOptions), b.(*PackageRevisionSchemaDiffOptions), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PackageRevisionSpec)(nil), (*porch.PackageRevisionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PackageRevisionSpec_To_porch_PackageRevisionSpec(a.(*PackageRevisionSpec), b.(*porch.PackageRevisionSpec), scope)
 	}); err != nil {
@@ -234,6 +496,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionTarget)(nil), (*porch.PackageRevisionTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionTarget_To_porch_PackageRevisionTarget(a.(*PackageRevisionTarget), b.(*porch.PackageRevisionTarget), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionTarget)(nil), (*PackageRevisionTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionTarget_To_v1alpha1_PackageRevisionTarget(a.(*porch.PackageRevisionTarget), b.(*PackageRevisionTarget), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PackageRevisionValidationReport)(nil), (*porch.PackageRevisionValidationReport)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageRevisionValidationReport_To_porch_PackageRevisionValidationReport(a.(*PackageRevisionValidationReport), b.(*porch.PackageRevisionValidationReport), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.PackageRevisionValidationReport)(nil), (*PackageRevisionValidationReport)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_PackageRevisionValidationReport_To_v1alpha1_PackageRevisionValidationReport(a.(*porch.PackageRevisionValidationReport), b.(*PackageRevisionValidationReport), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RepositoryConnectionTest)(nil), (*porch.RepositoryConnectionTest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RepositoryConnectionTest_To_porch_RepositoryConnectionTest(a.(*RepositoryConnectionTest), b.(*porch.RepositoryConnectionTest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.RepositoryConnectionTest)(nil), (*RepositoryConnectionTest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_RepositoryConnectionTest_To_v1alpha1_RepositoryConnectionTest(a.(*porch.RepositoryConnectionTest), b.(*RepositoryConnectionTest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RepositoryDependencyGraph)(nil), (*porch.RepositoryDependencyGraph)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RepositoryDependencyGraph_To_porch_RepositoryDependencyGraph(a.(*RepositoryDependencyGraph), b.(*porch.RepositoryDependencyGraph), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.RepositoryDependencyGraph)(nil), (*RepositoryDependencyGraph)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_RepositoryDependencyGraph_To_v1alpha1_RepositoryDependencyGraph(a.(*porch.RepositoryDependencyGraph), b.(*RepositoryDependencyGraph), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RepositoryDependencyGraphOptions)(nil), (*porch.RepositoryDependencyGraphOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RepositoryDependencyGraphOptions_To_porch_RepositoryDependencyGraphOptions(a.(*RepositoryDependencyGraphOptions), b.(*porch.RepositoryDependencyGraphOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.RepositoryDependencyGraphOptions)(nil), (*RepositoryDependencyGraphOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_RepositoryDependencyGraphOptions_To_v1alpha1_RepositoryDependencyGraphOptions(a.(*porch.RepositoryDependencyGraphOptions), b.(*RepositoryDependencyGraphOptions), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RepositoryRef)(nil), (*porch.RepositoryRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_RepositoryRef_To_porch_RepositoryRef(a.(*RepositoryRef), b.(*porch.RepositoryRef), scope)
 	}); err != nil {
@@ -244,6 +556,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RequiredSecret)(nil), (*porch.RequiredSecret)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RequiredSecret_To_porch_RequiredSecret(a.(*RequiredSecret), b.(*porch.RequiredSecret), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.RequiredSecret)(nil), (*RequiredSecret)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_RequiredSecret_To_v1alpha1_RequiredSecret(a.(*porch.RequiredSecret), b.(*RequiredSecret), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ResourceCostEstimate)(nil), (*porch.ResourceCostEstimate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ResourceCostEstimate_To_porch_ResourceCostEstimate(a.(*ResourceCostEstimate), b.(*porch.ResourceCostEstimate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.ResourceCostEstimate)(nil), (*ResourceCostEstimate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_ResourceCostEstimate_To_v1alpha1_ResourceCostEstimate(a.(*porch.ResourceCostEstimate), b.(*ResourceCostEstimate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ResourceValidationError)(nil), (*porch.ResourceValidationError)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ResourceValidationError_To_porch_ResourceValidationError(a.(*ResourceValidationError), b.(*porch.ResourceValidationError), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.ResourceValidationError)(nil), (*ResourceValidationError)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_ResourceValidationError_To_v1alpha1_ResourceValidationError(a.(*porch.ResourceValidationError), b.(*ResourceValidationError), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SecretRef)(nil), (*porch.SecretRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SecretRef_To_porch_SecretRef(a.(*SecretRef), b.(*porch.SecretRef), scope)
 	}); err != nil {
@@ -274,6 +616,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TemplateExpansionRequest)(nil), (*porch.TemplateExpansionRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TemplateExpansionRequest_To_porch_TemplateExpansionRequest(a.(*TemplateExpansionRequest), b.(*porch.TemplateExpansionRequest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.TemplateExpansionRequest)(nil), (*TemplateExpansionRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_TemplateExpansionRequest_To_v1alpha1_TemplateExpansionRequest(a.(*porch.TemplateExpansionRequest), b.(*TemplateExpansionRequest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TransferRequest)(nil), (*porch.TransferRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TransferRequest_To_porch_TransferRequest(a.(*TransferRequest), b.(*porch.TransferRequest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.TransferRequest)(nil), (*TransferRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_TransferRequest_To_v1alpha1_TransferRequest(a.(*porch.TransferRequest), b.(*TransferRequest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*UpstreamLock)(nil), (*porch.UpstreamLock)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_UpstreamLock_To_porch_UpstreamLock(a.(*UpstreamLock), b.(*porch.UpstreamLock), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*porch.UpstreamLock)(nil), (*UpstreamLock)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_porch_UpstreamLock_To_v1alpha1_UpstreamLock(a.(*porch.UpstreamLock), b.(*UpstreamLock), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*UpstreamPackage)(nil), (*porch.UpstreamPackage)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_UpstreamPackage_To_porch_UpstreamPackage(a.(*UpstreamPackage), b.(*porch.UpstreamPackage), scope)
 	}); err != nil {
@@ -284,9 +656,246 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*url.Values)(nil), (*PackageRevisionDiffOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_url_Values_To_v1alpha1_PackageRevisionDiffOptions(a.(*url.Values), b.(*PackageRevisionDiffOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*url.Values)(nil), (*PackageRevisionSchemaDiffOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_url_Values_To_v1alpha1_PackageRevisionSchemaDiffOptions(a.(*url.Values), b.(*PackageRevisionSchemaDiffOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*url.Values)(nil), (*RepositoryDependencyGraphOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_url_Values_To_v1alpha1_RepositoryDependencyGraphOptions(a.(*url.Values), b.(*RepositoryDependencyGraphOptions), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_ConflictResolution_To_porch_ConflictResolution(in *ConflictResolution, out *porch.ConflictResolution, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha1_ConflictResolutionSpec_To_porch_ConflictResolutionSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_ConflictResolutionStatus_To_porch_ConflictResolutionStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_ConflictResolution_To_porch_ConflictResolution is an autogenerated conversion function.
+func Convert_v1alpha1_ConflictResolution_To_porch_ConflictResolution(in *ConflictResolution, out *porch.ConflictResolution, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ConflictResolution_To_porch_ConflictResolution(in, out, s)
+}
+
+func autoConvert_porch_ConflictResolution_To_v1alpha1_ConflictResolution(in *porch.ConflictResolution, out *ConflictResolution, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_porch_ConflictResolutionSpec_To_v1alpha1_ConflictResolutionSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_porch_ConflictResolutionStatus_To_v1alpha1_ConflictResolutionStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_porch_ConflictResolution_To_v1alpha1_ConflictResolution is an autogenerated conversion function.
+func Convert_porch_ConflictResolution_To_v1alpha1_ConflictResolution(in *porch.ConflictResolution, out *ConflictResolution, s conversion.Scope) error {
+	return autoConvert_porch_ConflictResolution_To_v1alpha1_ConflictResolution(in, out, s)
+}
+
+func autoConvert_v1alpha1_ConflictResolutionSpec_To_porch_ConflictResolutionSpec(in *ConflictResolutionSpec, out *porch.ConflictResolutionSpec, s conversion.Scope) error {
+	out.Filename = in.Filename
+	out.ResolvedContent = in.ResolvedContent
+	return nil
+}
+
+// Convert_v1alpha1_ConflictResolutionSpec_To_porch_ConflictResolutionSpec is an autogenerated conversion function.
+func Convert_v1alpha1_ConflictResolutionSpec_To_porch_ConflictResolutionSpec(in *ConflictResolutionSpec, out *porch.ConflictResolutionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ConflictResolutionSpec_To_porch_ConflictResolutionSpec(in, out, s)
+}
+
+func autoConvert_porch_ConflictResolutionSpec_To_v1alpha1_ConflictResolutionSpec(in *porch.ConflictResolutionSpec, out *ConflictResolutionSpec, s conversion.Scope) error {
+	out.Filename = in.Filename
+	out.ResolvedContent = in.ResolvedContent
+	return nil
+}
+
+// Convert_porch_ConflictResolutionSpec_To_v1alpha1_ConflictResolutionSpec is an autogenerated conversion function.
+func Convert_porch_ConflictResolutionSpec_To_v1alpha1_ConflictResolutionSpec(in *porch.ConflictResolutionSpec, out *ConflictResolutionSpec, s conversion.Scope) error {
+	return autoConvert_porch_ConflictResolutionSpec_To_v1alpha1_ConflictResolutionSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_ConflictResolutionStatus_To_porch_ConflictResolutionStatus(in *ConflictResolutionStatus, out *porch.ConflictResolutionStatus, s conversion.Scope) error {
+	out.RemainingConflicts = *(*[]string)(unsafe.Pointer(&in.RemainingConflicts))
+	return nil
+}
+
+// Convert_v1alpha1_ConflictResolutionStatus_To_porch_ConflictResolutionStatus is an autogenerated conversion function.
+func Convert_v1alpha1_ConflictResolutionStatus_To_porch_ConflictResolutionStatus(in *ConflictResolutionStatus, out *porch.ConflictResolutionStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ConflictResolutionStatus_To_porch_ConflictResolutionStatus(in, out, s)
+}
+
+func autoConvert_porch_ConflictResolutionStatus_To_v1alpha1_ConflictResolutionStatus(in *porch.ConflictResolutionStatus, out *ConflictResolutionStatus, s conversion.Scope) error {
+	out.RemainingConflicts = *(*[]string)(unsafe.Pointer(&in.RemainingConflicts))
+	return nil
+}
+
+// Convert_porch_ConflictResolutionStatus_To_v1alpha1_ConflictResolutionStatus is an autogenerated conversion function.
+func Convert_porch_ConflictResolutionStatus_To_v1alpha1_ConflictResolutionStatus(in *porch.ConflictResolutionStatus, out *ConflictResolutionStatus, s conversion.Scope) error {
+	return autoConvert_porch_ConflictResolutionStatus_To_v1alpha1_ConflictResolutionStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_ConnectionTestResult_To_porch_ConnectionTestResult(in *ConnectionTestResult, out *porch.ConnectionTestResult, s conversion.Scope) error {
+	out.Success = in.Success
+	out.LatencyMs = in.LatencyMs
+	out.Error = in.Error
+	out.AuthenticatedAs = in.AuthenticatedAs
+	return nil
+}
+
+// Convert_v1alpha1_ConnectionTestResult_To_porch_ConnectionTestResult is an autogenerated conversion function.
+func Convert_v1alpha1_ConnectionTestResult_To_porch_ConnectionTestResult(in *ConnectionTestResult, out *porch.ConnectionTestResult, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ConnectionTestResult_To_porch_ConnectionTestResult(in, out, s)
+}
+
+func autoConvert_porch_ConnectionTestResult_To_v1alpha1_ConnectionTestResult(in *porch.ConnectionTestResult, out *ConnectionTestResult, s conversion.Scope) error {
+	out.Success = in.Success
+	out.LatencyMs = in.LatencyMs
+	out.Error = in.Error
+	out.AuthenticatedAs = in.AuthenticatedAs
+	return nil
+}
+
+// Convert_porch_ConnectionTestResult_To_v1alpha1_ConnectionTestResult is an autogenerated conversion function.
+func Convert_porch_ConnectionTestResult_To_v1alpha1_ConnectionTestResult(in *porch.ConnectionTestResult, out *ConnectionTestResult, s conversion.Scope) error {
+	return autoConvert_porch_ConnectionTestResult_To_v1alpha1_ConnectionTestResult(in, out, s)
+}
+
+func autoConvert_v1alpha1_CostEstimate_To_porch_CostEstimate(in *CostEstimate, out *porch.CostEstimate, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.PricingConfig = in.PricingConfig
+	out.Resources = *(*[]porch.ResourceCostEstimate)(unsafe.Pointer(&in.Resources))
+	out.HourlyCostUSD = in.HourlyCostUSD
 	return nil
 }
 
+// Convert_v1alpha1_CostEstimate_To_porch_CostEstimate is an autogenerated conversion function.
+func Convert_v1alpha1_CostEstimate_To_porch_CostEstimate(in *CostEstimate, out *porch.CostEstimate, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CostEstimate_To_porch_CostEstimate(in, out, s)
+}
+
+func autoConvert_porch_CostEstimate_To_v1alpha1_CostEstimate(in *porch.CostEstimate, out *CostEstimate, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.PricingConfig = in.PricingConfig
+	out.Resources = *(*[]ResourceCostEstimate)(unsafe.Pointer(&in.Resources))
+	out.HourlyCostUSD = in.HourlyCostUSD
+	return nil
+}
+
+// Convert_porch_CostEstimate_To_v1alpha1_CostEstimate is an autogenerated conversion function.
+func Convert_porch_CostEstimate_To_v1alpha1_CostEstimate(in *porch.CostEstimate, out *CostEstimate, s conversion.Scope) error {
+	return autoConvert_porch_CostEstimate_To_v1alpha1_CostEstimate(in, out, s)
+}
+
+func autoConvert_v1alpha1_DependencyGraphEdge_To_porch_DependencyGraphEdge(in *DependencyGraphEdge, out *porch.DependencyGraphEdge, s conversion.Scope) error {
+	out.Upstream = in.Upstream
+	out.Downstream = in.Downstream
+	return nil
+}
+
+// Convert_v1alpha1_DependencyGraphEdge_To_porch_DependencyGraphEdge is an autogenerated conversion function.
+func Convert_v1alpha1_DependencyGraphEdge_To_porch_DependencyGraphEdge(in *DependencyGraphEdge, out *porch.DependencyGraphEdge, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DependencyGraphEdge_To_porch_DependencyGraphEdge(in, out, s)
+}
+
+func autoConvert_porch_DependencyGraphEdge_To_v1alpha1_DependencyGraphEdge(in *porch.DependencyGraphEdge, out *DependencyGraphEdge, s conversion.Scope) error {
+	out.Upstream = in.Upstream
+	out.Downstream = in.Downstream
+	return nil
+}
+
+// Convert_porch_DependencyGraphEdge_To_v1alpha1_DependencyGraphEdge is an autogenerated conversion function.
+func Convert_porch_DependencyGraphEdge_To_v1alpha1_DependencyGraphEdge(in *porch.DependencyGraphEdge, out *DependencyGraphEdge, s conversion.Scope) error {
+	return autoConvert_porch_DependencyGraphEdge_To_v1alpha1_DependencyGraphEdge(in, out, s)
+}
+
+func autoConvert_v1alpha1_FileDiff_To_porch_FileDiff(in *FileDiff, out *porch.FileDiff, s conversion.Scope) error {
+	out.Name = in.Name
+	out.OldContent = in.OldContent
+	out.NewContent = in.NewContent
+	out.UnifiedDiff = in.UnifiedDiff
+	return nil
+}
+
+// Convert_v1alpha1_FileDiff_To_porch_FileDiff is an autogenerated conversion function.
+func Convert_v1alpha1_FileDiff_To_porch_FileDiff(in *FileDiff, out *porch.FileDiff, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FileDiff_To_porch_FileDiff(in, out, s)
+}
+
+func autoConvert_porch_FileDiff_To_v1alpha1_FileDiff(in *porch.FileDiff, out *FileDiff, s conversion.Scope) error {
+	out.Name = in.Name
+	out.OldContent = in.OldContent
+	out.NewContent = in.NewContent
+	out.UnifiedDiff = in.UnifiedDiff
+	return nil
+}
+
+// Convert_porch_FileDiff_To_v1alpha1_FileDiff is an autogenerated conversion function.
+func Convert_porch_FileDiff_To_v1alpha1_FileDiff(in *porch.FileDiff, out *FileDiff, s conversion.Scope) error {
+	return autoConvert_porch_FileDiff_To_v1alpha1_FileDiff(in, out, s)
+}
+
+func autoConvert_v1alpha1_FileMetadata_To_porch_FileMetadata(in *FileMetadata, out *porch.FileMetadata, s conversion.Scope) error {
+	out.Filename = in.Filename
+	out.ContentType = in.ContentType
+	out.Encoding = in.Encoding
+	return nil
+}
+
+// Convert_v1alpha1_FileMetadata_To_porch_FileMetadata is an autogenerated conversion function.
+func Convert_v1alpha1_FileMetadata_To_porch_FileMetadata(in *FileMetadata, out *porch.FileMetadata, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FileMetadata_To_porch_FileMetadata(in, out, s)
+}
+
+func autoConvert_porch_FileMetadata_To_v1alpha1_FileMetadata(in *porch.FileMetadata, out *FileMetadata, s conversion.Scope) error {
+	out.Filename = in.Filename
+	out.ContentType = in.ContentType
+	out.Encoding = in.Encoding
+	return nil
+}
+
+// Convert_porch_FileMetadata_To_v1alpha1_FileMetadata is an autogenerated conversion function.
+func Convert_porch_FileMetadata_To_v1alpha1_FileMetadata(in *porch.FileMetadata, out *FileMetadata, s conversion.Scope) error {
+	return autoConvert_porch_FileMetadata_To_v1alpha1_FileMetadata(in, out, s)
+}
+
+func autoConvert_v1alpha1_FileUpdate_To_porch_FileUpdate(in *FileUpdate, out *porch.FileUpdate, s conversion.Scope) error {
+	out.Filename = in.Filename
+	out.Content = in.Content
+	out.Operation = porch.FileOperation(in.Operation)
+	return nil
+}
+
+// Convert_v1alpha1_FileUpdate_To_porch_FileUpdate is an autogenerated conversion function.
+func Convert_v1alpha1_FileUpdate_To_porch_FileUpdate(in *FileUpdate, out *porch.FileUpdate, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FileUpdate_To_porch_FileUpdate(in, out, s)
+}
+
+func autoConvert_porch_FileUpdate_To_v1alpha1_FileUpdate(in *porch.FileUpdate, out *FileUpdate, s conversion.Scope) error {
+	out.Filename = in.Filename
+	out.Content = in.Content
+	out.Operation = FileOperation(in.Operation)
+	return nil
+}
+
+// Convert_porch_FileUpdate_To_v1alpha1_FileUpdate is an autogenerated conversion function.
+func Convert_porch_FileUpdate_To_v1alpha1_FileUpdate(in *porch.FileUpdate, out *FileUpdate, s conversion.Scope) error {
+	return autoConvert_porch_FileUpdate_To_v1alpha1_FileUpdate(in, out, s)
+}
+
 func autoConvert_v1alpha1_Function_To_porch_Function(in *Function, out *porch.Function, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha1_FunctionSpec_To_porch_FunctionSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -471,6 +1080,32 @@ func Convert_porch_FunctionStatus_To_v1alpha1_FunctionStatus(in *porch.FunctionS
 	return autoConvert_porch_FunctionStatus_To_v1alpha1_FunctionStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_GitLock_To_porch_GitLock(in *GitLock, out *porch.GitLock, s conversion.Scope) error {
+	out.Repo = in.Repo
+	out.Directory = in.Directory
+	out.Ref = in.Ref
+	out.Commit = in.Commit
+	return nil
+}
+
+// Convert_v1alpha1_GitLock_To_porch_GitLock is an autogenerated conversion function.
+func Convert_v1alpha1_GitLock_To_porch_GitLock(in *GitLock, out *porch.GitLock, s conversion.Scope) error {
+	return autoConvert_v1alpha1_GitLock_To_porch_GitLock(in, out, s)
+}
+
+func autoConvert_porch_GitLock_To_v1alpha1_GitLock(in *porch.GitLock, out *GitLock, s conversion.Scope) error {
+	out.Repo = in.Repo
+	out.Directory = in.Directory
+	out.Ref = in.Ref
+	out.Commit = in.Commit
+	return nil
+}
+
+// Convert_porch_GitLock_To_v1alpha1_GitLock is an autogenerated conversion function.
+func Convert_porch_GitLock_To_v1alpha1_GitLock(in *porch.GitLock, out *GitLock, s conversion.Scope) error {
+	return autoConvert_porch_GitLock_To_v1alpha1_GitLock(in, out, s)
+}
+
 func autoConvert_v1alpha1_GitPackage_To_porch_GitPackage(in *GitPackage, out *porch.GitPackage, s conversion.Scope) error {
 	out.Repo = in.Repo
 	out.Ref = in.Ref
@@ -501,6 +1136,60 @@ func Convert_porch_GitPackage_To_v1alpha1_GitPackage(in *porch.GitPackage, out *
 	return autoConvert_porch_GitPackage_To_v1alpha1_GitPackage(in, out, s)
 }
 
+func autoConvert_v1alpha1_LintFinding_To_porch_LintFinding(in *LintFinding, out *porch.LintFinding, s conversion.Scope) error {
+	out.Linter = in.Linter
+	out.Severity = in.Severity
+	out.Filename = in.Filename
+	out.Resource = in.Resource
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1alpha1_LintFinding_To_porch_LintFinding is an autogenerated conversion function.
+func Convert_v1alpha1_LintFinding_To_porch_LintFinding(in *LintFinding, out *porch.LintFinding, s conversion.Scope) error {
+	return autoConvert_v1alpha1_LintFinding_To_porch_LintFinding(in, out, s)
+}
+
+func autoConvert_porch_LintFinding_To_v1alpha1_LintFinding(in *porch.LintFinding, out *LintFinding, s conversion.Scope) error {
+	out.Linter = in.Linter
+	out.Severity = in.Severity
+	out.Filename = in.Filename
+	out.Resource = in.Resource
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_porch_LintFinding_To_v1alpha1_LintFinding is an autogenerated conversion function.
+func Convert_porch_LintFinding_To_v1alpha1_LintFinding(in *porch.LintFinding, out *LintFinding, s conversion.Scope) error {
+	return autoConvert_porch_LintFinding_To_v1alpha1_LintFinding(in, out, s)
+}
+
+func autoConvert_v1alpha1_LintReport_To_porch_LintReport(in *LintReport, out *porch.LintReport, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Policy = in.Policy
+	out.Passed = in.Passed
+	out.Findings = *(*[]porch.LintFinding)(unsafe.Pointer(&in.Findings))
+	return nil
+}
+
+// Convert_v1alpha1_LintReport_To_porch_LintReport is an autogenerated conversion function.
+func Convert_v1alpha1_LintReport_To_porch_LintReport(in *LintReport, out *porch.LintReport, s conversion.Scope) error {
+	return autoConvert_v1alpha1_LintReport_To_porch_LintReport(in, out, s)
+}
+
+func autoConvert_porch_LintReport_To_v1alpha1_LintReport(in *porch.LintReport, out *LintReport, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Policy = in.Policy
+	out.Passed = in.Passed
+	out.Findings = *(*[]LintFinding)(unsafe.Pointer(&in.Findings))
+	return nil
+}
+
+// Convert_porch_LintReport_To_v1alpha1_LintReport is an autogenerated conversion function.
+func Convert_porch_LintReport_To_v1alpha1_LintReport(in *porch.LintReport, out *LintReport, s conversion.Scope) error {
+	return autoConvert_porch_LintReport_To_v1alpha1_LintReport(in, out, s)
+}
+
 func autoConvert_v1alpha1_OciPackage_To_porch_OciPackage(in *OciPackage, out *porch.OciPackage, s conversion.Scope) error {
 	out.Image = in.Image
 	return nil
@@ -583,46 +1272,240 @@ func Convert_v1alpha1_PackagePatchTaskSpec_To_porch_PackagePatchTaskSpec(in *Pac
 	return autoConvert_v1alpha1_PackagePatchTaskSpec_To_porch_PackagePatchTaskSpec(in, out, s)
 }
 
-func autoConvert_porch_PackagePatchTaskSpec_To_v1alpha1_PackagePatchTaskSpec(in *porch.PackagePatchTaskSpec, out *PackagePatchTaskSpec, s conversion.Scope) error {
-	out.Patches = *(*[]string)(unsafe.Pointer(&in.Patches))
+func autoConvert_porch_PackagePatchTaskSpec_To_v1alpha1_PackagePatchTaskSpec(in *porch.PackagePatchTaskSpec, out *PackagePatchTaskSpec, s conversion.Scope) error {
+	out.Patches = *(*[]string)(unsafe.Pointer(&in.Patches))
+	return nil
+}
+
+// Convert_porch_PackagePatchTaskSpec_To_v1alpha1_PackagePatchTaskSpec is an autogenerated conversion function.
+func Convert_porch_PackagePatchTaskSpec_To_v1alpha1_PackagePatchTaskSpec(in *porch.PackagePatchTaskSpec, out *PackagePatchTaskSpec, s conversion.Scope) error {
+	return autoConvert_porch_PackagePatchTaskSpec_To_v1alpha1_PackagePatchTaskSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageResourceMatch_To_porch_PackageResourceMatch(in *PackageResourceMatch, out *porch.PackageResourceMatch, s conversion.Scope) error {
+	out.PackageRevisionName = in.PackageRevisionName
+	out.Filename = in.Filename
+	out.MatchedResource = in.MatchedResource
+	return nil
+}
+
+// Convert_v1alpha1_PackageResourceMatch_To_porch_PackageResourceMatch is an autogenerated conversion function.
+func Convert_v1alpha1_PackageResourceMatch_To_porch_PackageResourceMatch(in *PackageResourceMatch, out *porch.PackageResourceMatch, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageResourceMatch_To_porch_PackageResourceMatch(in, out, s)
+}
+
+func autoConvert_porch_PackageResourceMatch_To_v1alpha1_PackageResourceMatch(in *porch.PackageResourceMatch, out *PackageResourceMatch, s conversion.Scope) error {
+	out.PackageRevisionName = in.PackageRevisionName
+	out.Filename = in.Filename
+	out.MatchedResource = in.MatchedResource
+	return nil
+}
+
+// Convert_porch_PackageResourceMatch_To_v1alpha1_PackageResourceMatch is an autogenerated conversion function.
+func Convert_porch_PackageResourceMatch_To_v1alpha1_PackageResourceMatch(in *porch.PackageResourceMatch, out *PackageResourceMatch, s conversion.Scope) error {
+	return autoConvert_porch_PackageResourceMatch_To_v1alpha1_PackageResourceMatch(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageResourceSearch_To_porch_PackageResourceSearch(in *PackageResourceSearch, out *porch.PackageResourceSearch, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha1_PackageResourceSearchSpec_To_porch_PackageResourceSearchSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_PackageResourceSearchResult_To_porch_PackageResourceSearchResult(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_PackageResourceSearch_To_porch_PackageResourceSearch is an autogenerated conversion function.
+func Convert_v1alpha1_PackageResourceSearch_To_porch_PackageResourceSearch(in *PackageResourceSearch, out *porch.PackageResourceSearch, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageResourceSearch_To_porch_PackageResourceSearch(in, out, s)
+}
+
+func autoConvert_porch_PackageResourceSearch_To_v1alpha1_PackageResourceSearch(in *porch.PackageResourceSearch, out *PackageResourceSearch, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_porch_PackageResourceSearchSpec_To_v1alpha1_PackageResourceSearchSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_porch_PackageResourceSearchResult_To_v1alpha1_PackageResourceSearchResult(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_porch_PackageResourceSearch_To_v1alpha1_PackageResourceSearch is an autogenerated conversion function.
+func Convert_porch_PackageResourceSearch_To_v1alpha1_PackageResourceSearch(in *porch.PackageResourceSearch, out *PackageResourceSearch, s conversion.Scope) error {
+	return autoConvert_porch_PackageResourceSearch_To_v1alpha1_PackageResourceSearch(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageResourceSearchResult_To_porch_PackageResourceSearchResult(in *PackageResourceSearchResult, out *porch.PackageResourceSearchResult, s conversion.Scope) error {
+	out.Matches = *(*[]porch.PackageResourceMatch)(unsafe.Pointer(&in.Matches))
+	out.NextPageToken = in.NextPageToken
+	return nil
+}
+
+// Convert_v1alpha1_PackageResourceSearchResult_To_porch_PackageResourceSearchResult is an autogenerated conversion function.
+func Convert_v1alpha1_PackageResourceSearchResult_To_porch_PackageResourceSearchResult(in *PackageResourceSearchResult, out *porch.PackageResourceSearchResult, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageResourceSearchResult_To_porch_PackageResourceSearchResult(in, out, s)
+}
+
+func autoConvert_porch_PackageResourceSearchResult_To_v1alpha1_PackageResourceSearchResult(in *porch.PackageResourceSearchResult, out *PackageResourceSearchResult, s conversion.Scope) error {
+	out.Matches = *(*[]PackageResourceMatch)(unsafe.Pointer(&in.Matches))
+	out.NextPageToken = in.NextPageToken
+	return nil
+}
+
+// Convert_porch_PackageResourceSearchResult_To_v1alpha1_PackageResourceSearchResult is an autogenerated conversion function.
+func Convert_porch_PackageResourceSearchResult_To_v1alpha1_PackageResourceSearchResult(in *porch.PackageResourceSearchResult, out *PackageResourceSearchResult, s conversion.Scope) error {
+	return autoConvert_porch_PackageResourceSearchResult_To_v1alpha1_PackageResourceSearchResult(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageResourceSearchSpec_To_porch_PackageResourceSearchSpec(in *PackageResourceSearchSpec, out *porch.PackageResourceSearchSpec, s conversion.Scope) error {
+	out.Query = in.Query
+	out.Repositories = *(*[]string)(unsafe.Pointer(&in.Repositories))
+	out.PageToken = in.PageToken
+	return nil
+}
+
+// Convert_v1alpha1_PackageResourceSearchSpec_To_porch_PackageResourceSearchSpec is an autogenerated conversion function.
+func Convert_v1alpha1_PackageResourceSearchSpec_To_porch_PackageResourceSearchSpec(in *PackageResourceSearchSpec, out *porch.PackageResourceSearchSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageResourceSearchSpec_To_porch_PackageResourceSearchSpec(in, out, s)
+}
+
+func autoConvert_porch_PackageResourceSearchSpec_To_v1alpha1_PackageResourceSearchSpec(in *porch.PackageResourceSearchSpec, out *PackageResourceSearchSpec, s conversion.Scope) error {
+	out.Query = in.Query
+	out.Repositories = *(*[]string)(unsafe.Pointer(&in.Repositories))
+	out.PageToken = in.PageToken
+	return nil
+}
+
+// Convert_porch_PackageResourceSearchSpec_To_v1alpha1_PackageResourceSearchSpec is an autogenerated conversion function.
+func Convert_porch_PackageResourceSearchSpec_To_v1alpha1_PackageResourceSearchSpec(in *porch.PackageResourceSearchSpec, out *PackageResourceSearchSpec, s conversion.Scope) error {
+	return autoConvert_porch_PackageResourceSearchSpec_To_v1alpha1_PackageResourceSearchSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageRevision_To_porch_PackageRevision(in *PackageRevision, out *porch.PackageRevision, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha1_PackageRevisionSpec_To_porch_PackageRevisionSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_PackageRevisionStatus_To_porch_PackageRevisionStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevision_To_porch_PackageRevision is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevision_To_porch_PackageRevision(in *PackageRevision, out *porch.PackageRevision, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevision_To_porch_PackageRevision(in, out, s)
+}
+
+func autoConvert_porch_PackageRevision_To_v1alpha1_PackageRevision(in *porch.PackageRevision, out *PackageRevision, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_porch_PackageRevisionSpec_To_v1alpha1_PackageRevisionSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_porch_PackageRevisionStatus_To_v1alpha1_PackageRevisionStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_porch_PackageRevision_To_v1alpha1_PackageRevision is an autogenerated conversion function.
+func Convert_porch_PackageRevision_To_v1alpha1_PackageRevision(in *porch.PackageRevision, out *PackageRevision, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevision_To_v1alpha1_PackageRevision(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageRevisionDiff_To_porch_PackageRevisionDiff(in *PackageRevisionDiff, out *porch.PackageRevisionDiff, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Base = in.Base
+	out.Files = *(*[]porch.FileDiff)(unsafe.Pointer(&in.Files))
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionDiff_To_porch_PackageRevisionDiff is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionDiff_To_porch_PackageRevisionDiff(in *PackageRevisionDiff, out *porch.PackageRevisionDiff, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionDiff_To_porch_PackageRevisionDiff(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionDiff_To_v1alpha1_PackageRevisionDiff(in *porch.PackageRevisionDiff, out *PackageRevisionDiff, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Base = in.Base
+	out.Files = *(*[]FileDiff)(unsafe.Pointer(&in.Files))
+	return nil
+}
+
+// Convert_porch_PackageRevisionDiff_To_v1alpha1_PackageRevisionDiff is an autogenerated conversion function.
+func Convert_porch_PackageRevisionDiff_To_v1alpha1_PackageRevisionDiff(in *porch.PackageRevisionDiff, out *PackageRevisionDiff, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionDiff_To_v1alpha1_PackageRevisionDiff(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageRevisionDiffOptions_To_porch_PackageRevisionDiffOptions(in *PackageRevisionDiffOptions, out *porch.PackageRevisionDiffOptions, s conversion.Scope) error {
+	out.Base = in.Base
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionDiffOptions_To_porch_PackageRevisionDiffOptions is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionDiffOptions_To_porch_PackageRevisionDiffOptions(in *PackageRevisionDiffOptions, out *porch.PackageRevisionDiffOptions, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionDiffOptions_To_porch_PackageRevisionDiffOptions(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionDiffOptions_To_v1alpha1_PackageRevisionDiffOptions(in *porch.PackageRevisionDiffOptions, out *PackageRevisionDiffOptions, s conversion.Scope) error {
+	out.Base = in.Base
+	return nil
+}
+
+// Convert_porch_PackageRevisionDiffOptions_To_v1alpha1_PackageRevisionDiffOptions is an autogenerated conversion function.
+func Convert_porch_PackageRevisionDiffOptions_To_v1alpha1_PackageRevisionDiffOptions(in *porch.PackageRevisionDiffOptions, out *PackageRevisionDiffOptions, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionDiffOptions_To_v1alpha1_PackageRevisionDiffOptions(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageRevisionLineage_To_porch_PackageRevisionLineage(in *PackageRevisionLineage, out *porch.PackageRevisionLineage, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Depth = in.Depth
+	out.Omitted = in.Omitted
+	out.Ancestors = *(*[]porch.PackageRevisionLineageEntry)(unsafe.Pointer(&in.Ancestors))
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionLineage_To_porch_PackageRevisionLineage is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionLineage_To_porch_PackageRevisionLineage(in *PackageRevisionLineage, out *porch.PackageRevisionLineage, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionLineage_To_porch_PackageRevisionLineage(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionLineage_To_v1alpha1_PackageRevisionLineage(in *porch.PackageRevisionLineage, out *PackageRevisionLineage, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Depth = in.Depth
+	out.Omitted = in.Omitted
+	out.Ancestors = *(*[]PackageRevisionLineageEntry)(unsafe.Pointer(&in.Ancestors))
 	return nil
 }
 
-// Convert_porch_PackagePatchTaskSpec_To_v1alpha1_PackagePatchTaskSpec is an autogenerated conversion function.
-func Convert_porch_PackagePatchTaskSpec_To_v1alpha1_PackagePatchTaskSpec(in *porch.PackagePatchTaskSpec, out *PackagePatchTaskSpec, s conversion.Scope) error {
-	return autoConvert_porch_PackagePatchTaskSpec_To_v1alpha1_PackagePatchTaskSpec(in, out, s)
+// Convert_porch_PackageRevisionLineage_To_v1alpha1_PackageRevisionLineage is an autogenerated conversion function.
+func Convert_porch_PackageRevisionLineage_To_v1alpha1_PackageRevisionLineage(in *porch.PackageRevisionLineage, out *PackageRevisionLineage, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionLineage_To_v1alpha1_PackageRevisionLineage(in, out, s)
 }
 
-func autoConvert_v1alpha1_PackageRevision_To_porch_PackageRevision(in *PackageRevision, out *porch.PackageRevision, s conversion.Scope) error {
-	out.ObjectMeta = in.ObjectMeta
-	if err := Convert_v1alpha1_PackageRevisionSpec_To_porch_PackageRevisionSpec(&in.Spec, &out.Spec, s); err != nil {
-		return err
-	}
-	if err := Convert_v1alpha1_PackageRevisionStatus_To_porch_PackageRevisionStatus(&in.Status, &out.Status, s); err != nil {
-		return err
-	}
+func autoConvert_v1alpha1_PackageRevisionLineageEntry_To_porch_PackageRevisionLineageEntry(in *PackageRevisionLineageEntry, out *porch.PackageRevisionLineageEntry, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Revision = in.Revision
 	return nil
 }
 
-// Convert_v1alpha1_PackageRevision_To_porch_PackageRevision is an autogenerated conversion function.
-func Convert_v1alpha1_PackageRevision_To_porch_PackageRevision(in *PackageRevision, out *porch.PackageRevision, s conversion.Scope) error {
-	return autoConvert_v1alpha1_PackageRevision_To_porch_PackageRevision(in, out, s)
+// Convert_v1alpha1_PackageRevisionLineageEntry_To_porch_PackageRevisionLineageEntry is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionLineageEntry_To_porch_PackageRevisionLineageEntry(in *PackageRevisionLineageEntry, out *porch.PackageRevisionLineageEntry, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionLineageEntry_To_porch_PackageRevisionLineageEntry(in, out, s)
 }
 
-func autoConvert_porch_PackageRevision_To_v1alpha1_PackageRevision(in *porch.PackageRevision, out *PackageRevision, s conversion.Scope) error {
-	out.ObjectMeta = in.ObjectMeta
-	if err := Convert_porch_PackageRevisionSpec_To_v1alpha1_PackageRevisionSpec(&in.Spec, &out.Spec, s); err != nil {
-		return err
-	}
-	if err := Convert_porch_PackageRevisionStatus_To_v1alpha1_PackageRevisionStatus(&in.Status, &out.Status, s); err != nil {
-		return err
-	}
+func autoConvert_porch_PackageRevisionLineageEntry_To_v1alpha1_PackageRevisionLineageEntry(in *porch.PackageRevisionLineageEntry, out *PackageRevisionLineageEntry, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Revision = in.Revision
 	return nil
 }
 
-// Convert_porch_PackageRevision_To_v1alpha1_PackageRevision is an autogenerated conversion function.
-func Convert_porch_PackageRevision_To_v1alpha1_PackageRevision(in *porch.PackageRevision, out *PackageRevision, s conversion.Scope) error {
-	return autoConvert_porch_PackageRevision_To_v1alpha1_PackageRevision(in, out, s)
+// Convert_porch_PackageRevisionLineageEntry_To_v1alpha1_PackageRevisionLineageEntry is an autogenerated conversion function.
+func Convert_porch_PackageRevisionLineageEntry_To_v1alpha1_PackageRevisionLineageEntry(in *porch.PackageRevisionLineageEntry, out *PackageRevisionLineageEntry, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionLineageEntry_To_v1alpha1_PackageRevisionLineageEntry(in, out, s)
 }
 
 func autoConvert_v1alpha1_PackageRevisionList_To_porch_PackageRevisionList(in *PackageRevisionList, out *porch.PackageRevisionList, s conversion.Scope) error {
@@ -672,6 +1555,9 @@ func autoConvert_v1alpha1_PackageRevisionResources_To_porch_PackageRevisionResou
 	if err := Convert_v1alpha1_PackageRevisionResourcesSpec_To_porch_PackageRevisionResourcesSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	if err := Convert_v1alpha1_PackageRevisionResourcesStatus_To_porch_PackageRevisionResourcesStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -685,6 +1571,9 @@ func autoConvert_porch_PackageRevisionResources_To_v1alpha1_PackageRevisionResou
 	if err := Convert_porch_PackageRevisionResourcesSpec_To_v1alpha1_PackageRevisionResourcesSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	if err := Convert_porch_PackageRevisionResourcesStatus_To_v1alpha1_PackageRevisionResourcesStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -693,6 +1582,78 @@ func Convert_porch_PackageRevisionResources_To_v1alpha1_PackageRevisionResources
 	return autoConvert_porch_PackageRevisionResources_To_v1alpha1_PackageRevisionResources(in, out, s)
 }
 
+func autoConvert_v1alpha1_PackageRevisionResourcesBatchUpdate_To_porch_PackageRevisionResourcesBatchUpdate(in *PackageRevisionResourcesBatchUpdate, out *porch.PackageRevisionResourcesBatchUpdate, s conversion.Scope) error {
+	if err := Convert_v1alpha1_PackageRevisionResourcesBatchUpdateSpec_To_porch_PackageRevisionResourcesBatchUpdateSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_PackageRevisionResourcesBatchUpdateStatus_To_porch_PackageRevisionResourcesBatchUpdateStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionResourcesBatchUpdate_To_porch_PackageRevisionResourcesBatchUpdate is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionResourcesBatchUpdate_To_porch_PackageRevisionResourcesBatchUpdate(in *PackageRevisionResourcesBatchUpdate, out *porch.PackageRevisionResourcesBatchUpdate, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionResourcesBatchUpdate_To_porch_PackageRevisionResourcesBatchUpdate(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionResourcesBatchUpdate_To_v1alpha1_PackageRevisionResourcesBatchUpdate(in *porch.PackageRevisionResourcesBatchUpdate, out *PackageRevisionResourcesBatchUpdate, s conversion.Scope) error {
+	if err := Convert_porch_PackageRevisionResourcesBatchUpdateSpec_To_v1alpha1_PackageRevisionResourcesBatchUpdateSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_porch_PackageRevisionResourcesBatchUpdateStatus_To_v1alpha1_PackageRevisionResourcesBatchUpdateStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_porch_PackageRevisionResourcesBatchUpdate_To_v1alpha1_PackageRevisionResourcesBatchUpdate is an autogenerated conversion function.
+func Convert_porch_PackageRevisionResourcesBatchUpdate_To_v1alpha1_PackageRevisionResourcesBatchUpdate(in *porch.PackageRevisionResourcesBatchUpdate, out *PackageRevisionResourcesBatchUpdate, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionResourcesBatchUpdate_To_v1alpha1_PackageRevisionResourcesBatchUpdate(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageRevisionResourcesBatchUpdateSpec_To_porch_PackageRevisionResourcesBatchUpdateSpec(in *PackageRevisionResourcesBatchUpdateSpec, out *porch.PackageRevisionResourcesBatchUpdateSpec, s conversion.Scope) error {
+	out.ResourceVersion = in.ResourceVersion
+	out.Updates = *(*[]porch.FileUpdate)(unsafe.Pointer(&in.Updates))
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionResourcesBatchUpdateSpec_To_porch_PackageRevisionResourcesBatchUpdateSpec is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionResourcesBatchUpdateSpec_To_porch_PackageRevisionResourcesBatchUpdateSpec(in *PackageRevisionResourcesBatchUpdateSpec, out *porch.PackageRevisionResourcesBatchUpdateSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionResourcesBatchUpdateSpec_To_porch_PackageRevisionResourcesBatchUpdateSpec(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionResourcesBatchUpdateSpec_To_v1alpha1_PackageRevisionResourcesBatchUpdateSpec(in *porch.PackageRevisionResourcesBatchUpdateSpec, out *PackageRevisionResourcesBatchUpdateSpec, s conversion.Scope) error {
+	out.ResourceVersion = in.ResourceVersion
+	out.Updates = *(*[]FileUpdate)(unsafe.Pointer(&in.Updates))
+	return nil
+}
+
+// Convert_porch_PackageRevisionResourcesBatchUpdateSpec_To_v1alpha1_PackageRevisionResourcesBatchUpdateSpec is an autogenerated conversion function.
+func Convert_porch_PackageRevisionResourcesBatchUpdateSpec_To_v1alpha1_PackageRevisionResourcesBatchUpdateSpec(in *porch.PackageRevisionResourcesBatchUpdateSpec, out *PackageRevisionResourcesBatchUpdateSpec, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionResourcesBatchUpdateSpec_To_v1alpha1_PackageRevisionResourcesBatchUpdateSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageRevisionResourcesBatchUpdateStatus_To_porch_PackageRevisionResourcesBatchUpdateStatus(in *PackageRevisionResourcesBatchUpdateStatus, out *porch.PackageRevisionResourcesBatchUpdateStatus, s conversion.Scope) error {
+	out.Commit = in.Commit
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionResourcesBatchUpdateStatus_To_porch_PackageRevisionResourcesBatchUpdateStatus is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionResourcesBatchUpdateStatus_To_porch_PackageRevisionResourcesBatchUpdateStatus(in *PackageRevisionResourcesBatchUpdateStatus, out *porch.PackageRevisionResourcesBatchUpdateStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionResourcesBatchUpdateStatus_To_porch_PackageRevisionResourcesBatchUpdateStatus(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionResourcesBatchUpdateStatus_To_v1alpha1_PackageRevisionResourcesBatchUpdateStatus(in *porch.PackageRevisionResourcesBatchUpdateStatus, out *PackageRevisionResourcesBatchUpdateStatus, s conversion.Scope) error {
+	out.Commit = in.Commit
+	return nil
+}
+
+// Convert_porch_PackageRevisionResourcesBatchUpdateStatus_To_v1alpha1_PackageRevisionResourcesBatchUpdateStatus is an autogenerated conversion function.
+func Convert_porch_PackageRevisionResourcesBatchUpdateStatus_To_v1alpha1_PackageRevisionResourcesBatchUpdateStatus(in *porch.PackageRevisionResourcesBatchUpdateStatus, out *PackageRevisionResourcesBatchUpdateStatus, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionResourcesBatchUpdateStatus_To_v1alpha1_PackageRevisionResourcesBatchUpdateStatus(in, out, s)
+}
+
 func autoConvert_v1alpha1_PackageRevisionResourcesList_To_porch_PackageRevisionResourcesList(in *PackageRevisionResourcesList, out *porch.PackageRevisionResourcesList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	out.Items = *(*[]porch.PackageRevisionResources)(unsafe.Pointer(&in.Items))
@@ -735,12 +1696,85 @@ func Convert_porch_PackageRevisionResourcesSpec_To_v1alpha1_PackageRevisionResou
 	return autoConvert_porch_PackageRevisionResourcesSpec_To_v1alpha1_PackageRevisionResourcesSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_PackageRevisionResourcesStatus_To_porch_PackageRevisionResourcesStatus(in *PackageRevisionResourcesStatus, out *porch.PackageRevisionResourcesStatus, s conversion.Scope) error {
+	out.Files = *(*map[string]porch.FileMetadata)(unsafe.Pointer(&in.Files))
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionResourcesStatus_To_porch_PackageRevisionResourcesStatus is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionResourcesStatus_To_porch_PackageRevisionResourcesStatus(in *PackageRevisionResourcesStatus, out *porch.PackageRevisionResourcesStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionResourcesStatus_To_porch_PackageRevisionResourcesStatus(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionResourcesStatus_To_v1alpha1_PackageRevisionResourcesStatus(in *porch.PackageRevisionResourcesStatus, out *PackageRevisionResourcesStatus, s conversion.Scope) error {
+	out.Files = *(*map[string]FileMetadata)(unsafe.Pointer(&in.Files))
+	return nil
+}
+
+// Convert_porch_PackageRevisionResourcesStatus_To_v1alpha1_PackageRevisionResourcesStatus is an autogenerated conversion function.
+func Convert_porch_PackageRevisionResourcesStatus_To_v1alpha1_PackageRevisionResourcesStatus(in *porch.PackageRevisionResourcesStatus, out *PackageRevisionResourcesStatus, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionResourcesStatus_To_v1alpha1_PackageRevisionResourcesStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageRevisionSchemaDiff_To_porch_PackageRevisionSchemaDiff(in *PackageRevisionSchemaDiff, out *porch.PackageRevisionSchemaDiff, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Base = in.Base
+	out.AddedKinds = *(*[]string)(unsafe.Pointer(&in.AddedKinds))
+	out.RemovedKinds = *(*[]string)(unsafe.Pointer(&in.RemovedKinds))
+	out.ModifiedKinds = *(*map[string][]string)(unsafe.Pointer(&in.ModifiedKinds))
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionSchemaDiff_To_porch_PackageRevisionSchemaDiff is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionSchemaDiff_To_porch_PackageRevisionSchemaDiff(in *PackageRevisionSchemaDiff, out *porch.PackageRevisionSchemaDiff, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionSchemaDiff_To_porch_PackageRevisionSchemaDiff(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionSchemaDiff_To_v1alpha1_PackageRevisionSchemaDiff(in *porch.PackageRevisionSchemaDiff, out *PackageRevisionSchemaDiff, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Base = in.Base
+	out.AddedKinds = *(*[]string)(unsafe.Pointer(&in.AddedKinds))
+	out.RemovedKinds = *(*[]string)(unsafe.Pointer(&in.RemovedKinds))
+	out.ModifiedKinds = *(*map[string][]string)(unsafe.Pointer(&in.ModifiedKinds))
+	return nil
+}
+
+// Convert_porch_PackageRevisionSchemaDiff_To_v1alpha1_PackageRevisionSchemaDiff is an autogenerated conversion function.
+func Convert_porch_PackageRevisionSchemaDiff_To_v1alpha1_PackageRevisionSchemaDiff(in *porch.PackageRevisionSchemaDiff, out *PackageRevisionSchemaDiff, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionSchemaDiff_To_v1alpha1_PackageRevisionSchemaDiff(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageRevisionSchemaDiffOptions_To_porch_PackageRevisionSchemaDiffOptions(in *PackageRevisionSchemaDiffOptions, out *porch.PackageRevisionSchemaDiffOptions, s conversion.Scope) error {
+	out.Base = in.Base
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionSchemaDiffOptions_To_porch_PackageRevisionSchemaDiffOptions is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionSchemaDiffOptions_To_porch_PackageRevisionSchemaDiffOptions(in *PackageRevisionSchemaDiffOptions, out *porch.PackageRevisionSchemaDiffOptions, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionSchemaDiffOptions_To_porch_PackageRevisionSchemaDiffOptions(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionSchemaDiffOptions_To_v1alpha1_PackageRevisionSchemaDiffOptions(in *porch.PackageRevisionSchemaDiffOptions, out *PackageRevisionSchemaDiffOptions, s conversion.Scope) error {
+	out.Base = in.Base
+	return nil
+}
+
+// Convert_porch_PackageRevisionSchemaDiffOptions_To_v1alpha1_PackageRevisionSchemaDiffOptions is an autogenerated conversion function.
+func Convert_porch_PackageRevisionSchemaDiffOptions_To_v1alpha1_PackageRevisionSchemaDiffOptions(in *porch.PackageRevisionSchemaDiffOptions, out *PackageRevisionSchemaDiffOptions, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionSchemaDiffOptions_To_v1alpha1_PackageRevisionSchemaDiffOptions(in, out, s)
+}
+
 func autoConvert_v1alpha1_PackageRevisionSpec_To_porch_PackageRevisionSpec(in *PackageRevisionSpec, out *porch.PackageRevisionSpec, s conversion.Scope) error {
 	out.PackageName = in.PackageName
 	out.Revision = in.Revision
 	out.RepositoryName = in.RepositoryName
 	out.Lifecycle = porch.PackageRevisionLifecycle(in.Lifecycle)
 	out.Tasks = *(*[]porch.Task)(unsafe.Pointer(&in.Tasks))
+	out.ChangelogEntry = in.ChangelogEntry
+	out.RenderPolicy = porch.RenderPolicy(in.RenderPolicy)
+	out.RequiredSecrets = *(*[]porch.RequiredSecret)(unsafe.Pointer(&in.RequiredSecrets))
+	out.Target = (*porch.PackageRevisionTarget)(unsafe.Pointer(in.Target))
+	out.ImageLocks = *(*map[string]string)(unsafe.Pointer(&in.ImageLocks))
 	return nil
 }
 
@@ -755,6 +1789,11 @@ func autoConvert_porch_PackageRevisionSpec_To_v1alpha1_PackageRevisionSpec(in *p
 	out.RepositoryName = in.RepositoryName
 	out.Lifecycle = PackageRevisionLifecycle(in.Lifecycle)
 	out.Tasks = *(*[]Task)(unsafe.Pointer(&in.Tasks))
+	out.ChangelogEntry = in.ChangelogEntry
+	out.RenderPolicy = RenderPolicy(in.RenderPolicy)
+	out.RequiredSecrets = *(*[]RequiredSecret)(unsafe.Pointer(&in.RequiredSecrets))
+	out.Target = (*PackageRevisionTarget)(unsafe.Pointer(in.Target))
+	out.ImageLocks = *(*map[string]string)(unsafe.Pointer(&in.ImageLocks))
 	return nil
 }
 
@@ -764,6 +1803,13 @@ func Convert_porch_PackageRevisionSpec_To_v1alpha1_PackageRevisionSpec(in *porch
 }
 
 func autoConvert_v1alpha1_PackageRevisionStatus_To_porch_PackageRevisionStatus(in *PackageRevisionStatus, out *porch.PackageRevisionStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.LastRenderedAt = in.LastRenderedAt
+	out.LastRenderedFunctionDigests = *(*map[string]string)(unsafe.Pointer(&in.LastRenderedFunctionDigests))
+	out.Conditions = *(*[]v1.Condition)(unsafe.Pointer(&in.Conditions))
+	out.UpstreamLock = (*porch.UpstreamLock)(unsafe.Pointer(in.UpstreamLock))
+	out.UpstreamUpdateAvailable = in.UpstreamUpdateAvailable
+	out.RejectionReason = in.RejectionReason
 	return nil
 }
 
@@ -773,6 +1819,13 @@ func Convert_v1alpha1_PackageRevisionStatus_To_porch_PackageRevisionStatus(in *P
 }
 
 func autoConvert_porch_PackageRevisionStatus_To_v1alpha1_PackageRevisionStatus(in *porch.PackageRevisionStatus, out *PackageRevisionStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.LastRenderedAt = in.LastRenderedAt
+	out.LastRenderedFunctionDigests = *(*map[string]string)(unsafe.Pointer(&in.LastRenderedFunctionDigests))
+	out.Conditions = *(*[]v1.Condition)(unsafe.Pointer(&in.Conditions))
+	out.UpstreamLock = (*UpstreamLock)(unsafe.Pointer(in.UpstreamLock))
+	out.UpstreamUpdateAvailable = in.UpstreamUpdateAvailable
+	out.RejectionReason = in.RejectionReason
 	return nil
 }
 
@@ -781,6 +1834,124 @@ func Convert_porch_PackageRevisionStatus_To_v1alpha1_PackageRevisionStatus(in *p
 	return autoConvert_porch_PackageRevisionStatus_To_v1alpha1_PackageRevisionStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_PackageRevisionTarget_To_porch_PackageRevisionTarget(in *PackageRevisionTarget, out *porch.PackageRevisionTarget, s conversion.Scope) error {
+	out.Kubeconfig = (*porch.SecretRef)(unsafe.Pointer(in.Kubeconfig))
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionTarget_To_porch_PackageRevisionTarget is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionTarget_To_porch_PackageRevisionTarget(in *PackageRevisionTarget, out *porch.PackageRevisionTarget, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionTarget_To_porch_PackageRevisionTarget(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionTarget_To_v1alpha1_PackageRevisionTarget(in *porch.PackageRevisionTarget, out *PackageRevisionTarget, s conversion.Scope) error {
+	out.Kubeconfig = (*SecretRef)(unsafe.Pointer(in.Kubeconfig))
+	return nil
+}
+
+// Convert_porch_PackageRevisionTarget_To_v1alpha1_PackageRevisionTarget is an autogenerated conversion function.
+func Convert_porch_PackageRevisionTarget_To_v1alpha1_PackageRevisionTarget(in *porch.PackageRevisionTarget, out *PackageRevisionTarget, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionTarget_To_v1alpha1_PackageRevisionTarget(in, out, s)
+}
+
+func autoConvert_v1alpha1_PackageRevisionValidationReport_To_porch_PackageRevisionValidationReport(in *PackageRevisionValidationReport, out *porch.PackageRevisionValidationReport, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Valid = in.Valid
+	out.Errors = *(*[]porch.ResourceValidationError)(unsafe.Pointer(&in.Errors))
+	return nil
+}
+
+// Convert_v1alpha1_PackageRevisionValidationReport_To_porch_PackageRevisionValidationReport is an autogenerated conversion function.
+func Convert_v1alpha1_PackageRevisionValidationReport_To_porch_PackageRevisionValidationReport(in *PackageRevisionValidationReport, out *porch.PackageRevisionValidationReport, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageRevisionValidationReport_To_porch_PackageRevisionValidationReport(in, out, s)
+}
+
+func autoConvert_porch_PackageRevisionValidationReport_To_v1alpha1_PackageRevisionValidationReport(in *porch.PackageRevisionValidationReport, out *PackageRevisionValidationReport, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Valid = in.Valid
+	out.Errors = *(*[]ResourceValidationError)(unsafe.Pointer(&in.Errors))
+	return nil
+}
+
+// Convert_porch_PackageRevisionValidationReport_To_v1alpha1_PackageRevisionValidationReport is an autogenerated conversion function.
+func Convert_porch_PackageRevisionValidationReport_To_v1alpha1_PackageRevisionValidationReport(in *porch.PackageRevisionValidationReport, out *PackageRevisionValidationReport, s conversion.Scope) error {
+	return autoConvert_porch_PackageRevisionValidationReport_To_v1alpha1_PackageRevisionValidationReport(in, out, s)
+}
+
+func autoConvert_v1alpha1_RepositoryConnectionTest_To_porch_RepositoryConnectionTest(in *RepositoryConnectionTest, out *porch.RepositoryConnectionTest, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha1_ConnectionTestResult_To_porch_ConnectionTestResult(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_RepositoryConnectionTest_To_porch_RepositoryConnectionTest is an autogenerated conversion function.
+func Convert_v1alpha1_RepositoryConnectionTest_To_porch_RepositoryConnectionTest(in *RepositoryConnectionTest, out *porch.RepositoryConnectionTest, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RepositoryConnectionTest_To_porch_RepositoryConnectionTest(in, out, s)
+}
+
+func autoConvert_porch_RepositoryConnectionTest_To_v1alpha1_RepositoryConnectionTest(in *porch.RepositoryConnectionTest, out *RepositoryConnectionTest, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_porch_ConnectionTestResult_To_v1alpha1_ConnectionTestResult(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_porch_RepositoryConnectionTest_To_v1alpha1_RepositoryConnectionTest is an autogenerated conversion function.
+func Convert_porch_RepositoryConnectionTest_To_v1alpha1_RepositoryConnectionTest(in *porch.RepositoryConnectionTest, out *RepositoryConnectionTest, s conversion.Scope) error {
+	return autoConvert_porch_RepositoryConnectionTest_To_v1alpha1_RepositoryConnectionTest(in, out, s)
+}
+
+func autoConvert_v1alpha1_RepositoryDependencyGraph_To_porch_RepositoryDependencyGraph(in *RepositoryDependencyGraph, out *porch.RepositoryDependencyGraph, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Format = in.Format
+	out.Nodes = *(*[]string)(unsafe.Pointer(&in.Nodes))
+	out.Edges = *(*[]porch.DependencyGraphEdge)(unsafe.Pointer(&in.Edges))
+	out.Diagram = in.Diagram
+	return nil
+}
+
+// Convert_v1alpha1_RepositoryDependencyGraph_To_porch_RepositoryDependencyGraph is an autogenerated conversion function.
+func Convert_v1alpha1_RepositoryDependencyGraph_To_porch_RepositoryDependencyGraph(in *RepositoryDependencyGraph, out *porch.RepositoryDependencyGraph, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RepositoryDependencyGraph_To_porch_RepositoryDependencyGraph(in, out, s)
+}
+
+func autoConvert_porch_RepositoryDependencyGraph_To_v1alpha1_RepositoryDependencyGraph(in *porch.RepositoryDependencyGraph, out *RepositoryDependencyGraph, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Format = in.Format
+	out.Nodes = *(*[]string)(unsafe.Pointer(&in.Nodes))
+	out.Edges = *(*[]DependencyGraphEdge)(unsafe.Pointer(&in.Edges))
+	out.Diagram = in.Diagram
+	return nil
+}
+
+// Convert_porch_RepositoryDependencyGraph_To_v1alpha1_RepositoryDependencyGraph is an autogenerated conversion function.
+func Convert_porch_RepositoryDependencyGraph_To_v1alpha1_RepositoryDependencyGraph(in *porch.RepositoryDependencyGraph, out *RepositoryDependencyGraph, s conversion.Scope) error {
+	return autoConvert_porch_RepositoryDependencyGraph_To_v1alpha1_RepositoryDependencyGraph(in, out, s)
+}
+
+func autoConvert_v1alpha1_RepositoryDependencyGraphOptions_To_porch_RepositoryDependencyGraphOptions(in *RepositoryDependencyGraphOptions, out *porch.RepositoryDependencyGraphOptions, s conversion.Scope) error {
+	out.Format = in.Format
+	return nil
+}
+
+// Convert_v1alpha1_RepositoryDependencyGraphOptions_To_porch_RepositoryDependencyGraphOptions is an autogenerated conversion function.
+func Convert_v1alpha1_RepositoryDependencyGraphOptions_To_porch_RepositoryDependencyGraphOptions(in *RepositoryDependencyGraphOptions, out *porch.RepositoryDependencyGraphOptions, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RepositoryDependencyGraphOptions_To_porch_RepositoryDependencyGraphOptions(in, out, s)
+}
+
+func autoConvert_porch_RepositoryDependencyGraphOptions_To_v1alpha1_RepositoryDependencyGraphOptions(in *porch.RepositoryDependencyGraphOptions, out *RepositoryDependencyGraphOptions, s conversion.Scope) error {
+	out.Format = in.Format
+	return nil
+}
+
+// Convert_porch_RepositoryDependencyGraphOptions_To_v1alpha1_RepositoryDependencyGraphOptions is an autogenerated conversion function.
+func Convert_porch_RepositoryDependencyGraphOptions_To_v1alpha1_RepositoryDependencyGraphOptions(in *porch.RepositoryDependencyGraphOptions, out *RepositoryDependencyGraphOptions, s conversion.Scope) error {
+	return autoConvert_porch_RepositoryDependencyGraphOptions_To_v1alpha1_RepositoryDependencyGraphOptions(in, out, s)
+}
+
 func autoConvert_v1alpha1_RepositoryRef_To_porch_RepositoryRef(in *RepositoryRef, out *porch.RepositoryRef, s conversion.Scope) error {
 	out.Name = in.Name
 	return nil
@@ -801,6 +1972,84 @@ func Convert_porch_RepositoryRef_To_v1alpha1_RepositoryRef(in *porch.RepositoryR
 	return autoConvert_porch_RepositoryRef_To_v1alpha1_RepositoryRef(in, out, s)
 }
 
+func autoConvert_v1alpha1_RequiredSecret_To_porch_RequiredSecret(in *RequiredSecret, out *porch.RequiredSecret, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	out.Keys = *(*[]string)(unsafe.Pointer(&in.Keys))
+	return nil
+}
+
+// Convert_v1alpha1_RequiredSecret_To_porch_RequiredSecret is an autogenerated conversion function.
+func Convert_v1alpha1_RequiredSecret_To_porch_RequiredSecret(in *RequiredSecret, out *porch.RequiredSecret, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RequiredSecret_To_porch_RequiredSecret(in, out, s)
+}
+
+func autoConvert_porch_RequiredSecret_To_v1alpha1_RequiredSecret(in *porch.RequiredSecret, out *RequiredSecret, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	out.Keys = *(*[]string)(unsafe.Pointer(&in.Keys))
+	return nil
+}
+
+// Convert_porch_RequiredSecret_To_v1alpha1_RequiredSecret is an autogenerated conversion function.
+func Convert_porch_RequiredSecret_To_v1alpha1_RequiredSecret(in *porch.RequiredSecret, out *RequiredSecret, s conversion.Scope) error {
+	return autoConvert_porch_RequiredSecret_To_v1alpha1_RequiredSecret(in, out, s)
+}
+
+func autoConvert_v1alpha1_ResourceCostEstimate_To_porch_ResourceCostEstimate(in *ResourceCostEstimate, out *porch.ResourceCostEstimate, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Count = in.Count
+	out.CPU = in.CPU
+	out.Memory = in.Memory
+	out.Storage = in.Storage
+	out.HourlyCostUSD = in.HourlyCostUSD
+	return nil
+}
+
+// Convert_v1alpha1_ResourceCostEstimate_To_porch_ResourceCostEstimate is an autogenerated conversion function.
+func Convert_v1alpha1_ResourceCostEstimate_To_porch_ResourceCostEstimate(in *ResourceCostEstimate, out *porch.ResourceCostEstimate, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ResourceCostEstimate_To_porch_ResourceCostEstimate(in, out, s)
+}
+
+func autoConvert_porch_ResourceCostEstimate_To_v1alpha1_ResourceCostEstimate(in *porch.ResourceCostEstimate, out *ResourceCostEstimate, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Count = in.Count
+	out.CPU = in.CPU
+	out.Memory = in.Memory
+	out.Storage = in.Storage
+	out.HourlyCostUSD = in.HourlyCostUSD
+	return nil
+}
+
+// Convert_porch_ResourceCostEstimate_To_v1alpha1_ResourceCostEstimate is an autogenerated conversion function.
+func Convert_porch_ResourceCostEstimate_To_v1alpha1_ResourceCostEstimate(in *porch.ResourceCostEstimate, out *ResourceCostEstimate, s conversion.Scope) error {
+	return autoConvert_porch_ResourceCostEstimate_To_v1alpha1_ResourceCostEstimate(in, out, s)
+}
+
+func autoConvert_v1alpha1_ResourceValidationError_To_porch_ResourceValidationError(in *ResourceValidationError, out *porch.ResourceValidationError, s conversion.Scope) error {
+	out.Filename = in.Filename
+	out.Path = in.Path
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1alpha1_ResourceValidationError_To_porch_ResourceValidationError is an autogenerated conversion function.
+func Convert_v1alpha1_ResourceValidationError_To_porch_ResourceValidationError(in *ResourceValidationError, out *porch.ResourceValidationError, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ResourceValidationError_To_porch_ResourceValidationError(in, out, s)
+}
+
+func autoConvert_porch_ResourceValidationError_To_v1alpha1_ResourceValidationError(in *porch.ResourceValidationError, out *ResourceValidationError, s conversion.Scope) error {
+	out.Filename = in.Filename
+	out.Path = in.Path
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_porch_ResourceValidationError_To_v1alpha1_ResourceValidationError is an autogenerated conversion function.
+func Convert_porch_ResourceValidationError_To_v1alpha1_ResourceValidationError(in *porch.ResourceValidationError, out *ResourceValidationError, s conversion.Scope) error {
+	return autoConvert_porch_ResourceValidationError_To_v1alpha1_ResourceValidationError(in, out, s)
+}
+
 func autoConvert_v1alpha1_SecretRef_To_porch_SecretRef(in *SecretRef, out *porch.SecretRef, s conversion.Scope) error {
 	out.Name = in.Name
 	return nil
@@ -875,6 +2124,70 @@ func Convert_porch_Task_To_v1alpha1_Task(in *porch.Task, out *Task, s conversion
 	return autoConvert_porch_Task_To_v1alpha1_Task(in, out, s)
 }
 
+func autoConvert_v1alpha1_TemplateExpansionRequest_To_porch_TemplateExpansionRequest(in *TemplateExpansionRequest, out *porch.TemplateExpansionRequest, s conversion.Scope) error {
+	out.Engine = porch.TemplateEngine(in.Engine)
+	out.Values = *(*map[string]interface{})(unsafe.Pointer(&in.Values))
+	return nil
+}
+
+// Convert_v1alpha1_TemplateExpansionRequest_To_porch_TemplateExpansionRequest is an autogenerated conversion function.
+func Convert_v1alpha1_TemplateExpansionRequest_To_porch_TemplateExpansionRequest(in *TemplateExpansionRequest, out *porch.TemplateExpansionRequest, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TemplateExpansionRequest_To_porch_TemplateExpansionRequest(in, out, s)
+}
+
+func autoConvert_porch_TemplateExpansionRequest_To_v1alpha1_TemplateExpansionRequest(in *porch.TemplateExpansionRequest, out *TemplateExpansionRequest, s conversion.Scope) error {
+	out.Engine = TemplateEngine(in.Engine)
+	out.Values = *(*map[string]interface{})(unsafe.Pointer(&in.Values))
+	return nil
+}
+
+// Convert_porch_TemplateExpansionRequest_To_v1alpha1_TemplateExpansionRequest is an autogenerated conversion function.
+func Convert_porch_TemplateExpansionRequest_To_v1alpha1_TemplateExpansionRequest(in *porch.TemplateExpansionRequest, out *TemplateExpansionRequest, s conversion.Scope) error {
+	return autoConvert_porch_TemplateExpansionRequest_To_v1alpha1_TemplateExpansionRequest(in, out, s)
+}
+
+func autoConvert_v1alpha1_TransferRequest_To_porch_TransferRequest(in *TransferRequest, out *porch.TransferRequest, s conversion.Scope) error {
+	out.TargetRepository = in.TargetRepository
+	return nil
+}
+
+// Convert_v1alpha1_TransferRequest_To_porch_TransferRequest is an autogenerated conversion function.
+func Convert_v1alpha1_TransferRequest_To_porch_TransferRequest(in *TransferRequest, out *porch.TransferRequest, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TransferRequest_To_porch_TransferRequest(in, out, s)
+}
+
+func autoConvert_porch_TransferRequest_To_v1alpha1_TransferRequest(in *porch.TransferRequest, out *TransferRequest, s conversion.Scope) error {
+	out.TargetRepository = in.TargetRepository
+	return nil
+}
+
+// Convert_porch_TransferRequest_To_v1alpha1_TransferRequest is an autogenerated conversion function.
+func Convert_porch_TransferRequest_To_v1alpha1_TransferRequest(in *porch.TransferRequest, out *TransferRequest, s conversion.Scope) error {
+	return autoConvert_porch_TransferRequest_To_v1alpha1_TransferRequest(in, out, s)
+}
+
+func autoConvert_v1alpha1_UpstreamLock_To_porch_UpstreamLock(in *UpstreamLock, out *porch.UpstreamLock, s conversion.Scope) error {
+	out.Type = porch.RepositoryType(in.Type)
+	out.Git = (*porch.GitLock)(unsafe.Pointer(in.Git))
+	return nil
+}
+
+// Convert_v1alpha1_UpstreamLock_To_porch_UpstreamLock is an autogenerated conversion function.
+func Convert_v1alpha1_UpstreamLock_To_porch_UpstreamLock(in *UpstreamLock, out *porch.UpstreamLock, s conversion.Scope) error {
+	return autoConvert_v1alpha1_UpstreamLock_To_porch_UpstreamLock(in, out, s)
+}
+
+func autoConvert_porch_UpstreamLock_To_v1alpha1_UpstreamLock(in *porch.UpstreamLock, out *UpstreamLock, s conversion.Scope) error {
+	out.Type = RepositoryType(in.Type)
+	out.Git = (*GitLock)(unsafe.Pointer(in.Git))
+	return nil
+}
+
+// Convert_porch_UpstreamLock_To_v1alpha1_UpstreamLock is an autogenerated conversion function.
+func Convert_porch_UpstreamLock_To_v1alpha1_UpstreamLock(in *porch.UpstreamLock, out *UpstreamLock, s conversion.Scope) error {
+	return autoConvert_porch_UpstreamLock_To_v1alpha1_UpstreamLock(in, out, s)
+}
+
 func autoConvert_v1alpha1_UpstreamPackage_To_porch_UpstreamPackage(in *UpstreamPackage, out *porch.UpstreamPackage, s conversion.Scope) error {
 	out.Type = porch.RepositoryType(in.Type)
 	out.Git = (*porch.GitPackage)(unsafe.Pointer(in.Git))
@@ -900,3 +2213,57 @@ func autoConvert_porch_UpstreamPackage_To_v1alpha1_UpstreamPackage(in *porch.Ups
 func Convert_porch_UpstreamPackage_To_v1alpha1_UpstreamPackage(in *porch.UpstreamPackage, out *UpstreamPackage, s conversion.Scope) error {
 	return autoConvert_porch_UpstreamPackage_To_v1alpha1_UpstreamPackage(in, out, s)
 }
+
+func autoConvert_url_Values_To_v1alpha1_PackageRevisionDiffOptions(in *url.Values, out *PackageRevisionDiffOptions, s conversion.Scope) error {
+	// WARNING: Field TypeMeta does not have json tag, skipping.
+
+	if values, ok := map[string][]string(*in)["base"]; ok && len(values) > 0 {
+		if err := runtime.Convert_Slice_string_To_string(&values, &out.Base, s); err != nil {
+			return err
+		}
+	} else {
+		out.Base = ""
+	}
+	return nil
+}
+
+// Convert_url_Values_To_v1alpha1_PackageRevisionDiffOptions is an autogenerated conversion function.
+func Convert_url_Values_To_v1alpha1_PackageRevisionDiffOptions(in *url.Values, out *PackageRevisionDiffOptions, s conversion.Scope) error {
+	return autoConvert_url_Values_To_v1alpha1_PackageRevisionDiffOptions(in, out, s)
+}
+
+func autoConvert_url_Values_To_v1alpha1_PackageRevisionSchemaDiffOptions(in *url.Values, out *PackageRevisionSchemaDiffOptions, s conversion.Scope) error {
+	// WARNING: Field TypeMeta does not have json tag, skipping.
+
+	if values, ok := map[string][]string(*in)["base"]; ok && len(values) > 0 {
+		if err := runtime.Convert_Slice_string_To_string(&values, &out.Base, s); err != nil {
+			return err
+		}
+	} else {
+		out.Base = ""
+	}
+	return nil
+}
+
+// Convert_url_Values_To_v1alpha1_PackageRevisionSchemaDiffOptions is an autogenerated conversion function.
+func Convert_url_Values_To_v1alpha1_PackageRevisionSchemaDiffOptions(in *url.Values, out *PackageRevisionSchemaDiffOptions, s conversion.Scope) error {
+	return autoConvert_url_Values_To_v1alpha1_PackageRevisionSchemaDiffOptions(in, out, s)
+}
+
+func autoConvert_url_Values_To_v1alpha1_RepositoryDependencyGraphOptions(in *url.Values, out *RepositoryDependencyGraphOptions, s conversion.Scope) error {
+	// WARNING: Field TypeMeta does not have json tag, skipping.
+
+	if values, ok := map[string][]string(*in)["format"]; ok && len(values) > 0 {
+		if err := runtime.Convert_Slice_string_To_string(&values, &out.Format, s); err != nil {
+			return err
+		}
+	} else {
+		out.Format = ""
+	}
+	return nil
+}
+
+// Convert_url_Values_To_v1alpha1_RepositoryDependencyGraphOptions is an autogenerated conversion function.
+func Convert_url_Values_To_v1alpha1_RepositoryDependencyGraphOptions(in *url.Values, out *RepositoryDependencyGraphOptions, s conversion.Scope) error {
+	return autoConvert_url_Values_To_v1alpha1_RepositoryDependencyGraphOptions(in, out, s)
+}
@@ -20,9 +20,186 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConflictResolution) DeepCopyInto(out *ConflictResolution) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConflictResolution.
+func (in *ConflictResolution) DeepCopy() *ConflictResolution {
+	if in == nil {
+		return nil
+	}
+	out := new(ConflictResolution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConflictResolution) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConflictResolutionSpec) DeepCopyInto(out *ConflictResolutionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConflictResolutionSpec.
+func (in *ConflictResolutionSpec) DeepCopy() *ConflictResolutionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConflictResolutionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConflictResolutionStatus) DeepCopyInto(out *ConflictResolutionStatus) {
+	*out = *in
+	if in.RemainingConflicts != nil {
+		in, out := &in.RemainingConflicts, &out.RemainingConflicts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConflictResolutionStatus.
+func (in *ConflictResolutionStatus) DeepCopy() *ConflictResolutionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConflictResolutionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionTestResult) DeepCopyInto(out *ConnectionTestResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionTestResult.
+func (in *ConnectionTestResult) DeepCopy() *ConnectionTestResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionTestResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceCostEstimate, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CostEstimate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyGraphEdge) DeepCopyInto(out *DependencyGraphEdge) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyGraphEdge.
+func (in *DependencyGraphEdge) DeepCopy() *DependencyGraphEdge {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyGraphEdge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileDiff) DeepCopyInto(out *FileDiff) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileDiff.
+func (in *FileDiff) DeepCopy() *FileDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(FileDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileMetadata) DeepCopyInto(out *FileMetadata) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileMetadata.
+func (in *FileMetadata) DeepCopy() *FileMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(FileMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileUpdate) DeepCopyInto(out *FileUpdate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileUpdate.
+func (in *FileUpdate) DeepCopy() *FileUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(FileUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Function) DeepCopyInto(out *Function) {
 	*out = *in
@@ -201,6 +378,22 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitLock) DeepCopyInto(out *GitLock) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitLock.
+func (in *GitLock) DeepCopy() *GitLock {
+	if in == nil {
+		return nil
+	}
+	out := new(GitLock)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitPackage) DeepCopyInto(out *GitPackage) {
 	*out = *in
@@ -218,6 +411,53 @@ func (in *GitPackage) DeepCopy() *GitPackage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LintFinding) DeepCopyInto(out *LintFinding) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LintFinding.
+func (in *LintFinding) DeepCopy() *LintFinding {
+	if in == nil {
+		return nil
+	}
+	out := new(LintFinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LintReport) DeepCopyInto(out *LintReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Findings != nil {
+		in, out := &in.Findings, &out.Findings
+		*out = make([]LintFinding, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LintReport.
+func (in *LintReport) DeepCopy() *LintReport {
+	if in == nil {
+		return nil
+	}
+	out := new(LintReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LintReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OciPackage) DeepCopyInto(out *OciPackage) {
 	*out = *in
@@ -294,27 +534,43 @@ func (in *PackagePatchTaskSpec) DeepCopy() *PackagePatchTaskSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRevision) DeepCopyInto(out *PackageRevision) {
+func (in *PackageResourceMatch) DeepCopyInto(out *PackageResourceMatch) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageResourceMatch.
+func (in *PackageResourceMatch) DeepCopy() *PackageResourceMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageResourceMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageResourceSearch) DeepCopyInto(out *PackageResourceSearch) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevision.
-func (in *PackageRevision) DeepCopy() *PackageRevision {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageResourceSearch.
+func (in *PackageResourceSearch) DeepCopy() *PackageResourceSearch {
 	if in == nil {
 		return nil
 	}
-	out := new(PackageRevision)
+	out := new(PackageResourceSearch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PackageRevision) DeepCopyObject() runtime.Object {
+func (in *PackageResourceSearch) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -322,75 +578,69 @@ func (in *PackageRevision) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRevisionList) DeepCopyInto(out *PackageRevisionList) {
+func (in *PackageResourceSearchResult) DeepCopyInto(out *PackageResourceSearchResult) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]PackageRevision, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make([]PackageResourceMatch, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionList.
-func (in *PackageRevisionList) DeepCopy() *PackageRevisionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageResourceSearchResult.
+func (in *PackageResourceSearchResult) DeepCopy() *PackageResourceSearchResult {
 	if in == nil {
 		return nil
 	}
-	out := new(PackageRevisionList)
+	out := new(PackageResourceSearchResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PackageRevisionList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRevisionRef) DeepCopyInto(out *PackageRevisionRef) {
+func (in *PackageResourceSearchSpec) DeepCopyInto(out *PackageResourceSearchSpec) {
 	*out = *in
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionRef.
-func (in *PackageRevisionRef) DeepCopy() *PackageRevisionRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageResourceSearchSpec.
+func (in *PackageResourceSearchSpec) DeepCopy() *PackageResourceSearchSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PackageRevisionRef)
+	out := new(PackageResourceSearchSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRevisionResources) DeepCopyInto(out *PackageRevisionResources) {
+func (in *PackageRevision) DeepCopyInto(out *PackageRevision) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionResources.
-func (in *PackageRevisionResources) DeepCopy() *PackageRevisionResources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevision.
+func (in *PackageRevision) DeepCopy() *PackageRevision {
 	if in == nil {
 		return nil
 	}
-	out := new(PackageRevisionResources)
+	out := new(PackageRevision)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PackageRevisionResources) DeepCopyObject() runtime.Object {
+func (in *PackageRevision) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -398,32 +648,30 @@ func (in *PackageRevisionResources) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRevisionResourcesList) DeepCopyInto(out *PackageRevisionResourcesList) {
+func (in *PackageRevisionDiff) DeepCopyInto(out *PackageRevisionDiff) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]PackageRevisionResources, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]FileDiff, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionResourcesList.
-func (in *PackageRevisionResourcesList) DeepCopy() *PackageRevisionResourcesList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionDiff.
+func (in *PackageRevisionDiff) DeepCopy() *PackageRevisionDiff {
 	if in == nil {
 		return nil
 	}
-	out := new(PackageRevisionResourcesList)
+	out := new(PackageRevisionDiff)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PackageRevisionResourcesList) DeepCopyObject() runtime.Object {
+func (in *PackageRevisionDiff) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -431,13 +679,259 @@ func (in *PackageRevisionResourcesList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRevisionResourcesSpec) DeepCopyInto(out *PackageRevisionResourcesSpec) {
+func (in *PackageRevisionDiffOptions) DeepCopyInto(out *PackageRevisionDiffOptions) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionDiffOptions.
+func (in *PackageRevisionDiffOptions) DeepCopy() *PackageRevisionDiffOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionDiffOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRevisionDiffOptions) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionLineage) DeepCopyInto(out *PackageRevisionLineage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Ancestors != nil {
+		in, out := &in.Ancestors, &out.Ancestors
+		*out = make([]PackageRevisionLineageEntry, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionLineage.
+func (in *PackageRevisionLineage) DeepCopy() *PackageRevisionLineage {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionLineage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRevisionLineage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionLineageEntry) DeepCopyInto(out *PackageRevisionLineageEntry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionLineageEntry.
+func (in *PackageRevisionLineageEntry) DeepCopy() *PackageRevisionLineageEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionLineageEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionList) DeepCopyInto(out *PackageRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PackageRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionList.
+func (in *PackageRevisionList) DeepCopy() *PackageRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionRef) DeepCopyInto(out *PackageRevisionRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionRef.
+func (in *PackageRevisionRef) DeepCopy() *PackageRevisionRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionResources) DeepCopyInto(out *PackageRevisionResources) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionResources.
+func (in *PackageRevisionResources) DeepCopy() *PackageRevisionResources {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRevisionResources) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionResourcesBatchUpdate) DeepCopyInto(out *PackageRevisionResourcesBatchUpdate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionResourcesBatchUpdate.
+func (in *PackageRevisionResourcesBatchUpdate) DeepCopy() *PackageRevisionResourcesBatchUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionResourcesBatchUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRevisionResourcesBatchUpdate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionResourcesBatchUpdateSpec) DeepCopyInto(out *PackageRevisionResourcesBatchUpdateSpec) {
+	*out = *in
+	if in.Updates != nil {
+		in, out := &in.Updates, &out.Updates
+		*out = make([]FileUpdate, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionResourcesBatchUpdateSpec.
+func (in *PackageRevisionResourcesBatchUpdateSpec) DeepCopy() *PackageRevisionResourcesBatchUpdateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionResourcesBatchUpdateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionResourcesBatchUpdateStatus) DeepCopyInto(out *PackageRevisionResourcesBatchUpdateStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionResourcesBatchUpdateStatus.
+func (in *PackageRevisionResourcesBatchUpdateStatus) DeepCopy() *PackageRevisionResourcesBatchUpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionResourcesBatchUpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionResourcesList) DeepCopyInto(out *PackageRevisionResourcesList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PackageRevisionResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionResourcesList.
+func (in *PackageRevisionResourcesList) DeepCopy() *PackageRevisionResourcesList {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionResourcesList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRevisionResourcesList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionResourcesSpec) DeepCopyInto(out *PackageRevisionResourcesSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 	return
@@ -453,6 +947,105 @@ func (in *PackageRevisionResourcesSpec) DeepCopy() *PackageRevisionResourcesSpec
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionResourcesStatus) DeepCopyInto(out *PackageRevisionResourcesStatus) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make(map[string]FileMetadata, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionResourcesStatus.
+func (in *PackageRevisionResourcesStatus) DeepCopy() *PackageRevisionResourcesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionResourcesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionSchemaDiff) DeepCopyInto(out *PackageRevisionSchemaDiff) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.AddedKinds != nil {
+		in, out := &in.AddedKinds, &out.AddedKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedKinds != nil {
+		in, out := &in.RemovedKinds, &out.RemovedKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ModifiedKinds != nil {
+		in, out := &in.ModifiedKinds, &out.ModifiedKinds
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionSchemaDiff.
+func (in *PackageRevisionSchemaDiff) DeepCopy() *PackageRevisionSchemaDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionSchemaDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRevisionSchemaDiff) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionSchemaDiffOptions) DeepCopyInto(out *PackageRevisionSchemaDiffOptions) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionSchemaDiffOptions.
+func (in *PackageRevisionSchemaDiffOptions) DeepCopy() *PackageRevisionSchemaDiffOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionSchemaDiffOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRevisionSchemaDiffOptions) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageRevisionSpec) DeepCopyInto(out *PackageRevisionSpec) {
 	*out = *in
@@ -463,6 +1056,25 @@ func (in *PackageRevisionSpec) DeepCopyInto(out *PackageRevisionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RequiredSecrets != nil {
+		in, out := &in.RequiredSecrets, &out.RequiredSecrets
+		*out = make([]RequiredSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(PackageRevisionTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageLocks != nil {
+		in, out := &in.ImageLocks, &out.ImageLocks
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -479,6 +1091,26 @@ func (in *PackageRevisionSpec) DeepCopy() *PackageRevisionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
 	*out = *in
+	in.LastRenderedAt.DeepCopyInto(&out.LastRenderedAt)
+	if in.LastRenderedFunctionDigests != nil {
+		in, out := &in.LastRenderedFunctionDigests, &out.LastRenderedFunctionDigests
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpstreamLock != nil {
+		in, out := &in.UpstreamLock, &out.UpstreamLock
+		*out = new(UpstreamLock)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -492,6 +1124,146 @@ func (in *PackageRevisionStatus) DeepCopy() *PackageRevisionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionTarget) DeepCopyInto(out *PackageRevisionTarget) {
+	*out = *in
+	if in.Kubeconfig != nil {
+		in, out := &in.Kubeconfig, &out.Kubeconfig
+		*out = new(SecretRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionTarget.
+func (in *PackageRevisionTarget) DeepCopy() *PackageRevisionTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionValidationReport) DeepCopyInto(out *PackageRevisionValidationReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]ResourceValidationError, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionValidationReport.
+func (in *PackageRevisionValidationReport) DeepCopy() *PackageRevisionValidationReport {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionValidationReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRevisionValidationReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConnectionTest) DeepCopyInto(out *RepositoryConnectionTest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConnectionTest.
+func (in *RepositoryConnectionTest) DeepCopy() *RepositoryConnectionTest {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConnectionTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryConnectionTest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryDependencyGraph) DeepCopyInto(out *RepositoryDependencyGraph) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Edges != nil {
+		in, out := &in.Edges, &out.Edges
+		*out = make([]DependencyGraphEdge, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryDependencyGraph.
+func (in *RepositoryDependencyGraph) DeepCopy() *RepositoryDependencyGraph {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryDependencyGraph)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryDependencyGraph) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryDependencyGraphOptions) DeepCopyInto(out *RepositoryDependencyGraphOptions) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryDependencyGraphOptions.
+func (in *RepositoryDependencyGraphOptions) DeepCopy() *RepositoryDependencyGraphOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryDependencyGraphOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryDependencyGraphOptions) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepositoryRef) DeepCopyInto(out *RepositoryRef) {
 	*out = *in
@@ -508,6 +1280,59 @@ func (in *RepositoryRef) DeepCopy() *RepositoryRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredSecret) DeepCopyInto(out *RequiredSecret) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredSecret.
+func (in *RequiredSecret) DeepCopy() *RequiredSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceCostEstimate) DeepCopyInto(out *ResourceCostEstimate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceCostEstimate.
+func (in *ResourceCostEstimate) DeepCopy() *ResourceCostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceCostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceValidationError) DeepCopyInto(out *ResourceValidationError) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceValidationError.
+func (in *ResourceValidationError) DeepCopy() *ResourceValidationError {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceValidationError)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
@@ -576,6 +1401,52 @@ func (in *Task) DeepCopy() *Task {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferRequest) DeepCopyInto(out *TransferRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferRequest.
+func (in *TransferRequest) DeepCopy() *TransferRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TransferRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamLock) DeepCopyInto(out *UpstreamLock) {
+	*out = *in
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitLock)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamLock.
+func (in *UpstreamLock) DeepCopy() *UpstreamLock {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamLock)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpstreamPackage) DeepCopyInto(out *UpstreamPackage) {
 	*out = *in
@@ -36,7 +36,27 @@ var (
 		objects:  []runtime.Object{&Repository{}, &RepositoryList{}},
 	}
 
-	AllKinds = []KindInfo{KindRepository}
+	KindTransitionWebhook = KindInfo{
+		Resource: GroupVersion.WithResource("transitionwebhooks"),
+		objects:  []runtime.Object{&TransitionWebhook{}, &TransitionWebhookList{}},
+	}
+
+	KindPricingConfig = KindInfo{
+		Resource: GroupVersion.WithResource("pricingconfigs"),
+		objects:  []runtime.Object{&PricingConfig{}, &PricingConfigList{}},
+	}
+
+	KindLintPolicy = KindInfo{
+		Resource: GroupVersion.WithResource("lintpolicies"),
+		objects:  []runtime.Object{&LintPolicy{}, &LintPolicyList{}},
+	}
+
+	KindNotificationConfig = KindInfo{
+		Resource: GroupVersion.WithResource("notificationconfigs"),
+		objects:  []runtime.Object{&NotificationConfig{}, &NotificationConfigList{}},
+	}
+
+	AllKinds = []KindInfo{KindRepository, KindTransitionWebhook, KindPricingConfig, KindLintPolicy, KindNotificationConfig}
 )
 
 //+kubebuilder:object:generate=false
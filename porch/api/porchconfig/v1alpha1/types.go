@@ -20,6 +20,7 @@ import (
 
 //+kubebuilder:object:root=true
 //+kubebuilder:resource:path=repositories,singular=repository
+//+kubebuilder:subresource:status
 
 // Repository
 type Repository struct {
@@ -30,6 +31,25 @@ type Repository struct {
 	Status RepositoryStatus `json:"status,omitempty"`
 }
 
+const (
+	// AnnotationSyncRequested requests porch to synchronize the repository
+	// immediately rather than at the next polling interval. The value is an
+	// opaque token unique to the request; changing it requests a new sync.
+	// Once the sync completes, the token is recorded in the LastSyncRequest
+	// field of the repository status.
+	AnnotationSyncRequested = "kpt.dev/sync-requested"
+
+	// RepositoryConditionUpstreamSynced reports whether the packages of the
+	// repository were synchronized with the upstream repository after the last
+	// requested sync.
+	RepositoryConditionUpstreamSynced = "UpstreamSynced"
+
+	// RepositoryConditionReady reports whether porch opened the repository and
+	// loaded its packages, for the generation of the repository in
+	// observedGeneration.
+	RepositoryConditionReady = "Ready"
+)
+
 type RepositoryType string
 
 const (
@@ -60,10 +80,15 @@ type RepositorySpec struct {
 	// Content stored in the repository (i.e. Function, Package - the literal values correspond to the API resource names).
 	// TODO: support repository with mixed content?
 	Content RepositoryContent `json:"content,omitempty"`
+	// Priority of a function repository. When several function repositories provide a function with the same name and version,
+	// the function from the repository with the highest priority is used.
+	Priority int `json:"priority,omitempty"`
 	// Git repository details. Required if `type` is `git`. Ignored if `type` is not `git`.
 	Git *GitRepository `json:"git,omitempty"`
 	// OCI repository details. Required if `type` is `oci`. Ignored if `type` is not `oci`.
 	Oci *OciRepository `json:"oci,omitempty"`
+	// PackageNamingPolicy restricts the names of packages created in the repository.
+	PackageNamingPolicy *PackageNamingPolicy `json:"packageNamingPolicy,omitempty"`
 	// Upstream is the default upstream repository for packages in this
 	// repository. Specifying it per repository allows simpler UX when
 	// creating packages.
@@ -80,6 +105,19 @@ type RepositorySpec struct {
 	// Based on the Kubernetest Admission Controllers (https://kubernetes.io/docs/reference/access-authn-authz/admission-controllers/). The functions will be evaluated
 	// in the order specified in the list.
 	Validators []FunctionEval `json:"validators,omitempty"`
+
+	// RequireValidation requires the resources of package revisions to pass validation against the OpenAPI
+	// schemas of their kinds before the package revisions can be approved.
+	RequireValidation bool `json:"requireValidation,omitempty"`
+
+	// MaxPackageSizeBytes limits the total size of the files of each package revision in the repository.
+	// Zero or unspecified means no limit.
+	MaxPackageSizeBytes int64 `json:"maxPackageSizeBytes,omitempty"`
+
+	// TektonPipelineRef references a Tekton Pipeline in the namespace of the repository. When set, a PipelineRun
+	// of the pipeline is created for each package revision proposed in the repository, and the package revision
+	// can only be approved once the PipelineRun succeeds.
+	TektonPipelineRef *TektonPipelineRef `json:"tektonPipelineRef,omitempty"`
 }
 
 // GitRepository describes a Git repository.
@@ -88,14 +126,40 @@ type GitRepository struct {
 	// Address of the Git repository, for example:
 	//   `https://github.com/GoogleCloudPlatform/blueprints.git`
 	Repo string `json:"repo"`
-	// Name of the branch containig the packages. Finalized packages will be committed to this branch (if the repository allows write access). If unspecified, defaults to "main".
+	// Name of the branch containig the packages. Finalized packages will be committed to this branch (if the repository allows write access). If unspecified, defaults to the default branch of the repository, which HEAD refers to, or "main" if the repository is empty.
 	Branch string `json:"branch,omitempty"`
 	// Directory within the Git repository where the packages are stored. A subdirectory of this directory containing a Kptfile is considered a package. If unspecified, defaults to root directory.
+	// The directory may be a pattern containing `*` or `?` wildcards, such as `teams/*`, to register the packages of all matching directories.
 	Directory string `json:"directory,omitempty"`
 	// Reference to secret containing authentication credentials.
 	SecretRef SecretRef `json:"secretRef,omitempty"`
+	// Name of the git remote used to fetch from and push to the repository. If unspecified, defaults to "origin".
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+$`
+	RemoteName string `json:"remoteName,omitempty"`
+	// Submodules controls whether the content of git submodules is included in packages. If `Recursive`,
+	// the content of submodules within a package is fetched and included at the submodule path. Submodules
+	// must be hosted on the same server as the repository, and fetched over https, ssh or the protocol of
+	// the repository.
+	// +kubebuilder:validation:Enum=Recursive
+	Submodules GitSubmodules `json:"submodules,omitempty"`
+	// SubmoduleDepth limits the nesting of submodules fetched when `submodules` is `Recursive`. If unspecified,
+	// defaults to 1: only submodules of the repository itself are fetched.
+	SubmoduleDepth int `json:"submoduleDepth,omitempty"`
+	// CommitTemplate is a Go text/template for the messages of the commits Porch makes to the package revisions of
+	// the repository. The template can use `{{.PackageName}}`, `{{.Revision}}`, `{{.Lifecycle}}`, `{{.User}}`,
+	// `{{.Timestamp}}` and `{{.ChangelogEntry}}`. The rendered message must not be empty or exceed 500 characters.
+	// If unspecified, Porch generates the messages.
+	CommitTemplate string `json:"commitTemplate,omitempty"`
 }
 
+// GitSubmodules controls how git submodules are handled.
+type GitSubmodules string
+
+const (
+	// GitSubmodulesRecursive includes the content of submodules in packages.
+	GitSubmodulesRecursive GitSubmodules = "Recursive"
+)
+
 // OciRepository describes a repository compatible with the Open Container Registry standard.
 // TODO: allow sub-selection of the registry, i.e. filter by tags, ...?
 // TODO: authentication types?
@@ -104,6 +168,32 @@ type OciRepository struct {
 	Registry string `json:"registry"`
 	// Reference to secret containing authentication credentials.
 	SecretRef SecretRef `json:"secretRef,omitempty"`
+	// ContentDigestPolicy controls whether OCI images referenced by package revisions in the repository
+	// may use tags. If `RequireDigest`, references must use `@sha256:` digests.
+	ContentDigestPolicy ContentDigestPolicy `json:"contentDigestPolicy,omitempty"`
+}
+
+// ContentDigestPolicy controls how package revisions reference OCI images.
+type ContentDigestPolicy string
+
+const (
+	// ContentDigestPolicyRequireDigest requires OCI image references to use digests rather than tags.
+	ContentDigestPolicyRequireDigest ContentDigestPolicy = "RequireDigest"
+)
+
+// PackageNamingPolicy restricts the names of packages created in a repository.
+type PackageNamingPolicy struct {
+	// ReservedPrefixes lists package name prefixes reserved for system use. Packages whose names start with
+	// one of the prefixes cannot be created. If unspecified, defaults to `kpt-` and `porch-`; an empty list
+	// allows all package names.
+	// +optional
+	ReservedPrefixes []string `json:"reservedPrefixes"`
+}
+
+// TektonPipelineRef references a Tekton Pipeline.
+type TektonPipelineRef struct {
+	// Name of the Pipeline.
+	Name string `json:"name"`
 }
 
 // UpstreamRepository repository may be specified directly or by referencing another Repository resource.
@@ -146,6 +236,12 @@ type FunctionRef struct {
 
 // RepositoryStatus defines the observed state of Repository
 type RepositoryStatus struct {
+	// Conditions describes the state of the repository.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// DefaultBranch is the default branch of the git repository, detected when the repository is registered without `spec.git.branch`.
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+	// LastSyncRequest is the value of the `kpt.dev/sync-requested` annotation of the last sync request handled; the outcome of the sync is reported in the UpstreamSynced condition.
+	LastSyncRequest string `json:"lastSyncRequest,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,66 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=lintpolicies,singular=lintpolicy
+
+// LintPolicy configures the severity of the built-in linters run by the lint
+// subresource of the package revisions in the namespace.
+type LintPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec LintPolicySpec `json:"spec,omitempty"`
+}
+
+// LintPolicySpec defines the severity of the linters. Linters which are not
+// listed keep their built-in severity.
+type LintPolicySpec struct {
+	// Linters configures individual linters.
+	Linters []LinterConfig `json:"linters,omitempty"`
+}
+
+// LinterConfig configures a single built-in linter.
+type LinterConfig struct {
+	// Name of the linter: `NoLatestTag`, `ResourceLimitsRequired`,
+	// `NoPrivilegedContainers`, or `LabelsSufficient`.
+	Name string `json:"name"`
+	// Severity of the findings reported by the linter.
+	Severity LintSeverity `json:"severity"`
+}
+
+// LintSeverity is the severity of a lint finding. Findings with severity
+// `error` fail the lint report.
+// +kubebuilder:validation:Enum=warning;error
+type LintSeverity string
+
+const (
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityError   LintSeverity = "error"
+)
+
+//+kubebuilder:object:root=true
+
+// LintPolicyList contains a list of LintPolicy
+type LintPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LintPolicy `json:"items"`
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=notificationconfigs,singular=notificationconfig
+
+// NotificationConfig sends notifications to Slack, PagerDuty or a generic webhook
+// when package revisions in the namespace change lifecycle or fail to render.
+type NotificationConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NotificationConfigSpec `json:"spec,omitempty"`
+}
+
+// NotificationConfigSpec defines the events to notify of and where to send them.
+type NotificationConfigSpec struct {
+	// WebhookURL is the URL notifications are posted to: a Slack incoming webhook, the
+	// PagerDuty Events API v2 endpoint, or an endpoint accepting generic JSON events.
+	WebhookURL string `json:"webhookURL"`
+	// WebhookType selects the format of the notifications. Slack webhooks receive
+	// lifecycle transitions, PagerDuty webhooks receive render failures, and generic
+	// webhooks receive both.
+	WebhookType NotificationWebhookType `json:"webhookType"`
+	// Filter selects the package revisions to notify of. If unspecified, all package
+	// revisions in the namespace are notified of.
+	Filter NotificationFilter `json:"filter,omitempty"`
+	// Reference to a secret whose `routingKey` value is the PagerDuty integration key.
+	// Required for PagerDuty webhooks.
+	SecretRef *SecretRef `json:"secretRef,omitempty"`
+	// LinkBaseURL is the base URL of the package links in notifications; the link to a
+	// package revision is `<linkBaseURL>/<namespace>/<package revision name>`.
+	// If unspecified, notifications do not link to the package.
+	LinkBaseURL string `json:"linkBaseURL,omitempty"`
+}
+
+// NotificationWebhookType is the format of the notifications sent to a webhook.
+// +kubebuilder:validation:Enum=slack;pagerduty;generic
+type NotificationWebhookType string
+
+const (
+	NotificationWebhookTypeSlack     NotificationWebhookType = "slack"
+	NotificationWebhookTypePagerDuty NotificationWebhookType = "pagerduty"
+	NotificationWebhookTypeGeneric   NotificationWebhookType = "generic"
+)
+
+// NotificationFilter selects the package revisions to notify of.
+type NotificationFilter struct {
+	// Lifecycle limits notifications to transitions to this lifecycle value, and
+	// render failures to package revisions with this lifecycle value.
+	// +kubebuilder:validation:Enum=Draft;Proposed;Published
+	Lifecycle string `json:"lifecycle,omitempty"`
+	// RepositoryPattern limits notifications to package revisions in repositories whose
+	// name matches the glob pattern, for example `prod-*`.
+	RepositoryPattern string `json:"repositoryPattern,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NotificationConfigList contains a list of NotificationConfig
+type NotificationConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationConfig `json:"items"`
+}
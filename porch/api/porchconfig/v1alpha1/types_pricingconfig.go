@@ -0,0 +1,53 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=pricingconfigs,singular=pricingconfig
+
+// PricingConfig provides the unit prices used to estimate the cost of the
+// package revisions in the namespace.
+type PricingConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PricingConfigSpec `json:"spec,omitempty"`
+}
+
+// PricingConfigSpec defines the hourly unit prices, in US dollars, as decimal
+// strings such as `0.0316`. Resources without a price are estimated at no cost.
+type PricingConfigSpec struct {
+	// CPUCoreHour is the price of one CPU core for an hour.
+	CPUCoreHour string `json:"cpuCoreHour,omitempty"`
+	// MemoryGiBHour is the price of one GiB of memory for an hour.
+	MemoryGiBHour string `json:"memoryGiBHour,omitempty"`
+	// StorageGiBHour is the price of one GiB of persistent volume storage for an hour.
+	StorageGiBHour string `json:"storageGiBHour,omitempty"`
+	// LoadBalancerHour is the price of one load balancer for an hour.
+	LoadBalancerHour string `json:"loadBalancerHour,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PricingConfigList contains a list of PricingConfig
+type PricingConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PricingConfig `json:"items"`
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=transitionwebhooks,singular=transitionwebhook
+
+// TransitionWebhook notifies an external endpoint, such as a CI system, when a package revision
+// in the namespace transitions between lifecycle values.
+type TransitionWebhook struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TransitionWebhookSpec `json:"spec,omitempty"`
+}
+
+// TransitionWebhookSpec defines the transition and the endpoint to notify.
+type TransitionWebhookSpec struct {
+	// TransitionType is the lifecycle transition which triggers the webhook, in the form `From→To`,
+	// for example `Proposed→Published`. `Proposed->Published` is accepted as well.
+	TransitionType string `json:"transitionType"`
+	// Endpoint is the URL the transition is posted to.
+	Endpoint string `json:"endpoint"`
+	// Reference to a secret whose `key` value is used to sign the request body with HMAC-SHA256.
+	// The signature is sent in the `X-Porch-Signature` header. If unspecified, requests are not signed.
+	SecretRef *SecretRef `json:"secretRef,omitempty"`
+	// RetryPolicy controls how failed calls are retried.
+	RetryPolicy *WebhookRetryPolicy `json:"retryPolicy,omitempty"`
+	// Timeout of a single call to the endpoint. If unspecified, defaults to 10s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// WebhookRetryPolicy controls how failed webhook calls are retried.
+type WebhookRetryPolicy struct {
+	// MaxAttempts is the maximum number of calls made for a transition. If unspecified, defaults to 3.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Backoff is the delay before the first retry; the delay doubles after every retry.
+	// If unspecified, defaults to 1s.
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TransitionWebhookList contains a list of TransitionWebhook
+type TransitionWebhookList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TransitionWebhook `json:"items"`
+}
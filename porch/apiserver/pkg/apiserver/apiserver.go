@@ -17,13 +17,17 @@ package apiserver
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/GoogleContainerTools/kpt/porch/api/porch/install"
 	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/porch/apiserver/pkg/registry/porch"
 	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
 	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/kpt"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock"
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/cache"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/git"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/oci"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -64,9 +68,24 @@ func init() {
 
 // ExtraConfig holds custom apiserver config
 type ExtraConfig struct {
-	CoreAPIKubeconfigPath string
-	CacheDirectory        string
-	FunctionRunnerAddress string
+	CoreAPIKubeconfigPath      string
+	CacheDirectory             string
+	FunctionRunnerAddress      string
+	CircuitBreakerResetTimeout time.Duration
+	PruneStaleRefsOnStart      bool
+	MaxFunctionInvocations     int
+	FunctionMaxRetries         int
+	CredentialCacheTTL         time.Duration
+	CreateRateLimitRPM         int
+	CreateRateLimitBurst       int
+	UpstreamCheckInterval      time.Duration
+	MaxPackagesPerRepo         int
+	UploadMaxPartSizeBytes     int64
+	SkipSecretCheck            bool
+	VerifyObjectIntegrity      bool
+	// Clock is the time source of the time-dependent server components.
+	// Tests may inject a fake clock; defaults to the real clock.
+	Clock clock.Clock
 }
 
 // Config defines the config for the apiserver
@@ -80,6 +99,10 @@ type PorchServer struct {
 	GenericAPIServer *genericapiserver.GenericAPIServer
 	coreClient       client.WithWatch
 	cache            *cache.Cache
+	renderStaleness  *porch.RenderStalenessTracker
+	upstreamWatcher  *porch.UpstreamWatcher
+	upstreamInterval time.Duration
+	pipelineRuns     *porch.PipelineRunTracker
 }
 
 type completedConfig struct {
@@ -162,33 +185,72 @@ func (c completedConfig) New() (*PorchServer, error) {
 		return nil, fmt.Errorf("failed to build client for core apiserver: %w", err)
 	}
 
-	credentialResolver := porch.NewCredentialResolver(coreClient)
+	clk := c.ExtraConfig.Clock
+	if clk == nil {
+		clk = clock.RealClock
+	}
+
+	credentialResolver := porch.NewCachedCredentialProvider(porch.NewCredentialResolver(coreClient), c.ExtraConfig.CredentialCacheTTL, clk)
 	referenceResolver := porch.NewReferenceResolver(coreClient)
 	userInfoProvider := &porch.ApiserverUserInfoProvider{}
 
 	renderer := kpt.NewRenderer()
 
 	cache := cache.NewCache(c.ExtraConfig.CacheDirectory, cache.CacheOptions{
-		CredentialResolver: credentialResolver,
-		UserInfoProvider:   userInfoProvider,
+		CredentialResolver:         credentialResolver,
+		UserInfoProvider:           userInfoProvider,
+		CircuitBreakerResetTimeout: c.ExtraConfig.CircuitBreakerResetTimeout,
+		// Repositories are opened in the cache once per process, as the
+		// background watch reports the registered repositories on startup.
+		PruneStaleRefs:     c.ExtraConfig.PruneStaleRefsOnStart,
+		MaxPackagesPerRepo: c.ExtraConfig.MaxPackagesPerRepo,
+		VerifyIntegrity:    c.ExtraConfig.VerifyObjectIntegrity,
 	})
+	digestResolver := oci.NewImageDigestResolver()
 	cad, err := engine.NewCaDEngine(
 		engine.WithCache(cache),
 		// The order of registering the function runtimes matters here. When
 		// evaluating a function, the runtimes will be tried in the same
 		// order as they are registered.
 		engine.WithBuiltinFunctionRuntime(),
-		engine.WithGRPCFunctionRuntime(c.ExtraConfig.FunctionRunnerAddress),
+		engine.WithGRPCFunctionRuntime(c.ExtraConfig.FunctionRunnerAddress,
+			engine.NewLoggingInterceptor(),
+			engine.NewMetricsInterceptor(),
+			engine.NewRetryInterceptor(c.ExtraConfig.FunctionMaxRetries, engine.DefaultFunctionRetryBackoff)),
 		engine.WithCredentialResolver(credentialResolver),
 		engine.WithRenderer(renderer),
 		engine.WithReferenceResolver(referenceResolver),
 		engine.WithUserInfoProvider(userInfoProvider),
+		engine.WithMaxFunctionInvocations(c.ExtraConfig.MaxFunctionInvocations),
+		engine.WithFunctionDigestResolver(digestResolver),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	porchGroup, err := porch.NewRESTStorage(Scheme, Codecs, cad, coreClient)
+	renderStaleness := porch.NewRenderStalenessTracker(digestResolver, clk)
+	var upstreamWatcher *porch.UpstreamWatcher
+	if c.ExtraConfig.UpstreamCheckInterval > 0 {
+		upstreamWatcher = porch.NewUpstreamWatcher(porch.UpstreamRefResolverFunc(git.ResolveRemoteRefs), clk)
+	}
+	var secretChecker *porch.RequiredSecretChecker
+	if !c.ExtraConfig.SkipSecretCheck {
+		secretChecker = porch.NewRequiredSecretChecker(coreClient)
+	}
+	pipelineRuns := porch.NewPipelineRunTracker(coreClient, clk)
+	porchGroup, err := porch.NewRESTStorage(Scheme, Codecs, cad, coreClient, porch.RESTStorageOptions{
+		RenderStaleness:         renderStaleness,
+		CreateRateLimiter:       porch.NewCreateRateLimiter(c.ExtraConfig.CreateRateLimitRPM, c.ExtraConfig.CreateRateLimitBurst),
+		TransitionWebhooks:      porch.NewTransitionWebhookNotifier(coreClient, credentialResolver),
+		Notifications:           porch.NewNotificationDispatcher(coreClient, credentialResolver),
+		UpstreamWatcher:         upstreamWatcher,
+		FunctionConfigValidator: porch.NewFunctionConfigValidator(oci.NewConfigSchemaResolver()),
+		ConnectionTester:        porch.NewRepositoryConnectionTester(credentialResolver),
+		SecretChecker:           secretChecker,
+		PipelineRuns:            pipelineRuns,
+		UploadMaxPartSizeBytes:  c.ExtraConfig.UploadMaxPartSizeBytes,
+		Clock:                   clk,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +259,10 @@ func (c completedConfig) New() (*PorchServer, error) {
 		GenericAPIServer: genericServer,
 		coreClient:       coreClient,
 		cache:            cache,
+		renderStaleness:  renderStaleness,
+		upstreamWatcher:  upstreamWatcher,
+		upstreamInterval: c.ExtraConfig.UpstreamCheckInterval,
+		pipelineRuns:     pipelineRuns,
 	}
 
 	// Install the groups.
@@ -209,5 +275,10 @@ func (c completedConfig) New() (*PorchServer, error) {
 
 func (s *PorchServer) Run(ctx context.Context) error {
 	porch.RunBackground(ctx, s.coreClient, s.cache)
+	go s.renderStaleness.Run(ctx, porch.DefaultRenderStalenessPeriod)
+	go s.pipelineRuns.Run(ctx, porch.DefaultPipelineRunPollPeriod)
+	if s.upstreamWatcher != nil {
+		go s.upstreamWatcher.Run(ctx, s.upstreamInterval)
+	}
 	return s.GenericAPIServer.PrepareRun().Run(ctx.Done())
 }
@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -29,6 +31,9 @@ import (
 	sampleopenapi "github.com/GoogleContainerTools/kpt/porch/api/generated/openapi"
 	porchv1alpha1 "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/porch/apiserver/pkg/apiserver"
+	"github.com/GoogleContainerTools/kpt/porch/apiserver/pkg/registry/porch"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/git"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apiserver/pkg/admission"
@@ -51,6 +56,21 @@ type PorchServerOptions struct {
 	CoreAPIKubeconfigPath    string
 	FunctionRunnerAddress    string
 
+	CircuitBreakerResetTimeout time.Duration
+	PruneStaleRefsOnStart      bool
+	MaxFunctionInvocations     int
+	FunctionMaxRetries         int
+	CredentialCacheTTL         time.Duration
+	CreateRateLimitRPM         int
+	CreateRateLimitBurst       int
+	APIRateLimitRPM            int
+	APIRateLimitBurst          int
+	UpstreamCheckInterval      time.Duration
+	MaxPackagesPerRepo         int
+	UploadMaxPartSizeBytes     int64
+	SkipSecretCheck            bool
+	VerifyObjectIntegrity      bool
+
 	SharedInformerFactory informers.SharedInformerFactory
 	StdOut                io.Writer
 	StdErr                io.Writer
@@ -172,6 +192,12 @@ func (o *PorchServerOptions) Config() (*apiserver.Config, error) {
 	serverConfig.OpenAPIConfig.Info.Title = "Porch"
 	serverConfig.OpenAPIConfig.Info.Version = "0.1"
 
+	apiRateLimiter := porch.NewAPIRateLimiter(o.APIRateLimitRPM, o.APIRateLimitBurst)
+	serverConfig.BuildHandlerChainFunc = func(apiHandler http.Handler, c *genericapiserver.Config) http.Handler {
+		// Rate limit inside the default chain so that requests are authenticated.
+		return genericapiserver.DefaultBuildHandlerChain(apiRateLimiter.WithRateLimit(apiHandler), c)
+	}
+
 	if err := o.RecommendedOptions.ApplyTo(serverConfig); err != nil {
 		return nil, err
 	}
@@ -182,6 +208,19 @@ func (o *PorchServerOptions) Config() (*apiserver.Config, error) {
 			CoreAPIKubeconfigPath: o.CoreAPIKubeconfigPath,
 			CacheDirectory:        o.CacheDirectory,
 			FunctionRunnerAddress: o.FunctionRunnerAddress,
+
+			CircuitBreakerResetTimeout: o.CircuitBreakerResetTimeout,
+			PruneStaleRefsOnStart:      o.PruneStaleRefsOnStart,
+			MaxFunctionInvocations:     o.MaxFunctionInvocations,
+			FunctionMaxRetries:         o.FunctionMaxRetries,
+			CredentialCacheTTL:         o.CredentialCacheTTL,
+			CreateRateLimitRPM:         o.CreateRateLimitRPM,
+			CreateRateLimitBurst:       o.CreateRateLimitBurst,
+			UpstreamCheckInterval:      o.UpstreamCheckInterval,
+			MaxPackagesPerRepo:         o.MaxPackagesPerRepo,
+			UploadMaxPartSizeBytes:     o.UploadMaxPartSizeBytes,
+			SkipSecretCheck:            o.SkipSecretCheck,
+			VerifyObjectIntegrity:      o.VerifyObjectIntegrity,
 		},
 	}
 	return config, nil
@@ -226,4 +265,33 @@ func (o *PorchServerOptions) AddFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&o.FunctionRunnerAddress, "function-runner", "", "Address of the function runner gRPC service.")
 	fs.StringVar(&o.CacheDirectory, "cache-directory", "", "Directory where Porch server stores repository and package caches.")
+	fs.DurationVar(&o.CircuitBreakerResetTimeout, "circuit-breaker-reset-timeout", git.DefaultCircuitBreakerResetTimeout,
+		"Time after which a git repository circuit breaker, opened by repeated failures, allows a trial request.")
+	fs.BoolVar(&o.PruneStaleRefsOnStart, "prune-stale-refs-on-start", false,
+		"Delete draft and proposed branches that do not correspond to a package revision from registered git repositories on startup.")
+	fs.IntVar(&o.MaxFunctionInvocations, "max-function-invocations", 100,
+		"Maximum number of function invocations allowed while rendering a single package revision. Zero disables the limit.")
+	fs.IntVar(&o.FunctionMaxRetries, "function-max-retries", engine.DefaultFunctionMaxRetries,
+		"Maximum number of times a function evaluation is retried while the function runner is unavailable. Zero disables retries.")
+	fs.DurationVar(&o.CredentialCacheTTL, "credential-cache-ttl", porch.DefaultCredentialCacheTTL,
+		"How long repository credentials read from secrets are cached before the secret is read again. Zero disables caching.")
+	fs.IntVar(&o.CreateRateLimitRPM, "create-rate-limit-rpm", porch.DefaultCreateRateLimitRPM,
+		"Maximum number of package revisions a single user may create per minute. Zero disables the limit.")
+	fs.IntVar(&o.CreateRateLimitBurst, "create-rate-limit-burst", porch.DefaultCreateRateLimitBurst,
+		"Maximum number of package revisions a single user may create in a burst.")
+	fs.IntVar(&o.APIRateLimitRPM, "api-rate-limit-rpm", porch.DefaultAPIRateLimitRPM,
+		"Maximum number of API requests a single user agent may make per minute. Zero disables the limit.")
+	fs.IntVar(&o.APIRateLimitBurst, "api-rate-limit-burst", porch.DefaultAPIRateLimitBurst,
+		"Maximum number of API requests a single user agent may make in a burst.")
+	fs.DurationVar(&o.UpstreamCheckInterval, "upstream-check-interval", porch.DefaultUpstreamCheckInterval,
+		"How often the git upstreams of package revisions are checked for new commits. Zero disables the checks.")
+	fs.IntVar(&o.MaxPackagesPerRepo, "max-packages-per-repo", git.DefaultMaxPackagesPerRepo,
+		"Maximum number of directories the directory pattern of a git repository may match.")
+	fs.Int64Var(&o.UploadMaxPartSizeBytes, "upload-max-part-size-bytes", porch.DefaultUploadMaxPartSizeBytes,
+		"Maximum size of a single file uploaded to the packagerevisionresources/upload subresource. Zero disables the limit.")
+	fs.BoolVar(&o.SkipSecretCheck, "skip-secret-check", false,
+		"Do not check that the required secrets of a package revision exist in its target cluster when it is approved. "+
+			"Use in environments without access to the target clusters.")
+	fs.BoolVar(&o.VerifyObjectIntegrity, "verify-object-integrity", false,
+		"Verify the checksums of git objects read as package resources and fail reads of corrupted objects.")
 }
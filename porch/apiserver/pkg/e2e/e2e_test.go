@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
@@ -29,6 +30,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	coreapi "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +39,9 @@ import (
 
 const (
 	testBlueprintsRepo = "https://github.com/platkrm/test-blueprints.git"
+	// repositoryReadyTimeout bounds the wait for registered repositories to
+	// become ready.
+	repositoryReadyTimeout = 2 * time.Minute
 )
 
 func TestE2E(t *testing.T) {
@@ -52,8 +57,9 @@ func Run(suite interface{}, t *testing.T) {
 		var ts *TestSuite = sv.Elem().FieldByName("TestSuite").Addr().Interface().(*TestSuite)
 
 		ts.T = t
+		ts.WithTracing(DefaultTraceEndpoint)
 		if init, ok := suite.(Initializer); ok {
-			init.Initialize(ctx)
+			init.Initialize(ts.TraceTest(ctx))
 		}
 
 		for i, max := 0, st.NumMethod(); i < max; i++ {
@@ -61,7 +67,7 @@ func Run(suite interface{}, t *testing.T) {
 			if strings.HasPrefix(m.Name, "Test") {
 				t.Run(m.Name, func(t *testing.T) {
 					ts.T = t
-					m.Func.Call([]reflect.Value{sv, reflect.ValueOf(ctx)})
+					m.Func.Call([]reflect.Value{sv, reflect.ValueOf(ts.TraceTest(ctx))})
 				})
 			}
 		}
@@ -523,6 +529,173 @@ func (t *PorchSuite) TestUpdateResources(ctx context.Context) {
 	}
 }
 
+func (t *PorchSuite) TestRenderPolicyOnPropose(ctx context.Context) {
+	t.testRenderPolicy(ctx, "render-policy-on-propose", porchapi.RenderPolicyOnPropose, true, true)
+}
+
+func (t *PorchSuite) TestRenderPolicyOnApprove(ctx context.Context) {
+	t.testRenderPolicy(ctx, "render-policy-on-approve", porchapi.RenderPolicyOnApprove, false, true)
+}
+
+func (t *PorchSuite) TestRenderPolicyManual(ctx context.Context) {
+	t.testRenderPolicy(ctx, "render-policy-manual", porchapi.RenderPolicyManual, false, false)
+}
+
+// testRenderPolicy creates a package revision with the given render policy,
+// adds a mutator and a resource to its draft, and then proposes and approves
+// it, checking after the draft update and after approval whether the mutator
+// has been applied.
+func (t *PorchSuite) testRenderPolicy(ctx context.Context, repository string, policy porchapi.RenderPolicy, wantDraftRendered, wantPublishedRendered bool) {
+	const (
+		packageName = "render-policy-package"
+		revision    = "v1"
+	)
+	name := repository + ":" + packageName + ":" + revision
+
+	t.registerMainGitRepositoryF(ctx, repository)
+
+	t.CreateF(ctx, &porchapi.PackageRevision{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevision",
+			APIVersion: porchapi.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: t.namespace,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			PackageName:    packageName,
+			Revision:       revision,
+			RepositoryName: repository,
+			RenderPolicy:   policy,
+		},
+	})
+
+	var pkg porchapi.PackageRevision
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      name,
+	}, &pkg)
+	if got, want := pkg.Spec.RenderPolicy, policy; got != want {
+		t.Errorf("Package render policy: got %q, want %q", got, want)
+	}
+
+	var resources porchapi.PackageRevisionResources
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      name,
+	}, &resources)
+
+	kptfile := t.ParseKptfileF(&resources)
+	if kptfile.Pipeline == nil {
+		kptfile.Pipeline = &kptfilev1.Pipeline{}
+	}
+	kptfile.Pipeline.Mutators = append(kptfile.Pipeline.Mutators, kptfilev1.Function{
+		Image: "gcr.io/kpt-fn/set-annotations:v0.1.4",
+		ConfigMap: map[string]string{
+			"color": "red",
+		},
+		Name: "set-annotations",
+	})
+	t.SaveKptfileF(&resources, kptfile)
+
+	filename := filepath.Join("testdata", "update-resources", "add-config-map.yaml")
+	cm, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read ConfigMap from %q: %v", filename, err)
+	}
+	resources.Spec.Resources["config-map.yaml"] = string(cm)
+	t.UpdateF(ctx, &resources)
+
+	if got := t.configMapRendered(&resources); got != wantDraftRendered {
+		t.Errorf("Draft rendered with render policy %q: got %t, want %t", policy, got, wantDraftRendered)
+	}
+
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      name,
+	}, &pkg)
+	pkg.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	t.UpdateF(ctx, &pkg)
+
+	pkg.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
+	t.UpdateApprovalF(ctx, &pkg, metav1.UpdateOptions{})
+
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      name,
+	}, &resources)
+
+	if got := t.configMapRendered(&resources); got != wantPublishedRendered {
+		t.Errorf("Published package rendered with render policy %q: got %t, want %t", policy, got, wantPublishedRendered)
+	}
+}
+
+// configMapRendered returns true if the set-annotations mutator added by
+// testRenderPolicy has been applied to config-map.yaml.
+func (t *PorchSuite) configMapRendered(resources *porchapi.PackageRevisionResources) bool {
+	contents, ok := resources.Spec.Resources["config-map.yaml"]
+	if !ok {
+		t.Fatalf("Config map config-map.yaml not found")
+	}
+	node, err := yaml.Parse(contents)
+	if err != nil {
+		t.Fatalf("Failed to parse config-map.yaml: %v", err)
+	}
+	return node.GetAnnotations()["color"] == "red"
+}
+
+func (t *PorchSuite) TestRenderResourceSet(ctx context.Context) {
+	const (
+		repository  = "render-resource-set"
+		packageName = "multi-resource-package"
+		revision    = "v1"
+		name        = repository + ":" + packageName + ":" + revision
+	)
+
+	t.registerMainGitRepositoryF(ctx, repository)
+	t.createPackageDraftF(ctx, repository, packageName, revision)
+
+	var resources porchapi.PackageRevisionResources
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      name,
+	}, &resources)
+
+	kptfile := t.ParseKptfileF(&resources)
+	if kptfile.Pipeline == nil {
+		kptfile.Pipeline = &kptfilev1.Pipeline{}
+	}
+	kptfile.Pipeline.Mutators = append(kptfile.Pipeline.Mutators, kptfilev1.Function{
+		Image: "gcr.io/kpt-fn/set-annotations:v0.1.4",
+		ConfigMap: map[string]string{
+			"color": "red",
+		},
+	})
+	t.SaveKptfileF(&resources, kptfile)
+
+	input, err := ioutil.ReadFile(filepath.Join("testdata", "render-resource-set", "resources.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read resources: %v", err)
+	}
+	resources.Spec.Resources["resources.yaml"] = string(input)
+	t.UpdateF(ctx, &resources)
+
+	got, ok := resources.Spec.Resources["resources.yaml"]
+	if !ok {
+		t.Fatalf("Rendered resources.yaml not found")
+	}
+	want, err := ioutil.ReadFile(filepath.Join("testdata", "render-resource-set", "want-resources.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read expected resources: %v", err)
+	}
+
+	// The order of resources in the rendered file is determined by the function.
+	if diff := t.CompareResourceSets(got, string(want)); diff != "" {
+		t.Errorf("Unexpected rendered resources: (-want,+got): %s", diff)
+	}
+}
+
 func (t *PorchSuite) TestFunctionRepository(ctx context.Context) {
 	t.CreateF(ctx, &configapi.Repository{
 		ObjectMeta: metav1.ObjectMeta{
@@ -538,61 +711,491 @@ func (t *PorchSuite) TestFunctionRepository(ctx context.Context) {
 				Registry: "gcr.io/kpt-fn",
 			},
 		},
-	})
-
-	t.Cleanup(func() {
-		t.DeleteL(ctx, &configapi.Repository{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "function-repository",
-				Namespace: t.namespace,
-			},
-		})
-	})
-
-	list := &porchapi.FunctionList{}
-	t.ListE(ctx, list, client.InNamespace(t.namespace))
+	})
+
+	t.Cleanup(func() {
+		t.DeleteL(ctx, &configapi.Repository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "function-repository",
+				Namespace: t.namespace,
+			},
+		})
+	})
+
+	t.AssertRepositoryReady(ctx, "function-repository", repositoryReadyTimeout)
+
+	list := &porchapi.FunctionList{}
+	t.ListE(ctx, list, client.InNamespace(t.namespace))
+
+	if got := len(list.Items); got == 0 {
+		t.Errorf("Found no functions in gcr.io/kpt-fn repository; expected at least one")
+	}
+}
+
+func (t *PorchSuite) TestOciRepository(ctx context.Context) {
+	const (
+		repository  = "oci-repository"
+		packageName = "oci-package"
+		revision    = "v1"
+		name        = repository + ":" + packageName + ":" + revision
+	)
+
+	config := t.CreateOCIRegistry()
+
+	t.CreateF(ctx, &configapi.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      repository,
+			Namespace: t.namespace,
+		},
+		Spec: configapi.RepositorySpec{
+			Type:    configapi.RepositoryTypeOCI,
+			Content: configapi.RepositoryContentPackage,
+			Oci: &configapi.OciRepository{
+				Registry: config.Registry,
+			},
+		},
+	})
+
+	t.Cleanup(func() {
+		t.DeleteL(ctx, &configapi.Repository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      repository,
+				Namespace: t.namespace,
+			},
+		})
+	})
+
+	t.AssertRepositoryReady(ctx, repository, repositoryReadyTimeout)
+
+	t.createPackageDraftF(ctx, repository, packageName, revision)
+
+	var pr porchapi.PackageRevision
+	t.mustExist(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &pr)
+	if got, want := pr.Spec.Lifecycle, porchapi.PackageRevisionLifecycleDraft; got != want {
+		t.Errorf("Package revision %s has lifecycle %q; want %q", name, got, want)
+	}
+}
+
+func (t *PorchSuite) TestPublicGitRepository(ctx context.Context) {
+	t.registerGitRepositoryF(ctx, testBlueprintsRepo, "demo-blueprints")
+
+	if got := len(t.ListPackageRevisions(ctx, WithRepository("demo-blueprints"))); got == 0 {
+		t.Errorf("Found no package revisions in %s; expected at least one", testBlueprintsRepo)
+	}
+}
+
+func (t *PorchSuite) TestProposeApprove(ctx context.Context) {
+	const (
+		repository      = "lifecycle"
+		packageName     = "test-package"
+		packageRevision = "v1"
+		fullName        = repository + ":" + packageName + ":" + packageRevision
+	)
+
+	// Register the repository
+	t.registerMainGitRepositoryF(ctx, repository)
+
+	// Create a new package (via init)
+	t.CreateF(ctx, &porchapi.PackageRevision{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevision",
+			APIVersion: porchapi.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fullName,
+			Namespace: t.namespace,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			PackageName:    packageName,
+			Revision:       packageRevision,
+			RepositoryName: repository,
+			Tasks: []porchapi.Task{
+				{
+					Type: porchapi.TaskTypeInit,
+					Init: &porchapi.PackageInitTaskSpec{},
+				},
+			},
+		},
+	})
+
+	var pkg porchapi.PackageRevision
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      fullName,
+	}, &pkg)
+
+	// Propose the package revision to be finalized
+	pkg.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	t.UpdateF(ctx, &pkg)
+
+	var proposed porchapi.PackageRevision
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      fullName,
+	}, &proposed)
+
+	if got, want := proposed.Spec.Lifecycle, porchapi.PackageRevisionLifecycleProposed; got != want {
+		t.Fatalf("Proposed package lifecycle value: got %s, want %s", got, want)
+	}
+
+	// Approve using Update should fail.
+	proposed.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
+	if err := t.client.Update(ctx, &proposed); err == nil {
+		t.Fatalf("Finalization of a package via Update unexpectedly succeeded")
+	}
+
+	// Approve the package
+	proposed.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
+	approved := t.UpdateApprovalF(ctx, &proposed, metav1.UpdateOptions{})
+	if got, want := approved.Spec.Lifecycle, porchapi.PackageRevisionLifecyclePublished; got != want {
+		t.Fatalf("Approved package lifecycle value: got %s, want %s", got, want)
+	}
+}
+
+func (t *PorchSuite) TestProposeReject(ctx context.Context) {
+	const (
+		repository  = "rejection"
+		packageName = "test-package"
+		reason      = "The package fails the bucket policy"
+	)
+
+	t.registerMainGitRepositoryF(ctx, repository)
+	pr := t.createPackageDraftF(ctx, repository, packageName, "v1")
+	key := client.ObjectKeyFromObject(pr)
+
+	// Propose the package revision, then reject it.
+	t.GetF(ctx, key, pr)
+	pr.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	t.UpdateF(ctx, pr)
+
+	pr.Spec.Lifecycle = porchapi.PackageRevisionLifecycleRejected
+	pr.Status.RejectionReason = reason
+	rejected := t.RejectApprovalF(ctx, pr, metav1.UpdateOptions{})
+	if got, want := rejected.Spec.Lifecycle, porchapi.PackageRevisionLifecycleRejected; got != want {
+		t.Fatalf("Rejected package lifecycle value: got %s, want %s", got, want)
+	}
+
+	var stored porchapi.PackageRevision
+	t.GetF(ctx, key, &stored)
+	if got, want := stored.Spec.Lifecycle, porchapi.PackageRevisionLifecycleRejected; got != want {
+		t.Errorf("Stored package lifecycle value: got %s, want %s", got, want)
+	}
+	if got, want := stored.Status.RejectionReason, reason; got != want {
+		t.Errorf("Stored package rejection reason: got %q, want %q", got, want)
+	}
+
+	// A rejected package revision cannot be published or proposed again.
+	stored.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
+	if _, err := t.clientset.PorchV1alpha1().PackageRevisions(t.namespace).UpdateApproval(ctx, stored.Name, &stored, metav1.UpdateOptions{}); err == nil {
+		t.Fatalf("Approval of a rejected package unexpectedly succeeded")
+	}
+	stored.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	if err := t.client.Update(ctx, &stored); err == nil {
+		t.Fatalf("Proposal of a rejected package unexpectedly succeeded")
+	}
+
+	// After returning to draft, it can be proposed and published.
+	t.GetF(ctx, key, &stored)
+	stored.Spec.Lifecycle = porchapi.PackageRevisionLifecycleDraft
+	t.UpdateF(ctx, &stored)
+	if got := stored.Status.RejectionReason; got != "" {
+		t.Errorf("Redrafted package rejection reason: got %q, want none", got)
+	}
+
+	stored.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	t.UpdateF(ctx, &stored)
+	stored.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
+	approved := t.UpdateApprovalF(ctx, &stored, metav1.UpdateOptions{})
+	if got, want := approved.Spec.Lifecycle, porchapi.PackageRevisionLifecyclePublished; got != want {
+		t.Fatalf("Approved package lifecycle value: got %s, want %s", got, want)
+	}
+}
+
+func (t *PorchSuite) TestGitRemoteName(ctx context.Context) {
+	const (
+		repository      = "remote-name"
+		packageName     = "test-package"
+		packageRevision = "v1"
+		fullName        = repository + ":" + packageName + ":" + packageRevision
+	)
+
+	// Register the repository with non-default git remote name
+	t.registerMainGitRepositoryF(ctx, repository, withRemoteName("upstream"))
+
+	// Create a new package draft (pushes the draft branch)
+	t.createPackageDraftF(ctx, repository, packageName, packageRevision)
+
+	// Propose and approve the package (fetches main and pushes the final package)
+	var pkg porchapi.PackageRevision
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      fullName,
+	}, &pkg)
+
+	pkg.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	t.UpdateF(ctx, &pkg)
+
+	pkg.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
+	approved := t.UpdateApprovalF(ctx, &pkg, metav1.UpdateOptions{})
+	if got, want := approved.Spec.Lifecycle, porchapi.PackageRevisionLifecyclePublished; got != want {
+		t.Fatalf("Approved package lifecycle value: got %s, want %s", got, want)
+	}
+
+	var resources porchapi.PackageRevisionResources
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      fullName,
+	}, &resources)
+
+	if _, found := resources.Spec.Resources[kptfilev1.KptFileName]; !found {
+		t.Errorf("Published package %s has no Kptfile", fullName)
+	}
+}
+
+func (t *PorchSuite) TestReservedPackageName(ctx context.Context) {
+	const (
+		repository  = "reserved-name"
+		packageName = "kpt-test"
+		revision    = "v1"
+	)
+
+	t.registerMainGitRepositoryF(ctx, repository)
+
+	err := t.client.Create(ctx, &porchapi.PackageRevision{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevision",
+			APIVersion: porchapi.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      repository + ":" + packageName + ":" + revision,
+			Namespace: t.namespace,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			PackageName:    packageName,
+			Revision:       revision,
+			RepositoryName: repository,
+			Tasks: []porchapi.Task{
+				{
+					Type: porchapi.TaskTypeInit,
+					Init: &porchapi.PackageInitTaskSpec{},
+				},
+			},
+		},
+	})
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("Creating package with reserved name %q: got error %v, want 422 Invalid", packageName, err)
+	}
+}
+
+func (t *PorchSuite) TestMaxPackageSize(ctx context.Context) {
+	const (
+		repository  = "max-package-size"
+		packageName = "test-max-package-size"
+		revision    = "v1"
+		name        = repository + ":" + packageName + ":" + revision
+	)
+
+	// The Kptfile of an initialized package alone exceeds the limit.
+	t.registerMainGitRepositoryF(ctx, repository, withMaxPackageSize(16))
+
+	pr := &porchapi.PackageRevision{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevision",
+			APIVersion: porchapi.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: t.namespace,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			PackageName:    packageName,
+			Revision:       revision,
+			RepositoryName: repository,
+			Tasks: []porchapi.Task{
+				{
+					Type: porchapi.TaskTypeInit,
+					Init: &porchapi.PackageInitTaskSpec{},
+				},
+			},
+		},
+	}
+	err := t.client.Create(ctx, pr)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("Creating package exceeding the size limit: got error %v, want 422 Invalid", err)
+	}
+	if got, want := err.Error(), "exceeds the limit of 16 bytes"; !strings.Contains(got, want) {
+		t.Errorf("Creating package exceeding the size limit: got error %q, want it to contain %q", got, want)
+	}
+	t.mustNotExist(ctx, pr)
+}
+
+func (t *PorchSuite) TestMaxPackageSizeOnUpdate(ctx context.Context) {
+	const (
+		repository  = "max-package-size-update"
+		packageName = "test-max-package-size"
+		revision    = "v1"
+		name        = repository + ":" + packageName + ":" + revision
+	)
+
+	t.registerMainGitRepositoryF(ctx, repository, withMaxPackageSize(4096))
+	t.createPackageDraftF(ctx, repository, packageName, revision)
+
+	var resources porchapi.PackageRevisionResources
+	t.GetF(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &resources)
+
+	resources.Spec.Resources["large.txt"] = strings.Repeat("x", 8192)
+	err := t.client.Update(ctx, &resources)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("Updating package to exceed the size limit: got error %v, want 422 Invalid", err)
+	}
+	if got, want := err.Error(), "exceeds the limit of 4096 bytes"; !strings.Contains(got, want) {
+		t.Errorf("Updating package to exceed the size limit: got error %q, want it to contain %q", got, want)
+	}
+}
+
+func (t *PorchSuite) TestDryRun(ctx context.Context) {
+	const (
+		repository  = "dry-run"
+		packageName = "test-dry-run"
+	)
+
+	t.registerMainGitRepositoryF(ctx, repository)
+
+	// A dry-run create returns the package revision without creating it.
+	pr := &porchapi.PackageRevision{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevision",
+			APIVersion: porchapi.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      repository + ":" + packageName + ":v1",
+			Namespace: t.namespace,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			PackageName:    packageName,
+			Revision:       "v1",
+			RepositoryName: repository,
+			Tasks: []porchapi.Task{
+				{
+					Type: porchapi.TaskTypeInit,
+					Init: &porchapi.PackageInitTaskSpec{},
+				},
+			},
+		},
+	}
+	t.CreateF(ctx, pr, client.DryRunAll)
+	if got, want := pr.Annotations[porchapi.DryRunAnnotation], "true"; got != want {
+		t.Errorf("Dry-run create: got %s annotation %q, want %q", porchapi.DryRunAnnotation, got, want)
+	}
+	if got, want := pr.Spec.Lifecycle, porchapi.PackageRevisionLifecycleDraft; got != want {
+		t.Errorf("Dry-run create: got lifecycle %q, want %q", got, want)
+	}
+	t.mustNotExist(ctx, pr)
+
+	// A dry-run update returns the updated package revision without
+	// committing the update.
+	draft := t.createPackageDraftF(ctx, repository, packageName, "v2")
+	draft.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	t.UpdateF(ctx, draft, client.DryRunAll)
+	if got, want := draft.Annotations[porchapi.DryRunAnnotation], "true"; got != want {
+		t.Errorf("Dry-run update: got %s annotation %q, want %q", porchapi.DryRunAnnotation, got, want)
+	}
+	if got, want := draft.Spec.Lifecycle, porchapi.PackageRevisionLifecycleProposed; got != want {
+		t.Errorf("Dry-run update: got lifecycle %q, want %q", got, want)
+	}
 
-	if got := len(list.Items); got == 0 {
-		t.Errorf("Found no functions in gcr.io/kpt-fn repository; expected at least one")
+	var stored porchapi.PackageRevision
+	t.GetF(ctx, client.ObjectKeyFromObject(draft), &stored)
+	if got, want := stored.Spec.Lifecycle, porchapi.PackageRevisionLifecycleDraft; got != want {
+		t.Errorf("Package revision after dry-run update: got lifecycle %q, want %q", got, want)
+	}
+	if _, found := stored.Annotations[porchapi.DryRunAnnotation]; found {
+		t.Errorf("Package revision after dry-run update has the %s annotation", porchapi.DryRunAnnotation)
 	}
 }
 
-func (t *PorchSuite) TestPublicGitRepository(ctx context.Context) {
-	t.registerGitRepositoryF(ctx, testBlueprintsRepo, "demo-blueprints")
+func (t *PorchSuite) TestListFieldSelector(ctx context.Context) {
+	const (
+		repository  = "field-selector"
+		packageName = "test-field-selector"
+	)
+
+	t.registerMainGitRepositoryF(ctx, repository)
+
+	// Publish v1 and leave v2 as a draft.
+	published := t.createPackageDraftF(ctx, repository, packageName, "v1")
+	published.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	t.UpdateF(ctx, published)
+	published.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
+	t.UpdateApprovalF(ctx, published, metav1.UpdateOptions{})
+	draft := t.createPackageDraftF(ctx, repository, packageName, "v2")
 
 	var list porchapi.PackageRevisionList
-	t.ListE(ctx, &list, client.InNamespace(t.namespace))
+	t.ListE(ctx, &list, client.InNamespace(t.namespace), client.MatchingFields{
+		"spec.lifecycle": string(porchapi.PackageRevisionLifecyclePublished),
+	})
+	found := false
+	for _, pr := range list.Items {
+		if got, want := pr.Spec.Lifecycle, porchapi.PackageRevisionLifecyclePublished; got != want {
+			t.Errorf("Package revision %s: got lifecycle %q, want %q", pr.Name, got, want)
+		}
+		switch pr.Name {
+		case published.Name:
+			found = true
+		case draft.Name:
+			t.Errorf("Draft package revision %s listed with lifecycle field selector", pr.Name)
+		}
+	}
+	if !found {
+		t.Errorf("Published package revision %s not listed with lifecycle field selector", published.Name)
+	}
 
-	if got := len(list.Items); got == 0 {
-		t.Errorf("Found no package revisions in %s; expected at least one", testBlueprintsRepo)
+	// Selectors on different fields are combined.
+	t.ListE(ctx, &list, client.InNamespace(t.namespace), client.MatchingFields{
+		"spec.packageName": packageName,
+		"spec.lifecycle":   string(porchapi.PackageRevisionLifecycleDraft),
+	})
+	if got, want := len(list.Items), 1; got != want {
+		t.Fatalf("Listing draft revisions of %s: got %d package revisions, want %d", packageName, got, want)
+	}
+	if got, want := list.Items[0].Name, draft.Name; got != want {
+		t.Errorf("Listing draft revisions of %s: got %s, want %s", packageName, got, want)
+	}
+
+	// Unknown fields are rejected.
+	if err := t.client.List(ctx, &list, client.InNamespace(t.namespace), client.MatchingFields{
+		"spec.unknown": "value",
+	}); !apierrors.IsBadRequest(err) {
+		t.Errorf("Listing with unknown field selector: got error %v, want 400 Bad Request", err)
 	}
 }
 
-func (t *PorchSuite) TestProposeApprove(ctx context.Context) {
+func (t *PorchSuite) TestResolveConflict(ctx context.Context) {
 	const (
-		repository      = "lifecycle"
-		packageName     = "test-package"
-		packageRevision = "v1"
-		fullName        = repository + ":" + packageName + ":" + packageRevision
+		repository  = "resolve-conflict"
+		packageName = "test-resolve-conflict"
+		revision    = "v1"
+		name        = repository + ":" + packageName + ":" + revision
+		filename    = "configmap.yaml"
 	)
 
-	// Register the repository
 	t.registerMainGitRepositoryF(ctx, repository)
 
-	// Create a new package (via init)
+	// Conflicted files cannot be rendered, so render the package manually.
 	t.CreateF(ctx, &porchapi.PackageRevision{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PackageRevision",
 			APIVersion: porchapi.SchemeGroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fullName,
+			Name:      name,
 			Namespace: t.namespace,
 		},
 		Spec: porchapi.PackageRevisionSpec{
 			PackageName:    packageName,
-			Revision:       packageRevision,
+			Revision:       revision,
 			RepositoryName: repository,
+			RenderPolicy:   porchapi.RenderPolicyManual,
 			Tasks: []porchapi.Task{
 				{
 					Type: porchapi.TaskTypeInit,
@@ -602,37 +1205,154 @@ func (t *PorchSuite) TestProposeApprove(ctx context.Context) {
 		},
 	})
 
+	// Save a file as left by a three-way merge with conflicts.
+	var resources porchapi.PackageRevisionResources
+	t.GetF(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &resources)
+	resources.Spec.Resources[filename] = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  annotations:
+    kpt.dev/merge-conflict: "true"
+data:
+<<<<<<< local
+  value: local
+=======
+  value: upstream
+>>>>>>> upstream
+`
+	t.UpdateF(ctx, &resources)
+
 	var pkg porchapi.PackageRevision
-	t.GetF(ctx, client.ObjectKey{
-		Namespace: t.namespace,
-		Name:      fullName,
-	}, &pkg)
+	t.GetF(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &pkg)
+	if condition := meta.FindStatusCondition(pkg.Status.Conditions, "HasConflicts"); condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Errorf("HasConflicts condition of package revision with conflicts: got %v, want True", condition)
+	}
 
-	// Propose the package revision to be finalized
+	// Proposing the package revision is rejected while it has conflicts.
+	pkg.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	if err := t.client.Update(ctx, &pkg); !apierrors.IsInvalid(err) {
+		t.Fatalf("Proposing package revision with conflicts: got error %v, want 422 Invalid", err)
+	}
+
+	// Resolve the conflict.
+	resolution, err := t.clientset.PorchV1alpha1().PackageRevisionResources(t.namespace).ResolveConflict(ctx, name, &porchapi.ConflictResolution{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: t.namespace,
+		},
+		Spec: porchapi.ConflictResolutionSpec{
+			Filename: filename,
+			ResolvedContent: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  annotations:
+    kpt.dev/merge-conflict: "true"
+data:
+  value: upstream
+`,
+		},
+	}, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to resolve conflict of %s: %v", filename, err)
+	}
+	if got := resolution.Status.RemainingConflicts; len(got) != 0 {
+		t.Errorf("Remaining conflicts after resolving %s: got %v, want none", filename, got)
+	}
+
+	t.GetF(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &resources)
+	if strings.Contains(resources.Spec.Resources[filename], porchapi.MergeConflictAnnotation) {
+		t.Errorf("Resolved %s still has the %s annotation:\n%s", filename, porchapi.MergeConflictAnnotation, resources.Spec.Resources[filename])
+	}
+
+	t.GetF(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &pkg)
+	if condition := meta.FindStatusCondition(pkg.Status.Conditions, "HasConflicts"); condition != nil {
+		t.Errorf("HasConflicts condition after resolving all conflicts: got %v, want none", condition)
+	}
+
+	// Resolving a file without conflicts is rejected.
+	if _, err := t.clientset.PorchV1alpha1().PackageRevisionResources(t.namespace).ResolveConflict(ctx, name, &porchapi.ConflictResolution{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: t.namespace,
+		},
+		Spec: porchapi.ConflictResolutionSpec{
+			Filename:        filename,
+			ResolvedContent: resources.Spec.Resources[filename],
+		},
+	}, metav1.UpdateOptions{}); !apierrors.IsBadRequest(err) {
+		t.Errorf("Resolving file without conflicts: got error %v, want 400 Bad Request", err)
+	}
+
+	// The package revision can now be proposed.
 	pkg.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
 	t.UpdateF(ctx, &pkg)
+	t.GetF(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &pkg)
+	if got, want := pkg.Spec.Lifecycle, porchapi.PackageRevisionLifecycleProposed; got != want {
+		t.Errorf("Package revision lifecycle after resolving conflicts: got %s, want %s", got, want)
+	}
+}
 
-	var proposed porchapi.PackageRevision
-	t.GetF(ctx, client.ObjectKey{
-		Namespace: t.namespace,
-		Name:      fullName,
-	}, &proposed)
+func (t *PorchSuite) TestPackageRevisionDiff(ctx context.Context) {
+	const (
+		repository  = "diff"
+		packageName = "test-diff"
+		filename    = "config.yaml"
+	)
 
-	if got, want := proposed.Spec.Lifecycle, porchapi.PackageRevisionLifecycleProposed; got != want {
-		t.Fatalf("Proposed package lifecycle value: got %s, want %s", got, want)
+	t.registerMainGitRepositoryF(ctx, repository)
+
+	setConfig := func(name, value string) {
+		var resources porchapi.PackageRevisionResources
+		t.GetF(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &resources)
+		resources.Spec.Resources[filename] = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  key: " + value + "\n"
+		t.UpdateF(ctx, &resources)
 	}
 
-	// Approve using Update should fail.
-	proposed.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
-	if err := t.client.Update(ctx, &proposed); err == nil {
-		t.Fatalf("Finalization of a package via Update unexpectedly succeeded")
+	// Publish v1, then modify the config map in the v2 draft.
+	v1 := t.createPackageDraftF(ctx, repository, packageName, "v1")
+	setConfig(v1.Name, "value")
+	t.GetF(ctx, client.ObjectKeyFromObject(v1), v1)
+	v1.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+	t.UpdateF(ctx, v1)
+	v1.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
+	t.UpdateApprovalF(ctx, v1, metav1.UpdateOptions{})
+
+	v2 := t.createPackageDraftF(ctx, repository, packageName, "v2")
+	setConfig(v2.Name, "changed")
+
+	// The base defaults to the previous revision.
+	diff, err := t.clientset.PorchV1alpha1().PackageRevisions(t.namespace).Diff(ctx, v2.Name, "", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get diff of %s: %v", v2.Name, err)
+	}
+	if got, want := diff.Base, v1.Name; got != want {
+		t.Errorf("Diff base: got %q, want %q", got, want)
+	}
+	var found *porchapi.FileDiff
+	for i := range diff.Files {
+		if diff.Files[i].Name == filename {
+			found = &diff.Files[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("Diff of %s does not include %s: %v", v2.Name, filename, diff.Files)
+	}
+	if !strings.Contains(found.UnifiedDiff, "-  key: value\n") || !strings.Contains(found.UnifiedDiff, "+  key: changed\n") {
+		t.Errorf("Unified diff of %s does not show the change:\n%s", filename, found.UnifiedDiff)
+	}
+	if !strings.Contains(found.OldContent, "key: value") || !strings.Contains(found.NewContent, "key: changed") {
+		t.Errorf("Diff of %s: got old content %q and new content %q", filename, found.OldContent, found.NewContent)
 	}
 
-	// Approve the package
-	proposed.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
-	approved := t.UpdateApprovalF(ctx, &proposed, metav1.UpdateOptions{})
-	if got, want := approved.Spec.Lifecycle, porchapi.PackageRevisionLifecyclePublished; got != want {
-		t.Fatalf("Approved package lifecycle value: got %s, want %s", got, want)
+	// Comparing a package revision to itself reports no changes.
+	diff, err = t.clientset.PorchV1alpha1().PackageRevisions(t.namespace).Diff(ctx, v2.Name, v2.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get diff of %s with itself: %v", v2.Name, err)
+	}
+	if len(diff.Files) != 0 {
+		t.Errorf("Diff of %s with itself: got %d files, want none", v2.Name, len(diff.Files))
 	}
 }
 
@@ -725,13 +1445,20 @@ func (t *PorchSuite) TestDeleteFinal(ctx context.Context) {
 
 	t.mustExist(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &pkg)
 
-	// Delete the package
-	t.DeleteE(ctx, &porchapi.PackageRevision{
+	// Deleting a published package is rejected unless forced.
+	published := &porchapi.PackageRevision{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: t.namespace,
 			Name:      name,
 		},
-	})
+	}
+	if err := t.client.Delete(ctx, published); !apierrors.IsInvalid(err) {
+		t.Fatalf("Deleting published package: got error %v, want 422 Invalid", err)
+	}
+	t.mustExist(ctx, client.ObjectKey{Namespace: t.namespace, Name: name}, &pkg)
+
+	// Force the deletion
+	t.DeleteE(ctx, published, client.GracePeriodSeconds(0))
 
 	t.mustNotExist(ctx, &pkg)
 }
@@ -814,6 +1541,222 @@ func (t *PorchSuite) TestRegisterRepository(ctx context.Context) {
 	}
 }
 
+func (t *PorchSuite) TestRepositoryDefaultBranch(ctx context.Context) {
+	const repository = "default-branch"
+
+	// The test git server is registered without a branch, so porch detects
+	// its default branch.
+	t.registerMainGitRepositoryF(ctx, repository)
+	t.AssertRepositoryReady(ctx, repository, time.Minute)
+
+	var repo configapi.Repository
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      repository,
+	}, &repo)
+
+	if repo.Spec.Git.Branch != "" {
+		t.Skipf("Repository is registered with branch %q", repo.Spec.Git.Branch)
+	}
+	if got, want := repo.Status.DefaultBranch, "main"; got != want {
+		t.Errorf("Repo DefaultBranch: got %q, want %q", got, want)
+	}
+}
+
+func (t *PorchSuite) TestRepositoryWrongCredentials(ctx context.Context) {
+	const (
+		repository = "wrong-credentials"
+		secret     = repository + "-auth"
+	)
+
+	config := t.CreateLocalGitServerWithAuth("porch", "correct-password")
+
+	t.CreateF(ctx, &coreapi.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secret,
+			Namespace: t.namespace,
+		},
+		Data: map[string][]byte{
+			"username": []byte(config.Username),
+			"password": []byte("wrong-password"),
+		},
+		Type: coreapi.SecretTypeBasicAuth,
+	})
+	t.Cleanup(func() {
+		t.DeleteE(ctx, &coreapi.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secret,
+				Namespace: t.namespace,
+			},
+		})
+	})
+
+	t.CreateF(ctx, &configapi.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      repository,
+			Namespace: t.namespace,
+		},
+		Spec: configapi.RepositorySpec{
+			Type:    configapi.RepositoryTypeGit,
+			Content: configapi.RepositoryContentPackage,
+			Git: &configapi.GitRepository{
+				Repo:      config.Repo,
+				Directory: config.Directory,
+				SecretRef: configapi.SecretRef{
+					Name: secret,
+				},
+			},
+		},
+	})
+	t.Cleanup(func() {
+		t.DeleteE(ctx, &configapi.Repository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      repository,
+				Namespace: t.namespace,
+			},
+		})
+	})
+
+	var ready *metav1.Condition
+	t.WaitForConditionF(ctx, repositoryReadyTimeout, time.Second, func() (bool, error) {
+		var repo configapi.Repository
+		t.GetF(ctx, client.ObjectKey{Namespace: t.namespace, Name: repository}, &repo)
+		ready = meta.FindStatusCondition(repo.Status.Conditions, configapi.RepositoryConditionReady)
+		return ready != nil && ready.Status == metav1.ConditionFalse, nil
+	}, "waiting for repository %s to fail", repository)
+
+	if !strings.Contains(ready.Message, "authentication required") {
+		t.Errorf("Ready condition message: got %q, want an authentication error", ready.Message)
+	}
+}
+
+func (t *PorchSuite) TestPackageRevisionFromFixture(ctx context.Context) {
+	const repository = "fixture"
+
+	t.registerMainGitRepositoryF(ctx, repository)
+
+	pr := t.CreatePackageRevisionFromFixture(ctx, repository, filepath.Join("testdata", "fixtures", "basic-package"))
+	if got, want := pr.Spec.Lifecycle, porchapi.PackageRevisionLifecyclePublished; got != want {
+		t.Errorf("Package lifecycle: got %s, want %s", got, want)
+	}
+	if got, want := pr.Spec.PackageName, "basic-package"; got != want {
+		t.Errorf("Package name: got %q, want %q", got, want)
+	}
+
+	var resources porchapi.PackageRevisionResources
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      pr.Name,
+	}, &resources)
+	t.AssertHasResource(ctx, &resources, "Kptfile")
+	t.AssertHasResource(ctx, &resources, "config/config-map.yaml")
+}
+
+func (t *PorchSuite) TestSubTestIsolation(ctx context.Context) {
+	const (
+		repository  = "isolated"
+		packageName = "isolated-package"
+		name        = repository + ":" + packageName + ":v1"
+	)
+
+	// Both sub-tests create a package of the same name; each must only see
+	// its own.
+	for _, description := range []string{"first", "second"} {
+		description := description
+		t.SubTest(description, func(st *TestSuite) {
+			config := st.CreateGitRepo()
+			st.CreateF(ctx, &configapi.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      repository,
+					Namespace: st.namespace,
+				},
+				Spec: configapi.RepositorySpec{
+					Type:    configapi.RepositoryTypeGit,
+					Content: configapi.RepositoryContentPackage,
+					Git: &configapi.GitRepository{
+						Repo:      config.Repo,
+						Branch:    config.Branch,
+						Directory: config.Directory,
+					},
+				},
+			})
+			st.AssertRepositoryReady(ctx, repository, repositoryReadyTimeout)
+
+			st.CreateF(ctx, &porchapi.PackageRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: st.namespace,
+				},
+				Spec: porchapi.PackageRevisionSpec{
+					PackageName:    packageName,
+					Revision:       "v1",
+					RepositoryName: repository,
+					Tasks: []porchapi.Task{
+						{
+							Type: porchapi.TaskTypeInit,
+							Init: &porchapi.PackageInitTaskSpec{
+								Description: description,
+							},
+						},
+					},
+				},
+			})
+
+			st.AssertPackageRevisionCount(ctx, 1, WithPackageName(packageName))
+
+			var resources porchapi.PackageRevisionResources
+			st.GetF(ctx, client.ObjectKey{
+				Namespace: st.namespace,
+				Name:      name,
+			}, &resources)
+			if got, want := st.ParseKptfileF(&resources).Info.Description, description; got != want {
+				st.Errorf("Package description in sub-test %s: got %q, want %q", description, got, want)
+			}
+		})
+	}
+}
+
+func (t *PorchSuite) TestGitPush(ctx context.Context) {
+	const (
+		repository  = "git-push"
+		packageName = "pushed-package"
+	)
+	// Push the package directly to the git repository, bypassing porch.
+	t.GitPush(t.config, packageName, map[string]string{
+		"Kptfile": `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: pushed-package
+info:
+  description: Package pushed directly to git
+`,
+		"config/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: pushed-config
+data:
+  key: value
+`,
+	}, "Add pushed-package")
+
+	t.registerMainGitRepositoryF(ctx, repository)
+
+	revisions := t.ListPackageRevisions(ctx, WithRepository(repository), WithPackageName(packageName))
+	if len(revisions) == 0 {
+		t.Fatalf("Found no revisions of package %q pushed to the git repository", packageName)
+	}
+
+	var resources porchapi.PackageRevisionResources
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      revisions[0].Name,
+	}, &resources)
+
+	if _, found := resources.Spec.Resources["config/configmap.yaml"]; !found {
+		t.Errorf("Pushed file config/configmap.yaml not found in package %s", revisions[0].Name)
+	}
+}
+
 func (t *PorchSuite) TestBuiltinFunctionEvaluator(ctx context.Context) {
 	// Register the repository as 'git-fn'
 	t.registerMainGitRepositoryF(ctx, "git-builtin-fn")
@@ -1047,6 +1990,8 @@ func (t *PorchSuite) TestPodEvaluator(ctx context.Context) {
 }
 
 func (t *PorchSuite) registerGitRepositoryF(ctx context.Context, repo, name string) {
+	ctx = WithOperation(ctx, "RegisterRepository")
+
 	t.CreateF(ctx, &configapi.Repository{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Repository",
@@ -1075,11 +2020,15 @@ func (t *PorchSuite) registerGitRepositoryF(ctx context.Context, repo, name stri
 			},
 		})
 	})
+
+	t.AssertRepositoryReady(ctx, name, repositoryReadyTimeout)
 }
 
 type repositoryOption func(*configapi.Repository)
 
 func (t *PorchSuite) registerMainGitRepositoryF(ctx context.Context, name string, opts ...repositoryOption) {
+	ctx = WithOperation(ctx, "RegisterRepository")
+
 	config := t.config
 
 	var secret string
@@ -1151,6 +2100,8 @@ func (t *PorchSuite) registerMainGitRepositoryF(ctx context.Context, name string
 			},
 		})
 	})
+
+	t.AssertRepositoryReady(ctx, name, repositoryReadyTimeout)
 }
 
 func withDeployment() repositoryOption {
@@ -1171,14 +2122,28 @@ func withType(t configapi.RepositoryType) repositoryOption {
 	}
 }
 
+func withRemoteName(name string) repositoryOption {
+	return func(r *configapi.Repository) {
+		r.Spec.Git.RemoteName = name
+	}
+}
+
 func withContent(content configapi.RepositoryContent) repositoryOption {
 	return func(r *configapi.Repository) {
 		r.Spec.Content = content
 	}
 }
 
+func withMaxPackageSize(bytes int64) repositoryOption {
+	return func(r *configapi.Repository) {
+		r.Spec.MaxPackageSizeBytes = bytes
+	}
+}
+
 // Creates an empty package draft by initializing an empty package
 func (t *PorchSuite) createPackageDraftF(ctx context.Context, repository, name, revision string) *porchapi.PackageRevision {
+	ctx = WithOperation(ctx, "CreatePackageRevision")
+
 	fullName := fmt.Sprintf("%s:%s:%s", repository, name, revision)
 	pr := &porchapi.PackageRevision{
 		TypeMeta: metav1.TypeMeta{
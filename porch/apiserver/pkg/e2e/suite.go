@@ -18,11 +18,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -31,16 +36,29 @@ import (
 	internalpkg "github.com/GoogleContainerTools/kpt/internal/pkg"
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	porchtest "github.com/GoogleContainerTools/kpt/pkg/test/porch"
+	"github.com/GoogleContainerTools/kpt/pkg/test/porch/diffutil"
 	porchclient "github.com/GoogleContainerTools/kpt/porch/api/generated/clientset/versioned"
 	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
 	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock/fakeclock"
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/git"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
 	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/registry"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	coreapi "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -48,6 +66,7 @@ import (
 	aggregatorv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
@@ -57,6 +76,8 @@ const (
 	updateGoldenFiles   = "UPDATE_GOLDEN_FILES"
 )
 
+var parallelTests = flag.Bool("parallel-tests", false, "Run the sub-tests started by TestSuite.SubTest in parallel.")
+
 type GitConfig struct {
 	Repo      string   `json:"repo"`
 	Branch    string   `json:"branch"`
@@ -65,6 +86,13 @@ type GitConfig struct {
 	Password  Password `json:"password"`
 }
 
+// GitAuthConfig are the HTTP basic auth credentials required by a test git
+// server. Empty credentials disable authentication.
+type GitAuthConfig struct {
+	Username string
+	Password string
+}
+
 type OciConfig struct {
 	Registry string `json:"registry"`
 }
@@ -83,6 +111,11 @@ type TestSuite struct {
 
 	namespace string // K8s namespace for this test run
 	local     bool   // Tests running against local dev porch
+
+	clock clock.Clock // Time source of the test; see WithFakeClock
+
+	tracerProvider *sdktrace.TracerProvider // Exports traces of the tests; see WithTracing
+	traceUI        string                   // URL of the Jaeger UI showing the traces
 }
 
 type Initializer interface {
@@ -99,6 +132,7 @@ func (t *TestSuite) Initialize(ctx context.Context) {
 
 	t.Logf("Testing against server: %q", cfg.Host)
 	cfg.UserAgent = "Porch Test"
+	t.traceTransport(cfg)
 
 	scheme := createClientScheme(t.T)
 
@@ -119,6 +153,12 @@ func (t *TestSuite) Initialize(ctx context.Context) {
 
 	t.local = t.IsUsingDevPorch()
 
+	t.createNamespace(ctx)
+}
+
+// createNamespace creates a new namespace for the test and deletes it when the
+// test completes.
+func (t *TestSuite) createNamespace(ctx context.Context) {
 	namespace := fmt.Sprintf("porch-test-%d", time.Now().UnixMicro())
 	t.CreateF(ctx, &coreapi.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -141,6 +181,49 @@ func (t *TestSuite) Initialize(ctx context.Context) {
 	})
 }
 
+// SubTest runs f as a sub-test with a child test suite, which shares the
+// clients of the suite but has its own namespace, so that package revisions
+// of concurrent sub-tests don't interfere. The namespace is deleted when the
+// sub-test completes. With the --parallel-tests flag, the sub-test runs in
+// parallel with its siblings. SubTest reports whether f succeeded.
+func (t *TestSuite) SubTest(name string, f func(*TestSuite)) bool {
+	return t.Run(name, func(st *testing.T) {
+		child := *t
+		child.T = st
+		// Create the namespace before pausing for parallel execution, so
+		// that sibling sub-tests get distinct namespace names.
+		child.createNamespace(context.TODO())
+		if *parallelTests {
+			st.Parallel()
+		}
+		f(&child)
+	})
+}
+
+// WithFakeClock makes the test suite use a fake clock, set to initial, as its
+// time source, and returns the suite together with the clock. Time then only
+// moves when the test calls FakeClock.Advance.
+//
+// The clock stamps the commits made by GitPush. Tests which start porch
+// server components in-process inject it into them by passing it as
+// apiserver.ExtraConfig.Clock, or to the constructors of the credential
+// cache, render staleness tracker and upstream watcher. A porch server
+// running in a separate process keeps using the real clock.
+func (t *TestSuite) WithFakeClock(initial time.Time) (*TestSuite, *fakeclock.FakeClock) {
+	fake := fakeclock.NewFakeClock(initial)
+	t.clock = fake
+	return t, fake
+}
+
+// Clock returns the time source of the test suite; the real clock unless
+// WithFakeClock was called.
+func (t *TestSuite) Clock() clock.Clock {
+	if t.clock == nil {
+		return clock.RealClock
+	}
+	return t.clock
+}
+
 func (t *TestSuite) IsUsingDevPorch() bool {
 	porch := aggregatorv1.APIService{}
 	ctx := context.TODO()
@@ -159,112 +242,555 @@ func (t *TestSuite) IsUsingDevPorch() bool {
 func (t *TestSuite) CreateGitRepo() GitConfig {
 	if t.IsUsingDevPorch() {
 		// Create Git server on the local machine.
-		return createLocalGitServer(t.T)
+		return createLocalGitServer(t.T, GitAuthConfig{})
 	} else {
 		// Deploy Git server via k8s client.
 		return t.createInClusterGitServer()
 	}
 }
 
-type ErrorHandler func(format string, args ...interface{})
+// CreateOCIRegistry starts an OCI registry for the test, on the local machine
+// if the tests run against a local Porch server and in the test namespace
+// otherwise. The registry is stopped when the test completes.
+// CreateLocalGitServerWithAuth creates a git server on the local machine which
+// requires the given basic auth credentials. The returned config carries the
+// credentials. The local server is only reachable by local dev porch, so the
+// test is skipped otherwise.
+func (t *TestSuite) CreateLocalGitServerWithAuth(username, password string) GitConfig {
+	if !t.IsUsingDevPorch() {
+		t.Skip("Git server with authentication requires local dev porch")
+	}
+	return createLocalGitServer(t.T, GitAuthConfig{Username: username, Password: password})
+}
+
+func (t *TestSuite) CreateOCIRegistry() OciConfig {
+	if t.IsUsingDevPorch() {
+		return createLocalOciRegistry(t.T)
+	} else {
+		return t.createInClusterOciRegistry()
+	}
+}
+
+// GitPush commits the files to the package directory of the test git
+// repository, bypassing porch, and pushes the commit to the repository branch.
+// File paths are relative to packagePath.
+func (t *TestSuite) GitPush(cfg GitConfig, packagePath string, files map[string]string, commitMsg string) {
+	ctx := context.TODO()
+
+	var auth transport.AuthMethod
+	if cfg.Username != "" || cfg.Password != "" {
+		auth = &githttp.BasicAuth{
+			Username: cfg.Username,
+			Password: string(cfg.Password),
+		}
+	}
+
+	// Without a branch, clone the default branch of the repository.
+	var referenceName plumbing.ReferenceName
+	if cfg.Branch != "" {
+		referenceName = plumbing.NewBranchReferenceName(cfg.Branch)
+	}
+	repo, err := gogit.CloneContext(ctx, memory.NewStorage(), memfs.New(), &gogit.CloneOptions{
+		URL:           cfg.Repo,
+		Auth:          auth,
+		ReferenceName: referenceName,
+		SingleBranch:  true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to clone git repository %q: %v", cfg.Repo, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD of git repository %q: %v", cfg.Repo, err)
+	}
+	branch := head.Name().Short()
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to open worktree of git repository %q: %v", cfg.Repo, err)
+	}
+
+	dir := path.Join(strings.TrimPrefix(cfg.Directory, "/"), packagePath)
+	for name, contents := range files {
+		filePath := path.Join(dir, name)
+		if err := util.WriteFile(wt.Filesystem, filePath, []byte(contents), 0644); err != nil {
+			t.Fatalf("Failed to write %q: %v", filePath, err)
+		}
+		if _, err := wt.Add(filePath); err != nil {
+			t.Fatalf("Failed to add %q to git index: %v", filePath, err)
+		}
+	}
+
+	commit, err := wt.Commit(commitMsg, &gogit.CommitOptions{
+		Author: &object.Signature{
+			Name:  "Porch Test",
+			Email: "porch-test@kpt.dev",
+			When:  t.Clock().Now(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to commit to git repository %q: %v", cfg.Repo, err)
+	}
+
+	if err := repo.PushContext(ctx, &gogit.PushOptions{
+		Auth: auth,
+		RefSpecs: []gitconfig.RefSpec{
+			gitconfig.RefSpec(fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch)),
+		},
+	}); err != nil {
+		t.Fatalf("Failed to push commit %s to git repository %q: %v", commit, cfg.Repo, err)
+	}
+	t.Logf("Pushed commit %s (%q) to %s", commit, commitMsg, cfg.Repo)
+}
 
 func (t *TestSuite) get(ctx context.Context, key client.ObjectKey, obj client.Object, eh ErrorHandler) {
 	if err := t.client.Get(ctx, key, obj); err != nil {
-		eh("failed to get resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), key.Name, key.Namespace, err)
+		eh(ctx, "failed to get resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), key.Name, key.Namespace, err)
 	}
 }
 
 func (c *TestSuite) list(ctx context.Context, list client.ObjectList, opts []client.ListOption, eh ErrorHandler) {
 	if err := c.client.List(ctx, list, opts...); err != nil {
-		eh("failed to list resources %s %+v: %v", list.GetObjectKind().GroupVersionKind(), list, err)
+		eh(ctx, "failed to list resources %s %+v: %v", list.GetObjectKind().GroupVersionKind(), list, err)
 	}
 }
 
 func (c *TestSuite) create(ctx context.Context, obj client.Object, opts []client.CreateOption, eh ErrorHandler) {
 	if err := c.client.Create(ctx, obj, opts...); err != nil {
-		eh("failed to create resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+		eh(ctx, "failed to create resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
 	}
 }
 
 func (c *TestSuite) delete(ctx context.Context, obj client.Object, opts []client.DeleteOption, eh ErrorHandler) {
 	if err := c.client.Delete(ctx, obj, opts...); err != nil {
-		eh("failed to delete resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+		eh(ctx, "failed to delete resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
 	}
 }
 
 func (t *TestSuite) update(ctx context.Context, obj client.Object, opts []client.UpdateOption, eh ErrorHandler) {
 	if err := t.client.Update(ctx, obj, opts...); err != nil {
-		eh("failed to update resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+		eh(ctx, "failed to update resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
 	}
 }
 
 func (t *TestSuite) patch(ctx context.Context, obj client.Object, patch client.Patch, opts []client.PatchOption, eh ErrorHandler) {
 	if err := t.client.Patch(ctx, obj, patch, opts...); err != nil {
-		eh("failed to patch resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+		eh(ctx, "failed to patch resource %s %s/%s: %v", obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
 	}
 }
 
 func (t *TestSuite) updateApproval(ctx context.Context, obj *porchapi.PackageRevision, opts metav1.UpdateOptions, eh ErrorHandler) *porchapi.PackageRevision {
 	if res, err := t.clientset.PorchV1alpha1().PackageRevisions(obj.Namespace).UpdateApproval(ctx, obj.Name, obj, opts); err != nil {
-		eh("failed to update approval of %s/%s: %v", obj.Namespace, obj.Name, err)
+		eh(ctx, "failed to update approval of %s/%s: %v", obj.Namespace, obj.Name, err)
 		return nil
 	} else {
 		return res
 	}
 }
 
+func (t *TestSuite) rejectApproval(ctx context.Context, obj *porchapi.PackageRevision, opts metav1.UpdateOptions, eh ErrorHandler) *porchapi.PackageRevision {
+	res, err := t.clientset.PorchV1alpha1().PackageRevisions(obj.Namespace).RejectApproval(ctx, obj.Name, obj, opts)
+	if err != nil {
+		eh(ctx, "failed to reject %s/%s: %v", obj.Namespace, obj.Name, err)
+		return nil
+	}
+	return res
+}
+
 // deleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error
 
 func (t *TestSuite) GetE(ctx context.Context, key client.ObjectKey, obj client.Object) {
-	t.get(ctx, key, obj, ErrorHandler(t.Errorf))
+	t.get(ctx, key, obj, reportWith(t.Errorf))
 }
 
 func (t *TestSuite) GetF(ctx context.Context, key client.ObjectKey, obj client.Object) {
-	t.get(ctx, key, obj, ErrorHandler(t.Fatalf))
+	t.get(ctx, key, obj, reportWith(t.Fatalf))
 }
 
 func (t *TestSuite) ListE(ctx context.Context, list client.ObjectList, opts ...client.ListOption) {
-	t.list(ctx, list, opts, t.Errorf)
+	t.list(ctx, list, opts, reportWith(t.Errorf))
 }
 
 func (t *TestSuite) CreateF(ctx context.Context, obj client.Object, opts ...client.CreateOption) {
-	t.create(ctx, obj, opts, t.Fatalf)
+	t.create(ctx, obj, opts, reportWith(t.Fatalf))
 }
 
 func (t *TestSuite) CreateE(ctx context.Context, obj client.Object, opts ...client.CreateOption) {
-	t.create(ctx, obj, opts, t.Errorf)
+	t.create(ctx, obj, opts, reportWith(t.Errorf))
 }
 
 func (t *TestSuite) DeleteF(ctx context.Context, obj client.Object, opts ...client.DeleteOption) {
-	t.delete(ctx, obj, opts, t.Fatalf)
+	t.delete(ctx, obj, opts, reportWith(t.Fatalf))
 }
 
 func (t *TestSuite) DeleteE(ctx context.Context, obj client.Object, opts ...client.DeleteOption) {
-	t.delete(ctx, obj, opts, t.Errorf)
+	t.delete(ctx, obj, opts, reportWith(t.Errorf))
 }
 
 func (t *TestSuite) DeleteL(ctx context.Context, obj client.Object, opts ...client.DeleteOption) {
-	t.delete(ctx, obj, opts, t.Logf)
+	t.delete(ctx, obj, opts, reportWith(t.Logf))
 }
 
 func (t *TestSuite) UpdateF(ctx context.Context, obj client.Object, opts ...client.UpdateOption) {
-	t.update(ctx, obj, opts, t.Fatalf)
+	t.update(ctx, obj, opts, reportWith(t.Fatalf))
 }
 
 func (t *TestSuite) UpdateE(ctx context.Context, obj client.Object, opts ...client.UpdateOption) {
-	t.update(ctx, obj, opts, t.Errorf)
+	t.update(ctx, obj, opts, reportWith(t.Errorf))
 }
 
 func (t *TestSuite) PatchF(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) {
-	t.patch(ctx, obj, patch, opts, t.Fatalf)
+	t.patch(ctx, obj, patch, opts, reportWith(t.Fatalf))
 }
 
 func (t *TestSuite) PatchE(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) {
-	t.patch(ctx, obj, patch, opts, t.Errorf)
+	t.patch(ctx, obj, patch, opts, reportWith(t.Errorf))
 }
 
 func (t *TestSuite) UpdateApprovalF(ctx context.Context, pr *porchapi.PackageRevision, opts metav1.UpdateOptions) *porchapi.PackageRevision {
-	return t.updateApproval(ctx, pr, opts, t.Fatalf)
+	return t.updateApproval(WithOperation(ctx, "UpdateApproval"), pr, opts, reportWith(t.Fatalf))
+}
+
+func (t *TestSuite) RejectApprovalF(ctx context.Context, pr *porchapi.PackageRevision, opts metav1.UpdateOptions) *porchapi.PackageRevision {
+	return t.rejectApproval(WithOperation(ctx, "RejectApproval"), pr, opts, reportWith(t.Fatalf))
+}
+
+const (
+	// conflictRetries is the number of times RetryOnConflict retries an
+	// update which failed with a conflict.
+	conflictRetries = 5
+	// conflictRetryBackoff is the delay before the first retry; it doubles
+	// with each retry.
+	conflictRetryBackoff = 100 * time.Millisecond
+)
+
+// RetryOnConflict calls fn, and calls it again while it fails with a Conflict
+// error, up to 5 more times with exponential backoff. It returns the last
+// error of fn. The update in fn conflicts because the object changed since it
+// was read, so fn must get the object again before each update attempt.
+func (t *TestSuite) RetryOnConflict(ctx context.Context, fn func() error) error {
+	return retryOnConflict(ctx, fn, conflictRetries, conflictRetryBackoff)
+}
+
+func (t *TestSuite) RetryOnConflictE(ctx context.Context, fn func() error) {
+	if err := t.RetryOnConflict(ctx, fn); err != nil {
+		t.Errorf("update failed: %v", err)
+	}
+}
+
+func (t *TestSuite) RetryOnConflictF(ctx context.Context, fn func() error) {
+	if err := t.RetryOnConflict(ctx, fn); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+}
+
+func retryOnConflict(ctx context.Context, fn func() error, retries int, backoff time.Duration) error {
+	err := fn()
+	for i := 0; i < retries && apierrors.IsConflict(err); i++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+		err = fn()
+	}
+	return err
+}
+
+// CreatePackageRevisionFromFixture creates a published package revision in
+// the repository with the files of the fixture directory. The package is named
+// after the directory and its revision is v1.
+func (t *TestSuite) CreatePackageRevisionFromFixture(ctx context.Context, repoName, fixturePath string) *porchapi.PackageRevision {
+	pr := t.CreateDraftPackageRevisionFromFixture(ctx, repoName, fixturePath)
+	key := client.ObjectKeyFromObject(pr)
+
+	t.RetryOnConflictF(ctx, func() error {
+		if err := t.client.Get(ctx, key, pr); err != nil {
+			return err
+		}
+		pr.Spec.Lifecycle = porchapi.PackageRevisionLifecycleProposed
+		return t.client.Update(ctx, pr)
+	})
+
+	var approved *porchapi.PackageRevision
+	t.RetryOnConflictF(ctx, func() error {
+		if err := t.client.Get(ctx, key, pr); err != nil {
+			return err
+		}
+		pr.Spec.Lifecycle = porchapi.PackageRevisionLifecyclePublished
+		var err error
+		approved, err = t.clientset.PorchV1alpha1().PackageRevisions(pr.Namespace).UpdateApproval(ctx, pr.Name, pr, metav1.UpdateOptions{})
+		return err
+	})
+	return approved
+}
+
+// CreateDraftPackageRevisionFromFixture creates a draft package revision in
+// the repository with the files of the fixture directory, and leaves it in
+// the Draft lifecycle. The package is named after the directory and its
+// revision is v1.
+func (t *TestSuite) CreateDraftPackageRevisionFromFixture(ctx context.Context, repoName, fixturePath string) *porchapi.PackageRevision {
+	const revision = "v1"
+	packageName := filepath.Base(fixturePath)
+	key := client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      repoName + ":" + packageName + ":" + revision,
+	}
+
+	files := readFixture(t.T, fixturePath)
+
+	t.CreateF(ctx, &porchapi.PackageRevision{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevision",
+			APIVersion: porchapi.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			PackageName:    packageName,
+			Revision:       revision,
+			RepositoryName: repoName,
+		},
+	})
+
+	t.RetryOnConflictF(ctx, func() error {
+		var resources porchapi.PackageRevisionResources
+		if err := t.client.Get(ctx, key, &resources); err != nil {
+			return err
+		}
+		if resources.Spec.Resources == nil {
+			resources.Spec.Resources = map[string]string{}
+		}
+		for name, contents := range files {
+			resources.Spec.Resources[name] = contents
+		}
+		return t.client.Update(ctx, &resources)
+	})
+
+	var pr porchapi.PackageRevision
+	t.GetF(ctx, key, &pr)
+	return &pr
+}
+
+// readFixture returns the contents of the files in the fixture directory,
+// keyed by their slash-separated paths relative to the directory.
+func readFixture(t *testing.T, fixturePath string) map[string]string {
+	files := map[string]string{}
+	if err := filepath.WalkDir(fixturePath, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		contents, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(fixturePath, file)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(contents)
+		return nil
+	}); err != nil {
+		t.Fatalf("Failed to read fixture %q: %v", fixturePath, err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("Fixture %q has no files", fixturePath)
+	}
+	return files
+}
+
+// ListOption filters package revisions returned by ListPackageRevisions.
+type ListOption func(pr *porchapi.PackageRevision) bool
+
+// WithLifecycle selects package revisions with the given lifecycle.
+func WithLifecycle(lifecycle porchapi.PackageRevisionLifecycle) ListOption {
+	return func(pr *porchapi.PackageRevision) bool {
+		return pr.Spec.Lifecycle == lifecycle
+	}
+}
+
+// WithPackageName selects revisions of the given package.
+func WithPackageName(name string) ListOption {
+	return func(pr *porchapi.PackageRevision) bool {
+		return pr.Spec.PackageName == name
+	}
+}
+
+// WithRepository selects package revisions in the given repository.
+func WithRepository(repository string) ListOption {
+	return func(pr *porchapi.PackageRevision) bool {
+		return pr.Spec.RepositoryName == repository
+	}
+}
+
+// ListPackageRevisions lists package revisions in the test namespace which
+// match all of the options.
+func (t *TestSuite) ListPackageRevisions(ctx context.Context, opts ...ListOption) []*porchapi.PackageRevision {
+	var list porchapi.PackageRevisionList
+	t.list(ctx, &list, []client.ListOption{client.InNamespace(t.namespace)}, reportWith(t.Fatalf))
+
+	var result []*porchapi.PackageRevision
+nextRevision:
+	for i := range list.Items {
+		pr := &list.Items[i]
+		for _, match := range opts {
+			if !match(pr) {
+				continue nextRevision
+			}
+		}
+		result = append(result, pr)
+	}
+	return result
+}
+
+// AssertPackageRevisionCount asserts the number of package revisions in the
+// test namespace which match all of the options.
+func (t *TestSuite) AssertPackageRevisionCount(ctx context.Context, expected int, opts ...ListOption) {
+	revisions := t.ListPackageRevisions(ctx, opts...)
+	if got := len(revisions); got != expected {
+		names := make([]string, 0, len(revisions))
+		for _, pr := range revisions {
+			names = append(names, pr.Name)
+		}
+		t.Errorf("Found %d package revisions, want %d:\n%s", got, expected, strings.Join(names, "\n"))
+	}
+}
+
+// AssertRepositoryReady waits until the Ready condition of the repository in
+// the test namespace is True for its current generation, and fails the test
+// if that does not happen within the timeout.
+func (t *TestSuite) AssertRepositoryReady(ctx context.Context, name string, timeout time.Duration) {
+	key := client.ObjectKey{Namespace: t.namespace, Name: name}
+	giveUp := time.Now().Add(timeout)
+
+	for {
+		var repository configapi.Repository
+		t.GetF(ctx, key, &repository)
+
+		ready := meta.FindStatusCondition(repository.Status.Conditions, configapi.RepositoryConditionReady)
+		if ready != nil && ready.Status == metav1.ConditionTrue && ready.ObservedGeneration == repository.Generation {
+			return
+		}
+
+		if time.Now().After(giveUp) {
+			t.Fatalf("Repository %s is not ready after %v; Ready condition: %+v", name, timeout, ready)
+			return
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// WaitForCondition polls condition every interval until it returns true and
+// returns true. If condition returns an error, or does not return true within
+// the timeout, WaitForCondition reports the error with t.Errorf and returns
+// false. The optional msgAndArgs are a format string and its arguments
+// describing the condition in the error.
+func (t *TestSuite) WaitForCondition(ctx context.Context, timeout, interval time.Duration, condition func() (bool, error), msgAndArgs ...interface{}) bool {
+	return t.waitForCondition(ctx, timeout, interval, condition, reportWith(t.Errorf), msgAndArgs)
+}
+
+func (t *TestSuite) WaitForConditionE(ctx context.Context, timeout, interval time.Duration, condition func() (bool, error), msgAndArgs ...interface{}) bool {
+	return t.waitForCondition(ctx, timeout, interval, condition, reportWith(t.Errorf), msgAndArgs)
+}
+
+func (t *TestSuite) WaitForConditionF(ctx context.Context, timeout, interval time.Duration, condition func() (bool, error), msgAndArgs ...interface{}) bool {
+	return t.waitForCondition(ctx, timeout, interval, condition, reportWith(t.Fatalf), msgAndArgs)
+}
+
+func (t *TestSuite) waitForCondition(ctx context.Context, timeout, interval time.Duration, condition func() (bool, error), eh ErrorHandler, msgAndArgs []interface{}) bool {
+	giveUp := time.Now().Add(timeout)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := condition()
+		if err != nil {
+			eh(ctx, "%s: %v", conditionMessage(msgAndArgs), err)
+			return false
+		}
+		if done {
+			return true
+		}
+
+		if time.Now().After(giveUp) {
+			eh(ctx, "%s: condition not met within %v", conditionMessage(msgAndArgs), timeout)
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			eh(ctx, "%s: %v", conditionMessage(msgAndArgs), ctx.Err())
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// conditionMessage formats the message describing a condition passed to
+// WaitForCondition.
+func conditionMessage(msgAndArgs []interface{}) string {
+	if len(msgAndArgs) == 0 {
+		return "waiting for condition"
+	}
+	format, ok := msgAndArgs[0].(string)
+	if !ok {
+		return fmt.Sprint(msgAndArgs...)
+	}
+	return fmt.Sprintf(format, msgAndArgs[1:]...)
+}
+
+// packageRevisionPollInterval is the interval at which
+// WaitForPackageRevisionLifecycle and WaitForPackageRevisionCondition poll.
+const packageRevisionPollInterval = 2 * time.Second
+
+// WaitForPackageRevisionLifecycle waits until the package revision reaches
+// the lifecycle and returns it. The test fails with t.Fatalf if that does not
+// happen within the timeout.
+func (t *TestSuite) WaitForPackageRevisionLifecycle(ctx context.Context, key client.ObjectKey, lifecycle porchapi.PackageRevisionLifecycle, timeout time.Duration) *porchapi.PackageRevision {
+	return t.waitForPackageRevision(ctx, key, func(pr *porchapi.PackageRevision) bool {
+		return pr.Spec.Lifecycle == lifecycle
+	}, timeout, packageRevisionPollInterval, reportWith(t.Fatalf), fmt.Sprintf("package revision %s to reach lifecycle %s", key, lifecycle))
+}
+
+// WaitForPackageRevisionCondition waits until condition returns true for the
+// package revision and returns it. The test fails with t.Fatalf if that does
+// not happen within the timeout.
+func (t *TestSuite) WaitForPackageRevisionCondition(ctx context.Context, key client.ObjectKey, condition func(pr *porchapi.PackageRevision) bool, timeout time.Duration) *porchapi.PackageRevision {
+	return t.waitForPackageRevision(ctx, key, condition, timeout, packageRevisionPollInterval, reportWith(t.Fatalf), fmt.Sprintf("package revision %s to satisfy the condition", key))
+}
+
+func (t *TestSuite) waitForPackageRevision(ctx context.Context, key client.ObjectKey, condition func(pr *porchapi.PackageRevision) bool, timeout, interval time.Duration, eh ErrorHandler, description string) *porchapi.PackageRevision {
+	ctx = WithOperation(ctx, "WaitForPackageRevision")
+
+	// last is the most recently observed state of the package revision,
+	// reported if the wait fails.
+	var last *porchapi.PackageRevision
+	report := func(ctx context.Context, format string, args ...interface{}) {
+		observed := "not found"
+		if last != nil {
+			observed = fmt.Sprintf("lifecycle %s", last.Spec.Lifecycle)
+		}
+		eh(ctx, format+" (last observed: %s)", append(args, observed)...)
+	}
+
+	if !t.waitForCondition(ctx, timeout, interval, func() (bool, error) {
+		pr, err := t.clientset.PorchV1alpha1().PackageRevisions(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			last = nil
+			return false, nil
+		case err != nil:
+			return false, err
+		}
+		last = pr
+		return condition(pr), nil
+	}, report, []interface{}{"waiting for %s", description}) {
+		return nil
+	}
+	return last
 }
 
 // DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error
@@ -286,7 +812,7 @@ func createClientScheme(t *testing.T) *runtime.Scheme {
 	return scheme
 }
 
-func createLocalGitServer(t *testing.T) GitConfig {
+func createLocalGitServer(t *testing.T, auth GitAuthConfig) GitConfig {
 	tmp, err := os.MkdirTemp("", "porch-test-*")
 	if err != nil {
 		t.Fatalf("Failed to create temp directory for Git repository: %v", err)
@@ -308,7 +834,11 @@ func createLocalGitServer(t *testing.T) GitConfig {
 
 	createInitialCommit(t, repo)
 
-	server, err := git.NewGitServer(repo)
+	var opts []git.GitServerOption
+	if auth.Username != "" || auth.Password != "" {
+		opts = append(opts, git.WithBasicAuth(auth.Username, auth.Password))
+	}
+	server, err := git.NewGitServer(repo, opts...)
 	if err != nil {
 		t.Fatalf("Failed to start git server: %v", err)
 		return GitConfig{}
@@ -345,8 +875,43 @@ func createLocalGitServer(t *testing.T) GitConfig {
 
 	return GitConfig{
 		Repo:      fmt.Sprintf("http://%s", address),
-		Branch:    "main",
 		Directory: "/",
+		Username:  auth.Username,
+		Password:  Password(auth.Password),
+	}
+}
+
+func createLocalOciRegistry(t *testing.T) OciConfig {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen for OCI registry: %v", err)
+		return OciConfig{}
+	}
+
+	server := &http.Server{
+		Handler:           registry.New(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		if err := server.Close(); err != nil {
+			t.Errorf("Failed to stop OCI registry: %v", err)
+		}
+		wg.Wait()
+	})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			t.Errorf("OCI registry exited with error: %v", err)
+		}
+	}()
+
+	// Registries on loopback addresses are accessed over plain HTTP.
+	return OciConfig{
+		Registry: fmt.Sprintf("%s/porch-test", lis.Addr()),
 	}
 }
 
@@ -497,60 +1062,168 @@ func (t *TestSuite) createInClusterGitServer() GitConfig {
 
 	t.Logf("Waiting for git-server to start ...")
 
-	// Wait a minute for git server to start up.
-	giveUp := time.Now().Add(time.Minute)
-
-	for {
-		time.Sleep(5 * time.Second)
-
-		var server appsv1.Deployment
-		t.GetF(ctx, client.ObjectKey{
+	var server appsv1.Deployment
+	if !t.WaitForConditionF(ctx, time.Minute, 5*time.Second, func() (bool, error) {
+		err := t.client.Get(ctx, client.ObjectKey{
 			Namespace: t.namespace,
 			Name:      "git-server",
 		}, &server)
-		if server.Status.AvailableReplicas > 0 {
-			t.Logf("git server is up")
-			break
-		}
-
-		if time.Now().After(giveUp) {
-			t.Fatalf("git server failed to start: %s", &server)
-			return GitConfig{}
-		}
+		return err == nil && server.Status.AvailableReplicas > 0, err
+	}, "git server failed to start: %s", &server) {
+		return GitConfig{}
 	}
+	t.Logf("git server is up")
 
 	t.Logf("Waiting for git-server-service to be ready ...")
 
-	// Check the Endpoint resource for readiness
-	giveUp = time.Now().Add(time.Minute)
-
-	for {
-		time.Sleep(5 * time.Second)
-
-		var endpoint coreapi.Endpoints
+	// Check the Endpoint resource for readiness. The endpoint may not exist
+	// until the service is ready.
+	var endpoint coreapi.Endpoints
+	if !t.WaitForConditionF(ctx, time.Minute, 5*time.Second, func() (bool, error) {
 		err := t.client.Get(ctx, client.ObjectKey{
 			Namespace: t.namespace,
 			Name:      "git-server-service",
 		}, &endpoint)
-
-		if err == nil && endpointIsReady(&endpoint) {
-			t.Logf("git-server-service is ready")
-			break
-		}
-
-		if time.Now().After(giveUp) {
-			t.Fatalf("git-server-service not ready on time: %s", &endpoint)
-			return GitConfig{}
-		}
+		return err == nil && endpointIsReady(&endpoint), nil
+	}, "git-server-service not ready on time: %s", &endpoint) {
+		return GitConfig{}
 	}
+	t.Logf("git-server-service is ready")
 
 	return GitConfig{
 		Repo:      fmt.Sprintf("http://git-server-service.%s.svc.cluster.local:8080", t.namespace),
-		Branch:    "main",
 		Directory: "/",
 	}
 }
 
+func (t *TestSuite) createInClusterOciRegistry() OciConfig {
+	ctx := context.TODO()
+
+	var replicas int32 = 1
+	var selector = strings.ReplaceAll(t.Name(), "/", "_")
+
+	t.CreateF(ctx, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "oci-registry",
+			Namespace: t.namespace,
+			Annotations: map[string]string{
+				"kpt.dev/porch-test": t.Name(),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"oci-registry": selector,
+				},
+			},
+			Template: coreapi.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"oci-registry": selector,
+					},
+				},
+				Spec: coreapi.PodSpec{
+					Containers: []coreapi.Container{
+						{
+							Name:  "registry",
+							Image: "registry:2",
+							Ports: []coreapi.ContainerPort{
+								{
+									ContainerPort: 5000,
+									Protocol:      coreapi.ProtocolTCP,
+								},
+							},
+							ImagePullPolicy: coreapi.PullIfNotPresent,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	t.Cleanup(func() {
+		t.DeleteE(ctx, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "oci-registry",
+				Namespace: t.namespace,
+			},
+		})
+	})
+
+	t.CreateF(ctx, &coreapi.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "oci-registry-service",
+			Namespace: t.namespace,
+			Annotations: map[string]string{
+				"kpt.dev/porch-test": t.Name(),
+			},
+		},
+		Spec: coreapi.ServiceSpec{
+			Ports: []coreapi.ServicePort{
+				{
+					Protocol: coreapi.ProtocolTCP,
+					Port:     5000,
+					TargetPort: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: 5000,
+					},
+				},
+			},
+			Selector: map[string]string{
+				"oci-registry": selector,
+			},
+		},
+	})
+
+	t.Cleanup(func() {
+		t.DeleteE(ctx, &coreapi.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "oci-registry-service",
+				Namespace: t.namespace,
+			},
+		})
+	})
+
+	t.Logf("Waiting for oci-registry to start ...")
+
+	var server appsv1.Deployment
+	if !t.WaitForConditionF(ctx, time.Minute, 5*time.Second, func() (bool, error) {
+		err := t.client.Get(ctx, client.ObjectKey{
+			Namespace: t.namespace,
+			Name:      "oci-registry",
+		}, &server)
+		return err == nil && server.Status.AvailableReplicas > 0, err
+	}, "oci registry failed to start: %s", &server) {
+		return OciConfig{}
+	}
+
+	var endpoint coreapi.Endpoints
+	if !t.WaitForConditionF(ctx, time.Minute, 5*time.Second, func() (bool, error) {
+		err := t.client.Get(ctx, client.ObjectKey{
+			Namespace: t.namespace,
+			Name:      "oci-registry-service",
+		}, &endpoint)
+		return err == nil && endpointIsReady(&endpoint), nil
+	}, "oci-registry-service not ready on time: %s", &endpoint) {
+		return OciConfig{}
+	}
+	t.Logf("oci-registry-service is ready")
+
+	// Address the registry by its cluster IP: registries on private
+	// addresses are accessed over plain HTTP, while the service DNS name
+	// would require TLS.
+	var service coreapi.Service
+	t.GetF(ctx, client.ObjectKey{
+		Namespace: t.namespace,
+		Name:      "oci-registry-service",
+	}, &service)
+
+	return OciConfig{
+		Registry: fmt.Sprintf("%s:5000/porch-test", service.Spec.ClusterIP),
+	}
+}
+
 func endpointIsReady(endpoints *coreapi.Endpoints) bool {
 	if len(endpoints.Subsets) == 0 {
 		return false
@@ -577,6 +1250,15 @@ func (t *TestSuite) ParseKptfileF(resources *porchapi.PackageRevisionResources)
 	if err != nil {
 		t.Fatalf("Cannot decode Kptfile (%s): %v", contents, err)
 	}
+	if kptfile.Pipeline != nil {
+		for _, fns := range [][]kptfilev1.Function{kptfile.Pipeline.Mutators, kptfile.Pipeline.Validators} {
+			for i := range fns {
+				if _, err := fns[i].GetTimeout(); err != nil {
+					t.Fatalf("Invalid timeout for function %q in Kptfile: %v", fns[i].Image, err)
+				}
+			}
+		}
+	}
 	return kptfile
 }
 
@@ -607,6 +1289,71 @@ func (t *TestSuite) FindAndDecodeF(resources *porchapi.PackageRevisionResources,
 	}
 }
 
+// AssertHasResource asserts that the package contains the named resource
+// file, listing the files present in the package if it does not.
+func (t *TestSuite) AssertHasResource(ctx context.Context, resources *porchapi.PackageRevisionResources, name string) {
+	t.assertHasResource(ctx, resources, name, reportWith(t.Errorf))
+}
+
+func (t *TestSuite) assertHasResource(ctx context.Context, resources *porchapi.PackageRevisionResources, name string, eh ErrorHandler) {
+	if _, ok := resources.Spec.Resources[name]; !ok {
+		eh(ctx, "Resource %q not found in %s/%s package; found:\n%s", name, resources.Namespace, resources.Name, strings.Join(resourceNames(resources), "\n"))
+	}
+}
+
+// AssertNotHasResource asserts that the package does not contain the named
+// resource file.
+func (t *TestSuite) AssertNotHasResource(ctx context.Context, resources *porchapi.PackageRevisionResources, name string) {
+	t.assertNotHasResource(ctx, resources, name, reportWith(t.Errorf))
+}
+
+func (t *TestSuite) assertNotHasResource(ctx context.Context, resources *porchapi.PackageRevisionResources, name string, eh ErrorHandler) {
+	if _, ok := resources.Spec.Resources[name]; ok {
+		eh(ctx, "Unexpected resource %q found in %s/%s package", name, resources.Namespace, resources.Name)
+	}
+}
+
+// AssertResourceCount asserts the number of resource files in the package.
+func (t *TestSuite) AssertResourceCount(ctx context.Context, resources *porchapi.PackageRevisionResources, expected int) {
+	t.assertResourceCount(ctx, resources, expected, reportWith(t.Errorf))
+}
+
+func (t *TestSuite) assertResourceCount(ctx context.Context, resources *porchapi.PackageRevisionResources, expected int, eh ErrorHandler) {
+	if got := len(resources.Spec.Resources); got != expected {
+		eh(ctx, "Found %d resources in %s/%s package, want %d:\n%s", got, resources.Namespace, resources.Name, expected, strings.Join(resourceNames(resources), "\n"))
+	}
+}
+
+// AssertResourceYAML asserts that the named resource file of the package is
+// equal to the golden YAML, ignoring the order of fields.
+func (t *TestSuite) AssertResourceYAML(ctx context.Context, resources *porchapi.PackageRevisionResources, name, goldenYAML string) {
+	t.assertResourceYAML(ctx, resources, name, goldenYAML, reportWith(t.Errorf))
+}
+
+func (t *TestSuite) assertResourceYAML(ctx context.Context, resources *porchapi.PackageRevisionResources, name, goldenYAML string, eh ErrorHandler) {
+	contents, ok := resources.Spec.Resources[name]
+	if !ok {
+		t.assertHasResource(ctx, resources, name, eh)
+		return
+	}
+	if diff := cmp.Diff(normalizeYamlOrdering(t.T, goldenYAML), normalizeYamlOrdering(t.T, contents)); diff != "" {
+		eh(ctx, "Unexpected contents of %q in %s/%s package (-want, +got): %s", name, resources.Namespace, resources.Name, diff)
+	}
+}
+
+// resourceNames returns the sorted names of the resource files of the package.
+func resourceNames(resources *porchapi.PackageRevisionResources) []string {
+	names := make([]string, 0, len(resources.Spec.Resources))
+	for name := range resources.Spec.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CompareGoldenFileYAML compares the YAML with the golden file, ignoring the
+// order of fields, and returns a readable diff from the golden file, empty if
+// they are equal. See diffutil.PrettyDiff.
 func (t *TestSuite) CompareGoldenFileYAML(goldenPath string, gotContents string) string {
 	gotContents = normalizeYamlOrdering(t.T, gotContents)
 
@@ -619,7 +1366,38 @@ func (t *TestSuite) CompareGoldenFileYAML(goldenPath string, gotContents string)
 	if err != nil {
 		t.Fatalf("Failed to read golden file %q: %v", goldenPath, err)
 	}
-	return cmp.Diff(string(golden), gotContents)
+	return diffutil.PrettyDiff(string(golden), gotContents)
+}
+
+// CompareResourceSets compares two multi-document YAML strings ignoring the
+// order of the documents and of the fields within them. It returns a diff,
+// empty if the resource sets are equal.
+func (t *TestSuite) CompareResourceSets(got, want string) string {
+	return cmp.Diff(t.normalizeResourceSet(want), t.normalizeResourceSet(got))
+}
+
+func (t *TestSuite) normalizeResourceSet(contents string) string {
+	nodes, err := kio.FromBytes([]byte(contents))
+	if err != nil {
+		t.Fatalf("Failed to parse resources: %v\n%s\n", err, contents)
+	}
+
+	key := func(n *yaml.RNode) string {
+		return strings.Join([]string{n.GetApiVersion(), n.GetKind(), n.GetNamespace(), n.GetName()}, "/")
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return key(nodes[i]) < key(nodes[j])
+	})
+
+	documents := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		s, err := n.String()
+		if err != nil {
+			t.Fatalf("Failed to serialize resource %s: %v", key(n), err)
+		}
+		documents = append(documents, normalizeYamlOrdering(t.T, s))
+	}
+	return strings.Join(documents, "---\n")
 }
 
 func normalizeYamlOrdering(t *testing.T, contents string) string {
@@ -0,0 +1,439 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/generated/clientset/versioned/fake"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// sequenceClientset returns a fake clientset which returns the lifecycles in
+// order from consecutive gets of the package revision, repeating the last one.
+// An empty lifecycle makes the get return NotFound.
+func sequenceClientset(key client.ObjectKey, lifecycles ...porchapi.PackageRevisionLifecycle) *fake.Clientset {
+	cs := fake.NewSimpleClientset()
+	gets := 0
+	cs.PrependReactor("get", "packagerevisions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		lifecycle := lifecycles[len(lifecycles)-1]
+		if gets < len(lifecycles) {
+			lifecycle = lifecycles[gets]
+		}
+		gets++
+		if lifecycle == "" {
+			return true, nil, apierrors.NewNotFound(porchapi.Resource("packagerevisions"), key.Name)
+		}
+		return true, &porchapi.PackageRevision{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec:       porchapi.PackageRevisionSpec{Lifecycle: lifecycle},
+		}, nil
+	})
+	return cs
+}
+
+func TestWaitForPackageRevision(t *testing.T) {
+	key := client.ObjectKey{Namespace: "test", Name: "repo-0123456789"}
+	published := func(pr *porchapi.PackageRevision) bool {
+		return pr.Spec.Lifecycle == porchapi.PackageRevisionLifecyclePublished
+	}
+
+	for _, tc := range []struct {
+		name       string
+		lifecycles []porchapi.PackageRevisionLifecycle
+		condition  func(pr *porchapi.PackageRevision) bool
+		wantErr    bool
+	}{
+		{
+			name:       "already published",
+			lifecycles: []porchapi.PackageRevisionLifecycle{porchapi.PackageRevisionLifecyclePublished},
+			condition:  published,
+		},
+		{
+			name: "published after proposal",
+			lifecycles: []porchapi.PackageRevisionLifecycle{
+				porchapi.PackageRevisionLifecycleDraft,
+				porchapi.PackageRevisionLifecycleProposed,
+				porchapi.PackageRevisionLifecyclePublished,
+			},
+			condition: published,
+		},
+		{
+			name: "created while waiting",
+			lifecycles: []porchapi.PackageRevisionLifecycle{
+				"",
+				porchapi.PackageRevisionLifecyclePublished,
+			},
+			condition: published,
+		},
+		{
+			name:       "never published",
+			lifecycles: []porchapi.PackageRevisionLifecycle{porchapi.PackageRevisionLifecycleDraft},
+			condition:  published,
+			wantErr:    true,
+		},
+		{
+			name:       "never created",
+			lifecycles: []porchapi.PackageRevisionLifecycle{""},
+			condition:  published,
+			wantErr:    true,
+		},
+		{
+			name: "custom condition",
+			lifecycles: []porchapi.PackageRevisionLifecycle{
+				porchapi.PackageRevisionLifecycleDraft,
+				porchapi.PackageRevisionLifecycleProposed,
+			},
+			condition: func(pr *porchapi.PackageRevision) bool {
+				return pr.Spec.Lifecycle != porchapi.PackageRevisionLifecycleDraft
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := &TestSuite{T: t, clientset: sequenceClientset(key, tc.lifecycles...)}
+
+			var errs []string
+			eh := reportWith(func(format string, args ...interface{}) {
+				errs = append(errs, fmt.Sprintf(format, args...))
+			})
+
+			pr := suite.waitForPackageRevision(context.Background(), key, tc.condition, 100*time.Millisecond, time.Millisecond, eh, "test condition")
+
+			if tc.wantErr {
+				if len(errs) == 0 {
+					t.Errorf("waitForPackageRevision succeeded; want error")
+				} else if want := "(during WaitForPackageRevision)"; !strings.HasSuffix(errs[0], want) {
+					t.Errorf("waitForPackageRevision error %q does not end with %q", errs[0], want)
+				}
+				if pr != nil {
+					t.Errorf("waitForPackageRevision returned %v; want nil", pr)
+				}
+				return
+			}
+			if len(errs) != 0 {
+				t.Fatalf("waitForPackageRevision failed: %v", errs)
+			}
+			if pr == nil || !tc.condition(pr) {
+				t.Errorf("waitForPackageRevision returned %v which does not satisfy the condition", pr)
+			}
+		})
+	}
+}
+
+func TestWaitForPackageRevisionLifecycle(t *testing.T) {
+	key := client.ObjectKey{Namespace: "test", Name: "repo-0123456789"}
+
+	for _, lifecycle := range []porchapi.PackageRevisionLifecycle{
+		porchapi.PackageRevisionLifecycleDraft,
+		porchapi.PackageRevisionLifecycleProposed,
+		porchapi.PackageRevisionLifecyclePublished,
+	} {
+		t.Run(string(lifecycle), func(t *testing.T) {
+			suite := &TestSuite{T: t, clientset: sequenceClientset(key, lifecycle)}
+
+			pr := suite.WaitForPackageRevisionLifecycle(context.Background(), key, lifecycle, time.Second)
+			if got := pr.Spec.Lifecycle; got != lifecycle {
+				t.Errorf("lifecycle = %s; want %s", got, lifecycle)
+			}
+
+			pr = suite.WaitForPackageRevisionCondition(context.Background(), key, func(pr *porchapi.PackageRevision) bool {
+				return pr.Spec.Lifecycle == lifecycle
+			}, time.Second)
+			if got := pr.Spec.Lifecycle; got != lifecycle {
+				t.Errorf("lifecycle = %s; want %s", got, lifecycle)
+			}
+		})
+	}
+}
+
+func TestResourceAssertions(t *testing.T) {
+	ctx := context.Background()
+	resources := &porchapi.PackageRevisionResources{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "repo-0123456789"},
+		Spec: porchapi.PackageRevisionResourcesSpec{
+			Resources: map[string]string{
+				"Kptfile": "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: bucket\n",
+				"bucket.yaml": `apiVersion: storage.cnrm.cloud.google.com/v1beta1
+kind: StorageBucket
+metadata:
+  name: blueprints-project-bucket
+  namespace: config-control
+spec:
+  storageClass: standard
+`,
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		assert  func(suite *TestSuite, eh ErrorHandler)
+		wantErr string
+	}{
+		{
+			name: "has resource",
+			assert: func(suite *TestSuite, eh ErrorHandler) {
+				suite.assertHasResource(ctx, resources, "bucket.yaml", eh)
+			},
+		},
+		{
+			name: "missing resource",
+			assert: func(suite *TestSuite, eh ErrorHandler) {
+				suite.assertHasResource(ctx, resources, "README.md", eh)
+			},
+			wantErr: "Resource \"README.md\" not found in test/repo-0123456789 package; found:\nKptfile\nbucket.yaml",
+		},
+		{
+			name: "not has resource",
+			assert: func(suite *TestSuite, eh ErrorHandler) {
+				suite.assertNotHasResource(ctx, resources, "README.md", eh)
+			},
+		},
+		{
+			name: "unexpected resource",
+			assert: func(suite *TestSuite, eh ErrorHandler) {
+				suite.assertNotHasResource(ctx, resources, "Kptfile", eh)
+			},
+			wantErr: "Unexpected resource \"Kptfile\" found in test/repo-0123456789 package",
+		},
+		{
+			name: "resource count",
+			assert: func(suite *TestSuite, eh ErrorHandler) {
+				suite.assertResourceCount(ctx, resources, 2, eh)
+			},
+		},
+		{
+			name: "wrong resource count",
+			assert: func(suite *TestSuite, eh ErrorHandler) {
+				suite.assertResourceCount(ctx, resources, 3, eh)
+			},
+			wantErr: "Found 2 resources in test/repo-0123456789 package, want 3:\nKptfile\nbucket.yaml",
+		},
+		{
+			name: "resource yaml in different field order",
+			assert: func(suite *TestSuite, eh ErrorHandler) {
+				suite.assertResourceYAML(ctx, resources, "bucket.yaml", `kind: StorageBucket
+apiVersion: storage.cnrm.cloud.google.com/v1beta1
+spec:
+  storageClass: standard
+metadata:
+  namespace: config-control
+  name: blueprints-project-bucket
+`, eh)
+			},
+		},
+		{
+			name: "different resource yaml",
+			assert: func(suite *TestSuite, eh ErrorHandler) {
+				suite.assertResourceYAML(ctx, resources, "bucket.yaml", `apiVersion: storage.cnrm.cloud.google.com/v1beta1
+kind: StorageBucket
+metadata:
+  name: blueprints-project-bucket
+  namespace: config-control
+spec:
+  storageClass: nearline
+`, eh)
+			},
+			wantErr: "Unexpected contents of \"bucket.yaml\" in test/repo-0123456789 package (-want, +got): ",
+		},
+		{
+			name: "resource yaml of missing resource",
+			assert: func(suite *TestSuite, eh ErrorHandler) {
+				suite.assertResourceYAML(ctx, resources, "README.md", "", eh)
+			},
+			wantErr: "Resource \"README.md\" not found in test/repo-0123456789 package; found:\nKptfile\nbucket.yaml",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := &TestSuite{T: t}
+
+			var errs []string
+			tc.assert(suite, func(ctx context.Context, format string, args ...interface{}) {
+				errs = append(errs, fmt.Sprintf(format, args...))
+			})
+
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("assertion failed: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.HasPrefix(errs[0], tc.wantErr) {
+				t.Errorf("assertion errors: %q; want one error starting with %q", errs, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceAssertionsPass(t *testing.T) {
+	ctx := context.Background()
+	suite := &TestSuite{T: t}
+	resources := &porchapi.PackageRevisionResources{
+		Spec: porchapi.PackageRevisionResourcesSpec{
+			Resources: map[string]string{
+				"config.yaml": "b: 2\na: 1\n",
+			},
+		},
+	}
+
+	suite.AssertHasResource(ctx, resources, "config.yaml")
+	suite.AssertNotHasResource(ctx, resources, "Kptfile")
+	suite.AssertResourceCount(ctx, resources, 1)
+	suite.AssertResourceYAML(ctx, resources, "config.yaml", "a: 1\nb: 2\n")
+}
+
+func TestErrorHandlerOperations(t *testing.T) {
+	ctx := context.Background()
+	if tc := TestContextFrom(ctx); tc != nil {
+		t.Errorf("TestContext of background context = %v; want nil", tc)
+	}
+
+	outer := WithOperation(ctx, "RegisterRepository")
+	inner := WithOperation(WithOperation(outer, "CreatePackageRevision"), "UpdateApproval")
+	if got, want := TestContextFrom(outer).String(), "RegisterRepository"; got != want {
+		t.Errorf("outer operation chain = %q; want %q", got, want)
+	}
+	if got, want := TestContextFrom(inner).String(), "RegisterRepository → CreatePackageRevision → UpdateApproval"; got != want {
+		t.Errorf("inner operation chain = %q; want %q", got, want)
+	}
+
+	for _, tc := range []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "no operation",
+			ctx:  ctx,
+			want: "failed to get resource: not found",
+		},
+		{
+			name: "operation chain",
+			ctx:  inner,
+			want: "failed to get resource: not found (during RegisterRepository → CreatePackageRevision → UpdateApproval)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var errs []string
+			eh := reportWith(func(format string, args ...interface{}) {
+				errs = append(errs, fmt.Sprintf(format, args...))
+			})
+			eh(tc.ctx, "failed to get resource: %v", "not found")
+			if len(errs) != 1 || errs[0] != tc.want {
+				t.Errorf("reported errors: %q; want %q", errs, tc.want)
+			}
+		})
+	}
+}
+
+func TestReadFixture(t *testing.T) {
+	files := readFixture(t, filepath.Join("testdata", "fixtures", "basic-package"))
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if diff := cmp.Diff([]string{"Kptfile", "config/config-map.yaml"}, names); diff != "" {
+		t.Errorf("unexpected fixture files (-want, +got): %s", diff)
+	}
+	if !strings.Contains(files["config/config-map.yaml"], "name: fixture-configmap") {
+		t.Errorf("unexpected contents of config/config-map.yaml:\n%s", files["config/config-map.yaml"])
+	}
+}
+
+func TestRetryOnConflict(t *testing.T) {
+	conflict := apierrors.NewConflict(porchapi.Resource("packagerevisions"), "repo-0123456789", fmt.Errorf("the object has been modified"))
+	notFound := apierrors.NewNotFound(porchapi.Resource("packagerevisions"), "repo-0123456789")
+
+	for _, tc := range []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "success after conflicts",
+			errs:      []error{conflict, conflict, conflict, nil},
+			wantCalls: 4,
+		},
+		{
+			name:      "other error",
+			errs:      []error{conflict, notFound},
+			wantCalls: 2,
+			wantErr:   notFound,
+		},
+		{
+			name:      "persistent conflict",
+			errs:      []error{conflict},
+			wantCalls: conflictRetries + 1,
+			wantErr:   conflict,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			fn := func() error {
+				err := tc.errs[len(tc.errs)-1]
+				if calls < len(tc.errs) {
+					err = tc.errs[calls]
+				}
+				calls++
+				return err
+			}
+
+			err := retryOnConflict(context.Background(), fn, conflictRetries, time.Millisecond)
+			if err != tc.wantErr {
+				t.Errorf("retryOnConflict returned %v; want %v", err, tc.wantErr)
+			}
+			if calls != tc.wantCalls {
+				t.Errorf("fn called %d times; want %d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryOnConflictCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := retryOnConflict(ctx, func() error {
+		calls++
+		return apierrors.NewConflict(porchapi.Resource("packagerevisions"), "repo-0123456789", fmt.Errorf("the object has been modified"))
+	}, conflictRetries, time.Hour)
+	if err != context.Canceled {
+		t.Errorf("retryOnConflict returned %v; want %v", err, context.Canceled)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times; want 1", calls)
+	}
+}
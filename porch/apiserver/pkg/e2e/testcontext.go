@@ -0,0 +1,69 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"strings"
+)
+
+// TestContext tracks the chain of test operations in progress, such as
+// registering a repository and creating a package revision in it, so that
+// the ErrorHandler can report what the test was doing when an error occurred.
+type TestContext struct {
+	// Operations are the operations in progress, outermost first.
+	Operations []string
+}
+
+type testContextKey struct{}
+
+// WithOperation returns a copy of ctx whose TestContext has the operation
+// appended to the operation chain of ctx.
+func WithOperation(ctx context.Context, operation string) context.Context {
+	var operations []string
+	if tc := TestContextFrom(ctx); tc != nil {
+		operations = append(operations, tc.Operations...)
+	}
+	return context.WithValue(ctx, testContextKey{}, &TestContext{Operations: append(operations, operation)})
+}
+
+// TestContextFrom returns the TestContext of ctx, or nil if no operation was
+// started with WithOperation.
+func TestContextFrom(ctx context.Context) *TestContext {
+	tc, _ := ctx.Value(testContextKey{}).(*TestContext)
+	return tc
+}
+
+// String returns the operation chain, for example
+// "RegisterRepository → CreatePackageRevision → UpdateApproval".
+func (tc *TestContext) String() string {
+	return strings.Join(tc.Operations, " → ")
+}
+
+// ErrorHandler reports an error which occurred while running the operations
+// of the TestContext of ctx.
+type ErrorHandler func(ctx context.Context, format string, args ...interface{})
+
+// reportWith returns the ErrorHandler which reports errors with report,
+// typically t.Errorf or t.Fatalf, followed by the operation chain of ctx.
+func reportWith(report func(format string, args ...interface{})) ErrorHandler {
+	return func(ctx context.Context, format string, args ...interface{}) {
+		if tc := TestContextFrom(ctx); tc != nil && len(tc.Operations) > 0 {
+			format += " (during %s)"
+			args = append(args, tc)
+		}
+		report(format, args...)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// traceEnv must be set to 1 to enable tracing of the test suite.
+	traceEnv = "PORCH_TEST_TRACE"
+
+	// DefaultTraceEndpoint is the OTLP gRPC endpoint of the local Jaeger
+	// instance.
+	DefaultTraceEndpoint = "localhost:4317"
+
+	jaegerContainer = "porch-test-jaeger"
+	jaegerImage     = "jaegertracing/all-in-one:1.35"
+	jaegerUIPort    = "16686"
+)
+
+// WithTracing makes the test suite export a trace of every test, including
+// the API calls the test makes, to the OTLP gRPC endpoint. If the endpoint
+// is on the local machine and nothing listens on it, a Jaeger container is
+// started. The Jaeger URL of the trace is logged when a test fails.
+//
+// Tracing is only enabled if the PORCH_TEST_TRACE environment variable is
+// set to 1, so standard test runs don't depend on Jaeger. WithTracing must
+// be called before Initialize.
+func (t *TestSuite) WithTracing(endpoint string) *TestSuite {
+	if os.Getenv(traceEnv) != "1" {
+		return t
+	}
+
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		t.Fatalf("Invalid trace endpoint %q: %v", endpoint, err)
+	}
+	if host == "localhost" || host == "127.0.0.1" {
+		t.ensureJaeger(endpoint, port)
+	}
+
+	ctx := context.Background()
+	exporter, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(
+		otlpgrpc.WithInsecure(),
+		otlpgrpc.WithEndpoint(endpoint),
+	))
+	if err != nil {
+		t.Fatalf("Failed to create trace exporter for %q: %v", endpoint, err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.ServiceNameKey.String("porch-e2e"))),
+	)
+	t.Cleanup(func() {
+		// Flushes the pending spans.
+		if err := provider.Shutdown(context.Background()); err != nil {
+			t.Logf("Failed to shut down trace provider: %v", err)
+		}
+	})
+
+	t.tracerProvider = provider
+	t.traceUI = fmt.Sprintf("http://%s", net.JoinHostPort(host, jaegerUIPort))
+	t.Logf("Exporting traces to %s, view them at %s", endpoint, t.traceUI)
+	return t
+}
+
+// ensureJaeger starts a Jaeger container listening on the local port unless
+// the endpoint already accepts connections.
+func (t *TestSuite) ensureJaeger(endpoint, port string) {
+	if conn, err := net.DialTimeout("tcp", endpoint, time.Second); err == nil {
+		conn.Close()
+		return
+	}
+
+	t.Logf("Starting Jaeger container %q ...", jaegerContainer)
+	cmd := exec.Command("docker", "run", "--detach", "--rm",
+		"--name", jaegerContainer,
+		"--env", "COLLECTOR_OTLP_ENABLED=true",
+		"--publish", fmt.Sprintf("%s:4317", port),
+		"--publish", fmt.Sprintf("%s:%s", jaegerUIPort, jaegerUIPort),
+		jaegerImage)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to start Jaeger: %v\n%s", err, string(out))
+	}
+
+	giveUp := time.Now().Add(time.Minute)
+	for {
+		conn, err := net.DialTimeout("tcp", endpoint, time.Second)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(giveUp) {
+			t.Fatalf("Jaeger is not accepting connections on %s: %v", endpoint, err)
+		}
+		time.Sleep(time.Second)
+	}
+	// The container is left running so that the traces of failed tests can
+	// be inspected after the test run.
+	t.Logf("Jaeger is running; stop it with 'docker stop %s'", jaegerContainer)
+}
+
+// traceTransport adds the trace context of the request context to the API
+// calls made by the clients created from cfg.
+func (t *TestSuite) traceTransport(cfg *rest.Config) {
+	if t.tracerProvider == nil {
+		return
+	}
+	provider := t.tracerProvider
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return otelhttp.NewTransport(rt,
+			otelhttp.WithTracerProvider(provider),
+			otelhttp.WithPropagators(propagation.TraceContext{}))
+	})
+}
+
+// TraceTest starts a trace of the current test and returns the context to
+// make the API calls of the test with. The trace ends when the test
+// completes, and its URL is logged if the test failed. TraceTest returns
+// ctx unchanged if tracing is not enabled.
+func (t *TestSuite) TraceTest(ctx context.Context) context.Context {
+	if t.tracerProvider == nil {
+		return ctx
+	}
+	provider, ui, tt := t.tracerProvider, t.traceUI, t.T
+	ctx, span := provider.Tracer("porch-e2e").Start(ctx, tt.Name(), trace.WithNewRoot())
+	tt.Cleanup(func() {
+		span.End()
+		if !tt.Failed() {
+			return
+		}
+		if err := provider.ForceFlush(context.Background()); err != nil {
+			tt.Logf("Failed to flush the trace: %v", err)
+		}
+		tt.Logf("Trace of the failed test: %s/trace/%s", ui, span.SpanContext().TraceID())
+	})
+	return ctx
+}
@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,8 +32,9 @@ import (
 
 func RunBackground(ctx context.Context, coreClient client.WithWatch, cache *cache.Cache) {
 	b := background{
-		coreClient: coreClient,
-		cache:      cache,
+		coreClient:   coreClient,
+		cache:        cache,
+		syncRequests: map[types.NamespacedName]string{},
 	}
 	go b.run(ctx)
 }
@@ -40,6 +43,8 @@ func RunBackground(ctx context.Context, coreClient client.WithWatch, cache *cach
 type background struct {
 	coreClient client.WithWatch
 	cache      *cache.Cache
+	// syncRequests holds the last handled sync request annotation of each repository.
+	syncRequests map[types.NamespacedName]string
 }
 
 const (
@@ -103,7 +108,9 @@ loop:
 					bookmark = repository.ResourceVersion
 					klog.Infof("Bookmark: %q", bookmark)
 				} else {
-					b.updateCache(ctx, event.Type, repository)
+					if err := b.updateCache(ctx, event.Type, repository); err != nil {
+						klog.Errorf("Failed to handle repository %s event: %v", event.Type, err)
+					}
 				}
 			} else {
 				klog.V(5).Infof("Received unexpected watch event Object: %T", event.Object)
@@ -130,12 +137,18 @@ func (b *background) updateCache(ctx context.Context, event watch.EventType, rep
 	switch event {
 	case watch.Added:
 		klog.Infof("Repository added: %s:%s", repository.ObjectMeta.Namespace, repository.ObjectMeta.Name)
+		b.syncRequests[client.ObjectKeyFromObject(repository)] = repository.Annotations[configapi.AnnotationSyncRequested]
 		return b.cacheRepository(ctx, repository)
 	case watch.Modified:
 		klog.Infof("Repository modified: %s:%s", repository.ObjectMeta.Namespace, repository.ObjectMeta.Name)
-		// TODO: implement
+		key := client.ObjectKeyFromObject(repository)
+		if requested := repository.Annotations[configapi.AnnotationSyncRequested]; requested != "" && requested != b.syncRequests[key] {
+			b.syncRequests[key] = requested
+			return b.syncRepository(ctx, repository)
+		}
 	case watch.Deleted:
 		klog.Infof("Repository deleted: %s:%s", repository.ObjectMeta.Namespace, repository.ObjectMeta.Name)
+		delete(b.syncRequests, client.ObjectKeyFromObject(repository))
 		return b.cache.CloseRepository(repository)
 	default:
 		klog.Warning("Unhandled watch event type: %s", event)
@@ -167,10 +180,76 @@ func (b *background) runOnce(ctx context.Context) error {
 	return nil
 }
 
+// cacheRepository opens the repository in the cache, and reports the outcome
+// in the Ready condition of the repository.
 func (b *background) cacheRepository(ctx context.Context, repo *configapi.Repository) error {
-	if _, err := b.cache.OpenRepository(ctx, repo); err != nil {
+	cr, err := b.cache.OpenRepository(ctx, repo)
+	if err != nil {
+		if err := b.setCondition(ctx, repo, configapi.RepositoryConditionReady, v1.ConditionFalse, "Error", err.Error()); err != nil {
+			klog.Errorf("Cannot update status of repository %s:%s: %v", repo.Namespace, repo.Name, err)
+		}
 		return fmt.Errorf("error opening repository: %w", err)
 	}
+	if err := b.setDefaultBranch(ctx, repo, cr.DefaultBranch()); err != nil {
+		klog.Errorf("Cannot update status of repository %s:%s: %v", repo.Namespace, repo.Name, err)
+	}
+	return b.setCondition(ctx, repo, configapi.RepositoryConditionReady, v1.ConditionTrue, "Ready", "Repository ready")
+}
+
+// syncRepository synchronizes the repository immediately, as requested by the
+// sync-requested annotation, and reports the outcome in the UpstreamSynced
+// condition of the repository, along with the handled request.
+func (b *background) syncRepository(ctx context.Context, repo *configapi.Repository) error {
+	klog.Infof("Repository sync requested: %s:%s", repo.Namespace, repo.Name)
+	requested := repo.Annotations[configapi.AnnotationSyncRequested]
+	if err := b.setCondition(ctx, repo, configapi.RepositoryConditionUpstreamSynced, v1.ConditionFalse, "Syncing", "Repository sync in progress"); err != nil {
+		return err
+	}
+
+	// The handled request is recorded in the same status update as the
+	// outcome, so that clients waiting for the request see both together.
+	err := b.cache.SyncRepository(ctx, repo)
+	repo.Status.LastSyncRequest = requested
+	if err != nil {
+		if err := b.setCondition(ctx, repo, configapi.RepositoryConditionUpstreamSynced, v1.ConditionFalse, "SyncFailed", err.Error()); err != nil {
+			klog.Errorf("Cannot update status of repository %s:%s: %v", repo.Namespace, repo.Name, err)
+		}
+		return fmt.Errorf("error syncing repository: %w", err)
+	}
+	return b.setCondition(ctx, repo, configapi.RepositoryConditionUpstreamSynced, v1.ConditionTrue, "Synced", "Repository synced")
+}
+
+// setCondition sets the condition of the repository. The status of the
+// repository is only written if the condition changed, as repositories are
+// refreshed periodically.
+func (b *background) setCondition(ctx context.Context, repo *configapi.Repository, conditionType string, status v1.ConditionStatus, reason, message string) error {
+	if c := meta.FindStatusCondition(repo.Status.Conditions, conditionType); c != nil &&
+		c.Status == status && c.Reason == reason && c.Message == message && c.ObservedGeneration == repo.Generation {
+		return nil
+	}
+	meta.SetStatusCondition(&repo.Status.Conditions, v1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: repo.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	if err := b.coreClient.Status().Update(ctx, repo); err != nil {
+		return fmt.Errorf("error updating repository status: %w", err)
+	}
+	return nil
+}
+
+// setDefaultBranch records the detected default branch of the repository in
+// its status, if it changed.
+func (b *background) setDefaultBranch(ctx context.Context, repo *configapi.Repository, branch string) error {
+	if repo.Status.DefaultBranch == branch {
+		return nil
+	}
+	repo.Status.DefaultBranch = branch
+	if err := b.coreClient.Status().Update(ctx, repo); err != nil {
+		return fmt.Errorf("error updating repository status: %w", err)
+	}
 	return nil
 }
 
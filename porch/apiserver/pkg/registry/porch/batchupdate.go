@@ -0,0 +1,153 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// packageRevisionResourcesBatchUpdate commits several file updates to a
+// package revision atomically.
+type packageRevisionResourcesBatchUpdate struct {
+	common packageCommon
+
+	// inflight holds the package revisions with a batch update in progress.
+	mutex    sync.Mutex
+	inflight map[types.NamespacedName]bool
+}
+
+var _ rest.Storage = &packageRevisionResourcesBatchUpdate{}
+var _ rest.Scoper = &packageRevisionResourcesBatchUpdate{}
+var _ rest.NamedCreater = &packageRevisionResourcesBatchUpdate{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (b *packageRevisionResourcesBatchUpdate) New() runtime.Object {
+	return &api.PackageRevisionResourcesBatchUpdate{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (b *packageRevisionResourcesBatchUpdate) NamespaceScoped() bool {
+	return true
+}
+
+// Create applies the file updates of the posted
+// PackageRevisionResourcesBatchUpdate to the named package revision in a
+// single commit. The batch is rejected with a conflict if the package
+// revision changed since the resource version of the batch, or if another
+// batch update of the package revision is in progress.
+func (b *packageRevisionResourcesBatchUpdate) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
+	if !namespaced {
+		return nil, apierrors.NewBadRequest("namespace must be specified")
+	}
+
+	batch, ok := obj.(*api.PackageRevisionResourcesBatchUpdate)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected PackageRevisionResourcesBatchUpdate object, got %T", obj))
+	}
+	if batch.Spec.ResourceVersion == "" {
+		return nil, apierrors.NewBadRequest("spec.resourceVersion must be specified")
+	}
+
+	gr := api.PackageRevisionResourcesGVR.GroupResource()
+	key := types.NamespacedName{Namespace: ns, Name: name}
+	if !b.begin(key) {
+		return nil, apierrors.NewConflict(gr, name, errors.New("another batch update of the package revision is in progress"))
+	}
+	defer b.end(key)
+
+	oldPackage, err := b.common.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	oldObj, err := oldPackage.GetResources(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	if oldObj.ResourceVersion != batch.Spec.ResourceVersion {
+		return nil, apierrors.NewConflict(gr, name, fmt.Errorf("the package revision has been modified; resource version is %q, batch was made against %q", oldObj.ResourceVersion, batch.Spec.ResourceVersion))
+	}
+
+	nameTokens, err := ParseName(name)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid name %q", name))
+	}
+
+	var repositoryObj configapi.Repository
+	repositoryID := types.NamespacedName{Namespace: ns, Name: nameTokens.RepositoryName}
+	if err := b.common.coreClient.Get(ctx, repositoryID, &repositoryObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, apierrors.NewNotFound(gr, repositoryID.Name)
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting repository %v: %w", repositoryID, err))
+	}
+
+	rev, err := b.common.cad.BatchUpdatePackageResources(ctx, &repositoryObj, oldPackage, batch.Spec.Updates)
+	if err != nil {
+		if errors.Is(err, engine.ErrInvalidFileUpdate) {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
+		return nil, engineError(err)
+	}
+
+	updated, err := rev.GetResources(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	b.common.conflicts.Observe(ns, name, updated.Spec.Resources)
+
+	result := batch.DeepCopy()
+	// The resource version of a package revision in a git repository is the
+	// SHA of its commit.
+	result.Status.Commit = updated.ResourceVersion
+	return result, nil
+}
+
+// begin marks a batch update of the package revision as in progress. It
+// returns false if one already is.
+func (b *packageRevisionResourcesBatchUpdate) begin(key types.NamespacedName) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.inflight == nil {
+		b.inflight = map[types.NamespacedName]bool{}
+	}
+	if b.inflight[key] {
+		return false
+	}
+	b.inflight[key] = true
+	return true
+}
+
+func (b *packageRevisionResourcesBatchUpdate) end(key types.NamespacedName) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.inflight, key)
+}
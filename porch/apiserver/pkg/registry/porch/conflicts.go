@@ -0,0 +1,165 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+// conditionHasConflicts is set on package revisions with files left with
+// merge conflicts, and removed once all conflicts are resolved.
+const conditionHasConflicts = "HasConflicts"
+
+// conflictTracker records the files left with merge conflicts of the package
+// revisions whose resources were read or written, and sets their
+// HasConflicts condition.
+type conflictTracker struct {
+	mutex     sync.Mutex
+	conflicts map[types.NamespacedName][]string
+}
+
+func newConflictTracker() *conflictTracker {
+	return &conflictTracker{
+		conflicts: map[types.NamespacedName][]string{},
+	}
+}
+
+// Observe records the files in conflict of the package revision with the
+// given resources, and returns them.
+func (t *conflictTracker) Observe(namespace, name string, resources map[string]string) []string {
+	files := conflictedFiles(resources)
+	if t == nil {
+		return files
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if len(files) == 0 {
+		delete(t.conflicts, key)
+	} else {
+		t.conflicts[key] = files
+	}
+	return files
+}
+
+// UpdateConditions sets the HasConflicts condition of the package revision if
+// files of it were last observed in conflict, and removes it otherwise.
+func (t *conflictTracker) UpdateConditions(pr *api.PackageRevision) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	files := t.conflicts[types.NamespacedName{Namespace: pr.Namespace, Name: pr.Name}]
+	t.mutex.Unlock()
+
+	if len(files) == 0 {
+		meta.RemoveStatusCondition(&pr.Status.Conditions, conditionHasConflicts)
+		return
+	}
+	meta.SetStatusCondition(&pr.Status.Conditions, metav1.Condition{
+		Type:               conditionHasConflicts,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: pr.Generation,
+		Reason:             "MergeConflicts",
+		Message:            fmt.Sprintf("Files have unresolved merge conflicts: %s", strings.Join(files, ", ")),
+	})
+}
+
+// conflictedFiles returns the sorted names of the files of a package which
+// contain conflict markers or resources annotated with
+// api.MergeConflictAnnotation.
+func conflictedFiles(resources map[string]string) []string {
+	var files []string
+	for filename, contents := range resources {
+		if fileHasConflicts(filename, contents) {
+			files = append(files, filename)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func fileHasConflicts(filename, contents string) bool {
+	if hasConflictMarkers(contents) {
+		return true
+	}
+	if !isYAMLResourceFile(filename) || !strings.Contains(contents, api.MergeConflictAnnotation) {
+		return false
+	}
+	nodes, err := (&kio.ByteReader{
+		Reader:                strings.NewReader(contents),
+		OmitReaderAnnotations: true,
+	}).Read()
+	if err != nil {
+		return false
+	}
+	for _, n := range nodes {
+		if _, found := n.GetAnnotations()[api.MergeConflictAnnotation]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// hasConflictMarkers returns true if the contents have a line starting with
+// a git conflict marker. The "=======" separator is not considered on its own,
+// as it also underlines markdown headings.
+func hasConflictMarkers(contents string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "<<<<<<<") || strings.HasPrefix(line, ">>>>>>>") {
+			return true
+		}
+	}
+	return false
+}
+
+// clearMergeConflictAnnotation removes api.MergeConflictAnnotation from the
+// resources of a file. Files without the annotation are returned unchanged.
+func clearMergeConflictAnnotation(filename, contents string) (string, error) {
+	if !isYAMLResourceFile(filename) || !strings.Contains(contents, api.MergeConflictAnnotation) {
+		return contents, nil
+	}
+	nodes, err := (&kio.ByteReader{
+		Reader:                strings.NewReader(contents),
+		OmitReaderAnnotations: true,
+	}).Read()
+	if err != nil {
+		return "", fmt.Errorf("cannot parse resources: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := (kio.ByteWriter{
+		Writer:           &buf,
+		ClearAnnotations: []string{api.MergeConflictAnnotation},
+	}).Write(nodes); err != nil {
+		return "", fmt.Errorf("cannot write resources: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const conflictedConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  annotations:
+    kpt.dev/merge-conflict: "true"
+data:
+<<<<<<< local
+  value: local
+=======
+  value: upstream
+>>>>>>> upstream
+`
+
+const annotatedConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  annotations:
+    kpt.dev/merge-conflict: "true"
+    owner: team
+data:
+  value: upstream
+`
+
+func TestConflictedFiles(t *testing.T) {
+	got := conflictedFiles(map[string]string{
+		"Kptfile":        "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: app\n",
+		"configmap.yaml": conflictedConfigMap,
+		"annotated.yaml": annotatedConfigMap,
+		"notes.txt":      "<<<<<<< local\nmine\n=======\ntheirs\n>>>>>>> upstream\n",
+		"README.md":      "Title\n=======\n",
+	})
+	want := []string{"annotated.yaml", "configmap.yaml", "notes.txt"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("conflictedFiles: unexpected result (-want, +got): %s", diff)
+	}
+}
+
+func TestClearMergeConflictAnnotation(t *testing.T) {
+	got, err := clearMergeConflictAnnotation("configmap.yaml", annotatedConfigMap)
+	if err != nil {
+		t.Fatalf("clearMergeConflictAnnotation failed: %v", err)
+	}
+	want := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  annotations:
+    owner: team
+data:
+  value: upstream
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("clearMergeConflictAnnotation: unexpected result (-want, +got): %s", diff)
+	}
+
+	unannotated := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app # keep\n"
+	if got, err := clearMergeConflictAnnotation("configmap.yaml", unannotated); err != nil || got != unannotated {
+		t.Errorf("clearMergeConflictAnnotation of a file without the annotation: got %q, %v; want it unchanged", got, err)
+	}
+}
+
+func TestConflictTrackerUpdateConditions(t *testing.T) {
+	tracker := newConflictTracker()
+	pr := &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "repo:app:v1"},
+	}
+
+	tracker.Observe("default", "repo:app:v1", map[string]string{"configmap.yaml": conflictedConfigMap})
+	tracker.UpdateConditions(pr)
+	condition := meta.FindStatusCondition(pr.Status.Conditions, conditionHasConflicts)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Fatalf("HasConflicts condition with conflicted files: got %v, want True", condition)
+	}
+
+	tracker.Observe("default", "repo:app:v1", map[string]string{"configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n"})
+	tracker.UpdateConditions(pr)
+	if condition := meta.FindStatusCondition(pr.Status.Conditions, conditionHasConflicts); condition != nil {
+		t.Errorf("HasConflicts condition after resolving the conflicts: got %v, want none", condition)
+	}
+}
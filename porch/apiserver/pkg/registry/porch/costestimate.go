@@ -0,0 +1,271 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// defaultPricingConfig is the name of the PricingConfig used when the request
+// doesn't name one.
+const defaultPricingConfig = "default"
+
+// packageRevisionsCostEstimate estimates the cost of deploying the resources
+// of a package revision.
+type packageRevisionsCostEstimate struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageRevisionsCostEstimate{}
+var _ rest.Scoper = &packageRevisionsCostEstimate{}
+var _ rest.NamedCreater = &packageRevisionsCostEstimate{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (c *packageRevisionsCostEstimate) New() runtime.Object {
+	return &api.CostEstimate{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (c *packageRevisionsCostEstimate) NamespaceScoped() bool {
+	return true
+}
+
+// Create estimates the hourly cost of the resources of the named package
+// revision with the unit prices of the PricingConfig named by the posted
+// CostEstimate.
+func (c *packageRevisionsCostEstimate) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
+	if !namespaced {
+		return nil, apierrors.NewBadRequest("namespace must be specified")
+	}
+
+	request, ok := obj.(*api.CostEstimate)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected CostEstimate object, got %T", obj))
+	}
+	pricingConfigName := request.PricingConfig
+	if pricingConfigName == "" {
+		pricingConfigName = defaultPricingConfig
+	}
+
+	var pricingConfig configapi.PricingConfig
+	pricingConfigID := types.NamespacedName{Namespace: ns, Name: pricingConfigName}
+	if err := c.common.coreClient.Get(ctx, pricingConfigID, &pricingConfig); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, apierrors.NewNotFound(configapi.KindPricingConfig.GroupResource(), pricingConfigName)
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting pricing config %v: %w", pricingConfigID, err))
+	}
+	prices, err := parsePrices(&pricingConfig.Spec)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid pricing config %q: %v", pricingConfigName, err))
+	}
+
+	pkg, err := c.common.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := pkg.GetResources(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	estimate, err := estimateCost(resources.Spec.Resources, prices)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	estimate.ObjectMeta = metav1.ObjectMeta{
+		Name:              resources.Name,
+		Namespace:         resources.Namespace,
+		UID:               resources.UID,
+		ResourceVersion:   resources.ResourceVersion,
+		CreationTimestamp: resources.CreationTimestamp,
+	}
+	estimate.PricingConfig = pricingConfigName
+	return estimate, nil
+}
+
+// unitPrices are the hourly prices of a PricingConfig in US dollars.
+type unitPrices struct {
+	cpuCore, memoryGiB, storageGiB, loadBalancer float64
+}
+
+func parsePrices(spec *configapi.PricingConfigSpec) (unitPrices, error) {
+	var prices unitPrices
+	for _, p := range []struct {
+		field string
+		value string
+		price *float64
+	}{
+		{"cpuCoreHour", spec.CPUCoreHour, &prices.cpuCore},
+		{"memoryGiBHour", spec.MemoryGiBHour, &prices.memoryGiB},
+		{"storageGiBHour", spec.StorageGiBHour, &prices.storageGiB},
+		{"loadBalancerHour", spec.LoadBalancerHour, &prices.loadBalancer},
+	} {
+		if p.value == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(p.value, 64)
+		if err != nil || price < 0 {
+			return unitPrices{}, fmt.Errorf("%s must be a non-negative decimal number, got %q", p.field, p.value)
+		}
+		*p.price = price
+	}
+	return prices, nil
+}
+
+const gibibyte = 1 << 30
+
+// costAccumulator sums the requests of the resources of one kind.
+type costAccumulator struct {
+	count                int
+	cpu, memory, storage resource.Quantity
+}
+
+// estimateCost estimates the hourly cost of the Deployments, the
+// PersistentVolumeClaims and the LoadBalancer Services in the package
+// resources. Requests of Deployments count once per replica.
+func estimateCost(resources map[string]string, prices unitPrices) (*api.CostEstimate, error) {
+	filenames := make([]string, 0, len(resources))
+	for filename := range resources {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	var deployments, claims, loadBalancers costAccumulator
+	for _, filename := range filenames {
+		if !isYAMLResourceFile(filename) {
+			continue
+		}
+		nodes, err := (&kio.ByteReader{
+			Reader:                strings.NewReader(resources[filename]),
+			OmitReaderAnnotations: true,
+		}).Read()
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse resources in %s: %w", filename, err)
+		}
+		for _, n := range nodes {
+			switch n.GetKind() {
+			case "Deployment":
+				var d appsv1.Deployment
+				if err := decodeNode(n, &d); err != nil {
+					return nil, fmt.Errorf("cannot parse Deployment %s in %s: %w", n.GetName(), filename, err)
+				}
+				replicas := int64(1)
+				if d.Spec.Replicas != nil {
+					replicas = int64(*d.Spec.Replicas)
+				}
+				deployments.count++
+				for _, container := range d.Spec.Template.Spec.Containers {
+					addReplicas(&deployments.cpu, container.Resources.Requests[corev1.ResourceCPU], replicas)
+					addReplicas(&deployments.memory, container.Resources.Requests[corev1.ResourceMemory], replicas)
+				}
+			case "PersistentVolumeClaim":
+				var pvc corev1.PersistentVolumeClaim
+				if err := decodeNode(n, &pvc); err != nil {
+					return nil, fmt.Errorf("cannot parse PersistentVolumeClaim %s in %s: %w", n.GetName(), filename, err)
+				}
+				claims.count++
+				claims.storage.Add(pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+			case "Service":
+				var svc corev1.Service
+				if err := decodeNode(n, &svc); err != nil {
+					return nil, fmt.Errorf("cannot parse Service %s in %s: %w", n.GetName(), filename, err)
+				}
+				if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+					loadBalancers.count++
+				}
+			}
+		}
+	}
+
+	estimate := &api.CostEstimate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CostEstimate",
+			APIVersion: api.SchemeGroupVersion.Identifier(),
+		},
+	}
+	var total float64
+	if deployments.count > 0 {
+		cost := deployments.cpu.AsApproximateFloat64()*prices.cpuCore +
+			deployments.memory.AsApproximateFloat64()/gibibyte*prices.memoryGiB
+		total += cost
+		estimate.Resources = append(estimate.Resources, api.ResourceCostEstimate{
+			Kind:          "Deployment",
+			Count:         deployments.count,
+			CPU:           deployments.cpu.String(),
+			Memory:        deployments.memory.String(),
+			HourlyCostUSD: formatUSD(cost),
+		})
+	}
+	if claims.count > 0 {
+		cost := claims.storage.AsApproximateFloat64() / gibibyte * prices.storageGiB
+		total += cost
+		estimate.Resources = append(estimate.Resources, api.ResourceCostEstimate{
+			Kind:          "PersistentVolumeClaim",
+			Count:         claims.count,
+			Storage:       claims.storage.String(),
+			HourlyCostUSD: formatUSD(cost),
+		})
+	}
+	if loadBalancers.count > 0 {
+		cost := float64(loadBalancers.count) * prices.loadBalancer
+		total += cost
+		estimate.Resources = append(estimate.Resources, api.ResourceCostEstimate{
+			Kind:          "Service",
+			Count:         loadBalancers.count,
+			HourlyCostUSD: formatUSD(cost),
+		})
+	}
+	estimate.HourlyCostUSD = formatUSD(total)
+	return estimate, nil
+}
+
+// decodeNode decodes the KRM resource into the typed object.
+func decodeNode(n *yaml.RNode, obj interface{}) error {
+	b, err := n.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, obj)
+}
+
+func addReplicas(sum *resource.Quantity, request resource.Quantity, replicas int64) {
+	sum.Add(*resource.NewMilliQuantity(request.MilliValue()*replicas, request.Format))
+}
+
+func formatUSD(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', 4, 64)
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEstimateCost(t *testing.T) {
+	prices, err := parsePrices(&configapi.PricingConfigSpec{
+		CPUCoreHour:      "0.04",
+		MemoryGiBHour:    "0.005",
+		StorageGiBHour:   "0.0001",
+		LoadBalancerHour: "0.025",
+	})
+	if err != nil {
+		t.Fatalf("parsePrices failed: %v", err)
+	}
+
+	estimate, err := estimateCost(map[string]string{
+		"Kptfile": `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: app
+`,
+		"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx
+        resources:
+          requests:
+            cpu: 500m
+            memory: 1Gi
+      - name: sidecar
+        image: envoy
+        resources:
+          requests:
+            cpu: 100m
+`,
+		"storage.yaml": `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  resources:
+    requests:
+      storage: 100Gi
+`,
+		"services.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: public
+spec:
+  type: LoadBalancer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: internal
+spec:
+  type: ClusterIP
+`,
+		"README.md": "# app",
+	}, prices)
+	if err != nil {
+		t.Fatalf("estimateCost failed: %v", err)
+	}
+
+	want := []api.ResourceCostEstimate{
+		{Kind: "Deployment", Count: 1, CPU: "1800m", Memory: "3Gi", HourlyCostUSD: "0.0870"},
+		{Kind: "PersistentVolumeClaim", Count: 1, Storage: "100Gi", HourlyCostUSD: "0.0100"},
+		{Kind: "Service", Count: 1, HourlyCostUSD: "0.0250"},
+	}
+	if diff := cmp.Diff(want, estimate.Resources); diff != "" {
+		t.Errorf("unexpected estimate (-want, +got): %s", diff)
+	}
+	if got, want := estimate.HourlyCostUSD, "0.1220"; got != want {
+		t.Errorf("total hourly cost: got %s, want %s", got, want)
+	}
+}
+
+func TestParsePricesRejectsInvalidPrices(t *testing.T) {
+	for _, price := range []string{"abc", "-1"} {
+		if _, err := parsePrices(&configapi.PricingConfigSpec{CPUCoreHour: price}); err == nil {
+			t.Errorf("parsePrices accepted cpuCoreHour %q", price)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+)
+
+// DefaultCredentialCacheTTL is how long resolved credentials are reused
+// before the secret is read again.
+const DefaultCredentialCacheTTL = 5 * time.Minute
+
+// CachedCredentialProvider caches credentials resolved by another resolver so
+// that repeated git operations don't read the same secret over and over.
+// Cached credentials expire after the configured TTL, which allows rotated
+// secrets to be picked up.
+type CachedCredentialProvider struct {
+	resolver repository.CredentialResolver
+	ttl      time.Duration
+
+	mutex   sync.RWMutex
+	entries map[credentialKey]cachedCredential
+	clock   clock.Clock
+}
+
+type credentialKey struct {
+	namespace string
+	name      string
+}
+
+type cachedCredential struct {
+	credential repository.Credential
+	expires    time.Time
+}
+
+var _ repository.CredentialResolver = &CachedCredentialProvider{}
+
+// NewCachedCredentialProvider returns a credential resolver caching the
+// credentials returned by resolver for ttl, as measured by clk. A non-positive
+// ttl disables caching.
+func NewCachedCredentialProvider(resolver repository.CredentialResolver, ttl time.Duration, clk clock.Clock) *CachedCredentialProvider {
+	return &CachedCredentialProvider{
+		resolver: resolver,
+		ttl:      ttl,
+		entries:  map[credentialKey]cachedCredential{},
+		clock:    clk,
+	}
+}
+
+func (p *CachedCredentialProvider) ResolveCredential(ctx context.Context, namespace, name string) (repository.Credential, error) {
+	if p.ttl <= 0 {
+		return p.resolver.ResolveCredential(ctx, namespace, name)
+	}
+
+	key := credentialKey{namespace: namespace, name: name}
+
+	p.mutex.RLock()
+	entry, found := p.entries[key]
+	p.mutex.RUnlock()
+
+	if found && p.clock.Now().Before(entry.expires) {
+		return entry.credential, nil
+	}
+
+	credential, err := p.resolver.ResolveCredential(ctx, namespace, name)
+	if err != nil {
+		// Don't keep serving credentials from a secret which can no longer be read.
+		p.mutex.Lock()
+		delete(p.entries, key)
+		p.mutex.Unlock()
+		return repository.Credential{}, err
+	}
+
+	p.mutex.Lock()
+	p.entries[key] = cachedCredential{
+		credential: credential,
+		expires:    p.clock.Now().Add(p.ttl),
+	}
+	p.mutex.Unlock()
+
+	return credential, nil
+}
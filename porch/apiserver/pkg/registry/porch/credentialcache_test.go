@@ -0,0 +1,75 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock/fakeclock"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+)
+
+type countingCredentialResolver struct {
+	calls map[string]int
+}
+
+func (r *countingCredentialResolver) ResolveCredential(ctx context.Context, namespace, name string) (repository.Credential, error) {
+	r.calls[namespace+"/"+name]++
+	return repository.Credential{
+		Data: map[string][]byte{
+			"username": []byte("user"),
+			"password": []byte(name),
+		},
+	}, nil
+}
+
+func TestCachedCredentialProvider(t *testing.T) {
+	ctx := context.Background()
+	resolver := &countingCredentialResolver{calls: map[string]int{}}
+	clock := fakeclock.NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	provider := NewCachedCredentialProvider(resolver, DefaultCredentialCacheTTL, clock)
+
+	for i := 0; i < 3; i++ {
+		cred, err := provider.ResolveCredential(ctx, "default", "git-auth")
+		if err != nil {
+			t.Fatalf("ResolveCredential failed: %v", err)
+		}
+		if got, want := string(cred.Data["password"]), "git-auth"; got != want {
+			t.Errorf("unexpected password: got %q, want %q", got, want)
+		}
+	}
+	if got, want := resolver.calls["default/git-auth"], 1; got != want {
+		t.Errorf("secret read %d times within TTL; want %d", got, want)
+	}
+
+	// Secrets are cached independently.
+	if _, err := provider.ResolveCredential(ctx, "other", "git-auth"); err != nil {
+		t.Fatalf("ResolveCredential failed: %v", err)
+	}
+	if got, want := resolver.calls["other/git-auth"], 1; got != want {
+		t.Errorf("secret other/git-auth read %d times; want %d", got, want)
+	}
+
+	// After the TTL expires, the secret is read again.
+	clock.Advance(DefaultCredentialCacheTTL)
+	if _, err := provider.ResolveCredential(ctx, "default", "git-auth"); err != nil {
+		t.Fatalf("ResolveCredential failed: %v", err)
+	}
+	if got, want := resolver.calls["default/git-auth"], 2; got != want {
+		t.Errorf("secret read %d times after TTL expired; want %d", got, want)
+	}
+}
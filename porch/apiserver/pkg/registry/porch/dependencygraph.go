@@ -0,0 +1,283 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// dependencyGraphTTL is how long the dependency graph of a repository is
+// reused before it is computed again.
+const dependencyGraphTTL = 30 * time.Second
+
+// repositoryDependencyGraphs serves the clone dependency graph of the packages
+// in a repository, rendered as a DOT or Mermaid diagram.
+type repositoryDependencyGraphs struct {
+	common packageCommon
+	clock  clock.Clock
+
+	mutex   sync.Mutex
+	entries map[types.NamespacedName]cachedDependencyGraph
+}
+
+type cachedDependencyGraph struct {
+	graph   *dependencyGraph
+	expires time.Time
+}
+
+var _ rest.Storage = &repositoryDependencyGraphs{}
+var _ rest.Scoper = &repositoryDependencyGraphs{}
+var _ rest.GetterWithOptions = &repositoryDependencyGraphs{}
+
+func newRepositoryDependencyGraphs(common packageCommon, clk clock.Clock) *repositoryDependencyGraphs {
+	return &repositoryDependencyGraphs{
+		common:  common,
+		clock:   clk,
+		entries: map[types.NamespacedName]cachedDependencyGraph{},
+	}
+}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (g *repositoryDependencyGraphs) New() runtime.Object {
+	return &api.RepositoryDependencyGraph{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (g *repositoryDependencyGraphs) NamespaceScoped() bool {
+	return true
+}
+
+// NewGetOptions returns the options object decoded from the query parameters.
+func (g *repositoryDependencyGraphs) NewGetOptions() (runtime.Object, bool, string) {
+	return &api.RepositoryDependencyGraphOptions{}, false, ""
+}
+
+// Get returns the dependency graph of the packages in the repository of the
+// given name.
+func (g *repositoryDependencyGraphs) Get(ctx context.Context, name string, options runtime.Object) (runtime.Object, error) {
+	opts, ok := options.(*api.RepositoryDependencyGraphOptions)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid options object: %T", options))
+	}
+	format := opts.Format
+	switch format {
+	case "":
+		format = api.DependencyGraphFormatDot
+	case api.DependencyGraphFormatDot, api.DependencyGraphFormatMermaid:
+	default:
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unsupported format %q; must be %q or %q", format, api.DependencyGraphFormatDot, api.DependencyGraphFormatMermaid))
+	}
+
+	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
+	if !namespaced {
+		return nil, apierrors.NewBadRequest("namespace must be specified")
+	}
+
+	var repositoryObj configapi.Repository
+	repositoryID := types.NamespacedName{Namespace: ns, Name: name}
+	if err := g.common.coreClient.Get(ctx, repositoryID, &repositoryObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, apierrors.NewNotFound(configapi.KindRepository.GroupResource(), name)
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting repository %v: %w", repositoryID, err))
+	}
+
+	graph, err := g.getGraph(repositoryID, func() (*dependencyGraph, error) {
+		return g.computeGraph(ctx, &repositoryObj)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.RepositoryDependencyGraph{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RepositoryDependencyGraph",
+			APIVersion: api.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              repositoryObj.Name,
+			Namespace:         repositoryObj.Namespace,
+			UID:               repositoryObj.UID,
+			ResourceVersion:   repositoryObj.ResourceVersion,
+			CreationTimestamp: repositoryObj.CreationTimestamp,
+		},
+		Format: format,
+		Nodes:  graph.nodes,
+		Edges:  graph.edges,
+	}
+	if format == api.DependencyGraphFormatMermaid {
+		result.Diagram = graph.mermaid()
+	} else {
+		result.Diagram = graph.dot(repositoryObj.Name)
+	}
+	return result, nil
+}
+
+// getGraph returns the cached graph of the repository, or computes and caches
+// it if it is missing or expired.
+func (g *repositoryDependencyGraphs) getGraph(key types.NamespacedName, compute func() (*dependencyGraph, error)) (*dependencyGraph, error) {
+	g.mutex.Lock()
+	entry, found := g.entries[key]
+	g.mutex.Unlock()
+
+	if found && g.clock.Now().Before(entry.expires) {
+		return entry.graph, nil
+	}
+
+	graph, err := compute()
+	if err != nil {
+		return nil, err
+	}
+
+	g.mutex.Lock()
+	g.entries[key] = cachedDependencyGraph{
+		graph:   graph,
+		expires: g.clock.Now().Add(dependencyGraphTTL),
+	}
+	g.mutex.Unlock()
+
+	return graph, nil
+}
+
+func (g *repositoryDependencyGraphs) computeGraph(ctx context.Context, repositoryObj *configapi.Repository) (*dependencyGraph, error) {
+	repo, err := g.common.cad.OpenRepository(ctx, repositoryObj)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := repo.ListPackageRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []*api.PackageRevision
+	for _, rev := range revisions {
+		pr, err := rev.GetPackageRevision()
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	graph, err := buildDependencyGraph(prs)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return graph, nil
+}
+
+// dependencyGraph is the clone dependency graph of packages, identified as
+// `repo/pkg`.
+type dependencyGraph struct {
+	nodes []string
+	edges []api.DependencyGraphEdge
+}
+
+// buildDependencyGraph builds the dependency graph of the package revisions
+// from their clone ancestry. Revisions of the same package are a single node.
+func buildDependencyGraph(revisions []*api.PackageRevision) (*dependencyGraph, error) {
+	nodes := map[string]bool{}
+	edges := map[api.DependencyGraphEdge]bool{}
+
+	for _, pr := range revisions {
+		lineage, err := repository.ParseCloneLineage(pr.Annotations)
+		if err != nil {
+			return nil, fmt.Errorf("package revision %s: %w", pr.Name, err)
+		}
+
+		node := pr.Spec.RepositoryName + "/" + pr.Spec.PackageName
+		nodes[node] = true
+
+		// Ancestors run from the oldest to the direct upstream, each cloned
+		// from the preceding one.
+		chain := make([]string, 0, len(lineage.Ancestors)+1)
+		for _, ancestor := range lineage.Ancestors {
+			if i := strings.LastIndex(ancestor, "@"); i >= 0 {
+				ancestor = ancestor[:i]
+			}
+			chain = append(chain, ancestor)
+		}
+		chain = append(chain, node)
+
+		for i, n := range chain {
+			nodes[n] = true
+			if i > 0 && chain[i-1] != n {
+				edges[api.DependencyGraphEdge{Upstream: chain[i-1], Downstream: n}] = true
+			}
+		}
+	}
+
+	graph := &dependencyGraph{}
+	for n := range nodes {
+		graph.nodes = append(graph.nodes, n)
+	}
+	sort.Strings(graph.nodes)
+	for e := range edges {
+		graph.edges = append(graph.edges, e)
+	}
+	sort.Slice(graph.edges, func(i, j int) bool {
+		if graph.edges[i].Upstream != graph.edges[j].Upstream {
+			return graph.edges[i].Upstream < graph.edges[j].Upstream
+		}
+		return graph.edges[i].Downstream < graph.edges[j].Downstream
+	})
+	return graph, nil
+}
+
+// dot renders the graph in the Graphviz DOT language.
+func (g *dependencyGraph) dot(name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", name)
+	for _, n := range g.nodes {
+		fmt.Fprintf(&b, "  %q;\n", n)
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", e.Upstream, e.Downstream)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaid renders the graph as a top-down Mermaid flowchart. Nodes get
+// generated IDs, as package names are not valid Mermaid node IDs.
+func (g *dependencyGraph) mermaid() string {
+	ids := make(map[string]string, len(g.nodes))
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for i, n := range g.nodes {
+		ids[n] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n], strings.ReplaceAll(n, `"`, "#quot;"))
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[e.Upstream], ids[e.Downstream])
+	}
+	return b.String()
+}
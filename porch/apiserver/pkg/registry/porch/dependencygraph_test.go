@@ -0,0 +1,151 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock/fakeclock"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newDependencyGraphTestRevision(repo, pkg, ancestry string) *api.PackageRevision {
+	pr := &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: repo + "-" + pkg},
+		Spec: api.PackageRevisionSpec{
+			RepositoryName: repo,
+			PackageName:    pkg,
+		},
+	}
+	if ancestry != "" {
+		pr.Annotations = map[string]string{api.CloneAncestryAnnotation: ancestry}
+	}
+	return pr
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	graph, err := buildDependencyGraph([]*api.PackageRevision{
+		newDependencyGraphTestRevision("deployment", "bucket", "blueprints/bucket@v1 → team/bucket@v2"),
+		newDependencyGraphTestRevision("deployment", "bucket", "blueprints/bucket@v1 → team/bucket@v3"),
+		newDependencyGraphTestRevision("deployment", "network", "blueprints/network@v1"),
+		newDependencyGraphTestRevision("blueprints", "bucket", ""),
+	})
+	if err != nil {
+		t.Fatalf("buildDependencyGraph failed: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{
+		"blueprints/bucket",
+		"blueprints/network",
+		"deployment/bucket",
+		"deployment/network",
+		"team/bucket",
+	}, graph.nodes); diff != "" {
+		t.Errorf("unexpected nodes (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]api.DependencyGraphEdge{
+		{Upstream: "blueprints/bucket", Downstream: "team/bucket"},
+		{Upstream: "blueprints/network", Downstream: "deployment/network"},
+		{Upstream: "team/bucket", Downstream: "deployment/bucket"},
+	}, graph.edges); diff != "" {
+		t.Errorf("unexpected edges (-want, +got): %s", diff)
+	}
+}
+
+func TestBuildDependencyGraphInvalidLineage(t *testing.T) {
+	pr := newDependencyGraphTestRevision("deployment", "bucket", "")
+	pr.Annotations = map[string]string{api.CloneDepthAnnotation: "many"}
+
+	if _, err := buildDependencyGraph([]*api.PackageRevision{pr}); err == nil {
+		t.Errorf("buildDependencyGraph succeeded with invalid lineage; want error")
+	}
+}
+
+func TestDependencyGraphRendering(t *testing.T) {
+	graph := &dependencyGraph{
+		nodes: []string{"blueprints/bucket", "deployment/bucket"},
+		edges: []api.DependencyGraphEdge{
+			{Upstream: "blueprints/bucket", Downstream: "deployment/bucket"},
+		},
+	}
+
+	wantDot := `digraph "deployment" {
+  "blueprints/bucket";
+  "deployment/bucket";
+  "blueprints/bucket" -> "deployment/bucket";
+}
+`
+	if diff := cmp.Diff(wantDot, graph.dot("deployment")); diff != "" {
+		t.Errorf("unexpected DOT diagram (-want, +got): %s", diff)
+	}
+
+	wantMermaid := `flowchart TD
+  n0["blueprints/bucket"]
+  n1["deployment/bucket"]
+  n0 --> n1
+`
+	if diff := cmp.Diff(wantMermaid, graph.mermaid()); diff != "" {
+		t.Errorf("unexpected Mermaid diagram (-want, +got): %s", diff)
+	}
+}
+
+func TestDependencyGraphCache(t *testing.T) {
+	clk := fakeclock.NewFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	graphs := newRepositoryDependencyGraphs(packageCommon{}, clk)
+	key := types.NamespacedName{Namespace: "default", Name: "deployment"}
+
+	computed := 0
+	compute := func() (*dependencyGraph, error) {
+		computed++
+		return &dependencyGraph{}, nil
+	}
+
+	for _, step := range []struct {
+		advance      time.Duration
+		wantComputed int
+	}{
+		{advance: 0, wantComputed: 1},
+		{advance: dependencyGraphTTL / 2, wantComputed: 1},
+		{advance: dependencyGraphTTL, wantComputed: 2},
+		{advance: time.Second, wantComputed: 2},
+	} {
+		clk.Advance(step.advance)
+		if _, err := graphs.getGraph(key, compute); err != nil {
+			t.Fatalf("getGraph failed: %v", err)
+		}
+		if computed != step.wantComputed {
+			t.Errorf("after %v: graph computed %d times; want %d", step.advance, computed, step.wantComputed)
+		}
+	}
+
+	// Errors are not cached.
+	clk.Advance(dependencyGraphTTL)
+	if _, err := graphs.getGraph(key, func() (*dependencyGraph, error) {
+		return nil, errors.New("failed")
+	}); err == nil {
+		t.Errorf("getGraph succeeded; want error")
+	}
+	if _, err := graphs.getGraph(key, compute); err != nil {
+		t.Fatalf("getGraph failed: %v", err)
+	}
+	if computed != 3 {
+		t.Errorf("graph computed %d times after error; want 3", computed)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// packageRevisionsDiff serves the file-level difference between the
+// resources of a package revision and a base package revision.
+type packageRevisionsDiff struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageRevisionsDiff{}
+var _ rest.Scoper = &packageRevisionsDiff{}
+var _ rest.GetterWithOptions = &packageRevisionsDiff{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (d *packageRevisionsDiff) New() runtime.Object {
+	return &api.PackageRevisionDiff{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (d *packageRevisionsDiff) NamespaceScoped() bool {
+	return true
+}
+
+// NewGetOptions returns the options object decoded from the query parameters.
+func (d *packageRevisionsDiff) NewGetOptions() (runtime.Object, bool, string) {
+	return &api.PackageRevisionDiffOptions{}, false, ""
+}
+
+func (d *packageRevisionsDiff) Get(ctx context.Context, name string, options runtime.Object) (runtime.Object, error) {
+	opts, ok := options.(*api.PackageRevisionDiffOptions)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid options object: %T", options))
+	}
+
+	pkg, err := d.common.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := pkg.GetPackageRevision()
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	resources, err := pkg.GetResources(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	base := opts.Base
+	if base == "" {
+		if base, err = d.previousRevision(ctx, pr); err != nil {
+			return nil, err
+		}
+	}
+	var baseResources map[string]string
+	if base != "" {
+		basePkg, err := d.common.getPackage(ctx, base)
+		if err != nil {
+			return nil, err
+		}
+		r, err := basePkg.GetResources(ctx)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		baseResources = r.Spec.Resources
+	}
+
+	diff, err := computePackageRevisionDiff(baseResources, resources.Spec.Resources)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	diff.ObjectMeta = metav1.ObjectMeta{
+		Name:              pr.Name,
+		Namespace:         pr.Namespace,
+		UID:               pr.UID,
+		ResourceVersion:   pr.ResourceVersion,
+		CreationTimestamp: pr.CreationTimestamp,
+	}
+	diff.Base = base
+	return diff, nil
+}
+
+// previousRevision returns the name of the latest published revision of the
+// package which precedes the package revision, or "" if there is none.
+func (d *packageRevisionsDiff) previousRevision(ctx context.Context, pr *api.PackageRevision) (string, error) {
+	var previous *api.PackageRevision
+	filter := repository.ListPackageRevisionFilter{
+		Package:   pr.Spec.PackageName,
+		Lifecycle: api.PackageRevisionLifecyclePublished,
+	}
+	if err := d.common.listPackages(ctx, labels.Everything(), filter, func(p repository.PackageRevision) error {
+		candidate, err := p.GetPackageRevision()
+		if err != nil {
+			return err
+		}
+		if candidate.Spec.RepositoryName != pr.Spec.RepositoryName || candidate.Name == pr.Name ||
+			!revisionLess(candidate.Spec.Revision, pr.Spec.Revision) {
+			return nil
+		}
+		if previous == nil || revisionLess(previous.Spec.Revision, candidate.Spec.Revision) {
+			previous = candidate
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	if previous == nil {
+		return "", nil
+	}
+	return previous.Name, nil
+}
+
+// revisionLess orders revisions of the form `v<N>` or `<N>` numerically, and
+// other revisions lexically.
+func revisionLess(a, b string) bool {
+	na, aErr := strconv.Atoi(strings.TrimPrefix(a, "v"))
+	nb, bErr := strconv.Atoi(strings.TrimPrefix(b, "v"))
+	if aErr == nil && bErr == nil {
+		return na < nb
+	}
+	return a < b
+}
+
+// computePackageRevisionDiff returns the unified diff of each file which
+// differs between the base and the updated package resources.
+func computePackageRevisionDiff(base, updated map[string]string) (*api.PackageRevisionDiff, error) {
+	names := map[string]bool{}
+	for name := range base {
+		names[name] = true
+	}
+	for name := range updated {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	diff := &api.PackageRevisionDiff{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevisionDiff",
+			APIVersion: api.SchemeGroupVersion.Identifier(),
+		},
+	}
+	for _, name := range sorted {
+		oldContent, inBase := base[name]
+		newContent, inUpdated := updated[name]
+		if inBase && inUpdated && oldContent == newContent {
+			continue
+		}
+		// Empty files are added or removed without a diff.
+		var unified string
+		if oldContent != "" || newContent != "" {
+			var err error
+			unified, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        splitLines(oldContent),
+				B:        splitLines(newContent),
+				FromFile: "a/" + name,
+				ToFile:   "b/" + name,
+				Context:  3,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("cannot diff %q: %w", name, err)
+			}
+		}
+		diff.Files = append(diff.Files, api.FileDiff{
+			Name:        name,
+			OldContent:  oldContent,
+			NewContent:  newContent,
+			UnifiedDiff: unified,
+		})
+	}
+	return diff, nil
+}
+
+// splitLines splits the contents into lines, keeping their line endings.
+// Unlike difflib.SplitLines, it does not add an empty line after the last
+// newline.
+func splitLines(contents string) []string {
+	lines := strings.SplitAfter(contents, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestComputePackageRevisionDiff(t *testing.T) {
+	base := map[string]string{
+		"Kptfile":     "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: app\n",
+		"config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  key: value\n",
+		"removed.txt": "gone\n",
+	}
+	updated := map[string]string{
+		"Kptfile":     base["Kptfile"],
+		"config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  key: changed\n",
+		"added.txt":   "new\n",
+	}
+
+	diff, err := computePackageRevisionDiff(base, updated)
+	if err != nil {
+		t.Fatalf("computePackageRevisionDiff failed: %v", err)
+	}
+	want := []api.FileDiff{
+		{
+			Name:        "added.txt",
+			NewContent:  "new\n",
+			UnifiedDiff: "--- a/added.txt\n+++ b/added.txt\n@@ -0,0 +1 @@\n+new\n",
+		},
+		{
+			Name:        "config.yaml",
+			OldContent:  base["config.yaml"],
+			NewContent:  updated["config.yaml"],
+			UnifiedDiff: "--- a/config.yaml\n+++ b/config.yaml\n@@ -3,4 +3,4 @@\n metadata:\n   name: config\n data:\n-  key: value\n+  key: changed\n",
+		},
+		{
+			Name:        "removed.txt",
+			OldContent:  "gone\n",
+			UnifiedDiff: "--- a/removed.txt\n+++ b/removed.txt\n@@ -1 +0,0 @@\n-gone\n",
+		},
+	}
+	if diff := cmp.Diff(want, diff.Files); diff != "" {
+		t.Errorf("computePackageRevisionDiff: unexpected files (-want, +got): %s", diff)
+	}
+}
+
+func TestRevisionLess(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{"v1", "v2", true},
+		{"v2", "v10", true},
+		{"v10", "v2", false},
+		{"v1", "v1", false},
+		{"1", "2", true},
+		{"alpha", "beta", true},
+	} {
+		if got := revisionLess(tc.a, tc.b); got != tc.want {
+			t.Errorf("revisionLess(%q, %q): got %t, want %t", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// imageDigestResolver resolves the tags of OCI images referenced by package
+// revisions to digests.
+var imageDigestResolver engine.FunctionDigestResolver = oci.NewImageDigestResolver()
+
+// requiresDigest returns true if the repository requires package revisions to
+// reference OCI images by digest.
+func requiresDigest(repository *configapi.Repository) bool {
+	oci := repository.Spec.Oci
+	return oci != nil && oci.ContentDigestPolicy == configapi.ContentDigestPolicyRequireDigest
+}
+
+// ociImageReference is an OCI image referenced by a package revision task.
+type ociImageReference struct {
+	path *field.Path
+	oci  *api.OciPackage
+}
+
+// ociImageReferences returns the OCI images referenced by the package revision tasks.
+func ociImageReferences(pr *api.PackageRevision) []ociImageReference {
+	var refs []ociImageReference
+	for i := range pr.Spec.Tasks {
+		task := &pr.Spec.Tasks[i]
+		if task.Clone != nil && task.Clone.Upstream.Oci != nil {
+			refs = append(refs, ociImageReference{
+				path: field.NewPath("spec", "tasks").Index(i).Child("clone", "upstream", "oci", "image"),
+				oci:  task.Clone.Upstream.Oci,
+			})
+		}
+	}
+	return refs
+}
+
+func isDigestReference(image string) bool {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return false
+	}
+	_, ok := ref.(name.Digest)
+	return ok
+}
+
+// validateContentDigestPolicy rejects package revisions which reference OCI
+// images by tag if the repository requires digests.
+func validateContentDigestPolicy(pr *api.PackageRevision, repository *configapi.Repository) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !requiresDigest(repository) {
+		return allErrs
+	}
+	for _, ref := range ociImageReferences(pr) {
+		if !isDigestReference(ref.oci.Image) {
+			allErrs = append(allErrs, field.Invalid(ref.path, ref.oci.Image,
+				fmt.Sprintf("repository %q requires OCI images to be referenced by digest (image@sha256:...)", repository.Name)))
+		}
+	}
+	return allErrs
+}
+
+// ResolvePRTagToDigest replaces the tags of the OCI images referenced by the
+// package revision with the digests the tags currently resolve to.
+func ResolvePRTagToDigest(ctx context.Context, pr *api.PackageRevision) error {
+	for _, ref := range ociImageReferences(pr) {
+		image := ref.oci.Image
+		if isDigestReference(image) {
+			continue
+		}
+		parsed, err := name.ParseReference(image)
+		if err != nil {
+			return fmt.Errorf("%s: cannot parse image reference %q: %w", ref.path, image, err)
+		}
+		digest, err := imageDigestResolver.ResolveDigest(ctx, image)
+		if err != nil {
+			return fmt.Errorf("%s: cannot resolve digest of %q: %w", ref.path, image, err)
+		}
+		ref.oci.Image = parsed.Context().Name() + "@" + digest
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testDigest = "sha256:8ae9b8c6d2b2b1ee0c3b1d0a2e0d8c3a9f5e6b7c8d9e0f1a2b3c4d5e6f7a8b9c"
+
+func ociClonePackageRevision(image string) *api.PackageRevision {
+	return &api.PackageRevision{
+		Spec: api.PackageRevisionSpec{
+			PackageName:    "package",
+			Revision:       "v1",
+			RepositoryName: "oci",
+			Tasks: []api.Task{{
+				Type: api.TaskTypeClone,
+				Clone: &api.PackageCloneTaskSpec{
+					Upstream: api.UpstreamPackage{
+						Type: api.RepositoryTypeOCI,
+						Oci:  &api.OciPackage{Image: image},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestValidateContentDigestPolicy(t *testing.T) {
+	repository := &configapi.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "oci"},
+		Spec: configapi.RepositorySpec{
+			Oci: &configapi.OciRepository{
+				Registry:            "gcr.io/example",
+				ContentDigestPolicy: configapi.ContentDigestPolicyRequireDigest,
+			},
+		},
+	}
+
+	tagged := ociClonePackageRevision("gcr.io/example/upstream:v1")
+	if errs := (packageRevisionStrategy{}).ValidateCreate(context.Background(), tagged, repository); len(errs) != 1 {
+		t.Errorf("expected tag reference to be rejected, got %v", errs)
+	} else if got, want := errs[0].Field, "spec.tasks[0].clone.upstream.oci.image"; got != want {
+		t.Errorf("unexpected field: got %q, want %q", got, want)
+	}
+
+	pinned := ociClonePackageRevision("gcr.io/example/upstream@" + testDigest)
+	if errs := (packageRevisionStrategy{}).ValidateCreate(context.Background(), pinned, repository); len(errs) != 0 {
+		t.Errorf("unexpected errors for digest reference: %v", errs)
+	}
+
+	repository.Spec.Oci.ContentDigestPolicy = ""
+	if errs := (packageRevisionStrategy{}).ValidateCreate(context.Background(), tagged, repository); len(errs) != 0 {
+		t.Errorf("unexpected errors without digest policy: %v", errs)
+	}
+}
+
+func TestResolvePRTagToDigest(t *testing.T) {
+	saved := imageDigestResolver
+	defer func() { imageDigestResolver = saved }()
+	imageDigestResolver = fakeDigestResolver{"gcr.io/example/upstream:v1": testDigest}
+
+	pr := ociClonePackageRevision("gcr.io/example/upstream:v1")
+	if err := ResolvePRTagToDigest(context.Background(), pr); err != nil {
+		t.Fatalf("ResolvePRTagToDigest failed: %v", err)
+	}
+	if got, want := pr.Spec.Tasks[0].Clone.Upstream.Oci.Image, "gcr.io/example/upstream@"+testDigest; got != want {
+		t.Errorf("unexpected image: got %q, want %q", got, want)
+	}
+
+	// Digest references are left unchanged.
+	if err := ResolvePRTagToDigest(context.Background(), pr); err != nil {
+		t.Fatalf("ResolvePRTagToDigest failed: %v", err)
+	}
+	if got, want := pr.Spec.Tasks[0].Clone.Upstream.Oci.Image, "gcr.io/example/upstream@"+testDigest; got != want {
+		t.Errorf("unexpected image: got %q, want %q", got, want)
+	}
+}
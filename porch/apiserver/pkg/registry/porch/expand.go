@@ -0,0 +1,159 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// cueValuesField is the field of a CUE template the request values are unified with.
+	cueValuesField = "values"
+	// cueObjectsField is the optional field of a CUE template listing the
+	// resources it expands to. Templates without it expand to a single
+	// resource: the template value itself, without the values.
+	cueObjectsField = "objects"
+)
+
+// packageRevisionResourcesExpand expands the templates in the resources of a
+// package revision.
+type packageRevisionResourcesExpand struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageRevisionResourcesExpand{}
+var _ rest.Scoper = &packageRevisionResourcesExpand{}
+var _ rest.NamedCreater = &packageRevisionResourcesExpand{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (e *packageRevisionResourcesExpand) New() runtime.Object {
+	return &api.TemplateExpansionRequest{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (e *packageRevisionResourcesExpand) NamespaceScoped() bool {
+	return true
+}
+
+// Create expands the templates of the named package revision with the values
+// of the posted TemplateExpansionRequest. The package revision is not
+// modified; the expanded resources are returned.
+func (e *packageRevisionResourcesExpand) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	req, ok := obj.(*api.TemplateExpansionRequest)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected TemplateExpansionRequest object, got %T", obj))
+	}
+	if req.Engine != api.TemplateEngineCUE {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unsupported template engine %q; only %q is supported", req.Engine, api.TemplateEngineCUE))
+	}
+
+	pkg, err := e.common.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := pkg.GetResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := expandCUETemplates(resources.Spec.Resources, req.Values)
+	if err != nil {
+		return nil, &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: err.Error(),
+		}}
+	}
+	resources.Spec.Resources = expanded
+	return resources, nil
+}
+
+// expandCUETemplates evaluates the `*.cue` files of the package with the
+// values and replaces each of them with a YAML file of the same name
+// containing the resources it expands to. Other files are returned unchanged.
+func expandCUETemplates(resources map[string]string, values map[string]interface{}) (map[string]string, error) {
+	cuectx := cuecontext.New()
+	expanded := make(map[string]string, len(resources))
+	for filename, contents := range resources {
+		if path.Ext(filename) != ".cue" {
+			expanded[filename] = contents
+			continue
+		}
+
+		out, err := expandCUETemplate(cuectx, filename, contents, values)
+		if err != nil {
+			return nil, fmt.Errorf("CUE evaluation of %s failed: %s", filename, cueerrors.Details(err, nil))
+		}
+		yamlFilename := strings.TrimSuffix(filename, ".cue") + ".yaml"
+		if _, found := resources[yamlFilename]; found {
+			return nil, fmt.Errorf("cannot expand %s: %s already exists in the package", filename, yamlFilename)
+		}
+		expanded[yamlFilename] = out
+	}
+	return expanded, nil
+}
+
+func expandCUETemplate(cuectx *cue.Context, filename, contents string, values map[string]interface{}) (string, error) {
+	v := cuectx.CompileString(contents, cue.Filename(filename))
+	if err := v.Err(); err != nil {
+		return "", err
+	}
+	if values != nil {
+		v = v.FillPath(cue.ParsePath(cueValuesField), values)
+	}
+	if err := v.Validate(cue.Concrete(true)); err != nil {
+		return "", err
+	}
+
+	var objects []interface{}
+	if list := v.LookupPath(cue.ParsePath(cueObjectsField)); list.Exists() {
+		if err := list.Decode(&objects); err != nil {
+			return "", err
+		}
+	} else {
+		var object map[string]interface{}
+		if err := v.Decode(&object); err != nil {
+			return "", err
+		}
+		delete(object, cueValuesField)
+		objects = append(objects, object)
+	}
+
+	var docs []string
+	for _, o := range objects {
+		b, err := yaml.Marshal(o)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, string(b))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpandCUETemplates(t *testing.T) {
+	resources := map[string]string{
+		"Kptfile": "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: pkg\n",
+		"configmap.cue": `values: {
+	name:     string
+	replicas: int | *1
+}
+
+apiVersion: "v1"
+kind:       "ConfigMap"
+metadata: name: values.name
+data: replicas: "\(values.replicas)"
+`,
+		"objects.cue": `values: name: string
+
+objects: [
+	{apiVersion: "v1", kind: "ServiceAccount", metadata: name: values.name},
+	{apiVersion: "v1", kind: "Namespace", metadata: name: values.name},
+]
+`,
+	}
+
+	got, err := expandCUETemplates(resources, map[string]interface{}{"name": "app", "replicas": 3})
+	if err != nil {
+		t.Fatalf("expandCUETemplates failed: %v", err)
+	}
+
+	want := map[string]string{
+		"Kptfile": resources["Kptfile"],
+		"configmap.yaml": `apiVersion: v1
+data:
+  replicas: "3"
+kind: ConfigMap
+metadata:
+  name: app
+`,
+		"objects.yaml": `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected expanded resources (-want, +got): %s", diff)
+	}
+}
+
+func TestExpandCUETemplatesError(t *testing.T) {
+	resources := map[string]string{
+		"configmap.cue": `values: name: string
+apiVersion: "v1"
+kind:       "ConfigMap"
+metadata: name: values.name
+`,
+	}
+
+	// The name is not provided, so the template is not concrete.
+	_, err := expandCUETemplates(resources, nil)
+	if err == nil {
+		t.Fatalf("expandCUETemplates succeeded; want error")
+	}
+	if !strings.Contains(err.Error(), "configmap.cue") {
+		t.Errorf("Error %q does not identify the template", err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"fmt"
+
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// packageRevisionFieldSelector is the field selector of a package revision
+// list request. Package revisions can be selected by equality on
+// metadata.name, metadata.namespace, spec.packageName and spec.lifecycle.
+type packageRevisionFieldSelector struct {
+	name      string
+	namespace string
+	// filter is pushed down to the repositories.
+	filter repository.ListPackageRevisionFilter
+	// matchesNothing is set if the selector requires different values of
+	// the same field.
+	matchesNothing bool
+}
+
+// parsePackageRevisionFieldSelector parses the field selector of the list
+// options. Unsupported fields and operators are rejected with 400 Bad Request.
+func parsePackageRevisionFieldSelector(options *metainternalversion.ListOptions) (packageRevisionFieldSelector, error) {
+	var result packageRevisionFieldSelector
+	if options == nil || options.FieldSelector == nil || options.FieldSelector.Empty() {
+		return result, nil
+	}
+
+	selector, err := fields.ParseSelector(options.FieldSelector.String())
+	if err != nil {
+		return result, apierrors.NewBadRequest(fmt.Sprintf("invalid field selector: %v", err))
+	}
+	for _, r := range selector.Requirements() {
+		switch r.Operator {
+		case selection.Equals, selection.DoubleEquals:
+		default:
+			return result, apierrors.NewBadRequest(fmt.Sprintf("unsupported operator %q in field selector %q; only equality is supported", r.Operator, r.Field+string(r.Operator)+r.Value))
+		}
+
+		var target *string
+		switch r.Field {
+		case "metadata.name":
+			target = &result.name
+		case "metadata.namespace":
+			target = &result.namespace
+		case "spec.packageName":
+			target = &result.filter.Package
+		case "spec.lifecycle":
+			target = (*string)(&result.filter.Lifecycle)
+		default:
+			return result, apierrors.NewBadRequest(fmt.Sprintf("unsupported field selector %q; supported fields are metadata.name, metadata.namespace, spec.packageName and spec.lifecycle", r.Field))
+		}
+		if *target != "" && *target != r.Value {
+			result.matchesNothing = true
+		}
+		*target = r.Value
+	}
+	return result, nil
+}
+
+// matchesName returns true if a package revision with the given name may
+// match the selector.
+func (s *packageRevisionFieldSelector) matchesName(name string) bool {
+	return !s.matchesNothing && (s.name == "" || s.name == name)
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+func TestParsePackageRevisionFieldSelector(t *testing.T) {
+	for _, tc := range []struct {
+		selector string
+		want     packageRevisionFieldSelector
+	}{
+		{
+			selector: "",
+			want:     packageRevisionFieldSelector{},
+		},
+		{
+			selector: "spec.lifecycle=Published",
+			want: packageRevisionFieldSelector{
+				filter: repository.ListPackageRevisionFilter{Lifecycle: api.PackageRevisionLifecyclePublished},
+			},
+		},
+		{
+			selector: "metadata.namespace=ns,spec.packageName==app,metadata.name=repo:app:v1",
+			want: packageRevisionFieldSelector{
+				name:      "repo:app:v1",
+				namespace: "ns",
+				filter:    repository.ListPackageRevisionFilter{Package: "app"},
+			},
+		},
+		{
+			selector: "spec.lifecycle=Draft,spec.lifecycle=Published",
+			want: packageRevisionFieldSelector{
+				filter:         repository.ListPackageRevisionFilter{Lifecycle: api.PackageRevisionLifecyclePublished},
+				matchesNothing: true,
+			},
+		},
+	} {
+		t.Run(tc.selector, func(t *testing.T) {
+			selector, err := fields.ParseSelector(tc.selector)
+			if err != nil {
+				t.Fatalf("ParseSelector(%q) failed: %v", tc.selector, err)
+			}
+			got, err := parsePackageRevisionFieldSelector(&metainternalversion.ListOptions{FieldSelector: selector})
+			if err != nil {
+				t.Fatalf("parsePackageRevisionFieldSelector(%q) failed: %v", tc.selector, err)
+			}
+			if got != tc.want {
+				t.Errorf("parsePackageRevisionFieldSelector(%q): got %+v, want %+v", tc.selector, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParsePackageRevisionFieldSelectorRejected(t *testing.T) {
+	for _, selector := range []string{
+		"spec.unknown=value",
+		"spec.lifecycle!=Draft",
+	} {
+		t.Run(selector, func(t *testing.T) {
+			parsed, err := fields.ParseSelector(selector)
+			if err != nil {
+				t.Fatalf("ParseSelector(%q) failed: %v", selector, err)
+			}
+			if _, err := parsePackageRevisionFieldSelector(&metainternalversion.ListOptions{FieldSelector: parsed}); !apierrors.IsBadRequest(err) {
+				t.Errorf("parsePackageRevisionFieldSelector(%q): got error %v, want 400 Bad Request", selector, err)
+			}
+		})
+	}
+}
+
+func TestPackageRevisionFieldSelectorMatchesName(t *testing.T) {
+	s := packageRevisionFieldSelector{name: "repo:app:v1"}
+	if !s.matchesName("repo:app:v1") {
+		t.Errorf("matchesName(repo:app:v1) = false, want true")
+	}
+	if s.matchesName("repo:app:v2") {
+		t.Errorf("matchesName(repo:app:v2) = true, want false")
+	}
+	s.matchesNothing = true
+	if s.matchesName("repo:app:v1") {
+		t.Errorf("matchesName(repo:app:v1) with conflicting selector = true, want false")
+	}
+}
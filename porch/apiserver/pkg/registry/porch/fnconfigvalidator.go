@@ -0,0 +1,215 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
+	kptfile "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// configSchemaURL is the URL the config schema of a function is compiled as.
+// Schemas are self-contained; references to other documents are not loaded.
+const configSchemaURL = "mem:///config-schema.json"
+
+// FunctionConfigSchemaResolver returns the JSON schema of the config of a
+// function image, or an empty string if the image declares none.
+type FunctionConfigSchemaResolver interface {
+	ResolveConfigSchema(ctx context.Context, image string) (string, error)
+}
+
+// FunctionConfigValidator validates the configMap of the functions in Kptfile
+// pipelines against the config schemas declared by the function images.
+type FunctionConfigValidator struct {
+	resolver FunctionConfigSchemaResolver
+
+	mutex sync.Mutex
+	// schemas caches the parsed schema of every image; nil if the image
+	// declares no schema.
+	schemas map[string]*jsonschema.Schema
+}
+
+func NewFunctionConfigValidator(resolver FunctionConfigSchemaResolver) *FunctionConfigValidator {
+	return &FunctionConfigValidator{
+		resolver: resolver,
+		schemas:  map[string]*jsonschema.Schema{},
+	}
+}
+
+// ValidateUpdate validates the function configs of the Kptfiles changed by
+// the update. Functions whose schema cannot be retrieved are not validated.
+func (v *FunctionConfigValidator) ValidateUpdate(ctx context.Context, newObj, oldObj *api.PackageRevisionResources) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if v == nil {
+		return allErrs
+	}
+
+	var filenames []string
+	for filename, contents := range newObj.Spec.Resources {
+		if path.Base(filename) != kptfile.KptFileName {
+			continue
+		}
+		if old, found := oldObj.Spec.Resources[filename]; found && old == contents {
+			continue
+		}
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		var kf kptfile.KptFile
+		if err := yaml.Unmarshal([]byte(newObj.Spec.Resources[filename]), &kf); err != nil {
+			// Malformed Kptfiles are reported when the package is rendered.
+			continue
+		}
+		if kf.Pipeline == nil {
+			continue
+		}
+		report := &resourceValidator{filename: filename}
+		v.validateFunctions(ctx, report, "pipeline.mutators", kf.Pipeline.Mutators)
+		v.validateFunctions(ctx, report, "pipeline.validators", kf.Pipeline.Validators)
+		allErrs = append(allErrs, validationFieldErrors(&api.PackageRevisionValidationReport{Errors: report.errors})...)
+	}
+	return allErrs
+}
+
+func (v *FunctionConfigValidator) validateFunctions(ctx context.Context, report *resourceValidator, fieldPath string, functions []kptfile.Function) {
+	for i, fn := range functions {
+		if len(fn.ConfigMap) == 0 || fn.Image == "" {
+			continue
+		}
+		schema := v.schema(ctx, fnruntime.AddDefaultImagePathPrefix(fn.Image))
+		if schema == nil {
+			continue
+		}
+		err := schema.Validate(configMapValue(fn.ConfigMap))
+		if err == nil {
+			continue
+		}
+		var verr *jsonschema.ValidationError
+		if !errors.As(err, &verr) {
+			klog.Warningf("cannot validate config of function %q: %v", fn.Image, err)
+			continue
+		}
+		configPath := fmt.Sprintf("%s[%d].configMap", fieldPath, i)
+		for _, leaf := range validationLeaves(verr) {
+			report.errorf(instancePath(configPath, leaf.InstanceLocation), "%s", leaf.Message)
+		}
+	}
+}
+
+// configMapValue returns the configMap as an object whose values are typed the
+// way a function parsing them would, e.g. "3" as an integer.
+func configMapValue(configMap map[string]string) map[string]interface{} {
+	value := make(map[string]interface{}, len(configMap))
+	for key, s := range configMap {
+		value[key] = s
+		node := &yaml.Node{Kind: yaml.ScalarNode, Value: s}
+		switch node.ShortTag() {
+		case yaml.NodeTagInt, yaml.NodeTagFloat, yaml.NodeTagBool, yaml.NodeTagNull:
+			var v interface{}
+			if err := node.Decode(&v); err == nil {
+				value[key] = v
+			}
+		}
+	}
+	return value
+}
+
+// validationLeaves returns the errors without causes, which describe the
+// individual violations, ordered by instance location.
+func validationLeaves(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	var leaves []*jsonschema.ValidationError
+	var walk func(*jsonschema.ValidationError)
+	walk = func(err *jsonschema.ValidationError) {
+		if len(err.Causes) == 0 {
+			leaves = append(leaves, err)
+		}
+		for _, cause := range err.Causes {
+			walk(cause)
+		}
+	}
+	walk(err)
+	sort.SliceStable(leaves, func(i, j int) bool {
+		return leaves[i].InstanceLocation < leaves[j].InstanceLocation
+	})
+	return leaves
+}
+
+// instancePath returns the field path of the JSON pointer to a value within
+// the config.
+func instancePath(configPath, pointer string) string {
+	p := configPath
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		p = joinFieldPath(p, token)
+	}
+	return p
+}
+
+// schema returns the compiled config schema of the image, or nil if the image
+// declares no valid schema.
+func (v *FunctionConfigValidator) schema(ctx context.Context, image string) *jsonschema.Schema {
+	v.mutex.Lock()
+	schema, found := v.schemas[image]
+	v.mutex.Unlock()
+	if found {
+		return schema
+	}
+
+	raw, err := v.resolver.ResolveConfigSchema(ctx, image)
+	if err != nil {
+		// Don't cache the failure; the registry may be reachable next time.
+		klog.Warningf("cannot get config schema of function %q: %v", image, err)
+		return nil
+	}
+	if raw != "" {
+		if schema, err = compileConfigSchema(raw); err != nil {
+			klog.Warningf("invalid config schema of function %q: %v", image, err)
+			schema = nil
+		}
+	}
+
+	v.mutex.Lock()
+	v.schemas[image] = schema
+	v.mutex.Unlock()
+	return schema
+}
+
+// compileConfigSchema compiles the JSON schema of a function config. The
+// schema is interpreted according to its $schema, or as the latest draft.
+func compileConfigSchema(raw string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("cannot load %q: references to other schemas are not supported", url)
+	}
+	if err := compiler.AddResource(configSchemaURL, strings.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(configSchemaURL)
+}
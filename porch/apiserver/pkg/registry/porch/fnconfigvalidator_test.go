@@ -0,0 +1,150 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeConfigSchemaResolver map[string]string
+
+func (r fakeConfigSchemaResolver) ResolveConfigSchema(ctx context.Context, image string) (string, error) {
+	return r[image], nil
+}
+
+func TestFunctionConfigValidator(t *testing.T) {
+	const schema = `{
+  "type": "object",
+  "properties": {
+    "namespace": {"$ref": "#/$defs/name"},
+    "replicas": {"type": "integer"},
+    "mode": {"enum": ["strict", "lenient"]}
+  },
+  "required": ["namespace"],
+  "additionalProperties": false,
+  "if": {"properties": {"mode": {"const": "strict"}}, "required": ["mode"]},
+  "then": {"required": ["replicas"]},
+  "$defs": {
+    "name": {"type": "string", "pattern": "^[a-z0-9-]+$"}
+  }
+}`
+	validator := NewFunctionConfigValidator(fakeConfigSchemaResolver{
+		"gcr.io/kpt-fn/set-namespace:v0.4": schema,
+		"gcr.io/kpt-fn/remote-ref:v0.1":    `{"$ref": "https://example.com/schema.json"}`,
+	})
+
+	newResources := func(kptfile string) *api.PackageRevisionResources {
+		return &api.PackageRevisionResources{
+			Spec: api.PackageRevisionResourcesSpec{
+				Resources: map[string]string{"Kptfile": kptfile},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		kptfile string
+		want    []string
+	}{
+		{
+			name: "valid",
+			kptfile: `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: pkg
+pipeline:
+  mutators:
+  - image: set-namespace:v0.4
+    configMap:
+      namespace: example
+      replicas: "3"
+`,
+		},
+		{
+			name: "invalid",
+			kptfile: `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: pkg
+pipeline:
+  mutators:
+  - image: gcr.io/kpt-fn/apply-setters:v0.2
+    configMap:
+      anything: goes
+  - image: gcr.io/kpt-fn/set-namespace:v0.4
+    configMap:
+      replicas: three
+      namespaces: typo
+`,
+			want: []string{
+				`spec.resources[Kptfile]: Invalid value: "pipeline.mutators[1].configMap": missing properties: 'namespace'`,
+				`spec.resources[Kptfile]: Invalid value: "pipeline.mutators[1].configMap": additionalProperties 'namespaces' not allowed`,
+				`spec.resources[Kptfile]: Invalid value: "pipeline.mutators[1].configMap.replicas": expected integer, but got string`,
+			},
+		},
+		{
+			name: "keywords beyond OpenAPI",
+			kptfile: `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: pkg
+pipeline:
+  mutators:
+  - image: gcr.io/kpt-fn/set-namespace:v0.4
+    configMap:
+      namespace: Not_A_Name
+      mode: strict
+  - image: gcr.io/kpt-fn/remote-ref:v0.1
+    configMap:
+      anything: goes
+`,
+			// Schemas referencing other documents are not used.
+			want: []string{
+				`spec.resources[Kptfile]: Invalid value: "pipeline.mutators[0].configMap": missing properties: 'replicas'`,
+				`spec.resources[Kptfile]: Invalid value: "pipeline.mutators[0].configMap.namespace": does not match pattern '^[a-z0-9-]+$'`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validator.ValidateUpdate(context.Background(), newResources(tc.kptfile), newResources(""))
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected errors (-want, +got): %s", diff)
+			}
+		})
+	}
+
+	// Unchanged Kptfiles are not validated again.
+	invalid := newResources(`apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: pkg
+pipeline:
+  mutators:
+  - image: gcr.io/kpt-fn/set-namespace:v0.4
+    configMap:
+      replicas: "3"
+`)
+	if errs := validator.ValidateUpdate(context.Background(), invalid, invalid); len(errs) != 0 {
+		t.Errorf("unexpected errors for unchanged Kptfile: %v", errs)
+	}
+}
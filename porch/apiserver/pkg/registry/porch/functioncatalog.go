@@ -0,0 +1,199 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// negativeLookupTTL is how long a failed function lookup is remembered.
+const negativeLookupTTL = 60 * time.Second
+
+var (
+	functionCatalogHits = metrics.NewCounter(&metrics.CounterOpts{
+		Namespace:      "kpt",
+		Subsystem:      "porch",
+		Name:           "function_catalog_hits_total",
+		Help:           "Number of function catalog lookups which found the function.",
+		StabilityLevel: metrics.ALPHA,
+	})
+	functionCatalogMisses = metrics.NewCounter(&metrics.CounterOpts{
+		Namespace:      "kpt",
+		Subsystem:      "porch",
+		Name:           "function_catalog_misses_total",
+		Help:           "Number of function catalog lookups which did not find the function.",
+		StabilityLevel: metrics.ALPHA,
+	})
+)
+
+func init() {
+	legacyregistry.MustRegister(functionCatalogHits, functionCatalogMisses)
+}
+
+// FunctionRegistryAggregator merges the functions discovered in all
+// repositories registered in a namespace. When several repositories provide
+// a function with the same name and version, the function from the repository
+// with the highest spec.priority is used.
+type FunctionRegistryAggregator struct {
+	cad        engine.CaDEngine
+	coreClient client.Client
+
+	mutex sync.Mutex
+	// misses records the time of failed lookups, keyed by namespace and name.
+	misses map[string]time.Time
+	now    func() time.Time
+}
+
+func NewFunctionRegistryAggregator(cad engine.CaDEngine, coreClient client.Client) *FunctionRegistryAggregator {
+	return &FunctionRegistryAggregator{
+		cad:        cad,
+		coreClient: coreClient,
+		misses:     map[string]time.Time{},
+		now:        time.Now,
+	}
+}
+
+// ListFunctions returns the merged list of functions from all repositories in
+// the namespace.
+func (a *FunctionRegistryAggregator) ListFunctions(ctx context.Context, namespace string) ([]v1alpha1.Function, error) {
+	repositories, err := a.listRepositories(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []v1alpha1.Function
+	seen := map[string]bool{}
+
+	for i := range repositories {
+		repo := &repositories[i]
+		fns, err := a.cad.ListFunctions(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repository %s functions: %w", repo.Name, err)
+		}
+		for _, f := range fns {
+			key := unqualifiedFunctionName(repo.Name, f.Name())
+			if seen[key] {
+				continue
+			}
+			api, err := f.GetFunction()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get function details %s: %w", f.Name(), err)
+			}
+			seen[key] = true
+			result = append(result, *api)
+		}
+	}
+
+	return result, nil
+}
+
+// GetFunction returns the function with the given name, in the format
+// <repository>:<function name>:<version>. Functions which are not found are
+// remembered for negativeLookupTTL, during which they are not looked up again.
+func (a *FunctionRegistryAggregator) GetFunction(ctx context.Context, namespace, name string) (*v1alpha1.Function, error) {
+	fn, err := parseFunctionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	key := namespace + "/" + name
+	if a.isRecentMiss(key) {
+		functionCatalogMisses.Inc()
+		return nil, fmt.Errorf("function %s not found", name)
+	}
+
+	repositoryKey := client.ObjectKey{Namespace: namespace, Name: fn.repository}
+	var repository configapi.Repository
+	if err := a.coreClient.Get(ctx, repositoryKey, &repository); err != nil {
+		return nil, fmt.Errorf("cannot find repository %q", repositoryKey)
+	}
+
+	// TODO: implement get to avoid listing
+	fns, err := a.cad.ListFunctions(ctx, &repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository %s functions: %w", repository.Name, err)
+	}
+	for _, f := range fns {
+		if f.Name() == name {
+			functionCatalogHits.Inc()
+			return f.GetFunction()
+		}
+	}
+
+	a.recordMiss(key)
+	functionCatalogMisses.Inc()
+	return nil, fmt.Errorf("function %s not found", name)
+}
+
+// listRepositories lists the registered repositories, ordered by descending
+// priority.
+func (a *FunctionRegistryAggregator) listRepositories(ctx context.Context, namespace string) ([]configapi.Repository, error) {
+	var opts []client.ListOption
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	var repositories configapi.RepositoryList
+	if err := a.coreClient.List(ctx, &repositories, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list registered repositories: %w", err)
+	}
+
+	result := repositories.Items
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Spec.Priority > result[j].Spec.Priority
+	})
+	return result, nil
+}
+
+func (a *FunctionRegistryAggregator) isRecentMiss(key string) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	missed, ok := a.misses[key]
+	if !ok {
+		return false
+	}
+	if a.now().Sub(missed) >= negativeLookupTTL {
+		delete(a.misses, key)
+		return false
+	}
+	return true
+}
+
+func (a *FunctionRegistryAggregator) recordMiss(key string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.misses[key] = a.now()
+}
+
+// unqualifiedFunctionName strips the repository name from the function name
+// (<repository>:<function>:<version>) so that functions can be compared
+// across repositories.
+func unqualifiedFunctionName(repository, name string) string {
+	return strings.TrimPrefix(name, repository+":")
+}
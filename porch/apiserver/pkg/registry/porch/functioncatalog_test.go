@@ -0,0 +1,198 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeFunction struct {
+	name  string
+	image string
+}
+
+var _ repository.Function = &fakeFunction{}
+
+func (f *fakeFunction) Name() string {
+	return f.name
+}
+
+func (f *fakeFunction) GetFunction() (*v1alpha1.Function, error) {
+	return &v1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: f.name},
+		Spec:       v1alpha1.FunctionSpec{Image: f.image},
+	}, nil
+}
+
+// fakeFunctionEngine lists the functions of repositories by repository name,
+// and counts the repositories listed.
+type fakeFunctionEngine struct {
+	engine.CaDEngine
+
+	functions map[string][]repository.Function
+	listed    int
+}
+
+func (e *fakeFunctionEngine) ListFunctions(ctx context.Context, repositoryObj *configapi.Repository) ([]repository.Function, error) {
+	e.listed++
+	return e.functions[repositoryObj.Name], nil
+}
+
+func newTestFunctionRepository(name string, priority int) client.Object {
+	return &configapi.Repository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "porch", Name: name},
+		Spec: configapi.RepositorySpec{
+			Content:  configapi.RepositoryContentFunction,
+			Priority: priority,
+		},
+	}
+}
+
+func newTestFunctionRegistryAggregator(t *testing.T, cad *fakeFunctionEngine, objs ...client.Object) *FunctionRegistryAggregator {
+	scheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme failed: %v", err)
+	}
+	coreClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return NewFunctionRegistryAggregator(cad, coreClient)
+}
+
+// counterValue returns the value of the counter registered with the name.
+func counterValue(t *testing.T, name string) float64 {
+	families, err := legacyregistry.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestFunctionRegistryAggregatorListFunctions(t *testing.T) {
+	cad := &fakeFunctionEngine{
+		functions: map[string][]repository.Function{
+			"mirror": {
+				&fakeFunction{name: "mirror:set-labels:v0.1", image: "mirror.example.com/set-labels:v0.1"},
+				&fakeFunction{name: "mirror:set-namespace:v0.2", image: "mirror.example.com/set-namespace:v0.2"},
+			},
+			"upstream": {
+				&fakeFunction{name: "upstream:set-labels:v0.1", image: "gcr.io/kpt-fn/set-labels:v0.1"},
+				&fakeFunction{name: "upstream:apply-setters:v0.2", image: "gcr.io/kpt-fn/apply-setters:v0.2"},
+			},
+			"blueprints": {
+				&fakeFunction{name: "blueprints:starlark:v0.4", image: "gcr.io/kpt-fn/starlark:v0.4"},
+			},
+		},
+	}
+	blueprints := &configapi.Repository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "porch", Name: "blueprints"},
+	}
+	a := newTestFunctionRegistryAggregator(t, cad,
+		newTestFunctionRepository("upstream", 0),
+		newTestFunctionRepository("mirror", 10),
+		blueprints,
+	)
+
+	fns, err := a.ListFunctions(context.Background(), "porch")
+	if err != nil {
+		t.Fatalf("ListFunctions failed: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, fn := range fns {
+		got[fn.Name] = fn.Spec.Image
+	}
+	// The higher priority mirror shadows set-labels:v0.1 of upstream, and
+	// repositories without content type are listed too.
+	want := map[string]string{
+		"mirror:set-labels:v0.1":      "mirror.example.com/set-labels:v0.1",
+		"mirror:set-namespace:v0.2":   "mirror.example.com/set-namespace:v0.2",
+		"upstream:apply-setters:v0.2": "gcr.io/kpt-fn/apply-setters:v0.2",
+		"blueprints:starlark:v0.4":    "gcr.io/kpt-fn/starlark:v0.4",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListFunctions (-want, +got): %s", diff)
+	}
+}
+
+func TestFunctionRegistryAggregatorGetFunction(t *testing.T) {
+	ctx := context.Background()
+	cad := &fakeFunctionEngine{
+		functions: map[string][]repository.Function{
+			"upstream": {
+				&fakeFunction{name: "upstream:set-labels:v0.1", image: "gcr.io/kpt-fn/set-labels:v0.1"},
+			},
+		},
+	}
+	a := newTestFunctionRegistryAggregator(t, cad, newTestFunctionRepository("upstream", 0))
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+
+	hits := counterValue(t, "kpt_porch_function_catalog_hits_total")
+	misses := counterValue(t, "kpt_porch_function_catalog_misses_total")
+
+	fn, err := a.GetFunction(ctx, "porch", "upstream:set-labels:v0.1")
+	if err != nil {
+		t.Fatalf("GetFunction failed: %v", err)
+	}
+	if got, want := fn.Spec.Image, "gcr.io/kpt-fn/set-labels:v0.1"; got != want {
+		t.Errorf("GetFunction image: got %q, want %q", got, want)
+	}
+	if got, want := counterValue(t, "kpt_porch_function_catalog_hits_total")-hits, 1.0; got != want {
+		t.Errorf("function catalog hits: got %v, want %v", got, want)
+	}
+
+	// A missing function is looked up once, then served from the negative
+	// cache until it expires.
+	for i := 0; i < 2; i++ {
+		if _, err := a.GetFunction(ctx, "porch", "upstream:set-labels:v0.2"); err == nil {
+			t.Errorf("GetFunction of missing function succeeded")
+		}
+	}
+	if got, want := cad.listed, 2; got != want {
+		t.Errorf("repositories listed while cached: got %d, want %d", got, want)
+	}
+	if got, want := counterValue(t, "kpt_porch_function_catalog_misses_total")-misses, 2.0; got != want {
+		t.Errorf("function catalog misses: got %v, want %v", got, want)
+	}
+
+	now = now.Add(negativeLookupTTL)
+	if _, err := a.GetFunction(ctx, "porch", "upstream:set-labels:v0.2"); err == nil {
+		t.Errorf("GetFunction of missing function succeeded")
+	}
+	if got, want := cad.listed, 3; got != want {
+		t.Errorf("repositories listed after expiry: got %d, want %d", got, want)
+	}
+
+	if _, err := a.GetFunction(ctx, "porch", "set-labels"); err == nil {
+		t.Errorf("GetFunction of malformed name succeeded")
+	}
+}
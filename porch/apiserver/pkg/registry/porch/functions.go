@@ -21,7 +21,6 @@ import (
 	"strings"
 
 	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
-	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
 	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +35,7 @@ type functions struct {
 
 	cad        engine.CaDEngine
 	coreClient client.Client
+	catalog    *FunctionRegistryAggregator
 }
 
 var _ rest.Storage = &functions{}
@@ -57,68 +57,24 @@ func (f *functions) NewList() runtime.Object {
 
 // List selects resources in the storage which match to the selector. 'options' can be nil.
 func (f *functions) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
-	var opts []client.ListOption
-	if ns, ok := request.NamespaceFrom(ctx); ok {
-		opts = append(opts, client.InNamespace(ns))
-	}
-
-	var repositories configapi.RepositoryList
-	if err := f.coreClient.List(ctx, &repositories, opts...); err != nil {
-		return nil, fmt.Errorf("failed to list registered repositories: %w", err)
-	}
+	ns, _ := request.NamespaceFrom(ctx)
 
-	result := &v1alpha1.FunctionList{}
-
-	for i := range repositories.Items {
-		repo := &repositories.Items[i]
-		fns, err := f.cad.ListFunctions(ctx, repo)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list repository %s functions: %w", repositories.Items[i].Name, err)
-		}
-		for _, f := range fns {
-			api, err := f.GetFunction()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get function details %s: %w", f.Name(), err)
-			}
-			result.Items = append(result.Items, *api)
-		}
+	fns, err := f.catalog.ListFunctions(ctx, ns)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return &v1alpha1.FunctionList{
+		Items: fns,
+	}, nil
 }
 
 func (f *functions) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
-	var repositoryKey client.ObjectKey
-	if ns, ok := request.NamespaceFrom(ctx); !ok {
+	ns, ok := request.NamespaceFrom(ctx)
+	if !ok {
 		return nil, errors.New("namespace is required")
-	} else {
-		repositoryKey.Namespace = ns
-	}
-
-	if fn, err := parseFunctionName(name); err != nil {
-		return nil, err
-	} else {
-		repositoryKey.Name = fn.repository
 	}
-
-	var repository configapi.Repository
-	if err := f.coreClient.Get(ctx, repositoryKey, &repository); err != nil {
-		return nil, fmt.Errorf("cannot find repository %q", repositoryKey)
-	}
-
-	// TODO: implement get to avoid listing
-	fns, err := f.cad.ListFunctions(ctx, &repository)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list repository %s functions: %w", repository.Name, err)
-	}
-
-	for _, f := range fns {
-		if f.Name() == name {
-			return f.GetFunction()
-		}
-	}
-
-	return nil, fmt.Errorf("function %s not found", name)
+	return f.catalog.GetFunction(ctx, ns, name)
 }
 
 type functionName struct {
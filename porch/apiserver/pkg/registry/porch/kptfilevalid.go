@@ -0,0 +1,196 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	kptfile "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// conditionKptfileValid is set on package revisions being updated to
+	// report whether their Kptfile passes validation.
+	conditionKptfileValid = "KptfileValid"
+
+	// maxKptfileDescriptionLength bounds the length of info.description, which
+	// must be shorter.
+	maxKptfileDescriptionLength = 1000
+)
+
+// hexPattern matches the commit SHAs of upstream locks.
+var hexPattern = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+// kptfileViolation is a reason the Kptfile of a package is invalid.
+type kptfileViolation struct {
+	reason  string
+	message string
+}
+
+// validateKptfile returns the violations of the Kptfile: unparseable function
+// images, duplicate function names, an upstream lock commit which is not a
+// hex string and an overlong description.
+func validateKptfile(kf *kptfile.KptFile) []kptfileViolation {
+	var violations []kptfileViolation
+
+	if kf.Pipeline != nil {
+		names := map[string]bool{}
+		functions := append(append([]kptfile.Function{}, kf.Pipeline.Mutators...), kf.Pipeline.Validators...)
+		for _, fn := range functions {
+			if fn.Image != "" {
+				if _, err := name.ParseReference(fn.Image); err != nil {
+					violations = append(violations, kptfileViolation{
+						reason:  "InvalidFunctionImage",
+						message: fmt.Sprintf("function image %q cannot be parsed: %v", fn.Image, err),
+					})
+				}
+			}
+			if fn.Name == "" {
+				continue
+			}
+			if names[fn.Name] {
+				violations = append(violations, kptfileViolation{
+					reason:  "DuplicateFunctionName",
+					message: fmt.Sprintf("function name %q is not unique in the pipeline", fn.Name),
+				})
+			}
+			names[fn.Name] = true
+		}
+	}
+
+	if kf.UpstreamLock != nil && kf.UpstreamLock.Git != nil {
+		if commit := kf.UpstreamLock.Git.Commit; commit != "" && !hexPattern.MatchString(commit) {
+			violations = append(violations, kptfileViolation{
+				reason:  "InvalidUpstreamLockCommit",
+				message: fmt.Sprintf("upstream lock commit %q is not a hex string", commit),
+			})
+		}
+	}
+
+	if kf.Info != nil && len(kf.Info.Description) >= maxKptfileDescriptionLength {
+		violations = append(violations, kptfileViolation{
+			reason:  "DescriptionTooLong",
+			message: fmt.Sprintf("info.description is %d characters long; must be under %d", len(kf.Info.Description), maxKptfileDescriptionLength),
+		})
+	}
+
+	return violations
+}
+
+// kptfileValidCondition returns the KptfileValid condition of a package with
+// the given resources, and false if the package has no root Kptfile.
+func kptfileValidCondition(resources map[string]string, generation int64) (metav1.Condition, bool) {
+	contents, found := resources[kptfile.KptFileName]
+	if !found {
+		return metav1.Condition{}, false
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionKptfileValid,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "Valid",
+		Message:            "Kptfile is valid",
+	}
+
+	var kf kptfile.KptFile
+	if err := yaml.Unmarshal([]byte(contents), &kf); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "MalformedKptfile"
+		condition.Message = fmt.Sprintf("Kptfile cannot be parsed: %v", err)
+		return condition, true
+	}
+
+	if violations := validateKptfile(&kf); len(violations) > 0 {
+		messages := make([]string, len(violations))
+		for i, v := range violations {
+			messages[i] = v.message
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = violations[0].reason
+		condition.Message = strings.Join(messages, "; ")
+	}
+	return condition, true
+}
+
+// kptfileValidationStrategy sets the KptfileValid condition of package
+// revisions being updated from their stored Kptfile, and rejects spec changes
+// of package revisions with an invalid Kptfile, as those re-render the package.
+type kptfileValidationStrategy struct {
+	NoopUpdateStrategy
+
+	getPackage func(ctx context.Context, name string) (repository.PackageRevision, error)
+}
+
+var _ SimpleRESTUpdateStrategy = kptfileValidationStrategy{}
+
+func (s kptfileValidationStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	pr := obj.(*api.PackageRevision)
+
+	pkg, err := s.getPackage(ctx, pr.Name)
+	if err != nil {
+		klog.Warningf("cannot validate the Kptfile of package revision %s: %v", pr.Name, err)
+		return
+	}
+	resources, err := pkg.GetResources(ctx)
+	if err != nil {
+		klog.Warningf("cannot validate the Kptfile of package revision %s: %v", pr.Name, err)
+		return
+	}
+	if condition, found := kptfileValidCondition(resources.Spec.Resources, pr.Generation); found {
+		meta.SetStatusCondition(&pr.Status.Conditions, condition)
+	}
+}
+
+func (s kptfileValidationStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	newRevision := obj.(*api.PackageRevision)
+	oldRevision := old.(*api.PackageRevision)
+
+	if newRevision.Generation == oldRevision.Generation {
+		return nil
+	}
+	condition := meta.FindStatusCondition(newRevision.Status.Conditions, conditionKptfileValid)
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(field.NewPath("status", "conditions").Key(conditionKptfileValid), condition.Reason,
+		fmt.Sprintf("cannot render a package revision with an invalid Kptfile: %s", condition.Message))}
+}
+
+// validateKptfileResources rejects resource updates which make the root
+// Kptfile of the package invalid, as the update renders the package.
+func validateKptfileResources(newObj, oldObj *api.PackageRevisionResources) field.ErrorList {
+	contents := newObj.Spec.Resources[kptfile.KptFileName]
+	if old, found := oldObj.Spec.Resources[kptfile.KptFileName]; found && old == contents {
+		return nil
+	}
+	condition, found := kptfileValidCondition(newObj.Spec.Resources, newObj.Generation)
+	if !found || condition.Status != metav1.ConditionFalse {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(field.NewPath("spec", "resources").Key(kptfile.KptFileName), condition.Reason, condition.Message)}
+}
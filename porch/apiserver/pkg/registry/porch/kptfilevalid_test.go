@@ -0,0 +1,185 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	kptfile "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const validKptfile = `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: example
+info:
+  description: An example package
+upstreamLock:
+  type: git
+  git:
+    repo: https://github.com/GoogleContainerTools/kpt
+    commit: 0123456789abcdef0123456789abcdef01234567
+pipeline:
+  mutators:
+  - name: set-labels
+    image: gcr.io/kpt-fn/set-labels:v0.1
+  validators:
+  - name: kubeval
+    image: gcr.io/kpt-fn/kubeval:v0.3
+`
+
+func TestKptfileValidCondition(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		kptfile    string
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "Valid",
+			kptfile:    validKptfile,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: "Valid",
+		},
+		{
+			name:       "InvalidImage",
+			kptfile:    strings.Replace(validKptfile, "gcr.io/kpt-fn/set-labels:v0.1", "gcr.io/kpt-fn/Set Labels", 1),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "InvalidFunctionImage",
+		},
+		{
+			name:       "DuplicateName",
+			kptfile:    strings.Replace(validKptfile, "name: kubeval", "name: set-labels", 1),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "DuplicateFunctionName",
+		},
+		{
+			name:       "InvalidCommit",
+			kptfile:    strings.Replace(validKptfile, "0123456789abcdef0123456789abcdef01234567", "main", 1),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "InvalidUpstreamLockCommit",
+		},
+		{
+			name:       "LongDescription",
+			kptfile:    strings.Replace(validKptfile, "An example package", strings.Repeat("a", maxKptfileDescriptionLength+1), 1),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "DescriptionTooLong",
+		},
+		{
+			name:       "Malformed",
+			kptfile:    "pipeline: [",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "MalformedKptfile",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			condition, found := kptfileValidCondition(map[string]string{kptfile.KptFileName: tc.kptfile}, 3)
+			if !found {
+				t.Fatalf("expected the KptfileValid condition")
+			}
+			if condition.Status != tc.wantStatus || condition.Reason != tc.wantReason {
+				t.Errorf("got condition %s/%s (%s), want %s/%s", condition.Status, condition.Reason, condition.Message, tc.wantStatus, tc.wantReason)
+			}
+			if condition.ObservedGeneration != 3 {
+				t.Errorf("got observed generation %d, want 3", condition.ObservedGeneration)
+			}
+		})
+	}
+
+	if _, found := kptfileValidCondition(map[string]string{"README.md": "# example"}, 1); found {
+		t.Errorf("expected no condition for a package without a Kptfile")
+	}
+}
+
+type fakeKptfilePackage struct {
+	repository.PackageRevision
+	kptfile string
+}
+
+func (p *fakeKptfilePackage) GetResources(ctx context.Context) (*api.PackageRevisionResources, error) {
+	return &api.PackageRevisionResources{
+		Spec: api.PackageRevisionResourcesSpec{
+			Resources: map[string]string{kptfile.KptFileName: p.kptfile},
+		},
+	}, nil
+}
+
+func TestKptfileValidationStrategy(t *testing.T) {
+	invalid := strings.Replace(validKptfile, "name: kubeval", "name: set-labels", 1)
+
+	for _, tc := range []struct {
+		name        string
+		kptfile     string
+		specChanged bool
+		wantStatus  metav1.ConditionStatus
+		wantErrors  int
+	}{
+		{name: "ValidSpecChange", kptfile: validKptfile, specChanged: true, wantStatus: metav1.ConditionTrue},
+		{name: "InvalidSpecChange", kptfile: invalid, specChanged: true, wantStatus: metav1.ConditionFalse, wantErrors: 1},
+		{name: "InvalidStatusChange", kptfile: invalid, wantStatus: metav1.ConditionFalse},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := kptfileValidationStrategy{
+				getPackage: func(ctx context.Context, name string) (repository.PackageRevision, error) {
+					return &fakeKptfilePackage{kptfile: tc.kptfile}, nil
+				},
+			}
+			old := &api.PackageRevision{ObjectMeta: metav1.ObjectMeta{Name: "repo-1234", Generation: 1}}
+			pr := old.DeepCopy()
+			if tc.specChanged {
+				pr.Generation++
+			}
+
+			s.PrepareForUpdate(context.Background(), pr, old)
+			condition := meta.FindStatusCondition(pr.Status.Conditions, conditionKptfileValid)
+			if condition == nil {
+				t.Fatalf("expected the KptfileValid condition to be set")
+			}
+			if condition.Status != tc.wantStatus {
+				t.Errorf("got condition status %s, want %s", condition.Status, tc.wantStatus)
+			}
+			if errs := s.ValidateUpdate(context.Background(), pr, old); len(errs) != tc.wantErrors {
+				t.Errorf("got %d validation errors, want %d: %v", len(errs), tc.wantErrors, errs)
+			}
+		})
+	}
+}
+
+func TestValidateKptfileResources(t *testing.T) {
+	invalid := strings.Replace(validKptfile, "0123456789abcdef0123456789abcdef01234567", "main", 1)
+	resources := func(kf string) *api.PackageRevisionResources {
+		return &api.PackageRevisionResources{
+			Spec: api.PackageRevisionResourcesSpec{
+				Resources: map[string]string{kptfile.KptFileName: kf},
+			},
+		}
+	}
+
+	if errs := validateKptfileResources(resources(validKptfile), resources(invalid)); len(errs) != 0 {
+		t.Errorf("expected a valid Kptfile to be accepted, got %v", errs)
+	}
+	if errs := validateKptfileResources(resources(invalid), resources(validKptfile)); len(errs) != 1 {
+		t.Errorf("expected an invalid Kptfile to be rejected, got %v", errs)
+	}
+	if errs := validateKptfileResources(resources(invalid), resources(invalid)); len(errs) != 0 {
+		t.Errorf("expected an unchanged Kptfile to be accepted, got %v", errs)
+	}
+}
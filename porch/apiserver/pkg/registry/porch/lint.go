@@ -0,0 +1,344 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// defaultLintPolicy is the name of the LintPolicy used when the request
+// doesn't name one.
+const defaultLintPolicy = "default"
+
+// linter checks a single resource of a package for a best-practice violation.
+type linter struct {
+	// name identifies the linter in findings and in LintPolicy.
+	name string
+	// severity is the severity of the findings unless overridden by LintPolicy.
+	severity configapi.LintSeverity
+	// lint returns a message for each violation found in the resource.
+	lint func(n *yaml.RNode) []string
+}
+
+// builtinLinters are the linters run by the lint subresource.
+var builtinLinters = []linter{
+	{name: "NoLatestTag", severity: configapi.LintSeverityWarning, lint: lintNoLatestTag},
+	{name: "ResourceLimitsRequired", severity: configapi.LintSeverityWarning, lint: lintResourceLimitsRequired},
+	{name: "NoPrivilegedContainers", severity: configapi.LintSeverityError, lint: lintNoPrivilegedContainers},
+	{name: "LabelsSufficient", severity: configapi.LintSeverityWarning, lint: lintLabelsSufficient},
+}
+
+// requiredLabels are the labels checked by the LabelsSufficient linter.
+var requiredLabels = []string{"app", "version"}
+
+// packageRevisionsLint runs best-practice linters against the resources of a
+// package revision.
+type packageRevisionsLint struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageRevisionsLint{}
+var _ rest.Scoper = &packageRevisionsLint{}
+var _ rest.NamedCreater = &packageRevisionsLint{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (l *packageRevisionsLint) New() runtime.Object {
+	return &api.LintReport{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (l *packageRevisionsLint) NamespaceScoped() bool {
+	return true
+}
+
+// Create lints the resources of the named package revision with the
+// severities of the LintPolicy named by the posted LintReport.
+func (l *packageRevisionsLint) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
+	if !namespaced {
+		return nil, apierrors.NewBadRequest("namespace must be specified")
+	}
+
+	request, ok := obj.(*api.LintReport)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected LintReport object, got %T", obj))
+	}
+	policyName := request.Policy
+	if policyName == "" {
+		policyName = defaultLintPolicy
+	}
+
+	var policy *configapi.LintPolicy
+	var lintPolicy configapi.LintPolicy
+	policyID := types.NamespacedName{Namespace: ns, Name: policyName}
+	if err := l.common.coreClient.Get(ctx, policyID, &lintPolicy); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, apierrors.NewInternalError(fmt.Errorf("error getting lint policy %v: %w", policyID, err))
+		}
+		// The default policy is optional.
+		if request.Policy != "" {
+			return nil, apierrors.NewNotFound(configapi.KindLintPolicy.GroupResource(), policyName)
+		}
+	} else {
+		policy = &lintPolicy
+	}
+
+	severities, err := lintSeverities(policy)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid lint policy %q: %v", policyName, err))
+	}
+
+	pkg, err := l.common.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := pkg.GetResources(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	report, err := lintPackageResources(resources.Spec.Resources, severities)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	report.ObjectMeta = metav1.ObjectMeta{
+		Name:              resources.Name,
+		Namespace:         resources.Namespace,
+		UID:               resources.UID,
+		ResourceVersion:   resources.ResourceVersion,
+		CreationTimestamp: resources.CreationTimestamp,
+	}
+	if policy != nil {
+		report.Policy = policyName
+	}
+	return report, nil
+}
+
+// lintSeverities returns the severity of each built-in linter, applying the
+// overrides of the policy, if any.
+func lintSeverities(policy *configapi.LintPolicy) (map[string]configapi.LintSeverity, error) {
+	severities := map[string]configapi.LintSeverity{}
+	for _, l := range builtinLinters {
+		severities[l.name] = l.severity
+	}
+	if policy == nil {
+		return severities, nil
+	}
+	for _, c := range policy.Spec.Linters {
+		if _, found := severities[c.Name]; !found {
+			return nil, fmt.Errorf("unknown linter %q", c.Name)
+		}
+		switch c.Severity {
+		case configapi.LintSeverityWarning, configapi.LintSeverityError:
+			severities[c.Name] = c.Severity
+		default:
+			return nil, fmt.Errorf("invalid severity %q for linter %q", c.Severity, c.Name)
+		}
+	}
+	return severities, nil
+}
+
+// lintPackageResources runs the built-in linters against the KRM resources in
+// the package YAML files. The Kptfile and local config resources are skipped.
+func lintPackageResources(resources map[string]string, severities map[string]configapi.LintSeverity) (*api.LintReport, error) {
+	filenames := make([]string, 0, len(resources))
+	for filename := range resources {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	report := &api.LintReport{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "LintReport",
+			APIVersion: api.SchemeGroupVersion.Identifier(),
+		},
+		Passed: true,
+	}
+	for _, filename := range filenames {
+		if !isYAMLResourceFile(filename) {
+			continue
+		}
+		nodes, err := (&kio.ByteReader{
+			Reader:                strings.NewReader(resources[filename]),
+			OmitReaderAnnotations: true,
+		}).Read()
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse resources in %q: %w", filename, err)
+		}
+		for _, n := range nodes {
+			if n.GetKind() == "Kptfile" || n.GetAnnotations()[filters.LocalConfigAnnotation] == "true" {
+				continue
+			}
+			resource := n.GetKind() + "/" + n.GetName()
+			for _, l := range builtinLinters {
+				severity := severities[l.name]
+				for _, message := range l.lint(n) {
+					report.Findings = append(report.Findings, api.LintFinding{
+						Linter:   l.name,
+						Severity: string(severity),
+						Filename: filename,
+						Resource: resource,
+						Message:  message,
+					})
+					if severity == configapi.LintSeverityError {
+						report.Passed = false
+					}
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+// podSpec returns the pod spec of a Pod or of the pod template of a workload
+// resource, or nil if the resource has no pod spec.
+func podSpec(n *yaml.RNode) *yaml.RNode {
+	var path []string
+	switch n.GetKind() {
+	case "Pod":
+		path = []string{"spec"}
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		path = []string{"spec", "template", "spec"}
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil
+	}
+	spec, err := n.Pipe(yaml.Lookup(path...))
+	if err != nil {
+		return nil
+	}
+	return spec
+}
+
+// forEachContainer calls fn for each container and init container of the pod
+// spec of the resource.
+func forEachContainer(n *yaml.RNode, fn func(container *yaml.RNode)) {
+	spec := podSpec(n)
+	if spec == nil {
+		return
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, err := spec.Pipe(yaml.Lookup(field))
+		if err != nil || containers == nil {
+			continue
+		}
+		elements, err := containers.Elements()
+		if err != nil {
+			continue
+		}
+		for _, container := range elements {
+			fn(container)
+		}
+	}
+}
+
+// containerName returns the name of the container for use in messages.
+func containerName(container *yaml.RNode) string {
+	name, err := container.Pipe(yaml.Lookup("name"))
+	if err != nil || name == nil {
+		return ""
+	}
+	return yaml.GetValue(name)
+}
+
+// lookupValue returns the scalar value at the path in the node, or "" if not set.
+func lookupValue(n *yaml.RNode, path ...string) string {
+	value, err := n.Pipe(yaml.Lookup(path...))
+	if err != nil || value == nil {
+		return ""
+	}
+	return yaml.GetValue(value)
+}
+
+// lintNoLatestTag reports container images which are tagged `latest` or have
+// neither a tag nor a digest.
+func lintNoLatestTag(n *yaml.RNode) []string {
+	var messages []string
+	forEachContainer(n, func(container *yaml.RNode) {
+		image := lookupValue(container, "image")
+		if image == "" || strings.Contains(image, "@") {
+			return
+		}
+		tag := ""
+		repository := image[strings.LastIndex(image, "/")+1:]
+		if i := strings.LastIndex(repository, ":"); i >= 0 {
+			tag = repository[i+1:]
+		}
+		if tag == "" || tag == "latest" {
+			messages = append(messages, fmt.Sprintf("container %q uses image %q without a pinned tag", containerName(container), image))
+		}
+	})
+	return messages
+}
+
+// lintResourceLimitsRequired reports containers without CPU or memory limits.
+func lintResourceLimitsRequired(n *yaml.RNode) []string {
+	var messages []string
+	forEachContainer(n, func(container *yaml.RNode) {
+		var missing []string
+		for _, resource := range []string{"cpu", "memory"} {
+			if lookupValue(container, "resources", "limits", resource) == "" {
+				missing = append(missing, resource)
+			}
+		}
+		if len(missing) > 0 {
+			messages = append(messages, fmt.Sprintf("container %q has no %s limit", containerName(container), strings.Join(missing, " or ")))
+		}
+	})
+	return messages
+}
+
+// lintNoPrivilegedContainers reports containers running in privileged mode.
+func lintNoPrivilegedContainers(n *yaml.RNode) []string {
+	var messages []string
+	forEachContainer(n, func(container *yaml.RNode) {
+		if lookupValue(container, "securityContext", "privileged") == "true" {
+			messages = append(messages, fmt.Sprintf("container %q is privileged", containerName(container)))
+		}
+	})
+	return messages
+}
+
+// lintLabelsSufficient reports resources missing any of the required labels.
+func lintLabelsSufficient(n *yaml.RNode) []string {
+	labels := n.GetLabels()
+	var missing []string
+	for _, label := range requiredLabels {
+		if _, found := labels[label]; !found {
+			missing = append(missing, fmt.Sprintf("%q", label))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("resource is missing the %s label(s)", strings.Join(missing, ", "))}
+}
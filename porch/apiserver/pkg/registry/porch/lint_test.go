@@ -0,0 +1,133 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+)
+
+const lintTestResources = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx:latest
+        resources:
+          limits:
+            cpu: 500m
+      - name: sidecar
+        image: gcr.io/example/envoy:v1.2
+        securityContext:
+          privileged: true
+        resources:
+          limits:
+            cpu: 100m
+            memory: 64Mi
+`
+
+func TestLintPackageResources(t *testing.T) {
+	severities, err := lintSeverities(nil)
+	if err != nil {
+		t.Fatalf("lintSeverities failed: %v", err)
+	}
+
+	report, err := lintPackageResources(map[string]string{
+		"Kptfile": `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: app
+`,
+		"deployment.yaml": lintTestResources,
+		"setters.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: setters
+  annotations:
+    config.kubernetes.io/local-config: "true"
+`,
+		"README.md": "# app",
+	}, severities)
+	if err != nil {
+		t.Fatalf("lintPackageResources failed: %v", err)
+	}
+
+	want := []api.LintFinding{
+		{Linter: "NoLatestTag", Severity: "warning", Filename: "deployment.yaml", Resource: "Deployment/app", Message: `container "app" uses image "nginx:latest" without a pinned tag`},
+		{Linter: "ResourceLimitsRequired", Severity: "warning", Filename: "deployment.yaml", Resource: "Deployment/app", Message: `container "app" has no memory limit`},
+		{Linter: "NoPrivilegedContainers", Severity: "error", Filename: "deployment.yaml", Resource: "Deployment/app", Message: `container "sidecar" is privileged`},
+		{Linter: "LabelsSufficient", Severity: "warning", Filename: "deployment.yaml", Resource: "Deployment/app", Message: `resource is missing the "version" label(s)`},
+	}
+	if diff := cmp.Diff(want, report.Findings); diff != "" {
+		t.Errorf("unexpected findings (-want, +got): %s", diff)
+	}
+	if report.Passed {
+		t.Errorf("report passed despite a privileged container")
+	}
+}
+
+func TestLintPolicyOverridesSeverity(t *testing.T) {
+	severities, err := lintSeverities(&configapi.LintPolicy{
+		Spec: configapi.LintPolicySpec{
+			Linters: []configapi.LinterConfig{
+				{Name: "NoPrivilegedContainers", Severity: configapi.LintSeverityWarning},
+				{Name: "NoLatestTag", Severity: configapi.LintSeverityError},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("lintSeverities failed: %v", err)
+	}
+
+	report, err := lintPackageResources(map[string]string{
+		"deployment.yaml": lintTestResources,
+	}, severities)
+	if err != nil {
+		t.Fatalf("lintPackageResources failed: %v", err)
+	}
+	for _, finding := range report.Findings {
+		want := "warning"
+		if finding.Linter == "NoLatestTag" {
+			want = "error"
+		}
+		if finding.Severity != want {
+			t.Errorf("%s finding has severity %q, want %q", finding.Linter, finding.Severity, want)
+		}
+	}
+	if report.Passed {
+		t.Errorf("report passed despite an error finding")
+	}
+}
+
+func TestLintPolicyRejectsInvalidConfig(t *testing.T) {
+	for _, c := range []configapi.LinterConfig{
+		{Name: "NoSuchLinter", Severity: configapi.LintSeverityError},
+		{Name: "NoLatestTag", Severity: "fatal"},
+	} {
+		policy := &configapi.LintPolicy{Spec: configapi.LintPolicySpec{Linters: []configapi.LinterConfig{c}}}
+		if _, err := lintSeverities(policy); err == nil {
+			t.Errorf("lintSeverities accepted linter config %+v", c)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// pagerDutyRoutingKey is the key of the PagerDuty integration key in the
+	// notification secret.
+	pagerDutyRoutingKey = "routingKey"
+
+	NotificationTypeLifecycleTransition = "LifecycleTransition"
+	NotificationTypeRenderFailed        = "RenderFailed"
+)
+
+// NotificationEvent is the body posted to generic notification webhooks.
+type NotificationEvent struct {
+	// Type is LifecycleTransition or RenderFailed.
+	Type            string                       `json:"type"`
+	Config          string                       `json:"config"`
+	Namespace       string                       `json:"namespace"`
+	PackageRevision string                       `json:"packageRevision"`
+	Repository      string                       `json:"repository"`
+	Package         string                       `json:"package"`
+	Revision        string                       `json:"revision"`
+	Lifecycle       api.PackageRevisionLifecycle `json:"lifecycle"`
+	From            api.PackageRevisionLifecycle `json:"from,omitempty"`
+	// User is the user who made the change, for example the approver of a
+	// package revision.
+	User  string `json:"user,omitempty"`
+	Error string `json:"error,omitempty"`
+	Link  string `json:"link,omitempty"`
+}
+
+// NotificationDispatcher sends the notifications configured by the
+// NotificationConfigs matching the lifecycle transitions and render failures
+// of package revisions.
+type NotificationDispatcher struct {
+	coreClient         client.Reader
+	credentialResolver repository.CredentialResolver
+	httpClient         *http.Client
+	sleep              func(time.Duration)
+
+	// deliveries tracks the notifications in progress.
+	deliveries sync.WaitGroup
+}
+
+func NewNotificationDispatcher(coreClient client.Reader, credentialResolver repository.CredentialResolver) *NotificationDispatcher {
+	return &NotificationDispatcher{
+		coreClient:         coreClient,
+		credentialResolver: credentialResolver,
+		httpClient:         &http.Client{},
+		sleep:              time.Sleep,
+	}
+}
+
+// NotifyTransition notifies the Slack and generic webhooks configured in the
+// namespace of the package revision of its transition to a new lifecycle value.
+func (d *NotificationDispatcher) NotifyTransition(ctx context.Context, from, to api.PackageRevisionLifecycle, pr *api.PackageRevision) {
+	if d == nil || from == to {
+		return
+	}
+	event := newNotificationEvent(ctx, NotificationTypeLifecycleTransition, pr)
+	event.From = from
+	d.dispatch(ctx, event, configapi.NotificationWebhookTypeSlack, configapi.NotificationWebhookTypeGeneric)
+}
+
+// NotifyRenderFailed notifies the PagerDuty and generic webhooks configured in
+// the namespace of the package revision that rendering it failed.
+func (d *NotificationDispatcher) NotifyRenderFailed(ctx context.Context, pr *api.PackageRevision, renderErr error) {
+	if d == nil {
+		return
+	}
+	event := newNotificationEvent(ctx, NotificationTypeRenderFailed, pr)
+	event.Error = renderErr.Error()
+	d.dispatch(ctx, event, configapi.NotificationWebhookTypePagerDuty, configapi.NotificationWebhookTypeGeneric)
+}
+
+func newNotificationEvent(ctx context.Context, eventType string, pr *api.PackageRevision) NotificationEvent {
+	event := NotificationEvent{
+		Type:            eventType,
+		Namespace:       pr.Namespace,
+		PackageRevision: pr.Name,
+		Repository:      pr.Spec.RepositoryName,
+		Package:         pr.Spec.PackageName,
+		Revision:        pr.Spec.Revision,
+		Lifecycle:       pr.Spec.Lifecycle,
+	}
+	if user, ok := genericapirequest.UserFrom(ctx); ok {
+		event.User = user.GetName()
+	}
+	return event
+}
+
+func (d *NotificationDispatcher) dispatch(ctx context.Context, event NotificationEvent, webhookTypes ...configapi.NotificationWebhookType) {
+	var configs configapi.NotificationConfigList
+	if err := d.coreClient.List(ctx, &configs, client.InNamespace(event.Namespace)); err != nil {
+		klog.Warningf("Cannot list notification configs in namespace %q: %v", event.Namespace, err)
+		return
+	}
+
+	for i := range configs.Items {
+		config := &configs.Items[i]
+		if !hasWebhookType(webhookTypes, config.Spec.WebhookType) {
+			continue
+		}
+		matches, err := matchesNotificationFilter(config.Spec.Filter, event)
+		if err != nil {
+			klog.Warningf("Ignoring notification config %s/%s: %v", config.Namespace, config.Name, err)
+			continue
+		}
+		if !matches {
+			continue
+		}
+
+		configEvent := event
+		configEvent.Config = config.Name
+		if base := config.Spec.LinkBaseURL; base != "" {
+			configEvent.Link = strings.TrimSuffix(base, "/") + "/" + event.Namespace + "/" + event.PackageRevision
+		}
+		d.deliveries.Add(1)
+		go func() {
+			defer d.deliveries.Done()
+			// The request context ends with the API call; notifications outlive it.
+			d.deliver(context.Background(), config, configEvent)
+		}()
+	}
+}
+
+func hasWebhookType(webhookTypes []configapi.NotificationWebhookType, webhookType configapi.NotificationWebhookType) bool {
+	for _, t := range webhookTypes {
+		if t == webhookType {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesNotificationFilter(filter configapi.NotificationFilter, event NotificationEvent) (bool, error) {
+	if filter.Lifecycle != "" && api.PackageRevisionLifecycle(filter.Lifecycle) != event.Lifecycle {
+		return false, nil
+	}
+	if filter.RepositoryPattern != "" {
+		matched, err := path.Match(filter.RepositoryPattern, event.Repository)
+		if err != nil {
+			return false, fmt.Errorf("invalid repository pattern %q: %w", filter.RepositoryPattern, err)
+		}
+		return matched, nil
+	}
+	return true, nil
+}
+
+func (d *NotificationDispatcher) deliver(ctx context.Context, config *configapi.NotificationConfig, event NotificationEvent) {
+	id := config.Namespace + "/" + config.Name
+
+	var body interface{}
+	switch config.Spec.WebhookType {
+	case configapi.NotificationWebhookTypeSlack:
+		body = slackMessage(event)
+	case configapi.NotificationWebhookTypePagerDuty:
+		ref := config.Spec.SecretRef
+		if ref == nil || ref.Name == "" {
+			klog.Warningf("Cannot send notification %s: PagerDuty webhooks require a secretRef", id)
+			return
+		}
+		credential, err := d.credentialResolver.ResolveCredential(ctx, config.Namespace, ref.Name)
+		if err != nil {
+			klog.Warningf("Cannot send notification %s: %v", id, err)
+			return
+		}
+		routingKey := string(credential.Data[pagerDutyRoutingKey])
+		if routingKey == "" {
+			klog.Warningf("Cannot send notification %s: secret %q has no %q key", id, ref.Name, pagerDutyRoutingKey)
+			return
+		}
+		body = pagerDutyEvent(routingKey, event)
+	default:
+		body = event
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		klog.Errorf("Cannot encode notification %s: %v", id, err)
+		return
+	}
+
+	backoff := defaultWebhookBackoff
+	for attempt := 1; ; attempt++ {
+		err := d.post(ctx, config.Spec.WebhookURL, data)
+		if err == nil {
+			klog.Infof("Sent %s notification %s for %s", event.Type, id, event.PackageRevision)
+			return
+		}
+		if attempt >= defaultWebhookMaxAttempts {
+			klog.Warningf("Notification %s failed for %s after %d attempts: %v", id, event.PackageRevision, attempt, err)
+			return
+		}
+		klog.Warningf("Notification %s failed for %s, retrying in %v: %v", id, event.PackageRevision, backoff, err)
+		d.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (d *NotificationDispatcher) post(ctx context.Context, url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// slackMessage formats a lifecycle transition as a Slack incoming webhook message.
+func slackMessage(event NotificationEvent) map[string]interface{} {
+	var text strings.Builder
+	fmt.Fprintf(&text, "*%s* revision *%s* of package *%s* in repository *%s* is now *%s*",
+		event.PackageRevision, event.Revision, event.Package, event.Repository, event.Lifecycle)
+	if event.User != "" {
+		if event.Lifecycle == api.PackageRevisionLifecyclePublished {
+			fmt.Fprintf(&text, "\nApproved by: %s", event.User)
+		} else {
+			fmt.Fprintf(&text, "\nChanged by: %s", event.User)
+		}
+	}
+	if event.Link != "" {
+		fmt.Fprintf(&text, "\n<%s|View package>", event.Link)
+	}
+	return map[string]interface{}{"text": text.String()}
+}
+
+// pagerDutyEvent formats a render failure as a PagerDuty Events API v2 trigger event.
+func pagerDutyEvent(routingKey string, event NotificationEvent) map[string]interface{} {
+	pdEvent := map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": "trigger",
+		"dedup_key":    "porch/" + event.Namespace + "/" + event.PackageRevision,
+		"payload": map[string]interface{}{
+			"summary":   fmt.Sprintf("Rendering package revision %s failed: %s", event.PackageRevision, event.Error),
+			"source":    "porch",
+			"severity":  "error",
+			"component": event.Package,
+			"group":     event.Repository,
+			"class":     event.Type,
+			"custom_details": map[string]string{
+				"namespace":       event.Namespace,
+				"packageRevision": event.PackageRevision,
+				"revision":        event.Revision,
+				"user":            event.User,
+				"error":           event.Error,
+			},
+		},
+	}
+	if event.Link != "" {
+		pdEvent["links"] = []map[string]string{{"href": event.Link, "text": "View package"}}
+	}
+	return pdEvent
+}
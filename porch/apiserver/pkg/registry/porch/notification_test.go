@@ -0,0 +1,245 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// notificationReceiver is a mock notification endpoint which records the
+// bodies of the requests it receives.
+type notificationReceiver struct {
+	t *testing.T
+
+	mutex  sync.Mutex
+	bodies []map[string]interface{}
+}
+
+func (r *notificationReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		r.t.Errorf("cannot decode notification request: %v", err)
+		return
+	}
+	r.bodies = append(r.bodies, body)
+}
+
+func newTestNotificationDispatcher(t *testing.T, configs ...*configapi.NotificationConfig) *NotificationDispatcher {
+	scheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme failed: %v", err)
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, config := range configs {
+		builder = builder.WithObjects(config)
+	}
+	dispatcher := NewNotificationDispatcher(builder.Build(), staticCredentialResolver{
+		"default/pagerduty-secret": {Data: map[string][]byte{pagerDutyRoutingKey: []byte("routing-key")}},
+	})
+	dispatcher.sleep = func(time.Duration) {}
+	return dispatcher
+}
+
+func TestNotifyTransitionSlack(t *testing.T) {
+	slack := &notificationReceiver{t: t}
+	slackServer := httptest.NewServer(slack)
+	defer slackServer.Close()
+
+	other := &notificationReceiver{t: t}
+	otherServer := httptest.NewServer(other)
+	defer otherServer.Close()
+
+	dispatcher := newTestNotificationDispatcher(t,
+		&configapi.NotificationConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "default"},
+			Spec: configapi.NotificationConfigSpec{
+				WebhookURL:  slackServer.URL,
+				WebhookType: configapi.NotificationWebhookTypeSlack,
+				Filter:      configapi.NotificationFilter{Lifecycle: "Published", RepositoryPattern: "re*"},
+				LinkBaseURL: "https://console.example.com/packages/",
+			},
+		},
+		&configapi.NotificationConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: "default"},
+			Spec: configapi.NotificationConfigSpec{
+				WebhookURL:  otherServer.URL,
+				WebhookType: configapi.NotificationWebhookTypeSlack,
+				Filter:      configapi.NotificationFilter{RepositoryPattern: "prod-*"},
+			},
+		},
+		&configapi.NotificationConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: "default"},
+			Spec: configapi.NotificationConfigSpec{
+				WebhookURL:  otherServer.URL,
+				WebhookType: configapi.NotificationWebhookTypePagerDuty,
+				SecretRef:   &configapi.SecretRef{Name: "pagerduty-secret"},
+			},
+		},
+	)
+
+	pr := testPackageRevision()
+	pr.Spec.Lifecycle = api.PackageRevisionLifecyclePublished
+	ctx := genericapirequest.WithUser(context.Background(), &user.DefaultInfo{Name: "alice"})
+	dispatcher.NotifyTransition(ctx, api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished, pr)
+	dispatcher.deliveries.Wait()
+
+	if got, want := len(slack.bodies), 1; got != want {
+		t.Fatalf("slack webhook called %d times, want %d", got, want)
+	}
+	text, _ := slack.bodies[0]["text"].(string)
+	for _, want := range []string{
+		"repo:package:v1",
+		"*v1*",
+		"is now *Published*",
+		"Approved by: alice",
+		"<https://console.example.com/packages/default/repo:package:v1|View package>",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("slack message %q does not contain %q", text, want)
+		}
+	}
+	if got := len(other.bodies); got != 0 {
+		t.Errorf("non-matching webhooks called %d times", got)
+	}
+}
+
+func TestNotifyRenderFailed(t *testing.T) {
+	pagerDuty := &notificationReceiver{t: t}
+	pagerDutyServer := httptest.NewServer(pagerDuty)
+	defer pagerDutyServer.Close()
+
+	generic := &notificationReceiver{t: t}
+	genericServer := httptest.NewServer(generic)
+	defer genericServer.Close()
+
+	slack := &notificationReceiver{t: t}
+	slackServer := httptest.NewServer(slack)
+	defer slackServer.Close()
+
+	dispatcher := newTestNotificationDispatcher(t,
+		&configapi.NotificationConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: "default"},
+			Spec: configapi.NotificationConfigSpec{
+				WebhookURL:  pagerDutyServer.URL,
+				WebhookType: configapi.NotificationWebhookTypePagerDuty,
+				SecretRef:   &configapi.SecretRef{Name: "pagerduty-secret"},
+			},
+		},
+		&configapi.NotificationConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "generic", Namespace: "default"},
+			Spec: configapi.NotificationConfigSpec{
+				WebhookURL:  genericServer.URL,
+				WebhookType: configapi.NotificationWebhookTypeGeneric,
+				Filter:      configapi.NotificationFilter{Lifecycle: "Draft"},
+			},
+		},
+		&configapi.NotificationConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "default"},
+			Spec: configapi.NotificationConfigSpec{
+				WebhookURL:  slackServer.URL,
+				WebhookType: configapi.NotificationWebhookTypeSlack,
+			},
+		},
+	)
+
+	pr := testPackageRevision()
+	pr.Spec.Lifecycle = api.PackageRevisionLifecycleDraft
+	dispatcher.NotifyRenderFailed(context.Background(), pr, errors.New("set-namespace failed"))
+	dispatcher.deliveries.Wait()
+
+	if got, want := len(pagerDuty.bodies), 1; got != want {
+		t.Fatalf("pagerduty webhook called %d times, want %d", got, want)
+	}
+	pdEvent := pagerDuty.bodies[0]
+	if got, want := pdEvent["routing_key"], "routing-key"; got != want {
+		t.Errorf("routing_key: got %v, want %q", got, want)
+	}
+	if got, want := pdEvent["event_action"], "trigger"; got != want {
+		t.Errorf("event_action: got %v, want %q", got, want)
+	}
+	payload, _ := pdEvent["payload"].(map[string]interface{})
+	if got, want := payload["summary"], "Rendering package revision repo:package:v1 failed: set-namespace failed"; got != want {
+		t.Errorf("summary: got %v, want %q", got, want)
+	}
+
+	if got, want := len(generic.bodies), 1; got != want {
+		t.Fatalf("generic webhook called %d times, want %d", got, want)
+	}
+	want := map[string]interface{}{
+		"type":            NotificationTypeRenderFailed,
+		"config":          "generic",
+		"namespace":       "default",
+		"packageRevision": "repo:package:v1",
+		"repository":      "repo",
+		"package":         "package",
+		"revision":        "v1",
+		"lifecycle":       "Draft",
+		"error":           "set-namespace failed",
+	}
+	if diff := cmp.Diff(want, generic.bodies[0]); diff != "" {
+		t.Errorf("unexpected generic event (-want, +got): %s", diff)
+	}
+
+	if got := len(slack.bodies); got != 0 {
+		t.Errorf("slack webhook called %d times for a render failure", got)
+	}
+}
+
+func TestMatchesNotificationFilter(t *testing.T) {
+	event := NotificationEvent{Repository: "prod-blueprints", Lifecycle: api.PackageRevisionLifecyclePublished}
+
+	for _, tc := range []struct {
+		filter  configapi.NotificationFilter
+		want    bool
+		wantErr bool
+	}{
+		{filter: configapi.NotificationFilter{}, want: true},
+		{filter: configapi.NotificationFilter{Lifecycle: "Published"}, want: true},
+		{filter: configapi.NotificationFilter{Lifecycle: "Proposed"}, want: false},
+		{filter: configapi.NotificationFilter{RepositoryPattern: "prod-*"}, want: true},
+		{filter: configapi.NotificationFilter{RepositoryPattern: "staging-*"}, want: false},
+		{filter: configapi.NotificationFilter{Lifecycle: "Published", RepositoryPattern: "prod-*"}, want: true},
+		{filter: configapi.NotificationFilter{RepositoryPattern: "prod-["}, wantErr: true},
+	} {
+		got, err := matchesNotificationFilter(tc.filter, event)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("matchesNotificationFilter(%+v) error = %v, wantErr %v", tc.filter, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("matchesNotificationFilter(%+v) = %v, want %v", tc.filter, got, tc.want)
+		}
+	}
+}
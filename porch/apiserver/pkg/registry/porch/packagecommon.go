@@ -16,19 +16,26 @@ package porch
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
 	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
 	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/util/dryrun"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -39,9 +46,27 @@ type packageCommon struct {
 	coreClient     client.Client
 	gr             schema.GroupResource
 	updateStrategy SimpleRESTUpdateStrategy
+	// renderStaleness sets the RenderStale condition of package revisions. Optional.
+	renderStaleness *RenderStalenessTracker
+	// transitionWebhooks notifies webhooks of lifecycle transitions. Optional.
+	transitionWebhooks *TransitionWebhookNotifier
+	// notifications sends the notifications configured by NotificationConfigs. Optional.
+	notifications *NotificationDispatcher
+	// upstreamWatcher sets the UpstreamUpdateAvailable status of package revisions. Optional.
+	upstreamWatcher *UpstreamWatcher
+	// pipelineRuns runs the Tekton pipelines of proposed package revisions and gates their approval. Optional.
+	pipelineRuns *PipelineRunTracker
+	// conflicts sets the HasConflicts condition of package revisions. Optional.
+	conflicts *conflictTracker
+	// functionConfigValidator validates function configs in saved Kptfiles. Optional.
+	functionConfigValidator *FunctionConfigValidator
 }
 
-func (r *packageCommon) listPackages(ctx context.Context, callback func(p repository.PackageRevision) error) error {
+// listPackages calls callback with the package revisions of all repositories
+// in the namespace which match the filter. If the repositories index package
+// revisions by label, only package revisions which may match the selector are
+// passed to callback; the callback must still apply the selector.
+func (r *packageCommon) listPackages(ctx context.Context, selector labels.Selector, filter repository.ListPackageRevisionFilter, callback func(p repository.PackageRevision) error) error {
 	var opts []client.ListOption
 	if ns, namespaced := genericapirequest.NamespaceFrom(ctx); namespaced {
 		opts = append(opts, client.InNamespace(ns))
@@ -60,7 +85,7 @@ func (r *packageCommon) listPackages(ctx context.Context, callback func(p reposi
 			return err
 		}
 
-		revisions, err := repository.ListPackageRevisions(ctx)
+		revisions, err := listPackageRevisions(ctx, repository, selector, filter)
 		if err != nil {
 			return err
 		}
@@ -73,6 +98,46 @@ func (r *packageCommon) listPackages(ctx context.Context, callback func(p reposi
 	return nil
 }
 
+// listPackageRevisions lists the package revisions of the repository which
+// match the filter. The filter is pushed down to repositories which support
+// it, and applied in memory otherwise.
+func listPackageRevisions(ctx context.Context, repo repository.Repository, selector labels.Selector, filter repository.ListPackageRevisionFilter) ([]repository.PackageRevision, error) {
+	if filtered, ok := repo.(repository.FilteredRepository); ok && !filter.Empty() {
+		return filtered.ListPackageRevisionsFiltered(ctx, filter)
+	}
+
+	var revisions []repository.PackageRevision
+	var err error
+	if indexed, ok := repo.(repository.LabelIndexedRepository); ok && !selector.Empty() {
+		revisions, err = indexed.ListPackageRevisionsMatching(ctx, selector)
+	} else {
+		revisions, err = repo.ListPackageRevisions(ctx)
+	}
+	if err != nil || filter.Empty() {
+		return revisions, err
+	}
+
+	var matching []repository.PackageRevision
+	for _, rev := range revisions {
+		pr, err := rev.GetPackageRevision()
+		if err != nil {
+			return nil, err
+		}
+		if filter.Matches(pr) {
+			matching = append(matching, rev)
+		}
+	}
+	return matching, nil
+}
+
+// labelSelector returns the label selector of the list options.
+func labelSelector(options *metainternalversion.ListOptions) labels.Selector {
+	if options == nil || options.LabelSelector == nil {
+		return labels.Everything()
+	}
+	return options.LabelSelector
+}
+
 func (r *packageCommon) getPackage(ctx context.Context, name string) (repository.PackageRevision, error) {
 	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
 	if !namespaced {
@@ -121,6 +186,10 @@ func (r *packageCommon) getPackageRevision(ctx context.Context, name string, opt
 	if err != nil {
 		return nil, err
 	}
+	r.renderStaleness.UpdateConditions(obj)
+	r.upstreamWatcher.UpdateStatus(obj)
+	r.pipelineRuns.UpdateConditions(obj)
+	r.conflicts.UpdateConditions(obj)
 	return obj, nil
 }
 
@@ -171,6 +240,21 @@ func (r *packageCommon) updatePackageRevision(ctx context.Context, name string,
 		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("expected PackageRevision object, got %T", newRuntimeObj))
 	}
 
+	// Skip the repository write if the update is semantically a no-op, as
+	// is the case when a client round-trips the object unchanged.
+	dryRun := options != nil && dryrun.IsDryRun(options.DryRun)
+	if (SpecNormalizer{}).Equivalent(newObj, oldObj) {
+		klog.V(2).Infof("update of %s is equivalent to the stored object; skipping", name)
+		r.renderStaleness.UpdateConditions(oldObj)
+		r.upstreamWatcher.UpdateStatus(oldObj)
+		r.pipelineRuns.UpdateConditions(oldObj)
+		r.conflicts.UpdateConditions(oldObj)
+		if dryRun {
+			markDryRun(oldObj)
+		}
+		return oldObj, false, nil
+	}
+
 	nameTokens, err := ParseName(name)
 	if err != nil {
 		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("invalid name %q", name))
@@ -185,14 +269,90 @@ func (r *packageCommon) updatePackageRevision(ctx context.Context, name string,
 		return nil, false, apierrors.NewInternalError(fmt.Errorf("error getting repository %v: %w", repositoryID, err))
 	}
 
-	rev, err := r.cad.UpdatePackageRevision(ctx, &repositoryObj, oldPackage, oldObj, newObj)
+	if newObj.Spec.Lifecycle == api.PackageRevisionLifecycleProposed && oldObj.Spec.Lifecycle == api.PackageRevisionLifecycleDraft {
+		resources, err := oldPackage.GetResources(ctx)
+		if err != nil {
+			return nil, false, apierrors.NewInternalError(err)
+		}
+		if files := r.conflicts.Observe(ns, name, resources.Spec.Resources); len(files) > 0 {
+			return nil, false, apierrors.NewInvalid(api.SchemeGroupVersion.WithKind("PackageRevision").GroupKind(), oldObj.Name,
+				field.ErrorList{field.Forbidden(field.NewPath("spec", "lifecycle"),
+					fmt.Sprintf("cannot propose a package revision with unresolved merge conflicts in %s", strings.Join(files, ", ")))})
+		}
+	}
+
+	if newObj.Spec.Lifecycle == api.PackageRevisionLifecyclePublished && oldObj.Spec.Lifecycle != api.PackageRevisionLifecyclePublished && repositoryObj.Spec.RequireValidation {
+		resources, err := oldPackage.GetResources(ctx)
+		if err != nil {
+			return nil, false, apierrors.NewInternalError(err)
+		}
+		if report := validatePackageResources(resources.Spec.Resources); !report.Valid {
+			return nil, false, apierrors.NewInvalid(api.SchemeGroupVersion.WithKind("PackageRevision").GroupKind(), oldObj.Name, validationFieldErrors(report))
+		}
+	}
+
+	if newObj.Spec.Lifecycle == api.PackageRevisionLifecyclePublished && oldObj.Spec.Lifecycle != api.PackageRevisionLifecyclePublished && repositoryObj.Spec.TektonPipelineRef != nil {
+		if err := r.pipelineRuns.CheckPassed(ctx, oldObj); err != nil {
+			return nil, false, apierrors.NewInvalid(api.SchemeGroupVersion.WithKind("PackageRevision").GroupKind(), oldObj.Name,
+				field.ErrorList{field.Forbidden(field.NewPath("spec", "lifecycle"), err.Error())})
+		}
+	}
+
+	if newObj.Spec.Lifecycle == api.PackageRevisionLifecyclePublished && oldObj.Spec.Lifecycle != api.PackageRevisionLifecyclePublished && requiresDigest(&repositoryObj) {
+		// Pin the package revision to the images its tags resolve to on approval.
+		if err := ResolvePRTagToDigest(ctx, newObj); err != nil {
+			return nil, false, apierrors.NewInternalError(err)
+		}
+	}
+
+	rev, err := r.cad.UpdatePackageRevision(ctx, &repositoryObj, oldPackage, oldObj, newObj, engine.ChangeOptions{DryRun: dryRun})
 	if err != nil {
-		return nil, false, apierrors.NewInternalError(err)
+		if !dryRun {
+			r.notifyRenderFailed(ctx, oldObj, err)
+		}
+		return nil, false, engineError(err)
 	}
 
 	created, err := rev.GetPackageRevision()
 	if err != nil {
 		return nil, false, apierrors.NewInternalError(err)
 	}
+	if !dryRun && created.Spec.Lifecycle == api.PackageRevisionLifecycleProposed && oldObj.Spec.Lifecycle != api.PackageRevisionLifecycleProposed {
+		if err := r.pipelineRuns.StartPipelineRun(ctx, &repositoryObj, created); err != nil {
+			klog.Warningf("Cannot run the pipeline of %s: %v", created.Name, err)
+		}
+	}
+	r.renderStaleness.UpdateConditions(created)
+	r.upstreamWatcher.UpdateStatus(created)
+	r.pipelineRuns.UpdateConditions(created)
+	r.conflicts.UpdateConditions(created)
+	if dryRun {
+		markDryRun(created)
+		return created, false, nil
+	}
+	r.transitionWebhooks.NotifyTransition(ctx, oldObj.Spec.Lifecycle, created.Spec.Lifecycle, created)
+	r.notifications.NotifyTransition(ctx, oldObj.Spec.Lifecycle, created.Spec.Lifecycle, created)
 	return created, false, nil
 }
+
+// notifyRenderFailed notifies of the failure to render the package revision
+// if err is a render error.
+func (r *packageCommon) notifyRenderFailed(ctx context.Context, pr *api.PackageRevision, err error) {
+	var renderErr *engine.RenderError
+	if errors.As(err, &renderErr) {
+		r.notifications.NotifyRenderFailed(ctx, pr, renderErr)
+	}
+}
+
+// engineError converts an error returned by the CaD engine to an API error.
+func engineError(err error) error {
+	if errors.Is(err, engine.ErrMaxFunctionInvocationsExceeded) {
+		return &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: err.Error(),
+		}}
+	}
+	return apierrors.NewInternalError(err)
+}
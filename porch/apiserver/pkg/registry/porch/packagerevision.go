@@ -17,25 +17,35 @@ package porch
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
 	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/util/dryrun"
 	"k8s.io/klog/v2"
 )
 
 type packageRevisions struct {
 	packageCommon
 	rest.TableConvertor
+
+	createStrategy SimpleRESTCreateStrategy
+	deleteStrategy SimpleRESTDeleteStrategy
+	// createRateLimiter limits the rate of creates per user; nil disables the limit.
+	createRateLimiter *CreateRateLimiter
 }
 
 var _ rest.Storage = &packageRevisions{}
@@ -67,11 +77,36 @@ func (r *packageRevisions) List(ctx context.Context, options *metainternalversio
 		},
 	}
 
-	if err := r.packageCommon.listPackages(ctx, func(p repository.PackageRevision) error {
+	fieldSelector, err := parsePackageRevisionFieldSelector(options)
+	if err != nil {
+		return nil, err
+	}
+	if fieldSelector.matchesNothing {
+		return result, nil
+	}
+	if fieldSelector.namespace != "" {
+		if ns, _ := genericapirequest.NamespaceFrom(ctx); ns != "" && ns != fieldSelector.namespace {
+			return result, nil
+		}
+		ctx = genericapirequest.WithNamespace(ctx, fieldSelector.namespace)
+	}
+
+	selector := labelSelector(options)
+	if err := r.packageCommon.listPackages(ctx, selector, fieldSelector.filter, func(p repository.PackageRevision) error {
+		if !fieldSelector.matchesName(p.Name()) {
+			return nil
+		}
 		item, err := p.GetPackageRevision()
 		if err != nil {
 			return err
 		}
+		if !selector.Matches(labels.Set(item.Labels)) {
+			return nil
+		}
+		r.renderStaleness.UpdateConditions(item)
+		r.upstreamWatcher.UpdateStatus(item)
+		r.pipelineRuns.UpdateConditions(item)
+		r.conflicts.UpdateConditions(item)
 		result.Items = append(result.Items, *item)
 		return nil
 	}); err != nil {
@@ -88,6 +123,10 @@ func (r *packageRevisions) Get(ctx context.Context, name string, options *metav1
 
 // Create implements the Creater interface.
 func (r *packageRevisions) Create(ctx context.Context, runtimeObject runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	if err := r.createRateLimiter.Allow(ctx); err != nil {
+		return nil, err
+	}
+
 	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
 	if !namespaced {
 		return nil, apierrors.NewBadRequest("namespace must be specified")
@@ -98,16 +137,11 @@ func (r *packageRevisions) Create(ctx context.Context, runtimeObject runtime.Obj
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected PackageRevision object, got %T", runtimeObject))
 	}
 
-	expectedName := obj.Spec.RepositoryName + ":" + obj.Spec.PackageName + ":" + obj.Spec.Revision
-	name := obj.Name
-	if name == "" {
-		name = expectedName
-	}
-
-	if name != expectedName {
-		return nil, apierrors.NewBadRequest(fmt.Sprintf("name should be %q", expectedName))
+	if err := applyCreateStrategy(ctx, r.createStrategy, obj); err != nil {
+		return nil, err
 	}
 
+	name := obj.Name
 	nameTokens, err := ParseName(name)
 	if err != nil {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid name %q", name))
@@ -122,15 +156,53 @@ func (r *packageRevisions) Create(ctx context.Context, runtimeObject runtime.Obj
 		return nil, apierrors.NewInternalError(fmt.Errorf("error getting repository %v: %w", repositoryID, err))
 	}
 
-	rev, err := r.cad.CreatePackageRevision(ctx, &repositoryObj, obj)
+	if fieldErrors := (packageRevisionStrategy{}).ValidateCreate(ctx, obj, &repositoryObj); len(fieldErrors) > 0 {
+		return nil, apierrors.NewInvalid(api.SchemeGroupVersion.WithKind("PackageRevision").GroupKind(), name, fieldErrors)
+	}
+
+	dryRun := options != nil && dryrun.IsDryRun(options.DryRun)
+	rev, err := r.cad.CreatePackageRevision(ctx, &repositoryObj, obj, engine.ChangeOptions{DryRun: dryRun})
+	if err != nil {
+		if !dryRun {
+			failed := obj.DeepCopy()
+			failed.Name, failed.Namespace = name, ns
+			r.notifyRenderFailed(ctx, failed, err)
+		}
+		return nil, engineError(err)
+	}
+
+	// The size of the package is only known once its tasks have been
+	// executed, so a package exceeding the budget of the repository is
+	// removed again.
+	resources, err := rev.GetResources(ctx)
 	if err != nil {
 		return nil, apierrors.NewInternalError(err)
 	}
+	if fieldErrors := (packageRevisionResourcesStrategy{}).ValidateCreate(ctx, resources, &repositoryObj); len(fieldErrors) > 0 {
+		if !dryRun {
+			if err := r.cad.DeletePackageRevision(ctx, &repositoryObj, rev); err != nil {
+				klog.Warningf("failed to delete package revision %s exceeding the size limit: %v", name, err)
+			}
+		}
+		return nil, apierrors.NewInvalid(api.SchemeGroupVersion.WithKind("PackageRevision").GroupKind(), name, fieldErrors)
+	}
 
 	created, err := rev.GetPackageRevision()
 	if err != nil {
 		return nil, apierrors.NewInternalError(err)
 	}
+	if !dryRun && created.Spec.Lifecycle == api.PackageRevisionLifecycleProposed {
+		if err := r.pipelineRuns.StartPipelineRun(ctx, &repositoryObj, created); err != nil {
+			klog.Warningf("Cannot run the pipeline of %s: %v", created.Name, err)
+		}
+	}
+	r.renderStaleness.UpdateConditions(created)
+	r.upstreamWatcher.UpdateStatus(created)
+	r.pipelineRuns.UpdateConditions(created)
+	r.conflicts.UpdateConditions(created)
+	if dryRun {
+		markDryRun(created)
+	}
 	return created, nil
 }
 
@@ -179,7 +251,15 @@ func (r *packageRevisions) Delete(ctx context.Context, name string, deleteValida
 		}
 	}
 
-	// TODO: Verify options are empty?
+	if isForceDelete(options) {
+		if oldObj.Annotations == nil {
+			oldObj.Annotations = map[string]string{}
+		}
+		oldObj.Annotations[api.ForceDeleteAnnotation] = "true"
+	}
+	if err := applyDeleteStrategy(ctx, r.deleteStrategy, api.SchemeGroupVersion.WithKind("PackageRevision").GroupKind(), name, oldObj); err != nil {
+		return nil, false, err
+	}
 
 	nameTokens, err := ParseName(name)
 	if err != nil {
@@ -203,6 +283,84 @@ func (r *packageRevisions) Delete(ctx context.Context, name string, deleteValida
 	return oldObj, true, nil
 }
 
+// markDryRun annotates the package revision returned by a dry-run request,
+// as sent by kubectl apply --dry-run=server. Dry-run requests apply the
+// strategies and the package mutations, but don't write to the repository.
+func markDryRun(pr *api.PackageRevision) {
+	if pr.Annotations == nil {
+		pr.Annotations = map[string]string{}
+	}
+	pr.Annotations[api.DryRunAnnotation] = "true"
+}
+
+// PackageRevisions Create Strategy
+
+type packageRevisionCreateStrategy struct{}
+
+var _ SimpleRESTCreateStrategy = packageRevisionCreateStrategy{}
+
+// PrepareForCreate defaults the name of the package revision to the name made
+// of its repository, package and revision.
+func (s packageRevisionCreateStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	pr := obj.(*api.PackageRevision)
+	if pr.Name == "" {
+		pr.Name = packageRevisionName(pr)
+	}
+}
+
+func (s packageRevisionCreateStrategy) ValidateCreate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	allErrs := field.ErrorList{}
+	pr := obj.(*api.PackageRevision)
+
+	if expected := packageRevisionName(pr); pr.Name != expected {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), pr.Name, fmt.Sprintf("name should be %q", expected)))
+	} else if _, err := ParseName(pr.Name); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), pr.Name, err.Error()))
+	}
+	return allErrs
+}
+
+func (s packageRevisionCreateStrategy) Canonicalize(obj runtime.Object) {
+	pr := obj.(*api.PackageRevision)
+	if pr.Spec.Lifecycle == "" {
+		// Set default
+		pr.Spec.Lifecycle = api.PackageRevisionLifecycleDraft
+	}
+}
+
+// packageRevisionName returns the name of a package revision, made of its
+// repository, package and revision.
+func packageRevisionName(pr *api.PackageRevision) string {
+	return pr.Spec.RepositoryName + ":" + pr.Spec.PackageName + ":" + pr.Spec.Revision
+}
+
+// PackageRevisions Delete Strategy
+
+type packageRevisionDeleteStrategy struct {
+	NoopDeleteStrategy
+}
+
+var _ SimpleRESTDeleteStrategy = packageRevisionDeleteStrategy{}
+
+// ValidateDelete rejects the deletion of published package revisions, which
+// downstream packages may depend on, unless the deletion is forced.
+func (s packageRevisionDeleteStrategy) ValidateDelete(ctx context.Context, obj runtime.Object) field.ErrorList {
+	allErrs := field.ErrorList{}
+	pr := obj.(*api.PackageRevision)
+
+	if pr.Spec.Lifecycle == api.PackageRevisionLifecyclePublished && pr.Annotations[api.ForceDeleteAnnotation] != "true" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "lifecycle"),
+			fmt.Sprintf("cannot delete a %s package revision without forcing the deletion", pr.Spec.Lifecycle)))
+	}
+	return allErrs
+}
+
+// isForceDelete returns true if the delete options force the deletion, as
+// sent by kubectl delete --force: with a zero grace period.
+func isForceDelete(options *metav1.DeleteOptions) bool {
+	return options != nil && options.GracePeriodSeconds != nil && *options.GracePeriodSeconds == 0
+}
+
 // PackageRevisions Update Strategy
 
 type packageRevisionStrategy struct{}
@@ -210,6 +368,101 @@ type packageRevisionStrategy struct{}
 var _ SimpleRESTUpdateStrategy = packageRevisionStrategy{}
 
 func (s packageRevisionStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	updateGeneration(obj.(*api.PackageRevision), old.(*api.PackageRevision))
+}
+
+// maxChangelogEntryLength is the maximum length of spec.changelogEntry.
+const maxChangelogEntryLength = 4096
+
+// emailPattern matches email addresses in the changelog entry.
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+// validateChangelogEntry validates the length of the changelog entry and, as a
+// courtesy check, that it does not contain email addresses outside of the
+// kpt.dev domain.
+func validateChangelogEntry(pr *api.PackageRevision) field.ErrorList {
+	allErrs := field.ErrorList{}
+	entry := pr.Spec.ChangelogEntry
+	path := field.NewPath("spec", "changelogEntry")
+
+	if len(entry) > maxChangelogEntryLength {
+		allErrs = append(allErrs, field.TooLong(path, entry, maxChangelogEntryLength))
+	}
+	for _, match := range emailPattern.FindAllStringSubmatch(entry, -1) {
+		domain := strings.ToLower(match[1])
+		if domain != "kpt.dev" && !strings.HasSuffix(domain, ".kpt.dev") {
+			allErrs = append(allErrs, field.Invalid(path, match[0], "changelog entry must not contain email addresses outside of the kpt.dev domain"))
+		}
+	}
+	return allErrs
+}
+
+// validateRenderPolicy validates that the render policy, if set, is one of
+// the supported values.
+func validateRenderPolicy(pr *api.PackageRevision) field.ErrorList {
+	switch policy := pr.Spec.RenderPolicy; policy {
+	case "", api.RenderPolicyOnPropose, api.RenderPolicyOnApprove, api.RenderPolicyManual:
+		return nil
+	default:
+		return field.ErrorList{field.NotSupported(field.NewPath("spec", "renderPolicy"), policy, []string{
+			string(api.RenderPolicyOnPropose),
+			string(api.RenderPolicyOnApprove),
+			string(api.RenderPolicyManual),
+		})}
+	}
+}
+
+// updateChangelogAnnotation records the changelog entry in the
+// kpt.dev/changelog/{revision} annotation when the package revision is
+// being published.
+func updateChangelogAnnotation(newRevision, oldRevision *api.PackageRevision) {
+	if newRevision.Spec.ChangelogEntry == "" ||
+		newRevision.Spec.Lifecycle != api.PackageRevisionLifecyclePublished ||
+		oldRevision.Spec.Lifecycle == api.PackageRevisionLifecyclePublished {
+		return
+	}
+	if newRevision.Annotations == nil {
+		newRevision.Annotations = map[string]string{}
+	}
+	newRevision.Annotations[api.ChangelogAnnotationPrefix+newRevision.Spec.Revision] = newRevision.Spec.ChangelogEntry
+}
+
+// updateGeneration increments metadata.generation of the new package revision
+// if its normalized spec differs from the old one. Status-only changes and
+// semantically equivalent spec changes retain the old generation.
+func updateGeneration(newRevision, oldRevision *api.PackageRevision) {
+	newRevision.Generation = oldRevision.Generation
+	normalizer := SpecNormalizer{}
+	if !equality.Semantic.DeepEqual(normalizer.Normalize(newRevision).Spec, normalizer.Normalize(oldRevision).Spec) {
+		newRevision.Generation++
+	}
+}
+
+// defaultReservedPackagePrefixes are package name prefixes reserved for system
+// use in repositories which don't specify spec.packageNamingPolicy.reservedPrefixes.
+var defaultReservedPackagePrefixes = []string{"kpt-", "porch-"}
+
+// ValidateCreate validates a new package revision against the policies of the
+// repository in which it is created.
+func (s packageRevisionStrategy) ValidateCreate(ctx context.Context, obj runtime.Object, repository *configapi.Repository) field.ErrorList {
+	allErrs := field.ErrorList{}
+	pr := obj.(*api.PackageRevision)
+
+	reserved := defaultReservedPackagePrefixes
+	if policy := repository.Spec.PackageNamingPolicy; policy != nil && policy.ReservedPrefixes != nil {
+		reserved = policy.ReservedPrefixes
+	}
+	for _, prefix := range reserved {
+		if prefix != "" && strings.HasPrefix(pr.Spec.PackageName, prefix) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "packageName"), pr.Spec.PackageName,
+				fmt.Sprintf("package name prefix %q is reserved in repository %q", prefix, repository.Name)))
+		}
+	}
+
+	allErrs = append(allErrs, validateChangelogEntry(pr)...)
+	allErrs = append(allErrs, validateRenderPolicy(pr)...)
+	allErrs = append(allErrs, validateContentDigestPolicy(pr, repository)...)
+	return allErrs
 }
 
 func (s packageRevisionStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
@@ -221,11 +474,18 @@ func (s packageRevisionStrategy) ValidateUpdate(ctx context.Context, obj, old ru
 	case "", api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecycleProposed:
 		// valid
 
+	case api.PackageRevisionLifecycleRejected:
+		if newRevision.Spec.Lifecycle != api.PackageRevisionLifecycleDraft {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "lifecycle"), newRevision.Spec.Lifecycle,
+				fmt.Sprintf("a %s package can only return to %s", lifecycle, api.PackageRevisionLifecycleDraft)))
+		}
+
 	default:
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "lifecycle"), lifecycle, fmt.Sprintf("can only update package with lifecycle value one of %s",
 			strings.Join([]string{
 				string(api.PackageRevisionLifecycleDraft),
 				string(api.PackageRevisionLifecycleProposed),
+				string(api.PackageRevisionLifecycleRejected),
 			}, ",")),
 		))
 
@@ -244,6 +504,9 @@ func (s packageRevisionStrategy) ValidateUpdate(ctx context.Context, obj, old ru
 		))
 	}
 
+	allErrs = append(allErrs, validateChangelogEntry(newRevision)...)
+	allErrs = append(allErrs, validateRenderPolicy(newRevision)...)
+
 	return allErrs
 }
 
@@ -15,9 +15,12 @@
 package porch
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"k8s.io/apiserver/pkg/warning"
 )
 
 func TestApprovalUpdateStrategy(t *testing.T) {
@@ -55,6 +58,11 @@ func TestApprovalUpdateStrategy(t *testing.T) {
 			valid:   []api.PackageRevisionLifecycle{api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecyclePublished},
 			invalid: []api.PackageRevisionLifecycle{"", "Wrong", api.PackageRevisionLifecycleProposed},
 		},
+		{
+			old:     api.PackageRevisionLifecycleRejected,
+			valid:   []api.PackageRevisionLifecycle{},
+			invalid: []api.PackageRevisionLifecycle{"", "Wrong", api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished},
+		},
 	} {
 		for _, new := range tc.valid {
 			testValidateUpdate(t, s, tc.old, new, true)
@@ -64,3 +72,135 @@ func TestApprovalUpdateStrategy(t *testing.T) {
 		}
 	}
 }
+
+func TestRejection(t *testing.T) {
+	proposed := &api.PackageRevision{
+		Spec: api.PackageRevisionSpec{
+			Revision:  "v1",
+			Lifecycle: api.PackageRevisionLifecycleProposed,
+		},
+	}
+	rejected := proposed.DeepCopy()
+	rejected.Spec.Lifecycle = api.PackageRevisionLifecycleRejected
+	rejected.Status.RejectionReason = "Bucket must not be public"
+	unexplained := rejected.DeepCopy()
+	unexplained.Status.RejectionReason = " "
+	published := proposed.DeepCopy()
+	published.Spec.Lifecycle = api.PackageRevisionLifecyclePublished
+
+	for _, tc := range []struct {
+		name     string
+		strategy SimpleRESTUpdateStrategy
+		new, old *api.PackageRevision
+		valid    bool
+	}{
+		{name: "approval rejects", strategy: packageRevisionApprovalStrategy{}, new: rejected, old: proposed, valid: true},
+		{name: "approval rejects without reason", strategy: packageRevisionApprovalStrategy{}, new: unexplained, old: proposed},
+		{name: "approval publishes rejected", strategy: packageRevisionApprovalStrategy{}, new: published, old: rejected},
+		{name: "rejection rejects", strategy: packageRevisionRejectionStrategy{}, new: rejected, old: proposed, valid: true},
+		{name: "rejection rejects without reason", strategy: packageRevisionRejectionStrategy{}, new: unexplained, old: proposed},
+		{name: "rejection publishes", strategy: packageRevisionRejectionStrategy{}, new: published, old: proposed},
+		{name: "rejection rejects rejected", strategy: packageRevisionRejectionStrategy{}, new: rejected, old: rejected},
+		{name: "update publishes rejected", strategy: packageRevisionStrategy{}, new: published, old: rejected},
+		{name: "update proposes rejected", strategy: packageRevisionStrategy{}, new: proposed, old: rejected},
+		{name: "update rejects", strategy: packageRevisionStrategy{}, new: rejected, old: proposed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			allErrs := tc.strategy.ValidateUpdate(context.Background(), tc.new, tc.old)
+			if tc.valid && len(allErrs) > 0 {
+				t.Errorf("ValidateUpdate failed unexpectedly: %v", allErrs.ToAggregate())
+			}
+			if !tc.valid && len(allErrs) == 0 {
+				t.Errorf("ValidateUpdate of %s -> %s should fail but didn't", tc.old.Spec.Lifecycle, tc.new.Spec.Lifecycle)
+			}
+		})
+	}
+}
+
+func TestApprovalChangelogEntry(t *testing.T) {
+	s := packageRevisionApprovalStrategy{}
+
+	old := &api.PackageRevision{
+		Spec: api.PackageRevisionSpec{
+			Revision:  "v1",
+			Lifecycle: api.PackageRevisionLifecycleProposed,
+		},
+	}
+	approved := old.DeepCopy()
+	approved.Spec.Lifecycle = api.PackageRevisionLifecyclePublished
+	approved.Spec.ChangelogEntry = "Fixed the namespace. Contact: team@kpt.dev"
+
+	if allErrs := s.ValidateUpdate(context.Background(), approved, old); len(allErrs) > 0 {
+		t.Fatalf("ValidateUpdate failed unexpectedly: %v", allErrs.ToAggregate())
+	}
+	s.PrepareForUpdate(context.Background(), approved, old)
+	if got, want := approved.Annotations["kpt.dev/changelog/v1"], approved.Spec.ChangelogEntry; got != want {
+		t.Errorf("changelog annotation: got %q, want %q", got, want)
+	}
+
+	rejected := old.DeepCopy()
+	rejected.Spec.Lifecycle = api.PackageRevisionLifecycleDraft
+	rejected.Spec.ChangelogEntry = "Needs work"
+	s.PrepareForUpdate(context.Background(), rejected, old)
+	if _, found := rejected.Annotations["kpt.dev/changelog/v1"]; found {
+		t.Errorf("changelog annotation should only be set when the package revision is published")
+	}
+
+	for _, entry := range []string{
+		"Contact someone@example.com for details",
+		strings.Repeat("x", 4097),
+	} {
+		invalid := approved.DeepCopy()
+		invalid.Spec.ChangelogEntry = entry
+		if allErrs := s.ValidateUpdate(context.Background(), invalid, old); len(allErrs) == 0 {
+			t.Errorf("ValidateUpdate with changelog entry %.40q should fail but didn't", entry)
+		}
+	}
+}
+
+type recordingWarnings []string
+
+func (w *recordingWarnings) AddWarning(agent, text string) {
+	*w = append(*w, text)
+}
+
+func TestApprovalImageLockWarning(t *testing.T) {
+	s := packageRevisionApprovalStrategy{}
+
+	old := &api.PackageRevision{
+		Spec: api.PackageRevisionSpec{
+			Revision:   "v1",
+			Lifecycle:  api.PackageRevisionLifecycleProposed,
+			ImageLocks: map[string]string{"example.com/fn-a:v1": "sha256:aaa"},
+		},
+		Status: api.PackageRevisionStatus{
+			LastRenderedFunctionDigests: map[string]string{
+				"example.com/fn-a:v1": "sha256:aaa",
+				"example.com/fn-c:v1": "sha256:ccc",
+				"example.com/fn-b:v1": "sha256:bbb",
+			},
+		},
+	}
+	approved := old.DeepCopy()
+	approved.Spec.Lifecycle = api.PackageRevisionLifecyclePublished
+
+	var warnings recordingWarnings
+	ctx := warning.WithWarningRecorder(context.Background(), &warnings)
+	if allErrs := s.ValidateUpdate(ctx, approved, old); len(allErrs) > 0 {
+		t.Fatalf("ValidateUpdate failed unexpectedly: %v", allErrs.ToAggregate())
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "example.com/fn-b:v1, example.com/fn-c:v1") {
+		t.Errorf("warnings: got %q, want one listing the unlocked images", warnings)
+	}
+
+	// All images locked: no warning.
+	warnings = nil
+	old.Spec.ImageLocks["example.com/fn-b:v1"] = "sha256:bbb"
+	old.Spec.ImageLocks["example.com/fn-c:v1"] = "sha256:ccc"
+	if allErrs := s.ValidateUpdate(ctx, approved, old); len(allErrs) > 0 {
+		t.Fatalf("ValidateUpdate failed unexpectedly: %v", allErrs.ToAggregate())
+	}
+	if len(warnings) > 0 {
+		t.Errorf("warnings: got %q, want none", warnings)
+	}
+}
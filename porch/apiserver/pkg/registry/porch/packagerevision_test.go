@@ -15,9 +15,12 @@
 package porch
 
 import (
+	"context"
 	"testing"
 
 	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestUpdateStrategy(t *testing.T) {
@@ -45,6 +48,11 @@ func TestUpdateStrategy(t *testing.T) {
 			valid:   []api.PackageRevisionLifecycle{"", api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecycleProposed},
 			invalid: []api.PackageRevisionLifecycle{"Wrong", api.PackageRevisionLifecyclePublished},
 		},
+		{
+			old:     api.PackageRevisionLifecycleRejected,
+			valid:   []api.PackageRevisionLifecycle{api.PackageRevisionLifecycleDraft},
+			invalid: []api.PackageRevisionLifecycle{"", "Wrong", api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished, api.PackageRevisionLifecycleRejected},
+		},
 		{
 			old:     api.PackageRevisionLifecyclePublished,
 			valid:   []api.PackageRevisionLifecycle{},
@@ -64,3 +72,88 @@ func TestUpdateStrategy(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateStrategyGeneration(t *testing.T) {
+	s := packageRevisionStrategy{}
+
+	old := &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Generation: 3,
+		},
+		Spec: api.PackageRevisionSpec{
+			Lifecycle: api.PackageRevisionLifecycleDraft,
+		},
+	}
+
+	statusOnly := old.DeepCopy()
+	statusOnly.Status.ObservedGeneration = 3
+	s.PrepareForUpdate(context.Background(), statusOnly, old)
+	if got, want := statusOnly.Generation, int64(3); got != want {
+		t.Errorf("status-only update: generation = %d; want %d", got, want)
+	}
+
+	specChange := old.DeepCopy()
+	specChange.Spec.Lifecycle = api.PackageRevisionLifecycleProposed
+	s.PrepareForUpdate(context.Background(), specChange, old)
+	if got, want := specChange.Generation, int64(4); got != want {
+		t.Errorf("spec update: generation = %d; want %d", got, want)
+	}
+}
+
+func TestCreateStrategyReservedPrefixes(t *testing.T) {
+	s := packageRevisionStrategy{}
+
+	for _, tc := range []struct {
+		name     string
+		policy   *configapi.PackageNamingPolicy
+		pkg      string
+		wantErrs int
+	}{
+		{name: "default-kpt", pkg: "kpt-test", wantErrs: 1},
+		{name: "default-porch", pkg: "porch-system", wantErrs: 1},
+		{name: "default-allowed", pkg: "my-kpt-package", wantErrs: 0},
+		{name: "custom", policy: &configapi.PackageNamingPolicy{ReservedPrefixes: []string{"internal-"}}, pkg: "internal-pkg", wantErrs: 1},
+		{name: "custom-overrides-default", policy: &configapi.PackageNamingPolicy{ReservedPrefixes: []string{"internal-"}}, pkg: "kpt-test", wantErrs: 0},
+		{name: "empty-allows-all", policy: &configapi.PackageNamingPolicy{ReservedPrefixes: []string{}}, pkg: "kpt-test", wantErrs: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &configapi.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo"},
+				Spec: configapi.RepositorySpec{
+					PackageNamingPolicy: tc.policy,
+				},
+			}
+			pr := &api.PackageRevision{
+				Spec: api.PackageRevisionSpec{
+					PackageName: tc.pkg,
+					Revision:    "v1",
+				},
+			}
+			if got := s.ValidateCreate(context.Background(), pr, repository); len(got) != tc.wantErrs {
+				t.Errorf("ValidateCreate(%q): got errors %v, want %d errors", tc.pkg, got, tc.wantErrs)
+			}
+		})
+	}
+}
+
+func TestValidateRenderPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy   api.RenderPolicy
+		wantErrs int
+	}{
+		{policy: "", wantErrs: 0},
+		{policy: api.RenderPolicyOnPropose, wantErrs: 0},
+		{policy: api.RenderPolicyOnApprove, wantErrs: 0},
+		{policy: api.RenderPolicyManual, wantErrs: 0},
+		{policy: "Always", wantErrs: 1},
+	} {
+		pr := &api.PackageRevision{
+			Spec: api.PackageRevisionSpec{
+				RenderPolicy: tc.policy,
+			},
+		}
+		if got := validateRenderPolicy(pr); len(got) != tc.wantErrs {
+			t.Errorf("validateRenderPolicy(%q): got errors %v, want %d errors", tc.policy, got, tc.wantErrs)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
@@ -64,7 +65,7 @@ func (r *packageRevisionResources) List(ctx context.Context, options *metaintern
 		},
 	}
 
-	if err := r.packageCommon.listPackages(ctx, func(p repository.PackageRevision) error {
+	if err := r.packageCommon.listPackages(ctx, labels.Everything(), repository.ListPackageRevisionFilter{}, func(p repository.PackageRevision) error {
 		item, err := p.GetResources(ctx)
 		if err != nil {
 			return err
@@ -89,6 +90,7 @@ func (r *packageRevisionResources) Get(ctx context.Context, name string, options
 	if err != nil {
 		return nil, err
 	}
+	r.conflicts.Observe(obj.Namespace, name, obj.Spec.Resources)
 	return obj, nil
 }
 
@@ -130,28 +132,50 @@ func (r *packageRevisionResources) Update(ctx context.Context, name string, objI
 		}
 	}
 
+	created, err := r.updateResources(ctx, ns, name, oldPackage, oldObj, newObj)
+	if err != nil {
+		return nil, false, err
+	}
+	return created, false, nil
+}
+
+// updateResources validates the new resources of the package revision
+// against the policies of its repository and commits them.
+func (r *packageRevisionResources) updateResources(ctx context.Context, ns, name string, oldPackage repository.PackageRevision, oldObj, newObj *api.PackageRevisionResources) (*api.PackageRevisionResources, error) {
+	if fieldErrors := r.functionConfigValidator.ValidateUpdate(ctx, newObj, oldObj); len(fieldErrors) > 0 {
+		return nil, apierrors.NewInvalid(api.SchemeGroupVersion.WithKind("PackageRevisionResources").GroupKind(), name, fieldErrors)
+	}
+
 	nameTokens, err := ParseName(name)
 	if err != nil {
-		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("invalid name %q", name))
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid name %q", name))
 	}
 
 	var repositoryObj v1alpha1.Repository
 	repositoryID := types.NamespacedName{Namespace: ns, Name: nameTokens.RepositoryName}
 	if err := r.coreClient.Get(ctx, repositoryID, &repositoryObj); err != nil {
 		if apierrors.IsNotFound(err) {
-			return nil, false, apierrors.NewNotFound(api.PackageRevisionResourcesGVR.GroupResource(), repositoryID.Name)
+			return nil, apierrors.NewNotFound(api.PackageRevisionResourcesGVR.GroupResource(), repositoryID.Name)
 		}
-		return nil, false, apierrors.NewInternalError(fmt.Errorf("error getting repository %v: %w", repositoryID, err))
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting repository %v: %w", repositoryID, err))
+	}
+
+	if fieldErrors := (packageRevisionResourcesStrategy{}).ValidateUpdate(ctx, newObj, oldObj, &repositoryObj); len(fieldErrors) > 0 {
+		return nil, apierrors.NewInvalid(api.SchemeGroupVersion.WithKind("PackageRevisionResources").GroupKind(), name, fieldErrors)
 	}
 
 	rev, err := r.cad.UpdatePackageResources(ctx, &repositoryObj, oldPackage, oldObj, newObj)
 	if err != nil {
-		return nil, false, apierrors.NewInternalError(err)
+		if pr, prErr := oldPackage.GetPackageRevision(); prErr == nil {
+			r.notifyRenderFailed(ctx, pr, err)
+		}
+		return nil, engineError(err)
 	}
 
 	created, err := rev.GetResources(ctx)
 	if err != nil {
-		return nil, false, apierrors.NewInternalError(err)
+		return nil, apierrors.NewInternalError(err)
 	}
-	return created, false, nil
+	r.conflicts.Observe(ns, name, created.Spec.Resources)
+	return created, nil
 }
@@ -17,6 +17,7 @@ package porch
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
@@ -24,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/warning"
 )
 
 type packageRevisionsApproval struct {
@@ -58,9 +60,18 @@ func (a *packageRevisionsApproval) Update(ctx context.Context, name string, objI
 	return a.common.updatePackageRevision(ctx, name, objInfo, createValidation, updateValidation, allowCreate, options)
 }
 
-type packageRevisionApprovalStrategy struct{}
+type packageRevisionApprovalStrategy struct {
+	// secretChecker checks the required secrets of package revisions being
+	// published; nil disables the check.
+	secretChecker *RequiredSecretChecker
+}
 
 func (s packageRevisionApprovalStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	newRevision := obj.(*api.PackageRevision)
+	oldRevision := old.(*api.PackageRevision)
+
+	updateGeneration(newRevision, oldRevision)
+	updateChangelogAnnotation(newRevision, oldRevision)
 }
 
 func (s packageRevisionApprovalStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
@@ -68,26 +79,81 @@ func (s packageRevisionApprovalStrategy) ValidateUpdate(ctx context.Context, obj
 	oldRevision := old.(*api.PackageRevision)
 	newRevision := obj.(*api.PackageRevision)
 
-	if lifecycle := oldRevision.Spec.Lifecycle; lifecycle != api.PackageRevisionLifecycleProposed {
+	switch lifecycle := oldRevision.Spec.Lifecycle; lifecycle {
+	case api.PackageRevisionLifecycleProposed:
+		// valid
+
+	case api.PackageRevisionLifecycleRejected:
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "lifecycle"), lifecycle,
+			fmt.Sprintf("cannot approve package with %s lifecycle value; it must return to %s and be proposed again", lifecycle, api.PackageRevisionLifecycleDraft)))
+
+	default:
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "lifecycle"), lifecycle,
 			fmt.Sprintf("cannot approve package with %s lifecycle value; only Proposed packages can be approved", lifecycle)))
 	}
 
 	switch lifecycle := newRevision.Spec.Lifecycle; lifecycle {
-	// TODO: signal rejection of the approval differently than by returning to draft?
 	case api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecyclePublished:
 		// valid
 
+	case api.PackageRevisionLifecycleRejected:
+		if strings.TrimSpace(newRevision.Status.RejectionReason) == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("status", "rejectionReason"), "a rejected package must give the reason for the rejection"))
+		}
+
 	default:
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "lifecycle"), lifecycle, fmt.Sprintf("value for approval can be only one of %s",
 				strings.Join([]string{
 					string(api.PackageRevisionLifecycleDraft),
 					string(api.PackageRevisionLifecyclePublished),
+					string(api.PackageRevisionLifecycleRejected),
 				}, ",")),
 			))
 	}
+
+	allErrs = append(allErrs, validateChangelogEntry(newRevision)...)
+
+	if len(allErrs) == 0 && newRevision.Spec.Lifecycle == api.PackageRevisionLifecyclePublished {
+		allErrs = append(allErrs, s.validateRequiredSecrets(ctx, oldRevision)...)
+		if missing := missingImageLocks(oldRevision); len(missing) > 0 {
+			warning.AddWarning(ctx, "", fmt.Sprintf("function images are not locked to a digest: %s", strings.Join(missing, ", ")))
+		}
+	}
+
 	return allErrs
 }
 
+// validateRequiredSecrets checks that the secrets required by the package
+// revision exist in its target cluster. The requirements recorded in the
+// stored package revision are checked, so they cannot be dropped as part of
+// the approval.
+func (s packageRevisionApprovalStrategy) validateRequiredSecrets(ctx context.Context, pr *api.PackageRevision) field.ErrorList {
+	if s.secretChecker == nil {
+		return nil
+	}
+	path := field.NewPath("spec", "requiredSecrets")
+	missing, err := s.secretChecker.MissingSecrets(ctx, pr)
+	if err != nil {
+		return field.ErrorList{field.InternalError(path, err)}
+	}
+	if len(missing) > 0 {
+		return field.ErrorList{field.Invalid(path, missing, "required secrets not found in the target cluster")}
+	}
+	return nil
+}
+
+// missingImageLocks returns the function images of the last render of the
+// package revision which have no image lock, sorted.
+func missingImageLocks(pr *api.PackageRevision) []string {
+	var missing []string
+	for image := range pr.Status.LastRenderedFunctionDigests {
+		if pr.Spec.ImageLocks[image] == "" {
+			missing = append(missing, image)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
 func (s packageRevisionApprovalStrategy) Canonicalize(obj runtime.Object) {}
@@ -0,0 +1,96 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// packageRevisionsLineage serves the clone lineage of package revisions.
+type packageRevisionsLineage struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageRevisionsLineage{}
+var _ rest.Scoper = &packageRevisionsLineage{}
+var _ rest.Getter = &packageRevisionsLineage{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (l *packageRevisionsLineage) New() runtime.Object {
+	return &api.PackageRevisionLineage{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (l *packageRevisionsLineage) NamespaceScoped() bool {
+	return true
+}
+
+func (l *packageRevisionsLineage) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	pkg, err := l.common.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := pkg.GetPackageRevision()
+	if err != nil {
+		return nil, err
+	}
+	lineage, err := buildPackageRevisionLineage(pr)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return lineage, nil
+}
+
+// buildPackageRevisionLineage converts the clone lineage annotations of the
+// package revision into the structured lineage.
+func buildPackageRevisionLineage(pr *api.PackageRevision) (*api.PackageRevisionLineage, error) {
+	lineage, err := repository.ParseCloneLineage(pr.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.PackageRevisionLineage{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevisionLineage",
+			APIVersion: api.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              pr.Name,
+			Namespace:         pr.Namespace,
+			UID:               pr.UID,
+			ResourceVersion:   pr.ResourceVersion,
+			CreationTimestamp: pr.CreationTimestamp,
+		},
+		Depth:   lineage.Depth,
+		Omitted: lineage.Omitted(),
+	}
+	for _, ancestor := range lineage.Ancestors {
+		entry := api.PackageRevisionLineageEntry{Name: ancestor}
+		if i := strings.LastIndex(ancestor, "@"); i >= 0 {
+			entry.Name, entry.Revision = ancestor[:i], ancestor[i+1:]
+		}
+		result.Ancestors = append(result.Ancestors, entry)
+	}
+	return result, nil
+}
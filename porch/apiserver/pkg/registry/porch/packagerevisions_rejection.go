@@ -0,0 +1,74 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// packageRevisionsRejection rejects proposed package revisions.
+type packageRevisionsRejection struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageRevisionsRejection{}
+var _ rest.Scoper = &packageRevisionsRejection{}
+var _ rest.Getter = &packageRevisionsRejection{}
+var _ rest.Updater = &packageRevisionsRejection{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (r *packageRevisionsRejection) New() runtime.Object {
+	return &api.PackageRevision{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (r *packageRevisionsRejection) NamespaceScoped() bool {
+	return true
+}
+
+func (r *packageRevisionsRejection) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	return r.common.getPackageRevision(ctx, name, options)
+}
+
+// Update rejects the package revision with the lifecycle and rejection
+// reason of the updated object.
+func (r *packageRevisionsRejection) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	allowCreate := false // do not allow create on update
+	return r.common.updatePackageRevision(ctx, name, objInfo, createValidation, updateValidation, allowCreate, options)
+}
+
+// packageRevisionRejectionStrategy validates rejections as approvals whose
+// lifecycle value must be Rejected.
+type packageRevisionRejectionStrategy struct {
+	packageRevisionApprovalStrategy
+}
+
+func (s packageRevisionRejectionStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	newRevision := obj.(*api.PackageRevision)
+
+	if lifecycle := newRevision.Spec.Lifecycle; lifecycle != api.PackageRevisionLifecycleRejected {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "lifecycle"), lifecycle,
+			fmt.Sprintf("value for rejection can only be %s", api.PackageRevisionLifecycleRejected))}
+	}
+	return s.packageRevisionApprovalStrategy.ValidateUpdate(ctx, obj, old)
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// packageRevisionResourcesStrategy validates package revision resources
+// against the policies of the repository which contains them.
+type packageRevisionResourcesStrategy struct{}
+
+// ValidateCreate validates the resources of a newly created package revision.
+func (s packageRevisionResourcesStrategy) ValidateCreate(ctx context.Context, obj runtime.Object, repository *configapi.Repository) field.ErrorList {
+	resources := obj.(*api.PackageRevisionResources)
+	return validatePackageSize(resources, repository)
+}
+
+// ValidateUpdate validates updated package revision resources.
+func (s packageRevisionResourcesStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object, repository *configapi.Repository) field.ErrorList {
+	resources := obj.(*api.PackageRevisionResources)
+	allErrs := validatePackageSize(resources, repository)
+	allErrs = append(allErrs, validateKptfileResources(resources, old.(*api.PackageRevisionResources))...)
+	return allErrs
+}
+
+// packageSize returns the total size of the files of the package in bytes.
+func packageSize(resources *api.PackageRevisionResources) int64 {
+	var size int64
+	for _, contents := range resources.Spec.Resources {
+		size += int64(len(contents))
+	}
+	return size
+}
+
+// validatePackageSize rejects packages whose total size exceeds the
+// spec.maxPackageSizeBytes budget of the repository.
+func validatePackageSize(resources *api.PackageRevisionResources, repository *configapi.Repository) field.ErrorList {
+	allErrs := field.ErrorList{}
+	limit := repository.Spec.MaxPackageSizeBytes
+	if limit <= 0 {
+		return allErrs
+	}
+	if size := packageSize(resources); size > limit {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "resources"), fmt.Sprintf("%d bytes", size),
+			fmt.Sprintf("package size of %d bytes exceeds the limit of %d bytes of repository %q", size, limit, repository.Name)))
+	}
+	return allErrs
+}
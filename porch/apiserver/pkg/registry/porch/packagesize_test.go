@@ -0,0 +1,76 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidatePackageSize(t *testing.T) {
+	resources := &api.PackageRevisionResources{
+		Spec: api.PackageRevisionResourcesSpec{
+			Resources: map[string]string{
+				"Kptfile":   strings.Repeat("k", 60),
+				"cm.yaml":   strings.Repeat("c", 30),
+				"README.md": strings.Repeat("r", 10),
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name  string
+		limit int64
+		valid bool
+	}{
+		{name: "Unlimited", limit: 0, valid: true},
+		{name: "Below", limit: 200, valid: true},
+		{name: "Exact", limit: 100, valid: true},
+		{name: "Exceeded", limit: 99, valid: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &configapi.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo"},
+				Spec:       configapi.RepositorySpec{MaxPackageSizeBytes: tc.limit},
+			}
+			strategy := packageRevisionResourcesStrategy{}
+			createErrs := strategy.ValidateCreate(context.Background(), resources, repository)
+			updateErrs := strategy.ValidateUpdate(context.Background(), resources, resources, repository)
+			if tc.valid {
+				if len(createErrs) != 0 || len(updateErrs) != 0 {
+					t.Errorf("expected package to be valid, got %v, %v", createErrs, updateErrs)
+				}
+				return
+			}
+			for _, errs := range []field.ErrorList{createErrs, updateErrs} {
+				if len(errs) != 1 {
+					t.Fatalf("expected one error, got %v", errs)
+				}
+				if got, want := errs[0].Error(), "spec.resources"; !strings.HasPrefix(got, want) {
+					t.Errorf("expected error for %s, got %q", want, got)
+				}
+				if got, want := errs[0].Error(), "package size of 100 bytes exceeds the limit of 99 bytes"; !strings.Contains(got, want) {
+					t.Errorf("expected error to contain %q, got %q", want, got)
+				}
+			}
+		})
+	}
+}
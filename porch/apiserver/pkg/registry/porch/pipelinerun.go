@@ -0,0 +1,288 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// conditionCIPassed is set on proposed package revisions in repositories
+	// with a Tekton pipeline, from the status of their PipelineRun.
+	conditionCIPassed = "CIPassed"
+
+	// pipelineRunPackageRevisionAnnotation records the package revision a
+	// PipelineRun was created for.
+	pipelineRunPackageRevisionAnnotation = "porch.kpt.dev/package-revision"
+
+	// DefaultPipelineRunPollPeriod is how often the status of the PipelineRuns
+	// of proposed package revisions is polled.
+	DefaultPipelineRunPollPeriod = 30 * time.Second
+)
+
+var pipelineRunGVK = schema.GroupVersionKind{Group: "tekton.dev", Version: "v1beta1", Kind: "PipelineRun"}
+
+// PipelineRunTracker runs the Tekton pipelines of repositories for the
+// package revisions proposed in them, and tracks the status of the
+// PipelineRuns to gate the approval of the package revisions.
+type PipelineRunTracker struct {
+	coreClient client.Client
+
+	mutex sync.RWMutex
+	// runs records the latest observed status of the PipelineRuns, by
+	// PipelineRun. PipelineRuns are tracked once a proposed package revision
+	// referring to them is seen.
+	runs  map[types.NamespacedName]pipelineRunStatus
+	clock clock.Clock
+}
+
+type pipelineRunStatus struct {
+	// observed is false until the PipelineRun is first polled.
+	observed bool
+	// found is false if the PipelineRun does not exist.
+	found     bool
+	condition metav1.Condition
+}
+
+func NewPipelineRunTracker(coreClient client.Client, clk clock.Clock) *PipelineRunTracker {
+	return &PipelineRunTracker{
+		coreClient: coreClient,
+		runs:       map[types.NamespacedName]pipelineRunStatus{},
+		clock:      clk,
+	}
+}
+
+// pipelineRunName returns the name of the PipelineRun of the package
+// revision. The name depends on the content of the package revision, so the
+// package revision gets a new PipelineRun whenever it is proposed with
+// changed content.
+func pipelineRunName(pr *api.PackageRevision) string {
+	hash := sha256.Sum256([]byte(pr.Name + "\x00" + pr.ResourceVersion))
+	return "porch-ci-" + hex.EncodeToString(hash[:])[:16]
+}
+
+func pipelineRunKey(pr *api.PackageRevision) types.NamespacedName {
+	return types.NamespacedName{Namespace: pr.Namespace, Name: pipelineRunName(pr)}
+}
+
+// StartPipelineRun creates the PipelineRun of the Tekton pipeline of the
+// repository for the proposed package revision. The package revision details
+// are passed to the pipeline as params.
+func (t *PipelineRunTracker) StartPipelineRun(ctx context.Context, repository *configapi.Repository, pr *api.PackageRevision) error {
+	if t == nil || repository.Spec.TektonPipelineRef == nil {
+		return nil
+	}
+
+	key := pipelineRunKey(pr)
+	run := &unstructured.Unstructured{}
+	run.SetGroupVersionKind(pipelineRunGVK)
+	run.SetNamespace(key.Namespace)
+	run.SetName(key.Name)
+	run.SetAnnotations(map[string]string{pipelineRunPackageRevisionAnnotation: pr.Name})
+	run.Object["spec"] = map[string]interface{}{
+		"pipelineRef": map[string]interface{}{
+			"name": repository.Spec.TektonPipelineRef.Name,
+		},
+		"params": []interface{}{
+			pipelineRunParam("package-revision", pr.Name),
+			pipelineRunParam("namespace", pr.Namespace),
+			pipelineRunParam("repository", pr.Spec.RepositoryName),
+			pipelineRunParam("package", pr.Spec.PackageName),
+			pipelineRunParam("revision", pr.Spec.Revision),
+		},
+	}
+
+	if err := t.coreClient.Create(ctx, run); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("cannot create PipelineRun %s: %w", key, err)
+	}
+	klog.Infof("Created PipelineRun %s of pipeline %q for %s", key, repository.Spec.TektonPipelineRef.Name, pr.Name)
+
+	t.mutex.Lock()
+	t.runs[key] = pipelineRunStatus{}
+	t.mutex.Unlock()
+	return nil
+}
+
+func pipelineRunParam(name, value string) map[string]interface{} {
+	return map[string]interface{}{"name": name, "value": value}
+}
+
+// Run polls the status of the unfinished PipelineRuns every period until ctx
+// is done.
+func (t *PipelineRunTracker) Run(ctx context.Context, period time.Duration) {
+	ticker := t.clock.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			t.refresh(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *PipelineRunTracker) refresh(ctx context.Context) {
+	t.mutex.RLock()
+	var keys []types.NamespacedName
+	for key, status := range t.runs {
+		// Finished and missing PipelineRuns don't change anymore.
+		if status.observed && (!status.found || status.condition.Status != metav1.ConditionUnknown) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	t.mutex.RUnlock()
+
+	for _, key := range keys {
+		if _, err := t.poll(ctx, key); err != nil {
+			klog.Warningf("Cannot get status of PipelineRun %s: %v", key, err)
+		}
+	}
+}
+
+// poll gets the status of the PipelineRun and records it.
+func (t *PipelineRunTracker) poll(ctx context.Context, key types.NamespacedName) (pipelineRunStatus, error) {
+	run := &unstructured.Unstructured{}
+	run.SetGroupVersionKind(pipelineRunGVK)
+
+	status := pipelineRunStatus{observed: true}
+	switch err := t.coreClient.Get(ctx, key, run); {
+	case err == nil:
+		status.found = true
+		status.condition = pipelineRunCondition(run)
+	case apierrors.IsNotFound(err):
+	default:
+		return pipelineRunStatus{}, err
+	}
+
+	t.mutex.Lock()
+	t.runs[key] = status
+	t.mutex.Unlock()
+	return status, nil
+}
+
+// pipelineRunCondition returns the CIPassed condition reflecting the
+// Succeeded condition of the PipelineRun.
+func pipelineRunCondition(run *unstructured.Unstructured) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    conditionCIPassed,
+		Status:  metav1.ConditionUnknown,
+		Reason:  "Pending",
+		Message: fmt.Sprintf("PipelineRun %s has not started", run.GetName()),
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(run.Object, "status", "conditions")
+	for _, c := range conditions {
+		c, ok := c.(map[string]interface{})
+		if !ok || c["type"] != "Succeeded" {
+			continue
+		}
+		status, _ := c["status"].(string)
+		reason, _ := c["reason"].(string)
+		message, _ := c["message"].(string)
+		if lastTransition, _ := c["lastTransitionTime"].(string); lastTransition != "" {
+			if t, err := time.Parse(time.RFC3339, lastTransition); err == nil {
+				condition.LastTransitionTime = metav1.Time{Time: t}
+			}
+		}
+
+		switch metav1.ConditionStatus(status) {
+		case metav1.ConditionTrue:
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "PipelineRunSucceeded"
+		case metav1.ConditionFalse:
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "PipelineRunFailed"
+		default:
+			condition.Reason = "PipelineRunRunning"
+		}
+		condition.Message = fmt.Sprintf("PipelineRun %s", run.GetName())
+		if reason != "" {
+			condition.Message += ": " + reason
+		}
+		if message != "" {
+			condition.Message += ": " + message
+		}
+	}
+	return condition
+}
+
+// UpdateConditions sets the CIPassed condition of proposed package revisions
+// from the last observed status of their PipelineRun. Package revisions
+// without a PipelineRun are left unchanged.
+func (t *PipelineRunTracker) UpdateConditions(pr *api.PackageRevision) {
+	if t == nil || pr.Spec.Lifecycle != api.PackageRevisionLifecycleProposed {
+		return
+	}
+
+	key := pipelineRunKey(pr)
+	t.mutex.Lock()
+	status, found := t.runs[key]
+	if !found {
+		// Start tracking the PipelineRun; its status is polled on the next refresh.
+		t.runs[key] = pipelineRunStatus{}
+	}
+	t.mutex.Unlock()
+
+	if !status.observed || !status.found {
+		return
+	}
+	condition := status.condition
+	condition.ObservedGeneration = pr.Generation
+	if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = metav1.Time{Time: t.clock.Now()}
+	}
+	meta.SetStatusCondition(&pr.Status.Conditions, condition)
+}
+
+// CheckPassed returns an error unless the PipelineRun of the proposed package
+// revision succeeded. The status of the PipelineRun is read from the cluster.
+func (t *PipelineRunTracker) CheckPassed(ctx context.Context, pr *api.PackageRevision) error {
+	if t == nil {
+		return nil
+	}
+
+	key := pipelineRunKey(pr)
+	status, err := t.poll(ctx, key)
+	if err != nil {
+		return fmt.Errorf("cannot get status of PipelineRun %s: %w", key, err)
+	}
+	if !status.found {
+		return fmt.Errorf("PipelineRun %s of the package revision not found; propose the package revision again to run the pipeline", key.Name)
+	}
+	if status.condition.Status != metav1.ConditionTrue {
+		return fmt.Errorf("PipelineRun %s of the package revision has not succeeded (%s)", key.Name, status.condition.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock/fakeclock"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPipelineRunPackageRevision() *api.PackageRevision {
+	return &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "porch", Name: "repo:app:v1", ResourceVersion: "0123abcd"},
+		Spec: api.PackageRevisionSpec{
+			RepositoryName: "repo",
+			PackageName:    "app",
+			Revision:       "v1",
+			Lifecycle:      api.PackageRevisionLifecycleProposed,
+		},
+	}
+}
+
+// setPipelineRunSucceeded sets the Succeeded condition of the PipelineRun.
+func setPipelineRunSucceeded(t *testing.T, tracker *PipelineRunTracker, pr *api.PackageRevision, status metav1.ConditionStatus, reason string) {
+	run := &unstructured.Unstructured{}
+	run.SetGroupVersionKind(pipelineRunGVK)
+	if err := tracker.coreClient.Get(context.Background(), pipelineRunKey(pr), run); err != nil {
+		t.Fatalf("Get PipelineRun failed: %v", err)
+	}
+	if err := unstructured.SetNestedSlice(run.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": string(status), "reason": reason},
+	}, "status", "conditions"); err != nil {
+		t.Fatalf("SetNestedSlice failed: %v", err)
+	}
+	if err := tracker.coreClient.Update(context.Background(), run); err != nil {
+		t.Fatalf("Update PipelineRun failed: %v", err)
+	}
+}
+
+func TestPipelineRunTracker(t *testing.T) {
+	ctx := context.Background()
+	clock := fakeclock.NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewPipelineRunTracker(fake.NewClientBuilder().Build(), clock)
+	repository := &configapi.Repository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "porch", Name: "repo"},
+		Spec: configapi.RepositorySpec{
+			TektonPipelineRef: &configapi.TektonPipelineRef{Name: "validate"},
+		},
+	}
+	pr := newPipelineRunPackageRevision()
+
+	if err := tracker.StartPipelineRun(ctx, repository, pr); err != nil {
+		t.Fatalf("StartPipelineRun failed: %v", err)
+	}
+
+	run := &unstructured.Unstructured{}
+	run.SetGroupVersionKind(pipelineRunGVK)
+	if err := tracker.coreClient.Get(ctx, pipelineRunKey(pr), run); err != nil {
+		t.Fatalf("Get PipelineRun failed: %v", err)
+	}
+	if got, want := run.GetAnnotations()[pipelineRunPackageRevisionAnnotation], pr.Name; got != want {
+		t.Errorf("PipelineRun package revision annotation: got %q, want %q", got, want)
+	}
+	pipeline, _, _ := unstructured.NestedString(run.Object, "spec", "pipelineRef", "name")
+	if got, want := pipeline, "validate"; got != want {
+		t.Errorf("PipelineRun pipeline: got %q, want %q", got, want)
+	}
+	params, _, _ := unstructured.NestedSlice(run.Object, "spec", "params")
+	want := []interface{}{
+		map[string]interface{}{"name": "package-revision", "value": "repo:app:v1"},
+		map[string]interface{}{"name": "namespace", "value": "porch"},
+		map[string]interface{}{"name": "repository", "value": "repo"},
+		map[string]interface{}{"name": "package", "value": "app"},
+		map[string]interface{}{"name": "revision", "value": "v1"},
+	}
+	if diff := cmp.Diff(want, params); diff != "" {
+		t.Errorf("PipelineRun params (-want, +got): %s", diff)
+	}
+
+	// The PipelineRun is running.
+	tracker.refresh(ctx)
+	got := newPipelineRunPackageRevision()
+	tracker.UpdateConditions(got)
+	if condition := meta.FindStatusCondition(got.Status.Conditions, conditionCIPassed); condition == nil || condition.Status != metav1.ConditionUnknown {
+		t.Errorf("expected %s=Unknown for a running PipelineRun; got %v", conditionCIPassed, got.Status.Conditions)
+	}
+	if err := tracker.CheckPassed(ctx, pr); err == nil {
+		t.Errorf("expected approval to be blocked by a running PipelineRun")
+	}
+
+	// The PipelineRun fails.
+	setPipelineRunSucceeded(t, tracker, pr, metav1.ConditionFalse, "Failed")
+	tracker.refresh(ctx)
+	got = newPipelineRunPackageRevision()
+	tracker.UpdateConditions(got)
+	if !meta.IsStatusConditionFalse(got.Status.Conditions, conditionCIPassed) {
+		t.Errorf("expected %s=False for a failed PipelineRun; got %v", conditionCIPassed, got.Status.Conditions)
+	}
+	if err := tracker.CheckPassed(ctx, pr); err == nil {
+		t.Errorf("expected approval to be blocked by a failed PipelineRun")
+	}
+
+	// The PipelineRun is retried and succeeds.
+	setPipelineRunSucceeded(t, tracker, pr, metav1.ConditionTrue, "Succeeded")
+	if err := tracker.CheckPassed(ctx, pr); err != nil {
+		t.Errorf("expected approval to be allowed by a succeeded PipelineRun; got %v", err)
+	}
+	got = newPipelineRunPackageRevision()
+	tracker.UpdateConditions(got)
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, conditionCIPassed) {
+		t.Errorf("expected %s=True for a succeeded PipelineRun; got %v", conditionCIPassed, got.Status.Conditions)
+	}
+}
+
+func TestPipelineRunTrackerNoPipelineRun(t *testing.T) {
+	ctx := context.Background()
+	clock := fakeclock.NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewPipelineRunTracker(fake.NewClientBuilder().Build(), clock)
+
+	// Repositories without a Tekton pipeline don't run PipelineRuns.
+	pr := newPipelineRunPackageRevision()
+	if err := tracker.StartPipelineRun(ctx, &configapi.Repository{}, pr); err != nil {
+		t.Fatalf("StartPipelineRun failed: %v", err)
+	}
+
+	tracker.UpdateConditions(pr)
+	tracker.refresh(ctx)
+	tracker.UpdateConditions(pr)
+	if condition := meta.FindStatusCondition(pr.Status.Conditions, conditionCIPassed); condition != nil {
+		t.Errorf("expected no %s condition without a PipelineRun; got %v", conditionCIPassed, condition)
+	}
+	if err := tracker.CheckPassed(ctx, pr); err == nil {
+		t.Errorf("expected approval to be blocked without a PipelineRun")
+	}
+}
+
+func TestPipelineRunName(t *testing.T) {
+	pr := newPipelineRunPackageRevision()
+	name := pipelineRunName(pr)
+	if got := pipelineRunName(newPipelineRunPackageRevision()); got != name {
+		t.Errorf("expected a stable PipelineRun name; got %q and %q", name, got)
+	}
+
+	// Proposing changed content runs the pipeline again.
+	pr.ResourceVersion = "4567cdef"
+	if got := pipelineRunName(pr); got == name {
+		t.Errorf("expected a new PipelineRun name for changed content; got %q", got)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultCreateRateLimitRPM is the default number of package revisions a
+	// single user may create per minute.
+	DefaultCreateRateLimitRPM = 100
+	// DefaultCreateRateLimitBurst is the default number of package revisions a
+	// single user may create in a burst.
+	DefaultCreateRateLimitBurst = 20
+
+	// DefaultAPIRateLimitRPM is the default number of API requests a single
+	// user agent may make per minute.
+	DefaultAPIRateLimitRPM = 3000
+	// DefaultAPIRateLimitBurst is the default number of API requests a single
+	// user agent may make in a burst.
+	DefaultAPIRateLimitBurst = 500
+)
+
+// CreateRateLimiter limits the rate at which each user can create package
+// revisions, so that a single user cannot starve the others of API capacity.
+type CreateRateLimiter struct {
+	limiters *keyedLimiters
+	now      func() time.Time
+}
+
+// NewCreateRateLimiter returns a limiter allowing every user rpm creates per
+// minute, in bursts of up to burst creates. A non-positive rpm disables the
+// limit and returns nil.
+func NewCreateRateLimiter(rpm, burst int) *CreateRateLimiter {
+	if rpm <= 0 {
+		return nil
+	}
+	return &CreateRateLimiter{
+		limiters: newKeyedLimiters(rpm, burst),
+		now:      time.Now,
+	}
+}
+
+// Allow returns a TooManyRequests error, carrying the delay after which the
+// request may be retried, if the user making the request exceeded the limit.
+// Requests without an authenticated user are not limited.
+func (l *CreateRateLimiter) Allow(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	userinfo, ok := request.UserFrom(ctx)
+	if !ok || userinfo.GetName() == "" {
+		return nil
+	}
+	name := userinfo.GetName()
+
+	retryAfter := l.limiters.reserve(name, l.now())
+	if retryAfter == 0 {
+		return nil
+	}
+	return apierrors.NewTooManyRequests(fmt.Sprintf("user %q exceeded the package revision create rate limit", name), retryAfter)
+}
+
+// rateLimitExemptUsers are the users whose API requests are never limited.
+var rateLimitExemptUsers = map[string]bool{
+	"system:kube-controller-manager": true,
+	"system:kube-scheduler":          true,
+}
+
+// APIRateLimiter limits the rate of API requests by user agent, so that a
+// misbehaving client cannot overload the porch apiserver.
+type APIRateLimiter struct {
+	limiters *keyedLimiters
+	now      func() time.Time
+}
+
+// NewAPIRateLimiter returns a limiter allowing every user agent rpm requests
+// per minute, in bursts of up to burst requests. A non-positive rpm disables
+// the limit and returns nil.
+func NewAPIRateLimiter(rpm, burst int) *APIRateLimiter {
+	if rpm <= 0 {
+		return nil
+	}
+	return &APIRateLimiter{
+		limiters: newKeyedLimiters(rpm, burst),
+		now:      time.Now,
+	}
+}
+
+// WithRateLimit wraps the handler, rejecting requests over the limit of their
+// user agent with 429 Too Many Requests and a Retry-After header. Requests of
+// the kube-controller-manager and kube-scheduler users are not limited. The
+// handler must be wrapped by the authentication filter for the user to be
+// known.
+func (l *APIRateLimiter) WithRateLimit(handler http.Handler) http.Handler {
+	if l == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if userinfo, ok := request.UserFrom(req.Context()); ok && rateLimitExemptUsers[userinfo.GetName()] {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		userAgent := req.UserAgent()
+		if retryAfter := l.limiters.reserve(userAgent, l.now()); retryAfter > 0 {
+			klog.V(2).Infof("User agent %q exceeded the API rate limit", userAgent)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			http.Error(w, "Too many requests, please try again later.", http.StatusTooManyRequests)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// defaultMaxLimiterKeys bounds the number of keys with their own limiter.
+const defaultMaxLimiterKeys = 10000
+
+// keyedLimiters is a set of token bucket limiters with the same rate, one per
+// key. Limiters whose bucket has refilled are evicted, as a new limiter for
+// the key would behave the same. At most maxKeys keys have their own limiter;
+// once they are all in use, new keys share a single overflow limiter, so that
+// clients rotating their keys cannot escape the limit.
+type keyedLimiters struct {
+	limit   rate.Limit
+	burst   int
+	maxKeys int
+	// idle is how long it takes an unused limiter to refill its bucket.
+	idle time.Duration
+
+	mutex    sync.Mutex
+	limiters map[string]*list.Element
+	lru      *list.List // front is the most recently used limiter
+	overflow *rate.Limiter
+}
+
+type keyedLimiter struct {
+	key      string
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+func newKeyedLimiters(rpm, burst int) *keyedLimiters {
+	if burst < 1 {
+		burst = 1
+	}
+	limit := rate.Limit(float64(rpm) / time.Minute.Seconds())
+	return &keyedLimiters{
+		limit:    limit,
+		burst:    burst,
+		maxKeys:  defaultMaxLimiterKeys,
+		idle:     time.Duration(float64(burst) / float64(limit) * float64(time.Second)),
+		limiters: map[string]*list.Element{},
+		lru:      list.New(),
+		overflow: rate.NewLimiter(limit, burst),
+	}
+}
+
+// reserve takes a token from the limiter of the key. If none is available, no
+// token is taken and the number of seconds after which one will be is
+// returned.
+func (l *keyedLimiters) reserve(key string, now time.Time) int {
+	r := l.limiter(key, now).ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	if delay == 0 {
+		return 0
+	}
+	// Don't consume a token the request will not use.
+	r.CancelAt(now)
+	return int(math.Ceil(delay.Seconds()))
+}
+
+func (l *keyedLimiters) limiter(key string, now time.Time) *rate.Limiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if e, found := l.limiters[key]; found {
+		entry := e.Value.(*keyedLimiter)
+		entry.lastUsed = now
+		l.lru.MoveToFront(e)
+		return entry.limiter
+	}
+
+	// The least recently used limiters are the first to refill.
+	for e := l.lru.Back(); e != nil; e = l.lru.Back() {
+		entry := e.Value.(*keyedLimiter)
+		if now.Sub(entry.lastUsed) < l.idle {
+			break
+		}
+		delete(l.limiters, entry.key)
+		l.lru.Remove(e)
+	}
+	if l.lru.Len() >= l.maxKeys {
+		return l.overflow
+	}
+
+	limiter := rate.NewLimiter(l.limit, l.burst)
+	l.limiters[key] = l.lru.PushFront(&keyedLimiter{
+		key:      key,
+		limiter:  limiter,
+		lastUsed: now,
+	})
+	return limiter
+}
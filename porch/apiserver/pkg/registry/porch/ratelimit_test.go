@@ -0,0 +1,176 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestCreateRateLimit(t *testing.T) {
+	limiter := NewCreateRateLimiter(DefaultCreateRateLimitRPM, DefaultCreateRateLimitBurst)
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	r := &packageRevisions{createRateLimiter: limiter}
+	// The request carries no namespace, so creates allowed by the limiter fail
+	// with BadRequest before reaching the engine.
+	ctx := request.WithUser(context.Background(), &user.DefaultInfo{Name: "alice"})
+
+	for i := 1; i <= 25; i++ {
+		_, err := r.Create(ctx, &api.PackageRevision{}, nil, nil)
+		if i <= DefaultCreateRateLimitBurst {
+			if apierrors.IsTooManyRequests(err) {
+				t.Errorf("create %d: unexpected rate limit error: %v", i, err)
+			}
+			continue
+		}
+		if !apierrors.IsTooManyRequests(err) {
+			t.Errorf("create %d: expected rate limit error, got %v", i, err)
+			continue
+		}
+		if seconds, ok := apierrors.SuggestsClientDelay(err); !ok || seconds != 1 {
+			t.Errorf("create %d: Retry-After: got %d, want 1", i, seconds)
+		}
+	}
+
+	// Other users have their own limit.
+	other := request.WithUser(context.Background(), &user.DefaultInfo{Name: "bob"})
+	if _, err := r.Create(other, &api.PackageRevision{}, nil, nil); apierrors.IsTooManyRequests(err) {
+		t.Errorf("unexpected rate limit error for another user: %v", err)
+	}
+
+	// Tokens are replenished over time.
+	now = now.Add(time.Minute)
+	if _, err := r.Create(ctx, &api.PackageRevision{}, nil, nil); apierrors.IsTooManyRequests(err) {
+		t.Errorf("unexpected rate limit error after a minute: %v", err)
+	}
+}
+
+func TestCreateRateLimitDisabled(t *testing.T) {
+	if limiter := NewCreateRateLimiter(0, DefaultCreateRateLimitBurst); limiter != nil {
+		t.Fatalf("expected zero rpm to disable the limiter")
+	}
+	var limiter *CreateRateLimiter
+	ctx := request.WithUser(context.Background(), &user.DefaultInfo{Name: "alice"})
+	for i := 0; i < 100; i++ {
+		if err := limiter.Allow(ctx); err != nil {
+			t.Fatalf("unexpected error from disabled limiter: %v", err)
+		}
+	}
+}
+
+func TestAPIRateLimit(t *testing.T) {
+	limiter := NewAPIRateLimiter(100, 100)
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	handler := limiter.WithRateLimit(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(userAgent, userName string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/apis/porch.kpt.dev/v1alpha1/packagerevisions", nil)
+		req.Header.Set("User-Agent", userAgent)
+		if userName != "" {
+			req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userName}))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 1; i <= 200; i++ {
+		rec := serve("controller/v1", "controller")
+		if i <= 100 {
+			if rec.Code != http.StatusOK {
+				t.Errorf("request %d: got status %d, want %d", i, rec.Code, http.StatusOK)
+			}
+			continue
+		}
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("request %d: got status %d, want %d", i, rec.Code, http.StatusTooManyRequests)
+			continue
+		}
+		if got, want := rec.Header().Get("Retry-After"), "1"; got != want {
+			t.Errorf("request %d: Retry-After: got %q, want %q", i, got, want)
+		}
+	}
+
+	// Other user agents have their own limit.
+	if rec := serve("kubectl/v1.23.5", "alice"); rec.Code != http.StatusOK {
+		t.Errorf("another user agent: got status %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	// The kube-controller-manager and kube-scheduler users are exempt.
+	for _, name := range []string{"system:kube-controller-manager", "system:kube-scheduler"} {
+		if rec := serve("controller/v1", name); rec.Code != http.StatusOK {
+			t.Errorf("exempt user %s: got status %d, want %d", name, rec.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestAPIRateLimitDisabled(t *testing.T) {
+	if limiter := NewAPIRateLimiter(0, DefaultAPIRateLimitBurst); limiter != nil {
+		t.Fatalf("expected zero rpm to disable the limiter")
+	}
+}
+
+func TestKeyedLimitersEviction(t *testing.T) {
+	limiters := newKeyedLimiters(60, 2)
+	limiters.maxKeys = 2
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, key := range []string{"a", "b"} {
+		for i := 0; i < 2; i++ {
+			if retryAfter := limiters.reserve(key, now); retryAfter != 0 {
+				t.Errorf("key %q request %d: unexpected retry after %ds", key, i, retryAfter)
+			}
+		}
+	}
+
+	// Once all keys are in use, new keys share the overflow limiter.
+	for i, key := range []string{"c", "d", "e"} {
+		retryAfter := limiters.reserve(key, now)
+		if i < 2 && retryAfter != 0 {
+			t.Errorf("key %q: unexpected retry after %ds", key, retryAfter)
+		}
+		if i >= 2 && retryAfter == 0 {
+			t.Errorf("key %q: rotating keys escaped the limit", key)
+		}
+	}
+	if got, want := len(limiters.limiters), 2; got != want {
+		t.Errorf("got %d limiters, want %d", got, want)
+	}
+
+	// Limiters whose bucket has refilled are evicted.
+	now = now.Add(limiters.idle)
+	if retryAfter := limiters.reserve("f", now); retryAfter != 0 {
+		t.Errorf("key %q: unexpected retry after %ds", "f", retryAfter)
+	}
+	if _, found := limiters.limiters["a"]; found {
+		t.Errorf("idle limiter of key %q was not evicted", "a")
+	}
+	if got, want := len(limiters.limiters), 1; got != want {
+		t.Errorf("got %d limiters, want %d", got, want)
+	}
+}
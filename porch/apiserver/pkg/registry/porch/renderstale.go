@@ -0,0 +1,158 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// conditionRenderStale is set on package revisions rendered with function
+	// images whose digests changed since the render.
+	conditionRenderStale = "RenderStale"
+
+	// DefaultRenderStalenessPeriod is how often the digests of the rendered
+	// functions are checked for changes.
+	DefaultRenderStalenessPeriod = 10 * time.Minute
+)
+
+// RenderStalenessTracker watches the digests of the function images used to
+// render package revisions, and marks package revisions rendered with images
+// which changed since as stale.
+type RenderStalenessTracker struct {
+	resolver engine.FunctionDigestResolver
+
+	mutex sync.RWMutex
+	// images records the latest observed digest of every image used in a
+	// render. Images are tracked once they are seen in a package revision.
+	images map[string]observedDigest
+	clock  clock.Clock
+}
+
+type observedDigest struct {
+	digest    string
+	changedAt time.Time
+}
+
+func NewRenderStalenessTracker(resolver engine.FunctionDigestResolver, clk clock.Clock) *RenderStalenessTracker {
+	return &RenderStalenessTracker{
+		resolver: resolver,
+		images:   map[string]observedDigest{},
+		clock:    clk,
+	}
+}
+
+// Run refreshes the digests of the tracked images every period until ctx is done.
+func (t *RenderStalenessTracker) Run(ctx context.Context, period time.Duration) {
+	ticker := t.clock.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			t.refresh(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *RenderStalenessTracker) refresh(ctx context.Context) {
+	t.mutex.RLock()
+	images := make([]string, 0, len(t.images))
+	for image := range t.images {
+		images = append(images, image)
+	}
+	t.mutex.RUnlock()
+
+	for _, image := range images {
+		digest, err := t.resolver.ResolveDigest(ctx, image)
+		if err != nil {
+			klog.Warningf("cannot resolve digest of function %q: %v", image, err)
+			continue
+		}
+		t.observe(image, digest)
+	}
+}
+
+func (t *RenderStalenessTracker) observe(image, digest string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if current, found := t.images[image]; !found || current.digest != digest {
+		if found && current.digest != "" {
+			klog.Infof("digest of function %q changed from %s to %s", image, current.digest, digest)
+		}
+		t.images[image] = observedDigest{digest: digest, changedAt: t.clock.Now()}
+	}
+}
+
+// UpdateConditions sets the RenderStale condition of the package revision.
+// Package revisions without a recorded render are left unchanged.
+func (t *RenderStalenessTracker) UpdateConditions(pr *api.PackageRevision) {
+	if t == nil || len(pr.Status.LastRenderedFunctionDigests) == 0 {
+		return
+	}
+
+	var stale []string
+	var changedAt time.Time
+
+	t.mutex.Lock()
+	for image, rendered := range pr.Status.LastRenderedFunctionDigests {
+		current, found := t.images[image]
+		if !found {
+			// Start tracking the image; its digest is resolved on the next refresh.
+			t.images[image] = observedDigest{}
+			continue
+		}
+		if rendered == "" || current.digest == "" || current.digest == rendered {
+			continue
+		}
+		stale = append(stale, image)
+		if current.changedAt.After(changedAt) {
+			changedAt = current.changedAt
+		}
+	}
+	t.mutex.Unlock()
+
+	condition := metav1.Condition{
+		Type:               conditionRenderStale,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: pr.Generation,
+		LastTransitionTime: pr.Status.LastRenderedAt,
+		Reason:             "FunctionsUnchanged",
+		Message:            "Functions have not changed since the last render",
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		condition.Status = metav1.ConditionTrue
+		condition.LastTransitionTime = metav1.Time{Time: changedAt}
+		condition.Reason = "FunctionDigestChanged"
+		condition.Message = fmt.Sprintf("Functions changed since the last render: %s", strings.Join(stale, ", "))
+	}
+	meta.SetStatusCondition(&pr.Status.Conditions, condition)
+}
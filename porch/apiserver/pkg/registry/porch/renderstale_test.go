@@ -0,0 +1,80 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock/fakeclock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeDigestResolver map[string]string
+
+func (r fakeDigestResolver) ResolveDigest(ctx context.Context, image string) (string, error) {
+	return r[image], nil
+}
+
+func TestRenderStalenessTracker(t *testing.T) {
+	const image = "gcr.io/kpt-fn/set-labels:v0.1"
+
+	resolver := fakeDigestResolver{image: "sha256:aaa"}
+	clock := fakeclock.NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRenderStalenessTracker(resolver, clock)
+
+	newPackageRevision := func() *api.PackageRevision {
+		return &api.PackageRevision{
+			Status: api.PackageRevisionStatus{
+				LastRenderedAt:              metav1.Time{Time: time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)},
+				LastRenderedFunctionDigests: map[string]string{image: "sha256:aaa"},
+			},
+		}
+	}
+
+	// The first read starts tracking the image.
+	tracker.UpdateConditions(newPackageRevision())
+	tracker.refresh(context.Background())
+
+	pr := newPackageRevision()
+	tracker.UpdateConditions(pr)
+	if !meta.IsStatusConditionFalse(pr.Status.Conditions, conditionRenderStale) {
+		t.Errorf("expected %s=False with unchanged function; got %v", conditionRenderStale, pr.Status.Conditions)
+	}
+
+	// The function image is updated in the registry.
+	clock.Advance(time.Hour)
+	resolver[image] = "sha256:bbb"
+	tracker.refresh(context.Background())
+
+	pr = newPackageRevision()
+	tracker.UpdateConditions(pr)
+	if !meta.IsStatusConditionTrue(pr.Status.Conditions, conditionRenderStale) {
+		t.Errorf("expected %s=True after function digest changed; got %v", conditionRenderStale, pr.Status.Conditions)
+	}
+	if got, want := meta.FindStatusCondition(pr.Status.Conditions, conditionRenderStale).LastTransitionTime.Time, clock.Now(); !got.Equal(want) {
+		t.Errorf("%s transition time: got %v, want %v", conditionRenderStale, got, want)
+	}
+
+	// Package revisions without a recorded render have no condition.
+	pr = &api.PackageRevision{}
+	tracker.UpdateConditions(pr)
+	if len(pr.Status.Conditions) != 0 {
+		t.Errorf("unexpected conditions on package revision without render: %v", pr.Status.Conditions)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/git"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/oci"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RepositoryConnectionTester tests the connectivity to the git or OCI server
+// backing a repository.
+type RepositoryConnectionTester interface {
+	// TestConnection performs a read-only request to the server backing the
+	// repository. It returns the user name of the repository credentials, if
+	// any.
+	TestConnection(ctx context.Context, repository *configapi.Repository) (string, error)
+}
+
+// RepositoryConnectionTesterFunc adapts a function to a RepositoryConnectionTester.
+type RepositoryConnectionTesterFunc func(ctx context.Context, repository *configapi.Repository) (string, error)
+
+func (f RepositoryConnectionTesterFunc) TestConnection(ctx context.Context, repository *configapi.Repository) (string, error) {
+	return f(ctx, repository)
+}
+
+// NewRepositoryConnectionTester returns a tester which lists the references
+// of git repositories, authenticating with the credentials resolved by the
+// resolver, and the tags of OCI repositories.
+func NewRepositoryConnectionTester(resolver repository.CredentialResolver) RepositoryConnectionTester {
+	return RepositoryConnectionTesterFunc(func(ctx context.Context, repositoryObj *configapi.Repository) (string, error) {
+		switch repositoryObj.Spec.Type {
+		case configapi.RepositoryTypeGit:
+			if repositoryObj.Spec.Git == nil {
+				return "", fmt.Errorf("git repository %s is missing the git spec", repositoryObj.Name)
+			}
+			return git.TestConnection(ctx, repositoryObj.Namespace, repositoryObj.Spec.Git, resolver)
+		case configapi.RepositoryTypeOCI:
+			if repositoryObj.Spec.Oci == nil {
+				return "", fmt.Errorf("oci repository %s is missing the oci spec", repositoryObj.Name)
+			}
+			return "", oci.TestConnection(ctx, repositoryObj.Spec.Oci)
+		default:
+			return "", fmt.Errorf("type %q of repository %s is not supported", repositoryObj.Spec.Type, repositoryObj.Name)
+		}
+	})
+}
+
+// repositoryConnectionTests serves the create-only RepositoryConnectionTest
+// resource, which tests the connectivity to the server backing a Repository.
+type repositoryConnectionTests struct {
+	coreClient     client.Client
+	createStrategy SimpleRESTCreateStrategy
+	tester         RepositoryConnectionTester
+	now            func() time.Time
+}
+
+var _ rest.Storage = &repositoryConnectionTests{}
+var _ rest.Scoper = &repositoryConnectionTests{}
+var _ rest.Creater = &repositoryConnectionTests{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (r *repositoryConnectionTests) New() runtime.Object {
+	return &api.RepositoryConnectionTest{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (r *repositoryConnectionTests) NamespaceScoped() bool {
+	return true
+}
+
+// Create tests the connectivity to the server backing the Repository named by
+// the posted RepositoryConnectionTest. A failed test is reported in the
+// status of the returned object rather than as an error.
+func (r *repositoryConnectionTests) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
+	if !namespaced {
+		return nil, apierrors.NewBadRequest("namespace must be specified")
+	}
+
+	test, ok := obj.(*api.RepositoryConnectionTest)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected RepositoryConnectionTest object, got %T", obj))
+	}
+	if err := applyCreateStrategy(ctx, r.createStrategy, test); err != nil {
+		return nil, err
+	}
+
+	if createValidation != nil {
+		if err := createValidation(ctx, test); err != nil {
+			return nil, err
+		}
+	}
+
+	var repositoryObj configapi.Repository
+	repositoryID := types.NamespacedName{Namespace: ns, Name: test.Name}
+	if err := r.coreClient.Get(ctx, repositoryID, &repositoryObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, apierrors.NewNotFound(configapi.KindRepository.GroupResource(), repositoryID.Name)
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting repository %v: %w", repositoryID, err))
+	}
+
+	start := r.now()
+	authenticatedAs, err := r.tester.TestConnection(ctx, &repositoryObj)
+	result := api.ConnectionTestResult{
+		Success:         err == nil,
+		LatencyMs:       r.now().Sub(start).Milliseconds(),
+		AuthenticatedAs: authenticatedAs,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return &api.RepositoryConnectionTest{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RepositoryConnectionTest",
+			APIVersion: api.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      test.Name,
+			Namespace: ns,
+		},
+		Status: result,
+	}, nil
+}
+
+// repositoryConnectionTestStrategy requires the name of the repository to
+// test.
+type repositoryConnectionTestStrategy struct {
+	NoopCreateStrategy
+}
+
+var _ SimpleRESTCreateStrategy = repositoryConnectionTestStrategy{}
+
+func (s repositoryConnectionTestStrategy) ValidateCreate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if obj.(*api.RepositoryConnectionTest).Name == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("metadata", "name"), "name of the repository to test must be specified"))
+	}
+	return allErrs
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestRepositoryConnectionTests(t *testing.T, tester RepositoryConnectionTesterFunc, repositories ...*configapi.Repository) *repositoryConnectionTests {
+	scheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme failed: %v", err)
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, repository := range repositories {
+		builder = builder.WithObjects(repository)
+	}
+
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	return &repositoryConnectionTests{
+		coreClient:     builder.Build(),
+		createStrategy: repositoryConnectionTestStrategy{},
+		tester:         tester,
+		now: func() time.Time {
+			now = now.Add(25 * time.Millisecond)
+			return now
+		},
+	}
+}
+
+func TestRepositoryConnectionTestCreate(t *testing.T) {
+	repositoryObj := &configapi.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "blueprints", Namespace: "default"},
+		Spec: configapi.RepositorySpec{
+			Type: configapi.RepositoryTypeGit,
+			Git:  &configapi.GitRepository{Repo: "https://example.com/blueprints.git"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		err    error
+		user   string
+		status api.ConnectionTestResult
+	}{
+		{
+			name:   "Success",
+			user:   "porch-bot",
+			status: api.ConnectionTestResult{Success: true, LatencyMs: 25, AuthenticatedAs: "porch-bot"},
+		},
+		{
+			name:   "Failure",
+			err:    errors.New("authentication required"),
+			status: api.ConnectionTestResult{Success: false, LatencyMs: 25, Error: "authentication required"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var tested string
+			r := newTestRepositoryConnectionTests(t, func(ctx context.Context, repository *configapi.Repository) (string, error) {
+				tested = repository.Spec.Git.Repo
+				return tc.user, tc.err
+			}, repositoryObj.DeepCopy())
+
+			ctx := genericapirequest.WithNamespace(context.Background(), "default")
+			obj, err := r.Create(ctx, &api.RepositoryConnectionTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "blueprints"},
+			}, nil, &metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			if got, want := tested, repositoryObj.Spec.Git.Repo; got != want {
+				t.Errorf("tested repository %q, want %q", got, want)
+			}
+			result := obj.(*api.RepositoryConnectionTest)
+			if got, want := result.Namespace, "default"; got != want {
+				t.Errorf("namespace: got %q, want %q", got, want)
+			}
+			if diff := cmp.Diff(tc.status, result.Status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRepositoryConnectionTestCreateErrors(t *testing.T) {
+	r := newTestRepositoryConnectionTests(t, func(ctx context.Context, repository *configapi.Repository) (string, error) {
+		t.Errorf("unexpected connection test of repository %s", repository.Name)
+		return "", nil
+	})
+
+	ctx := genericapirequest.WithNamespace(context.Background(), "default")
+
+	if _, err := r.Create(ctx, &api.RepositoryConnectionTest{}, nil, &metav1.CreateOptions{}); !apierrors.IsBadRequest(err) {
+		t.Errorf("Create without name: got %v, want BadRequest", err)
+	}
+
+	_, err := r.Create(ctx, &api.RepositoryConnectionTest{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing"},
+	}, nil, &metav1.CreateOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Create of missing repository: got %v, want NotFound", err)
+	}
+}
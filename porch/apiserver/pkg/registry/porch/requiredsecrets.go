@@ -0,0 +1,113 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RequiredSecretChecker checks that the secrets required by a package
+// revision exist in its target cluster.
+type RequiredSecretChecker struct {
+	coreClient client.Reader
+	// newTargetClient returns a client for the cluster described by the
+	// kubeconfig; overridden in tests.
+	newTargetClient func(kubeconfig []byte) (client.Reader, error)
+}
+
+func NewRequiredSecretChecker(coreClient client.Reader) *RequiredSecretChecker {
+	return &RequiredSecretChecker{
+		coreClient:      coreClient,
+		newTargetClient: newKubeconfigClient,
+	}
+}
+
+// MissingSecrets returns a description of each required secret of the
+// package revision which does not exist in its target cluster, or lacks one
+// of the required keys.
+func (c *RequiredSecretChecker) MissingSecrets(ctx context.Context, pr *api.PackageRevision) ([]string, error) {
+	if len(pr.Spec.RequiredSecrets) == 0 {
+		return nil, nil
+	}
+	target, err := c.targetClient(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, required := range pr.Spec.RequiredSecrets {
+		namespace := required.Namespace
+		if namespace == "" {
+			namespace = pr.Namespace
+		}
+		var secret core.Secret
+		if err := target.Get(ctx, client.ObjectKey{Namespace: namespace, Name: required.Name}, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				missing = append(missing, fmt.Sprintf("%s/%s", namespace, required.Name))
+				continue
+			}
+			return nil, fmt.Errorf("cannot read secret %s/%s: %w", namespace, required.Name, err)
+		}
+		var missingKeys []string
+		for _, key := range required.Keys {
+			if _, ok := secret.Data[key]; !ok {
+				missingKeys = append(missingKeys, key)
+			}
+		}
+		if len(missingKeys) > 0 {
+			missing = append(missing, fmt.Sprintf("%s/%s (missing keys: %s)", namespace, required.Name, strings.Join(missingKeys, ", ")))
+		}
+	}
+	return missing, nil
+}
+
+// targetClient returns a client for the target cluster of the package
+// revision: the cluster described by the referenced kubeconfig secret, or
+// the cluster porch runs in if there is none.
+func (c *RequiredSecretChecker) targetClient(ctx context.Context, pr *api.PackageRevision) (client.Reader, error) {
+	if pr.Spec.Target == nil || pr.Spec.Target.Kubeconfig == nil {
+		return c.coreClient, nil
+	}
+	name := pr.Spec.Target.Kubeconfig.Name
+	var secret core.Secret
+	if err := c.coreClient.Get(ctx, client.ObjectKey{Namespace: pr.Namespace, Name: name}, &secret); err != nil {
+		return nil, fmt.Errorf("cannot read kubeconfig secret %s/%s: %w", pr.Namespace, name, err)
+	}
+	kubeconfig, ok := secret.Data[api.TargetKubeconfigKey]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig secret %s/%s has no %q key", pr.Namespace, name, api.TargetKubeconfigKey)
+	}
+	target, err := c.newTargetClient(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create client for the target cluster in kubeconfig secret %s/%s: %w", pr.Namespace, name, err)
+	}
+	return target, nil
+}
+
+func newKubeconfigClient(kubeconfig []byte) (client.Reader, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{})
+}
@@ -0,0 +1,154 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestSecret(namespace, name string, keys ...string) *core.Secret {
+	data := map[string][]byte{}
+	for _, key := range keys {
+		data[key] = []byte("value")
+	}
+	return &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data:       data,
+	}
+}
+
+func newRequiredSecretsPackageRevision(lifecycle api.PackageRevisionLifecycle, target *api.PackageRevisionTarget, secrets ...api.RequiredSecret) *api.PackageRevision {
+	return &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "porch", Name: "repo-app-v1"},
+		Spec: api.PackageRevisionSpec{
+			Revision:        "v1",
+			Lifecycle:       lifecycle,
+			RequiredSecrets: secrets,
+			Target:          target,
+		},
+	}
+}
+
+func TestMissingSecrets(t *testing.T) {
+	coreClient := fake.NewClientBuilder().WithObjects(
+		newTestSecret("porch", "tls", "tls.crt", "tls.key"),
+		newTestSecret("app", "db-credentials", "username"),
+	).Build()
+	checker := &RequiredSecretChecker{coreClient: coreClient}
+
+	pr := newRequiredSecretsPackageRevision(api.PackageRevisionLifecycleProposed, nil,
+		api.RequiredSecret{Name: "tls", Keys: []string{"tls.crt", "tls.key"}},
+		api.RequiredSecret{Name: "db-credentials", Namespace: "app", Keys: []string{"username", "password"}},
+		api.RequiredSecret{Name: "api-token", Namespace: "app"},
+	)
+	missing, err := checker.MissingSecrets(context.Background(), pr)
+	if err != nil {
+		t.Fatalf("MissingSecrets failed: %v", err)
+	}
+	want := []string{
+		"app/db-credentials (missing keys: password)",
+		"app/api-token",
+	}
+	if diff := cmp.Diff(want, missing); diff != "" {
+		t.Errorf("MissingSecrets (-want, +got): %s", diff)
+	}
+}
+
+func TestMissingSecretsTargetKubeconfig(t *testing.T) {
+	coreClient := fake.NewClientBuilder().WithObjects(
+		newTestSecret("porch", "prod-kubeconfig", api.TargetKubeconfigKey),
+		newTestSecret("porch", "broken-kubeconfig"),
+	).Build()
+	targetClient := fake.NewClientBuilder().WithObjects(
+		newTestSecret("app", "db-credentials", "username", "password"),
+	).Build()
+	checker := &RequiredSecretChecker{
+		coreClient: coreClient,
+		newTargetClient: func(kubeconfig []byte) (client.Reader, error) {
+			return targetClient, nil
+		},
+	}
+	required := api.RequiredSecret{Name: "db-credentials", Namespace: "app", Keys: []string{"password"}}
+
+	// The secret exists only in the target cluster.
+	pr := newRequiredSecretsPackageRevision(api.PackageRevisionLifecycleProposed,
+		&api.PackageRevisionTarget{Kubeconfig: &api.SecretRef{Name: "prod-kubeconfig"}}, required)
+	if missing, err := checker.MissingSecrets(context.Background(), pr); err != nil || len(missing) > 0 {
+		t.Errorf("MissingSecrets = %v, %v; want none", missing, err)
+	}
+	pr.Spec.Target = nil
+	if missing, err := checker.MissingSecrets(context.Background(), pr); err != nil || len(missing) != 1 {
+		t.Errorf("MissingSecrets without target = %v, %v; want the secret to be missing", missing, err)
+	}
+
+	for _, name := range []string{"no-such-kubeconfig", "broken-kubeconfig"} {
+		pr.Spec.Target = &api.PackageRevisionTarget{Kubeconfig: &api.SecretRef{Name: name}}
+		if _, err := checker.MissingSecrets(context.Background(), pr); err == nil {
+			t.Errorf("MissingSecrets with kubeconfig secret %q should fail but didn't", name)
+		}
+	}
+
+	checker.newTargetClient = func(kubeconfig []byte) (client.Reader, error) {
+		return nil, errors.New("invalid kubeconfig")
+	}
+	pr.Spec.Target = &api.PackageRevisionTarget{Kubeconfig: &api.SecretRef{Name: "prod-kubeconfig"}}
+	if _, err := checker.MissingSecrets(context.Background(), pr); err == nil {
+		t.Errorf("MissingSecrets with an invalid kubeconfig should fail but didn't")
+	}
+}
+
+func TestApprovalRequiredSecrets(t *testing.T) {
+	coreClient := fake.NewClientBuilder().WithObjects(newTestSecret("porch", "tls", "tls.crt")).Build()
+	s := packageRevisionApprovalStrategy{secretChecker: &RequiredSecretChecker{coreClient: coreClient}}
+
+	old := newRequiredSecretsPackageRevision(api.PackageRevisionLifecycleProposed, nil,
+		api.RequiredSecret{Name: "tls", Keys: []string{"tls.crt"}},
+		api.RequiredSecret{Name: "api-token"},
+	)
+	approved := old.DeepCopy()
+	approved.Spec.Lifecycle = api.PackageRevisionLifecyclePublished
+	// Dropping the requirements in the approval request does not skip the check.
+	approved.Spec.RequiredSecrets = nil
+
+	if allErrs := s.ValidateUpdate(context.Background(), approved, old); len(allErrs) != 1 {
+		t.Errorf("ValidateUpdate returned %v; want the missing secret to be reported", allErrs.ToAggregate())
+	}
+
+	rejected := old.DeepCopy()
+	rejected.Spec.Lifecycle = api.PackageRevisionLifecycleDraft
+	if allErrs := s.ValidateUpdate(context.Background(), rejected, old); len(allErrs) > 0 {
+		t.Errorf("ValidateUpdate of rejection failed unexpectedly: %v", allErrs.ToAggregate())
+	}
+
+	old.Spec.RequiredSecrets = old.Spec.RequiredSecrets[:1]
+	if allErrs := s.ValidateUpdate(context.Background(), approved, old); len(allErrs) > 0 {
+		t.Errorf("ValidateUpdate failed unexpectedly: %v", allErrs.ToAggregate())
+	}
+
+	// Without a checker, the secrets are not checked.
+	old.Spec.RequiredSecrets = append(old.Spec.RequiredSecrets, api.RequiredSecret{Name: "api-token"})
+	if allErrs := (packageRevisionApprovalStrategy{}).ValidateUpdate(context.Background(), approved, old); len(allErrs) > 0 {
+		t.Errorf("ValidateUpdate with the secret check skipped failed unexpectedly: %v", allErrs.ToAggregate())
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/klog/v2"
+)
+
+// packageRevisionResourcesResolveConflict replaces a file of a draft package
+// revision left with merge conflicts by its resolved content.
+type packageRevisionResourcesResolveConflict struct {
+	resources *packageRevisionResources
+}
+
+var _ rest.Storage = &packageRevisionResourcesResolveConflict{}
+var _ rest.Scoper = &packageRevisionResourcesResolveConflict{}
+var _ rest.Updater = &packageRevisionResourcesResolveConflict{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (r *packageRevisionResourcesResolveConflict) New() runtime.Object {
+	return &api.ConflictResolution{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (r *packageRevisionResourcesResolveConflict) NamespaceScoped() bool {
+	return true
+}
+
+// Update overwrites the file of the put ConflictResolution with its resolved
+// content, clearing the merge conflict annotation of its resources, and
+// reports the files of the package revision still in conflict.
+func (r *packageRevisionResourcesResolveConflict) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
+	if !namespaced {
+		return nil, false, apierrors.NewBadRequest("namespace must be specified")
+	}
+
+	oldPackage, err := r.resources.getPackage(ctx, name)
+	if err != nil {
+		return nil, false, err
+	}
+	oldObj, err := oldPackage.GetResources(ctx)
+	if err != nil {
+		klog.Infof("resolving conflict failed to retrieve package resources: %v", err)
+		return nil, false, err
+	}
+
+	newRuntimeObj, err := objInfo.UpdatedObject(ctx, &api.ConflictResolution{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	resolution, ok := newRuntimeObj.(*api.ConflictResolution)
+	if !ok {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("expected ConflictResolution object, got %T", newRuntimeObj))
+	}
+
+	filename := resolution.Spec.Filename
+	contents, found := oldObj.Spec.Resources[filename]
+	if !found {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("file %q not found in package revision %s", filename, name))
+	}
+	if !fileHasConflicts(filename, contents) {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("file %q of package revision %s has no merge conflicts", filename, name))
+	}
+	if hasConflictMarkers(resolution.Spec.ResolvedContent) {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("resolved content of file %q contains conflict markers", filename))
+	}
+	resolved, err := clearMergeConflictAnnotation(filename, resolution.Spec.ResolvedContent)
+	if err != nil {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("invalid resolved content of file %q: %v", filename, err))
+	}
+
+	newObj := oldObj.DeepCopy()
+	newObj.Spec.Resources[filename] = resolved
+	updated, err := r.resources.updateResources(ctx, ns, name, oldPackage, oldObj, newObj)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := resolution.DeepCopy()
+	result.Status.RemainingConflicts = conflictedFiles(updated.Spec.Resources)
+	return result, false, nil
+}
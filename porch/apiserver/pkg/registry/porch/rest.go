@@ -17,8 +17,11 @@ package porch
 import (
 	"context"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
 )
 
 // SimpleRESTUpdateStrategy is similar to rest.RESTUpdateStrategy, though only contains
@@ -36,3 +39,129 @@ func (s NoopUpdateStrategy) ValidateUpdate(ctx context.Context, obj, old runtime
 	return nil
 }
 func (s NoopUpdateStrategy) Canonicalize(obj runtime.Object) {}
+
+// ComposeUpdateStrategies returns a strategy which applies all the strategies
+// in order and reports the validation errors of all of them.
+func ComposeUpdateStrategies(strategies ...SimpleRESTUpdateStrategy) SimpleRESTUpdateStrategy {
+	return composedUpdateStrategy(strategies)
+}
+
+type composedUpdateStrategy []SimpleRESTUpdateStrategy
+
+func (c composedUpdateStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	for _, s := range c {
+		s.PrepareForUpdate(ctx, obj, old)
+	}
+}
+
+func (c composedUpdateStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, s := range c {
+		allErrs = append(allErrs, s.ValidateUpdate(ctx, obj, old)...)
+	}
+	return allErrs
+}
+
+func (c composedUpdateStrategy) Canonicalize(obj runtime.Object) {
+	canonicalizers := make([]func(runtime.Object), len(c))
+	for i, s := range c {
+		canonicalizers[i] = s.Canonicalize
+	}
+	canonicalizeAll(canonicalizers, obj)
+}
+
+// SimpleRESTCreateStrategy is similar to rest.RESTCreateStrategy, though only contains
+// methods currently required.
+type SimpleRESTCreateStrategy interface {
+	PrepareForCreate(ctx context.Context, obj runtime.Object)
+	ValidateCreate(ctx context.Context, obj runtime.Object) field.ErrorList
+	Canonicalize(obj runtime.Object)
+}
+
+type NoopCreateStrategy struct{}
+
+func (s NoopCreateStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {}
+func (s NoopCreateStrategy) ValidateCreate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	return nil
+}
+func (s NoopCreateStrategy) Canonicalize(obj runtime.Object) {}
+
+// SimpleRESTDeleteStrategy validates whether an object may be deleted. It is
+// the counterpart of the create and update strategies; rest.RESTDeleteStrategy
+// has no validation hook.
+type SimpleRESTDeleteStrategy interface {
+	PrepareForDelete(ctx context.Context, obj runtime.Object)
+	ValidateDelete(ctx context.Context, obj runtime.Object) field.ErrorList
+}
+
+type NoopDeleteStrategy struct{}
+
+func (s NoopDeleteStrategy) PrepareForDelete(ctx context.Context, obj runtime.Object) {}
+func (s NoopDeleteStrategy) ValidateDelete(ctx context.Context, obj runtime.Object) field.ErrorList {
+	return nil
+}
+
+// ComposeCreateStrategies returns a strategy which applies all the strategies
+// in order and reports the validation errors of all of them.
+func ComposeCreateStrategies(strategies ...SimpleRESTCreateStrategy) SimpleRESTCreateStrategy {
+	return composedCreateStrategy(strategies)
+}
+
+type composedCreateStrategy []SimpleRESTCreateStrategy
+
+func (c composedCreateStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	for _, s := range c {
+		s.PrepareForCreate(ctx, obj)
+	}
+}
+
+func (c composedCreateStrategy) ValidateCreate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, s := range c {
+		allErrs = append(allErrs, s.ValidateCreate(ctx, obj)...)
+	}
+	return allErrs
+}
+
+func (c composedCreateStrategy) Canonicalize(obj runtime.Object) {
+	canonicalizers := make([]func(runtime.Object), len(c))
+	for i, s := range c {
+		canonicalizers[i] = s.Canonicalize
+	}
+	canonicalizeAll(canonicalizers, obj)
+}
+
+// canonicalizeAll calls the canonicalizers in order. Canonicalize runs after
+// validation, so a panicking strategy is recovered from and stops the
+// remaining canonicalizers rather than failing the request.
+func canonicalizeAll(canonicalizers []func(runtime.Object), obj runtime.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			klog.Errorf("canonicalizing %T panicked: %v", obj, r)
+		}
+	}()
+	for _, canonicalize := range canonicalizers {
+		canonicalize(obj)
+	}
+}
+
+// applyCreateStrategy prepares, validates and canonicalizes a new object with
+// the strategy. Validation errors are returned as BadRequest.
+func applyCreateStrategy(ctx context.Context, strategy SimpleRESTCreateStrategy, obj runtime.Object) error {
+	strategy.PrepareForCreate(ctx, obj)
+	if fieldErrors := strategy.ValidateCreate(ctx, obj); len(fieldErrors) > 0 {
+		return apierrors.NewBadRequest(fieldErrors.ToAggregate().Error())
+	}
+	strategy.Canonicalize(obj)
+	return nil
+}
+
+// applyDeleteStrategy prepares and validates an object about to be deleted
+// with the strategy. Validation errors are returned as Invalid.
+func applyDeleteStrategy(ctx context.Context, strategy SimpleRESTDeleteStrategy, qualifiedKind schema.GroupKind, name string, obj runtime.Object) error {
+	strategy.PrepareForDelete(ctx, obj)
+	if fieldErrors := strategy.ValidateDelete(ctx, obj); len(fieldErrors) > 0 {
+		return apierrors.NewInvalid(qualifiedKind, name, fieldErrors)
+	}
+	return nil
+}
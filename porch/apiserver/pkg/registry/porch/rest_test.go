@@ -0,0 +1,291 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// recordingCreateStrategy records the calls to the strategy and fails the
+// validation with errs.
+type recordingCreateStrategy struct {
+	calls []string
+	errs  field.ErrorList
+}
+
+func (s *recordingCreateStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	s.calls = append(s.calls, "PrepareForCreate")
+}
+
+func (s *recordingCreateStrategy) ValidateCreate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	s.calls = append(s.calls, "ValidateCreate")
+	return s.errs
+}
+
+func (s *recordingCreateStrategy) Canonicalize(obj runtime.Object) {
+	s.calls = append(s.calls, "Canonicalize")
+}
+
+func TestApplyCreateStrategy(t *testing.T) {
+	strategy := &recordingCreateStrategy{}
+	if err := applyCreateStrategy(context.Background(), strategy, &api.PackageRevision{}); err != nil {
+		t.Fatalf("applyCreateStrategy failed: %v", err)
+	}
+	if got, want := len(strategy.calls), 3; got != want {
+		t.Errorf("strategy calls: got %v, want PrepareForCreate, ValidateCreate, Canonicalize", strategy.calls)
+	}
+
+	if err := applyCreateStrategy(context.Background(), NoopCreateStrategy{}, &api.PackageRevision{}); err != nil {
+		t.Errorf("applyCreateStrategy with NoopCreateStrategy failed: %v", err)
+	}
+}
+
+func TestCreateStrategyValidationErrors(t *testing.T) {
+	invalid := field.ErrorList{field.Invalid(field.NewPath("spec", "packageName"), "bad", "test error")}
+	ctx := genericapirequest.WithNamespace(context.Background(), "default")
+
+	t.Run("PackageRevision", func(t *testing.T) {
+		strategy := &recordingCreateStrategy{errs: invalid}
+		r := &packageRevisions{createStrategy: strategy}
+		_, err := r.Create(ctx, &api.PackageRevision{}, nil, &metav1.CreateOptions{})
+		if !apierrors.IsBadRequest(err) {
+			t.Errorf("Create: got %v, want BadRequest", err)
+		}
+		if got, want := len(strategy.calls), 2; got != want {
+			t.Errorf("strategy calls: got %v, want PrepareForCreate, ValidateCreate", strategy.calls)
+		}
+	})
+
+	t.Run("RepositoryConnectionTest", func(t *testing.T) {
+		strategy := &recordingCreateStrategy{errs: invalid}
+		r := &repositoryConnectionTests{createStrategy: strategy}
+		_, err := r.Create(ctx, &api.RepositoryConnectionTest{
+			ObjectMeta: metav1.ObjectMeta{Name: "blueprints"},
+		}, nil, &metav1.CreateOptions{})
+		if !apierrors.IsBadRequest(err) {
+			t.Errorf("Create: got %v, want BadRequest", err)
+		}
+	})
+}
+
+func TestPackageRevisionCreateStrategy(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		objName  string
+		wantName string
+		wantErrs int
+	}{
+		{name: "defaulted name", wantName: "blueprints:bucket:v1"},
+		{name: "matching name", objName: "blueprints:bucket:v1", wantName: "blueprints:bucket:v1"},
+		{name: "mismatched name", objName: "blueprints:bucket:v2", wantName: "blueprints:bucket:v2", wantErrs: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := &api.PackageRevision{
+				ObjectMeta: metav1.ObjectMeta{Name: tc.objName},
+				Spec: api.PackageRevisionSpec{
+					RepositoryName: "blueprints",
+					PackageName:    "bucket",
+					Revision:       "v1",
+				},
+			}
+			s := packageRevisionCreateStrategy{}
+			s.PrepareForCreate(context.Background(), pr)
+			if got := pr.Name; got != tc.wantName {
+				t.Errorf("name: got %q, want %q", got, tc.wantName)
+			}
+			if errs := s.ValidateCreate(context.Background(), pr); len(errs) != tc.wantErrs {
+				t.Errorf("ValidateCreate: got %v, want %d errors", errs, tc.wantErrs)
+			}
+			s.Canonicalize(pr)
+			if got, want := pr.Spec.Lifecycle, api.PackageRevisionLifecycleDraft; got != want {
+				t.Errorf("lifecycle: got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyDeleteStrategy(t *testing.T) {
+	gk := api.SchemeGroupVersion.WithKind("PackageRevision").GroupKind()
+	if err := applyDeleteStrategy(context.Background(), NoopDeleteStrategy{}, gk, "blueprints:bucket:v1", &api.PackageRevision{}); err != nil {
+		t.Errorf("applyDeleteStrategy with NoopDeleteStrategy failed: %v", err)
+	}
+
+	published := &api.PackageRevision{
+		Spec: api.PackageRevisionSpec{Lifecycle: api.PackageRevisionLifecyclePublished},
+	}
+	err := applyDeleteStrategy(context.Background(), packageRevisionDeleteStrategy{}, gk, "blueprints:bucket:v1", published)
+	if !apierrors.IsInvalid(err) {
+		t.Errorf("applyDeleteStrategy: got %v, want Invalid", err)
+	}
+}
+
+func TestPackageRevisionDeleteStrategy(t *testing.T) {
+	for _, tc := range []struct {
+		lifecycle api.PackageRevisionLifecycle
+		force     bool
+		wantErrs  int
+	}{
+		{lifecycle: api.PackageRevisionLifecycleDraft},
+		{lifecycle: api.PackageRevisionLifecycleProposed},
+		{lifecycle: api.PackageRevisionLifecyclePublished, wantErrs: 1},
+		{lifecycle: api.PackageRevisionLifecyclePublished, force: true},
+	} {
+		pr := &api.PackageRevision{
+			Spec: api.PackageRevisionSpec{Lifecycle: tc.lifecycle},
+		}
+		if tc.force {
+			pr.Annotations = map[string]string{api.ForceDeleteAnnotation: "true"}
+		}
+		if errs := (packageRevisionDeleteStrategy{}).ValidateDelete(context.Background(), pr); len(errs) != tc.wantErrs {
+			t.Errorf("ValidateDelete(%s, force=%t): got %v, want %d errors", tc.lifecycle, tc.force, errs, tc.wantErrs)
+		}
+	}
+}
+
+func TestIsForceDelete(t *testing.T) {
+	zero, thirty := int64(0), int64(30)
+	for _, tc := range []struct {
+		options *metav1.DeleteOptions
+		want    bool
+	}{
+		{options: nil, want: false},
+		{options: &metav1.DeleteOptions{}, want: false},
+		{options: &metav1.DeleteOptions{GracePeriodSeconds: &thirty}, want: false},
+		{options: &metav1.DeleteOptions{GracePeriodSeconds: &zero}, want: true},
+	} {
+		if got := isForceDelete(tc.options); got != tc.want {
+			t.Errorf("isForceDelete(%v): got %t, want %t", tc.options, got, tc.want)
+		}
+	}
+}
+
+// orderedStrategy records its calls, prefixed with its name, to a shared log.
+type orderedStrategy struct {
+	name                string
+	log                 *[]string
+	errs                field.ErrorList
+	panicOnCanonicalize bool
+}
+
+func (s *orderedStrategy) record(call string) {
+	*s.log = append(*s.log, s.name+"."+call)
+}
+
+func (s *orderedStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	s.record("PrepareForUpdate")
+}
+
+func (s *orderedStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	s.record("ValidateUpdate")
+	return s.errs
+}
+
+func (s *orderedStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	s.record("PrepareForCreate")
+}
+
+func (s *orderedStrategy) ValidateCreate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	s.record("ValidateCreate")
+	return s.errs
+}
+
+func (s *orderedStrategy) Canonicalize(obj runtime.Object) {
+	s.record("Canonicalize")
+	if s.panicOnCanonicalize {
+		panic("broken strategy")
+	}
+}
+
+func TestComposeStrategies(t *testing.T) {
+	labelErr := field.Invalid(field.NewPath("metadata", "labels"), "", "missing label")
+	nameErr := field.Invalid(field.NewPath("metadata", "name"), "", "bad name")
+
+	for _, tc := range []struct {
+		name       string
+		strategies []orderedStrategy
+		wantErrs   int
+		wantUpdate []string
+		wantCreate []string
+	}{
+		{
+			name:       "empty",
+			wantUpdate: nil,
+			wantCreate: nil,
+		},
+		{
+			name:       "all strategies in order",
+			strategies: []orderedStrategy{{name: "a"}, {name: "b"}},
+			wantUpdate: []string{"a.PrepareForUpdate", "b.PrepareForUpdate", "a.ValidateUpdate", "b.ValidateUpdate", "a.Canonicalize", "b.Canonicalize"},
+			wantCreate: []string{"a.PrepareForCreate", "b.PrepareForCreate", "a.ValidateCreate", "b.ValidateCreate", "a.Canonicalize", "b.Canonicalize"},
+		},
+		{
+			name:       "errors collected",
+			strategies: []orderedStrategy{{name: "a", errs: field.ErrorList{labelErr}}, {name: "b", errs: field.ErrorList{nameErr}}},
+			wantErrs:   2,
+			wantUpdate: []string{"a.PrepareForUpdate", "b.PrepareForUpdate", "a.ValidateUpdate", "b.ValidateUpdate", "a.Canonicalize", "b.Canonicalize"},
+			wantCreate: []string{"a.PrepareForCreate", "b.PrepareForCreate", "a.ValidateCreate", "b.ValidateCreate", "a.Canonicalize", "b.Canonicalize"},
+		},
+		{
+			name:       "canonicalize panic",
+			strategies: []orderedStrategy{{name: "a", panicOnCanonicalize: true}, {name: "b", errs: field.ErrorList{nameErr}}},
+			wantErrs:   1,
+			wantUpdate: []string{"a.PrepareForUpdate", "b.PrepareForUpdate", "a.ValidateUpdate", "b.ValidateUpdate", "a.Canonicalize"},
+			wantCreate: []string{"a.PrepareForCreate", "b.PrepareForCreate", "a.ValidateCreate", "b.ValidateCreate", "a.Canonicalize"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var log []string
+			var updates []SimpleRESTUpdateStrategy
+			var creates []SimpleRESTCreateStrategy
+			for i := range tc.strategies {
+				s := tc.strategies[i]
+				s.log = &log
+				updates = append(updates, &s)
+				creates = append(creates, &s)
+			}
+			obj, old := &api.PackageRevision{}, &api.PackageRevision{}
+
+			update := ComposeUpdateStrategies(updates...)
+			update.PrepareForUpdate(context.Background(), obj, old)
+			if errs := update.ValidateUpdate(context.Background(), obj, old); len(errs) != tc.wantErrs {
+				t.Errorf("ValidateUpdate: got %v, want %d errors", errs, tc.wantErrs)
+			}
+			update.Canonicalize(obj)
+			if diff := cmp.Diff(tc.wantUpdate, log); diff != "" {
+				t.Errorf("update calls (-want, +got): %s", diff)
+			}
+
+			log = nil
+			create := ComposeCreateStrategies(creates...)
+			create.PrepareForCreate(context.Background(), obj)
+			if errs := create.ValidateCreate(context.Background(), obj); len(errs) != tc.wantErrs {
+				t.Errorf("ValidateCreate: got %v, want %d errors", errs, tc.wantErrs)
+			}
+			create.Canonicalize(obj)
+			if diff := cmp.Diff(tc.wantCreate, log); diff != "" {
+				t.Errorf("create calls (-want, +got): %s", diff)
+			}
+		})
+	}
+}
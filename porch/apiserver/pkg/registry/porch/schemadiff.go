@@ -0,0 +1,265 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+// packageRevisionResourcesSchemaDiff serves the kind-level difference between
+// the resources of two package revisions.
+type packageRevisionResourcesSchemaDiff struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageRevisionResourcesSchemaDiff{}
+var _ rest.Scoper = &packageRevisionResourcesSchemaDiff{}
+var _ rest.GetterWithOptions = &packageRevisionResourcesSchemaDiff{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (d *packageRevisionResourcesSchemaDiff) New() runtime.Object {
+	return &api.PackageRevisionSchemaDiff{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (d *packageRevisionResourcesSchemaDiff) NamespaceScoped() bool {
+	return true
+}
+
+// NewGetOptions returns the options object decoded from the query parameters.
+func (d *packageRevisionResourcesSchemaDiff) NewGetOptions() (runtime.Object, bool, string) {
+	return &api.PackageRevisionSchemaDiffOptions{}, false, ""
+}
+
+func (d *packageRevisionResourcesSchemaDiff) Get(ctx context.Context, name string, options runtime.Object) (runtime.Object, error) {
+	opts, ok := options.(*api.PackageRevisionSchemaDiffOptions)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid options object: %T", options))
+	}
+	if opts.Base == "" {
+		return nil, apierrors.NewBadRequest("the base package revision must be specified with the `base` query parameter")
+	}
+
+	resources, err := d.getResources(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	base, err := d.getResources(ctx, opts.Base)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := computeSchemaDiff(base.Spec.Resources, resources.Spec.Resources)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	diff.ObjectMeta = metav1.ObjectMeta{
+		Name:              resources.Name,
+		Namespace:         resources.Namespace,
+		UID:               resources.UID,
+		ResourceVersion:   resources.ResourceVersion,
+		CreationTimestamp: resources.CreationTimestamp,
+	}
+	diff.Base = opts.Base
+	return diff, nil
+}
+
+func (d *packageRevisionResourcesSchemaDiff) getResources(ctx context.Context, name string) (*api.PackageRevisionResources, error) {
+	pkg, err := d.common.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return pkg.GetResources(ctx)
+}
+
+// computeSchemaDiff compares the KRM resources of two package revisions by
+// kind. Resources are matched by kind, namespace and name; the fields which
+// differ between matching resources are reported for their kind.
+func computeSchemaDiff(base, updated map[string]string) (*api.PackageRevisionSchemaDiff, error) {
+	baseObjects, err := parseObjects(base)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse base package resources: %w", err)
+	}
+	updatedObjects, err := parseObjects(updated)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse package resources: %w", err)
+	}
+
+	baseKinds := map[string]bool{}
+	for key := range baseObjects {
+		baseKinds[key.kind] = true
+	}
+	updatedKinds := map[string]bool{}
+	for key := range updatedObjects {
+		updatedKinds[key.kind] = true
+	}
+
+	diff := &api.PackageRevisionSchemaDiff{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevisionSchemaDiff",
+			APIVersion: api.SchemeGroupVersion.Identifier(),
+		},
+	}
+	for kind := range updatedKinds {
+		if !baseKinds[kind] {
+			diff.AddedKinds = append(diff.AddedKinds, kind)
+		}
+	}
+	for kind := range baseKinds {
+		if !updatedKinds[kind] {
+			diff.RemovedKinds = append(diff.RemovedKinds, kind)
+		}
+	}
+	sort.Strings(diff.AddedKinds)
+	sort.Strings(diff.RemovedKinds)
+
+	changed := map[string]map[string]bool{}
+	record := func(kind string, paths []string) {
+		if len(paths) == 0 {
+			return
+		}
+		if changed[kind] == nil {
+			changed[kind] = map[string]bool{}
+		}
+		for _, p := range paths {
+			changed[kind][p] = true
+		}
+	}
+	for key, obj := range updatedObjects {
+		if !baseKinds[key.kind] {
+			continue
+		}
+		// Resources added to, or removed from, a kind present in both
+		// revisions are compared against an empty object.
+		record(key.kind, changedFields("", baseObjects[key], obj))
+	}
+	for key, obj := range baseObjects {
+		if _, found := updatedObjects[key]; !found && updatedKinds[key.kind] {
+			record(key.kind, changedFields("", obj, nil))
+		}
+	}
+
+	if len(changed) > 0 {
+		diff.ModifiedKinds = map[string][]string{}
+		for kind, paths := range changed {
+			list := make([]string, 0, len(paths))
+			for p := range paths {
+				list = append(list, p)
+			}
+			sort.Strings(list)
+			diff.ModifiedKinds[kind] = list
+		}
+	}
+	return diff, nil
+}
+
+type objectKey struct {
+	kind            string // apiVersion/kind
+	namespace, name string
+}
+
+// parseObjects parses the KRM resources in the package YAML files.
+func parseObjects(resources map[string]string) (map[objectKey]map[string]interface{}, error) {
+	objects := map[objectKey]map[string]interface{}{}
+	for path, contents := range resources {
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" && filepath.Base(path) != "Kptfile" {
+			continue
+		}
+		nodes, err := (&kio.ByteReader{
+			Reader:                strings.NewReader(contents),
+			OmitReaderAnnotations: true,
+		}).Read()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, n := range nodes {
+			obj, err := n.Map()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			key := objectKey{
+				kind:      n.GetApiVersion() + "/" + n.GetKind(),
+				namespace: n.GetNamespace(),
+				name:      n.GetName(),
+			}
+			objects[key] = obj
+		}
+	}
+	return objects, nil
+}
+
+// changedFields returns the paths of the fields which differ between the two
+// values. Paths of nested fields are dot-separated; list items are indexed.
+func changedFields(path string, base, updated interface{}) []string {
+	baseMap, baseIsMap := base.(map[string]interface{})
+	updatedMap, updatedIsMap := updated.(map[string]interface{})
+	if path == "" {
+		// Compare missing objects as empty objects so that their top-level
+		// fields are reported.
+		baseIsMap, updatedIsMap = baseIsMap || base == nil, updatedIsMap || updated == nil
+	}
+	if baseIsMap && updatedIsMap {
+		var changed []string
+		for k, v := range baseMap {
+			changed = append(changed, changedFields(joinFieldPath(path, k), v, updatedMap[k])...)
+		}
+		for k := range updatedMap {
+			if _, found := baseMap[k]; !found {
+				changed = append(changed, joinFieldPath(path, k))
+			}
+		}
+		return changed
+	}
+
+	baseList, baseIsList := base.([]interface{})
+	updatedList, updatedIsList := updated.([]interface{})
+	if baseIsList && updatedIsList {
+		var changed []string
+		for i := 0; i < len(baseList) || i < len(updatedList); i++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i >= len(baseList) || i >= len(updatedList) {
+				changed = append(changed, itemPath)
+				continue
+			}
+			changed = append(changed, changedFields(itemPath, baseList[i], updatedList[i])...)
+		}
+		return changed
+	}
+
+	if reflect.DeepEqual(base, updated) {
+		return nil
+	}
+	return []string{path}
+}
+
+func joinFieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
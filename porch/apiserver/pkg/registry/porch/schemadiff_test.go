@@ -0,0 +1,125 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestComputeSchemaDiff(t *testing.T) {
+	base := map[string]string{
+		"Kptfile": `
+apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: app
+`,
+		"deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+`,
+		"config.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+`,
+		"README.md": "not: [a resource",
+	}
+	updated := map[string]string{
+		"Kptfile": base["Kptfile"],
+		"deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+      - name: sidecar
+        image: sidecar:v1
+`,
+		"service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`,
+		"README.md": base["README.md"],
+	}
+
+	diff, err := computeSchemaDiff(base, updated)
+	if err != nil {
+		t.Fatalf("computeSchemaDiff failed: %v", err)
+	}
+
+	if got, want := diff.AddedKinds, []string{"v1/Service"}; !cmp.Equal(want, got) {
+		t.Errorf("addedKinds: %s", cmp.Diff(want, got))
+	}
+	if got, want := diff.RemovedKinds, []string{"v1/ConfigMap"}; !cmp.Equal(want, got) {
+		t.Errorf("removedKinds: %s", cmp.Diff(want, got))
+	}
+	wantModified := map[string][]string{
+		"apps/v1/Deployment": {
+			"metadata.labels",
+			"spec.replicas",
+			"spec.template.spec.containers[0].image",
+			"spec.template.spec.containers[1]",
+		},
+	}
+	if diff := cmp.Diff(wantModified, diff.ModifiedKinds); diff != "" {
+		t.Errorf("modifiedKinds (-want, +got): %s", diff)
+	}
+}
+
+func TestComputeSchemaDiffAddedResource(t *testing.T) {
+	base := map[string]string{
+		"a.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+	}
+	updated := map[string]string{
+		"a.yaml": base["a.yaml"],
+		"b.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\ndata:\n  key: value\n",
+	}
+
+	diff, err := computeSchemaDiff(base, updated)
+	if err != nil {
+		t.Fatalf("computeSchemaDiff failed: %v", err)
+	}
+	want := map[string][]string{
+		"v1/ConfigMap": {"apiVersion", "data", "kind", "metadata"},
+	}
+	if got := diff.ModifiedKinds; !cmp.Equal(want, got) {
+		t.Errorf("modifiedKinds (-want, +got): %s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+// maxSearchResults is the maximum number of matches returned by a search.
+const maxSearchResults = 100
+
+// packageResourceSearches serves the create-only PackageResourceSearch
+// resource, which searches the resources of the package revisions in a
+// namespace.
+type packageResourceSearches struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageResourceSearches{}
+var _ rest.Scoper = &packageResourceSearches{}
+var _ rest.Creater = &packageResourceSearches{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (s *packageResourceSearches) New() runtime.Object {
+	return &api.PackageResourceSearch{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (s *packageResourceSearches) NamespaceScoped() bool {
+	return true
+}
+
+// Create evaluates the query of the posted PackageResourceSearch against the
+// resources of the package revisions in the searched repositories, and
+// returns the matching resources in the status.
+func (s *packageResourceSearches) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
+	if !namespaced {
+		return nil, apierrors.NewBadRequest("namespace must be specified")
+	}
+
+	search, ok := obj.(*api.PackageResourceSearch)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected PackageResourceSearch object, got %T", obj))
+	}
+
+	if createValidation != nil {
+		if err := createValidation(ctx, search); err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := parseSearchQuery(search.Spec.Query)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	offset, err := decodePageToken(search.Spec.PageToken)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+
+	var repositories configapi.RepositoryList
+	if err := s.common.coreClient.List(ctx, &repositories, client.InNamespace(ns)); err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("error listing repository objects: %w", err))
+	}
+	searched := map[string]bool{}
+	for _, name := range search.Spec.Repositories {
+		searched[name] = true
+	}
+	sort.Slice(repositories.Items, func(i, j int) bool {
+		return repositories.Items[i].Name < repositories.Items[j].Name
+	})
+
+	result := search.DeepCopy()
+	result.Namespace = ns
+	result.Status = api.PackageResourceSearchResult{}
+
+	// Matches are visited in a stable order so that page tokens, which are
+	// offsets into the sequence of matches, select the same matches again.
+	skipped := 0
+	done := false
+	for i := range repositories.Items {
+		repositoryObj := &repositories.Items[i]
+		if len(searched) > 0 && !searched[repositoryObj.Name] {
+			continue
+		}
+
+		repo, err := s.common.cad.OpenRepository(ctx, repositoryObj)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		revisions, err := repo.ListPackageRevisions(ctx)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		sort.Slice(revisions, func(i, j int) bool {
+			return revisions[i].Name() < revisions[j].Name()
+		})
+
+		for _, rev := range revisions {
+			resources, err := rev.GetResources(ctx)
+			if err != nil {
+				return nil, apierrors.NewInternalError(err)
+			}
+			err = searchPackageResources(query, resources.Spec.Resources, func(filename, resource string) bool {
+				if skipped < offset {
+					skipped++
+					return true
+				}
+				if len(result.Status.Matches) == maxSearchResults {
+					result.Status.NextPageToken = encodePageToken(offset + maxSearchResults)
+					done = true
+					return false
+				}
+				result.Status.Matches = append(result.Status.Matches, api.PackageResourceMatch{
+					PackageRevisionName: rev.Name(),
+					Filename:            filename,
+					MatchedResource:     resource,
+				})
+				return true
+			})
+			if err != nil {
+				return nil, apierrors.NewInternalError(fmt.Errorf("error searching %s: %w", rev.Name(), err))
+			}
+			if done {
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
+
+// parseSearchQuery parses a JSONPath query. The enclosing braces are
+// optional, as in kubectl.
+func parseSearchQuery(query string) (*jsonpath.JSONPath, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("spec.query must be specified")
+	}
+	if !strings.HasPrefix(query, "{") {
+		query = "{" + query + "}"
+	}
+	jp := jsonpath.New("query").AllowMissingKeys(true)
+	if err := jp.Parse(query); err != nil {
+		return nil, fmt.Errorf("invalid query %q: %w", query, err)
+	}
+	return jp, nil
+}
+
+// searchPackageResources calls match with the file name and `<kind>/<name>`
+// of each resource matching the query, in order, until match returns false.
+func searchPackageResources(query *jsonpath.JSONPath, resources map[string]string, match func(filename, resource string) bool) error {
+	filenames := make([]string, 0, len(resources))
+	for filename := range resources {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		if !isYAMLResourceFile(filename) {
+			continue
+		}
+		nodes, err := (&kio.ByteReader{
+			Reader:                strings.NewReader(resources[filename]),
+			OmitReaderAnnotations: true,
+		}).Read()
+		if err != nil {
+			return fmt.Errorf("cannot parse resources in %q: %w", filename, err)
+		}
+		for _, n := range nodes {
+			data, err := n.Map()
+			if err != nil {
+				return fmt.Errorf("cannot decode resource in %q: %w", filename, err)
+			}
+			results, err := query.FindResults(data)
+			if err != nil {
+				// Queries may not apply to every resource, for example
+				// when indexing a field which is not a list.
+				continue
+			}
+			if !hasNonEmptyResult(results) {
+				continue
+			}
+			if !match(filename, n.GetKind()+"/"+n.GetName()) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// hasNonEmptyResult returns true if any of the JSONPath results is a value
+// other than nil, false, or an empty string, list or map.
+func hasNonEmptyResult(results [][]reflect.Value) bool {
+	for _, values := range results {
+		for _, v := range values {
+			for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+				if v.IsNil() {
+					break
+				}
+				v = v.Elem()
+			}
+			if !v.IsValid() {
+				continue
+			}
+			switch v.Kind() {
+			case reflect.Interface, reflect.Ptr:
+				continue
+			case reflect.Bool:
+				if v.Bool() {
+					return true
+				}
+			case reflect.String, reflect.Slice, reflect.Map:
+				if v.Len() > 0 {
+					return true
+				}
+			default:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+	offset, err := strconv.Atoi(string(decoded))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+	return offset, nil
+}
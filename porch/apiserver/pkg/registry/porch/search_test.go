@@ -0,0 +1,145 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const searchTestDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.21
+`
+
+const searchTestResources = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  labels:
+    app: web
+data:
+  enabled: "false"
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`
+
+func TestSearchPackageResources(t *testing.T) {
+	resources := map[string]string{
+		"Kptfile":         "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: web\n",
+		"deployment.yaml": searchTestDeployment,
+		"resources.yaml":  searchTestResources,
+		"README.md":       "app: web\n",
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{
+			query: `{.metadata.labels.app}`,
+			want:  []string{"deployment.yaml Deployment/web", "resources.yaml ConfigMap/settings"},
+		},
+		{
+			query: `.spec.template.spec.containers[?(@.image=="nginx:1.21")]`,
+			want:  []string{"deployment.yaml Deployment/web"},
+		},
+		{
+			query: `.spec.template.spec.containers[?(@.image=="nginx:latest")]`,
+		},
+		{
+			query: `{.kind}`,
+			want:  []string{"Kptfile Kptfile/web", "deployment.yaml Deployment/web", "resources.yaml ConfigMap/settings", "resources.yaml Service/web"},
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			query, err := parseSearchQuery(tc.query)
+			if err != nil {
+				t.Fatalf("parseSearchQuery failed: %v", err)
+			}
+			var got []string
+			if err := searchPackageResources(query, resources, func(filename, resource string) bool {
+				got = append(got, filename+" "+resource)
+				return true
+			}); err != nil {
+				t.Fatalf("searchPackageResources failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected matches (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestSearchPackageResourcesStops(t *testing.T) {
+	query, err := parseSearchQuery(`.metadata.name`)
+	if err != nil {
+		t.Fatalf("parseSearchQuery failed: %v", err)
+	}
+	calls := 0
+	if err := searchPackageResources(query, map[string]string{"resources.yaml": searchTestResources}, func(filename, resource string) bool {
+		calls++
+		return false
+	}); err != nil {
+		t.Fatalf("searchPackageResources failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("match called %d times after returning false, want 1", calls)
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	for _, query := range []string{"", "  ", "{.metadata.labels[}"} {
+		if _, err := parseSearchQuery(query); err == nil {
+			t.Errorf("parseSearchQuery(%q) succeeded; expected error", query)
+		}
+	}
+}
+
+func TestPageToken(t *testing.T) {
+	for _, offset := range []int{0, 100, 1200} {
+		got, err := decodePageToken(encodePageToken(offset))
+		if err != nil {
+			t.Fatalf("decodePageToken failed: %v", err)
+		}
+		if got != offset {
+			t.Errorf("page token round trip: got %d, want %d", got, offset)
+		}
+	}
+	if offset, err := decodePageToken(""); err != nil || offset != 0 {
+		t.Errorf("decodePageToken(\"\") = %d, %v; want 0, nil", offset, err)
+	}
+	for _, token := range []string{"not base64!", encodePageToken(-1), "YWJj"} {
+		if _, err := decodePageToken(token); err == nil {
+			t.Errorf("decodePageToken(%q) succeeded; expected error", token)
+		}
+	}
+}
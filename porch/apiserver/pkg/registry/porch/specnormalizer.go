@@ -0,0 +1,82 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// SpecNormalizer canonicalizes the user-editable parts of a PackageRevision
+// so that semantically equivalent updates, such as those produced by a
+// client round-tripping the object through encode/decode, compare as equal.
+//
+// Only values a round trip can change are normalized: empty collections and
+// defaulted fields. Free text, such as the changelog entry, is compared as
+// is, so that whitespace-only edits are still recorded.
+//
+// The Kptfile pipeline is not part of the PackageRevision spec; it is
+// normalized as part of the package resources, not here.
+type SpecNormalizer struct{}
+
+// Normalize returns a normalized copy of the package revision. The original
+// object is not modified.
+func (n SpecNormalizer) Normalize(pr *api.PackageRevision) *api.PackageRevision {
+	out := pr.DeepCopy()
+
+	if len(out.Labels) == 0 {
+		out.Labels = nil
+	}
+	if len(out.Annotations) == 0 {
+		out.Annotations = nil
+	}
+
+	spec := &out.Spec
+	if spec.Lifecycle == "" {
+		spec.Lifecycle = api.PackageRevisionLifecycleDraft
+	}
+	if spec.RenderPolicy == "" {
+		spec.RenderPolicy = api.RenderPolicyOnPropose
+	}
+	if len(spec.Tasks) == 0 {
+		spec.Tasks = nil
+	}
+	for i := range spec.Tasks {
+		normalizeTask(&spec.Tasks[i])
+	}
+	return out
+}
+
+// Equivalent reports whether the two package revisions have the same
+// normalized spec, labels and annotations.
+func (n SpecNormalizer) Equivalent(newObj, oldObj *api.PackageRevision) bool {
+	newNormalized, oldNormalized := n.Normalize(newObj), n.Normalize(oldObj)
+	return equality.Semantic.DeepEqual(newNormalized.Spec, oldNormalized.Spec) &&
+		equality.Semantic.DeepEqual(newNormalized.Labels, oldNormalized.Labels) &&
+		equality.Semantic.DeepEqual(newNormalized.Annotations, oldNormalized.Annotations)
+}
+
+func normalizeTask(task *api.Task) {
+	if init := task.Init; init != nil {
+		if len(init.Keywords) == 0 {
+			init.Keywords = nil
+		}
+	}
+	if eval := task.Eval; eval != nil {
+		if len(eval.ConfigMap) == 0 {
+			eval.ConfigMap = nil
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSpecNormalizerRoundTrip(t *testing.T) {
+	old := &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "blueprints:basens:v1",
+			Generation: 2,
+			Labels:     map[string]string{"team": "platform", "env": "dev"},
+		},
+		Spec: api.PackageRevisionSpec{
+			PackageName:    "basens",
+			Revision:       "v1",
+			RepositoryName: "blueprints",
+			Lifecycle:      api.PackageRevisionLifecycleDraft,
+			Tasks: []api.Task{
+				{
+					Type: api.TaskTypeInit,
+					Init: &api.PackageInitTaskSpec{Description: "sample package"},
+				},
+				{
+					Type: api.TaskTypeEval,
+					Eval: &api.FunctionEvalTaskSpec{
+						Image:     "gcr.io/kpt-fn/set-namespace:v0.2.0",
+						ConfigMap: map[string]string{"namespace": "bar"},
+					},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(old)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	roundTripped := &api.PackageRevision{}
+	if err := json.Unmarshal(data, roundTripped); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if !(SpecNormalizer{}).Equivalent(roundTripped, old) {
+		t.Errorf("round-tripped package revision is not equivalent to the original; update would create a new commit")
+	}
+
+	packageRevisionStrategy{}.PrepareForUpdate(context.Background(), roundTripped, old)
+	if got, want := roundTripped.Generation, old.Generation; got != want {
+		t.Errorf("round-trip update: generation = %d; want %d", got, want)
+	}
+}
+
+func TestSpecNormalizerEquivalent(t *testing.T) {
+	base := &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"team": "platform"},
+		},
+		Spec: api.PackageRevisionSpec{
+			Lifecycle:      api.PackageRevisionLifecycleDraft,
+			ChangelogEntry: "Add the namespace.\nFix labels.",
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(pr *api.PackageRevision)
+		want   bool
+	}{
+		{
+			name:   "unchanged",
+			mutate: func(pr *api.PackageRevision) {},
+			want:   true,
+		},
+		{
+			name: "changelog whitespace",
+			mutate: func(pr *api.PackageRevision) {
+				pr.Spec.ChangelogEntry = "Add the namespace.\n\nFix labels."
+			},
+			want: false,
+		},
+		{
+			name: "labels removed",
+			mutate: func(pr *api.PackageRevision) {
+				pr.Labels = map[string]string{}
+			},
+			want: false,
+		},
+		{
+			name: "default lifecycle",
+			mutate: func(pr *api.PackageRevision) {
+				pr.Spec.Lifecycle = ""
+			},
+			want: true,
+		},
+		{
+			name: "empty tasks",
+			mutate: func(pr *api.PackageRevision) {
+				pr.Spec.Tasks = []api.Task{}
+			},
+			want: true,
+		},
+		{
+			name: "lifecycle change",
+			mutate: func(pr *api.PackageRevision) {
+				pr.Spec.Lifecycle = api.PackageRevisionLifecycleProposed
+			},
+			want: false,
+		},
+		{
+			name: "changelog change",
+			mutate: func(pr *api.PackageRevision) {
+				pr.Spec.ChangelogEntry = "Add the namespace."
+			},
+			want: false,
+		},
+		{
+			name: "label change",
+			mutate: func(pr *api.PackageRevision) {
+				pr.Labels["team"] = "apps"
+			},
+			want: false,
+		},
+		{
+			name: "empty annotations",
+			mutate: func(pr *api.PackageRevision) {
+				pr.Annotations = map[string]string{}
+			},
+			want: true,
+		},
+		{
+			name: "annotation change",
+			mutate: func(pr *api.PackageRevision) {
+				pr.Annotations = map[string]string{api.ForceDeleteAnnotation: "true"}
+			},
+			want: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			updated := base.DeepCopy()
+			tc.mutate(updated)
+			if got := (SpecNormalizer{}).Equivalent(updated, base); got != tc.want {
+				t.Errorf("Equivalent() = %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -15,61 +15,271 @@
 package porch
 
 import (
+	"net/url"
+	"time"
+
 	"github.com/GoogleContainerTools/kpt/porch/api/porch"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/engine"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func NewRESTStorage(scheme *runtime.Scheme, codecs serializer.CodecFactory, cad engine.CaDEngine, coreClient client.WithWatch) (genericapiserver.APIGroupInfo, error) {
+// RESTStorageOptions holds the dependencies of the porch REST storage other
+// than the engine and the core client.
+type RESTStorageOptions struct {
+	RenderStaleness    *RenderStalenessTracker
+	CreateRateLimiter  *CreateRateLimiter
+	TransitionWebhooks *TransitionWebhookNotifier
+	Notifications      *NotificationDispatcher
+	UpstreamWatcher    *UpstreamWatcher
+	// FunctionConfigValidator validates the function configs of Kptfiles
+	// updated through package revision resources.
+	FunctionConfigValidator *FunctionConfigValidator
+	ConnectionTester        RepositoryConnectionTester
+	// SecretChecker checks the secrets required by a package revision before
+	// it is approved.
+	SecretChecker *RequiredSecretChecker
+	PipelineRuns  *PipelineRunTracker
+	// UploadMaxPartSizeBytes limits the size of each part of a package
+	// revision resources upload.
+	UploadMaxPartSizeBytes int64
+	Clock                  clock.Clock
+}
+
+func NewRESTStorage(scheme *runtime.Scheme, codecs serializer.CodecFactory, cad engine.CaDEngine, coreClient client.WithWatch, opts RESTStorageOptions) (genericapiserver.APIGroupInfo, error) {
+	conflicts := newConflictTracker()
+
 	packageRevisions := &packageRevisions{
 		TableConvertor: rest.NewDefaultTableConvertor(porch.Resource("packagerevisions")),
 		packageCommon: packageCommon{
-			cad:            cad,
-			gr:             porch.Resource("packagerevisions"),
-			coreClient:     coreClient,
-			updateStrategy: packageRevisionStrategy{},
+			cad:                cad,
+			gr:                 porch.Resource("packagerevisions"),
+			coreClient:         coreClient,
+			renderStaleness:    opts.RenderStaleness,
+			transitionWebhooks: opts.TransitionWebhooks,
+			notifications:      opts.Notifications,
+			upstreamWatcher:    opts.UpstreamWatcher,
+			pipelineRuns:       opts.PipelineRuns,
+			conflicts:          conflicts,
 		},
+		createStrategy:    packageRevisionCreateStrategy{},
+		deleteStrategy:    packageRevisionDeleteStrategy{},
+		createRateLimiter: opts.CreateRateLimiter,
 	}
+	packageRevisions.updateStrategy = ComposeUpdateStrategies(packageRevisionStrategy{},
+		kptfileValidationStrategy{getPackage: packageRevisions.getPackage})
 
 	packageRevisionsApproval := &packageRevisionsApproval{
 		common: packageCommon{
-			cad:            cad,
-			coreClient:     coreClient,
-			gr:             porch.Resource("packagerevisions"),
-			updateStrategy: packageRevisionApprovalStrategy{},
+			cad:                cad,
+			coreClient:         coreClient,
+			gr:                 porch.Resource("packagerevisions"),
+			renderStaleness:    opts.RenderStaleness,
+			transitionWebhooks: opts.TransitionWebhooks,
+			notifications:      opts.Notifications,
+			upstreamWatcher:    opts.UpstreamWatcher,
+			pipelineRuns:       opts.PipelineRuns,
+			conflicts:          conflicts,
 		},
 	}
+	packageRevisionsApproval.common.updateStrategy = ComposeUpdateStrategies(packageRevisionApprovalStrategy{secretChecker: opts.SecretChecker},
+		kptfileValidationStrategy{getPackage: packageRevisionsApproval.common.getPackage})
 
-	packageRevisionResources := &packageRevisionResources{
-		TableConvertor: rest.NewDefaultTableConvertor(porch.Resource("packagerevisionresources")),
-		packageCommon: packageCommon{
+	packageRevisionsRejection := &packageRevisionsRejection{
+		common: packageCommon{
+			cad:                cad,
+			coreClient:         coreClient,
+			gr:                 porch.Resource("packagerevisions"),
+			renderStaleness:    opts.RenderStaleness,
+			transitionWebhooks: opts.TransitionWebhooks,
+			notifications:      opts.Notifications,
+			upstreamWatcher:    opts.UpstreamWatcher,
+			pipelineRuns:       opts.PipelineRuns,
+			conflicts:          conflicts,
+		},
+	}
+	packageRevisionsRejection.common.updateStrategy = packageRevisionRejectionStrategy{}
+
+	packageRevisionsLineage := &packageRevisionsLineage{
+		common: packageCommon{
+			cad:        cad,
+			coreClient: coreClient,
+			gr:         porch.Resource("packagerevisions"),
+		},
+	}
+
+	packageRevisionsDiff := &packageRevisionsDiff{
+		common: packageCommon{
+			cad:        cad,
+			coreClient: coreClient,
+			gr:         porch.Resource("packagerevisions"),
+		},
+	}
+
+	packageRevisionsValidate := &packageRevisionsValidate{
+		common: packageCommon{
+			cad:        cad,
+			coreClient: coreClient,
+			gr:         porch.Resource("packagerevisions"),
+		},
+	}
+
+	packageRevisionsCostEstimate := &packageRevisionsCostEstimate{
+		common: packageCommon{
+			cad:        cad,
+			coreClient: coreClient,
+			gr:         porch.Resource("packagerevisions"),
+		},
+	}
+
+	packageRevisionsLint := &packageRevisionsLint{
+		common: packageCommon{
+			cad:        cad,
+			coreClient: coreClient,
+			gr:         porch.Resource("packagerevisions"),
+		},
+	}
+
+	packageRevisionsTransfer := &packageRevisionsTransfer{
+		common: packageCommon{
+			cad:        cad,
+			coreClient: coreClient,
+			gr:         porch.Resource("packagerevisions"),
+		},
+	}
+
+	packageRevisionResourcesSchemaDiff := &packageRevisionResourcesSchemaDiff{
+		common: packageCommon{
 			cad:        cad,
+			coreClient: coreClient,
 			gr:         porch.Resource("packagerevisionresources"),
+		},
+	}
+
+	packageRevisionResourcesExpand := &packageRevisionResourcesExpand{
+		common: packageCommon{
+			cad:        cad,
 			coreClient: coreClient,
+			gr:         porch.Resource("packagerevisionresources"),
+		},
+	}
+
+	packageRevisionResourcesBatchUpdate := &packageRevisionResourcesBatchUpdate{
+		common: packageCommon{
+			cad:        cad,
+			coreClient: coreClient,
+			gr:         porch.Resource("packagerevisionresources"),
+			conflicts:  conflicts,
+		},
+	}
+
+	packageResourceSearches := &packageResourceSearches{
+		common: packageCommon{
+			cad:        cad,
+			coreClient: coreClient,
+			gr:         porch.Resource("packageresourcesearches"),
+		},
+	}
+
+	packageRevisionResources := &packageRevisionResources{
+		TableConvertor: rest.NewDefaultTableConvertor(porch.Resource("packagerevisionresources")),
+		packageCommon: packageCommon{
+			cad:                     cad,
+			gr:                      porch.Resource("packagerevisionresources"),
+			coreClient:              coreClient,
+			notifications:           opts.Notifications,
+			conflicts:               conflicts,
+			functionConfigValidator: opts.FunctionConfigValidator,
 		},
 	}
 
+	packageRevisionResourcesResolveConflict := &packageRevisionResourcesResolveConflict{
+		resources: packageRevisionResources,
+	}
+
+	packageRevisionResourcesUpload := &packageRevisionResourcesUpload{
+		resources:   packageRevisionResources,
+		maxPartSize: opts.UploadMaxPartSizeBytes,
+	}
+
 	functions := &functions{
 		TableConvertor: rest.NewDefaultTableConvertor(porch.Resource("functions")),
 		cad:            cad,
 		coreClient:     coreClient,
+		catalog:        NewFunctionRegistryAggregator(cad, coreClient),
+	}
+
+	repositoryConnectionTests := &repositoryConnectionTests{
+		coreClient:     coreClient,
+		createStrategy: repositoryConnectionTestStrategy{},
+		tester:         opts.ConnectionTester,
+		now:            time.Now,
 	}
 
-	group := genericapiserver.NewDefaultAPIGroupInfo(porch.GroupName, scheme, metav1.ParameterCodec, codecs)
+	repositoryDependencyGraphs := newRepositoryDependencyGraphs(packageCommon{
+		cad:        cad,
+		coreClient: coreClient,
+		gr:         porch.Resource("repositorydependencygraphs"),
+	}, opts.Clock)
+
+	group := genericapiserver.NewDefaultAPIGroupInfo(porch.GroupName, scheme, &parameterCodec{porch: runtime.NewParameterCodec(scheme)}, codecs)
 
 	group.VersionedResourcesStorageMap = map[string]map[string]rest.Storage{
 		"v1alpha1": {
-			"packagerevisions":          packageRevisions,
-			"packagerevisions/approval": packageRevisionsApproval,
-			"packagerevisionresources":  packageRevisionResources,
-			"functions":                 functions,
+			"packagerevisions":                         packageRevisions,
+			"packagerevisions/approval":                packageRevisionsApproval,
+			"packagerevisions/cost-estimate":           packageRevisionsCostEstimate,
+			"packagerevisions/diff":                    packageRevisionsDiff,
+			"packagerevisions/lineage":                 packageRevisionsLineage,
+			"packagerevisions/lint":                    packageRevisionsLint,
+			"packagerevisions/rejection":               packageRevisionsRejection,
+			"packagerevisions/transfer":                packageRevisionsTransfer,
+			"packagerevisions/validate":                packageRevisionsValidate,
+			"packagerevisionresources":                 packageRevisionResources,
+			"packagerevisionresources/batchupdate":     packageRevisionResourcesBatchUpdate,
+			"packagerevisionresources/expand":          packageRevisionResourcesExpand,
+			"packagerevisionresources/resolveconflict": packageRevisionResourcesResolveConflict,
+			"packagerevisionresources/schemadiff":      packageRevisionResourcesSchemaDiff,
+			"packagerevisionresources/upload":          packageRevisionResourcesUpload,
+			"functions":                                functions,
+			"packageresourcesearches":                  packageResourceSearches,
+			"repositoryconnectiontests":                repositoryConnectionTests,
+			"repositorydependencygraphs":               repositoryDependencyGraphs,
 		},
 	}
 
 	return group, nil
 }
+
+// parameterCodec decodes query parameters into the options of porch
+// subresources using the porch scheme, and into all other options using
+// metav1.ParameterCodec.
+type parameterCodec struct {
+	porch runtime.ParameterCodec
+}
+
+var _ runtime.ParameterCodec = &parameterCodec{}
+
+func (c *parameterCodec) codecFor(obj runtime.Object) runtime.ParameterCodec {
+	switch obj.(type) {
+	case *api.PackageRevisionDiffOptions, *api.PackageRevisionSchemaDiffOptions, *api.RepositoryDependencyGraphOptions:
+		return c.porch
+	default:
+		return metav1.ParameterCodec
+	}
+}
+
+func (c *parameterCodec) DecodeParameters(parameters url.Values, from schema.GroupVersion, into runtime.Object) error {
+	return c.codecFor(into).DecodeParameters(parameters, from, into)
+}
+
+func (c *parameterCodec) EncodeParameters(obj runtime.Object, to schema.GroupVersion) (url.Values, error) {
+	return c.codecFor(obj).EncodeParameters(obj, to)
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// packageRevisionsTransfer moves package revisions between repositories.
+type packageRevisionsTransfer struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageRevisionsTransfer{}
+var _ rest.Scoper = &packageRevisionsTransfer{}
+var _ rest.NamedCreater = &packageRevisionsTransfer{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (t *packageRevisionsTransfer) New() runtime.Object {
+	return &api.TransferRequest{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (t *packageRevisionsTransfer) NamespaceScoped() bool {
+	return true
+}
+
+// Create transfers the named package revision to the target repository of
+// the posted TransferRequest and returns the package revision created there.
+// The source package revision is deleted in the same request; if that fails,
+// the package revision created in the target repository is removed again.
+func (t *packageRevisionsTransfer) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
+	if !namespaced {
+		return nil, apierrors.NewBadRequest("namespace must be specified")
+	}
+
+	request, ok := obj.(*api.TransferRequest)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected TransferRequest object, got %T", obj))
+	}
+	if request.TargetRepository == "" {
+		return nil, apierrors.NewBadRequest("targetRepository must be specified")
+	}
+
+	nameTokens, err := ParseName(name)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid name %q", name))
+	}
+	if nameTokens.RepositoryName == request.TargetRepository {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("package revision %q is already in repository %q", name, request.TargetRepository))
+	}
+
+	oldPackage, err := t.common.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceRepositoryObj, err := t.getRepository(ctx, ns, nameTokens.RepositoryName)
+	if err != nil {
+		return nil, err
+	}
+	targetRepositoryObj, err := t.getRepository(ctx, ns, request.TargetRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	transferred, err := t.common.cad.TransferPackageRevision(ctx, sourceRepositoryObj, targetRepositoryObj, oldPackage)
+	if err != nil {
+		return nil, engineError(err)
+	}
+
+	created, err := transferred.GetPackageRevision()
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return created, nil
+}
+
+func (t *packageRevisionsTransfer) getRepository(ctx context.Context, namespace, name string) (*configapi.Repository, error) {
+	var repositoryObj configapi.Repository
+	repositoryID := types.NamespacedName{Namespace: namespace, Name: name}
+	if err := t.common.coreClient.Get(ctx, repositoryID, &repositoryObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, apierrors.NewNotFound(configapi.KindRepository.GroupResource(), repositoryID.Name)
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("error getting repository %v: %w", repositoryID, err))
+	}
+	return &repositoryObj, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestTransferRejectsInvalidRequests(t *testing.T) {
+	transfer := &packageRevisionsTransfer{}
+	ctx := genericapirequest.WithNamespace(context.Background(), "default")
+
+	for _, tc := range []struct {
+		name    string
+		request *api.TransferRequest
+	}{
+		{
+			name:    "missing target",
+			request: &api.TransferRequest{},
+		},
+		{
+			name:    "same repository",
+			request: &api.TransferRequest{TargetRepository: "blueprints"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := transfer.Create(ctx, "blueprints:basens:v1", tc.request, nil, nil)
+			if !apierrors.IsBadRequest(err) {
+				t.Errorf("Create() error = %v, want BadRequest", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookMaxAttempts = 3
+	defaultWebhookBackoff     = time.Second
+
+	// webhookSignatureHeader carries the HMAC-SHA256 signature of the
+	// request body, as "sha256=<hex digest>".
+	webhookSignatureHeader = "X-Porch-Signature"
+	// webhookSecretKey is the key of the signing key in the webhook secret.
+	webhookSecretKey = "key"
+)
+
+// TransitionEvent is the body posted to transition webhooks.
+type TransitionEvent struct {
+	Webhook         string                       `json:"webhook"`
+	Namespace       string                       `json:"namespace"`
+	PackageRevision string                       `json:"packageRevision"`
+	Repository      string                       `json:"repository"`
+	Package         string                       `json:"package"`
+	Revision        string                       `json:"revision"`
+	From            api.PackageRevisionLifecycle `json:"from"`
+	To              api.PackageRevisionLifecycle `json:"to"`
+}
+
+// TransitionWebhookNotifier calls the TransitionWebhooks matching the
+// lifecycle transitions of package revisions.
+type TransitionWebhookNotifier struct {
+	coreClient         client.Reader
+	credentialResolver repository.CredentialResolver
+	httpClient         *http.Client
+	sleep              func(time.Duration)
+
+	// deliveries tracks the webhook calls in progress.
+	deliveries sync.WaitGroup
+}
+
+func NewTransitionWebhookNotifier(coreClient client.Reader, credentialResolver repository.CredentialResolver) *TransitionWebhookNotifier {
+	return &TransitionWebhookNotifier{
+		coreClient:         coreClient,
+		credentialResolver: credentialResolver,
+		httpClient:         &http.Client{},
+		sleep:              time.Sleep,
+	}
+}
+
+// NotifyTransition calls the webhooks registered in the namespace of the
+// package revision for its transition from one lifecycle value to another.
+// Webhooks are called in the background, and retried per their retry policy.
+func (n *TransitionWebhookNotifier) NotifyTransition(ctx context.Context, from, to api.PackageRevisionLifecycle, pr *api.PackageRevision) {
+	if n == nil || from == to {
+		return
+	}
+
+	var webhooks configapi.TransitionWebhookList
+	if err := n.coreClient.List(ctx, &webhooks, client.InNamespace(pr.Namespace)); err != nil {
+		klog.Warningf("Cannot list transition webhooks in namespace %q: %v", pr.Namespace, err)
+		return
+	}
+
+	for i := range webhooks.Items {
+		webhook := &webhooks.Items[i]
+		webhookFrom, webhookTo, err := parseTransitionType(webhook.Spec.TransitionType)
+		if err != nil {
+			klog.Warningf("Ignoring transition webhook %s/%s: %v", webhook.Namespace, webhook.Name, err)
+			continue
+		}
+		if webhookFrom != from || webhookTo != to {
+			continue
+		}
+
+		event := TransitionEvent{
+			Webhook:         webhook.Name,
+			Namespace:       pr.Namespace,
+			PackageRevision: pr.Name,
+			Repository:      pr.Spec.RepositoryName,
+			Package:         pr.Spec.PackageName,
+			Revision:        pr.Spec.Revision,
+			From:            from,
+			To:              to,
+		}
+		n.deliveries.Add(1)
+		go func() {
+			defer n.deliveries.Done()
+			// The request context ends with the API call; deliveries outlive it.
+			n.deliver(context.Background(), webhook, event)
+		}()
+	}
+}
+
+// parseTransitionType parses transition types of the form "From→To" or "From->To".
+func parseTransitionType(transitionType string) (from, to api.PackageRevisionLifecycle, err error) {
+	var parts []string
+	for _, separator := range []string{"→", "->"} {
+		if parts = strings.Split(transitionType, separator); len(parts) == 2 {
+			break
+		}
+	}
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid transition type %q; expected From→To", transitionType)
+	}
+
+	from, to = api.PackageRevisionLifecycle(strings.TrimSpace(parts[0])), api.PackageRevisionLifecycle(strings.TrimSpace(parts[1]))
+	for _, lifecycle := range []api.PackageRevisionLifecycle{from, to} {
+		switch lifecycle {
+		case api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished, api.PackageRevisionLifecycleRejected:
+		default:
+			return "", "", fmt.Errorf("invalid lifecycle value %q in transition type %q", lifecycle, transitionType)
+		}
+	}
+	return from, to, nil
+}
+
+func (n *TransitionWebhookNotifier) deliver(ctx context.Context, webhook *configapi.TransitionWebhook, event TransitionEvent) {
+	id := webhook.Namespace + "/" + webhook.Name
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		klog.Errorf("Cannot encode transition event for webhook %s: %v", id, err)
+		return
+	}
+
+	var key []byte
+	if ref := webhook.Spec.SecretRef; ref != nil && ref.Name != "" {
+		credential, err := n.credentialResolver.ResolveCredential(ctx, webhook.Namespace, ref.Name)
+		if err != nil {
+			klog.Warningf("Cannot call transition webhook %s: %v", id, err)
+			return
+		}
+		if key = credential.Data[webhookSecretKey]; len(key) == 0 {
+			klog.Warningf("Cannot call transition webhook %s: secret %q has no %q key", id, ref.Name, webhookSecretKey)
+			return
+		}
+	}
+
+	timeout := defaultWebhookTimeout
+	if webhook.Spec.Timeout != nil && webhook.Spec.Timeout.Duration > 0 {
+		timeout = webhook.Spec.Timeout.Duration
+	}
+	maxAttempts, backoff := defaultWebhookMaxAttempts, defaultWebhookBackoff
+	if policy := webhook.Spec.RetryPolicy; policy != nil {
+		if policy.MaxAttempts > 0 {
+			maxAttempts = policy.MaxAttempts
+		}
+		if policy.Backoff != nil && policy.Backoff.Duration > 0 {
+			backoff = policy.Backoff.Duration
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := n.call(ctx, webhook.Spec.Endpoint, body, key, timeout)
+		if err == nil {
+			klog.Infof("Called transition webhook %s for %s (%s→%s)", id, event.PackageRevision, event.From, event.To)
+			return
+		}
+		if attempt >= maxAttempts {
+			klog.Warningf("Transition webhook %s failed for %s after %d attempts: %v", id, event.PackageRevision, attempt, err)
+			return
+		}
+		klog.Warningf("Transition webhook %s failed for %s, retrying in %v: %v", id, event.PackageRevision, backoff, err)
+		n.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (n *TransitionWebhookNotifier) call(ctx context.Context, endpoint string, body, key []byte, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key != nil {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(body)
+		req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
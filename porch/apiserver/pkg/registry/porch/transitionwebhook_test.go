@@ -0,0 +1,243 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type staticCredentialResolver map[string]repository.Credential
+
+func (r staticCredentialResolver) ResolveCredential(ctx context.Context, namespace, name string) (repository.Credential, error) {
+	return r[namespace+"/"+name], nil
+}
+
+// webhookReceiver is a mock webhook endpoint which fails the first failures
+// calls and records the events of the rest.
+type webhookReceiver struct {
+	t        *testing.T
+	key      []byte
+	failures int
+
+	mutex  sync.Mutex
+	calls  int
+	events []TransitionEvent
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.calls++
+	if r.calls <= r.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.t.Errorf("cannot read webhook request: %v", err)
+		return
+	}
+	if r.key != nil {
+		mac := hmac.New(sha256.New, r.key)
+		mac.Write(body)
+		if got, want := req.Header.Get(webhookSignatureHeader), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+			r.t.Errorf("unexpected signature: got %q, want %q", got, want)
+		}
+	}
+	var event TransitionEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		r.t.Errorf("cannot decode webhook request: %v", err)
+		return
+	}
+	r.events = append(r.events, event)
+}
+
+func newTestNotifier(t *testing.T, webhooks ...*configapi.TransitionWebhook) *TransitionWebhookNotifier {
+	scheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme failed: %v", err)
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, webhook := range webhooks {
+		builder = builder.WithObjects(webhook)
+	}
+	notifier := NewTransitionWebhookNotifier(builder.Build(), staticCredentialResolver{
+		"default/webhook-secret": {Data: map[string][]byte{webhookSecretKey: []byte("s3cr3t")}},
+	})
+	notifier.sleep = func(time.Duration) {}
+	return notifier
+}
+
+func testPackageRevision() *api.PackageRevision {
+	return &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "repo:package:v1",
+			Namespace: "default",
+		},
+		Spec: api.PackageRevisionSpec{
+			RepositoryName: "repo",
+			PackageName:    "package",
+			Revision:       "v1",
+		},
+	}
+}
+
+func TestTransitionWebhook(t *testing.T) {
+	published := &webhookReceiver{t: t, key: []byte("s3cr3t")}
+	publishedServer := httptest.NewServer(published)
+	defer publishedServer.Close()
+
+	proposed := &webhookReceiver{t: t}
+	proposedServer := httptest.NewServer(proposed)
+	defer proposedServer.Close()
+
+	notifier := newTestNotifier(t,
+		&configapi.TransitionWebhook{
+			ObjectMeta: metav1.ObjectMeta{Name: "published", Namespace: "default"},
+			Spec: configapi.TransitionWebhookSpec{
+				TransitionType: "Proposed→Published",
+				Endpoint:       publishedServer.URL,
+				SecretRef:      &configapi.SecretRef{Name: "webhook-secret"},
+			},
+		},
+		&configapi.TransitionWebhook{
+			ObjectMeta: metav1.ObjectMeta{Name: "proposed", Namespace: "default"},
+			Spec: configapi.TransitionWebhookSpec{
+				TransitionType: "Draft->Proposed",
+				Endpoint:       proposedServer.URL,
+			},
+		},
+	)
+
+	notifier.NotifyTransition(context.Background(), api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished, testPackageRevision())
+	notifier.deliveries.Wait()
+
+	want := []TransitionEvent{{
+		Webhook:         "published",
+		Namespace:       "default",
+		PackageRevision: "repo:package:v1",
+		Repository:      "repo",
+		Package:         "package",
+		Revision:        "v1",
+		From:            api.PackageRevisionLifecycleProposed,
+		To:              api.PackageRevisionLifecyclePublished,
+	}}
+	if diff := cmp.Diff(want, published.events); diff != "" {
+		t.Errorf("unexpected events (-want, +got): %s", diff)
+	}
+	if proposed.calls != 0 {
+		t.Errorf("webhook for a different transition was called %d times", proposed.calls)
+	}
+}
+
+func TestTransitionWebhookRetry(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		failures    int
+		wantCalls   int
+		wantEvents  int
+		wantBackoff []time.Duration
+	}{
+		{
+			name:        "succeeds after retries",
+			failures:    2,
+			wantCalls:   3,
+			wantEvents:  1,
+			wantBackoff: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:        "gives up",
+			failures:    5,
+			wantCalls:   3,
+			wantEvents:  0,
+			wantBackoff: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver := &webhookReceiver{t: t, failures: tc.failures}
+			server := httptest.NewServer(receiver)
+			defer server.Close()
+
+			notifier := newTestNotifier(t, &configapi.TransitionWebhook{
+				ObjectMeta: metav1.ObjectMeta{Name: "published", Namespace: "default"},
+				Spec: configapi.TransitionWebhookSpec{
+					TransitionType: "Proposed→Published",
+					Endpoint:       server.URL,
+					RetryPolicy: &configapi.WebhookRetryPolicy{
+						MaxAttempts: 3,
+						Backoff:     &metav1.Duration{Duration: 100 * time.Millisecond},
+					},
+				},
+			})
+			var backoff []time.Duration
+			notifier.sleep = func(d time.Duration) { backoff = append(backoff, d) }
+
+			notifier.NotifyTransition(context.Background(), api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished, testPackageRevision())
+			notifier.deliveries.Wait()
+
+			if receiver.calls != tc.wantCalls {
+				t.Errorf("calls: got %d, want %d", receiver.calls, tc.wantCalls)
+			}
+			if len(receiver.events) != tc.wantEvents {
+				t.Errorf("events: got %d, want %d", len(receiver.events), tc.wantEvents)
+			}
+			if diff := cmp.Diff(tc.wantBackoff, backoff); diff != "" {
+				t.Errorf("unexpected backoff (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestParseTransitionType(t *testing.T) {
+	for _, tc := range []struct {
+		transitionType string
+		from, to       api.PackageRevisionLifecycle
+		wantErr        bool
+	}{
+		{transitionType: "Proposed→Published", from: api.PackageRevisionLifecycleProposed, to: api.PackageRevisionLifecyclePublished},
+		{transitionType: "Draft -> Proposed", from: api.PackageRevisionLifecycleDraft, to: api.PackageRevisionLifecycleProposed},
+		{transitionType: "Proposed", wantErr: true},
+		{transitionType: "Proposed→Deployed", wantErr: true},
+	} {
+		from, to, err := parseTransitionType(tc.transitionType)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseTransitionType(%q): unexpected error %v", tc.transitionType, err)
+			continue
+		}
+		if from != tc.from || to != tc.to {
+			t.Errorf("parseTransitionType(%q): got %s→%s, want %s→%s", tc.transitionType, from, to, tc.from, tc.to)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// DefaultUploadMaxPartSizeBytes bounds the size of a single file uploaded to
+// the upload subresource of package revision resources.
+const DefaultUploadMaxPartSizeBytes = 10 << 20
+
+// packageRevisionResourcesUpload replaces the files of a package revision with
+// the parts of a multipart/form-data request. The form name of each part is
+// the path of a file in the package. The parts are read one at a time, so
+// clients can stream a package instead of building a PackageRevisionResources
+// object holding all of it.
+type packageRevisionResourcesUpload struct {
+	resources *packageRevisionResources
+
+	// maxPartSize bounds the size of a single part. Zero disables the limit.
+	maxPartSize int64
+}
+
+var _ rest.Storage = &packageRevisionResourcesUpload{}
+var _ rest.Scoper = &packageRevisionResourcesUpload{}
+var _ rest.Connecter = &packageRevisionResourcesUpload{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (u *packageRevisionResourcesUpload) New() runtime.Object {
+	return &api.PackageRevisionResources{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (u *packageRevisionResourcesUpload) NamespaceScoped() bool {
+	return true
+}
+
+// NewConnectOptions returns nil; the upload has no options.
+func (u *packageRevisionResourcesUpload) NewConnectOptions() (runtime.Object, bool, string) {
+	return nil, false, ""
+}
+
+// ConnectMethods returns the HTTP methods handled by Connect.
+func (u *packageRevisionResourcesUpload) ConnectMethods() []string {
+	return []string{http.MethodPost}
+}
+
+// Connect returns the handler of an upload to the named package revision,
+// which responds with the updated package revision resources.
+func (u *packageRevisionResourcesUpload) Connect(ctx context.Context, name string, options runtime.Object, responder rest.Responder) (http.Handler, error) {
+	ns, namespaced := genericapirequest.NamespaceFrom(ctx)
+	if !namespaced {
+		return nil, apierrors.NewBadRequest("namespace must be specified")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		updated, err := u.upload(ctx, ns, name, req)
+		if err != nil {
+			responder.Error(err)
+			return
+		}
+		responder.Object(http.StatusOK, updated)
+	}), nil
+}
+
+func (u *packageRevisionResourcesUpload) upload(ctx context.Context, ns, name string, req *http.Request) (*api.PackageRevisionResources, error) {
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a multipart/form-data body: %v", err))
+	}
+
+	oldPackage, err := u.resources.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	oldObj, err := oldPackage.GetResources(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	files, err := readUploadParts(reader, u.maxPartSize)
+	if err != nil {
+		return nil, err
+	}
+
+	newObj := oldObj.DeepCopy()
+	newObj.Spec.Resources = files
+	return u.resources.updateResources(ctx, ns, name, oldPackage, oldObj, newObj)
+}
+
+// readUploadParts reads the files of a package from the parts of a multipart
+// body, rejecting parts larger than maxPartSize unless it is zero.
+func readUploadParts(reader *multipart.Reader, maxPartSize int64) (map[string]string, error) {
+	files := map[string]string{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("failed to read multipart body: %v", err))
+		}
+
+		filename := path.Clean(part.FormName())
+		if part.FormName() == "" || filename == "." || filename == ".." || path.IsAbs(filename) || strings.HasPrefix(filename, "../") {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid filename %q", part.FormName()))
+		}
+		if _, exists := files[filename]; exists {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("file %q is uploaded more than once", filename))
+		}
+
+		contents, err := readUploadPart(part, maxPartSize)
+		part.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[filename] = contents
+	}
+
+	if len(files) == 0 {
+		return nil, apierrors.NewBadRequest("the upload contains no files")
+	}
+	return files, nil
+}
+
+func readUploadPart(part *multipart.Part, maxPartSize int64) (string, error) {
+	var r io.Reader = part
+	if maxPartSize > 0 {
+		r = io.LimitReader(part, maxPartSize+1)
+	}
+	var contents strings.Builder
+	n, err := io.Copy(&contents, r)
+	if err != nil {
+		return "", apierrors.NewBadRequest(fmt.Sprintf("failed to read file %q: %v", part.FormName(), err))
+	}
+	if maxPartSize > 0 && n > maxPartSize {
+		return "", apierrors.NewRequestEntityTooLargeError(fmt.Sprintf("file %q exceeds the limit of %d bytes per part", part.FormName(), maxPartSize))
+	}
+	return contents.String(), nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"bytes"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// uploadBody returns a multipart reader of a body with a part per file.
+func uploadBody(t *testing.T, files ...[2]string) *multipart.Reader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, file := range files {
+		part, err := writer.CreateFormFile(file[0], file[0])
+		if err != nil {
+			t.Fatalf("CreateFormFile failed: %v", err)
+		}
+		if _, err := part.Write([]byte(file[1])); err != nil {
+			t.Fatalf("Failed to write part: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close multipart writer: %v", err)
+	}
+	return multipart.NewReader(&body, writer.Boundary())
+}
+
+func TestReadUploadParts(t *testing.T) {
+	got, err := readUploadParts(uploadBody(t,
+		[2]string{"Kptfile", "kind: Kptfile\n"},
+		[2]string{"config/cm.yaml", "kind: ConfigMap\n"},
+		[2]string{"./README.md", "# Package\n"},
+	), 64)
+	if err != nil {
+		t.Fatalf("readUploadParts failed: %v", err)
+	}
+	want := map[string]string{
+		"Kptfile":        "kind: Kptfile\n",
+		"config/cm.yaml": "kind: ConfigMap\n",
+		"README.md":      "# Package\n",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected files (-want, +got): %s", diff)
+	}
+}
+
+func TestReadUploadPartsErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		files       [][2]string
+		maxPartSize int64
+		tooLarge    bool
+	}{
+		{
+			name:        "part too large",
+			files:       [][2]string{{"Kptfile", strings.Repeat("x", 65)}},
+			maxPartSize: 64,
+			tooLarge:    true,
+		},
+		{
+			name:  "absolute path",
+			files: [][2]string{{"/etc/passwd", "root"}},
+		},
+		{
+			name:  "path outside package",
+			files: [][2]string{{"../Kptfile", "kind: Kptfile"}},
+		},
+		{
+			name:  "duplicate file",
+			files: [][2]string{{"Kptfile", "a"}, {"./Kptfile", "b"}},
+		},
+		{
+			name: "no files",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := readUploadParts(uploadBody(t, tc.files...), tc.maxPartSize)
+			switch {
+			case tc.tooLarge && !apierrors.IsRequestEntityTooLargeError(err):
+				t.Errorf("got error %v, want 413 RequestEntityTooLarge", err)
+			case !tc.tooLarge && !apierrors.IsBadRequest(err):
+				t.Errorf("got error %v, want 400 BadRequest", err)
+			}
+		})
+	}
+}
+
+func TestReadUploadPartsUnlimited(t *testing.T) {
+	contents := strings.Repeat("x", 1<<20)
+	got, err := readUploadParts(uploadBody(t, [2]string{"large.txt", contents}), 0)
+	if err != nil {
+		t.Fatalf("readUploadParts failed: %v", err)
+	}
+	if len(got["large.txt"]) != len(contents) {
+		t.Errorf("got %d bytes, want %d", len(got["large.txt"]), len(contents))
+	}
+}
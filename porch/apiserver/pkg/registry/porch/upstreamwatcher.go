@@ -0,0 +1,182 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultUpstreamCheckInterval is how often the upstream repositories of
+	// package revisions are checked for new commits.
+	DefaultUpstreamCheckInterval = time.Hour
+
+	// upstreamCheckDebounce is the minimum time between two checks of the
+	// same upstream repository.
+	upstreamCheckDebounce = time.Minute
+)
+
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// UpstreamRefResolver resolves the branches and tags of a remote git
+// repository to the commits they point to.
+type UpstreamRefResolver interface {
+	ResolveRefs(ctx context.Context, repo string) (map[string]string, error)
+}
+
+// UpstreamRefResolverFunc adapts a function to an UpstreamRefResolver.
+type UpstreamRefResolverFunc func(ctx context.Context, repo string) (map[string]string, error)
+
+func (f UpstreamRefResolverFunc) ResolveRefs(ctx context.Context, repo string) (map[string]string, error) {
+	return f(ctx, repo)
+}
+
+// UpstreamWatcher watches the git upstreams of package revisions for new
+// commits, and marks package revisions whose upstream ref moved past the
+// locked commit as having an upstream update available.
+type UpstreamWatcher struct {
+	resolver UpstreamRefResolver
+
+	mutex sync.Mutex
+	// upstreams records the refs of every upstream repository seen in a
+	// package revision, with the commits they resolved to when last checked.
+	upstreams map[string]*upstreamRepository
+	clock     clock.Clock
+}
+
+type upstreamRepository struct {
+	// refs maps the tracked refs to their commits; empty until checked.
+	refs      map[string]string
+	checkedAt time.Time
+}
+
+func NewUpstreamWatcher(resolver UpstreamRefResolver, clk clock.Clock) *UpstreamWatcher {
+	return &UpstreamWatcher{
+		resolver:  resolver,
+		upstreams: map[string]*upstreamRepository{},
+		clock:     clk,
+	}
+}
+
+// Run checks the tracked upstream repositories every interval until ctx is
+// done. Repositories with newly tracked refs are checked without waiting for
+// the interval, but at most once per debounce period.
+func (w *UpstreamWatcher) Run(ctx context.Context, interval time.Duration) {
+	period := upstreamCheckDebounce
+	if interval < period {
+		period = interval
+	}
+	ticker := w.clock.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			w.refresh(ctx, interval)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh resolves the refs of the upstream repositories which are due for a
+// check. Each repository is listed once for all of its refs.
+func (w *UpstreamWatcher) refresh(ctx context.Context, interval time.Duration) {
+	now := w.clock.Now()
+	w.mutex.Lock()
+	var repos []string
+	for repo, upstream := range w.upstreams {
+		if upstream.due(now, interval) {
+			repos = append(repos, repo)
+		}
+	}
+	w.mutex.Unlock()
+
+	for _, repo := range repos {
+		refs, err := w.resolver.ResolveRefs(ctx, repo)
+		w.observe(repo, refs, err)
+	}
+}
+
+func (u *upstreamRepository) due(now time.Time, interval time.Duration) bool {
+	elapsed := now.Sub(u.checkedAt)
+	if elapsed >= interval {
+		return true
+	}
+	if elapsed < upstreamCheckDebounce {
+		return false
+	}
+	for _, commit := range u.refs {
+		if commit == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *UpstreamWatcher) observe(repo string, resolved map[string]string, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	upstream := w.upstreams[repo]
+	upstream.checkedAt = w.clock.Now()
+	if err != nil {
+		klog.Warningf("cannot check upstream repository %s for updates: %v", repo, err)
+		return
+	}
+	for ref, commit := range upstream.refs {
+		if latest, found := resolved[ref]; found && latest != commit {
+			if commit != "" {
+				klog.Infof("upstream ref %s of %s moved from %s to %s", ref, repo, commit, latest)
+			}
+			upstream.refs[ref] = latest
+		}
+	}
+}
+
+// UpdateStatus sets the UpstreamUpdateAvailable status of the package
+// revision, and starts tracking its upstream ref. Package revisions not
+// cloned from a git upstream, or locked to a commit SHA rather than a branch
+// or tag, are left unchanged.
+func (w *UpstreamWatcher) UpdateStatus(pr *api.PackageRevision) {
+	lock := pr.Status.UpstreamLock
+	if w == nil || lock == nil || lock.Git == nil || lock.Git.Repo == "" || lock.Git.Ref == "" || commitSHA.MatchString(lock.Git.Ref) {
+		return
+	}
+	repo, ref := lock.Git.Repo, lock.Git.Ref
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	upstream, found := w.upstreams[repo]
+	if !found {
+		upstream = &upstreamRepository{refs: map[string]string{}}
+		w.upstreams[repo] = upstream
+	}
+	latest, found := upstream.refs[ref]
+	if !found {
+		// Start tracking the ref; it is resolved on the next check.
+		upstream.refs[ref] = ""
+		return
+	}
+	pr.Status.UpstreamUpdateAvailable = latest != "" && latest != lock.Git.Commit
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock/fakeclock"
+)
+
+func TestUpstreamWatcher(t *testing.T) {
+	const (
+		repo   = "https://github.com/example/blueprints.git"
+		locked = "1111111111111111111111111111111111111111"
+		newer  = "2222222222222222222222222222222222222222"
+	)
+
+	refs := map[string]string{"main": locked}
+	calls := 0
+	clock := fakeclock.NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	watcher := NewUpstreamWatcher(UpstreamRefResolverFunc(func(ctx context.Context, url string) (map[string]string, error) {
+		calls++
+		return refs, nil
+	}), clock)
+
+	newPackageRevision := func(ref string) *api.PackageRevision {
+		return &api.PackageRevision{
+			Status: api.PackageRevisionStatus{
+				UpstreamLock: &api.UpstreamLock{
+					Type: api.RepositoryTypeGit,
+					Git:  &api.GitLock{Repo: repo, Directory: "basens", Ref: ref, Commit: locked},
+				},
+			},
+		}
+	}
+
+	// The first read starts tracking the upstream ref.
+	watcher.UpdateStatus(newPackageRevision("main"))
+	watcher.refresh(context.Background(), time.Hour)
+
+	pr := newPackageRevision("main")
+	watcher.UpdateStatus(pr)
+	if pr.Status.UpstreamUpdateAvailable {
+		t.Errorf("expected no upstream update with unchanged upstream ref")
+	}
+
+	// A commit is pushed to the upstream branch. The repository was just
+	// checked, so it is not checked again before the interval elapses.
+	refs = map[string]string{"main": newer}
+	clock.Advance(10 * time.Minute)
+	watcher.refresh(context.Background(), time.Hour)
+	if got, want := calls, 1; got != want {
+		t.Errorf("upstream checks before interval: got %d, want %d", got, want)
+	}
+
+	clock.Advance(time.Hour)
+	watcher.refresh(context.Background(), time.Hour)
+	pr = newPackageRevision("main")
+	watcher.UpdateStatus(pr)
+	if !pr.Status.UpstreamUpdateAvailable {
+		t.Errorf("expected upstream update after upstream ref moved")
+	}
+
+	// Package revisions without an upstream, or locked to a commit, are not tracked.
+	pr = &api.PackageRevision{}
+	watcher.UpdateStatus(pr)
+	watcher.UpdateStatus(newPackageRevision(locked))
+	if got, want := len(watcher.upstreams[repo].refs), 1; got != want {
+		t.Errorf("tracked refs: got %d, want %d", got, want)
+	}
+}
+
+func TestUpstreamWatcherDebounce(t *testing.T) {
+	const repo = "https://github.com/example/blueprints.git"
+
+	calls := 0
+	clock := fakeclock.NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	watcher := NewUpstreamWatcher(UpstreamRefResolverFunc(func(ctx context.Context, url string) (map[string]string, error) {
+		calls++
+		return map[string]string{"main": "aaa", "v1": "bbb"}, nil
+	}), clock)
+
+	track := func(ref string) {
+		watcher.UpdateStatus(&api.PackageRevision{
+			Status: api.PackageRevisionStatus{
+				UpstreamLock: &api.UpstreamLock{Git: &api.GitLock{Repo: repo, Ref: ref, Commit: "aaa"}},
+			},
+		})
+	}
+
+	track("main")
+	watcher.refresh(context.Background(), time.Hour)
+
+	// A new ref of the same repository is tracked right after the check; it
+	// is resolved once the debounce period elapses, without waiting for the
+	// interval.
+	track("v1")
+	watcher.refresh(context.Background(), time.Hour)
+	if got, want := calls, 1; got != want {
+		t.Errorf("upstream checks within debounce period: got %d, want %d", got, want)
+	}
+	clock.Advance(upstreamCheckDebounce)
+	watcher.refresh(context.Background(), time.Hour)
+	if got, want := calls, 2; got != want {
+		t.Errorf("upstream checks after debounce period: got %d, want %d", got, want)
+	}
+	if got, want := watcher.upstreams[repo].refs["v1"], "bbb"; got != want {
+		t.Errorf("resolved ref v1: got %q, want %q", got, want)
+	}
+}
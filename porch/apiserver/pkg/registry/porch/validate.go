@@ -0,0 +1,253 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// packageRevisionsValidate validates the resources of a package revision
+// against the OpenAPI schemas of their kinds.
+type packageRevisionsValidate struct {
+	common packageCommon
+}
+
+var _ rest.Storage = &packageRevisionsValidate{}
+var _ rest.Scoper = &packageRevisionsValidate{}
+var _ rest.NamedCreater = &packageRevisionsValidate{}
+
+// New returns an empty object that can be used with Create and Update after request data has been put into it.
+// This object must be a pointer type for use with Codec.DecodeInto([]byte, runtime.Object)
+func (v *packageRevisionsValidate) New() runtime.Object {
+	return &api.PackageRevisionValidationReport{}
+}
+
+// NamespaceScoped returns true if the storage is namespaced
+func (v *packageRevisionsValidate) NamespaceScoped() bool {
+	return true
+}
+
+// Create validates the resources of the named package revision. The posted
+// object is ignored; the validation report is returned.
+func (v *packageRevisionsValidate) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	pkg, err := v.common.getPackage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := pkg.GetResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := validatePackageResources(resources.Spec.Resources)
+	report.ObjectMeta = metav1.ObjectMeta{
+		Name:              resources.Name,
+		Namespace:         resources.Namespace,
+		UID:               resources.UID,
+		ResourceVersion:   resources.ResourceVersion,
+		CreationTimestamp: resources.CreationTimestamp,
+	}
+	return report, nil
+}
+
+// validatePackageResources validates the KRM resources in the package YAML
+// files against the built-in OpenAPI schemas of the Kubernetes kinds.
+// Resources of kinds without a known schema, such as custom resources and the
+// Kptfile, are only checked to be well-formed KRM.
+func validatePackageResources(resources map[string]string) *api.PackageRevisionValidationReport {
+	v := &resourceValidator{}
+
+	filenames := make([]string, 0, len(resources))
+	for filename := range resources {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		if !isYAMLResourceFile(filename) {
+			continue
+		}
+		v.filename = filename
+		nodes, err := (&kio.ByteReader{
+			Reader:                strings.NewReader(resources[filename]),
+			OmitReaderAnnotations: true,
+		}).Read()
+		if err != nil {
+			v.errorf("", "cannot parse resources: %v", err)
+			continue
+		}
+		for _, n := range nodes {
+			v.validateResource(n)
+		}
+	}
+
+	return &api.PackageRevisionValidationReport{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevisionValidationReport",
+			APIVersion: api.SchemeGroupVersion.Identifier(),
+		},
+		Valid:  len(v.errors) == 0,
+		Errors: v.errors,
+	}
+}
+
+func isYAMLResourceFile(filename string) bool {
+	return strings.HasSuffix(filename, ".yaml") || strings.HasSuffix(filename, ".yml") || filename == "Kptfile" || strings.HasSuffix(filename, "/Kptfile")
+}
+
+// validationFieldErrors converts a failed validation report to field errors
+// of the package revision resources.
+func validationFieldErrors(report *api.PackageRevisionValidationReport) field.ErrorList {
+	var errs field.ErrorList
+	for _, e := range report.Errors {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "resources").Key(e.Filename), e.Path, e.Message))
+	}
+	return errs
+}
+
+type resourceValidator struct {
+	filename string
+	errors   []api.ResourceValidationError
+}
+
+func (v *resourceValidator) errorf(path string, format string, args ...interface{}) {
+	v.errors = append(v.errors, api.ResourceValidationError{
+		Filename: v.filename,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *resourceValidator) validateResource(n *yaml.RNode) {
+	apiVersion, kind := n.GetApiVersion(), n.GetKind()
+	if apiVersion == "" {
+		v.errorf("apiVersion", "resource has no apiVersion")
+	}
+	if kind == "" {
+		v.errorf("kind", "resource has no kind")
+	}
+	if apiVersion == "" || kind == "" {
+		return
+	}
+
+	schema := openapi.SchemaForResourceType(yaml.TypeMeta{APIVersion: apiVersion, Kind: kind})
+	if schema == nil {
+		return
+	}
+	v.validate("", n.YNode(), schema)
+}
+
+// validate checks the node against the schema: the node type must match the
+// schema type, required fields must be present and objects with declared
+// properties must not have unknown fields.
+func (v *resourceValidator) validate(path string, node *yaml.Node, schema *openapi.ResourceSchema) {
+	if schema == nil || schema.Schema == nil || node.ShortTag() == yaml.NodeTagNull {
+		return
+	}
+	s := schema.Schema
+
+	switch {
+	case s.Type.Contains("object"):
+		if node.Kind != yaml.MappingNode {
+			v.errorf(path, "expected object, got %s", nodeType(node))
+			return
+		}
+		present := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name := node.Content[i].Value
+			present[name] = true
+			fieldPath := joinFieldPath(path, name)
+			if _, found := s.Properties[name]; !found && s.AdditionalProperties == nil {
+				if len(s.Properties) > 0 {
+					v.errorf(fieldPath, "unknown field %q", name)
+				}
+				continue
+			}
+			v.validate(fieldPath, node.Content[i+1], schema.Field(name))
+		}
+		for _, name := range s.Required {
+			if !present[name] {
+				v.errorf(joinFieldPath(path, name), "required field %q is missing", name)
+			}
+		}
+
+	case s.Type.Contains("array"):
+		if node.Kind != yaml.SequenceNode {
+			v.errorf(path, "expected array, got %s", nodeType(node))
+			return
+		}
+		items := schema.Elements()
+		for i, item := range node.Content {
+			v.validate(fmt.Sprintf("%s[%d]", path, i), item, items)
+		}
+
+	case s.Format == "int-or-string":
+		if tag := node.ShortTag(); node.Kind != yaml.ScalarNode || (tag != yaml.NodeTagInt && tag != yaml.NodeTagString) {
+			v.errorf(path, "expected integer or string, got %s", nodeType(node))
+		}
+
+	case s.Type.Contains("string"):
+		// Numbers are accepted for strings such as resource quantities.
+		if tag := node.ShortTag(); node.Kind != yaml.ScalarNode || tag == yaml.NodeTagBool {
+			v.errorf(path, "expected string, got %s", nodeType(node))
+		}
+
+	case s.Type.Contains("integer"):
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != yaml.NodeTagInt {
+			v.errorf(path, "expected integer, got %s", nodeType(node))
+		}
+
+	case s.Type.Contains("number"):
+		if tag := node.ShortTag(); node.Kind != yaml.ScalarNode || (tag != yaml.NodeTagInt && tag != yaml.NodeTagFloat) {
+			v.errorf(path, "expected number, got %s", nodeType(node))
+		}
+
+	case s.Type.Contains("boolean"):
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != yaml.NodeTagBool {
+			v.errorf(path, "expected boolean, got %s", nodeType(node))
+		}
+	}
+}
+
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.ShortTag() {
+	case yaml.NodeTagInt:
+		return "integer"
+	case yaml.NodeTagFloat:
+		return "number"
+	case yaml.NodeTagBool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
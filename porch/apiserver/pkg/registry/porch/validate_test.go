@@ -0,0 +1,150 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidatePackageResources(t *testing.T) {
+	const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: app
+  template:
+    metadata:
+      labels:
+        app: app
+    spec:
+      containers:
+      - name: app
+        image: nginx
+        ports:
+        - containerPort: 80
+        resources:
+          limits:
+            cpu: 1
+            memory: 128Mi
+`
+	const kptfile = `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: pkg
+`
+
+	for _, tc := range []struct {
+		name      string
+		resources map[string]string
+		want      []api.ResourceValidationError
+	}{
+		{
+			name: "Valid",
+			resources: map[string]string{
+				"Kptfile":         kptfile,
+				"deployment.yaml": deployment,
+				"README.md":       "# Not a resource: {",
+			},
+		},
+		{
+			name: "WrongType",
+			resources: map[string]string{
+				"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: three
+  selector: {}
+  template:
+    spec:
+      containers:
+      - name: app
+`,
+			},
+			want: []api.ResourceValidationError{
+				{Filename: "deployment.yaml", Path: "spec.replicas", Message: "expected integer, got string"},
+			},
+		},
+		{
+			name: "UnknownAndMissingFields",
+			resources: map[string]string{
+				"configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+datum:
+  key: value
+`,
+				"pod.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  containers:
+  - image: nginx
+`,
+			},
+			want: []api.ResourceValidationError{
+				{Filename: "configmap.yaml", Path: "datum", Message: `unknown field "datum"`},
+				{Filename: "pod.yaml", Path: "spec.containers[0].name", Message: `required field "name" is missing`},
+			},
+		},
+		{
+			name: "NotKRM",
+			resources: map[string]string{
+				"custom.yaml": `apiVersion: example.com/v1
+metadata:
+  name: custom
+`,
+			},
+			want: []api.ResourceValidationError{
+				{Filename: "custom.yaml", Path: "kind", Message: "resource has no kind"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			report := validatePackageResources(tc.resources)
+			if got, want := report.Valid, len(tc.want) == 0; got != want {
+				t.Errorf("Valid: got %t, want %t", got, want)
+			}
+			if diff := cmp.Diff(tc.want, report.Errors); diff != "" {
+				t.Errorf("Unexpected validation errors (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidatePackageResourcesParseError(t *testing.T) {
+	report := validatePackageResources(map[string]string{
+		"broken.yaml": "key: [unterminated\n",
+	})
+	if report.Valid {
+		t.Fatalf("Valid: got true, want false")
+	}
+	if got, want := len(report.Errors), 1; got != want {
+		t.Fatalf("Errors: got %d, want %d: %v", got, want, report.Errors)
+	}
+	if got, want := report.Errors[0].Filename, "broken.yaml"; got != want {
+		t.Errorf("Filename: got %q, want %q", got, want)
+	}
+}
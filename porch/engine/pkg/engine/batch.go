@@ -0,0 +1,88 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+)
+
+// ErrInvalidFileUpdate is returned when a file update of a batch cannot be
+// applied to the package, for example when adding a file which exists.
+var ErrInvalidFileUpdate = errors.New("invalid file update")
+
+// BatchUpdatePackageResources applies all file updates to the resources of
+// the package revision and commits them together. The updates are applied in
+// memory first; if any of them fails, nothing is written to the repository.
+func (cad *cadEngine) BatchUpdatePackageResources(ctx context.Context, repositoryObj *configapi.Repository, oldPackage repository.PackageRevision, updates []api.FileUpdate) (repository.PackageRevision, error) {
+	old, err := oldPackage.GetResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get package resources: %w", err)
+	}
+
+	resources, err := applyFileUpdates(old.Spec.Resources, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	new := old.DeepCopy()
+	new.Spec.Resources = resources
+	return cad.UpdatePackageResources(ctx, repositoryObj, oldPackage, old, new)
+}
+
+// applyFileUpdates returns the resources with the updates applied. The
+// resources passed in are not modified.
+func applyFileUpdates(resources map[string]string, updates []api.FileUpdate) (map[string]string, error) {
+	result := make(map[string]string, len(resources))
+	for k, v := range resources {
+		result[k] = v
+	}
+
+	for _, u := range updates {
+		filename := path.Clean(u.Filename)
+		if u.Filename == "" || filename == "." || filename == ".." || path.IsAbs(filename) || strings.HasPrefix(filename, "../") {
+			return nil, fmt.Errorf("%w: invalid filename %q", ErrInvalidFileUpdate, u.Filename)
+		}
+		_, exists := result[filename]
+
+		switch u.Operation {
+		case api.FileOperationAdd:
+			if exists {
+				return nil, fmt.Errorf("%w: cannot add %q; file already exists", ErrInvalidFileUpdate, filename)
+			}
+			result[filename] = u.Content
+		case api.FileOperationUpdate:
+			if !exists {
+				return nil, fmt.Errorf("%w: cannot update %q; file does not exist", ErrInvalidFileUpdate, filename)
+			}
+			result[filename] = u.Content
+		case api.FileOperationDelete:
+			if !exists {
+				return nil, fmt.Errorf("%w: cannot delete %q; file does not exist", ErrInvalidFileUpdate, filename)
+			}
+			delete(result, filename)
+		default:
+			return nil, fmt.Errorf("%w: unsupported operation %q for %q", ErrInvalidFileUpdate, u.Operation, filename)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApplyFileUpdates(t *testing.T) {
+	resources := map[string]string{
+		"Kptfile":         "kptfile",
+		"deployment.yaml": "deployment",
+		"service.yaml":    "service",
+	}
+	original := map[string]string{}
+	for k, v := range resources {
+		original[k] = v
+	}
+
+	for _, tc := range []struct {
+		name    string
+		updates []api.FileUpdate
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "all operations",
+			updates: []api.FileUpdate{
+				{Filename: "configmap.yaml", Content: "configmap", Operation: api.FileOperationAdd},
+				{Filename: "deployment.yaml", Content: "deployment v2", Operation: api.FileOperationUpdate},
+				{Filename: "service.yaml", Operation: api.FileOperationDelete},
+			},
+			want: map[string]string{
+				"Kptfile":         "kptfile",
+				"configmap.yaml":  "configmap",
+				"deployment.yaml": "deployment v2",
+			},
+		},
+		{
+			name: "add then update",
+			updates: []api.FileUpdate{
+				{Filename: "sub/configmap.yaml", Content: "configmap", Operation: api.FileOperationAdd},
+				{Filename: "sub/configmap.yaml", Content: "configmap v2", Operation: api.FileOperationUpdate},
+			},
+			want: map[string]string{
+				"Kptfile":            "kptfile",
+				"deployment.yaml":    "deployment",
+				"service.yaml":       "service",
+				"sub/configmap.yaml": "configmap v2",
+			},
+		},
+		{
+			name: "add existing",
+			updates: []api.FileUpdate{
+				{Filename: "configmap.yaml", Content: "configmap", Operation: api.FileOperationAdd},
+				{Filename: "service.yaml", Content: "service", Operation: api.FileOperationAdd},
+			},
+			wantErr: true,
+		},
+		{
+			name: "update missing",
+			updates: []api.FileUpdate{
+				{Filename: "missing.yaml", Content: "missing", Operation: api.FileOperationUpdate},
+			},
+			wantErr: true,
+		},
+		{
+			name: "delete missing",
+			updates: []api.FileUpdate{
+				{Filename: "missing.yaml", Operation: api.FileOperationDelete},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown operation",
+			updates: []api.FileUpdate{
+				{Filename: "service.yaml", Operation: "rename"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "outside package",
+			updates: []api.FileUpdate{
+				{Filename: "../escape.yaml", Content: "escape", Operation: api.FileOperationAdd},
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := applyFileUpdates(resources, tc.updates)
+			if tc.wantErr {
+				if !errors.Is(err, ErrInvalidFileUpdate) {
+					t.Errorf("applyFileUpdates() error = %v, want %v", err, ErrInvalidFileUpdate)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyFileUpdates() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected resources (-want, +got): %s", diff)
+			}
+		})
+	}
+
+	// The resources passed in must not be modified, even by a failed batch.
+	if diff := cmp.Diff(original, resources); diff != "" {
+		t.Errorf("applyFileUpdates modified its input (-want, +got): %s", diff)
+	}
+}
@@ -68,7 +68,8 @@ func newBuiltinRuntime() *builtinRuntime {
 var _ kpt.FunctionRuntime = &builtinRuntime{}
 
 func (br *builtinRuntime) GetRunner(ctx context.Context, funct *v1.Function) (fn.FunctionRunner, error) {
-	processor, found := br.fnMapping[funct.Image]
+	// Builtin functions match images pinned to a digest by their tag.
+	processor, found := br.fnMapping[unpinImage(funct.Image)]
 	if !found {
 		return nil, &fn.NotFoundError{Function: *funct}
 	}
@@ -116,6 +116,9 @@ func (m *clonePackageMutation) cloneFromRegisteredRepository(ctx context.Context
 	if err := kpt.UpdateKptfileUpstream(m.name, resources.Spec.Resources, upstream, lock); err != nil {
 		return repository.PackageResources{}, fmt.Errorf("failed to apply upstream lock to pakcage %q: %w", ref.Name, err)
 	}
+	if err := updateCloneLineage(m.name, resources.Spec.Resources, repository.CloneSource(parsed.repo, parsed.pkg, parsed.version)); err != nil {
+		return repository.PackageResources{}, fmt.Errorf("failed to record clone lineage of package %q: %w", ref.Name, err)
+	}
 
 	return repository.PackageResources{
 		Contents: resources.Spec.Resources,
@@ -174,6 +177,9 @@ func (m *clonePackageMutation) cloneFromGit(ctx context.Context, gitPackage *api
 	}); err != nil {
 		return repository.PackageResources{}, fmt.Errorf("failed to clone package %s@%s: %w", gitPackage.Directory, gitPackage.Ref, err)
 	}
+	if err := updateCloneLineage(m.name, contents, repository.CloneSource(lock.Repo, lock.Directory, lock.Ref)); err != nil {
+		return repository.PackageResources{}, fmt.Errorf("failed to record clone lineage of package %s@%s: %w", gitPackage.Directory, gitPackage.Ref, err)
+	}
 
 	return repository.PackageResources{
 		Contents: contents,
@@ -184,6 +190,19 @@ func (m *clonePackageMutation) cloneFromOci(ctx context.Context, ociPackage *api
 	return repository.PackageResources{}, errors.New("clone from OCI is not implemented")
 }
 
+// updateCloneLineage appends the clone source to the clone lineage recorded
+// in the Kptfile annotations of the cloned package.
+func updateCloneLineage(name string, contents map[string]string, source string) error {
+	return kpt.UpdateKptfileAnnotations(name, contents, func(annotations map[string]string) error {
+		lineage, err := repository.ParseCloneLineage(annotations)
+		if err != nil {
+			return err
+		}
+		lineage.Append(source).SetAnnotations(annotations)
+		return nil
+	})
+}
+
 type parsedRef struct {
 	repo, pkg, version string
 }
@@ -0,0 +1,119 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// dryRunDraft is a package draft which keeps the updates in memory instead of
+// writing them to the repository. Closing it returns the package revision
+// which would have been written.
+type dryRunDraft struct {
+	revision  *api.PackageRevision
+	resources map[string]string
+}
+
+var _ repository.PackageDraft = &dryRunDraft{}
+var _ repository.RenderRecorder = &dryRunDraft{}
+var _ repository.RejectionRecorder = &dryRunDraft{}
+var _ repository.GenerationRecorder = &dryRunDraft{}
+
+// newDryRunDraft returns a draft of the package revision with the given
+// resources. The draft owns a copy of the package revision.
+func newDryRunDraft(revision *api.PackageRevision, resources map[string]string) *dryRunDraft {
+	return &dryRunDraft{
+		revision:  revision.DeepCopy(),
+		resources: resources,
+	}
+}
+
+func (d *dryRunDraft) UpdateResources(ctx context.Context, new *api.PackageRevisionResources, task *api.Task) error {
+	d.resources = new.Spec.Resources
+	return nil
+}
+
+func (d *dryRunDraft) RecordRender(ctx context.Context, status repository.RenderStatus) error {
+	d.revision.Status.LastRenderedAt = metav1.Time{Time: status.RenderedAt}
+	d.revision.Status.LastRenderedFunctionDigests = status.FunctionDigests
+	return nil
+}
+
+func (d *dryRunDraft) RecordRejection(ctx context.Context, reason string) error {
+	d.revision.Status.RejectionReason = reason
+	return nil
+}
+
+func (d *dryRunDraft) RecordGeneration(ctx context.Context, generation int64) error {
+	d.revision.Generation = generation
+	return nil
+}
+
+func (d *dryRunDraft) RecordObservedGeneration(ctx context.Context, generation int64) error {
+	d.revision.Status.ObservedGeneration = generation
+	return nil
+}
+
+func (d *dryRunDraft) UpdateLifecycle(ctx context.Context, new api.PackageRevisionLifecycle) error {
+	d.revision.Spec.Lifecycle = new
+	return nil
+}
+
+func (d *dryRunDraft) Close(ctx context.Context) (repository.PackageRevision, error) {
+	return &dryRunPackageRevision{
+		revision:  d.revision,
+		resources: d.resources,
+	}, nil
+}
+
+// dryRunPackageRevision is the package revision produced by a dry run. It
+// exists only in memory.
+type dryRunPackageRevision struct {
+	revision  *api.PackageRevision
+	resources map[string]string
+}
+
+var _ repository.PackageRevision = &dryRunPackageRevision{}
+
+func (p *dryRunPackageRevision) Name() string {
+	return p.revision.Name
+}
+
+func (p *dryRunPackageRevision) GetPackageRevision() (*api.PackageRevision, error) {
+	return p.revision.DeepCopy(), nil
+}
+
+func (p *dryRunPackageRevision) GetResources(ctx context.Context) (*api.PackageRevisionResources, error) {
+	return &api.PackageRevisionResources{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevisionResources",
+			APIVersion: api.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: *p.revision.ObjectMeta.DeepCopy(),
+		Spec: api.PackageRevisionResourcesSpec{
+			Resources: p.resources,
+		},
+	}, nil
+}
+
+func (p *dryRunPackageRevision) GetUpstreamLock() (kptfilev1.Upstream, kptfilev1.UpstreamLock, error) {
+	return kptfilev1.Upstream{}, kptfilev1.UpstreamLock{}, fmt.Errorf("package revision %s was not persisted by the dry run and cannot be an upstream", p.Name())
+}
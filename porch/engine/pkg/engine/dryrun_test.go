@@ -0,0 +1,90 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDryRunDraft(t *testing.T) {
+	ctx := context.Background()
+	base := &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo:app:v1", Namespace: "default"},
+		Spec: api.PackageRevisionSpec{
+			PackageName: "app",
+			Revision:    "v1",
+			Lifecycle:   api.PackageRevisionLifecycleDraft,
+		},
+	}
+	draft := newDryRunDraft(base, map[string]string{"Kptfile": "old"})
+
+	renderedAt := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	digests := map[string]string{"gcr.io/kpt-fn/set-labels:v0.1": "sha256:1234"}
+	if err := draft.RecordRender(ctx, repository.RenderStatus{RenderedAt: renderedAt, FunctionDigests: digests}); err != nil {
+		t.Fatalf("RecordRender failed: %v", err)
+	}
+	resources := map[string]string{"Kptfile": "new", "cm.yaml": "kind: ConfigMap"}
+	if err := draft.UpdateResources(ctx, &api.PackageRevisionResources{
+		Spec: api.PackageRevisionResourcesSpec{Resources: resources},
+	}, &api.Task{Type: api.TaskTypeEval}); err != nil {
+		t.Fatalf("UpdateResources failed: %v", err)
+	}
+	if err := draft.UpdateLifecycle(ctx, api.PackageRevisionLifecycleProposed); err != nil {
+		t.Fatalf("UpdateLifecycle failed: %v", err)
+	}
+
+	rev, err := draft.Close(ctx)
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	pr, err := rev.GetPackageRevision()
+	if err != nil {
+		t.Fatalf("GetPackageRevision failed: %v", err)
+	}
+	if got, want := pr.Spec.Lifecycle, api.PackageRevisionLifecycleProposed; got != want {
+		t.Errorf("lifecycle: got %q, want %q", got, want)
+	}
+	if got, want := pr.Status.LastRenderedAt.Time, renderedAt; !got.Equal(want) {
+		t.Errorf("last rendered at: got %v, want %v", got, want)
+	}
+	if diff := cmp.Diff(digests, pr.Status.LastRenderedFunctionDigests); diff != "" {
+		t.Errorf("unexpected function digests (-want, +got): %s", diff)
+	}
+	if got, want := base.Spec.Lifecycle, api.PackageRevisionLifecycleDraft; got != want {
+		t.Errorf("base package revision was modified: lifecycle %q, want %q", got, want)
+	}
+
+	got, err := rev.GetResources(ctx)
+	if err != nil {
+		t.Fatalf("GetResources failed: %v", err)
+	}
+	if got.Name != base.Name || got.Namespace != base.Namespace {
+		t.Errorf("resources name: got %s/%s, want %s/%s", got.Namespace, got.Name, base.Namespace, base.Name)
+	}
+	if diff := cmp.Diff(resources, got.Spec.Resources); diff != "" {
+		t.Errorf("unexpected resources (-want, +got): %s", diff)
+	}
+
+	if _, _, err := rev.GetUpstreamLock(); err == nil {
+		t.Errorf("expected the dry-run package revision to have no upstream lock")
+	}
+}
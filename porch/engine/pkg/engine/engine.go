@@ -33,11 +33,20 @@ import (
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
 )
 
+// ChangeOptions control how package revision changes are applied.
+type ChangeOptions struct {
+	// DryRun computes the changed package revision without writing it to
+	// the repository.
+	DryRun bool
+}
+
 type CaDEngine interface {
 	OpenRepository(ctx context.Context, repositorySpec *configapi.Repository) (repository.Repository, error)
-	CreatePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, obj *api.PackageRevision) (repository.PackageRevision, error)
-	UpdatePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, oldPackage repository.PackageRevision, old, new *api.PackageRevision) (repository.PackageRevision, error)
+	CreatePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, obj *api.PackageRevision, opts ChangeOptions) (repository.PackageRevision, error)
+	UpdatePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, oldPackage repository.PackageRevision, old, new *api.PackageRevision, opts ChangeOptions) (repository.PackageRevision, error)
 	UpdatePackageResources(ctx context.Context, repositoryObj *configapi.Repository, oldPackage repository.PackageRevision, old, new *api.PackageRevisionResources) (repository.PackageRevision, error)
+	BatchUpdatePackageResources(ctx context.Context, repositoryObj *configapi.Repository, oldPackage repository.PackageRevision, updates []api.FileUpdate) (repository.PackageRevision, error)
+	TransferPackageRevision(ctx context.Context, sourceRepositoryObj, targetRepositoryObj *configapi.Repository, oldPackage repository.PackageRevision) (repository.PackageRevision, error)
 	DeletePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, obj repository.PackageRevision) error
 	ListFunctions(ctx context.Context, repositoryObj *configapi.Repository) ([]repository.Function, error)
 }
@@ -59,6 +68,9 @@ type cadEngine struct {
 	credentialResolver repository.CredentialResolver
 	referenceResolver  ReferenceResolver
 	userInfoProvider   repository.UserInfoProvider
+	// maxFunctionInvocations limits the number of function calls per package render. Zero means no limit.
+	maxFunctionInvocations int
+	functionDigestResolver FunctionDigestResolver
 }
 
 var _ CaDEngine = &cadEngine{}
@@ -71,7 +83,7 @@ func (cad *cadEngine) OpenRepository(ctx context.Context, repositorySpec *config
 	return cad.cache.OpenRepository(ctx, repositorySpec)
 }
 
-func (cad *cadEngine) CreatePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, obj *api.PackageRevision) (repository.PackageRevision, error) {
+func (cad *cadEngine) CreatePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, obj *api.PackageRevision, opts ChangeOptions) (repository.PackageRevision, error) {
 	// Validate package lifecycle. Cannot create a final package
 	switch obj.Spec.Lifecycle {
 	case "":
@@ -86,13 +98,24 @@ func (cad *cadEngine) CreatePackageRevision(ctx context.Context, repositoryObj *
 		return nil, fmt.Errorf("unsupported lifecycle value: %s", obj.Spec.Lifecycle)
 	}
 
-	repo, err := cad.cache.OpenRepository(ctx, repositoryObj)
-	if err != nil {
-		return nil, err
+	var draft repository.PackageDraft
+	if opts.DryRun {
+		draft = newDryRunDraft(obj, nil)
+	} else {
+		repo, err := cad.cache.OpenRepository(ctx, repositoryObj)
+		if err != nil {
+			return nil, err
+		}
+		draft, err = repo.CreatePackageRevision(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
 	}
-	draft, err := repo.CreatePackageRevision(ctx, obj)
-	if err != nil {
-		return nil, err
+
+	if recorder, ok := draft.(repository.LabelRecorder); ok {
+		if err := recorder.RecordLabels(ctx, obj.Labels); err != nil {
+			return nil, err
+		}
 	}
 
 	var mutations []mutation
@@ -127,11 +150,41 @@ func (cad *cadEngine) CreatePackageRevision(ctx context.Context, repositoryObj *
 		mutations = append(mutations, mutation)
 	}
 
-	// Render package after creation.
-	mutations = append(mutations, &renderPackageMutation{
-		renderer: cad.renderer,
-		runtime:  cad.runtime,
-	})
+	// Record the render policy in the Kptfile.
+	if obj.Spec.RenderPolicy != "" {
+		mutations = append(mutations, &renderPolicyMutation{
+			name:   obj.Spec.PackageName,
+			policy: obj.Spec.RenderPolicy,
+		})
+	}
+
+	// Record the required secrets and the target cluster in the Kptfile.
+	if hasSecretRequirements(&obj.Spec) {
+		mutations = append(mutations, &requiredSecretsMutation{
+			name:    obj.Spec.PackageName,
+			secrets: obj.Spec.RequiredSecrets,
+			target:  obj.Spec.Target,
+		})
+	}
+
+	// Record the function image locks in the Kptfile, before rendering
+	// pins the functions to them.
+	if len(obj.Spec.ImageLocks) > 0 {
+		mutations = append(mutations, &imageLocksMutation{
+			name:  obj.Spec.PackageName,
+			locks: obj.Spec.ImageLocks,
+		})
+	}
+
+	// Render package after creation, unless the render policy defers it.
+	if renderOnChange(obj.Spec.RenderPolicy) {
+		mutations = append(mutations, &renderPackageMutation{
+			renderer:               cad.renderer,
+			runtime:                cad.runtime,
+			maxFunctionInvocations: cad.maxFunctionInvocations,
+			digestResolver:         cad.functionDigestResolver,
+		})
+	}
 
 	baseResources := repository.PackageResources{}
 	if err := applyResourceMutations(ctx, draft, baseResources, mutations); err != nil {
@@ -190,13 +243,13 @@ func (cad *cadEngine) mapTaskToMutation(ctx context.Context, obj *api.PackageRev
 	}
 }
 
-func (cad *cadEngine) UpdatePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, oldPackage repository.PackageRevision, oldObj, newObj *api.PackageRevision) (repository.PackageRevision, error) {
+func (cad *cadEngine) UpdatePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, oldPackage repository.PackageRevision, oldObj, newObj *api.PackageRevision, opts ChangeOptions) (repository.PackageRevision, error) {
 	// Validate package lifecycle. Can only update a draft.
 	switch lifecycle := oldObj.Spec.Lifecycle; lifecycle {
 	default:
 		return nil, fmt.Errorf("invalid original lifecycle value: %q", lifecycle)
-	case api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecycleProposed:
-		// Draft or proposed can be updated.
+	case api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecycleRejected:
+		// Draft, proposed or rejected can be updated.
 	case api.PackageRevisionLifecyclePublished:
 		// TODO: generate errors that can be translated to correct HTTP responses
 		return nil, fmt.Errorf("cannot update a package revision with lifecycle value %q", lifecycle)
@@ -204,7 +257,7 @@ func (cad *cadEngine) UpdatePackageRevision(ctx context.Context, repositoryObj *
 	switch lifecycle := newObj.Spec.Lifecycle; lifecycle {
 	default:
 		return nil, fmt.Errorf("invalid desired lifecycle value: %q", lifecycle)
-	case api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished:
+	case api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished, api.PackageRevisionLifecycleRejected:
 		// These values are ok
 	}
 
@@ -249,22 +302,80 @@ func (cad *cadEngine) UpdatePackageRevision(ctx context.Context, repositoryObj *
 		}
 	}
 
-	// Re-render if we are making changes.
-	if len(mutations) > 0 {
+	policy := oldObj.Spec.RenderPolicy
+	if newObj.Spec.RenderPolicy != "" && effectiveRenderPolicy(newObj.Spec.RenderPolicy) != effectiveRenderPolicy(policy) {
+		if oldObj.Spec.Lifecycle != api.PackageRevisionLifecycleDraft {
+			return nil, fmt.Errorf("cannot change the render policy of a package revision with lifecycle value %q; package must be Draft", oldObj.Spec.Lifecycle)
+		}
+		policy = newObj.Spec.RenderPolicy
+		mutations = append(mutations, &renderPolicyMutation{
+			name:   oldObj.Spec.PackageName,
+			policy: policy,
+		})
+	}
+
+	if secretRequirementsChanged(&oldObj.Spec, &newObj.Spec) {
+		if oldObj.Spec.Lifecycle != api.PackageRevisionLifecycleDraft {
+			return nil, fmt.Errorf("cannot change the required secrets of a package revision with lifecycle value %q; package must be Draft", oldObj.Spec.Lifecycle)
+		}
+		mutations = append(mutations, &requiredSecretsMutation{
+			name:    oldObj.Spec.PackageName,
+			secrets: newObj.Spec.RequiredSecrets,
+			target:  newObj.Spec.Target,
+		})
+	}
+
+	if imageLocksChanged(&oldObj.Spec, &newObj.Spec) {
+		if oldObj.Spec.Lifecycle != api.PackageRevisionLifecycleDraft {
+			return nil, fmt.Errorf("cannot change the image locks of a package revision with lifecycle value %q; package must be Draft", oldObj.Spec.Lifecycle)
+		}
+		mutations = append(mutations, &imageLocksMutation{
+			name:  oldObj.Spec.PackageName,
+			locks: newObj.Spec.ImageLocks,
+		})
+	}
+
+	// Re-render if we are making changes, or if the render policy defers
+	// rendering until the package is approved.
+	render := renderOnApproval(policy, oldObj.Spec.Lifecycle, newObj.Spec.Lifecycle)
+	if len(mutations) > 0 && renderOnChange(policy) {
+		render = true
+	}
+	// The spec is reconciled with the package contents once it is rendered,
+	// or if it makes no changes to an already reconciled package. Changes
+	// left unrendered by the render policy are not reconciled.
+	reconciled := render || (len(mutations) == 0 && oldObj.Status.ObservedGeneration == oldObj.Generation)
+	if render {
 		mutations = append(mutations, &renderPackageMutation{
-			renderer: cad.renderer,
-			runtime:  cad.runtime,
+			renderer:               cad.renderer,
+			runtime:                cad.runtime,
+			maxFunctionInvocations: cad.maxFunctionInvocations,
+			digestResolver:         cad.functionDigestResolver,
 		})
 	}
 
-	draft, err := repo.UpdatePackage(ctx, oldPackage)
+	draft, err := cad.updateDraft(ctx, repo, oldPackage, newObj, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	generationRecorder, recordsGeneration := draft.(repository.GenerationRecorder)
+	if recordsGeneration {
+		if err := generationRecorder.RecordGeneration(ctx, newObj.Generation); err != nil {
+			return nil, err
+		}
+	}
+
+	if recorder, ok := draft.(repository.LabelRecorder); ok {
+		if err := recorder.RecordLabels(ctx, newObj.Labels); err != nil {
+			return nil, err
+		}
+	}
+
 	// TODO: Handle the case if alongside lifecycle change, tasks are changed too.
-	// Update package contents only if the package is in draft state
-	if oldObj.Spec.Lifecycle == api.PackageRevisionLifecycleDraft {
+	// Update package contents only if the package is in draft state, or if
+	// it is rendered on approval.
+	if oldObj.Spec.Lifecycle == api.PackageRevisionLifecycleDraft || render {
 		apiResources, err := oldPackage.GetResources(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("cannot get package resources: %w", err)
@@ -278,6 +389,24 @@ func (cad *cadEngine) UpdatePackageRevision(ctx context.Context, repositoryObj *
 		}
 	}
 
+	if recordsGeneration && reconciled {
+		if err := generationRecorder.RecordObservedGeneration(ctx, newObj.Generation); err != nil {
+			return nil, err
+		}
+	}
+
+	if recorder, ok := draft.(repository.ChangelogRecorder); ok && newObj.Spec.ChangelogEntry != "" {
+		if err := recorder.RecordChangelog(ctx, newObj.Spec.ChangelogEntry); err != nil {
+			return nil, err
+		}
+	}
+
+	if recorder, ok := draft.(repository.RejectionRecorder); ok && newObj.Spec.Lifecycle == api.PackageRevisionLifecycleRejected {
+		if err := recorder.RecordRejection(ctx, newObj.Status.RejectionReason); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := draft.UpdateLifecycle(ctx, newObj.Spec.Lifecycle); err != nil {
 		return nil, err
 	}
@@ -286,6 +415,20 @@ func (cad *cadEngine) UpdatePackageRevision(ctx context.Context, repositoryObj *
 	return draft.Close(ctx)
 }
 
+// updateDraft returns the draft to apply an update of the package revision
+// to. A dry run applies the update to an in-memory copy of the new package
+// revision with the resources of the old one.
+func (cad *cadEngine) updateDraft(ctx context.Context, repo repository.Repository, oldPackage repository.PackageRevision, newObj *api.PackageRevision, opts ChangeOptions) (repository.PackageDraft, error) {
+	if !opts.DryRun {
+		return repo.UpdatePackage(ctx, oldPackage)
+	}
+	resources, err := oldPackage.GetResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get package resources: %w", err)
+	}
+	return newDryRunDraft(newObj, resources.Spec.Resources), nil
+}
+
 func (cad *cadEngine) DeletePackageRevision(ctx context.Context, repositoryObj *configapi.Repository, oldPackage repository.PackageRevision) error {
 	repo, err := cad.cache.OpenRepository(ctx, repositoryObj)
 	if err != nil {
@@ -311,7 +454,7 @@ func (cad *cadEngine) UpdatePackageResources(ctx context.Context, repositoryObj
 		return nil, fmt.Errorf("invalid original lifecycle value: %q", lifecycle)
 	case api.PackageRevisionLifecycleDraft:
 		// Only draf can be updated.
-	case api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished:
+	case api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished, api.PackageRevisionLifecycleRejected:
 		// TODO: generate errors that can be translated to correct HTTP responses
 		return nil, fmt.Errorf("cannot update a package revision with lifecycle value %q; package must be Draft", lifecycle)
 	}
@@ -331,10 +474,14 @@ func (cad *cadEngine) UpdatePackageResources(ctx context.Context, repositoryObj
 			newResources: new,
 			oldResources: old,
 		},
-		&renderPackageMutation{
-			renderer: cad.renderer,
-			runtime:  cad.runtime,
-		},
+	}
+	if renderOnChange(rev.Spec.RenderPolicy) {
+		mutations = append(mutations, &renderPackageMutation{
+			renderer:               cad.renderer,
+			runtime:                cad.runtime,
+			maxFunctionInvocations: cad.maxFunctionInvocations,
+			digestResolver:         cad.functionDigestResolver,
+		})
 	}
 
 	apiResources, err := oldPackage.GetResources(ctx)
@@ -359,6 +506,13 @@ func applyResourceMutations(ctx context.Context, draft repository.PackageDraft,
 		if err != nil {
 			return err
 		}
+		if render, ok := m.(*renderPackageMutation); ok && render.status != nil {
+			if recorder, ok := draft.(repository.RenderRecorder); ok {
+				if err := recorder.RecordRender(ctx, *render.status); err != nil {
+					return err
+				}
+			}
+		}
 		if err := draft.UpdateResources(ctx, &api.PackageRevisionResources{
 			Spec: api.PackageRevisionResourcesSpec{
 				Resources: applied.Contents,
@@ -0,0 +1,129 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// FileOrderAnnotation orders the resources written to the same file, for
+// example `kpt.dev/file-order: "0001"`. Values are compared as strings, so
+// they should be zero-padded.
+const FileOrderAnnotation = "kpt.dev/file-order"
+
+// NormalizeFileOrder returns the package resources with the YAML documents of
+// every file sorted by their FileOrderAnnotation. Documents without the
+// annotation follow the annotated ones. Documents with the same annotation
+// value, or without the annotation, are sorted by apiVersion, kind, namespace
+// and name, so that the order doesn't depend on the function which wrote
+// them. Files which cannot be parsed are returned unchanged.
+//
+// Git orders the entries of a tree by name, so the order of the files
+// themselves is already deterministic; functions which generate several
+// resources into the same file however may emit them in arbitrary order.
+func NormalizeFileOrder(resources map[string]string) map[string]string {
+	result := make(map[string]string, len(resources))
+	for k, v := range resources {
+		result[k] = v
+		if ext := path.Ext(k); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		if sorted, ok := sortDocuments(v); ok {
+			result[k] = sorted
+		}
+	}
+	return result
+}
+
+// documentKey orders the YAML documents of a file.
+type documentKey struct {
+	// order is the value of the FileOrderAnnotation; empty if the document
+	// is not annotated.
+	order                             string
+	apiVersion, kind, namespace, name string
+}
+
+func (a documentKey) less(b documentKey) bool {
+	if (a.order == "") != (b.order == "") {
+		return a.order != ""
+	}
+	if a.order != b.order {
+		return a.order < b.order
+	}
+	if a.apiVersion != b.apiVersion {
+		return a.apiVersion < b.apiVersion
+	}
+	if a.kind != b.kind {
+		return a.kind < b.kind
+	}
+	if a.namespace != b.namespace {
+		return a.namespace < b.namespace
+	}
+	return a.name < b.name
+}
+
+// sortDocuments sorts the YAML documents in contents. It returns false if
+// contents needs no reordering.
+func sortDocuments(contents string) (string, bool) {
+	// The sequence indentation of every document is recorded by the reader,
+	// so that the writer keeps it.
+	nodes, err := (&kio.ByteReader{
+		Reader:            strings.NewReader(contents),
+		PreserveSeqIndent: true,
+	}).Read()
+	if err != nil || len(nodes) < 2 {
+		return "", false
+	}
+
+	keys := make([]documentKey, len(nodes))
+	for i, n := range nodes {
+		keys[i] = documentKey{
+			order:      n.GetAnnotations()[FileOrderAnnotation],
+			apiVersion: n.GetApiVersion(),
+			kind:       n.GetKind(),
+			namespace:  n.GetNamespace(),
+			name:       n.GetName(),
+		}
+	}
+
+	order := make([]int, len(nodes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return keys[order[i]].less(keys[order[j]])
+	})
+	changed := false
+	sorted := make([]*yaml.RNode, len(nodes))
+	for i, j := range order {
+		sorted[i] = nodes[j]
+		changed = changed || i != j
+	}
+	if !changed {
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := (kio.ByteWriter{Writer: &buf}).Write(sorted); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
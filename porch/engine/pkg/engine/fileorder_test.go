@@ -0,0 +1,114 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+func TestNormalizeFileOrder(t *testing.T) {
+	configMap := func(name, order string) string {
+		s := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n"
+		if order != "" {
+			s += "  annotations:\n    kpt.dev/file-order: \"" + order + "\"\n"
+		}
+		return s
+	}
+
+	for _, tc := range []struct {
+		name      string
+		contents  string
+		wantNames []string
+		unchanged bool
+	}{
+		{
+			name:      "annotated",
+			contents:  configMap("c", "0003") + "---\n" + configMap("a", "0001") + "---\n" + configMap("b", "0002"),
+			wantNames: []string{"a", "b", "c"},
+		},
+		{
+			name:      "unannotated last",
+			contents:  configMap("y", "") + "---\n" + configMap("x", "") + "---\n" + configMap("z", "0001"),
+			wantNames: []string{"z", "x", "y"},
+		},
+		{
+			name:      "not annotated",
+			contents:  configMap("b", "") + "---\n" + configMap("a", ""),
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name:      "same annotation",
+			contents:  configMap("b", "0001") + "---\n" + configMap("a", "0001") + "---\n" + configMap("c", "0000"),
+			wantNames: []string{"c", "a", "b"},
+		},
+		{
+			name:      "by kind",
+			contents:  "apiVersion: v1\nkind: Service\nmetadata:\n  name: a\n---\n" + configMap("b", ""),
+			wantNames: []string{"b", "a"},
+		},
+		{
+			name:      "already sorted",
+			contents:  configMap("a", "0001") + "---\n" + configMap("b", "0002"),
+			unchanged: true,
+		},
+		{
+			name:      "not annotated and already sorted",
+			contents:  configMap("a", "") + "---\n" + configMap("b", ""),
+			unchanged: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NormalizeFileOrder(map[string]string{
+				"resources.yaml": tc.contents,
+				"README.md":      tc.contents,
+			})
+			if got["README.md"] != tc.contents {
+				t.Errorf("non-YAML file was modified")
+			}
+			if tc.unchanged {
+				if got["resources.yaml"] != tc.contents {
+					t.Errorf("NormalizeFileOrder modified file which needs no reordering:\n%s", got["resources.yaml"])
+				}
+				return
+			}
+
+			nodes, err := (&kio.ByteReader{Reader: strings.NewReader(got["resources.yaml"])}).Read()
+			if err != nil {
+				t.Fatalf("failed to parse normalized file: %v", err)
+			}
+			var names []string
+			for _, n := range nodes {
+				names = append(names, n.GetName())
+			}
+			if diff := cmp.Diff(tc.wantNames, names); diff != "" {
+				t.Errorf("unexpected resource order (-want,+got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeFileOrderKeepsSequenceIndent(t *testing.T) {
+	wide := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\ndata:\n  items: |\n    x\nlist:\n  - a\n  - b\n"
+	compact := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\nlist:\n- c\n"
+
+	got := NormalizeFileOrder(map[string]string{"resources.yaml": wide + "---\n" + compact})
+	if want := compact + "---\n" + wide; got["resources.yaml"] != want {
+		t.Errorf("unexpected normalized file (-want,+got): %s", cmp.Diff(want, got["resources.yaml"]))
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"time"
 
 	v1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/fn"
@@ -34,7 +35,7 @@ type grpcRuntime struct {
 	client evaluator.FunctionEvaluatorClient
 }
 
-func newGRPCFunctionRuntime(address string) (*grpcRuntime, error) {
+func newGRPCFunctionRuntime(address string, interceptors ...FunctionEvaluatorInterceptor) (*grpcRuntime, error) {
 	if address == "" {
 		return nil, fmt.Errorf("address is required to instantiate gRPC function runtime")
 	}
@@ -48,7 +49,7 @@ func newGRPCFunctionRuntime(address string) (*grpcRuntime, error) {
 
 	return &grpcRuntime{
 		cc:     cc,
-		client: evaluator.NewFunctionEvaluatorClient(cc),
+		client: ChainedFunctionEvaluator(evaluator.NewFunctionEvaluatorClient(cc), interceptors...),
 	}, err
 }
 
@@ -56,10 +57,15 @@ var _ kpt.FunctionRuntime = &grpcRuntime{}
 
 func (gr *grpcRuntime) GetRunner(ctx context.Context, fn *v1.Function) (fn.FunctionRunner, error) {
 	// TODO: Check if the function is actually available?
+	timeout, err := fn.GetTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout for function %q: %w", fn.Image, err)
+	}
 	return &grpcRunner{
-		ctx:    ctx,
-		client: gr.client,
-		image:  fn.Image,
+		ctx:     ctx,
+		client:  gr.client,
+		image:   fn.Image,
+		timeout: timeout,
 	}, nil
 }
 
@@ -78,6 +84,10 @@ type grpcRunner struct {
 	ctx    context.Context
 	client evaluator.FunctionEvaluatorClient
 	image  string
+	// timeout is the per-function timeout from the Kptfile pipeline. When
+	// set, it is sent as the gRPC deadline and overrides the default of the
+	// function runner.
+	timeout time.Duration
 }
 
 var _ fn.FunctionRunner = &grpcRunner{}
@@ -88,7 +98,14 @@ func (gr *grpcRunner) Run(r io.Reader, w io.Writer) error {
 		return fmt.Errorf("failed to read function runner input: %w", err)
 	}
 
-	res, err := gr.client.EvaluateFunction(gr.ctx, &evaluator.EvaluateFunctionRequest{
+	ctx := gr.ctx
+	if gr.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, gr.timeout)
+		defer cancel()
+	}
+
+	res, err := gr.client.EvaluateFunction(ctx, &evaluator.EvaluateFunctionRequest{
 		ResourceList: in,
 		Image:        gr.image,
 	})
@@ -0,0 +1,156 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	internalpkg "github.com/GoogleContainerTools/kpt/internal/pkg"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/fn"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/kpt"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"k8s.io/klog/v2"
+)
+
+// imageLocksChanged returns true if the image locks differ between the two
+// specs.
+func imageLocksChanged(oldSpec, newSpec *api.PackageRevisionSpec) bool {
+	if len(oldSpec.ImageLocks) == 0 && len(newSpec.ImageLocks) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(oldSpec.ImageLocks, newSpec.ImageLocks)
+}
+
+// imageLocksMutation records the function image locks of a package revision
+// in the annotations of its Kptfile.
+type imageLocksMutation struct {
+	name  string
+	locks map[string]string
+}
+
+var _ mutation = &imageLocksMutation{}
+
+func (m *imageLocksMutation) Apply(ctx context.Context, resources repository.PackageResources) (repository.PackageResources, *api.Task, error) {
+	contents := make(map[string]string, len(resources.Contents))
+	for k, v := range resources.Contents {
+		contents[k] = v
+	}
+	if err := setImageLocks(m.name, contents, m.locks); err != nil {
+		return repository.PackageResources{}, nil, err
+	}
+
+	return repository.PackageResources{Contents: contents}, &api.Task{
+		Type: api.TaskTypePatch,
+		Patch: &api.PackagePatchTaskSpec{
+			Patches: []string{kptfilev1.KptFileName},
+		},
+	}, nil
+}
+
+// setImageLocks records the image locks in the annotations of the Kptfile in
+// contents, removing the annotation if there are none.
+func setImageLocks(name string, contents map[string]string, locks map[string]string) error {
+	return kpt.UpdateKptfileAnnotations(name, contents, func(annotations map[string]string) error {
+		if len(locks) == 0 {
+			delete(annotations, api.ImageLocksAnnotation)
+			return nil
+		}
+		b, err := json.Marshal(locks)
+		if err != nil {
+			return fmt.Errorf("cannot encode image locks: %w", err)
+		}
+		annotations[api.ImageLocksAnnotation] = string(b)
+		return nil
+	})
+}
+
+// readImageLocks returns the image locks recorded in the Kptfile in
+// contents, or nil if there are none.
+func readImageLocks(contents map[string]string) map[string]string {
+	kptfile, found := contents[kptfilev1.KptFileName]
+	if !found {
+		return nil
+	}
+	kf, err := internalpkg.DecodeKptfile(strings.NewReader(kptfile))
+	if err != nil {
+		return nil
+	}
+	value, found := kf.Annotations[api.ImageLocksAnnotation]
+	if !found {
+		return nil
+	}
+	var locks map[string]string
+	if err := json.Unmarshal([]byte(value), &locks); err != nil {
+		klog.Warningf("Cannot parse %s annotation of Kptfile %s: %v", api.ImageLocksAnnotation, kf.Name, err)
+		return nil
+	}
+	return locks
+}
+
+// pinImage returns the image reference pinned to the digest. The tag is
+// kept, so the image can still be matched against builtin functions; the
+// digest takes precedence when the image is pulled. Images which already
+// reference a digest are returned unchanged.
+func pinImage(image, digest string) string {
+	if digest == "" || strings.Contains(image, "@") {
+		return image
+	}
+	return image + "@" + digest
+}
+
+// unpinImage returns the image reference without its digest.
+func unpinImage(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i]
+	}
+	return image
+}
+
+// lockingFunctionRuntime wraps a function runtime and pins the images of
+// the functions to their locked digests.
+type lockingFunctionRuntime struct {
+	runtime fn.FunctionRuntime
+	locks   map[string]string
+}
+
+var _ fn.FunctionRuntime = &lockingFunctionRuntime{}
+
+func (l *lockingFunctionRuntime) GetRunner(ctx context.Context, function *kptfilev1.Function) (fn.FunctionRunner, error) {
+	if digest := l.locks[function.Image]; digest != "" {
+		pinned := *function
+		pinned.Image = pinImage(function.Image, digest)
+		function = &pinned
+	}
+	return l.runtime.GetRunner(ctx, function)
+}
+
+// recordImageLocks adds the digests of the rendered functions which have no
+// lock, or an empty one, to locks and returns true if any were added.
+func recordImageLocks(locks map[string]string, digests map[string]string) bool {
+	changed := false
+	for image, digest := range digests {
+		if digest != "" && locks[image] == "" {
+			locks[image] = digest
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	v1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/fn"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/kpt"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+// recordingRuntime records the images of the functions it runs.
+type recordingRuntime struct {
+	mutex  sync.Mutex
+	images []string
+}
+
+func (r *recordingRuntime) GetRunner(ctx context.Context, function *v1.Function) (fn.FunctionRunner, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.images = append(r.images, function.Image)
+	return passthroughRunner{}, nil
+}
+
+type fakeDigestResolver map[string]string
+
+func (r fakeDigestResolver) ResolveDigest(ctx context.Context, image string) (string, error) {
+	return r[image], nil
+}
+
+const imageLocksKptfile = `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: pkg
+pipeline:
+  mutators:
+  - image: example.com/fn-a:v1
+  - image: example.com/fn-b:v1
+`
+
+func TestPinImage(t *testing.T) {
+	for _, tc := range []struct {
+		image, digest, want string
+	}{
+		{image: "example.com/fn:v1", digest: "sha256:aaa", want: "example.com/fn:v1@sha256:aaa"},
+		{image: "example.com/fn:v1", digest: "", want: "example.com/fn:v1"},
+		{image: "example.com/fn@sha256:bbb", digest: "sha256:aaa", want: "example.com/fn@sha256:bbb"},
+	} {
+		if got := pinImage(tc.image, tc.digest); got != tc.want {
+			t.Errorf("pinImage(%q, %q) = %q; want %q", tc.image, tc.digest, got, tc.want)
+		}
+	}
+
+	if got, want := unpinImage("example.com/fn:v1@sha256:aaa"), "example.com/fn:v1"; got != want {
+		t.Errorf("unpinImage = %q; want %q", got, want)
+	}
+}
+
+func TestRenderImageLocks(t *testing.T) {
+	contents := map[string]string{
+		"Kptfile":        imageLocksKptfile,
+		"configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+	}
+	if err := setImageLocks("pkg", contents, map[string]string{
+		"example.com/fn-a:v1": "sha256:aaa",
+		"example.com/fn-b:v1": "",
+	}); err != nil {
+		t.Fatalf("setImageLocks failed: %v", err)
+	}
+
+	runtime := &recordingRuntime{}
+	render := &renderPackageMutation{
+		renderer: kpt.NewRenderer(),
+		runtime:  runtime,
+		digestResolver: fakeDigestResolver{
+			"example.com/fn-a:v1": "sha256:new",
+			"example.com/fn-b:v1": "sha256:bbb",
+		},
+	}
+	rendered, _, err := render.Apply(context.Background(), repository.PackageResources{Contents: contents})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	// Locked functions are pinned; the others run by their tag.
+	if diff := cmp.Diff([]string{"example.com/fn-a:v1@sha256:aaa", "example.com/fn-b:v1"}, runtime.images); diff != "" {
+		t.Errorf("rendered images (-want, +got): %s", diff)
+	}
+	// The digest of the unlocked function is recorded; the lock is kept.
+	want := map[string]string{
+		"example.com/fn-a:v1": "sha256:aaa",
+		"example.com/fn-b:v1": "sha256:bbb",
+	}
+	if diff := cmp.Diff(want, readImageLocks(rendered.Contents)); diff != "" {
+		t.Errorf("image locks (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(want, render.status.FunctionDigests); diff != "" {
+		t.Errorf("rendered function digests (-want, +got): %s", diff)
+	}
+}
+
+func TestRenderWithoutImageLocks(t *testing.T) {
+	runtime := &recordingRuntime{}
+	render := &renderPackageMutation{
+		renderer:       kpt.NewRenderer(),
+		runtime:        runtime,
+		digestResolver: fakeDigestResolver{"example.com/fn-a:v1": "sha256:aaa"},
+	}
+	rendered, _, err := render.Apply(context.Background(), repository.PackageResources{
+		Contents: map[string]string{"Kptfile": imageLocksKptfile},
+	})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"example.com/fn-a:v1", "example.com/fn-b:v1"}, runtime.images); diff != "" {
+		t.Errorf("rendered images (-want, +got): %s", diff)
+	}
+	if locks := readImageLocks(rendered.Contents); locks != nil {
+		t.Errorf("image locks = %v; want none recorded for a package without locks", locks)
+	}
+}
+
+func TestImageLocksMutation(t *testing.T) {
+	resources := repository.PackageResources{
+		Contents: map[string]string{"Kptfile": imageLocksKptfile},
+	}
+	locks := map[string]string{"example.com/fn-a:v1": "sha256:aaa"}
+	got, _, err := (&imageLocksMutation{name: "pkg", locks: locks}).Apply(context.Background(), resources)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if diff := cmp.Diff(locks, readImageLocks(got.Contents)); diff != "" {
+		t.Errorf("image locks (-want, +got): %s", diff)
+	}
+
+	cleared, _, err := (&imageLocksMutation{name: "pkg"}).Apply(context.Background(), got)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if _, found := kptfileAnnotations(t, cleared)[api.ImageLocksAnnotation]; found {
+		t.Errorf("%s annotation should be removed when there are no image locks", api.ImageLocksAnnotation)
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// FunctionEvaluatorInterceptor adds behavior to the evaluation of functions
+// by the function runner. Intercept is called with the evaluation request and
+// calls next to continue the evaluation, or returns without calling next to
+// short-circuit it.
+type FunctionEvaluatorInterceptor interface {
+	Intercept(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error)
+}
+
+// FunctionEvaluatorInterceptorFunc adapts a function to a FunctionEvaluatorInterceptor.
+type FunctionEvaluatorInterceptorFunc func(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error)
+
+var _ FunctionEvaluatorInterceptor = FunctionEvaluatorInterceptorFunc(nil)
+
+func (f FunctionEvaluatorInterceptorFunc) Intercept(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error) {
+	return f(ctx, req, next)
+}
+
+// ChainedFunctionEvaluator wraps the evaluator in the interceptors. The first
+// interceptor is the outermost one; the evaluator is called by the last.
+func ChainedFunctionEvaluator(evaluator evaluator.FunctionEvaluatorClient, interceptors ...FunctionEvaluatorInterceptor) evaluator.FunctionEvaluatorClient {
+	if len(interceptors) == 0 {
+		return evaluator
+	}
+	return &chainedFunctionEvaluator{
+		FunctionEvaluatorClient: evaluator,
+		interceptors:            interceptors,
+	}
+}
+
+// chainedFunctionEvaluator intercepts EvaluateFunction calls; other calls go
+// directly to the embedded client.
+type chainedFunctionEvaluator struct {
+	evaluator.FunctionEvaluatorClient
+	interceptors []FunctionEvaluatorInterceptor
+}
+
+var _ evaluator.FunctionEvaluatorClient = &chainedFunctionEvaluator{}
+
+func (c *chainedFunctionEvaluator) EvaluateFunction(ctx context.Context, in *evaluator.EvaluateFunctionRequest, opts ...grpc.CallOption) (*evaluator.EvaluateFunctionResponse, error) {
+	return c.call(ctx, in, 0, opts)
+}
+
+func (c *chainedFunctionEvaluator) call(ctx context.Context, in *evaluator.EvaluateFunctionRequest, i int, opts []grpc.CallOption) (*evaluator.EvaluateFunctionResponse, error) {
+	if i == len(c.interceptors) {
+		return c.FunctionEvaluatorClient.EvaluateFunction(ctx, in, opts...)
+	}
+	return c.interceptors[i].Intercept(ctx, in, func() (*evaluator.EvaluateFunctionResponse, error) {
+		return c.call(ctx, in, i+1, opts)
+	})
+}
+
+// NewLoggingInterceptor returns an interceptor which logs every function
+// evaluation with its duration and outcome.
+func NewLoggingInterceptor() FunctionEvaluatorInterceptor {
+	return FunctionEvaluatorInterceptorFunc(func(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error) {
+		start := time.Now()
+		res, err := next()
+		if err != nil {
+			klog.Warningf("Evaluation of function %q failed after %s: %v", req.Image, time.Since(start), err)
+		} else {
+			klog.Infof("Evaluated function %q in %s (%d bytes in, %d bytes out)", req.Image, time.Since(start), len(req.ResourceList), len(res.ResourceList))
+		}
+		return res, err
+	})
+}
+
+// NewMetricsInterceptor returns an interceptor which records the number and
+// latency of function evaluations, by image and result, with the global
+// OpenTelemetry meter provider.
+func NewMetricsInterceptor() FunctionEvaluatorInterceptor {
+	meter := metric.Must(global.Meter("porch/engine"))
+	evaluations := meter.NewInt64Counter("porch.function.evaluations",
+		metric.WithDescription("Number of function evaluations"))
+	latency := meter.NewFloat64ValueRecorder("porch.function.evaluation.duration",
+		metric.WithDescription("Duration of function evaluations"),
+		metric.WithUnit("s"))
+
+	return FunctionEvaluatorInterceptorFunc(func(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error) {
+		start := time.Now()
+		res, err := next()
+		labels := []attribute.KeyValue{
+			attribute.String("image", req.Image),
+			attribute.String("code", status.Code(err).String()),
+		}
+		evaluations.Add(ctx, 1, labels...)
+		latency.Record(ctx, time.Since(start).Seconds(), labels...)
+		return res, err
+	})
+}
+
+// NewCachingInterceptor returns an interceptor which caches the responses of
+// successful function evaluations, keyed by the image and the input resource
+// list. At most size responses are cached; the least recently used response is
+// evicted first. Functions must be deterministic for their output to be
+// cached.
+func NewCachingInterceptor(size int) FunctionEvaluatorInterceptor {
+	return &cachingInterceptor{
+		size:    size,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+type cachingInterceptor struct {
+	size int
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // of *cacheEntry, most recently used first
+}
+
+type cacheEntry struct {
+	key      string
+	response *evaluator.EvaluateFunctionResponse
+}
+
+var _ FunctionEvaluatorInterceptor = &cachingInterceptor{}
+
+func (c *cachingInterceptor) Intercept(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error) {
+	if c.size <= 0 {
+		return next()
+	}
+
+	key := cacheKey(req)
+	if res, found := c.get(key); found {
+		klog.V(4).Infof("Using cached evaluation of function %q", req.Image)
+		return res, nil
+	}
+
+	res, err := next()
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, res)
+	return res, nil
+}
+
+func (c *cachingInterceptor) get(key string) (*evaluator.EvaluateFunctionResponse, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*cacheEntry).response, true
+}
+
+func (c *cachingInterceptor) add(key string, res *evaluator.EvaluateFunctionResponse) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, found := c.entries[key]; found {
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, response: res})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func cacheKey(req *evaluator.EvaluateFunctionRequest) string {
+	h := sha256.New()
+	h.Write([]byte(req.Image))
+	h.Write([]byte{0})
+	h.Write(req.ResourceList)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NewRateLimitingInterceptor returns an interceptor which limits function
+// evaluations to qps per second, with bursts of up to burst evaluations.
+// Evaluations over the limit wait for their turn, or fail with
+// ResourceExhausted if the context deadline would expire first.
+func NewRateLimitingInterceptor(qps float64, burst int) FunctionEvaluatorInterceptor {
+	limiter := rate.NewLimiter(rate.Limit(qps), burst)
+	return FunctionEvaluatorInterceptorFunc(func(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error) {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, status.Error(codes.ResourceExhausted, fmt.Sprintf("function evaluation rate limit exceeded: %v", err))
+		}
+		return next()
+	})
+}
+
+const (
+	// DefaultFunctionMaxRetries is the default number of times the retry
+	// interceptor retries an evaluation.
+	DefaultFunctionMaxRetries = 3
+	// DefaultFunctionRetryBackoff is the default delay before the first retry.
+	DefaultFunctionRetryBackoff = 500 * time.Millisecond
+
+	// maxRetryBackoff caps the delay between retries of the retry interceptor.
+	maxRetryBackoff = 30 * time.Second
+)
+
+// evaluateFunctionMethod is the gRPC method retried by the retry interceptor.
+const evaluateFunctionMethod = "/evaluator.FunctionEvaluator/EvaluateFunction"
+
+// NewRetryInterceptor returns an interceptor which retries function
+// evaluations failing with Unavailable, such as while the function runner is
+// restarting, up to maxRetries times. The delay before the first retry is
+// backoff and doubles with every further retry. Evaluations failing with any
+// other code are not retried.
+//
+// The retries are made by the grpc_retry client interceptor, adapted to
+// intercept the evaluation rather than the gRPC call, so that the other
+// interceptors of the chain can observe every attempt.
+func NewRetryInterceptor(maxRetries int, backoff time.Duration) FunctionEvaluatorInterceptor {
+	retry := grpc_retry.UnaryClientInterceptor(
+		// The first attempt counts towards the maximum.
+		grpc_retry.WithMax(uint(maxRetries)+1),
+		grpc_retry.WithCodes(codes.Unavailable),
+		grpc_retry.WithBackoff(retryBackoff(backoff)),
+	)
+	return FunctionEvaluatorInterceptorFunc(func(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error) {
+		var res *evaluator.EvaluateFunctionResponse
+		attempt := 0
+		err := retry(ctx, evaluateFunctionMethod, req, nil, nil, func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+			if attempt > 0 {
+				klog.Warningf("Function runner unavailable evaluating %q, retrying (%d/%d)", req.Image, attempt, maxRetries)
+			}
+			attempt++
+			var err error
+			res, err = next()
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	})
+}
+
+// retryBackoff returns the delay before each retry: backoff before the first
+// retry, doubling with every further retry up to maxRetryBackoff.
+func retryBackoff(backoff time.Duration) grpc_retry.BackoffFunc {
+	exponential := grpc_retry.BackoffExponential(backoff)
+	return func(attempt uint) time.Duration {
+		if delay := exponential(attempt); delay > 0 && delay < maxRetryBackoff {
+			return delay
+		}
+		return maxRetryBackoff
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type countingEvaluator struct {
+	evaluator.FunctionEvaluatorClient
+	calls int
+}
+
+var _ evaluator.FunctionEvaluatorClient = &countingEvaluator{}
+
+func (e *countingEvaluator) EvaluateFunction(ctx context.Context, in *evaluator.EvaluateFunctionRequest, opts ...grpc.CallOption) (*evaluator.EvaluateFunctionResponse, error) {
+	e.calls++
+	return &evaluator.EvaluateFunctionResponse{ResourceList: append([]byte(in.Image+":"), in.ResourceList...)}, nil
+}
+
+func TestChainedFunctionEvaluatorOrder(t *testing.T) {
+	var order []string
+	record := func(name string) FunctionEvaluatorInterceptor {
+		return FunctionEvaluatorInterceptorFunc(func(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error) {
+			order = append(order, name+":before")
+			res, err := next()
+			order = append(order, name+":after")
+			return res, err
+		})
+	}
+
+	inner := &countingEvaluator{}
+	client := ChainedFunctionEvaluator(inner, record("first"), record("second"))
+	res, err := client.EvaluateFunction(context.Background(), &evaluator.EvaluateFunctionRequest{Image: "fn", ResourceList: []byte("in")})
+	if err != nil {
+		t.Fatalf("EvaluateFunction failed: %v", err)
+	}
+	if got, want := string(res.ResourceList), "fn:in"; got != want {
+		t.Errorf("ResourceList: got %q, want %q", got, want)
+	}
+	if got, want := order, []string{"first:before", "second:before", "second:after", "first:after"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Interceptor order: got %v, want %v", got, want)
+	}
+}
+
+func TestChainedFunctionEvaluatorShortCircuit(t *testing.T) {
+	inner := &countingEvaluator{}
+	deny := FunctionEvaluatorInterceptorFunc(func(ctx context.Context, req *evaluator.EvaluateFunctionRequest, next func() (*evaluator.EvaluateFunctionResponse, error)) (*evaluator.EvaluateFunctionResponse, error) {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	})
+	client := ChainedFunctionEvaluator(inner, deny)
+	if _, err := client.EvaluateFunction(context.Background(), &evaluator.EvaluateFunctionRequest{Image: "fn"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("EvaluateFunction: got error %v, want PermissionDenied", err)
+	}
+	if inner.calls != 0 {
+		t.Errorf("Evaluator called %d times, want 0", inner.calls)
+	}
+}
+
+func TestCachingInterceptor(t *testing.T) {
+	inner := &countingEvaluator{}
+	client := ChainedFunctionEvaluator(inner, NewCachingInterceptor(2))
+	ctx := context.Background()
+
+	evaluate := func(image, input string) {
+		t.Helper()
+		res, err := client.EvaluateFunction(ctx, &evaluator.EvaluateFunctionRequest{Image: image, ResourceList: []byte(input)})
+		if err != nil {
+			t.Fatalf("EvaluateFunction failed: %v", err)
+		}
+		if got, want := string(res.ResourceList), image+":"+input; got != want {
+			t.Errorf("ResourceList: got %q, want %q", got, want)
+		}
+	}
+
+	evaluate("a", "1")
+	evaluate("a", "1")
+	if got, want := inner.calls, 1; got != want {
+		t.Errorf("Evaluator calls after cache hit: got %d, want %d", got, want)
+	}
+	evaluate("a", "2")
+	evaluate("b", "1")
+	// "a:1" is the least recently used and has been evicted.
+	evaluate("a", "1")
+	if got, want := inner.calls, 4; got != want {
+		t.Errorf("Evaluator calls after eviction: got %d, want %d", got, want)
+	}
+}
+
+func TestRateLimitingInterceptor(t *testing.T) {
+	inner := &countingEvaluator{}
+	// A single token, replenished every 1000 seconds.
+	client := ChainedFunctionEvaluator(inner, NewRateLimitingInterceptor(0.001, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := client.EvaluateFunction(ctx, &evaluator.EvaluateFunctionRequest{Image: "fn"}); err != nil {
+		t.Fatalf("First EvaluateFunction failed: %v", err)
+	}
+	// The next token is not available before the deadline.
+	if _, err := client.EvaluateFunction(ctx, &evaluator.EvaluateFunctionRequest{Image: "fn"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("EvaluateFunction over the limit: got error %v, want ResourceExhausted", err)
+	}
+	if got, want := inner.calls, 1; got != want {
+		t.Errorf("Evaluator calls: got %d, want %d", got, want)
+	}
+}
+
+// failingEvaluator fails the first failures evaluations with code.
+type failingEvaluator struct {
+	evaluator.FunctionEvaluatorClient
+	code     codes.Code
+	failures int
+	calls    int
+}
+
+var _ evaluator.FunctionEvaluatorClient = &failingEvaluator{}
+
+func (e *failingEvaluator) EvaluateFunction(ctx context.Context, in *evaluator.EvaluateFunctionRequest, opts ...grpc.CallOption) (*evaluator.EvaluateFunctionResponse, error) {
+	e.calls++
+	if e.calls <= e.failures {
+		return nil, status.Error(e.code, "evaluation failed")
+	}
+	return &evaluator.EvaluateFunctionResponse{ResourceList: in.ResourceList}, nil
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		code      codes.Code
+		failures  int
+		wantCode  codes.Code
+		wantCalls int
+	}{
+		{name: "unavailable", code: codes.Unavailable, failures: 2, wantCode: codes.OK, wantCalls: 3},
+		{name: "retries exhausted", code: codes.Unavailable, failures: 10, wantCode: codes.Unavailable, wantCalls: 4},
+		{name: "invalid argument", code: codes.InvalidArgument, failures: 1, wantCode: codes.InvalidArgument, wantCalls: 1},
+		{name: "not found", code: codes.NotFound, failures: 1, wantCode: codes.NotFound, wantCalls: 1},
+		{name: "permission denied", code: codes.PermissionDenied, failures: 1, wantCode: codes.PermissionDenied, wantCalls: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			inner := &failingEvaluator{code: tc.code, failures: tc.failures}
+			client := ChainedFunctionEvaluator(inner, NewRetryInterceptor(3, time.Millisecond))
+			_, err := client.EvaluateFunction(context.Background(), &evaluator.EvaluateFunctionRequest{Image: "fn"})
+			if got := status.Code(err); got != tc.wantCode {
+				t.Errorf("EvaluateFunction: got code %s, want %s (error %v)", got, tc.wantCode, err)
+			}
+			if inner.calls != tc.wantCalls {
+				t.Errorf("Evaluator calls: got %d, want %d", inner.calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	backoff := retryBackoff(500 * time.Millisecond)
+	for attempt, want := range map[uint]time.Duration{
+		1:  500 * time.Millisecond,
+		2:  time.Second,
+		3:  2 * time.Second,
+		6:  16 * time.Second,
+		7:  maxRetryBackoff,
+		64: maxRetryBackoff,
+	} {
+		if got := backoff(attempt); got != want {
+			t.Errorf("backoff before attempt %d: got %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func TestRetryInterceptorCanceled(t *testing.T) {
+	inner := &failingEvaluator{code: codes.Unavailable, failures: 10}
+	client := ChainedFunctionEvaluator(inner, NewRetryInterceptor(3, time.Hour))
+
+	// The context expires while waiting for the first retry.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.EvaluateFunction(ctx, &evaluator.EvaluateFunctionRequest{Image: "fn"}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("EvaluateFunction: got error %v, want DeadlineExceeded", err)
+	}
+	if got, want := inner.calls, 1; got != want {
+		t.Errorf("Evaluator calls: got %d, want %d", got, want)
+	}
+}
+
+type echoEvaluatorServer struct {
+	evaluator.UnimplementedFunctionEvaluatorServer
+}
+
+func (s *echoEvaluatorServer) EvaluateFunction(ctx context.Context, req *evaluator.EvaluateFunctionRequest) (*evaluator.EvaluateFunctionResponse, error) {
+	return &evaluator.EvaluateFunctionResponse{ResourceList: req.ResourceList}, nil
+}
+
+func serveEvaluator(t *testing.T, lis net.Listener) *grpc.Server {
+	server := grpc.NewServer()
+	evaluator.RegisterFunctionEvaluatorServer(server, &echoEvaluatorServer{})
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			t.Logf("function evaluator server stopped: %v", err)
+		}
+	}()
+	return server
+}
+
+func TestRetryInterceptorFunctionRunnerRestart(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	address := lis.Addr().String()
+	server := serveEvaluator(t, lis)
+
+	runtime, err := newGRPCFunctionRuntime(address, NewRetryInterceptor(10, 100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("newGRPCFunctionRuntime failed: %v", err)
+	}
+	defer runtime.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	req := &evaluator.EvaluateFunctionRequest{Image: "fn", ResourceList: []byte("in")}
+	if _, err := runtime.client.EvaluateFunction(ctx, req); err != nil {
+		t.Fatalf("EvaluateFunction before restart failed: %v", err)
+	}
+
+	// Restart the function runner on the same address.
+	server.Stop()
+	restarted := make(chan *grpc.Server, 1)
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		lis, err := net.Listen("tcp", address)
+		if err != nil {
+			t.Errorf("Listen on %s failed: %v", address, err)
+			close(restarted)
+			return
+		}
+		restarted <- serveEvaluator(t, lis)
+	}()
+	defer func() {
+		if server := <-restarted; server != nil {
+			server.Stop()
+		}
+	}()
+
+	res, err := runtime.client.EvaluateFunction(ctx, req)
+	if err != nil {
+		t.Fatalf("EvaluateFunction during restart failed: %v", err)
+	}
+	if got, want := string(res.ResourceList), "in"; got != want {
+		t.Errorf("ResourceList: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"path"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// kptIgnoreFile lists patterns of package files which are not passed to
+// functions during render. Patterns apply to the files in the directory of
+// the .kptignore file and its subdirectories.
+const kptIgnoreFile = ".kptignore"
+
+// kptIgnore holds the patterns of the .kptignore files of a package.
+type kptIgnore struct {
+	// patterns maps the directory of each .kptignore file ("." for the root)
+	// to its patterns.
+	patterns map[string][]string
+}
+
+// newKptIgnore collects the patterns of the .kptignore files in resources.
+func newKptIgnore(resources map[string]string) *kptIgnore {
+	ignore := &kptIgnore{patterns: map[string][]string{}}
+	for k, v := range resources {
+		if path.Base(k) != kptIgnoreFile {
+			continue
+		}
+		ignore.patterns[path.Dir(k)] = parseKptIgnore(v)
+	}
+	return ignore
+}
+
+// parseKptIgnore returns the patterns of a .kptignore file, skipping blank
+// lines and comments starting with '#'.
+func parseKptIgnore(content string) []string {
+	var patterns []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// ignored returns true if the file matches a pattern of a .kptignore file in
+// its directory or any of its parent directories. The .kptignore files
+// themselves are always ignored.
+func (i *kptIgnore) ignored(file string) bool {
+	if path.Base(file) == kptIgnoreFile {
+		return true
+	}
+	for dir, patterns := range i.patterns {
+		rel := file
+		if dir != "." {
+			if !strings.HasPrefix(file, dir+"/") {
+				continue
+			}
+			rel = strings.TrimPrefix(file, dir+"/")
+		}
+		for _, pattern := range patterns {
+			if matchKptIgnorePattern(pattern, rel) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchKptIgnorePattern matches the file path, relative to the directory of
+// the .kptignore file, against pattern:
+//
+//   - A pattern without a slash matches the name of a file at any depth,
+//     like `*.md`.
+//   - A pattern with a slash is matched against the whole relative path;
+//     a leading slash is ignored, so `/docs/*.md` only matches files in docs.
+//   - A pattern ending with a slash matches everything under a directory,
+//     like `examples/`.
+//
+// Patterns are otherwise matched with doublestar, so `**` matches zero or
+// more directories, like `docs/**/*.png`.
+func matchKptIgnorePattern(pattern, file string) bool {
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	if directory {
+		pattern += "/**"
+	}
+	ok, err := doublestar.Match(pattern, file)
+	return err == nil && ok
+}
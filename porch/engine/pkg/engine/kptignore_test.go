@@ -0,0 +1,71 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+)
+
+func TestMatchKptIgnorePattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{pattern: "README.md", file: "README.md", want: true},
+		{pattern: "README.md", file: "docs/README.md", want: true},
+		{pattern: "*.md", file: "docs/guide/intro.md", want: true},
+		{pattern: "*.md", file: "config.yaml", want: false},
+		{pattern: "/README.md", file: "README.md", want: true},
+		{pattern: "/README.md", file: "docs/README.md", want: false},
+		{pattern: "docs/*.md", file: "docs/intro.md", want: true},
+		{pattern: "docs/*.md", file: "docs/guide/intro.md", want: false},
+		{pattern: "docs/**/*.png", file: "docs/logo.png", want: true},
+		{pattern: "docs/**/*.png", file: "docs/img/a/logo.png", want: true},
+		{pattern: "docs/**/*.png", file: "img/logo.png", want: false},
+		{pattern: "examples/", file: "examples/app/deployment.yaml", want: true},
+		{pattern: "examples/", file: "pkg/examples/service.yaml", want: true},
+		{pattern: "examples/", file: "examples.yaml", want: false},
+		{pattern: "*.{md,txt}", file: "docs/notes.txt", want: true},
+		{pattern: "[", file: "[", want: false},
+	} {
+		if got := matchKptIgnorePattern(tc.pattern, tc.file); got != tc.want {
+			t.Errorf("matchKptIgnorePattern(%q, %q) = %t; want %t", tc.pattern, tc.file, got, tc.want)
+		}
+	}
+}
+
+func TestKptIgnore(t *testing.T) {
+	ignore := newKptIgnore(map[string]string{
+		".kptignore":     "# Documentation\nREADME.md\n\n",
+		"sub/.kptignore": "*.txt\n",
+	})
+
+	for file, want := range map[string]bool{
+		".kptignore":        true,
+		"sub/.kptignore":    true,
+		"README.md":         true,
+		"sub/README.md":     true,
+		"sub/notes.txt":     true,
+		"notes.txt":         false,
+		"other/notes.txt":   false,
+		"config-map.yaml":   false,
+		"sub/resource.yaml": false,
+	} {
+		if got := ignore.ignored(file); got != want {
+			t.Errorf("ignored(%q) = %t; want %t", file, got, want)
+		}
+	}
+}
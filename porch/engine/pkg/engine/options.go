@@ -56,9 +56,11 @@ func WithBuiltinFunctionRuntime() EngineOption {
 	})
 }
 
-func WithGRPCFunctionRuntime(address string) EngineOption {
+// WithGRPCFunctionRuntime evaluates functions with the function runner at the
+// address. Evaluations pass through the interceptors, outermost first.
+func WithGRPCFunctionRuntime(address string, interceptors ...FunctionEvaluatorInterceptor) EngineOption {
 	return EngineOptionFunc(func(engine *cadEngine) error {
-		runtime, err := newGRPCFunctionRuntime(address)
+		runtime, err := newGRPCFunctionRuntime(address, interceptors...)
 		if err != nil {
 			return fmt.Errorf("failed to create function runtime: %w", err)
 		}
@@ -114,3 +116,24 @@ func WithUserInfoProvider(provider repository.UserInfoProvider) EngineOption {
 		return nil
 	})
 }
+
+// WithMaxFunctionInvocations limits the number of functions invoked while
+// rendering a single package revision. Zero disables the limit.
+func WithMaxFunctionInvocations(max int) EngineOption {
+	return EngineOptionFunc(func(engine *cadEngine) error {
+		if max < 0 {
+			return fmt.Errorf("invalid maximum function invocation count %d", max)
+		}
+		engine.maxFunctionInvocations = max
+		return nil
+	})
+}
+
+// WithFunctionDigestResolver sets the resolver used to record the digests of
+// the functions evaluated when rendering a package revision.
+func WithFunctionDigestResolver(resolver FunctionDigestResolver) EngineOption {
+	return EngineOptionFunc(func(engine *cadEngine) error {
+		engine.functionDigestResolver = resolver
+		return nil
+	})
+}
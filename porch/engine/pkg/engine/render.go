@@ -16,20 +16,62 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	iofs "io/fs"
 	"path"
 	"strings"
+	"sync"
+	"time"
 
+	v1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/fn"
 	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
+// fnSkipAnnotation marks package files which are not passed to functions
+// during render. The annotation is read from the YAML front-matter of the
+// file (the first YAML document), so it can be used on non-KRM files too.
+const fnSkipAnnotation = "kpt.dev/fn-skip"
+
+// ErrMaxFunctionInvocationsExceeded is returned when rendering a package
+// would invoke more functions than the engine allows.
+var ErrMaxFunctionInvocationsExceeded = errors.New("maximum function invocation count exceeded")
+
+// RenderError is returned when the functions of a package fail to render it.
+type RenderError struct {
+	Err error
+}
+
+func (e *RenderError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// FunctionDigestResolver resolves function images to the digests of their contents.
+type FunctionDigestResolver interface {
+	ResolveDigest(ctx context.Context, image string) (string, error)
+}
+
 type renderPackageMutation struct {
 	renderer fn.Renderer
 	runtime  fn.FunctionRuntime
+	// maxFunctionInvocations limits the number of function calls made
+	// during a single render. Zero means no limit.
+	maxFunctionInvocations int
+	// digestResolver resolves digests of the rendered functions. Optional.
+	digestResolver FunctionDigestResolver
+
+	// status is the status of the last successful render, if any.
+	status *repository.RenderStatus
 }
 
 var _ mutation = &renderPackageMutation{}
@@ -37,6 +79,9 @@ var _ mutation = &renderPackageMutation{}
 func (m *renderPackageMutation) Apply(ctx context.Context, resources repository.PackageResources) (repository.PackageResources, *api.Task, error) {
 	fs := filesys.MakeFsInMemory()
 
+	locks := readImageLocks(resources.Contents)
+	resources, skipped := splitSkippedResources(resources)
+
 	pkgPath, err := writeResources(fs, resources)
 	if err != nil {
 		return repository.PackageResources{}, nil, err
@@ -47,11 +92,28 @@ func (m *renderPackageMutation) Apply(ctx context.Context, resources repository.
 		// TODO: we should handle this better
 		klog.Warningf("skipping render as no package was found")
 	} else {
+		runtime := m.runtime
+		if len(locks) > 0 {
+			runtime = &lockingFunctionRuntime{runtime: m.runtime, locks: locks}
+		}
+		counter := &countingFunctionRuntime{
+			runtime: runtime,
+			max:     m.maxFunctionInvocations,
+			images:  map[string]bool{},
+		}
 		if err := m.renderer.Render(ctx, fs, fn.RenderOptions{
 			PkgPath: pkgPath,
-			Runtime: m.runtime,
+			Runtime: counter,
 		}); err != nil {
-			return repository.PackageResources{}, nil, err
+			// The renderer does not preserve the error chain, so report the limit explicitly.
+			if counter.exceeded() {
+				return repository.PackageResources{}, nil, &RenderError{Err: fmt.Errorf("%w (limit %d)", ErrMaxFunctionInvocationsExceeded, m.maxFunctionInvocations)}
+			}
+			return repository.PackageResources{}, nil, &RenderError{Err: err}
+		}
+		m.status = &repository.RenderStatus{
+			RenderedAt:      time.Now(),
+			FunctionDigests: m.resolveDigests(ctx, counter.images, locks),
 		}
 	}
 
@@ -59,6 +121,18 @@ func (m *renderPackageMutation) Apply(ctx context.Context, resources repository.
 	if err != nil {
 		return repository.PackageResources{}, nil, err
 	}
+	// Lock the functions rendered by their tags to the resolved digests.
+	if pkgPath != "" && len(locks) > 0 && recordImageLocks(locks, m.status.FunctionDigests) {
+		if err := setImageLocks(pkgPath, result.Contents, locks); err != nil {
+			return repository.PackageResources{}, nil, err
+		}
+	}
+	// Functions may emit resources in arbitrary order; normalize it so
+	// rendering the same package yields the same commit.
+	result.Contents = NormalizeFileOrder(result.Contents)
+	for k, v := range skipped {
+		result.Contents[k] = v
+	}
 
 	// TODO: There are internal tasks not represented in the API; Update the Apply interface to enable them.
 	return result, &api.Task{
@@ -117,3 +191,113 @@ func readResources(fs filesys.FileSystem) (repository.PackageResources, error) {
 		Contents: contents,
 	}, nil
 }
+
+// splitSkippedResources separates the files annotated with fnSkipAnnotation
+// or matching a pattern of a .kptignore file from the rest of the package
+// resources.
+func splitSkippedResources(resources repository.PackageResources) (repository.PackageResources, map[string]string) {
+	contents := map[string]string{}
+	skipped := map[string]string{}
+	ignore := newKptIgnore(resources.Contents)
+	for k, v := range resources.Contents {
+		if hasFnSkipAnnotation(v) {
+			klog.V(3).Infof("skipping %q during render; annotated with %s", k, fnSkipAnnotation)
+			skipped[k] = v
+		} else if ignore.ignored(k) {
+			klog.V(3).Infof("skipping %q during render; listed in %s", k, kptIgnoreFile)
+			skipped[k] = v
+		} else {
+			contents[k] = v
+		}
+	}
+	return repository.PackageResources{Contents: contents}, skipped
+}
+
+func hasFnSkipAnnotation(content string) bool {
+	frontMatter := strings.TrimPrefix(content, "---\n")
+	if i := strings.Index(frontMatter, "\n---"); i >= 0 {
+		frontMatter = frontMatter[:i]
+	}
+	node, err := yaml.Parse(frontMatter)
+	if err != nil || node.YNode().Kind != yaml.MappingNode {
+		return false
+	}
+	return node.GetAnnotations()[fnSkipAnnotation] == "true"
+}
+
+// resolveDigests resolves the digests of the function images. Images with a
+// lock resolve to the locked digest. Digests which cannot be resolved are
+// recorded as empty.
+func (m *renderPackageMutation) resolveDigests(ctx context.Context, images map[string]bool, locks map[string]string) map[string]string {
+	digests := map[string]string{}
+	for image := range images {
+		digests[image] = locks[image]
+		if digests[image] != "" || m.digestResolver == nil {
+			continue
+		}
+		digest, err := m.digestResolver.ResolveDigest(ctx, image)
+		if err != nil {
+			klog.Warningf("cannot resolve digest of function %q: %v", image, err)
+			continue
+		}
+		digests[image] = digest
+	}
+	return digests
+}
+
+// countingFunctionRuntime wraps a function runtime, records the images of the
+// functions it provides and fails function calls once more than max functions
+// were invoked. Zero max means no limit.
+type countingFunctionRuntime struct {
+	runtime fn.FunctionRuntime
+	max     int
+
+	mutex  sync.Mutex
+	count  int
+	images map[string]bool
+}
+
+var _ fn.FunctionRuntime = &countingFunctionRuntime{}
+
+func (c *countingFunctionRuntime) GetRunner(ctx context.Context, function *v1.Function) (fn.FunctionRunner, error) {
+	runner, err := c.runtime.GetRunner(ctx, function)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.images[function.Image] = true
+	c.mutex.Unlock()
+
+	return &countingFunctionRunner{runner: runner, counter: c}, nil
+}
+
+// increment records a function invocation and reports whether it is within the limit.
+func (c *countingFunctionRuntime) increment() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.count++
+	return c.max == 0 || c.count <= c.max
+}
+
+func (c *countingFunctionRuntime) exceeded() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.max > 0 && c.count > c.max
+}
+
+type countingFunctionRunner struct {
+	runner  fn.FunctionRunner
+	counter *countingFunctionRuntime
+}
+
+var _ fn.FunctionRunner = &countingFunctionRunner{}
+
+func (r *countingFunctionRunner) Run(in io.Reader, out io.Writer) error {
+	if !r.counter.increment() {
+		return ErrMaxFunctionInvocationsExceeded
+	}
+	return r.runner.Run(in, out)
+}
@@ -16,11 +16,17 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	v1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/fn"
 	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/kpt"
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
 	"github.com/google/go-cmp/cmp"
@@ -75,3 +81,162 @@ func TestRender(t *testing.T) {
 		t.Errorf("Unexpected result (-want, +got): %s", diff)
 	}
 }
+
+// deletingRenderer simulates a pipeline whose functions delete every file except the Kptfile.
+type deletingRenderer struct{}
+
+func (deletingRenderer) Render(ctx context.Context, fs filesys.FileSystem, opts fn.RenderOptions) error {
+	var files []string
+	if err := fs.Walk(opts.PkgPath, func(path string, info iofs.FileInfo, err error) error {
+		// The in-memory file system reports directories as regular files.
+		if !fs.IsDir(path) && filepath.Base(path) != v1.KptFileName {
+			files = append(files, path)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := fs.RemoveAll(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRenderSkipsAnnotatedFiles(t *testing.T) {
+	render := &renderPackageMutation{
+		renderer: deletingRenderer{},
+		runtime:  kpt.NewSimpleFunctionRuntime(),
+	}
+
+	const (
+		readme = `---
+metadata:
+  annotations:
+    kpt.dev/fn-skip: "true"
+---
+# Package documentation
+`
+		skipped = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: testdata
+  annotations:
+    kpt.dev/fn-skip: "true"
+`
+		deleted = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: deleted
+`
+	)
+
+	rendered, _, err := render.Apply(context.Background(), repository.PackageResources{
+		Contents: map[string]string{
+			"Kptfile":               "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: pkg\n",
+			"README.md":             readme,
+			"testdata/config.yaml":  skipped,
+			"deleted-resource.yaml": deleted,
+		},
+	})
+	if err != nil {
+		t.Fatalf("package render failed: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"README.md":            readme,
+		"testdata/config.yaml": skipped,
+	} {
+		if got := rendered.Contents[name]; got != want {
+			t.Errorf("%s: unexpected content after render (-want, +got): %s", name, cmp.Diff(want, got))
+		}
+	}
+	if _, found := rendered.Contents["deleted-resource.yaml"]; found {
+		t.Errorf("deleted-resource.yaml was expected to be removed by render")
+	}
+}
+
+func TestRenderSkipsKptIgnoredFiles(t *testing.T) {
+	render := &renderPackageMutation{
+		renderer: deletingRenderer{},
+		runtime:  kpt.NewSimpleFunctionRuntime(),
+	}
+
+	const (
+		kptignore = "README.md\n"
+		readme    = "# Package documentation\n"
+	)
+
+	rendered, _, err := render.Apply(context.Background(), repository.PackageResources{
+		Contents: map[string]string{
+			"Kptfile":    "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: pkg\n",
+			".kptignore": kptignore,
+			"README.md":  readme,
+			"NOTES.txt":  "deleted by render\n",
+		},
+	})
+	if err != nil {
+		t.Fatalf("package render failed: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		".kptignore": kptignore,
+		"README.md":  readme,
+	} {
+		if got := rendered.Contents[name]; got != want {
+			t.Errorf("%s: unexpected content after render (-want, +got): %s", name, cmp.Diff(want, got))
+		}
+	}
+	if _, found := rendered.Contents["NOTES.txt"]; found {
+		t.Errorf("NOTES.txt was expected to be removed by render")
+	}
+}
+
+// passthroughRuntime provides runners which return their input unchanged.
+type passthroughRuntime struct{}
+
+func (passthroughRuntime) GetRunner(ctx context.Context, function *v1.Function) (fn.FunctionRunner, error) {
+	return passthroughRunner{}, nil
+}
+
+type passthroughRunner struct{}
+
+func (passthroughRunner) Run(r io.Reader, w io.Writer) error {
+	_, err := io.Copy(w, r)
+	return err
+}
+
+func TestRenderMaxFunctionInvocations(t *testing.T) {
+	render := &renderPackageMutation{
+		renderer:               kpt.NewRenderer(),
+		runtime:                passthroughRuntime{},
+		maxFunctionInvocations: 100,
+	}
+
+	var kptfile strings.Builder
+	kptfile.WriteString("apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: pkg\npipeline:\n  mutators:\n")
+	for i := 0; i < 101; i++ {
+		fmt.Fprintf(&kptfile, "  - image: example.com/fn-%d:v1\n", i)
+	}
+
+	_, _, err := render.Apply(context.Background(), repository.PackageResources{
+		Contents: map[string]string{
+			"Kptfile":        kptfile.String(),
+			"configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+		},
+	})
+	if err == nil {
+		t.Fatalf("render with 101 functions succeeded; expected invocation limit error")
+	}
+	if !errors.Is(err, ErrMaxFunctionInvocationsExceeded) {
+		t.Errorf("unexpected render error: %v", err)
+	}
+	var renderErr *RenderError
+	if !errors.As(err, &renderErr) {
+		t.Errorf("render error %T is not a *RenderError", err)
+	}
+	if got, want := err.Error(), "maximum function invocation count exceeded"; !strings.Contains(got, want) {
+		t.Errorf("render error %q does not contain %q", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/kpt"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+)
+
+// effectiveRenderPolicy returns the render policy which applies to a package
+// revision with the given policy, OnPropose if none is set.
+func effectiveRenderPolicy(policy api.RenderPolicy) api.RenderPolicy {
+	if policy == "" {
+		return api.RenderPolicyOnPropose
+	}
+	return policy
+}
+
+// renderOnChange returns true if changes to the draft of a package revision
+// with the given render policy are rendered.
+func renderOnChange(policy api.RenderPolicy) bool {
+	return effectiveRenderPolicy(policy) == api.RenderPolicyOnPropose
+}
+
+// renderOnApproval returns true if a package revision with the given render
+// policy is rendered when it moves from oldLifecycle to newLifecycle.
+func renderOnApproval(policy api.RenderPolicy, oldLifecycle, newLifecycle api.PackageRevisionLifecycle) bool {
+	return effectiveRenderPolicy(policy) == api.RenderPolicyOnApprove &&
+		oldLifecycle != api.PackageRevisionLifecyclePublished &&
+		newLifecycle == api.PackageRevisionLifecyclePublished
+}
+
+// renderPolicyMutation records the render policy of a package revision in
+// the annotations of its Kptfile.
+type renderPolicyMutation struct {
+	name   string
+	policy api.RenderPolicy
+}
+
+var _ mutation = &renderPolicyMutation{}
+
+func (m *renderPolicyMutation) Apply(ctx context.Context, resources repository.PackageResources) (repository.PackageResources, *api.Task, error) {
+	contents := make(map[string]string, len(resources.Contents))
+	for k, v := range resources.Contents {
+		contents[k] = v
+	}
+	if err := kpt.UpdateKptfileAnnotations(m.name, contents, func(annotations map[string]string) error {
+		annotations[api.RenderPolicyAnnotation] = string(m.policy)
+		return nil
+	}); err != nil {
+		return repository.PackageResources{}, nil, err
+	}
+
+	return repository.PackageResources{Contents: contents}, &api.Task{
+		Type: api.TaskTypePatch,
+		Patch: &api.PackagePatchTaskSpec{
+			Patches: []string{kptfilev1.KptFileName},
+		},
+	}, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+)
+
+func TestRenderPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy         api.RenderPolicy
+		wantOnChange   bool
+		wantOnApproval bool
+	}{
+		{policy: "", wantOnChange: true, wantOnApproval: false},
+		{policy: api.RenderPolicyOnPropose, wantOnChange: true, wantOnApproval: false},
+		{policy: api.RenderPolicyOnApprove, wantOnChange: false, wantOnApproval: true},
+		{policy: api.RenderPolicyManual, wantOnChange: false, wantOnApproval: false},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			if got := renderOnChange(tc.policy); got != tc.wantOnChange {
+				t.Errorf("renderOnChange(%q) = %t; want %t", tc.policy, got, tc.wantOnChange)
+			}
+			if got := renderOnApproval(tc.policy, api.PackageRevisionLifecycleProposed, api.PackageRevisionLifecyclePublished); got != tc.wantOnApproval {
+				t.Errorf("renderOnApproval(%q, Proposed, Published) = %t; want %t", tc.policy, got, tc.wantOnApproval)
+			}
+			if renderOnApproval(tc.policy, api.PackageRevisionLifecycleDraft, api.PackageRevisionLifecycleProposed) {
+				t.Errorf("renderOnApproval(%q, Draft, Proposed) = true; want false", tc.policy)
+			}
+		})
+	}
+}
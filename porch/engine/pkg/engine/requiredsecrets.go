@@ -0,0 +1,86 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/kpt"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+)
+
+// hasSecretRequirements returns true if the spec declares required secrets
+// or a target cluster.
+func hasSecretRequirements(spec *api.PackageRevisionSpec) bool {
+	return len(spec.RequiredSecrets) > 0 || spec.Target != nil
+}
+
+// secretRequirementsChanged returns true if the required secrets or the
+// target cluster differ between the two specs.
+func secretRequirementsChanged(oldSpec, newSpec *api.PackageRevisionSpec) bool {
+	if !hasSecretRequirements(oldSpec) && !hasSecretRequirements(newSpec) {
+		return false
+	}
+	return !reflect.DeepEqual(oldSpec.RequiredSecrets, newSpec.RequiredSecrets) ||
+		!reflect.DeepEqual(oldSpec.Target, newSpec.Target)
+}
+
+// requiredSecretsMutation records the required secrets and the target
+// cluster of a package revision in the annotations of its Kptfile.
+type requiredSecretsMutation struct {
+	name    string
+	secrets []api.RequiredSecret
+	target  *api.PackageRevisionTarget
+}
+
+var _ mutation = &requiredSecretsMutation{}
+
+func (m *requiredSecretsMutation) Apply(ctx context.Context, resources repository.PackageResources) (repository.PackageResources, *api.Task, error) {
+	contents := make(map[string]string, len(resources.Contents))
+	for k, v := range resources.Contents {
+		contents[k] = v
+	}
+	if err := kpt.UpdateKptfileAnnotations(m.name, contents, func(annotations map[string]string) error {
+		if len(m.secrets) == 0 {
+			delete(annotations, api.RequiredSecretsAnnotation)
+		} else {
+			b, err := json.Marshal(m.secrets)
+			if err != nil {
+				return fmt.Errorf("cannot encode required secrets: %w", err)
+			}
+			annotations[api.RequiredSecretsAnnotation] = string(b)
+		}
+		if m.target == nil || m.target.Kubeconfig == nil {
+			delete(annotations, api.TargetKubeconfigAnnotation)
+		} else {
+			annotations[api.TargetKubeconfigAnnotation] = m.target.Kubeconfig.Name
+		}
+		return nil
+	}); err != nil {
+		return repository.PackageResources{}, nil, err
+	}
+
+	return repository.PackageResources{Contents: contents}, &api.Task{
+		Type: api.TaskTypePatch,
+		Patch: &api.PackagePatchTaskSpec{
+			Patches: []string{kptfilev1.KptFileName},
+		},
+	}, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	internalpkg "github.com/GoogleContainerTools/kpt/internal/pkg"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRequiredSecretsMutation(t *testing.T) {
+	secrets := []api.RequiredSecret{
+		{Name: "db-credentials", Namespace: "app", Keys: []string{"username", "password"}},
+		{Name: "tls"},
+	}
+	resources := repository.PackageResources{
+		Contents: map[string]string{
+			kptfilev1.KptFileName: "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: app\n",
+		},
+	}
+
+	m := &requiredSecretsMutation{
+		name:    "app",
+		secrets: secrets,
+		target:  &api.PackageRevisionTarget{Kubeconfig: &api.SecretRef{Name: "prod-kubeconfig"}},
+	}
+	got, task, err := m.Apply(context.Background(), resources)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if task == nil || task.Type != api.TaskTypePatch {
+		t.Errorf("Apply returned task %v; want patch task", task)
+	}
+	annotations := kptfileAnnotations(t, got)
+	var recorded []api.RequiredSecret
+	if err := json.Unmarshal([]byte(annotations[api.RequiredSecretsAnnotation]), &recorded); err != nil {
+		t.Fatalf("cannot decode %s annotation: %v", api.RequiredSecretsAnnotation, err)
+	}
+	if diff := cmp.Diff(secrets, recorded); diff != "" {
+		t.Errorf("recorded required secrets (-want, +got): %s", diff)
+	}
+	if got, want := annotations[api.TargetKubeconfigAnnotation], "prod-kubeconfig"; got != want {
+		t.Errorf("%s annotation = %q; want %q", api.TargetKubeconfigAnnotation, got, want)
+	}
+
+	// Clearing the requirements removes the annotations.
+	cleared, _, err := (&requiredSecretsMutation{name: "app"}).Apply(context.Background(), got)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	annotations = kptfileAnnotations(t, cleared)
+	for _, key := range []string{api.RequiredSecretsAnnotation, api.TargetKubeconfigAnnotation} {
+		if value, ok := annotations[key]; ok {
+			t.Errorf("%s annotation = %q; want none", key, value)
+		}
+	}
+}
+
+func TestSecretRequirementsChanged(t *testing.T) {
+	secrets := []api.RequiredSecret{{Name: "tls"}}
+	target := &api.PackageRevisionTarget{Kubeconfig: &api.SecretRef{Name: "prod-kubeconfig"}}
+
+	for _, tc := range []struct {
+		name     string
+		old, new api.PackageRevisionSpec
+		want     bool
+	}{
+		{name: "none", want: false},
+		{name: "empty list", new: api.PackageRevisionSpec{RequiredSecrets: []api.RequiredSecret{}}, want: false},
+		{name: "unchanged", old: api.PackageRevisionSpec{RequiredSecrets: secrets, Target: target}, new: api.PackageRevisionSpec{RequiredSecrets: secrets, Target: target}, want: false},
+		{name: "added", new: api.PackageRevisionSpec{RequiredSecrets: secrets}, want: true},
+		{name: "removed", old: api.PackageRevisionSpec{RequiredSecrets: secrets}, want: true},
+		{name: "target", old: api.PackageRevisionSpec{RequiredSecrets: secrets}, new: api.PackageRevisionSpec{RequiredSecrets: secrets, Target: target}, want: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := secretRequirementsChanged(&tc.old, &tc.new); got != tc.want {
+				t.Errorf("secretRequirementsChanged() = %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func kptfileAnnotations(t *testing.T, resources repository.PackageResources) map[string]string {
+	t.Helper()
+	kf, err := internalpkg.DecodeKptfile(strings.NewReader(resources.Contents[kptfilev1.KptFileName]))
+	if err != nil {
+		t.Fatalf("cannot parse Kptfile: %v", err)
+	}
+	return kf.Annotations
+}
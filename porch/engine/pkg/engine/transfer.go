@@ -0,0 +1,106 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/engine/pkg/kpt"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// TransferPackageRevision moves the package revision to the target
+// repository. A draft with the same package name, revision and resources,
+// including the upstream lock recorded in the Kptfile, is created in the
+// target repository and the source package revision is deleted. The Kptfile
+// of the new package revision records the source in the
+// kpt.dev/transferred-from annotation. If the source cannot be deleted, the
+// new package revision is deleted again so that the package exists in exactly
+// one of the repositories.
+func (cad *cadEngine) TransferPackageRevision(ctx context.Context, sourceRepositoryObj, targetRepositoryObj *configapi.Repository, oldPackage repository.PackageRevision) (repository.PackageRevision, error) {
+	rev, err := oldPackage.GetPackageRevision()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package revision: %w", err)
+	}
+	old, err := oldPackage.GetResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get package resources: %w", err)
+	}
+
+	contents := make(map[string]string, len(old.Spec.Resources))
+	for k, v := range old.Spec.Resources {
+		contents[k] = v
+	}
+	if err := kpt.UpdateKptfileAnnotations(rev.Spec.PackageName, contents, func(annotations map[string]string) error {
+		annotations[api.TransferredFromAnnotation] = rev.Name
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	targetRepo, err := cad.cache.OpenRepository(ctx, targetRepositoryObj)
+	if err != nil {
+		return nil, err
+	}
+	draft, err := targetRepo.CreatePackageRevision(ctx, &api.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: rev.Namespace,
+		},
+		Spec: api.PackageRevisionSpec{
+			PackageName:    rev.Spec.PackageName,
+			Revision:       rev.Spec.Revision,
+			RepositoryName: targetRepositoryObj.Name,
+			Lifecycle:      api.PackageRevisionLifecycleDraft,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The resources were rendered in the source repository already.
+	mutations := []mutation{
+		&mutationReplaceResources{
+			newResources: &api.PackageRevisionResources{
+				Spec: api.PackageRevisionResourcesSpec{
+					Resources: contents,
+				},
+			},
+		},
+	}
+	if err := applyResourceMutations(ctx, draft, repository.PackageResources{}, mutations); err != nil {
+		return nil, err
+	}
+	if err := draft.UpdateLifecycle(ctx, api.PackageRevisionLifecycleDraft); err != nil {
+		return nil, err
+	}
+	transferred, err := draft.Close(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cad.DeletePackageRevision(ctx, sourceRepositoryObj, oldPackage); err != nil {
+		if rollbackErr := targetRepo.DeletePackageRevision(ctx, transferred); rollbackErr != nil {
+			klog.Errorf("failed to delete package revision %q after failed transfer: %v", transferred.Name(), rollbackErr)
+		}
+		return nil, fmt.Errorf("cannot delete source package revision: %w", err)
+	}
+
+	return transferred, nil
+}
@@ -59,3 +59,29 @@ func UpdateKptfileUpstream(name string, contents map[string]string, upstream kpt
 	contents[kptfilev1.KptFileName] = kptfile
 	return nil
 }
+
+// UpdateKptfileAnnotations applies update to the annotations of the package Kptfile.
+func UpdateKptfileAnnotations(name string, contents map[string]string, update func(annotations map[string]string) error) error {
+	kptfileContents, found := contents[kptfilev1.KptFileName]
+	if !found {
+		return fmt.Errorf("package %q is missing Kptfile", name)
+	}
+
+	kptfile, err := internalpkg.DecodeKptfile(strings.NewReader(kptfileContents))
+	if err != nil {
+		return fmt.Errorf("cannot parse Kptfile: %w", err)
+	}
+	if kptfile.Annotations == nil {
+		kptfile.Annotations = map[string]string{}
+	}
+	if err := update(kptfile.Annotations); err != nil {
+		return err
+	}
+
+	b, err := yaml.MarshalWithOptions(kptfile, &yaml.EncoderOptions{SeqIndent: yaml.WideSequenceStyle})
+	if err != nil {
+		return fmt.Errorf("cannot save Kptfile: %w", err)
+	}
+	contents[kptfilev1.KptFileName] = string(b)
+	return nil
+}
@@ -24,6 +24,16 @@ const _ = grpc.SupportPackageIsVersion7
 type FunctionEvaluatorClient interface {
 	// Evaluates a kpt function on the provided package
 	EvaluateFunction(ctx context.Context, in *EvaluateFunctionRequest, opts ...grpc.CallOption) (*EvaluateFunctionResponse, error)
+	// Starts evaluating a kpt function and returns without waiting for the
+	// function to complete
+	AsyncEvaluateFunction(ctx context.Context, in *EvaluateFunctionRequest, opts ...grpc.CallOption) (*AsyncEvaluationJob, error)
+	// Returns the status of an evaluation started by AsyncEvaluateFunction
+	GetEvaluationStatus(ctx context.Context, in *GetEvaluationStatusRequest, opts ...grpc.CallOption) (*EvaluationStatus, error)
+	// Cancels an evaluation started by AsyncEvaluateFunction
+	CancelEvaluation(ctx context.Context, in *CancelEvaluationRequest, opts ...grpc.CallOption) (*CancelEvaluationResponse, error)
+	// Evaluates a kpt function on the provided package, streaming the lines of
+	// the function log as they are written. The last event holds the response.
+	EvaluateFunctionStream(ctx context.Context, in *EvaluateFunctionRequest, opts ...grpc.CallOption) (FunctionEvaluator_EvaluateFunctionStreamClient, error)
 }
 
 type functionEvaluatorClient struct {
@@ -43,12 +53,81 @@ func (c *functionEvaluatorClient) EvaluateFunction(ctx context.Context, in *Eval
 	return out, nil
 }
 
+func (c *functionEvaluatorClient) AsyncEvaluateFunction(ctx context.Context, in *EvaluateFunctionRequest, opts ...grpc.CallOption) (*AsyncEvaluationJob, error) {
+	out := new(AsyncEvaluationJob)
+	err := c.cc.Invoke(ctx, "/evaluator.FunctionEvaluator/AsyncEvaluateFunction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *functionEvaluatorClient) GetEvaluationStatus(ctx context.Context, in *GetEvaluationStatusRequest, opts ...grpc.CallOption) (*EvaluationStatus, error) {
+	out := new(EvaluationStatus)
+	err := c.cc.Invoke(ctx, "/evaluator.FunctionEvaluator/GetEvaluationStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *functionEvaluatorClient) CancelEvaluation(ctx context.Context, in *CancelEvaluationRequest, opts ...grpc.CallOption) (*CancelEvaluationResponse, error) {
+	out := new(CancelEvaluationResponse)
+	err := c.cc.Invoke(ctx, "/evaluator.FunctionEvaluator/CancelEvaluation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *functionEvaluatorClient) EvaluateFunctionStream(ctx context.Context, in *EvaluateFunctionRequest, opts ...grpc.CallOption) (FunctionEvaluator_EvaluateFunctionStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FunctionEvaluator_ServiceDesc.Streams[0], "/evaluator.FunctionEvaluator/EvaluateFunctionStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &functionEvaluatorEvaluateFunctionStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FunctionEvaluator_EvaluateFunctionStreamClient interface {
+	Recv() (*EvaluateFunctionStreamEvent, error)
+	grpc.ClientStream
+}
+
+type functionEvaluatorEvaluateFunctionStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *functionEvaluatorEvaluateFunctionStreamClient) Recv() (*EvaluateFunctionStreamEvent, error) {
+	m := new(EvaluateFunctionStreamEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // FunctionEvaluatorServer is the server API for FunctionEvaluator service.
 // All implementations must embed UnimplementedFunctionEvaluatorServer
 // for forward compatibility
 type FunctionEvaluatorServer interface {
 	// Evaluates a kpt function on the provided package
 	EvaluateFunction(context.Context, *EvaluateFunctionRequest) (*EvaluateFunctionResponse, error)
+	// Starts evaluating a kpt function and returns without waiting for the
+	// function to complete
+	AsyncEvaluateFunction(context.Context, *EvaluateFunctionRequest) (*AsyncEvaluationJob, error)
+	// Returns the status of an evaluation started by AsyncEvaluateFunction
+	GetEvaluationStatus(context.Context, *GetEvaluationStatusRequest) (*EvaluationStatus, error)
+	// Cancels an evaluation started by AsyncEvaluateFunction
+	CancelEvaluation(context.Context, *CancelEvaluationRequest) (*CancelEvaluationResponse, error)
+	// Evaluates a kpt function on the provided package, streaming the lines of
+	// the function log as they are written. The last event holds the response.
+	EvaluateFunctionStream(*EvaluateFunctionRequest, FunctionEvaluator_EvaluateFunctionStreamServer) error
 	mustEmbedUnimplementedFunctionEvaluatorServer()
 }
 
@@ -59,6 +138,18 @@ type UnimplementedFunctionEvaluatorServer struct {
 func (UnimplementedFunctionEvaluatorServer) EvaluateFunction(context.Context, *EvaluateFunctionRequest) (*EvaluateFunctionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EvaluateFunction not implemented")
 }
+func (UnimplementedFunctionEvaluatorServer) AsyncEvaluateFunction(context.Context, *EvaluateFunctionRequest) (*AsyncEvaluationJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AsyncEvaluateFunction not implemented")
+}
+func (UnimplementedFunctionEvaluatorServer) GetEvaluationStatus(context.Context, *GetEvaluationStatusRequest) (*EvaluationStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvaluationStatus not implemented")
+}
+func (UnimplementedFunctionEvaluatorServer) CancelEvaluation(context.Context, *CancelEvaluationRequest) (*CancelEvaluationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelEvaluation not implemented")
+}
+func (UnimplementedFunctionEvaluatorServer) EvaluateFunctionStream(*EvaluateFunctionRequest, FunctionEvaluator_EvaluateFunctionStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EvaluateFunctionStream not implemented")
+}
 func (UnimplementedFunctionEvaluatorServer) mustEmbedUnimplementedFunctionEvaluatorServer() {}
 
 // UnsafeFunctionEvaluatorServer may be embedded to opt out of forward compatibility for this service.
@@ -90,6 +181,81 @@ func _FunctionEvaluator_EvaluateFunction_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FunctionEvaluator_AsyncEvaluateFunction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateFunctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FunctionEvaluatorServer).AsyncEvaluateFunction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/evaluator.FunctionEvaluator/AsyncEvaluateFunction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FunctionEvaluatorServer).AsyncEvaluateFunction(ctx, req.(*EvaluateFunctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FunctionEvaluator_GetEvaluationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEvaluationStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FunctionEvaluatorServer).GetEvaluationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/evaluator.FunctionEvaluator/GetEvaluationStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FunctionEvaluatorServer).GetEvaluationStatus(ctx, req.(*GetEvaluationStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FunctionEvaluator_CancelEvaluation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelEvaluationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FunctionEvaluatorServer).CancelEvaluation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/evaluator.FunctionEvaluator/CancelEvaluation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FunctionEvaluatorServer).CancelEvaluation(ctx, req.(*CancelEvaluationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FunctionEvaluator_EvaluateFunctionStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EvaluateFunctionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FunctionEvaluatorServer).EvaluateFunctionStream(m, &functionEvaluatorEvaluateFunctionStreamServer{stream})
+}
+
+type FunctionEvaluator_EvaluateFunctionStreamServer interface {
+	Send(*EvaluateFunctionStreamEvent) error
+	grpc.ServerStream
+}
+
+type functionEvaluatorEvaluateFunctionStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *functionEvaluatorEvaluateFunctionStreamServer) Send(m *EvaluateFunctionStreamEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // FunctionEvaluator_ServiceDesc is the grpc.ServiceDesc for FunctionEvaluator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -101,7 +267,25 @@ var FunctionEvaluator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "EvaluateFunction",
 			Handler:    _FunctionEvaluator_EvaluateFunction_Handler,
 		},
+		{
+			MethodName: "AsyncEvaluateFunction",
+			Handler:    _FunctionEvaluator_AsyncEvaluateFunction_Handler,
+		},
+		{
+			MethodName: "GetEvaluationStatus",
+			Handler:    _FunctionEvaluator_GetEvaluationStatus_Handler,
+		},
+		{
+			MethodName: "CancelEvaluation",
+			Handler:    _FunctionEvaluator_CancelEvaluation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EvaluateFunctionStream",
+			Handler:       _FunctionEvaluator_EvaluateFunctionStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "evaluator.proto",
 }
@@ -0,0 +1,89 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultMaxConcurrentEvaluations bounds the number of function processes
+	// running at the same time.
+	defaultMaxConcurrentEvaluations = 10
+	// defaultQueueTimeout bounds how long an evaluation waits for one of the
+	// running evaluations to complete.
+	defaultQueueTimeout = 10 * time.Second
+)
+
+// evaluationLimiter bounds the number of concurrent evaluations.
+type evaluationLimiter struct {
+	// slots holds a token for every running evaluation.
+	slots chan struct{}
+	// timeout bounds how long acquire waits for a slot. Zero waits until the
+	// context is done.
+	timeout time.Duration
+}
+
+func newEvaluationLimiter(max int, timeout time.Duration) *evaluationLimiter {
+	return &evaluationLimiter{
+		slots:   make(chan struct{}, max),
+		timeout: timeout,
+	}
+}
+
+// tryAcquire takes a free slot without waiting. It returns the function
+// releasing the slot, or false if all slots are taken.
+func (l *evaluationLimiter) tryAcquire() (func(), bool) {
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, true
+	default:
+		return nil, false
+	}
+}
+
+// acquire waits for a free slot and returns the function releasing it.
+func (l *evaluationLimiter) acquire(ctx context.Context) (func(), error) {
+	if release, ok := l.tryAcquire(); ok {
+		return release, nil
+	}
+
+	var expired <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-expired:
+		return nil, status.Errorf(codes.ResourceExhausted, "Concurrency limit of %d evaluations reached; no evaluation completed within %s", cap(l.slots), l.timeout)
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, status.Errorf(codes.DeadlineExceeded, "Deadline exceeded while waiting for one of %d concurrent evaluations to complete", cap(l.slots))
+		}
+		return nil, status.Errorf(codes.Canceled, "Canceled while waiting for one of %d concurrent evaluations to complete", cap(l.slots))
+	}
+}
+
+func (l *evaluationLimiter) release() {
+	<-l.slots
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	const (
+		limit    = 3
+		requests = 50
+	)
+
+	// Every function registers itself in running while it runs and logs
+	// how many functions were running at that time.
+	dir := t.TempDir()
+	running := filepath.Join(dir, "running")
+	if err := os.Mkdir(running, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	log := filepath.Join(dir, "log")
+	script := `touch "$1/$$"; ls "$1" | wc -l >> "$2"; sleep 0.05; rm "$1/$$"`
+
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"sh", "-c", script, "sh", running, log},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+		timeout:    defaultFunctionTimeout,
+		limiter:    newEvaluationLimiter(limit, time.Minute),
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("EvaluateFunction failed: %v", err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	lines := strings.Fields(string(data))
+	if got, want := len(lines), requests; got != want {
+		t.Errorf("got %d evaluations, want %d", got, want)
+	}
+	max := 0
+	for _, line := range lines {
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		if n > max {
+			max = n
+		}
+	}
+	if max > limit {
+		t.Errorf("%d functions ran concurrently; want at most %d", max, limit)
+	}
+}
+
+func TestConcurrencyLimitQueueTimeout(t *testing.T) {
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"sleep", "1"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+		timeout:    defaultFunctionTimeout,
+		limiter:    newEvaluationLimiter(1, 50*time.Millisecond),
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"})
+		done <- err
+	}()
+	// Wait for the first evaluation to take the only slot.
+	for len(evaluator.limiter.slots) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"})
+	if got, want := status.Code(err), codes.ResourceExhausted; got != want {
+		t.Errorf("EvaluateFunction returned %v (%v); want code %s", got, err, want)
+	}
+	if message := status.Convert(err).Message(); !strings.Contains(message, "Concurrency limit") {
+		t.Errorf("error %q does not mention the concurrency limit", message)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("First EvaluateFunction failed: %v", err)
+	}
+}
+
+func TestEvaluationLimiterTryAcquire(t *testing.T) {
+	limiter := newEvaluationLimiter(1, time.Minute)
+
+	release, ok := limiter.tryAcquire()
+	if !ok {
+		t.Fatalf("tryAcquire failed with a free slot")
+	}
+	if _, ok := limiter.tryAcquire(); ok {
+		t.Errorf("tryAcquire succeeded with all slots taken")
+	}
+	release()
+	if _, ok := limiter.tryAcquire(); !ok {
+		t.Errorf("tryAcquire failed after the slot was released")
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// parseEnvFile reads the environment variables of a file with one KEY=VALUE
+// entry per line. Blank lines and lines starting with '#' are ignored.
+func parseEnvFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read env file: %w", err)
+	}
+	defer f.Close()
+
+	var env []string
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		key, _, ok := cut(entry, "=")
+		if !ok || !validEnvKey(key) {
+			return nil, fmt.Errorf("malformed entry in env file %s:%d: expected KEY=VALUE, got %q", path, line, entry)
+		}
+		env = append(env, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read env file %s: %w", path, err)
+	}
+	return env, nil
+}
+
+// validEnvKey returns true if key is a non-empty environment variable name
+// without whitespace or '='.
+func validEnvKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, " \t=")
+}
+
+// functionEnv returns the environment of a function evaluation: base, then
+// the entries of the env file, then the per-request overrides, each taking
+// precedence over the previous ones.
+func functionEnv(base, file []string, overrides map[string]string) []string {
+	env := []string{}
+	index := map[string]int{}
+	set := func(key, entry string) {
+		if i, ok := index[key]; ok {
+			env[i] = entry
+			return
+		}
+		index[key] = len(env)
+		env = append(env, entry)
+	}
+	for _, list := range [][]string{base, file} {
+		for _, entry := range list {
+			key, _, _ := cut(entry, "=")
+			set(key, entry)
+		}
+	}
+
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		set(key, key+"="+overrides[key])
+	}
+	return env
+}
+
+// cut slices s around the first instance of sep, like strings.Cut.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func writeEnvFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "env")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+	return path
+}
+
+func TestParseEnvFile(t *testing.T) {
+	path := writeEnvFile(t, "# Proxy settings\nHTTP_PROXY=http://proxy:3128\n\nNO_PROXY=localhost,127.0.0.1\nEMPTY=\nEQUALS=a=b\n")
+	got, err := parseEnvFile(path)
+	if err != nil {
+		t.Fatalf("parseEnvFile failed: %v", err)
+	}
+	want := []string{"HTTP_PROXY=http://proxy:3128", "NO_PROXY=localhost,127.0.0.1", "EMPTY=", "EQUALS=a=b"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected env (-want,+got): %s", diff)
+	}
+}
+
+func TestParseEnvFileMalformed(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+	}{
+		{name: "missing value", content: "KEY=value\nMISSING\n"},
+		{name: "empty key", content: "=value\n"},
+		{name: "whitespace in key", content: "MY KEY=value\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseEnvFile(writeEnvFile(t, tc.content))
+			if err == nil {
+				t.Fatalf("parseEnvFile succeeded; want error")
+			}
+			if !strings.Contains(err.Error(), "malformed entry") {
+				t.Errorf("error %q does not report the malformed entry", err)
+			}
+		})
+	}
+}
+
+func TestOptionsRejectMalformedEnvFile(t *testing.T) {
+	o := &options{
+		envFile:                  writeEnvFile(t, "NOT_AN_ENTRY\n"),
+		maxConcurrentEvaluations: defaultMaxConcurrentEvaluations,
+		keepaliveIdle:            defaultKeepaliveIdle,
+		keepaliveInterval:        defaultKeepaliveInterval,
+		keepaliveTimeout:         defaultKeepaliveTimeout,
+	}
+	if err := o.run(); err == nil || !strings.Contains(err.Error(), "malformed entry") {
+		t.Errorf("run() returned %v; want malformed env file error", err)
+	}
+}
+
+func TestFunctionEnvPrecedence(t *testing.T) {
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"sh", "-c", `printf '%s,%s,%s' "$FROM_FILE" "$OVERRIDDEN" "$FROM_REQUEST"`},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+		timeout:    defaultFunctionTimeout,
+		env:        []string{"FROM_FILE=file", "OVERRIDDEN=file"},
+	}
+
+	res, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{
+		Image: "test-function",
+		EnvOverrides: map[string]string{
+			"OVERRIDDEN":   "request",
+			"FROM_REQUEST": "request",
+		},
+	})
+	if err != nil {
+		t.Fatalf("EvaluateFunction failed: %v", err)
+	}
+	if got, want := string(res.ResourceList), "file,request,request"; got != want {
+		t.Errorf("function environment: got %q, want %q", got, want)
+	}
+}
+
+func TestFunctionEnvInheritance(t *testing.T) {
+	t.Setenv("WRAPPER_SERVER_TEST_INHERITED", "inherited")
+	t.Setenv("WRAPPER_SERVER_TEST_OVERRIDDEN", "inherited")
+
+	for _, tc := range []struct {
+		name     string
+		cleanEnv bool
+		want     string
+	}{
+		{name: "inherit", want: "inherited,file"},
+		{name: "clean", cleanEnv: true, want: ",file"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator := &singleFunctionEvaluator{
+				// The absolute path, as a clean environment has no PATH.
+				entrypoint: []string{"/bin/sh", "-c", `printf '%s,%s' "$WRAPPER_SERVER_TEST_INHERITED" "$WRAPPER_SERVER_TEST_OVERRIDDEN"`},
+				results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+				jobs:       newJobTracker(defaultJobRetention),
+				timeout:    defaultFunctionTimeout,
+				env:        []string{"WRAPPER_SERVER_TEST_OVERRIDDEN=file"},
+				cleanEnv:   tc.cleanEnv,
+			}
+			res, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"})
+			if err != nil {
+				t.Fatalf("EvaluateFunction failed: %v", err)
+			}
+			if got := string(res.ResourceList); got != tc.want {
+				t.Errorf("function environment: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInvalidEnvOverride(t *testing.T) {
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"true"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+		timeout:    defaultFunctionTimeout,
+	}
+	_, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{
+		Image:        "test-function",
+		EnvOverrides: map[string]string{"A=B": "value"},
+	})
+	if got, want := status.Code(err), codes.InvalidArgument; got != want {
+		t.Errorf("EvaluateFunction returned %v (%v); want code %s", got, err, want)
+	}
+}
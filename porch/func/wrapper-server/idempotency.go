@@ -0,0 +1,138 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultIdempotencyTTL is how long the result of an evaluation is kept
+	// for requests retried with the same idempotency key.
+	defaultIdempotencyTTL = 5 * time.Minute
+	// defaultIdempotencyCacheSize bounds the number of results kept; the
+	// least recently used results are evicted first.
+	defaultIdempotencyCacheSize = 1000
+)
+
+// resultCache is an LRU cache of evaluation results keyed by idempotency key.
+// Every result is stored with the digest of the request which produced it, so
+// that a key reused by a different request is detected.
+type resultCache struct {
+	mutex    sync.RWMutex
+	ttl      time.Duration
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List // front is the most recently used entry
+
+	now func() time.Time
+}
+
+type resultCacheEntry struct {
+	key     string
+	digest  string
+	expires time.Time
+	result  *pb.EvaluateFunctionResponse
+}
+
+func newResultCache(ttl time.Duration, capacity int) *resultCache {
+	return &resultCache{
+		ttl:      ttl,
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+		now:      time.Now,
+	}
+}
+
+// get returns the cached result for the key and the digest of the request
+// which produced it, if present and not expired.
+func (c *resultCache) get(key string) (*pb.EvaluateFunctionResponse, string, bool) {
+	c.mutex.RLock()
+	e, ok := c.entries[key]
+	c.mutex.RUnlock()
+	if !ok {
+		return nil, "", false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry := e.Value.(*resultCacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(e)
+		return nil, "", false
+	}
+	// MoveToFront is a no-op if the entry was evicted in the meantime.
+	c.lru.MoveToFront(e)
+	return entry.result, entry.digest, true
+}
+
+// put stores the result of the request with the digest under the key,
+// evicting the least recently used results if the cache is full.
+func (c *resultCache) put(key, digest string, result *pb.EvaluateFunctionResponse) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*resultCacheEntry)
+		entry.digest = digest
+		entry.expires = expires
+		entry.result = result
+		c.lru.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&resultCacheEntry{
+		key:     key,
+		digest:  digest,
+		expires: expires,
+		result:  result,
+	})
+	for c.lru.Len() > c.capacity {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove deletes the list element from the cache. Caller must hold the lock.
+func (c *resultCache) remove(e *list.Element) {
+	entry := e.Value.(*resultCacheEntry)
+	if c.entries[entry.key] == e {
+		delete(c.entries, entry.key)
+	}
+	c.lru.Remove(e)
+}
+
+// requestDigest returns the digest of the request, excluding its idempotency
+// key.
+func requestDigest(req *pb.EvaluateFunctionRequest) (string, error) {
+	payload := proto.Clone(req).(*pb.EvaluateFunctionRequest)
+	payload.IdempotencyKey = ""
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal request: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
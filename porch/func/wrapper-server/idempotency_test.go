@@ -0,0 +1,206 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIdempotentEvaluation(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "invocations")
+	evaluator := &singleFunctionEvaluator{
+		// Record every invocation and echo the input back.
+		entrypoint: []string{"sh", "-c", "echo invoked >> " + counter + " && cat"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		res, err := evaluator.EvaluateFunction(ctx, &pb.EvaluateFunctionRequest{
+			ResourceList:   []byte("resources"),
+			Image:          "test-function",
+			IdempotencyKey: "key",
+		})
+		if err != nil {
+			t.Fatalf("EvaluateFunction failed: %v", err)
+		}
+		if got, want := string(res.ResourceList), "resources"; got != want {
+			t.Errorf("EvaluateFunction returned %q; want %q", got, want)
+		}
+	}
+
+	if got, want := countInvocations(t, counter), 1; got != want {
+		t.Errorf("function invoked %d times; want %d", got, want)
+	}
+
+	// Requests without a key are always evaluated.
+	for i := 0; i < 2; i++ {
+		if _, err := evaluator.EvaluateFunction(ctx, &pb.EvaluateFunctionRequest{
+			ResourceList: []byte("resources"),
+			Image:        "test-function",
+		}); err != nil {
+			t.Fatalf("EvaluateFunction failed: %v", err)
+		}
+	}
+
+	if got, want := countInvocations(t, counter), 3; got != want {
+		t.Errorf("function invoked %d times; want %d", got, want)
+	}
+
+	// A key reused by a different request is rejected.
+	for _, req := range []*pb.EvaluateFunctionRequest{
+		{ResourceList: []byte("other resources"), Image: "test-function", IdempotencyKey: "key"},
+		{ResourceList: []byte("resources"), Image: "other-function", IdempotencyKey: "key"},
+		{ResourceList: []byte("resources"), Image: "test-function", IdempotencyKey: "key", EnvOverrides: map[string]string{"A": "b"}},
+	} {
+		_, err := evaluator.EvaluateFunction(ctx, req)
+		if got, want := status.Code(err), codes.InvalidArgument; got != want {
+			t.Errorf("EvaluateFunction with reused key returned %v (%v); want code %s", got, err, want)
+		}
+	}
+	if got, want := countInvocations(t, counter), 3; got != want {
+		t.Errorf("function invoked %d times; want %d", got, want)
+	}
+}
+
+func TestIdempotentEvaluationSkipsLimits(t *testing.T) {
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"cat"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		limiter:    newEvaluationLimiter(1, time.Millisecond),
+	}
+	req := &pb.EvaluateFunctionRequest{
+		ResourceList:   []byte("resources"),
+		Image:          "test-function",
+		IdempotencyKey: "key",
+	}
+
+	ctx := context.Background()
+	if _, err := evaluator.EvaluateFunction(ctx, req); err != nil {
+		t.Fatalf("EvaluateFunction failed: %v", err)
+	}
+
+	// Retries are answered from the cache while all slots are taken.
+	release, err := evaluator.limiter.acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	defer release()
+	if _, err := evaluator.EvaluateFunction(ctx, req); err != nil {
+		t.Errorf("EvaluateFunction of cached result failed: %v", err)
+	}
+}
+
+func TestResultCacheExpiry(t *testing.T) {
+	now := time.Now()
+	cache := newResultCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	cache.put("a", "digest", &pb.EvaluateFunctionResponse{Log: []byte("a")})
+	if _, _, ok := cache.get("a"); !ok {
+		t.Errorf("result for key %q not found", "a")
+	}
+
+	now = now.Add(time.Minute)
+	if _, _, ok := cache.get("a"); ok {
+		t.Errorf("expired result for key %q returned", "a")
+	}
+}
+
+func TestResultCacheEviction(t *testing.T) {
+	cache := newResultCache(time.Minute, 2)
+
+	cache.put("a", "digest", &pb.EvaluateFunctionResponse{})
+	cache.put("b", "digest", &pb.EvaluateFunctionResponse{})
+	// Use "a" so that "b" becomes the least recently used result.
+	if _, _, ok := cache.get("a"); !ok {
+		t.Errorf("result for key %q not found", "a")
+	}
+	cache.put("c", "digest", &pb.EvaluateFunctionResponse{})
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, _, got := cache.get(key); got != want {
+			t.Errorf("cache.get(%q) found = %t; want %t", key, got, want)
+		}
+	}
+}
+
+func TestConcurrentIdempotentEvaluation(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "invocations")
+	proceed := filepath.Join(dir, "proceed")
+	evaluator := &singleFunctionEvaluator{
+		// Record the invocation, then wait for the test to let it complete.
+		entrypoint: []string{"sh", "-c", "echo invoked >> " + counter + " && while [ ! -f " + proceed + " ]; do sleep 0.01; done && cat"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+	}
+	req := &pb.EvaluateFunctionRequest{
+		ResourceList:   []byte("resources"),
+		Image:          "test-function",
+		IdempotencyKey: "key",
+	}
+
+	ctx := context.Background()
+	results := make(chan error, 2)
+	evaluate := func() {
+		res, err := evaluator.EvaluateFunction(ctx, req)
+		if err == nil && string(res.ResourceList) != "resources" {
+			err = fmt.Errorf("EvaluateFunction returned %q", res.ResourceList)
+		}
+		results <- err
+	}
+
+	go evaluate()
+	for {
+		if _, err := os.Stat(counter); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// The retry joins the evaluation in flight.
+	go evaluate()
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(proceed, nil, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("EvaluateFunction failed: %v", err)
+		}
+	}
+	if got, want := countInvocations(t, counter), 1; got != want {
+		t.Errorf("function invoked %d times; want %d", got, want)
+	}
+}
+
+func countInvocations(t *testing.T, path string) int {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read invocation counter: %v", err)
+	}
+	return strings.Count(string(b), "invoked")
+}
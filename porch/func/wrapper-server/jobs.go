@@ -0,0 +1,150 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// defaultJobRetention is how long the results of completed asynchronous
+// evaluations are kept for status requests.
+const defaultJobRetention = 5 * time.Minute
+
+// evaluationJob is an asynchronous function evaluation.
+type evaluationJob struct {
+	id     string
+	cancel context.CancelFunc
+
+	mutex    sync.Mutex
+	state    pb.EvaluationState
+	response *pb.EvaluateFunctionResponse
+	err      string
+}
+
+// status returns the current status of the job.
+func (j *evaluationJob) status() *pb.EvaluationStatus {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return &pb.EvaluationStatus{
+		JobId:    j.id,
+		State:    j.state,
+		Response: j.response,
+		Error:    j.err,
+	}
+}
+
+// complete records the outcome of the job. It returns false if the job had
+// already completed, for example because it was cancelled.
+func (j *evaluationJob) complete(state pb.EvaluationState, response *pb.EvaluateFunctionResponse, err string) bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.state != pb.EvaluationState_RUNNING {
+		return false
+	}
+	j.state = state
+	j.response = response
+	j.err = err
+	return true
+}
+
+// jobTracker tracks asynchronous evaluations. Completed jobs are forgotten
+// after the retention period.
+type jobTracker struct {
+	jobs      sync.Map // job ID -> *evaluationJob
+	retention time.Duration
+}
+
+func newJobTracker(retention time.Duration) *jobTracker {
+	return &jobTracker{
+		retention: retention,
+	}
+}
+
+// start runs evaluate in the background and returns the job tracking it.
+func (t *jobTracker) start(evaluate func(ctx context.Context) (*pb.EvaluateFunctionResponse, error)) (*evaluationJob, error) {
+	id, err := newJobID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to generate job id: %s", err)
+	}
+
+	// The job outlives the request that started it.
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &evaluationJob{
+		id:     id,
+		cancel: cancel,
+		state:  pb.EvaluationState_RUNNING,
+	}
+	t.jobs.Store(id, job)
+
+	go func() {
+		defer cancel()
+		res, err := evaluate(ctx)
+		if err != nil {
+			job.complete(pb.EvaluationState_FAILED, nil, err.Error())
+		} else {
+			job.complete(pb.EvaluationState_SUCCEEDED, res, "")
+		}
+		klog.Infof("Asynchronous evaluation %s completed", id)
+		t.forgetAfterRetention(job)
+	}()
+	return job, nil
+}
+
+// get returns the job with the id.
+func (t *jobTracker) get(id string) (*evaluationJob, error) {
+	job, ok := t.jobs.Load(id)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "evaluation job %q not found", id)
+	}
+	return job.(*evaluationJob), nil
+}
+
+// cancel cancels the job with the id. Cancelling a completed job has no effect.
+func (t *jobTracker) cancel(id string) error {
+	job, err := t.get(id)
+	if err != nil {
+		return err
+	}
+	if job.complete(pb.EvaluationState_CANCELLED, nil, "") {
+		klog.Infof("Asynchronous evaluation %s cancelled", id)
+	}
+	job.cancel()
+	return nil
+}
+
+func (t *jobTracker) forgetAfterRetention(job *evaluationJob) {
+	time.AfterFunc(t.retention, func() {
+		t.jobs.Delete(job.id)
+	})
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
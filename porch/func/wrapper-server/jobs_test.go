@@ -0,0 +1,140 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAsyncEvaluation(t *testing.T) {
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"cat"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+	}
+
+	ctx := context.Background()
+	job, err := evaluator.AsyncEvaluateFunction(ctx, &pb.EvaluateFunctionRequest{
+		ResourceList: []byte("resources"),
+		Image:        "test-function",
+	})
+	if err != nil {
+		t.Fatalf("AsyncEvaluateFunction failed: %v", err)
+	}
+
+	s := waitForCompletion(t, evaluator, job.JobId)
+	if got, want := s.State, pb.EvaluationState_SUCCEEDED; got != want {
+		t.Fatalf("evaluation state is %s; want %s (error %q)", got, want, s.Error)
+	}
+	if got, want := string(s.Response.GetResourceList()), "resources"; got != want {
+		t.Errorf("evaluation returned %q; want %q", got, want)
+	}
+}
+
+func TestCancelEvaluation(t *testing.T) {
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"sleep", "60"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+	}
+
+	ctx := context.Background()
+	job, err := evaluator.AsyncEvaluateFunction(ctx, &pb.EvaluateFunctionRequest{Image: "test-function"})
+	if err != nil {
+		t.Fatalf("AsyncEvaluateFunction failed: %v", err)
+	}
+
+	s, err := evaluator.GetEvaluationStatus(ctx, &pb.GetEvaluationStatusRequest{JobId: job.JobId})
+	if err != nil {
+		t.Fatalf("GetEvaluationStatus failed: %v", err)
+	}
+	if got, want := s.State, pb.EvaluationState_RUNNING; got != want {
+		t.Errorf("evaluation state is %s; want %s", got, want)
+	}
+
+	if _, err := evaluator.CancelEvaluation(ctx, &pb.CancelEvaluationRequest{JobId: job.JobId}); err != nil {
+		t.Fatalf("CancelEvaluation failed: %v", err)
+	}
+	s = waitForCompletion(t, evaluator, job.JobId)
+	if got, want := s.State, pb.EvaluationState_CANCELLED; got != want {
+		t.Errorf("evaluation state is %s; want %s", got, want)
+	}
+}
+
+func TestJobRetention(t *testing.T) {
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"cat"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(10 * time.Millisecond),
+	}
+
+	ctx := context.Background()
+	job, err := evaluator.AsyncEvaluateFunction(ctx, &pb.EvaluateFunctionRequest{Image: "test-function"})
+	if err != nil {
+		t.Fatalf("AsyncEvaluateFunction failed: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		_, err := evaluator.GetEvaluationStatus(ctx, &pb.GetEvaluationStatusRequest{JobId: job.JobId})
+		if status.Code(err) == codes.NotFound {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("completed job was not removed after the retention period")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUnknownJob(t *testing.T) {
+	evaluator := &singleFunctionEvaluator{
+		jobs: newJobTracker(defaultJobRetention),
+	}
+
+	ctx := context.Background()
+	if _, err := evaluator.GetEvaluationStatus(ctx, &pb.GetEvaluationStatusRequest{JobId: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetEvaluationStatus returned %v; want NotFound", err)
+	}
+	if _, err := evaluator.CancelEvaluation(ctx, &pb.CancelEvaluationRequest{JobId: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("CancelEvaluation returned %v; want NotFound", err)
+	}
+}
+
+// waitForCompletion polls the status of the job until it is no longer running.
+func waitForCompletion(t *testing.T, evaluator *singleFunctionEvaluator, id string) *pb.EvaluationStatus {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		s, err := evaluator.GetEvaluationStatus(context.Background(), &pb.GetEvaluationStatusRequest{JobId: id})
+		if err != nil {
+			t.Fatalf("GetEvaluationStatus failed: %v", err)
+		}
+		if s.State != pb.EvaluationState_RUNNING {
+			return s
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("evaluation %s did not complete", id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	// defaultKeepaliveIdle is how long a connection without streams is kept
+	// open.
+	defaultKeepaliveIdle = time.Minute
+	// defaultKeepaliveInterval is how long a connection may be inactive
+	// before the server pings the client.
+	defaultKeepaliveInterval = 30 * time.Second
+	// defaultKeepaliveTimeout is how long the server waits for the ping to be
+	// acknowledged before it closes the connection of a dead client.
+	defaultKeepaliveTimeout = 10 * time.Second
+)
+
+// defaultKeepalive are the keepalive parameters of the server unless
+// overridden by flags.
+var defaultKeepalive = keepalive.ServerParameters{
+	MaxConnectionIdle: defaultKeepaliveIdle,
+	Time:              defaultKeepaliveInterval,
+	Timeout:           defaultKeepaliveTimeout,
+}
+
+// keepaliveParameters returns the keepalive parameters of the server. Zero
+// idle keeps connections without streams open indefinitely.
+func keepaliveParameters(idle, interval, timeout time.Duration) (keepalive.ServerParameters, error) {
+	if idle < 0 {
+		return keepalive.ServerParameters{}, fmt.Errorf("--grpc-keepalive-idle must not be negative, got %v", idle)
+	}
+	if interval <= 0 {
+		return keepalive.ServerParameters{}, fmt.Errorf("--grpc-keepalive-interval must be positive, got %v", interval)
+	}
+	if timeout <= 0 {
+		return keepalive.ServerParameters{}, fmt.Errorf("--grpc-keepalive-timeout must be positive, got %v", timeout)
+	}
+	return keepalive.ServerParameters{
+		MaxConnectionIdle: idle,
+		Time:              interval,
+		Timeout:           timeout,
+	}, nil
+}
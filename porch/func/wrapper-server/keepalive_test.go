@@ -0,0 +1,191 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+func TestKeepaliveParameters(t *testing.T) {
+	if _, err := keepaliveParameters(0, time.Second, time.Second); err != nil {
+		t.Errorf("keepaliveParameters with zero idle failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name                    string
+		idle, interval, timeout time.Duration
+	}{
+		{name: "negative idle", idle: -time.Second, interval: time.Second, timeout: time.Second},
+		{name: "zero interval", idle: time.Second, timeout: time.Second},
+		{name: "zero timeout", idle: time.Second, interval: time.Second},
+	} {
+		if _, err := keepaliveParameters(tc.idle, tc.interval, tc.timeout); err == nil {
+			t.Errorf("%s: keepaliveParameters succeeded; want error", tc.name)
+		}
+	}
+}
+
+// blockingEvaluator blocks evaluations until they are cancelled.
+type blockingEvaluator struct {
+	pb.UnimplementedFunctionEvaluatorServer
+	started   chan struct{}
+	cancelled chan struct{}
+}
+
+func (e *blockingEvaluator) EvaluateFunction(ctx context.Context, req *pb.EvaluateFunctionRequest) (*pb.EvaluateFunctionResponse, error) {
+	close(e.started)
+	<-ctx.Done()
+	close(e.cancelled)
+	return nil, ctx.Err()
+}
+
+// blackholeProxy forwards TCP connections until blackhole is called, after
+// which it silently drops all traffic without closing the connections, like
+// a client which vanished without sending a FIN.
+type blackholeProxy struct {
+	mutex   sync.Mutex
+	dropped bool
+	conns   []net.Conn
+}
+
+func (p *blackholeProxy) blackhole() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.dropped = true
+}
+
+func (p *blackholeProxy) isDropped() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.dropped
+}
+
+func (p *blackholeProxy) close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, c := range p.conns {
+		_ = c.Close()
+	}
+}
+
+func (p *blackholeProxy) serve(lis net.Listener, target string) {
+	for {
+		client, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		server, err := net.Dial("tcp", target)
+		if err != nil {
+			_ = client.Close()
+			continue
+		}
+		p.mutex.Lock()
+		p.conns = append(p.conns, client, server)
+		p.mutex.Unlock()
+
+		go p.forward(server, client)
+		go p.forward(client, server)
+	}
+}
+
+func (p *blackholeProxy) forward(dst io.Writer, src io.Reader) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 && !p.isDropped() {
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func TestKeepaliveDetectsDeadClient(t *testing.T) {
+	kp := keepalive.ServerParameters{
+		Time:    time.Second,
+		Timeout: time.Second,
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	evaluator := &blockingEvaluator{
+		started:   make(chan struct{}),
+		cancelled: make(chan struct{}),
+	}
+	server := newGRPCServer(evaluator, nil, kp)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	proxyLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	proxy := &blackholeProxy{}
+	go proxy.serve(proxyLis, lis.Addr().String())
+	t.Cleanup(func() {
+		_ = proxyLis.Close()
+		proxy.close()
+	})
+
+	cc, err := grpc.Dial(proxyLis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial %s: %v", proxyLis.Addr(), err)
+	}
+	t.Cleanup(func() { _ = cc.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_, _ = pb.NewFunctionEvaluatorClient(cc).EvaluateFunction(ctx, &pb.EvaluateFunctionRequest{
+			ResourceList: []byte("resources"),
+			Image:        "test-function",
+		})
+	}()
+
+	select {
+	case <-evaluator.started:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("evaluation did not start")
+	}
+
+	proxy.blackhole()
+	start := time.Now()
+
+	// The server pings the client after kp.Time of inactivity and closes the
+	// connection when the ping is not acknowledged within kp.Timeout.
+	deadline := kp.Time + kp.Timeout + 5*time.Second
+	select {
+	case <-evaluator.cancelled:
+		t.Logf("evaluation of dead client cancelled after %v", time.Since(start))
+	case <-time.After(deadline):
+		t.Errorf("evaluation of dead client not cancelled within %v", deadline)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "bytes"
+
+// defaultMaxResourceListBytes bounds the size of the ResourceList a function
+// is evaluated with and of the output it produces.
+const defaultMaxResourceListBytes = 100 << 20
+
+// limitedBuffer is a buffer which keeps at most limit bytes. Writes beyond
+// the limit are counted but discarded rather than failed, so the function
+// writing to it is not blocked on a full pipe before it exits.
+type limitedBuffer struct {
+	buf bytes.Buffer
+	// limit is the maximum number of bytes kept. Zero disables the limit.
+	limit int64
+	// written is the number of bytes written, including discarded ones.
+	written int64
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.written += int64(len(p))
+	if b.limit > 0 {
+		remaining := b.limit - int64(b.buf.Len())
+		if remaining <= 0 {
+			return len(p), nil
+		}
+		if int64(len(p)) > remaining {
+			b.buf.Write(p[:remaining])
+			return len(p), nil
+		}
+	}
+	return b.buf.Write(p)
+}
+
+// exceeded returns true if more than limit bytes were written.
+func (b *limitedBuffer) exceeded() bool {
+	return b.limit > 0 && b.written > b.limit
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
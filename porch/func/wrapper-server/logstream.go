@@ -0,0 +1,110 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"io"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+)
+
+const (
+	// partialLogBytes is the size of the log tail returned as PartialLog.
+	partialLogBytes = 64 << 10
+	// maxLogLineBytes bounds the length of a streamed log line. Longer lines
+	// are split.
+	maxLogLineBytes = 64 << 10
+)
+
+// ringBuffer keeps the last size bytes written to it.
+type ringBuffer struct {
+	size int
+	buf  []byte
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{size: size}
+}
+
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) >= r.size {
+		r.buf = append(r.buf[:0], p[len(p)-r.size:]...)
+		return n, nil
+	}
+	if over := len(r.buf) + len(p) - r.size; over > 0 {
+		r.buf = append(r.buf[:0], r.buf[over:]...)
+	}
+	r.buf = append(r.buf, p...)
+	return n, nil
+}
+
+func (r *ringBuffer) Bytes() []byte {
+	return r.buf
+}
+
+// lineStreamer passes the lines written to it to a callback, from a
+// goroutine reading the other end of a pipe, so a slow callback does not
+// block the function. Once the callback fails, the remaining lines are
+// discarded.
+type lineStreamer struct {
+	w    *io.PipeWriter
+	done chan struct{}
+}
+
+func newLineStreamer(onLine func(line []byte) error) *lineStreamer {
+	r, w := io.Pipe()
+	s := &lineStreamer{w: w, done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		reader := bufio.NewReaderSize(r, maxLogLineBytes)
+		var failed bool
+		for {
+			line, err := reader.ReadSlice('\n')
+			if len(line) > 0 && !failed {
+				if cbErr := onLine(append([]byte(nil), line...)); cbErr != nil {
+					failed = true
+				}
+			}
+			switch err {
+			case nil, bufio.ErrBufferFull:
+				continue
+			default:
+				// The pipe is closed once the function exits.
+				return
+			}
+		}
+	}()
+	return s
+}
+
+func (s *lineStreamer) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
+// Close flushes the last line and waits for the callback to return.
+func (s *lineStreamer) Close() error {
+	err := s.w.Close()
+	<-s.done
+	return err
+}
+
+// streamLogLine returns a callback sending log lines to stream.
+func streamLogLine(stream pb.FunctionEvaluator_EvaluateFunctionStreamServer) func(line []byte) error {
+	return func(line []byte) error {
+		return stream.Send(&pb.EvaluateFunctionStreamEvent{LogLine: line})
+	}
+}
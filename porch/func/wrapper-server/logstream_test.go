@@ -0,0 +1,148 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestRingBuffer(t *testing.T) {
+	r := newRingBuffer(8)
+	for _, tc := range []struct {
+		write string
+		want  string
+	}{
+		{write: "abc", want: "abc"},
+		{write: "defgh", want: "abcdefgh"},
+		{write: "ij", want: "cdefghij"},
+		{write: "0123456789", want: "23456789"},
+	} {
+		if _, err := r.Write([]byte(tc.write)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if got := string(r.Bytes()); got != tc.want {
+			t.Errorf("after writing %q: got %q, want %q", tc.write, got, tc.want)
+		}
+	}
+}
+
+// startEvaluatorServer serves evaluator on a local port and returns a client.
+func startEvaluatorServer(t *testing.T, evaluator pb.FunctionEvaluatorServer) pb.FunctionEvaluatorClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := newGRPCServer(evaluator, nil, defaultKeepalive)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	cc, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial %s: %v", lis.Addr(), err)
+	}
+	t.Cleanup(func() { _ = cc.Close() })
+	return pb.NewFunctionEvaluatorClient(cc)
+}
+
+func TestEvaluateFunctionStream(t *testing.T) {
+	client := startEvaluatorServer(t, &singleFunctionEvaluator{
+		// The second line is only written after the first one was received.
+		entrypoint: []string{"sh", "-c", "echo first >&2; sleep 2; printf 'second\\nunterminated' >&2; exec cat"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+		timeout:    defaultFunctionTimeout,
+	})
+
+	start := time.Now()
+	stream, err := client.EvaluateFunctionStream(context.Background(), &pb.EvaluateFunctionRequest{
+		ResourceList: []byte("resources"),
+		Image:        "test-function",
+	})
+	if err != nil {
+		t.Fatalf("EvaluateFunctionStream failed: %v", err)
+	}
+
+	var lines []string
+	var res *pb.EvaluateFunctionResponse
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if event.Response != nil {
+			res = event.Response
+			continue
+		}
+		if len(lines) == 0 {
+			if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+				t.Errorf("first log line received after %v; want before the function completes", elapsed)
+			}
+		}
+		lines = append(lines, string(event.LogLine))
+	}
+
+	if diff := cmp.Diff([]string{"first\n", "second\n", "unterminated"}, lines); diff != "" {
+		t.Errorf("unexpected log lines (-want,+got): %s", diff)
+	}
+	if res == nil {
+		t.Fatalf("stream did not end with a response")
+	}
+	if got, want := string(res.ResourceList), "resources"; got != want {
+		t.Errorf("ResourceList: got %q, want %q", got, want)
+	}
+	for name, got := range map[string][]byte{"Log": res.Log, "PartialLog": res.PartialLog} {
+		if want := "first\nsecond\nunterminated"; string(got) != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestEvaluateFunctionUnaryPartialLog(t *testing.T) {
+	client := startEvaluatorServer(t, &singleFunctionEvaluator{
+		entrypoint: []string{"sh", "-c", "head -c 100000 /dev/zero | tr '\\0' x >&2; echo tail >&2; exit 1"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+		timeout:    defaultFunctionTimeout,
+	})
+
+	res, err := client.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"})
+	if err != nil {
+		t.Fatalf("EvaluateFunction failed: %v", err)
+	}
+	if got, want := len(res.PartialLog), partialLogBytes; got != want {
+		t.Errorf("PartialLog is %d bytes; want %d", got, want)
+	}
+	if !strings.HasSuffix(string(res.PartialLog), "xtail\n") {
+		t.Errorf("PartialLog does not end with the end of the log")
+	}
+	if got, want := len(res.Log), 100000+len("tail\n"); got != want {
+		t.Errorf("Log is %d bytes; want %d", got, want)
+	}
+}
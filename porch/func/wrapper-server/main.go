@@ -17,21 +17,32 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
+	"time"
 
 	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/status"
 	"k8s.io/klog/v2"
 )
 
+// defaultFunctionTimeout bounds the duration of a single function evaluation.
+const defaultFunctionTimeout = 30 * time.Second
+
 func main() {
 	op := &options{}
 	cmd := &cobra.Command{
@@ -46,6 +57,50 @@ func main() {
 		},
 	}
 	cmd.Flags().IntVar(&op.port, "port", 9446, "The server port")
+	cmd.Flags().BoolVar(&op.sandboxTmpfs, "sandbox-tmpfs", false,
+		"Mount a tmpfs as the working directory of every function evaluation. Requires linux.")
+	cmd.Flags().Int64Var(&op.sandboxTmpfsSizeBytes, "sandbox-tmpfs-size-bytes", defaultSandboxTmpfsSizeBytes,
+		"Size limit of the tmpfs mounted by --sandbox-tmpfs.")
+	cmd.Flags().DurationVar(&op.jobRetention, "job-retention", defaultJobRetention,
+		"How long the results of completed asynchronous evaluations are kept.")
+	cmd.Flags().DurationVar(&op.functionTimeout, "function-timeout", defaultFunctionTimeout,
+		"Maximum duration of a single function evaluation. Zero disables the timeout.")
+	cmd.Flags().StringVar(&op.tlsCertFile, "tls-cert-file", "",
+		"File containing the certificate of the gRPC server. Enables TLS together with --tls-key-file.")
+	cmd.Flags().StringVar(&op.tlsKeyFile, "tls-key-file", "",
+		"File containing the private key matching --tls-cert-file.")
+	cmd.Flags().StringVar(&op.tlsCAFile, "tls-ca-file", "",
+		"File containing the CA certificates clients must present a certificate signed by. Enables mutual TLS.")
+	cmd.Flags().DurationVar(&op.shutdownTimeout, "shutdown-timeout", defaultShutdownTimeout,
+		"How long in-flight evaluations may take to complete after SIGTERM or SIGINT before the server is stopped.")
+	cmd.Flags().IntVar(&op.metricsPort, "metrics-port", defaultMetricsPort,
+		"The port serving Prometheus metrics on /metrics. Zero disables the metrics server.")
+	cmd.Flags().StringSliceVar(&op.metricsAllowlist, "metrics-allowlist", nil,
+		"IP addresses and CIDR ranges allowed to scrape the metrics. All clients are allowed if empty.")
+	cmd.Flags().Int64Var(&op.maxResourceListBytes, "max-resource-list-bytes", defaultMaxResourceListBytes,
+		"Maximum size of the ResourceList a function is evaluated with, and of its output and log. Zero disables the limit.")
+	cmd.Flags().IntVar(&op.maxConcurrentEvaluations, "max-concurrent-evaluations", defaultMaxConcurrentEvaluations,
+		"Maximum number of function evaluations running at the same time.")
+	cmd.Flags().DurationVar(&op.queueTimeout, "queue-timeout", defaultQueueTimeout,
+		"How long an evaluation waits when --max-concurrent-evaluations are running before it is rejected. Zero waits until the request is cancelled.")
+	cmd.Flags().StringVar(&op.envFile, "env-file", "",
+		"File with KEY=VALUE lines setting environment variables of every function evaluation.")
+	cmd.Flags().BoolVar(&op.inheritEnv, "inherit-env", true,
+		"Pass the environment of the server to the functions.")
+	cmd.Flags().StringVar(&op.workDir, "work-dir", "",
+		"Working directory of the functions, created if missing. %TEMP% and $TMPDIR expand to the directory for temporary files. Defaults to the working directory of the server. Requests may only run functions in directories within it. Ignored with --sandbox-tmpfs.")
+	cmd.Flags().Float64Var(&op.rateLimit, "rate-limit", 0,
+		"Maximum number of function evaluations per second, enforced before --max-concurrent-evaluations. Zero disables the limit.")
+	cmd.Flags().IntVar(&op.rateBurst, "rate-burst", defaultRateBurst,
+		"Number of function evaluations which may exceed --rate-limit at once.")
+	cmd.Flags().StringVar(&op.rateLimitOverrides, "rate-limit-overrides", "",
+		"YAML file mapping image names to the rate and burst of their evaluations, overriding --rate-limit and --rate-burst.")
+	cmd.Flags().DurationVar(&op.keepaliveIdle, "grpc-keepalive-idle", defaultKeepaliveIdle,
+		"How long a client connection without streams is kept open. Zero keeps idle connections open.")
+	cmd.Flags().DurationVar(&op.keepaliveInterval, "grpc-keepalive-interval", defaultKeepaliveInterval,
+		"How long a client connection may be inactive before the server pings the client.")
+	cmd.Flags().DurationVar(&op.keepaliveTimeout, "grpc-keepalive-timeout", defaultKeepaliveTimeout,
+		"How long the server waits for a ping to be acknowledged before it closes the connection and cancels its evaluations.")
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "unexpected error: %v\n", err)
 		os.Exit(1)
@@ -53,53 +108,447 @@ func main() {
 }
 
 type options struct {
-	port       int
-	entrypoint []string
+	port                     int
+	entrypoint               []string
+	sandboxTmpfs             bool
+	sandboxTmpfsSizeBytes    int64
+	jobRetention             time.Duration
+	functionTimeout          time.Duration
+	tlsCertFile              string
+	tlsKeyFile               string
+	tlsCAFile                string
+	shutdownTimeout          time.Duration
+	metricsPort              int
+	metricsAllowlist         []string
+	maxResourceListBytes     int64
+	maxConcurrentEvaluations int
+	queueTimeout             time.Duration
+	envFile                  string
+	inheritEnv               bool
+	workDir                  string
+	rateLimit                float64
+	rateBurst                int
+	rateLimitOverrides       string
+	keepaliveIdle            time.Duration
+	keepaliveInterval        time.Duration
+	keepaliveTimeout         time.Duration
 }
 
 func (o *options) run() error {
+	if o.functionTimeout < 0 {
+		return fmt.Errorf("--function-timeout must not be negative, got %v", o.functionTimeout)
+	}
+	if o.shutdownTimeout < 0 {
+		return fmt.Errorf("--shutdown-timeout must not be negative, got %v", o.shutdownTimeout)
+	}
+	if o.maxResourceListBytes < 0 {
+		return fmt.Errorf("--max-resource-list-bytes must not be negative, got %d", o.maxResourceListBytes)
+	}
+	if o.maxConcurrentEvaluations <= 0 {
+		return fmt.Errorf("--max-concurrent-evaluations must be positive, got %d", o.maxConcurrentEvaluations)
+	}
+	if o.queueTimeout < 0 {
+		return fmt.Errorf("--queue-timeout must not be negative, got %v", o.queueTimeout)
+	}
+	keepaliveParams, err := keepaliveParameters(o.keepaliveIdle, o.keepaliveInterval, o.keepaliveTimeout)
+	if err != nil {
+		return err
+	}
+	globalRateLimit := rateLimit{Rate: o.rateLimit, Burst: o.rateBurst}
+	if err := globalRateLimit.validate(); err != nil {
+		return fmt.Errorf("invalid --rate-limit or --rate-burst: %w", err)
+	}
+	var tmpfsSizeBytes int64
+	if o.sandboxTmpfs {
+		if o.sandboxTmpfsSizeBytes <= 0 {
+			return fmt.Errorf("--sandbox-tmpfs-size-bytes must be positive, got %d", o.sandboxTmpfsSizeBytes)
+		}
+		if tmpfsSupported {
+			tmpfsSizeBytes = o.sandboxTmpfsSizeBytes
+		} else {
+			klog.Warning("--sandbox-tmpfs is not supported on this platform; functions will run without a tmpfs sandbox")
+		}
+	}
+
+	var env []string
+	if o.envFile != "" {
+		var err error
+		if env, err = parseEnvFile(o.envFile); err != nil {
+			return err
+		}
+	}
+
+	var workDir string
+	if o.workDir != "" {
+		var err error
+		if workDir, err = prepareWorkDir(o.workDir); err != nil {
+			return err
+		}
+	}
+
+	var rateLimiter *requestRateLimiter
+	if o.rateLimit > 0 || o.rateLimitOverrides != "" {
+		var overrides map[string]rateLimit
+		if o.rateLimitOverrides != "" {
+			var err error
+			if overrides, err = parseRateLimitOverrides(o.rateLimitOverrides); err != nil {
+				return err
+			}
+		}
+		rateLimiter = newRequestRateLimiter(globalRateLimit, overrides)
+	}
+
+	tlsConfig, err := serverTLSConfig(o.tlsCertFile, o.tlsKeyFile, o.tlsCAFile)
+	if err != nil {
+		return err
+	}
+
+	allowlist, err := parseAllowlist(o.metricsAllowlist)
+	if err != nil {
+		return err
+	}
+	metrics, err := newFunctionMetrics(prometheus.DefaultRegisterer)
+	if err != nil {
+		return err
+	}
+
 	address := fmt.Sprintf(":%d", o.port)
 	lis, err := net.Listen("tcp", address)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
+	if o.metricsPort != 0 {
+		metricsAddress := fmt.Sprintf(":%d", o.metricsPort)
+		metricsLis, err := net.Listen("tcp", metricsAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen for metrics: %w", err)
+		}
+		metricsServer := newMetricsServer(metricsAddress, prometheus.DefaultGatherer, allowlist)
+		go func() {
+			if err := metricsServer.Serve(metricsLis); err != nil && err != http.ErrServerClosed {
+				klog.Errorf("Metrics server failed: %v", err)
+			}
+		}()
+		klog.Infof("Serving metrics on %s", metricsAddress)
+	}
+
 	evaluator := &singleFunctionEvaluator{
-		entrypoint: o.entrypoint,
+		entrypoint:  o.entrypoint,
+		results:     newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		tmpfsSize:   tmpfsSizeBytes,
+		jobs:        newJobTracker(o.jobRetention),
+		timeout:     o.functionTimeout,
+		metrics:     metrics,
+		maxBytes:    o.maxResourceListBytes,
+		limiter:     newEvaluationLimiter(o.maxConcurrentEvaluations, o.queueTimeout),
+		env:         env,
+		cleanEnv:    !o.inheritEnv,
+		workDir:     workDir,
+		rateLimiter: rateLimiter,
 	}
 
-	klog.Infof("Listening on %s", address)
+	switch {
+	case tlsConfig == nil:
+		klog.Infof("Listening on %s", address)
+	case tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert:
+		klog.Infof("Listening on %s with mutual TLS", address)
+	default:
+		klog.Infof("Listening on %s with TLS", address)
+	}
 
 	// Start the gRPC server
-	server := grpc.NewServer()
+	server := newGRPCServer(evaluator, tlsConfig, keepaliveParams)
+	return serveUntilSignalled(server, lis, o.shutdownTimeout)
+}
+
+// newGRPCServer returns a gRPC server serving the evaluator and the health
+// service. If tlsConfig is nil, the server accepts plaintext connections. The
+// keepalive parameters detect dead clients, whose evaluations are cancelled.
+func newGRPCServer(evaluator pb.FunctionEvaluatorServer, tlsConfig *tls.Config, kp keepalive.ServerParameters) *grpc.Server {
+	opts := []grpc.ServerOption{grpc.KeepaliveParams(kp)}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(opts...)
 	pb.RegisterFunctionEvaluatorServer(server, evaluator)
 	healthService := NewHealthChecker()
 	grpc_health_v1.RegisterHealthServer(server, healthService)
-
-	if err := server.Serve(lis); err != nil {
-		return fmt.Errorf("server failed: %w", err)
-	}
-	return nil
+	return server
 }
 
 type singleFunctionEvaluator struct {
 	pb.UnimplementedFunctionEvaluatorServer
 
 	entrypoint []string
+	// results caches evaluation results of requests with an idempotency key.
+	results *resultCache
+	// inflight deduplicates concurrent evaluations of requests with the same
+	// idempotency key.
+	inflight singleflight.Group
+	// tmpfsSize is the size of the tmpfs mounted as the working directory of
+	// the function. Zero disables the tmpfs.
+	tmpfsSize int64
+	// jobs tracks asynchronous evaluations.
+	jobs *jobTracker
+	// timeout bounds the duration of a function evaluation. Zero disables
+	// the timeout.
+	timeout time.Duration
+	// metrics instruments the evaluations. Nil disables the metrics.
+	metrics *functionMetrics
+	// maxBytes bounds the size of the ResourceList in the request, and of the
+	// output and log of the function. Zero disables the limits.
+	maxBytes int64
+	// limiter bounds the number of concurrent evaluations. Nil disables the
+	// limit.
+	limiter *evaluationLimiter
+	// env holds KEY=VALUE environment variables set for every evaluation.
+	env []string
+	// cleanEnv starts functions without the environment of the server.
+	cleanEnv bool
+	// workDir is the working directory of the functions. Empty uses the
+	// working directory of the server. The tmpfs sandbox takes precedence.
+	workDir string
+	// rateLimiter throttles the evaluations before they wait for the
+	// concurrency limit. Nil disables the rate limit.
+	rateLimiter *requestRateLimiter
 }
 
 func (e *singleFunctionEvaluator) EvaluateFunction(ctx context.Context, req *pb.EvaluateFunctionRequest) (*pb.EvaluateFunctionResponse, error) {
-	var stdout, stderr bytes.Buffer
+	return e.evaluateFunction(ctx, req, nil)
+}
+
+// EvaluateFunctionStream evaluates the function like EvaluateFunction, but
+// sends the lines of the function log as they are written before sending the
+// response.
+func (e *singleFunctionEvaluator) EvaluateFunctionStream(req *pb.EvaluateFunctionRequest, stream pb.FunctionEvaluator_EvaluateFunctionStreamServer) error {
+	res, err := e.evaluateFunction(stream.Context(), req, streamLogLine(stream))
+	if err != nil {
+		return err
+	}
+	return stream.Send(&pb.EvaluateFunctionStreamEvent{Response: res})
+}
+
+// evaluateFunction evaluates the function, passing the lines of its log to
+// onLog if set. Results cached for the idempotency key of the request are
+// returned without streaming their log, as are the results of an evaluation
+// shared with a concurrent request with the same key.
+func (e *singleFunctionEvaluator) evaluateFunction(ctx context.Context, req *pb.EvaluateFunctionRequest, onLog func(line []byte) error) (*pb.EvaluateFunctionResponse, error) {
+	// Reject oversized requests before hashing them for the idempotency key.
+	if size := int64(len(req.ResourceList)); e.maxBytes > 0 && size > e.maxBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "ResourceList for function %q is %d bytes, exceeding the limit of %d bytes", req.Image, size, e.maxBytes)
+	}
+
+	key := req.IdempotencyKey
+	if key == "" {
+		return e.evaluateLimited(ctx, req, onLog)
+	}
+
+	// Look up cached results before waiting for the limits, so that retries
+	// are answered without queueing.
+	digest, err := requestDigest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request for function %q: %s", req.Image, err)
+	}
+	if res, ok, err := e.cachedResult(req, key, digest); ok || err != nil {
+		return res, err
+	}
+
+	// Concurrent requests with the same key and digest, such as a retry of a
+	// request still being evaluated, share a single evaluation.
+	flight := key + "/" + digest
+	for {
+		ch := e.inflight.DoChan(flight, func() (interface{}, error) {
+			if res, ok, err := e.cachedResult(req, key, digest); ok || err != nil {
+				return &sharedEvaluation{res: res, err: err}, nil
+			}
+			res, err := e.evaluateLimited(ctx, req, onLog)
+			if err == nil && ctx.Err() == nil {
+				e.results.put(key, digest, res)
+			}
+			return &sharedEvaluation{res: res, err: err, canceled: ctx.Err() != nil}, nil
+		})
+
+		select {
+		case r := <-ch:
+			shared := r.Val.(*sharedEvaluation)
+			if r.Shared && shared.canceled && ctx.Err() == nil {
+				// The request running the evaluation was canceled; evaluate
+				// the function again.
+				continue
+			}
+			return shared.res, shared.err
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// sharedEvaluation is the outcome of an evaluation shared by concurrent
+// requests with the same idempotency key.
+type sharedEvaluation struct {
+	res *pb.EvaluateFunctionResponse
+	err error
+	// canceled is set if the request running the evaluation was canceled.
+	canceled bool
+}
+
+// cachedResult returns the result cached for the idempotency key, if any. A
+// key cached for a request with a different digest is rejected.
+func (e *singleFunctionEvaluator) cachedResult(req *pb.EvaluateFunctionRequest, key, digest string) (*pb.EvaluateFunctionResponse, bool, error) {
+	res, cached, ok := e.results.get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	if cached != digest {
+		return nil, false, status.Errorf(codes.InvalidArgument, "Idempotency key %q was used by a different request", key)
+	}
+	klog.Infof("Returning cached result of %q for idempotency key %q", req.Image, key)
+	return res, true, nil
+}
+
+// evaluateLimited evaluates the function once the rate and concurrency limits
+// allow it.
+func (e *singleFunctionEvaluator) evaluateLimited(ctx context.Context, req *pb.EvaluateFunctionRequest, onLog func(line []byte) error) (res *pb.EvaluateFunctionResponse, err error) {
+	if e.rateLimiter != nil {
+		throttled, err := e.rateLimiter.wait(ctx, req.Image)
+		if e.metrics != nil {
+			e.metrics.rateLimit(req.Image, throttled, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if e.limiter != nil {
+		// Only evaluations which find no free slot are counted as queued.
+		release, ok := e.limiter.tryAcquire()
+		if !ok {
+			var waited func(error)
+			if e.metrics != nil {
+				waited = e.metrics.wait(req.Image)
+			}
+			release, err = e.limiter.acquire(ctx)
+			if waited != nil {
+				waited(err)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		defer release()
+	}
+
+	if e.metrics != nil {
+		done := e.metrics.start(req.Image)
+		defer func() { done(err) }()
+	}
+
+	workDir := e.workDir
+	if req.WorkDir != "" {
+		if workDir, err = resolveRequestWorkDir(e.workDir, req.WorkDir); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid working directory for function %q: %s", req.Image, err)
+		}
+	}
+	for key := range req.EnvOverrides {
+		if !validEnvKey(key) {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid environment variable name %q for function %q", key, req.Image)
+		}
+	}
+	return e.evaluate(ctx, req, workDir, onLog)
+}
+
+// AsyncEvaluateFunction starts evaluating the function in the background and
+// returns the id of the job tracking the evaluation.
+func (e *singleFunctionEvaluator) AsyncEvaluateFunction(ctx context.Context, req *pb.EvaluateFunctionRequest) (*pb.AsyncEvaluationJob, error) {
+	job, err := e.jobs.start(func(ctx context.Context) (*pb.EvaluateFunctionResponse, error) {
+		return e.EvaluateFunction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	klog.Infof("Started asynchronous evaluation %s of %q", job.id, req.Image)
+	return &pb.AsyncEvaluationJob{JobId: job.id}, nil
+}
+
+func (e *singleFunctionEvaluator) GetEvaluationStatus(ctx context.Context, req *pb.GetEvaluationStatusRequest) (*pb.EvaluationStatus, error) {
+	job, err := e.jobs.get(req.JobId)
+	if err != nil {
+		return nil, err
+	}
+	return job.status(), nil
+}
+
+func (e *singleFunctionEvaluator) CancelEvaluation(ctx context.Context, req *pb.CancelEvaluationRequest) (*pb.CancelEvaluationResponse, error) {
+	if err := e.jobs.cancel(req.JobId); err != nil {
+		return nil, err
+	}
+	return &pb.CancelEvaluationResponse{}, nil
+}
+
+// evaluate runs the function in workDir, the validated working directory of
+// the request.
+func (e *singleFunctionEvaluator) evaluate(ctx context.Context, req *pb.EvaluateFunctionRequest, workDir string, onLog func(line []byte) error) (*pb.EvaluateFunctionResponse, error) {
+	// Porch does not set a deadline, so bound the evaluation here to keep a
+	// hung function from blocking the gRPC worker.
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	stdout := limitedBuffer{limit: e.maxBytes}
+	stderr := limitedBuffer{limit: e.maxBytes}
 	cmd := exec.CommandContext(ctx, e.entrypoint[0], e.entrypoint[1:]...)
 	cmd.Stdin = bytes.NewReader(req.ResourceList)
+	cmd.Dir = workDir
+	if e.cleanEnv || len(e.env) > 0 || len(req.EnvOverrides) > 0 {
+		var base []string
+		if !e.cleanEnv {
+			base = os.Environ()
+		}
+		cmd.Env = functionEnv(base, e.env, req.EnvOverrides)
+	}
 	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
 
+	if e.tmpfsSize > 0 {
+		dir, cleanup, err := newTmpfsSandbox(e.tmpfsSize)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to create sandbox for function %q: %s", req.Image, err)
+		}
+		// Unmounted once the function exits.
+		defer cleanup()
+		cmd.Dir = dir
+	}
+
+	partialLog := newRingBuffer(partialLogBytes)
+	var streamer *lineStreamer
+	if onLog != nil {
+		streamer = newLineStreamer(onLog)
+		cmd.Stderr = io.MultiWriter(&stderr, partialLog, streamer)
+	} else {
+		cmd.Stderr = io.MultiWriter(&stderr, partialLog)
+	}
+
+	// Run returns once the function exits and its output is drained, so the
+	// partial stderr of a function killed by the deadline is available.
 	err := cmd.Run()
+	if streamer != nil {
+		streamer.Close()
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, status.Errorf(codes.DeadlineExceeded, "Function %q did not complete within the deadline (%s)", req.Image, stderr.String())
+	}
 	var exitErr *exec.ExitError
 	if err != nil && !errors.As(err, &exitErr) {
 		return nil, status.Errorf(codes.Internal, "Failed to execute function %q: %s (%s)", req.Image, err, stderr.String())
 	}
+	if stdout.exceeded() {
+		return nil, status.Errorf(codes.ResourceExhausted, "Output of function %q is %d bytes, exceeding the limit of %d bytes", req.Image, stdout.written, e.maxBytes)
+	}
+	if stderr.exceeded() {
+		return nil, status.Errorf(codes.ResourceExhausted, "Log of function %q is %d bytes, exceeding the limit of %d bytes", req.Image, stderr.written, e.maxBytes)
+	}
 
 	outbytes := stdout.Bytes()
 	klog.Infof("Evaluated %q: stdout length: %d\nstderr:\n%v", req.Image, len(outbytes), stderr.String())
@@ -107,6 +556,7 @@ func (e *singleFunctionEvaluator) EvaluateFunction(ctx context.Context, req *pb.
 	return &pb.EvaluateFunctionResponse{
 		ResourceList: outbytes,
 		Log:          stderr.Bytes(),
+		PartialLog:   partialLog.Bytes(),
 	}, nil
 }
 
@@ -0,0 +1,154 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEvaluateFunctionTimeout(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+
+	for _, tc := range []struct {
+		name       string
+		entrypoint []string
+		wantLog    string
+	}{
+		{
+			name:       "sleep",
+			entrypoint: []string{"sleep", "60"},
+		},
+		{
+			name:       "partial log",
+			entrypoint: []string{"sh", "-c", "echo partial log >&2; exec sleep 60"},
+			wantLog:    "partial log",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator := &singleFunctionEvaluator{
+				entrypoint: tc.entrypoint,
+				results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+				jobs:       newJobTracker(defaultJobRetention),
+				timeout:    timeout,
+			}
+
+			start := time.Now()
+			_, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"})
+			elapsed := time.Since(start)
+
+			if got, want := status.Code(err), codes.DeadlineExceeded; got != want {
+				t.Fatalf("EvaluateFunction returned %v (%v); want code %s", got, err, want)
+			}
+			if elapsed < timeout || elapsed > timeout+5*time.Second {
+				t.Errorf("EvaluateFunction returned after %v; want about %v", elapsed, timeout)
+			}
+			message := status.Convert(err).Message()
+			if !strings.Contains(message, "test-function") {
+				t.Errorf("error %q does not name the function image", message)
+			}
+			if !strings.Contains(message, tc.wantLog) {
+				t.Errorf("error %q does not contain the function log %q", message, tc.wantLog)
+			}
+		})
+	}
+}
+
+func TestEvaluateFunctionWithinTimeout(t *testing.T) {
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"cat"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+		timeout:    defaultFunctionTimeout,
+	}
+
+	res, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{
+		ResourceList: []byte("resources"),
+		Image:        "test-function",
+	})
+	if err != nil {
+		t.Fatalf("EvaluateFunction failed: %v", err)
+	}
+	if got, want := string(res.ResourceList), "resources"; got != want {
+		t.Errorf("EvaluateFunction returned %q; want %q", got, want)
+	}
+}
+
+func TestEvaluateFunctionSizeLimits(t *testing.T) {
+	const maxBytes = 16
+
+	for _, tc := range []struct {
+		name         string
+		entrypoint   []string
+		resourceList string
+		wantCode     codes.Code
+		wantMessage  string
+	}{
+		{
+			name:         "within limits",
+			entrypoint:   []string{"cat"},
+			resourceList: "resources",
+			wantCode:     codes.OK,
+		},
+		{
+			name:         "resource list too large",
+			entrypoint:   []string{"cat"},
+			resourceList: strings.Repeat("r", maxBytes+1),
+			wantCode:     codes.InvalidArgument,
+			wantMessage:  "17 bytes, exceeding the limit of 16 bytes",
+		},
+		{
+			name:         "output too large",
+			entrypoint:   []string{"sh", "-c", "head -c 1024 /dev/zero"},
+			resourceList: "resources",
+			wantCode:     codes.ResourceExhausted,
+			wantMessage:  "1024 bytes, exceeding the limit of 16 bytes",
+		},
+		{
+			name:         "log too large",
+			entrypoint:   []string{"sh", "-c", "head -c 1024 /dev/zero >&2"},
+			resourceList: "resources",
+			wantCode:     codes.ResourceExhausted,
+			wantMessage:  "1024 bytes, exceeding the limit of 16 bytes",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator := &singleFunctionEvaluator{
+				entrypoint: tc.entrypoint,
+				results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+				jobs:       newJobTracker(defaultJobRetention),
+				timeout:    defaultFunctionTimeout,
+				maxBytes:   maxBytes,
+			}
+
+			_, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{
+				ResourceList: []byte(tc.resourceList),
+				Image:        "test-function",
+			})
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("EvaluateFunction returned %v (%v); want code %s", got, err, want)
+			}
+			if message := status.Convert(err).Message(); !strings.Contains(message, tc.wantMessage) {
+				t.Errorf("error %q does not contain %q", message, tc.wantMessage)
+			}
+		})
+	}
+}
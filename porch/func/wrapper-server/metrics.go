@@ -0,0 +1,207 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultMetricsPort is the port serving the Prometheus metrics.
+	defaultMetricsPort = 9447
+
+	metricsNamespace = "wrapper_server"
+
+	statusSuccess = "success"
+	statusTimeout = "timeout"
+	statusError   = "error"
+	// statusRejected marks evaluations rejected by the concurrency limit.
+	statusRejected = "rejected"
+)
+
+// functionMetrics instruments the function evaluations of the server.
+type functionMetrics struct {
+	// latency observes the duration of evaluations by image and status.
+	latency *prometheus.HistogramVec
+	// evaluations counts the evaluations by image and status.
+	evaluations *prometheus.CounterVec
+	// inFlight is the number of evaluations in progress.
+	inFlight prometheus.Gauge
+	// queued is the number of evaluations waiting for the concurrency limit.
+	queued prometheus.Gauge
+	// throttled counts the evaluations by image delayed by the rate limit.
+	throttled *prometheus.CounterVec
+	// rateLimited counts the evaluations by image rejected by the rate limit.
+	rateLimited *prometheus.CounterVec
+}
+
+// newFunctionMetrics creates the function metrics and registers them with
+// registerer.
+func newFunctionMetrics(registerer prometheus.Registerer) (*functionMetrics, error) {
+	m := &functionMetrics{
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "function_evaluation_duration_seconds",
+			Help:      "Duration of KRM function evaluations in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"image", "status"}),
+		evaluations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "function_evaluations_total",
+			Help:      "Total number of KRM function evaluations.",
+		}, []string{"image", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "function_evaluations_in_flight",
+			Help:      "Number of KRM function evaluations in progress.",
+		}),
+		queued: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "function_evaluations_queued",
+			Help:      "Number of KRM function evaluations waiting for the concurrency limit.",
+		}),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "rate_limit_throttled_total",
+			Help:      "Total number of KRM function evaluations delayed by the rate limit.",
+		}, []string{"image"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "rate_limit_rejected_total",
+			Help:      "Total number of KRM function evaluations rejected by the rate limit.",
+		}, []string{"image"}),
+	}
+	for _, c := range []prometheus.Collector{m.latency, m.evaluations, m.inFlight, m.queued, m.throttled, m.rateLimited} {
+		if err := registerer.Register(c); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// start records the start of an evaluation of image. The returned function
+// must be called with the result of the evaluation once it completes.
+func (m *functionMetrics) start(image string) func(err error) {
+	m.inFlight.Inc()
+	start := time.Now()
+	return func(err error) {
+		m.inFlight.Dec()
+		s := evaluationStatus(err)
+		m.latency.WithLabelValues(image, s).Observe(time.Since(start).Seconds())
+		m.evaluations.WithLabelValues(image, s).Inc()
+	}
+}
+
+// wait records that an evaluation of image is waiting for the concurrency
+// limit. The returned function must be called once the wait is over, with the
+// error rejecting the evaluation, if any.
+func (m *functionMetrics) wait(image string) func(err error) {
+	m.queued.Inc()
+	return func(err error) {
+		m.queued.Dec()
+		if err != nil {
+			m.evaluations.WithLabelValues(image, statusRejected).Inc()
+		}
+	}
+}
+
+// rateLimit records the outcome of waiting for the rate limit of image.
+func (m *functionMetrics) rateLimit(image string, throttled bool, err error) {
+	if throttled {
+		m.throttled.WithLabelValues(image).Inc()
+	}
+	if err != nil {
+		m.rateLimited.WithLabelValues(image).Inc()
+	}
+}
+
+// evaluationStatus returns the status label of an evaluation which returned
+// err.
+func evaluationStatus(err error) string {
+	switch {
+	case err == nil:
+		return statusSuccess
+	case status.Code(err) == codes.DeadlineExceeded:
+		return statusTimeout
+	default:
+		return statusError
+	}
+}
+
+// parseAllowlist parses a list of IP addresses and CIDR ranges.
+func parseAllowlist(entries []string) ([]*net.IPNet, error) {
+	var allowlist []*net.IPNet
+	for _, entry := range entries {
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			allowlist = append(allowlist, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics allowlist entry %q: must be an IP address or a CIDR range", entry)
+		}
+		allowlist = append(allowlist, ipNet)
+	}
+	return allowlist, nil
+}
+
+// allowlistHandler only passes requests from clients with an address in
+// allowlist to next. An empty allowlist allows all clients.
+func allowlistHandler(allowlist []*net.IPNet, next http.Handler) http.Handler {
+	if len(allowlist) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			for _, ipNet := range allowlist {
+				if ipNet.Contains(ip) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+		klog.Warningf("Rejecting metrics request from %s", r.RemoteAddr)
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+}
+
+// newMetricsServer returns an HTTP server serving the metrics gathered by
+// gatherer on /metrics to the clients in allowlist.
+func newMetricsServer(address string, gatherer prometheus.Gatherer, allowlist []*net.IPNet) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", allowlistHandler(allowlist, promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})))
+	return &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// startTestMetricsServer serves the metrics of registry on a local port and
+// returns the URL of the metrics endpoint.
+func startTestMetricsServer(t *testing.T, registry *prometheus.Registry, allowlist []*net.IPNet) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := newMetricsServer(lis.Addr().String(), registry, allowlist)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(func() { _ = server.Close() })
+	return fmt.Sprintf("http://%s/metrics", lis.Addr())
+}
+
+func scrape(t *testing.T, url string) (int, string) {
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Failed to scrape metrics: %v", err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("Failed to read metrics: %v", err)
+	}
+	return res.StatusCode, string(body)
+}
+
+func TestEvaluateFunctionMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := newFunctionMetrics(registry)
+	if err != nil {
+		t.Fatalf("newFunctionMetrics failed: %v", err)
+	}
+
+	newEvaluator := func(entrypoint []string, timeout time.Duration) *singleFunctionEvaluator {
+		return &singleFunctionEvaluator{
+			entrypoint: entrypoint,
+			results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+			jobs:       newJobTracker(defaultJobRetention),
+			timeout:    timeout,
+			metrics:    metrics,
+		}
+	}
+	succeeding := newEvaluator([]string{"cat"}, defaultFunctionTimeout)
+	failing := newEvaluator([]string{"does-not-exist"}, defaultFunctionTimeout)
+	hanging := newEvaluator([]string{"sleep", "60"}, 100*time.Millisecond)
+
+	const successes = 3
+	for i := 0; i < successes; i++ {
+		if _, err := succeeding.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"}); err != nil {
+			t.Fatalf("EvaluateFunction failed: %v", err)
+		}
+	}
+	if _, err := failing.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"}); err == nil {
+		t.Fatalf("EvaluateFunction of a missing binary succeeded")
+	}
+	if _, err := hanging.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"}); err == nil {
+		t.Fatalf("EvaluateFunction of a hanging function succeeded")
+	}
+
+	code, body := scrape(t, startTestMetricsServer(t, registry, nil))
+	if code != http.StatusOK {
+		t.Fatalf("Scraping metrics returned status %d", code)
+	}
+	for _, want := range []string{
+		`wrapper_server_function_evaluations_total{image="test-function",status="success"} 3`,
+		`wrapper_server_function_evaluations_total{image="test-function",status="error"} 1`,
+		`wrapper_server_function_evaluations_total{image="test-function",status="timeout"} 1`,
+		`wrapper_server_function_evaluation_duration_seconds_count{image="test-function",status="success"} 3`,
+		`wrapper_server_function_evaluations_in_flight 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsAllowlist(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := newFunctionMetrics(registry); err != nil {
+		t.Fatalf("newFunctionMetrics failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		allowlist []string
+		wantCode  int
+	}{
+		{
+			name:     "empty",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "loopback address",
+			allowlist: []string{"127.0.0.1"},
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "loopback range",
+			allowlist: []string{"10.0.0.0/8", "127.0.0.0/8"},
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "other range",
+			allowlist: []string{"10.0.0.0/8", "::1"},
+			wantCode:  http.StatusForbidden,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			allowlist, err := parseAllowlist(tc.allowlist)
+			if err != nil {
+				t.Fatalf("parseAllowlist failed: %v", err)
+			}
+			if code, _ := scrape(t, startTestMetricsServer(t, registry, allowlist)); code != tc.wantCode {
+				t.Errorf("Scraping metrics returned status %d; want %d", code, tc.wantCode)
+			}
+		})
+	}
+
+	if _, err := parseAllowlist([]string{"not-an-address"}); err == nil {
+		t.Errorf("parseAllowlist accepted an invalid entry")
+	}
+}
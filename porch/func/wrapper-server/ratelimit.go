@@ -0,0 +1,138 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultRateBurst is the number of evaluations which may exceed the rate
+// limit at once.
+const defaultRateBurst = 10
+
+// rateLimit is a token bucket rate limit of function evaluations.
+type rateLimit struct {
+	// Rate is the number of evaluations per second. Zero disables the limit.
+	Rate float64 `json:"rate"`
+	// Burst is the number of evaluations which may exceed the rate at once.
+	Burst int `json:"burst"`
+}
+
+func (l rateLimit) validate() error {
+	if l.Rate < 0 {
+		return fmt.Errorf("rate must not be negative, got %g", l.Rate)
+	}
+	if l.Rate > 0 && l.Burst <= 0 {
+		return fmt.Errorf("burst must be positive, got %d", l.Burst)
+	}
+	return nil
+}
+
+// parseRateLimitOverrides reads a YAML file mapping image names to the rate
+// limits of their evaluations, such as:
+//
+//	gcr.io/kpt-fn/set-namespace:v0.2:
+//	  rate: 5
+//	  burst: 10
+func parseRateLimitOverrides(file string) (map[string]rateLimit, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limit overrides: %w", err)
+	}
+	overrides := map[string]rateLimit{}
+	if err := yaml.UnmarshalStrict(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit overrides %s: %w", file, err)
+	}
+	for image, limit := range overrides {
+		if err := limit.validate(); err != nil {
+			return nil, fmt.Errorf("invalid rate limit of %q in %s: %w", image, file, err)
+		}
+	}
+	return overrides, nil
+}
+
+// requestRateLimiter throttles evaluations with a token bucket per image
+// with a rate limit override and a token bucket shared by all other images.
+type requestRateLimiter struct {
+	// limiter is shared by the images without an override. Nil disables the
+	// limit.
+	limiter *rate.Limiter
+	// overrides holds the limiters of images with a rate limit override. A
+	// nil limiter disables the limit of the image.
+	overrides map[string]*rate.Limiter
+}
+
+func newRequestRateLimiter(limit rateLimit, overrides map[string]rateLimit) *requestRateLimiter {
+	l := &requestRateLimiter{
+		limiter:   newRateLimiter(limit),
+		overrides: map[string]*rate.Limiter{},
+	}
+	for image, override := range overrides {
+		l.overrides[image] = newRateLimiter(override)
+	}
+	return l
+}
+
+func newRateLimiter(limit rateLimit) *rate.Limiter {
+	if limit.Rate == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+}
+
+func (l *requestRateLimiter) limiterFor(image string) *rate.Limiter {
+	if limiter, ok := l.overrides[image]; ok {
+		return limiter
+	}
+	return l.limiter
+}
+
+// wait waits until the rate limit of image allows another evaluation, and
+// reports whether the evaluation was throttled. If the context is done
+// before, or its deadline is too close to wait, wait returns a
+// ResourceExhausted error with the delay after which to retry.
+func (l *requestRateLimiter) wait(ctx context.Context, image string) (throttled bool, err error) {
+	limiter := l.limiterFor(image)
+	if limiter == nil || limiter.Allow() {
+		return false, nil
+	}
+	if err := limiter.Wait(ctx); err != nil {
+		return true, rateLimitExceeded(image, limiter)
+	}
+	return true, nil
+}
+
+// rateLimitExceeded returns the error rejecting an evaluation of image,
+// carrying the delay until limiter allows the next evaluation as RetryInfo.
+func rateLimitExceeded(image string, limiter *rate.Limiter) error {
+	reservation := limiter.Reserve()
+	delay := reservation.Delay()
+	reservation.Cancel()
+
+	st := status.Newf(codes.ResourceExhausted, "Rate limit of %g evaluations per second of function %q exceeded; retry after %s", float64(limiter.Limit()), image, delay)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,205 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newRateLimitedEvaluator(t *testing.T, limiter *requestRateLimiter) (*singleFunctionEvaluator, *prometheus.Registry) {
+	registry := prometheus.NewRegistry()
+	metrics, err := newFunctionMetrics(registry)
+	if err != nil {
+		t.Fatalf("newFunctionMetrics failed: %v", err)
+	}
+	return &singleFunctionEvaluator{
+		entrypoint:  []string{"cat"},
+		results:     newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:        newJobTracker(defaultJobRetention),
+		timeout:     defaultFunctionTimeout,
+		metrics:     metrics,
+		rateLimiter: limiter,
+	}, registry
+}
+
+func TestRateLimitBurst(t *testing.T) {
+	const (
+		burst    = 5
+		requests = 20
+	)
+
+	// With one evaluation per second, only the burst completes within the
+	// deadline of the requests.
+	evaluator, registry := newRateLimitedEvaluator(t, newRequestRateLimiter(rateLimit{Rate: 1, Burst: burst}, nil))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			_, err := evaluator.EvaluateFunction(ctx, &pb.EvaluateFunctionRequest{Image: "test-function"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var succeeded, rejected int
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		st := status.Convert(err)
+		if st.Code() != codes.ResourceExhausted {
+			t.Errorf("EvaluateFunction returned %v; want ResourceExhausted", err)
+			continue
+		}
+		rejected++
+		var retryAfter time.Duration
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok {
+				retryAfter = info.RetryDelay.AsDuration()
+			}
+		}
+		if retryAfter <= 0 || retryAfter > time.Second {
+			t.Errorf("got retry delay %s; want a delay up to 1s", retryAfter)
+		}
+	}
+	if got, want := succeeded, burst; got != want {
+		t.Errorf("got %d successful evaluations, want %d", got, want)
+	}
+	if got, want := rejected, requests-burst; got != want {
+		t.Errorf("got %d rejected evaluations, want %d", got, want)
+	}
+
+	_, body := scrape(t, startTestMetricsServer(t, registry, nil))
+	for _, want := range []string{
+		`wrapper_server_rate_limit_throttled_total{image="test-function"} 15`,
+		`wrapper_server_rate_limit_rejected_total{image="test-function"} 15`,
+		`wrapper_server_function_evaluations_total{image="test-function",status="success"} 5`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	const requests = 5
+
+	// Evaluations exceeding the burst wait for the rate limit.
+	evaluator, registry := newRateLimitedEvaluator(t, newRequestRateLimiter(rateLimit{Rate: 20, Burst: 1}, nil))
+
+	start := time.Now()
+	for i := 0; i < requests; i++ {
+		if _, err := evaluator.EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{Image: "test-function"}); err != nil {
+			t.Fatalf("EvaluateFunction failed: %v", err)
+		}
+	}
+	if elapsed, min := time.Since(start), 150*time.Millisecond; elapsed < min {
+		t.Errorf("%d evaluations took %s; want at least %s", requests, elapsed, min)
+	}
+
+	_, body := scrape(t, startTestMetricsServer(t, registry, nil))
+	want := `wrapper_server_rate_limit_throttled_total{image="test-function"} 4`
+	if !strings.Contains(body, want) {
+		t.Errorf("metrics do not contain %q:\n%s", want, body)
+	}
+	if strings.Contains(body, "wrapper_server_rate_limit_rejected_total{") {
+		t.Errorf("metrics report rejected evaluations:\n%s", body)
+	}
+}
+
+func TestRateLimitOverrides(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "overrides.yaml")
+	overrides := `
+limited:
+  rate: 1
+  burst: 1
+unlimited:
+  rate: 0
+`
+	if err := os.WriteFile(file, []byte(overrides), 0644); err != nil {
+		t.Fatalf("Failed to write overrides: %v", err)
+	}
+	parsed, err := parseRateLimitOverrides(file)
+	if err != nil {
+		t.Fatalf("parseRateLimitOverrides failed: %v", err)
+	}
+	evaluator, _ := newRateLimitedEvaluator(t, newRequestRateLimiter(rateLimit{Rate: 1, Burst: 2}, parsed))
+
+	evaluate := func(image string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := evaluator.EvaluateFunction(ctx, &pb.EvaluateFunctionRequest{Image: image})
+		return err
+	}
+
+	for _, tc := range []struct {
+		image     string
+		successes int
+	}{
+		{image: "limited", successes: 1},
+		{image: "other", successes: 2},
+		{image: "unlimited", successes: 10},
+	} {
+		for i := 0; i < tc.successes; i++ {
+			if err := evaluate(tc.image); err != nil {
+				t.Fatalf("Evaluation %d of %q failed: %v", i, tc.image, err)
+			}
+		}
+		if tc.image == "unlimited" {
+			continue
+		}
+		if err := evaluate(tc.image); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("Evaluation of %q exceeding the rate limit returned %v; want ResourceExhausted", tc.image, err)
+		}
+	}
+}
+
+func TestParseRateLimitOverridesErrors(t *testing.T) {
+	for name, overrides := range map[string]string{
+		"negative rate": "image:\n  rate: -1\n  burst: 1\n",
+		"missing burst": "image:\n  rate: 1\n",
+		"unknown field": "image:\n  rate: 1\n  burst: 1\n  limit: 2\n",
+		"not a map":     "- image\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "overrides.yaml")
+			if err := os.WriteFile(file, []byte(overrides), 0644); err != nil {
+				t.Fatalf("Failed to write overrides: %v", err)
+			}
+			if _, err := parseRateLimitOverrides(file); err == nil {
+				t.Errorf("parseRateLimitOverrides accepted %q", overrides)
+			}
+		})
+	}
+}
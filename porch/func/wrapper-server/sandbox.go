@@ -0,0 +1,50 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+)
+
+// defaultSandboxTmpfsSizeBytes is the default size limit of the tmpfs mounted
+// as the working directory of functions.
+const defaultSandboxTmpfsSizeBytes = 100 * 1024 * 1024
+
+// newTmpfsSandbox creates a directory with a tmpfs of the given size mounted
+// on it, for use as the working directory of a function. The returned cleanup
+// function unmounts the tmpfs and removes the directory.
+func newTmpfsSandbox(sizeBytes int64) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "fn-sandbox-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create sandbox directory: %w", err)
+	}
+	if err := mountTmpfs(dir, sizeBytes); err != nil {
+		os.Remove(dir)
+		return "", nil, fmt.Errorf("failed to mount tmpfs at %q: %w", dir, err)
+	}
+	cleanup := func() {
+		if err := unmountTmpfs(dir); err != nil {
+			klog.Warningf("Failed to unmount sandbox tmpfs at %q: %v", dir, err)
+			return
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			klog.Warningf("Failed to remove sandbox directory %q: %v", dir, err)
+		}
+	}
+	return dir, cleanup, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"syscall"
+)
+
+const tmpfsSupported = true
+
+func mountTmpfs(dir string, sizeBytes int64) error {
+	// Functions must not be able to execute, or escalate privileges via,
+	// the files they write.
+	flags := uintptr(syscall.MS_NOEXEC | syscall.MS_NOSUID)
+	return syscall.Mount("tmpfs", dir, "tmpfs", flags, fmt.Sprintf("size=%d", sizeBytes))
+}
+
+func unmountTmpfs(dir string) error {
+	return syscall.Unmount(dir, 0)
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"k8s.io/klog/v2"
+)
+
+// defaultShutdownTimeout bounds how long in-flight evaluations may take to
+// complete once the server is asked to shut down.
+const defaultShutdownTimeout = 15 * time.Second
+
+// serveUntilSignalled serves on lis until SIGTERM or SIGINT is received and
+// then stops the server gracefully, letting in-flight evaluations complete.
+// If they don't complete within shutdownTimeout, the server is stopped
+// forcibly, cancelling them.
+func serveUntilSignalled(server *grpc.Server, lis net.Listener, shutdownTimeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(lis)
+	}()
+
+	select {
+	case err := <-served:
+		if err != nil {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+	// Restore the default signal handling, so that a second signal
+	// terminates the process right away.
+	stop()
+
+	klog.Infof("Received shutdown signal; waiting up to %v for in-flight evaluations to complete", shutdownTimeout)
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(shutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+		klog.Info("Server stopped gracefully")
+	case <-timer.C:
+		klog.Warningf("In-flight evaluations did not complete within %v; stopping the server", shutdownTimeout)
+		server.Stop()
+		<-stopped
+		klog.Info("Server stopped")
+	}
+
+	// Serve returns nil once the server is stopped.
+	if err := <-served; err != nil {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
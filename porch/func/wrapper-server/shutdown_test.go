@@ -0,0 +1,98 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := lis.Addr().String()
+	server := newGRPCServer(&singleFunctionEvaluator{
+		// Keeps the evaluation in flight while the server shuts down.
+		entrypoint: []string{"sh", "-c", "sleep 1; exec cat"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+		timeout:    defaultFunctionTimeout,
+	}, nil, defaultKeepalive)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- serveUntilSignalled(server, lis, 10*time.Second)
+	}()
+
+	// Once the server answers, it has installed its signal handler.
+	if err := checkHealth(address, insecure.NewCredentials()); err != nil {
+		t.Fatalf("health check failed: %v", err)
+	}
+
+	cc, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial %s: %v", address, err)
+	}
+	defer cc.Close()
+
+	type result struct {
+		res *pb.EvaluateFunctionResponse
+		err error
+	}
+	evaluated := make(chan result, 1)
+	go func() {
+		res, err := pb.NewFunctionEvaluatorClient(cc).EvaluateFunction(context.Background(), &pb.EvaluateFunctionRequest{
+			ResourceList: []byte("resources"),
+			Image:        "test-function",
+		})
+		evaluated <- result{res: res, err: err}
+	}()
+
+	// Let the evaluation start before asking the server to shut down.
+	time.Sleep(300 * time.Millisecond)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send SIGTERM: %v", err)
+	}
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("server did not stop cleanly: %v", err)
+		}
+	case <-time.After(20 * time.Second):
+		t.Fatalf("server did not stop after SIGTERM")
+	}
+
+	r := <-evaluated
+	if r.err != nil {
+		t.Fatalf("in-flight EvaluateFunction failed: %v", r.err)
+	}
+	if got, want := string(r.res.ResourceList), "resources"; got != want {
+		t.Errorf("EvaluateFunction returned %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testCertificate is a certificate and key generated for a test.
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCertificate creates a certificate signed by parent, or a
+// self-signed CA certificate if parent is nil.
+func newTestCertificate(t *testing.T, serial int64, parent *testCertificate, usage x509.ExtKeyUsage) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "wrapper-server-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCertificate) keyPair(t *testing.T) tls.Certificate {
+	pair, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+	if err != nil {
+		t.Fatalf("failed to load key pair: %v", err)
+	}
+	return pair
+}
+
+func writeTestFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// startTestServer serves the wrapper-server gRPC services on a local port and
+// returns its address.
+func startTestServer(t *testing.T, tlsConfig *tls.Config) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := newGRPCServer(&singleFunctionEvaluator{
+		entrypoint: []string{"cat"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+	}, tlsConfig, defaultKeepalive)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func checkHealth(address string, creds credentials.TransportCredentials) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cc, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	_, err = grpc_health_v1.NewHealthClient(cc).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	return err
+}
+
+func TestServerTLS(t *testing.T) {
+	ca := newTestCertificate(t, 1, nil, 0)
+	serverCert := newTestCertificate(t, 2, ca, x509.ExtKeyUsageServerAuth)
+	clientCert := newTestCertificate(t, 3, ca, x509.ExtKeyUsageClientAuth)
+
+	dir := t.TempDir()
+	certFile := writeTestFile(t, dir, "tls.crt", serverCert.certPEM)
+	keyFile := writeTestFile(t, dir, "tls.key", serverCert.keyPEM)
+	caFile := writeTestFile(t, dir, "ca.crt", ca.certPEM)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	t.Run("plaintext", func(t *testing.T) {
+		tlsConfig, err := serverTLSConfig("", "", "")
+		if err != nil {
+			t.Fatalf("serverTLSConfig failed: %v", err)
+		}
+		if tlsConfig != nil {
+			t.Fatalf("serverTLSConfig returned a TLS configuration without certificate")
+		}
+		address := startTestServer(t, tlsConfig)
+		if err := checkHealth(address, insecure.NewCredentials()); err != nil {
+			t.Errorf("plaintext health check failed: %v", err)
+		}
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		tlsConfig, err := serverTLSConfig(certFile, keyFile, "")
+		if err != nil {
+			t.Fatalf("serverTLSConfig failed: %v", err)
+		}
+		address := startTestServer(t, tlsConfig)
+		if err := checkHealth(address, credentials.NewTLS(&tls.Config{RootCAs: roots})); err != nil {
+			t.Errorf("TLS health check failed: %v", err)
+		}
+	})
+
+	t.Run("mTLS", func(t *testing.T) {
+		tlsConfig, err := serverTLSConfig(certFile, keyFile, caFile)
+		if err != nil {
+			t.Fatalf("serverTLSConfig failed: %v", err)
+		}
+		address := startTestServer(t, tlsConfig)
+		if err := checkHealth(address, credentials.NewTLS(&tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{clientCert.keyPair(t)},
+		})); err != nil {
+			t.Errorf("mTLS health check failed: %v", err)
+		}
+		if err := checkHealth(address, credentials.NewTLS(&tls.Config{RootCAs: roots})); err == nil {
+			t.Errorf("health check without client certificate succeeded; expected mTLS to reject it")
+		}
+	})
+}
+
+func TestServerTLSConfigErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name                      string
+		certFile, keyFile, caFile string
+	}{
+		{name: "cert without key", certFile: "tls.crt"},
+		{name: "key without cert", keyFile: "tls.key"},
+		{name: "CA without cert", caFile: "ca.crt"},
+		{name: "missing files", certFile: "missing.crt", keyFile: "missing.key"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := serverTLSConfig(tc.certFile, tc.keyFile, tc.caFile); err == nil {
+				t.Errorf("serverTLSConfig(%q, %q, %q) succeeded; expected error", tc.certFile, tc.keyFile, tc.caFile)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// expandWorkDir replaces the %TEMP% and $TMPDIR tokens in dir with the
+// directory for temporary files.
+func expandWorkDir(dir string) string {
+	tmp := os.TempDir()
+	return strings.NewReplacer("%TEMP%", tmp, "$TMPDIR", tmp).Replace(dir)
+}
+
+// prepareWorkDir expands the tokens in dir and creates the directory if it
+// does not exist. It returns the absolute path of the directory.
+func prepareWorkDir(dir string) (string, error) {
+	dir, err := filepath.Abs(expandWorkDir(dir))
+	if err != nil {
+		return "", fmt.Errorf("invalid --work-dir: %w", err)
+	}
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		klog.Warningf("Working directory %s does not exist; creating it", dir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("cannot create working directory: %w", err)
+		}
+	case err != nil:
+		return "", fmt.Errorf("cannot access working directory: %w", err)
+	case !info.IsDir():
+		return "", fmt.Errorf("working directory %s is not a directory", dir)
+	}
+	return dir, nil
+}
+
+// resolveRequestWorkDir checks the working directory requested for an
+// evaluation and returns it with symlinks resolved. The directory must be
+// within the working directory configured by --work-dir, base, and requests
+// are rejected if there is none.
+func resolveRequestWorkDir(base, dir string) (string, error) {
+	if base == "" {
+		return "", fmt.Errorf("the server does not accept working directories without --work-dir")
+	}
+	if !filepath.IsAbs(dir) {
+		return "", fmt.Errorf("working directory %q is not an absolute path", dir)
+	}
+	resolvedBase, err := filepath.EvalSymlinks(base)
+	if err != nil {
+		return "", fmt.Errorf("cannot access configured working directory: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(dir))
+	if err != nil {
+		return "", fmt.Errorf("cannot access working directory: %w", err)
+	}
+	rel, err := filepath.Rel(resolvedBase, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("working directory %q is not within %s", dir, base)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", fmt.Errorf("cannot access working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("working directory %q is not a directory", dir)
+	}
+	return resolved, nil
+}
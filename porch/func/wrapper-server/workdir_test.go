@@ -0,0 +1,126 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/GoogleContainerTools/kpt/porch/func/evaluator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// evaluatePwd evaluates a function printing its working directory.
+func evaluatePwd(t *testing.T, workDir string, req *pb.EvaluateFunctionRequest) (string, error) {
+	evaluator := &singleFunctionEvaluator{
+		entrypoint: []string{"sh", "-c", "pwd -P"},
+		results:    newResultCache(defaultIdempotencyTTL, defaultIdempotencyCacheSize),
+		jobs:       newJobTracker(defaultJobRetention),
+		timeout:    defaultFunctionTimeout,
+		workDir:    workDir,
+	}
+	res, err := evaluator.EvaluateFunction(context.Background(), req)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(res.ResourceList)), nil
+}
+
+// realPath resolves symlinks in dir, as `pwd -P` does.
+func realPath(t *testing.T, dir string) string {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatalf("Failed to resolve %s: %v", dir, err)
+	}
+	return resolved
+}
+
+func TestWorkDir(t *testing.T) {
+	configured := filepath.Join(t.TempDir(), "functions")
+	dir, err := prepareWorkDir(configured)
+	if err != nil {
+		t.Fatalf("prepareWorkDir failed: %v", err)
+	}
+	if info, err := os.Stat(configured); err != nil || !info.IsDir() {
+		t.Fatalf("prepareWorkDir did not create %s: %v", configured, err)
+	}
+
+	got, err := evaluatePwd(t, dir, &pb.EvaluateFunctionRequest{Image: "test-function"})
+	if err != nil {
+		t.Fatalf("EvaluateFunction failed: %v", err)
+	}
+	if want := realPath(t, configured); got != want {
+		t.Errorf("function working directory: got %q, want %q", got, want)
+	}
+}
+
+func TestRequestWorkDir(t *testing.T) {
+	configured := t.TempDir()
+	requested := filepath.Join(configured, "job")
+	if err := os.Mkdir(requested, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", requested, err)
+	}
+
+	got, err := evaluatePwd(t, configured, &pb.EvaluateFunctionRequest{Image: "test-function", WorkDir: requested})
+	if err != nil {
+		t.Fatalf("EvaluateFunction failed: %v", err)
+	}
+	if want := realPath(t, requested); got != want {
+		t.Errorf("function working directory: got %q, want %q", got, want)
+	}
+
+	outside := t.TempDir()
+	escape := filepath.Join(configured, "escape")
+	if err := os.Symlink(outside, escape); err != nil {
+		t.Fatalf("Failed to create symlink %s: %v", escape, err)
+	}
+	for _, dir := range []string{
+		"relative/dir",
+		filepath.Join(requested, "missing"),
+		outside,
+		"/",
+		filepath.Join(requested, "..", ".."),
+		escape,
+	} {
+		_, err := evaluatePwd(t, configured, &pb.EvaluateFunctionRequest{Image: "test-function", WorkDir: dir})
+		if got, want := status.Code(err), codes.InvalidArgument; got != want {
+			t.Errorf("EvaluateFunction with work dir %q returned %v (%v); want code %s", dir, got, err, want)
+		}
+	}
+
+	// Without --work-dir, no working directory may be requested.
+	_, err = evaluatePwd(t, "", &pb.EvaluateFunctionRequest{Image: "test-function", WorkDir: requested})
+	if got, want := status.Code(err), codes.InvalidArgument; got != want {
+		t.Errorf("EvaluateFunction without --work-dir returned %v (%v); want code %s", got, err, want)
+	}
+}
+
+func TestExpandWorkDir(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	for _, dir := range []string{"%TEMP%/functions", "$TMPDIR/functions"} {
+		if got, want := expandWorkDir(dir), filepath.Join(os.TempDir(), "functions"); got != want {
+			t.Errorf("expandWorkDir(%q) = %q; want %q", dir, got, want)
+		}
+	}
+	if got, want := expandWorkDir("/var/lib/functions"), "/var/lib/functions"; got != want {
+		t.Errorf("expandWorkDir(%q) = %q; want %q", "/var/lib/functions", got, want)
+	}
+}
@@ -157,6 +157,18 @@ function main() {
   # Repository CRD
   cp "./api/porchconfig/v1alpha1/config.porch.kpt.dev_repositories.yaml" \
      "${DESTINATION}/0-repositories.yaml"
+  # TransitionWebhook CRD
+  cp "./api/porchconfig/v1alpha1/config.porch.kpt.dev_transitionwebhooks.yaml" \
+     "${DESTINATION}/0-transitionwebhooks.yaml"
+  # PricingConfig CRD
+  cp "./api/porchconfig/v1alpha1/config.porch.kpt.dev_pricingconfigs.yaml" \
+     "${DESTINATION}/0-pricingconfigs.yaml"
+  # LintPolicy CRD
+  cp "./api/porchconfig/v1alpha1/config.porch.kpt.dev_lintpolicies.yaml" \
+     "${DESTINATION}/0-lintpolicies.yaml"
+  # NotificationConfig CRD
+  cp "./api/porchconfig/v1alpha1/config.porch.kpt.dev_notificationconfigs.yaml" \
+     "${DESTINATION}/0-notificationconfigs.yaml"
 
   # Porch Deployment Config
   cp ${PORCH_DIR}/config/deploy/*.yaml "${PORCH_DIR}/config/deploy/Kptfile" "${DESTINATION}"
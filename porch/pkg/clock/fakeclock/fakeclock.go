@@ -0,0 +1,144 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakeclock provides a Clock whose time only moves when advanced.
+package fakeclock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/pkg/clock"
+)
+
+// FakeClock is a clock.Clock for tests. Its time only moves forward when
+// Advance is called, which fires the timers and tickers that became due.
+type FakeClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	waiters []*waiter
+}
+
+var _ clock.Clock = &FakeClock{}
+
+// waiter is a pending timer, or a ticker if period is positive.
+type waiter struct {
+	at      time.Time
+	period  time.Duration
+	ch      chan time.Time
+	stopped bool
+}
+
+// NewFakeClock returns a fake clock set to initial.
+func NewFakeClock(initial time.Time) *FakeClock {
+	return &FakeClock{now: initial}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.addWaiter(d, 0).ch
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock.NewTicker")
+	}
+	return &fakeTicker{clock: c, waiter: c.addWaiter(d, d)}
+}
+
+func (c *FakeClock) addWaiter(d, period time.Duration) *waiter {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	w := &waiter{
+		at:     c.now.Add(d),
+		period: period,
+		// Like the channels of the time package, ticks are dropped rather
+		// than blocking the clock when the receiver falls behind.
+		ch: make(chan time.Time, 1),
+	}
+	if d <= 0 && period == 0 {
+		w.ch <- c.now
+		return w
+	}
+	c.waiters = append(c.waiters, w)
+	return w
+}
+
+// Advance moves the clock forward by d, firing all timers and tickers due
+// at the new time. A ticker due several times fires once.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.stopped {
+			continue
+		}
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		select {
+		case w.ch <- c.now:
+		default:
+		}
+		if w.period > 0 {
+			for !w.at.After(c.now) {
+				w.at = w.at.Add(w.period)
+			}
+			pending = append(pending, w)
+		}
+	}
+	c.waiters = pending
+}
+
+// HasWaiters returns true if any timer or ticker is waiting for the clock to
+// advance. Tests use it to wait until a component started waiting.
+func (c *FakeClock) HasWaiters() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, w := range c.waiters {
+		if !w.stopped {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTicker struct {
+	clock  *FakeClock
+	waiter *waiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.waiter.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	t.waiter.stopped = true
+}
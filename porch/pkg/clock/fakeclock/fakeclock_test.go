@@ -0,0 +1,76 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakeclock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	after := clock.After(time.Minute)
+	ticker := clock.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	clock.Advance(5 * time.Second)
+	if got, want := clock.Now(), start.Add(5*time.Second); !got.Equal(want) {
+		t.Errorf("Now() after Advance: got %v, want %v", got, want)
+	}
+	select {
+	case <-after:
+		t.Errorf("After fired before its duration elapsed")
+	case <-ticker.C():
+		t.Errorf("ticker fired before its period elapsed")
+	default:
+	}
+
+	// Ticks due several times while advancing are delivered once.
+	clock.Advance(25 * time.Second)
+	select {
+	case got := <-ticker.C():
+		if want := start.Add(30 * time.Second); !got.Equal(want) {
+			t.Errorf("tick: got %v, want %v", got, want)
+		}
+	default:
+		t.Errorf("ticker did not fire after its period elapsed")
+	}
+	select {
+	case <-ticker.C():
+		t.Errorf("ticker fired more than once for a single Advance")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case <-after:
+	default:
+		t.Errorf("After did not fire after its duration elapsed")
+	}
+
+	ticker.Stop()
+	<-ticker.C()
+	clock.Advance(time.Minute)
+	select {
+	case <-ticker.C():
+		t.Errorf("stopped ticker fired")
+	default:
+	}
+	if clock.HasWaiters() {
+		t.Errorf("expected no waiters after timers fired and ticker stopped")
+	}
+}
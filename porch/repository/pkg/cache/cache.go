@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"time"
 
 	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/git"
@@ -43,11 +44,26 @@ type Cache struct {
 	cacheDir           string
 	credentialResolver repository.CredentialResolver
 	userInfoProvider   repository.UserInfoProvider
+	resetTimeout       time.Duration
+	pruneStaleRefs     bool
+	maxPackages        int
+	verifyIntegrity    bool
 }
 
 type CacheOptions struct {
 	CredentialResolver repository.CredentialResolver
 	UserInfoProvider   repository.UserInfoProvider
+	// CircuitBreakerResetTimeout configures the git repository circuit breakers.
+	CircuitBreakerResetTimeout time.Duration
+	// PruneStaleRefs deletes stale draft and proposed branches when a git
+	// repository is first opened by the cache.
+	PruneStaleRefs bool
+	// MaxPackagesPerRepo limits the number of directories the directory
+	// pattern of a git repository may match.
+	MaxPackagesPerRepo int
+	// VerifyIntegrity re-hashes the blobs read from git repositories as
+	// package resources and fails reads of corrupted objects.
+	VerifyIntegrity bool
 }
 
 func NewCache(cacheDir string, opts CacheOptions) *Cache {
@@ -56,6 +72,10 @@ func NewCache(cacheDir string, opts CacheOptions) *Cache {
 		cacheDir:           cacheDir,
 		credentialResolver: opts.CredentialResolver,
 		userInfoProvider:   opts.UserInfoProvider,
+		resetTimeout:       opts.CircuitBreakerResetTimeout,
+		pruneStaleRefs:     opts.PruneStaleRefs,
+		maxPackages:        opts.MaxPackagesPerRepo,
+		verifyIntegrity:    opts.VerifyIntegrity,
 	}
 }
 
@@ -101,8 +121,12 @@ func (c *Cache) OpenRepository(ctx context.Context, repositorySpec *configapi.Re
 		cr := c.repositories[key]
 		if cr == nil {
 			if r, err := git.OpenRepository(ctx, repositorySpec.Name, repositorySpec.Namespace, gitSpec, filepath.Join(c.cacheDir, "git"), git.GitRepositoryOptions{
-				CredentialResolver: c.credentialResolver,
-				UserInfoProvider:   c.userInfoProvider,
+				CredentialResolver:         c.credentialResolver,
+				UserInfoProvider:           c.userInfoProvider,
+				CircuitBreakerResetTimeout: c.resetTimeout,
+				PruneStaleRefs:             c.pruneStaleRefs,
+				MaxPackagesPerRepo:         c.maxPackages,
+				VerifyIntegrity:            c.verifyIntegrity,
 			}); err != nil {
 				return nil, err
 			} else {
@@ -117,6 +141,16 @@ func (c *Cache) OpenRepository(ctx context.Context, repositorySpec *configapi.Re
 	}
 }
 
+// SyncRepository synchronizes the cached content of the repository with the
+// underlying repository immediately, rather than at the next polling interval.
+func (c *Cache) SyncRepository(ctx context.Context, repositorySpec *configapi.Repository) error {
+	cr, err := c.OpenRepository(ctx, repositorySpec)
+	if err != nil {
+		return err
+	}
+	return cr.refresh(ctx)
+}
+
 func isPackageContent(content configapi.RepositoryContent) bool {
 	switch content {
 	case "PackageRevision":
@@ -26,6 +26,53 @@ type cachedDraft struct {
 }
 
 var _ repository.PackageDraft = &cachedDraft{}
+var _ repository.RenderRecorder = &cachedDraft{}
+var _ repository.ChangelogRecorder = &cachedDraft{}
+var _ repository.RejectionRecorder = &cachedDraft{}
+var _ repository.GenerationRecorder = &cachedDraft{}
+var _ repository.LabelRecorder = &cachedDraft{}
+
+func (cd *cachedDraft) RecordRender(ctx context.Context, status repository.RenderStatus) error {
+	if recorder, ok := cd.PackageDraft.(repository.RenderRecorder); ok {
+		return recorder.RecordRender(ctx, status)
+	}
+	return nil
+}
+
+func (cd *cachedDraft) RecordChangelog(ctx context.Context, entry string) error {
+	if recorder, ok := cd.PackageDraft.(repository.ChangelogRecorder); ok {
+		return recorder.RecordChangelog(ctx, entry)
+	}
+	return nil
+}
+
+func (cd *cachedDraft) RecordRejection(ctx context.Context, reason string) error {
+	if recorder, ok := cd.PackageDraft.(repository.RejectionRecorder); ok {
+		return recorder.RecordRejection(ctx, reason)
+	}
+	return nil
+}
+
+func (cd *cachedDraft) RecordGeneration(ctx context.Context, generation int64) error {
+	if recorder, ok := cd.PackageDraft.(repository.GenerationRecorder); ok {
+		return recorder.RecordGeneration(ctx, generation)
+	}
+	return nil
+}
+
+func (cd *cachedDraft) RecordObservedGeneration(ctx context.Context, generation int64) error {
+	if recorder, ok := cd.PackageDraft.(repository.GenerationRecorder); ok {
+		return recorder.RecordObservedGeneration(ctx, generation)
+	}
+	return nil
+}
+
+func (cd *cachedDraft) RecordLabels(ctx context.Context, labels map[string]string) error {
+	if recorder, ok := cd.PackageDraft.(repository.LabelRecorder); ok {
+		return recorder.RecordLabels(ctx, labels)
+	}
+	return nil
+}
 
 func (cd *cachedDraft) Close(ctx context.Context) (repository.PackageRevision, error) {
 	if closed, err := cd.PackageDraft.Close(ctx); err != nil {
@@ -0,0 +1,138 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/klog/v2"
+)
+
+// labelIndex is an inverted index of the labels of package revisions, used to
+// select the package revisions matching a label selector without reading all
+// of them.
+type labelIndex struct {
+	// revisions maps label key/value pairs to the names of the package
+	// revisions with the label.
+	revisions map[labelPair]map[string]bool
+	// labels records the labels of the indexed package revisions.
+	labels map[string]map[string]string
+	// unindexed records the package revisions whose labels couldn't be read.
+	// The index can't answer queries while there are any.
+	unindexed map[string]bool
+}
+
+type labelPair struct {
+	key, value string
+}
+
+func newLabelIndex() *labelIndex {
+	return &labelIndex{
+		revisions: map[labelPair]map[string]bool{},
+		labels:    map[string]map[string]string{},
+		unindexed: map[string]bool{},
+	}
+}
+
+// buildLabelIndex indexes the labels of the package revisions.
+func buildLabelIndex(packages []repository.PackageRevision) *labelIndex {
+	index := newLabelIndex()
+	for _, p := range packages {
+		revisionLabels, ok := packageLabels(p)
+		index.add(p.Name(), revisionLabels, ok)
+	}
+	return index
+}
+
+// packageLabels returns the labels of the package revision, and false if they
+// can't be read.
+func packageLabels(p repository.PackageRevision) (map[string]string, bool) {
+	pr, err := p.GetPackageRevision()
+	if err != nil {
+		klog.Warningf("cannot index labels of package revision %q: %v", p.Name(), err)
+		return nil, false
+	}
+	return pr.Labels, true
+}
+
+// add indexes the labels of the package revision, replacing the labels it
+// was indexed with before. Package revisions whose labels couldn't be read
+// (ok is false) are recorded as unindexed.
+func (i *labelIndex) add(name string, revisionLabels map[string]string, ok bool) {
+	i.remove(name)
+	if !ok {
+		i.unindexed[name] = true
+		return
+	}
+	for k, v := range revisionLabels {
+		pair := labelPair{key: k, value: v}
+		names := i.revisions[pair]
+		if names == nil {
+			names = map[string]bool{}
+			i.revisions[pair] = names
+		}
+		names[name] = true
+	}
+	i.labels[name] = revisionLabels
+}
+
+func (i *labelIndex) remove(name string) {
+	for k, v := range i.labels[name] {
+		pair := labelPair{key: k, value: v}
+		delete(i.revisions[pair], name)
+		if len(i.revisions[pair]) == 0 {
+			delete(i.revisions, pair)
+		}
+	}
+	delete(i.labels, name)
+	delete(i.unindexed, name)
+}
+
+// candidates returns the names of the package revisions which may match the
+// selector: the intersection of the revisions matching each equality or set
+// requirement of the selector. Returns false if the selector has no such
+// requirement, or the labels of some revisions couldn't be indexed, and the
+// index cannot narrow down the revisions.
+func (i *labelIndex) candidates(selector labels.Selector) (map[string]bool, bool) {
+	if len(i.unindexed) > 0 {
+		return nil, false
+	}
+	requirements, selectable := selector.Requirements()
+	if !selectable {
+		// The selector matches nothing.
+		return map[string]bool{}, true
+	}
+
+	var result map[string]bool
+	for _, r := range requirements {
+		switch r.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+		default:
+			continue
+		}
+
+		matching := map[string]bool{}
+		for value := range r.Values() {
+			for name := range i.revisions[labelPair{key: r.Key(), value: value}] {
+				if result == nil || result[name] {
+					matching[name] = true
+				}
+			}
+		}
+		result = matching
+	}
+	return result, result != nil
+}
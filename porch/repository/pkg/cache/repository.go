@@ -16,6 +16,7 @@ package cache
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
 )
 
@@ -35,6 +37,8 @@ type cachedRepository struct {
 
 	mutex          sync.Mutex
 	cachedPackages []repository.PackageRevision
+	// labels indexes the labels of cachedPackages; nil when cachedPackages is nil.
+	labels *labelIndex
 	// TODO: Currently we support repositories with homogenous content (only packages xor functions). Model this more optimally?
 	cachedFunctions []repository.Function
 }
@@ -54,6 +58,9 @@ func newRepository(id string, repo repository.Repository) *cachedRepository {
 
 var _ repository.Repository = &cachedRepository{}
 var _ repository.FunctionRepository = &cachedRepository{}
+var _ repository.LabelIndexedRepository = &cachedRepository{}
+var _ repository.DefaultBranchRepository = &cachedRepository{}
+var _ repository.FilteredRepository = &cachedRepository{}
 
 func (r *cachedRepository) ListPackageRevisions(ctx context.Context) ([]repository.PackageRevision, error) {
 	packages, err := r.getPackages(ctx, false)
@@ -63,6 +70,63 @@ func (r *cachedRepository) ListPackageRevisions(ctx context.Context) ([]reposito
 	return packages, nil
 }
 
+// ListPackageRevisionsMatching returns the cached package revisions which may
+// match the selector, as determined by the label index.
+func (r *cachedRepository) ListPackageRevisionsMatching(ctx context.Context, selector labels.Selector) ([]repository.PackageRevision, error) {
+	packages, err := r.getPackages(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.labels == nil {
+		return packages, nil
+	}
+	names, ok := r.labels.candidates(selector)
+	if !ok {
+		return r.cachedPackages, nil
+	}
+	var matching []repository.PackageRevision
+	for _, p := range r.cachedPackages {
+		if names[p.Name()] {
+			matching = append(matching, p)
+		}
+	}
+	return matching, nil
+}
+
+// ListPackageRevisionsFiltered returns the cached package revisions which
+// match the filter.
+func (r *cachedRepository) ListPackageRevisionsFiltered(ctx context.Context, filter repository.ListPackageRevisionFilter) ([]repository.PackageRevision, error) {
+	packages, err := r.getPackages(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []repository.PackageRevision
+	for _, p := range packages {
+		pr, err := p.GetPackageRevision()
+		if err != nil {
+			return nil, err
+		}
+		if filter.Matches(pr) {
+			matching = append(matching, p)
+		}
+	}
+	return matching, nil
+}
+
+// DefaultBranch returns the default branch detected by the underlying
+// repository, or "" if it does not detect one.
+func (r *cachedRepository) DefaultBranch() string {
+	if d, ok := r.repo.(repository.DefaultBranchRepository); ok {
+		return d.DefaultBranch()
+	}
+	return ""
+}
+
 func (r *cachedRepository) ListFunctions(ctx context.Context) ([]repository.Function, error) {
 	functions, err := r.getFunctions(ctx, false)
 	if err != nil {
@@ -87,9 +151,11 @@ func (r *cachedRepository) getPackages(ctx context.Context, forceRefresh bool) (
 			return nil, err
 		}
 		packages = p
+		index := buildLabelIndex(p)
 
 		r.mutex.Lock()
 		r.cachedPackages = p
+		r.labels = index
 		r.mutex.Unlock()
 	}
 
@@ -150,9 +216,15 @@ func (r *cachedRepository) UpdatePackage(ctx context.Context, old repository.Pac
 }
 
 func (r *cachedRepository) update(closed repository.PackageRevision) {
+	closedLabels, ok := packageLabels(closed)
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	if r.labels != nil {
+		r.labels.add(closed.Name(), closedLabels, ok)
+	}
+
 	for i, cached := range r.cachedPackages {
 		if cached.Name() == closed.Name() {
 			if cached == closed {
@@ -175,6 +247,7 @@ func (r *cachedRepository) DeletePackageRevision(ctx context.Context, old reposi
 	r.mutex.Lock()
 	// TODO: Do something more efficient than a full cache flush
 	r.cachedPackages = nil
+	r.labels = nil
 	r.mutex.Unlock()
 
 	return nil
@@ -206,10 +279,18 @@ func (r *cachedRepository) pollOnce(ctx context.Context) {
 	ctx, span := tracer.Start(ctx, "Repository.pollOnce", trace.WithAttributes())
 	defer span.End()
 
+	if err := r.refresh(ctx); err != nil {
+		klog.Warningf("error polling repo %s: %v", r.id, err)
+	}
+}
+
+// refresh reloads the cached packages and functions from the repository.
+func (r *cachedRepository) refresh(ctx context.Context) error {
 	if _, err := r.getPackages(ctx, true); err != nil {
-		klog.Warningf("error polling repo packages %s: %v", r.id, err)
+		return fmt.Errorf("error refreshing repo packages: %w", err)
 	}
 	if _, err := r.getFunctions(ctx, true); err != nil {
-		klog.Warningf("error polling repo functions %s: %v", r.id, err)
+		return fmt.Errorf("error refreshing repo functions: %w", err)
 	}
+	return nil
 }
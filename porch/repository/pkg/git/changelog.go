@@ -0,0 +1,87 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// The changelog entry of a published package revision is stored as trailers
+// of the commit message of the commit approving the package, one trailer per
+// line of the entry:
+//
+//	Porch-Changelog-Package: <package path>
+//	Porch-Changelog-Entry: <line>
+//
+// The package path guards against reading the trailers of a commit which
+// approved a different package, e.g. at the head of the main branch.
+const (
+	changelogPackageTrailer = "Porch-Changelog-Package"
+	changelogEntryTrailer   = "Porch-Changelog-Entry"
+)
+
+// appendChangelogTrailers appends the changelog trailers to the commit message.
+func appendChangelogTrailers(message, pkgPath, entry string) string {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return message
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(message, "\n"))
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "%s: %s\n", changelogPackageTrailer, pkgPath)
+	for _, line := range strings.Split(entry, "\n") {
+		fmt.Fprintf(&b, "%s: %s\n", changelogEntryTrailer, strings.TrimSpace(line))
+	}
+	return b.String()
+}
+
+// parseChangelogTrailers returns the changelog entry of the package recorded
+// in the commit message, or "" if the message doesn't record one.
+func parseChangelogTrailers(message, pkgPath string) string {
+	var lines []string
+	matched := false
+	for _, line := range strings.Split(message, "\n") {
+		key, value, found := cut(line, ": ")
+		if !found {
+			// Tools rewriting the message may strip the trailing space of
+			// trailers recording empty lines of the entry.
+			key = strings.TrimSuffix(line, ":")
+			value = ""
+		}
+		switch key {
+		case changelogPackageTrailer:
+			matched = value == pkgPath
+			lines = nil
+		case changelogEntryTrailer:
+			if matched {
+				lines = append(lines, value)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// loadChangelog returns the changelog entry of the package recorded in the commit.
+func (r *gitRepository) loadChangelog(commitHash plumbing.Hash, pkgPath string) (string, error) {
+	commit, err := r.repo.CommitObject(commitHash)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve package commit %s: %w", commitHash, err)
+	}
+	return parseChangelogTrailers(commit.Message, pkgPath), nil
+}
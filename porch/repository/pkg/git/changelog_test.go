@@ -0,0 +1,91 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+)
+
+func TestChangelogTrailers(t *testing.T) {
+	const entry = "Add the namespace.\n\nFix labels."
+	message := appendChangelogTrailers("Approve catalog/bucket", "catalog/bucket", entry)
+
+	if got := parseChangelogTrailers(message, "catalog/bucket"); got != entry {
+		t.Errorf("changelog entry: got %q, want %q", got, entry)
+	}
+	if got := parseChangelogTrailers(message, "catalog/other"); got != "" {
+		t.Errorf("changelog entry of other package: got %q, want none", got)
+	}
+	if got, want := appendChangelogTrailers("Approve catalog/bucket", "catalog/bucket", " "), "Approve catalog/bucket"; got != want {
+		t.Errorf("message with empty changelog entry: got %q, want %q", got, want)
+	}
+}
+
+func TestApproveDraftChangelog(t *testing.T) {
+	tempdir := t.TempDir()
+	tarfile := filepath.Join("testdata", "drafts-repository.tar")
+	_, address := ServeGitRepository(t, tarfile, tempdir)
+
+	const entry = "Fixed the namespace.\nContact: team@kpt.dev"
+	ctx := context.Background()
+	git, err := OpenRepository(ctx, "changelog", "default", &configapi.GitRepository{
+		Repo:      address,
+		Branch:    "main",
+		Directory: "/",
+	}, tempdir, GitRepositoryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to open Git repository loaded from %q: %v", tarfile, err)
+	}
+
+	revisions, err := git.ListPackageRevisions(ctx)
+	if err != nil {
+		t.Fatalf("ListPackageRevisions failed: %v", err)
+	}
+	bucket := findPackage(t, revisions, "changelog:bucket:v1")
+
+	update, err := git.UpdatePackage(ctx, bucket)
+	if err != nil {
+		t.Fatalf("UpdatePackage failed: %v", err)
+	}
+	if err := update.(repository.ChangelogRecorder).RecordChangelog(ctx, entry); err != nil {
+		t.Fatalf("RecordChangelog failed: %v", err)
+	}
+	update.UpdateLifecycle(ctx, v1alpha1.PackageRevisionLifecyclePublished)
+	if _, err := update.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// The changelog entry is read back from the repository.
+	revisions, err = git.ListPackageRevisions(ctx)
+	if err != nil {
+		t.Fatalf("ListPackageRevisions failed: %v", err)
+	}
+	rev, err := findPackage(t, revisions, "changelog:bucket:v1").GetPackageRevision()
+	if err != nil {
+		t.Fatalf("GetPackageRevision failed: %v", err)
+	}
+	if got := rev.Annotations[v1alpha1.ChangelogAnnotationPrefix+"v1"]; got != entry {
+		t.Errorf("changelog annotation: got %q, want %q", got, entry)
+	}
+	if got := rev.Spec.ChangelogEntry; got != entry {
+		t.Errorf("changelog entry: got %q, want %q", got, entry)
+	}
+}
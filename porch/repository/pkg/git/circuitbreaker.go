@@ -0,0 +1,192 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+const (
+	// DefaultCircuitBreakerResetTimeout is the time the circuit stays open
+	// before a trial request is allowed through.
+	DefaultCircuitBreakerResetTimeout = 30 * time.Second
+
+	// circuitBreakerThreshold is the number of consecutive failures which
+	// open the circuit.
+	circuitBreakerThreshold = 5
+)
+
+type CircuitBreakerState int
+
+const (
+	// CircuitBreakerClosed is the normal state; operations are executed.
+	CircuitBreakerClosed CircuitBreakerState = iota
+	// CircuitBreakerOpen fails all operations fast without contacting the remote.
+	CircuitBreakerOpen
+	// CircuitBreakerHalfOpen allows a single trial operation to test whether the remote recovered.
+	CircuitBreakerHalfOpen
+)
+
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case CircuitBreakerClosed:
+		return "Closed"
+	case CircuitBreakerOpen:
+		return "Open"
+	case CircuitBreakerHalfOpen:
+		return "HalfOpen"
+	default:
+		return fmt.Sprintf("CircuitBreakerState(%d)", int(s))
+	}
+}
+
+// RetryableError is returned when an operation was not attempted because the
+// circuit is open. The caller may retry the operation later.
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// CircuitBreaker guards operations against a remote git server. After
+// circuitBreakerThreshold consecutive failures the circuit opens and
+// operations fail immediately until resetTimeout elapses, after which a single
+// trial operation is allowed to determine whether the remote recovered.
+type CircuitBreaker struct {
+	mutex        sync.Mutex
+	state        CircuitBreakerState
+	failures     int
+	openedAt     time.Time
+	resetTimeout time.Duration
+	trial        bool // a trial operation is in progress in the HalfOpen state
+
+	now func() time.Time
+}
+
+func NewCircuitBreaker(resetTimeout time.Duration) *CircuitBreaker {
+	if resetTimeout <= 0 {
+		resetTimeout = DefaultCircuitBreakerResetTimeout
+	}
+	return &CircuitBreaker{
+		state:        CircuitBreakerClosed,
+		resetTimeout: resetTimeout,
+		now:          time.Now,
+	}
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitBreakerState {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	return cb.state
+}
+
+// Do executes the operation if the circuit allows it, and records its outcome.
+func (cb *CircuitBreaker) Do(operation func() error) error {
+	if err := cb.allow(); err != nil {
+		return err
+	}
+	err := operation()
+	cb.record(err)
+	return err
+}
+
+func (cb *CircuitBreaker) allow() error {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	switch cb.state {
+	case CircuitBreakerOpen:
+		if cb.now().Sub(cb.openedAt) < cb.resetTimeout {
+			return &RetryableError{Err: fmt.Errorf("git circuit breaker is open after %d consecutive failures", cb.failures)}
+		}
+		cb.state = CircuitBreakerHalfOpen
+		cb.trial = true
+		return nil
+
+	case CircuitBreakerHalfOpen:
+		if cb.trial {
+			return &RetryableError{Err: fmt.Errorf("git circuit breaker is half-open; trial operation in progress")}
+		}
+		cb.trial = true
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+func (cb *CircuitBreaker) record(err error) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.trial = false
+
+	if err == nil {
+		cb.state = CircuitBreakerClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitBreakerHalfOpen || cb.failures >= circuitBreakerThreshold {
+		cb.state = CircuitBreakerOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// isRemoteFailure reports whether the error of an operation against a remote
+// repository indicates that the remote is unavailable: the connection failed
+// or timed out, or the server responded with a 5xx status. Other errors, such
+// as rejected pushes and authentication failures, are answers of an available
+// remote and don't count towards opening the circuit.
+func isRemoteFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var unexpected *plumbing.UnexpectedError
+	if errors.As(err, &unexpected) {
+		err = unexpected.Err
+	}
+
+	var httpErr *githttp.Err
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode() >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
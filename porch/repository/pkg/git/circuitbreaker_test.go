@@ -0,0 +1,196 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+var errRemote = errors.New("remote unavailable")
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestCircuitBreaker() (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cb := NewCircuitBreaker(30 * time.Second)
+	cb.now = clock.Now
+	return cb, clock
+}
+
+func fail() error    { return errRemote }
+func succeed() error { return nil }
+
+func openCircuit(t *testing.T, cb *CircuitBreaker) {
+	t.Helper()
+	for i := 0; i < circuitBreakerThreshold; i++ {
+		if err := cb.Do(fail); err != errRemote {
+			t.Fatalf("Do() returned %v; want %v", err, errRemote)
+		}
+	}
+	if got, want := cb.State(), CircuitBreakerOpen; got != want {
+		t.Fatalf("circuit state: got %s, want %s", got, want)
+	}
+}
+
+func TestCircuitBreakerStaysClosedBelowThreshold(t *testing.T) {
+	cb, _ := newTestCircuitBreaker()
+
+	for i := 0; i < circuitBreakerThreshold-1; i++ {
+		cb.Do(fail)
+	}
+	if got, want := cb.State(), CircuitBreakerClosed; got != want {
+		t.Errorf("circuit state: got %s, want %s", got, want)
+	}
+
+	// Success resets the consecutive failure count.
+	cb.Do(succeed)
+	cb.Do(fail)
+	if got, want := cb.State(), CircuitBreakerClosed; got != want {
+		t.Errorf("circuit state: got %s, want %s", got, want)
+	}
+}
+
+func TestCircuitBreakerClosedToOpen(t *testing.T) {
+	cb, _ := newTestCircuitBreaker()
+	openCircuit(t, cb)
+
+	called := false
+	err := cb.Do(func() error {
+		called = true
+		return nil
+	})
+	if called {
+		t.Errorf("operation was executed while circuit is open")
+	}
+	var retryable *RetryableError
+	if !errors.As(err, &retryable) {
+		t.Errorf("Do() returned %v; want RetryableError", err)
+	}
+}
+
+func TestCircuitBreakerOpenToHalfOpen(t *testing.T) {
+	cb, clock := newTestCircuitBreaker()
+	openCircuit(t, cb)
+
+	clock.now = clock.now.Add(30 * time.Second)
+
+	// The trial operation observes the HalfOpen state; concurrent operations are rejected.
+	var trialState CircuitBreakerState
+	var concurrent error
+	if err := cb.Do(func() error {
+		trialState = cb.State()
+		concurrent = cb.Do(succeed)
+		return nil
+	}); err != nil {
+		t.Fatalf("trial operation failed: %v", err)
+	}
+	if got, want := trialState, CircuitBreakerHalfOpen; got != want {
+		t.Errorf("circuit state during trial: got %s, want %s", got, want)
+	}
+	var retryable *RetryableError
+	if !errors.As(concurrent, &retryable) {
+		t.Errorf("concurrent operation during trial returned %v; want RetryableError", concurrent)
+	}
+}
+
+func TestCircuitBreakerHalfOpenToClosed(t *testing.T) {
+	cb, clock := newTestCircuitBreaker()
+	openCircuit(t, cb)
+
+	clock.now = clock.now.Add(30 * time.Second)
+	if err := cb.Do(succeed); err != nil {
+		t.Fatalf("trial operation failed: %v", err)
+	}
+	if got, want := cb.State(), CircuitBreakerClosed; got != want {
+		t.Errorf("circuit state: got %s, want %s", got, want)
+	}
+}
+
+func TestCircuitBreakerHalfOpenToOpen(t *testing.T) {
+	cb, clock := newTestCircuitBreaker()
+	openCircuit(t, cb)
+
+	clock.now = clock.now.Add(30 * time.Second)
+	if err := cb.Do(fail); err != errRemote {
+		t.Fatalf("Do() returned %v; want %v", err, errRemote)
+	}
+	if got, want := cb.State(), CircuitBreakerOpen; got != want {
+		t.Errorf("circuit state: got %s, want %s", got, want)
+	}
+
+	// The reset timeout starts over after a failed trial.
+	clock.now = clock.now.Add(29 * time.Second)
+	var retryable *RetryableError
+	if err := cb.Do(succeed); !errors.As(err, &retryable) {
+		t.Errorf("Do() returned %v; want RetryableError", err)
+	}
+}
+
+func TestIsRemoteFailure(t *testing.T) {
+	statusErr := func(code int) error {
+		return plumbing.NewUnexpectedError(&githttp.Err{Response: &http.Response{StatusCode: code}})
+	}
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "success", err: nil, want: false},
+		{name: "already up to date", err: git.NoErrAlreadyUpToDate, want: false},
+		{name: "empty remote", err: transport.ErrEmptyRemoteRepository, want: false},
+		{name: "non-fast-forward push", err: git.ErrNonFastForwardUpdate, want: false},
+		{name: "required ref conflict", err: fmt.Errorf("remote ref refs/heads/main required to be abc but is def"), want: false},
+		{name: "authentication required", err: transport.ErrAuthenticationRequired, want: false},
+		{name: "authorization failed", err: transport.ErrAuthorizationFailed, want: false},
+		{name: "4xx status", err: statusErr(http.StatusConflict), want: false},
+		{name: "5xx status", err: statusErr(http.StatusServiceUnavailable), want: true},
+		{name: "dial error", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, want: true},
+		{name: "timeout", err: fmt.Errorf("fetch: %w", context.DeadlineExceeded), want: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isRemoteFailure(tc.err); got != tc.want {
+				t.Errorf("isRemoteFailure(%v): got %t, want %t", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRejectedPushesDontOpenCircuit(t *testing.T) {
+	cb, _ := newTestCircuitBreaker()
+	for i := 0; i < 2*circuitBreakerThreshold; i++ {
+		if err := doGuarded(cb, func() error { return git.ErrNonFastForwardUpdate }); err != git.ErrNonFastForwardUpdate {
+			t.Fatalf("doGuarded() returned %v; want %v", err, git.ErrNonFastForwardUpdate)
+		}
+	}
+	if got, want := cb.State(), CircuitBreakerClosed; got != want {
+		t.Errorf("circuit state: got %s, want %s", got, want)
+	}
+}
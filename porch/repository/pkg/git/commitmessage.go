@@ -0,0 +1,82 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// maxCommitMessageLength is the maximum length, in characters, of a commit
+// message rendered from the commit template of a repository.
+const maxCommitMessageLength = 500
+
+// commitMessageData holds the values available to commit templates.
+type commitMessageData struct {
+	PackageName    string
+	Revision       string
+	Lifecycle      string
+	User           string
+	Timestamp      string
+	ChangelogEntry string
+}
+
+// parseCommitTemplate parses the commit template of a repository.
+func parseCommitTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("commitTemplate").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// commitMessage returns the message of a commit to a package revision. It
+// renders the commit template of the repository, if any, or returns
+// defaultMessage.
+func (r *gitRepository) commitMessage(ctx context.Context, defaultMessage string, data commitMessageData) (string, error) {
+	if r.commitTemplate == nil {
+		return defaultMessage, nil
+	}
+
+	data.User = porchSignatureName
+	if r.userInfoProvider != nil {
+		if ui := r.userInfoProvider.GetUserInfo(ctx); ui != nil {
+			data.User = ui.Name
+		}
+	}
+	data.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	return renderCommitMessage(r.commitTemplate, data)
+}
+
+// renderCommitMessage renders the commit template and validates the length of
+// the message.
+func renderCommitMessage(tmpl *template.Template, data commitMessageData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("cannot render commit template: %w", err)
+	}
+	message := strings.TrimSpace(b.String())
+	if message == "" {
+		return "", fmt.Errorf("commit template rendered an empty commit message")
+	}
+	if n := utf8.RuneCountInString(message); n > maxCommitMessageLength {
+		return "", fmt.Errorf("commit template rendered a commit message of %d characters, exceeding the limit of %d", n, maxCommitMessageLength)
+	}
+	return message, nil
+}
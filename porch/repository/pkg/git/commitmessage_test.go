@@ -0,0 +1,90 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderCommitMessage(t *testing.T) {
+	data := commitMessageData{
+		PackageName:    "my-app",
+		Revision:       "v3",
+		Lifecycle:      "Published",
+		User:           "jane",
+		Timestamp:      "2022-05-01T12:00:00Z",
+		ChangelogEntry: "Bump replicas",
+	}
+
+	for _, tc := range []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "all variables",
+			template: "{{.Lifecycle}} {{.PackageName}} {{.Revision}}\n\n{{.ChangelogEntry}}\n\nBy {{.User}} at {{.Timestamp}}\n",
+			want:     "Published my-app v3\n\nBump replicas\n\nBy jane at 2022-05-01T12:00:00Z",
+		},
+		{
+			name:     "conditional",
+			template: "Update {{.PackageName}}{{if .ChangelogEntry}}: {{.ChangelogEntry}}{{end}}",
+			want:     "Update my-app: Bump replicas",
+		},
+		{
+			name:     "empty",
+			template: "{{if eq .Lifecycle \"Draft\"}}draft{{end}}  \n",
+			wantErr:  "empty commit message",
+		},
+		{
+			name:     "too long",
+			template: strings.Repeat("x", maxCommitMessageLength) + "{{.Revision}}",
+			wantErr:  "502 characters, exceeding the limit of 500",
+		},
+		{
+			name:     "unknown variable",
+			template: "{{.Branch}}",
+			wantErr:  "cannot render commit template",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := parseCommitTemplate(tc.template)
+			if err != nil {
+				t.Fatalf("parseCommitTemplate failed: %v", err)
+			}
+			got, err := renderCommitMessage(tmpl, data)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("renderCommitMessage returned %v; want error containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderCommitMessage failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("renderCommitMessage: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseCommitTemplateInvalid(t *testing.T) {
+	if _, err := parseCommitTemplate("{{.PackageName"); err == nil {
+		t.Errorf("parseCommitTemplate succeeded for an invalid template")
+	}
+}
@@ -28,18 +28,28 @@ import (
 )
 
 type gitPackageDraft struct {
-	parent    *gitRepository
-	path      string
-	revision  string
-	lifecycle v1alpha1.PackageRevisionLifecycle // New value of the package revision lifecycle
-	updated   time.Time
-	base      *plumbing.Reference // ref to the base of the package update commit chain (used for conditional push)
-	branch    BranchName          // name of the branch where the changes will be pushed
-	commit    plumbing.Hash       // Current HEAD of the package changes (commit sha)
-	tree      plumbing.Hash       // Cached tree of the package itself, some descendent of commit.Tree()
+	parent     *gitRepository
+	path       string
+	revision   string
+	lifecycle  v1alpha1.PackageRevisionLifecycle // New value of the package revision lifecycle
+	updated    time.Time
+	base       *plumbing.Reference      // ref to the base of the package update commit chain (used for conditional push)
+	branch     BranchName               // name of the branch where the changes will be pushed
+	commit     plumbing.Hash            // Current HEAD of the package changes (commit sha)
+	tree       plumbing.Hash            // Cached tree of the package itself, some descendent of commit.Tree()
+	render     *repository.RenderStatus // Render status to record with the next resource update
+	changelog  string                   // Changelog entry, stored with the commit of the approved package
+	rejection  string                   // Reason for rejecting the package, stored if the package is rejected
+	generation packageGeneration        // Generation of the package revision spec, stored with every commit
+	labels     map[string]string        // Labels of the package revision, stored with every commit
 }
 
 var _ repository.PackageDraft = &gitPackageDraft{}
+var _ repository.RenderRecorder = &gitPackageDraft{}
+var _ repository.ChangelogRecorder = &gitPackageDraft{}
+var _ repository.RejectionRecorder = &gitPackageDraft{}
+var _ repository.GenerationRecorder = &gitPackageDraft{}
+var _ repository.LabelRecorder = &gitPackageDraft{}
 
 func (d *gitPackageDraft) UpdateResources(ctx context.Context, new *v1alpha1.PackageRevisionResources, change *v1alpha1.Task) error {
 	ch, err := newCommitHelper(d.parent.repo.Storer, d.parent.userInfoProvider, d.commit, d.path, plumbing.ZeroHash)
@@ -51,7 +61,13 @@ func (d *gitPackageDraft) UpdateResources(ctx context.Context, new *v1alpha1.Pac
 		ch.storeFile(path.Join(d.path, k), v)
 	}
 
-	message := fmt.Sprintf("Intermittent commit: %s", change.Type)
+	message, err := d.parent.commitMessage(ctx, fmt.Sprintf("Intermittent commit: %s", change.Type), d.commitMessageData())
+	if err != nil {
+		return err
+	}
+	message = appendRenderTrailers(message, d.path, d.render)
+	message = appendGenerationTrailers(message, d.path, d.generation)
+	message = appendLabelTrailers(message, d.path, d.labels)
 	commitHash, packageTree, err := ch.commit(ctx, message, d.path)
 	if err != nil {
 		return fmt.Errorf("failed to commit package: %w", err)
@@ -59,9 +75,50 @@ func (d *gitPackageDraft) UpdateResources(ctx context.Context, new *v1alpha1.Pac
 
 	d.tree = packageTree
 	d.commit = commitHash
+	d.render = nil
+	return nil
+}
+
+func (d *gitPackageDraft) RecordRender(ctx context.Context, status repository.RenderStatus) error {
+	d.render = &status
+	return nil
+}
+
+func (d *gitPackageDraft) RecordChangelog(ctx context.Context, entry string) error {
+	d.changelog = entry
+	return nil
+}
+
+func (d *gitPackageDraft) RecordRejection(ctx context.Context, reason string) error {
+	d.rejection = reason
 	return nil
 }
 
+func (d *gitPackageDraft) RecordGeneration(ctx context.Context, generation int64) error {
+	d.generation.generation = generation
+	return nil
+}
+
+func (d *gitPackageDraft) RecordObservedGeneration(ctx context.Context, generation int64) error {
+	d.generation.observed = generation
+	return nil
+}
+
+func (d *gitPackageDraft) RecordLabels(ctx context.Context, labels map[string]string) error {
+	d.labels = labels
+	return nil
+}
+
+// commitMessageData returns the commit template values of the draft.
+func (d *gitPackageDraft) commitMessageData() commitMessageData {
+	return commitMessageData{
+		PackageName:    d.path,
+		Revision:       d.revision,
+		Lifecycle:      string(d.lifecycle),
+		ChangelogEntry: d.changelog,
+	}
+}
+
 func (d *gitPackageDraft) UpdateLifecycle(ctx context.Context, new v1alpha1.PackageRevisionLifecycle) error {
 	d.lifecycle = new
 	return nil
@@ -76,6 +133,7 @@ func (r *gitRepository) closeDraft(ctx context.Context, d *gitPackageDraft) (*gi
 	refSpecs := newPushRefSpecBuilder()
 	draftBranch := createDraftName(d.path, d.revision)
 	proposedBranch := createProposedName(d.path, d.revision)
+	rejectedBranch := createRejectedName(d.path, d.revision)
 
 	var newRef *plumbing.Reference
 
@@ -105,6 +163,10 @@ func (r *gitRepository) closeDraft(ctx context.Context, d *gitPackageDraft) (*gi
 		newRef = plumbing.NewHashReference(tag, commitHash)
 
 	case v1alpha1.PackageRevisionLifecycleProposed:
+		if err := r.commitGenerationIfChanged(ctx, d); err != nil {
+			return nil, err
+		}
+
 		// Push the package revision into a proposed branch.
 		refSpecs.AddRefToPush(d.commit, proposedBranch.RefInLocal())
 
@@ -115,10 +177,33 @@ func (r *gitRepository) closeDraft(ctx context.Context, d *gitPackageDraft) (*gi
 			refSpecs.AddRefToDelete(base)
 		}
 
-		// Update package referemce (commit and tree hash stay the same)
+		// Update package reference (tree hash stays the same)
 		newRef = plumbing.NewHashReference(proposedBranch.RefInLocal(), d.commit)
 
+	case v1alpha1.PackageRevisionLifecycleRejected:
+		// Commit the rejection and push it into a rejected branch.
+		commitHash, err := r.commitRejection(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		refSpecs.AddRefToPush(commitHash, rejectedBranch.RefInLocal())
+
+		// Delete base branch (if one exists and should be deleted)
+		switch base := d.base; {
+		case base == nil: // no branch to delete
+		case base.Name() != rejectedBranch.RefInLocal():
+			refSpecs.AddRefToDelete(base)
+		}
+
+		// Update package reference (tree hash stays the same)
+		d.commit = commitHash
+		newRef = plumbing.NewHashReference(rejectedBranch.RefInLocal(), commitHash)
+
 	case v1alpha1.PackageRevisionLifecycleDraft:
+		if err := r.commitGenerationIfChanged(ctx, d); err != nil {
+			return nil, err
+		}
+
 		// Push the package revision into a draft branch.
 		refSpecs.AddRefToPush(d.commit, draftBranch.RefInLocal())
 		// Delete base branch (if one exists and should be deleted)
@@ -128,7 +213,7 @@ func (r *gitRepository) closeDraft(ctx context.Context, d *gitPackageDraft) (*gi
 			refSpecs.AddRefToDelete(base)
 		}
 
-		// Update package referemce (commit and tree hash stay the same)
+		// Update package reference (tree hash stays the same)
 		newRef = plumbing.NewHashReference(draftBranch.RefInLocal(), d.commit)
 
 	default:
@@ -164,10 +249,12 @@ func (r *gitRepository) commitPackageToMain(ctx context.Context, d *gitPackageDr
 	repo := r.repo
 
 	// Fetch main
-	switch err := repo.Fetch(&git.FetchOptions{
-		RemoteName: OriginName,
-		RefSpecs:   []config.RefSpec{branch.ForceFetchSpec()},
-		Auth:       auth,
+	switch err := r.doRemote(func() error {
+		return repo.Fetch(&git.FetchOptions{
+			RemoteName: r.remoteName,
+			RefSpecs:   []config.RefSpec{branch.ForceFetchSpec()},
+			Auth:       auth,
+		})
 	}); err {
 	case nil, git.NoErrAlreadyUpToDate:
 		// ok
@@ -194,7 +281,19 @@ func (r *gitRepository) commitPackageToMain(ctx context.Context, d *gitPackageDr
 	if err != nil {
 		return zero, zero, nil, fmt.Errorf("failed to initialize commit of package %s to %s", packagePath, localRef)
 	}
-	message := fmt.Sprintf("Approve %s", packagePath)
+	message, err := r.commitMessage(ctx, fmt.Sprintf("Approve %s", packagePath), d.commitMessageData())
+	if err != nil {
+		return zero, zero, nil, err
+	}
+	// Carry the render status of the approved package over to the main branch.
+	render, err := r.loadRenderStatus(d.commit, packagePath)
+	if err != nil {
+		return zero, zero, nil, err
+	}
+	message = appendRenderTrailers(message, packagePath, render)
+	message = appendGenerationTrailers(message, packagePath, d.generation)
+	message = appendLabelTrailers(message, packagePath, d.labels)
+	message = appendChangelogTrailers(message, packagePath, d.changelog)
 	commitHash, newPackageTreeHash, err = ch.commit(ctx, message, packagePath)
 	if err != nil {
 		return zero, zero, nil, fmt.Errorf("failed to commit package %s to %s", packagePath, localRef)
@@ -0,0 +1,67 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	kptfile "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+)
+
+const (
+	encodingUTF8   = "utf-8"
+	encodingBinary = "binary"
+)
+
+// contentTypesByExtension maps well-known package file extensions to content
+// types. http.DetectContentType only sniffs content and reports most text files,
+// including YAML and JSON, as text/plain.
+var contentTypesByExtension = map[string]string{
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+	".json": "application/json",
+	".sh":   "text/x-shellscript",
+	".md":   "text/markdown",
+}
+
+// detectFileMetadata determines the content type and encoding of a package file.
+func detectFileMetadata(name, content string) v1alpha1.FileMetadata {
+	encoding := encodingUTF8
+	if !utf8.ValidString(content) {
+		encoding = encodingBinary
+	}
+	return v1alpha1.FileMetadata{
+		Filename:    name,
+		ContentType: detectContentType(name, content),
+		Encoding:    encoding,
+	}
+}
+
+func detectContentType(name, content string) string {
+	if path.Base(name) == kptfile.KptFileName {
+		return "application/yaml"
+	}
+	if ct, ok := contentTypesByExtension[strings.ToLower(path.Ext(name))]; ok {
+		return ct
+	}
+	if strings.HasPrefix(content, "#!") {
+		return "text/x-shellscript"
+	}
+	return http.DetectContentType([]byte(content))
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectFileMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		want    v1alpha1.FileMetadata
+	}{
+		{
+			name:    "Kptfile",
+			content: "apiVersion: kpt.dev/v1\nkind: Kptfile\n",
+			want:    v1alpha1.FileMetadata{Filename: "Kptfile", ContentType: "application/yaml", Encoding: "utf-8"},
+		},
+		{
+			name:    "sub/deployment.YML",
+			content: "kind: Deployment\n",
+			want:    v1alpha1.FileMetadata{Filename: "sub/deployment.YML", ContentType: "application/yaml", Encoding: "utf-8"},
+		},
+		{
+			name:    "schema.json",
+			content: "{}",
+			want:    v1alpha1.FileMetadata{Filename: "schema.json", ContentType: "application/json", Encoding: "utf-8"},
+		},
+		{
+			name:    "setup",
+			content: "#!/bin/bash\necho hello\n",
+			want:    v1alpha1.FileMetadata{Filename: "setup", ContentType: "text/x-shellscript", Encoding: "utf-8"},
+		},
+		{
+			name:    "LICENSE",
+			content: "Apache License\n",
+			want:    v1alpha1.FileMetadata{Filename: "LICENSE", ContentType: "text/plain; charset=utf-8", Encoding: "utf-8"},
+		},
+		{
+			name:    "logo.png",
+			content: "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\xff",
+			want:    v1alpha1.FileMetadata{Filename: "logo.png", ContentType: "image/png", Encoding: "binary"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := detectFileMetadata(tc.name, tc.content)
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("detectFileMetadata(%q): unexpected result (-want, +got): %s", tc.name, cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// The generation of a package revision is stored as trailers of the commit
+// message of the commit at the head of the package revision:
+//
+//	Porch-Generation-Package: <package path>
+//	Porch-Generation: <generation>
+//	Porch-Observed-Generation: <observed generation>
+//
+// As with the render trailers, the package path guards against reading the
+// trailers of a commit which updated a different package.
+const (
+	generationPackageTrailer  = "Porch-Generation-Package"
+	generationTrailer         = "Porch-Generation"
+	observedGenerationTrailer = "Porch-Observed-Generation"
+)
+
+// packageGeneration is the generation of a package revision spec, and the
+// generation which has been reconciled with the package contents.
+type packageGeneration struct {
+	generation int64
+	observed   int64
+}
+
+// defaultGeneration is the generation of package revisions which don't
+// record one, such as packages created outside of Porch.
+var defaultGeneration = packageGeneration{generation: 1, observed: 1}
+
+// appendGenerationTrailers appends the generation trailers to the commit message.
+func appendGenerationTrailers(message, pkgPath string, generation packageGeneration) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(message, "\n"))
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "%s: %s\n", generationPackageTrailer, pkgPath)
+	fmt.Fprintf(&b, "%s: %d\n", generationTrailer, generation.generation)
+	fmt.Fprintf(&b, "%s: %d\n", observedGenerationTrailer, generation.observed)
+	return b.String()
+}
+
+// parseGenerationTrailers returns the generation of the package recorded in
+// the commit message, or the default generation if the message doesn't
+// record one for the package.
+func parseGenerationTrailers(message, pkgPath string) packageGeneration {
+	generation := defaultGeneration
+	matched := false
+	for _, line := range strings.Split(message, "\n") {
+		key, value, found := cut(line, ": ")
+		if !found {
+			continue
+		}
+		switch key {
+		case generationPackageTrailer:
+			matched = value == pkgPath
+		case generationTrailer:
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil && matched {
+				generation.generation = n
+			}
+		case observedGenerationTrailer:
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil && matched {
+				generation.observed = n
+			}
+		}
+	}
+	return generation
+}
+
+// loadGeneration returns the generation of the package recorded in the commit.
+func (r *gitRepository) loadGeneration(commitHash plumbing.Hash, pkgPath string) (packageGeneration, error) {
+	if commitHash.IsZero() {
+		return defaultGeneration, nil
+	}
+	commit, err := r.repo.CommitObject(commitHash)
+	if err != nil {
+		return packageGeneration{}, fmt.Errorf("cannot resolve package commit %s: %w", commitHash, err)
+	}
+	return parseGenerationTrailers(commit.Message, pkgPath), nil
+}
+
+// commitGeneration commits the generation and labels of the package draft on
+// top of its current commit, keeping the package tree and its render status.
+// Spec and label changes which don't change the package contents, such as
+// lifecycle transitions, are recorded this way.
+func (r *gitRepository) commitGeneration(ctx context.Context, d *gitPackageDraft) (plumbing.Hash, error) {
+	ch, err := newCommitHelper(r.repo.Storer, r.userInfoProvider, d.commit, d.path, d.tree)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to initialize commit of package %s: %w", d.path, err)
+	}
+	message, err := r.commitMessage(ctx, fmt.Sprintf("Update %s", d.path), d.commitMessageData())
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	render, err := r.loadRenderStatus(d.commit, d.path)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	message = appendRenderTrailers(message, d.path, render)
+	message = appendGenerationTrailers(message, d.path, d.generation)
+	message = appendLabelTrailers(message, d.path, d.labels)
+	commitHash, _, err := ch.commit(ctx, message, d.path)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to commit package %s: %w", d.path, err)
+	}
+	return commitHash, nil
+}
+
+// commitGenerationIfChanged commits the generation and labels of the package
+// draft if either differs from those recorded in its current commit.
+func (r *gitRepository) commitGenerationIfChanged(ctx context.Context, d *gitPackageDraft) error {
+	current, err := r.loadGeneration(d.commit, d.path)
+	if err != nil {
+		return err
+	}
+	currentLabels, err := r.loadLabels(d.commit, d.path)
+	if err != nil {
+		return err
+	}
+	if current == d.generation && labelsEqual(currentLabels, d.labels) {
+		return nil
+	}
+	commitHash, err := r.commitGeneration(ctx, d)
+	if err != nil {
+		return err
+	}
+	d.commit = commitHash
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+)
+
+func TestGenerationTrailers(t *testing.T) {
+	want := packageGeneration{generation: 4, observed: 3}
+	message := appendGenerationTrailers("Update catalog/bucket", "catalog/bucket", want)
+
+	if got := parseGenerationTrailers(message, "catalog/bucket"); got != want {
+		t.Errorf("generation: got %+v, want %+v", got, want)
+	}
+	if got := parseGenerationTrailers(message, "catalog/other"); got != defaultGeneration {
+		t.Errorf("generation of other package: got %+v, want %+v", got, defaultGeneration)
+	}
+	if got := parseGenerationTrailers("Approve catalog/bucket", "catalog/bucket"); got != defaultGeneration {
+		t.Errorf("generation of commit without trailers: got %+v, want %+v", got, defaultGeneration)
+	}
+}
+
+func TestProposeDraftGeneration(t *testing.T) {
+	tempdir := t.TempDir()
+	tarfile := filepath.Join("testdata", "drafts-repository.tar")
+	_, address := ServeGitRepository(t, tarfile, tempdir)
+
+	ctx := context.Background()
+	git, err := OpenRepository(ctx, "generation", "default", &configapi.GitRepository{
+		Repo:      address,
+		Branch:    "main",
+		Directory: "/",
+	}, tempdir, GitRepositoryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to open Git repository loaded from %q: %v", tarfile, err)
+	}
+
+	revisions, err := git.ListPackageRevisions(ctx)
+	if err != nil {
+		t.Fatalf("ListPackageRevisions failed: %v", err)
+	}
+	bucket := findPackage(t, revisions, "generation:bucket:v1")
+
+	// A lifecycle transition doesn't change the package contents, but
+	// bumps the generation.
+	update, err := git.UpdatePackage(ctx, bucket)
+	if err != nil {
+		t.Fatalf("UpdatePackage failed: %v", err)
+	}
+	recorder := update.(repository.GenerationRecorder)
+	if err := recorder.RecordGeneration(ctx, 2); err != nil {
+		t.Fatalf("RecordGeneration failed: %v", err)
+	}
+	update.UpdateLifecycle(ctx, v1alpha1.PackageRevisionLifecycleProposed)
+	proposed, err := update.Close(ctx)
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	rev, err := proposed.GetPackageRevision()
+	if err != nil {
+		t.Fatalf("GetPackageRevision failed: %v", err)
+	}
+	if got, want := rev.Generation, int64(2); got != want {
+		t.Errorf("Proposed package generation: got %d, want %d", got, want)
+	}
+	if got, want := rev.Status.ObservedGeneration, int64(1); got != want {
+		t.Errorf("Proposed package observed generation: got %d, want %d", got, want)
+	}
+
+	// The generation is read back from the repository once reconciled.
+	update, err = git.UpdatePackage(ctx, proposed)
+	if err != nil {
+		t.Fatalf("UpdatePackage failed: %v", err)
+	}
+	if err := update.(repository.GenerationRecorder).RecordObservedGeneration(ctx, 2); err != nil {
+		t.Fatalf("RecordObservedGeneration failed: %v", err)
+	}
+	if _, err := update.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	revisions, err = git.ListPackageRevisions(ctx)
+	if err != nil {
+		t.Fatalf("ListPackageRevisions failed: %v", err)
+	}
+	rev, err = findPackage(t, revisions, "generation:bucket:v1").GetPackageRevision()
+	if err != nil {
+		t.Fatalf("GetPackageRevision failed: %v", err)
+	}
+	if got, want := rev.Generation, int64(2); got != want {
+		t.Errorf("Reconciled package generation: got %d, want %d", got, want)
+	}
+	if got, want := rev.Status.ObservedGeneration, int64(2); got != want {
+		t.Errorf("Reconciled package observed generation: got %d, want %d", got, want)
+	}
+}
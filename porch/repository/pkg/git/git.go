@@ -22,7 +22,10 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
@@ -44,14 +47,34 @@ const (
 	OriginName               string                 = "origin"
 )
 
+var remoteNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
 type GitRepository interface {
 	repository.Repository
+	repository.DefaultBranchRepository
 	GetPackage(ref, path string) (repository.PackageRevision, kptfilev1.GitLock, error)
+	// ListTags fetches the repository and returns the names of its tags,
+	// sorted. Tags are always fetched, as published package revisions are
+	// tags; a tag without a package path prefix, such as `v1`, is a revision
+	// of every package in the repository.
+	ListTags(ctx context.Context) ([]string, error)
 }
 
 type GitRepositoryOptions struct {
 	CredentialResolver repository.CredentialResolver
 	UserInfoProvider   repository.UserInfoProvider
+	// CircuitBreakerResetTimeout is the time operations against the remote
+	// repository fail fast after repeated failures. Defaults to DefaultCircuitBreakerResetTimeout.
+	CircuitBreakerResetTimeout time.Duration
+	// PruneStaleRefs deletes draft, proposed and rejected branches that do not
+	// correspond to a package revision when the repository is opened.
+	PruneStaleRefs bool
+	// MaxPackagesPerRepo limits the number of directories a directory glob
+	// may match. Defaults to DefaultMaxPackagesPerRepo.
+	MaxPackagesPerRepo int
+	// VerifyIntegrity re-hashes the blobs read as package resources and fails
+	// the read if they do not match the hashes recorded in the tree.
+	VerifyIntegrity bool
 }
 
 func OpenRepository(ctx context.Context, name, namespace string, spec *configapi.GitRepository, root string, opts GitRepositoryOptions) (GitRepository, error) {
@@ -83,16 +106,43 @@ func OpenRepository(ctx context.Context, name, namespace string, spec *configapi
 		repo = r
 	}
 
+	remoteName := OriginName
+	if spec.RemoteName != "" {
+		if !remoteNamePattern.MatchString(spec.RemoteName) {
+			return nil, fmt.Errorf("invalid git remote name %q; must match %s", spec.RemoteName, remoteNamePattern)
+		}
+		remoteName = spec.RemoteName
+	}
+
 	// Create Remote
-	if err := initializeOrigin(repo, spec.Repo); err != nil {
+	if err := initializeOrigin(repo, remoteName, spec.Repo); err != nil {
 		return nil, fmt.Errorf("error cloning git repository %q, cannot create remote: %v", spec.Repo, err)
 	}
 
-	branch := MainBranch
-	if spec.Branch != "" {
-		// TODO: Validate branch name syntax (we can't check whether the branch exists;
-		// the repository may be empty).
-		branch = BranchName(spec.Branch)
+	// TODO: Validate branch name syntax (we can't check whether the branch exists;
+	// the repository may be empty).
+	branch := BranchName(spec.Branch)
+
+	var directoryGlob string
+	if directory := strings.Trim(spec.Directory, "/"); isDirectoryGlob(directory) {
+		if err := validateDirectoryGlob(directory); err != nil {
+			return nil, err
+		}
+		directoryGlob = directory
+	}
+
+	var commitTemplate *template.Template
+	if spec.CommitTemplate != "" {
+		tmpl, err := parseCommitTemplate(spec.CommitTemplate)
+		if err != nil {
+			return nil, err
+		}
+		commitTemplate = tmpl
+	}
+
+	maxPackages := opts.MaxPackagesPerRepo
+	if maxPackages <= 0 {
+		maxPackages = DefaultMaxPackagesPerRepo
 	}
 
 	repository := &gitRepository{
@@ -100,15 +150,46 @@ func OpenRepository(ctx context.Context, name, namespace string, spec *configapi
 		namespace:          namespace,
 		repo:               repo,
 		branch:             branch,
+		remoteName:         remoteName,
 		secret:             spec.SecretRef.Name,
 		credentialResolver: opts.CredentialResolver,
 		userInfoProvider:   opts.UserInfoProvider,
+		breaker:            NewCircuitBreaker(opts.CircuitBreakerResetTimeout),
+		directoryGlob:      directoryGlob,
+		maxPackages:        maxPackages,
+		commitTemplate:     commitTemplate,
+		verifyIntegrity:    opts.VerifyIntegrity,
+	}
+
+	if spec.Submodules == configapi.GitSubmodulesRecursive {
+		repository.submoduleDepth = spec.SubmoduleDepth
+		if repository.submoduleDepth <= 0 {
+			repository.submoduleDepth = 1
+		}
+		repository.submodules = newSubmoduleCache()
+		repository.submoduleBreakers = newSubmoduleBreakers(opts.CircuitBreakerResetTimeout)
+	}
+
+	if branch == "" {
+		defaultBranch, err := repository.detectDefaultBranch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		repository.branch = defaultBranch
+		repository.defaultBranch = defaultBranch
 	}
 
 	if err := repository.fetchRemoteRepository(ctx); err != nil {
 		return nil, err
 	}
 
+	if opts.PruneStaleRefs {
+		if err := repository.pruneStaleRefs(ctx); err != nil {
+			// Stale refs are harmless to keep around; do not fail the registration.
+			klog.Warningf("Failed to prune stale refs in repository %s/%s: %v", namespace, name, err)
+		}
+	}
+
 	return repository, nil
 }
 
@@ -116,11 +197,26 @@ type gitRepository struct {
 	name               string     // Repository resource name
 	namespace          string     // Repository resource namespace
 	secret             string     // Name of the k8s Secret resource containing credentials
-	branch             BranchName // The main branch from repository registration (defaults to 'main' if unspecified)
+	branch             BranchName // The main branch from repository registration (defaults to the default branch of the remote if unspecified)
+	defaultBranch      BranchName // The detected default branch of the remote; empty if the branch was registered
+	remoteName         string     // Name of the git remote (defaults to 'origin' if unspecified)
 	repo               *git.Repository
-	cachedCredentials  transport.AuthMethod
 	credentialResolver repository.CredentialResolver
 	userInfoProvider   repository.UserInfoProvider
+	breaker            *CircuitBreaker    // Guards operations against the remote repository
+	submoduleDepth     int                // Levels of submodules included in packages; zero disables submodules
+	submodules         *submoduleCache    // Files of fetched submodule commits
+	submoduleBreakers  *submoduleBreakers // Guard operations against the submodule repositories
+	directoryGlob      string             // Pattern of the directories containing packages; empty for the whole repository
+	maxPackages        int                // Maximum number of directories directoryGlob may match
+	commitTemplate     *template.Template // Template of the commit messages; nil for the default messages
+	verifyIntegrity    bool               // Whether blobs read as package resources are re-hashed and checked
+}
+
+// DefaultBranch returns the default branch of the remote repository, detected
+// when the repository was registered without a branch, or "" otherwise.
+func (r *gitRepository) DefaultBranch() string {
+	return string(r.defaultBranch)
 }
 
 func (r *gitRepository) ListPackageRevisions(ctx context.Context) ([]repository.PackageRevision, error) {
@@ -150,7 +246,7 @@ func (r *gitRepository) ListPackageRevisions(ctx context.Context) ([]repository.
 			main = ref
 			continue
 
-		case isProposedBranchNameInLocal(ref.Name()), isDraftBranchNameInLocal(ref.Name()):
+		case isProposedBranchNameInLocal(ref.Name()), isDraftBranchNameInLocal(ref.Name()), isRejectedBranchNameInLocal(ref.Name()):
 			draft, err := r.loadDraft(ref)
 			if err != nil {
 				return nil, fmt.Errorf("failed to load package draft %q: %w", name.String(), err)
@@ -180,6 +276,30 @@ func (r *gitRepository) ListPackageRevisions(ctx context.Context) ([]repository.
 	return result, nil
 }
 
+func (r *gitRepository) ListTags(ctx context.Context) ([]string, error) {
+	if err := r.fetchRemoteRepository(ctx); err != nil {
+		return nil, err
+	}
+
+	refs, err := r.repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list tags of repository %s/%s: %w", r.namespace, r.name, err)
+	}
+	defer refs.Close()
+
+	var tags []string
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		if name, ok := getTagNameInLocalRepo(ref.Name()); ok {
+			tags = append(tags, name)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("cannot list tags of repository %s/%s: %w", r.namespace, r.name, err)
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
 func (r *gitRepository) CreatePackageRevision(ctx context.Context, obj *v1alpha1.PackageRevision) (repository.PackageDraft, error) {
 	var base plumbing.Hash
 	refName := r.branch.RefInLocal()
@@ -195,14 +315,15 @@ func (r *gitRepository) CreatePackageRevision(ctx context.Context, obj *v1alpha1
 	draft := createDraftName(obj.Spec.PackageName, obj.Spec.Revision)
 
 	return &gitPackageDraft{
-		parent:    r,
-		path:      obj.Spec.PackageName,
-		revision:  obj.Spec.Revision,
-		lifecycle: v1alpha1.PackageRevisionLifecycleDraft,
-		updated:   time.Now(),
-		base:      nil, // Creating a new package
-		branch:    draft,
-		commit:    base,
+		parent:     r,
+		path:       obj.Spec.PackageName,
+		revision:   obj.Spec.Revision,
+		lifecycle:  v1alpha1.PackageRevisionLifecycleDraft,
+		updated:    time.Now(),
+		base:       nil, // Creating a new package
+		branch:     draft,
+		commit:     base,
+		generation: defaultGeneration,
 	}, nil
 }
 
@@ -227,15 +348,26 @@ func (r *gitRepository) UpdatePackage(ctx context.Context, old repository.Packag
 		return nil, fmt.Errorf("cannot load draft package: %w", err)
 	}
 
+	generation, err := r.loadGeneration(rev.commit, oldGitPackage.path)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := r.loadLabels(rev.commit, oldGitPackage.path)
+	if err != nil {
+		return nil, err
+	}
+
 	return &gitPackageDraft{
-		parent:    r,
-		path:      oldGitPackage.path,
-		revision:  oldGitPackage.revision,
-		lifecycle: oldGitPackage.getPackageRevisionLifecycle(),
-		updated:   rev.updated,
-		base:      rev.ref,
-		tree:      rev.tree,
-		commit:    rev.commit,
+		parent:     r,
+		path:       oldGitPackage.path,
+		revision:   oldGitPackage.revision,
+		lifecycle:  oldGitPackage.getPackageRevisionLifecycle(),
+		updated:    rev.updated,
+		base:       rev.ref,
+		tree:       rev.tree,
+		commit:     rev.commit,
+		generation: generation,
+		labels:     labels,
 	}, nil
 }
 
@@ -268,8 +400,8 @@ func (r *gitRepository) DeletePackageRevision(ctx context.Context, old repositor
 		// Delete the tag
 		refSpecs.AddRefToDelete(ref)
 
-	case isDraftBranchNameInLocal(rn), isProposedBranchNameInLocal(rn):
-		// PackageRevision is proposed or draft; delete the branch directly.
+	case isDraftBranchNameInLocal(rn), isProposedBranchNameInLocal(rn), isRejectedBranchNameInLocal(rn):
+		// PackageRevision is proposed, rejected or draft; delete the branch directly.
 		refSpecs.AddRefToDelete(ref)
 
 	case isBranchInLocalRepo(rn):
@@ -406,20 +538,33 @@ func (r *gitRepository) discoverFinalizedPackages(ref *plumbing.Reference) ([]re
 		return nil, fmt.Errorf("cannot determine revision from ref: %q", rev)
 	}
 
+	roots := []matchingDirectory{{path: "", tree: tree}}
+	if r.directoryGlob != "" {
+		// Each matching directory is searched for packages, as the
+		// registered directory is when it is not a pattern.
+		matches, err := matchDirectories(git, tree, r.directoryGlob, r.maxPackages)
+		if err != nil {
+			return nil, err
+		}
+		roots = matches
+	}
+
 	var result []repository.PackageRevision
-	if err := discoverPackagesInTree(git, tree, "", func(dir string, tree, kptfile plumbing.Hash) error {
-		result = append(result, &gitPackageRevision{
-			parent:   r,
-			path:     dir,
-			revision: revision,
-			updated:  commit.Author.When,
-			ref:      ref,
-			tree:     tree,
-			commit:   ref.Hash(),
-		})
-		return nil
-	}); err != nil {
-		return nil, err
+	for _, root := range roots {
+		if err := discoverPackagesInTree(git, root.tree, root.path, func(dir string, tree, kptfile plumbing.Hash) error {
+			result = append(result, &gitPackageRevision{
+				parent:   r,
+				path:     dir,
+				revision: revision,
+				updated:  commit.Author.When,
+				ref:      ref,
+				tree:     tree,
+				commit:   ref.Hash(),
+			})
+			return nil
+		}); err != nil {
+			return nil, err
+		}
 	}
 	return result, nil
 }
@@ -502,6 +647,8 @@ func parseDraftName(draft *plumbing.Reference) (name, revision string, err error
 		suffix = string(b)
 	} else if b, ok = getProposedBranchNameInLocal(refName); ok {
 		suffix = string(b)
+	} else if b, ok = getRejectedBranchNameInLocal(refName); ok {
+		suffix = string(b)
 	} else {
 		return "", "", fmt.Errorf("invalid draft ref name: %q", refName)
 	}
@@ -614,22 +761,17 @@ func resolveCredential(ctx context.Context, namespace, name string, resolver rep
 	}, nil
 }
 
+// getAuthMethod resolves the repository credentials on every call so that
+// rotated secrets are picked up; caching is left to the credential resolver.
 func (r *gitRepository) getAuthMethod(ctx context.Context) (transport.AuthMethod, error) {
-	if r.cachedCredentials == nil {
-		if r.secret != "" {
-			if auth, err := resolveCredential(ctx, r.namespace, r.secret, r.credentialResolver); err != nil {
-				return nil, err
-			} else {
-				r.cachedCredentials = auth
-			}
-		}
+	if r.secret == "" {
+		return nil, nil
 	}
-
-	return r.cachedCredentials, nil
+	return resolveCredential(ctx, r.namespace, r.secret, r.credentialResolver)
 }
 
 func (r *gitRepository) getRepo() (string, error) {
-	origin, err := r.repo.Remote("origin")
+	origin, err := r.repo.Remote(r.remoteName)
 	if err != nil {
 		return "", fmt.Errorf("cannot determine repository origin: %w", err)
 	}
@@ -644,10 +786,12 @@ func (r *gitRepository) fetchRemoteRepository(ctx context.Context) error {
 	}
 
 	// Fetch
-	switch err := r.repo.Fetch(&git.FetchOptions{
-		RemoteName: OriginName,
-		Auth:       auth,
-		Prune:      git.Prune,
+	switch err := r.doRemote(func() error {
+		return r.repo.Fetch(&git.FetchOptions{
+			RemoteName: r.remoteName,
+			Auth:       auth,
+			Prune:      git.Prune,
+		})
 	}); err {
 	case nil: // OK
 	case git.NoErrAlreadyUpToDate:
@@ -677,11 +821,13 @@ func (r *gitRepository) createPackageDeleteCommit(ctx context.Context, branch pl
 	}
 	// Fetch the branch
 	// TODO: Fetch only as part of conflict resolution & Retry
-	switch err := repo.Fetch(&git.FetchOptions{
-		RemoteName: OriginName,
-		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", local, branch))},
-		Auth:       auth,
-		Tags:       git.NoTags,
+	switch err := r.doRemote(func() error {
+		return repo.Fetch(&git.FetchOptions{
+			RemoteName: r.remoteName,
+			RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", local, branch))},
+			Auth:       auth,
+			Tags:       git.NoTags,
+		})
 	}); err {
 	case nil, git.NoErrAlreadyUpToDate:
 		// ok
@@ -744,13 +890,39 @@ func (r *gitRepository) pushAndCleanup(ctx context.Context, ph *pushRefSpecBuild
 		return err
 	}
 
-	if err := r.repo.Push(&git.PushOptions{
-		RemoteName:        OriginName,
-		RefSpecs:          specs,
-		Auth:              auth,
-		RequireRemoteRefs: require,
+	if err := r.doRemote(func() error {
+		return r.repo.Push(&git.PushOptions{
+			RemoteName:        r.remoteName,
+			RefSpecs:          specs,
+			Auth:              auth,
+			RequireRemoteRefs: require,
+		})
 	}); err != nil {
 		return err
 	}
 	return nil
 }
+
+// doRemote runs an operation against the remote repository, guarded by the
+// circuit breaker of the repository.
+func (r *gitRepository) doRemote(operation func() error) error {
+	return doGuarded(r.breaker, operation)
+}
+
+// doGuarded runs an operation against a remote repository, guarded by the
+// circuit breaker. Errors which do not indicate a failure of the remote, such
+// as rejected pushes or authentication errors, are returned to the caller but
+// are not counted as failures by the breaker.
+func doGuarded(breaker *CircuitBreaker, operation func() error) error {
+	var result error
+	if err := breaker.Do(func() error {
+		result = operation()
+		if isRemoteFailure(result) {
+			return result
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return result
+}
@@ -22,11 +22,13 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
 	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
@@ -495,6 +497,57 @@ func TestListPackagesSimple(t *testing.T) {
 	}
 }
 
+func TestListTags(t *testing.T) {
+	tempdir := t.TempDir()
+	tarfile := filepath.Join("testdata", "simple-repository.tar")
+	repo, address := ServeGitRepository(t, tarfile, tempdir)
+
+	ctx := context.Background()
+	const (
+		repositoryName = "simple"
+		namespace      = "default"
+	)
+
+	git, err := OpenRepository(ctx, repositoryName, namespace, &configapi.GitRepository{
+		Repo:      address,
+		Branch:    "main",
+		Directory: "/",
+		SecretRef: configapi.SecretRef{},
+	}, tempdir, GitRepositoryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to open Git repository loaded from %q: %v", tarfile, err)
+	}
+
+	tags, err := git.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	want := []string{"basens/v1", "basens/v2", "empty/v1", "istions/v1", "istions/v2"}
+	if !cmp.Equal(want, tags) {
+		t.Errorf("ListTags (-want,+got): %s", cmp.Diff(want, tags))
+	}
+
+	// Tag the main branch in the upstream repository with a version only.
+	main := resolveReference(t, repo, DefaultMainReferenceName)
+	if _, err := repo.CreateTag("v3", main.Hash(), nil); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+
+	tags, err = git.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	want = append(want, "v3")
+	if !cmp.Equal(want, tags) {
+		t.Errorf("ListTags after tagging (-want,+got): %s", cmp.Diff(want, tags))
+	}
+
+	// Every package on the tagged commit is a revision named after the tag.
+	for _, name := range []string{"simple:empty:v3", "simple:basens:v3", "simple:istions:v3"} {
+		repositoryMustHavePackageRevision(t, git, name)
+	}
+}
+
 func TestListPackagesDrafts(t *testing.T) {
 	tempdir := t.TempDir()
 	tarfile := filepath.Join("testdata", "drafts-repository.tar")
@@ -609,6 +662,91 @@ func TestApproveDraft(t *testing.T) {
 	refMustExist(t, repo, finalReferenceName)
 }
 
+func TestRejectDraft(t *testing.T) {
+	tempdir := t.TempDir()
+	tarfile := filepath.Join("testdata", "drafts-repository.tar")
+	repo, address := ServeGitRepository(t, tarfile, tempdir)
+
+	const (
+		repositoryName            = "reject"
+		namespace                 = "default"
+		draft          BranchName = "drafts/bucket/v1"
+		rejected       BranchName = "rejected/bucket/v1"
+		reason                    = "bucket must not be public"
+	)
+	ctx := context.Background()
+	git, err := OpenRepository(ctx, repositoryName, namespace, &configapi.GitRepository{
+		Repo:      address,
+		Branch:    "main",
+		Directory: "/",
+	}, tempdir, GitRepositoryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to open Git repository loaded from %q: %v", tarfile, err)
+	}
+
+	revisions, err := git.ListPackageRevisions(ctx)
+	if err != nil {
+		t.Fatalf("ListPackageRevisions failed: %v", err)
+	}
+	bucket := findPackage(t, revisions, "reject:bucket:v1")
+
+	update, err := git.UpdatePackage(ctx, bucket)
+	if err != nil {
+		t.Fatalf("UpdatePackage failed: %v", err)
+	}
+	update.UpdateLifecycle(ctx, v1alpha1.PackageRevisionLifecycleRejected)
+	if err := update.(repository.RejectionRecorder).RecordRejection(ctx, reason); err != nil {
+		t.Fatalf("RecordRejection failed: %v", err)
+	}
+	if _, err := update.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// The rejected package replaces the draft and is listed with its reason.
+	refMustNotExist(t, repo, draft.RefInRemote())
+	refMustExist(t, repo, rejected.RefInRemote())
+
+	revisions, err = git.ListPackageRevisions(ctx)
+	if err != nil {
+		t.Fatalf("ListPackageRevisions failed: %v", err)
+	}
+	bucket = findPackage(t, revisions, "reject:bucket:v1")
+	rev, err := bucket.GetPackageRevision()
+	if err != nil {
+		t.Fatalf("GetPackageRevision failed: %v", err)
+	}
+	if got, want := rev.Spec.Lifecycle, v1alpha1.PackageRevisionLifecycleRejected; got != want {
+		t.Errorf("Rejected package lifecycle: got %s, want %s", got, want)
+	}
+	if got, want := rev.Status.RejectionReason, reason; got != want {
+		t.Errorf("Rejected package rejection reason: got %q, want %q", got, want)
+	}
+
+	// Returning the package to draft moves it back to the draft branch.
+	update, err = git.UpdatePackage(ctx, bucket)
+	if err != nil {
+		t.Fatalf("UpdatePackage failed: %v", err)
+	}
+	update.UpdateLifecycle(ctx, v1alpha1.PackageRevisionLifecycleDraft)
+	redrafted, err := update.Close(ctx)
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	rev, err = redrafted.GetPackageRevision()
+	if err != nil {
+		t.Fatalf("GetPackageRevision failed: %v", err)
+	}
+	if got, want := rev.Spec.Lifecycle, v1alpha1.PackageRevisionLifecycleDraft; got != want {
+		t.Errorf("Redrafted package lifecycle: got %s, want %s", got, want)
+	}
+	if got := rev.Status.RejectionReason; got != "" {
+		t.Errorf("Redrafted package rejection reason: got %q, want none", got)
+	}
+
+	refMustExist(t, repo, draft.RefInRemote())
+	refMustNotExist(t, repo, rejected.RefInRemote())
+}
+
 func TestDeletePackages(t *testing.T) {
 	tempdir := t.TempDir()
 	tarfile := filepath.Join("testdata", "drafts-repository.tar")
@@ -846,3 +984,109 @@ func TestPruneRemotes(t *testing.T) {
 		repositoryMustNotHavePackageRevision(t, git, pair.pkg)
 	}
 }
+
+func TestRemoteName(t *testing.T) {
+	tempdir := t.TempDir()
+	tarfile := filepath.Join("testdata", "drafts-repository.tar")
+	repo, address := ServeGitRepository(t, tarfile, tempdir)
+
+	ctx := context.Background()
+	git, err := OpenRepository(ctx, "remote", "default", &configapi.GitRepository{
+		Repo:       address,
+		RemoteName: "upstream",
+	}, tempdir, GitRepositoryOptions{})
+	if err != nil {
+		t.Fatalf("OpenRepository(%q) failed: %v", address, err)
+	}
+
+	remote, err := git.(*gitRepository).repo.Remote("upstream")
+	if err != nil {
+		t.Fatalf("Remote(upstream) failed: %v", err)
+	}
+	if got, want := remote.Config().URLs, []string{address}; !cmp.Equal(want, got) {
+		t.Errorf("upstream remote URLs: got %v, want %v", got, want)
+	}
+
+	// Fetch
+	revisions, err := git.ListPackageRevisions(ctx)
+	if err != nil {
+		t.Fatalf("ListPackageRevisions failed: %v", err)
+	}
+	draft := findPackage(t, revisions, "remote:bucket:v1")
+
+	// Push
+	if err := git.DeletePackageRevision(ctx, draft); err != nil {
+		t.Fatalf("DeletePackageRevision failed: %v", err)
+	}
+	refMustNotExist(t, repo, "refs/heads/drafts/bucket/v1")
+}
+
+func TestInvalidRemoteName(t *testing.T) {
+	tempdir := t.TempDir()
+
+	if _, err := OpenRepository(context.Background(), "remote", "default", &configapi.GitRepository{
+		Repo:       "https://example.com/repo.git",
+		RemoteName: "up stream",
+	}, tempdir, GitRepositoryOptions{}); err == nil {
+		t.Errorf("OpenRepository with invalid remote name unexpectedly succeeded")
+	}
+}
+
+// BenchmarkGetResources compares fetching package revision resources
+// sequentially and concurrently. Packages are read directly from the object
+// storage of the bare repository, so concurrent reads do not contend on a
+// shared working tree.
+func BenchmarkGetResources(b *testing.B) {
+	const concurrency = 10
+
+	tempdir := b.TempDir()
+	tarfile := filepath.Join("testdata", "simple-repository.tar")
+	_, address := ServeGitRepository(b, tarfile, tempdir)
+
+	ctx := context.Background()
+	git, err := OpenRepository(ctx, "simple", "default", &configapi.GitRepository{
+		Repo:      address,
+		Branch:    "main",
+		Directory: "/",
+	}, tempdir, GitRepositoryOptions{})
+	if err != nil {
+		b.Fatalf("Failed to open Git repository loaded from %q: %v", tarfile, err)
+	}
+
+	revisions, err := git.ListPackageRevisions(ctx)
+	if err != nil {
+		b.Fatalf("Failed to list packages from %q: %v", tarfile, err)
+	}
+	if len(revisions) == 0 {
+		b.Fatalf("No packages found in %q", tarfile)
+	}
+
+	fetch := func(i int) {
+		r := revisions[i%len(revisions)]
+		if _, err := r.GetResources(ctx); err != nil {
+			b.Errorf("GetResources(%q) failed: %v", r.Name(), err)
+		}
+	}
+
+	b.Run("Sequential", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for i := 0; i < concurrency; i++ {
+				fetch(i)
+			}
+		}
+	})
+
+	b.Run("Concurrent", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			var wg sync.WaitGroup
+			for i := 0; i < concurrency; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					fetch(i)
+				}(i)
+			}
+			wg.Wait()
+		}
+	})
+}
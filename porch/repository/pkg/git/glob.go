@@ -0,0 +1,84 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DefaultMaxPackagesPerRepo is the default limit on the number of directories
+// a directory glob of a repository may match.
+const DefaultMaxPackagesPerRepo = 500
+
+// isDirectoryGlob returns true if the repository directory is a glob pattern
+// matching multiple directories, such as `teams/*`.
+func isDirectoryGlob(directory string) bool {
+	return strings.ContainsAny(directory, "*?")
+}
+
+// validateDirectoryGlob checks the syntax of the directory glob.
+func validateDirectoryGlob(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid directory pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchingDirectory is a directory of a tree matched by a directory glob.
+type matchingDirectory struct {
+	path string
+	tree *object.Tree
+}
+
+// matchDirectories returns the directories of tree matching the glob pattern,
+// matching each path segment with path.Match. It fails if more than max
+// directories match; zero means no limit.
+func matchDirectories(r *git.Repository, tree *object.Tree, pattern string, max int) ([]matchingDirectory, error) {
+	matches := []matchingDirectory{{path: "", tree: tree}}
+	for _, segment := range strings.Split(pattern, "/") {
+		var next []matchingDirectory
+		for _, m := range matches {
+			for _, e := range m.tree.Entries {
+				if e.Mode != filemode.Dir {
+					continue
+				}
+				if ok, err := path.Match(segment, e.Name); err != nil {
+					return nil, fmt.Errorf("invalid directory pattern %q: %w", pattern, err)
+				} else if !ok {
+					continue
+				}
+				subtree, err := r.TreeObject(e.Hash)
+				if err != nil {
+					return nil, fmt.Errorf("cannot read directory %q: %w", path.Join(m.path, e.Name), err)
+				}
+				next = append(next, matchingDirectory{path: path.Join(m.path, e.Name), tree: subtree})
+			}
+		}
+		matches = next
+	}
+
+	if max > 0 && len(matches) > max {
+		return nil, fmt.Errorf("directory pattern %q matches %d directories, more than the limit of %d", pattern, len(matches), max)
+	}
+	return matches, nil
+}
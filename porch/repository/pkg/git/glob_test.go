@@ -0,0 +1,113 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDirectoryGlob(t *testing.T) {
+	repo := initInMemoryRepository(t)
+	writeTestCommit(t, repo, map[string]string{
+		"teams/alpha/frontend/Kptfile": Kptfile,
+		"teams/alpha/backend/Kptfile":  Kptfile,
+		"teams/beta/Kptfile":           Kptfile,
+		"other/Kptfile":                Kptfile,
+	}, nil)
+	address := ServeExistingRepository(t, repo)
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name      string
+		directory string
+		max       int
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "no pattern",
+			directory: "",
+			want:      []string{"other", "teams/alpha/backend", "teams/alpha/frontend", "teams/beta"},
+		},
+		{
+			name:      "single level",
+			directory: "teams/*",
+			want:      []string{"teams/alpha/backend", "teams/alpha/frontend", "teams/beta"},
+		},
+		{
+			name:      "nested",
+			directory: "/teams/alpha/*end/",
+			want:      []string{"teams/alpha/backend", "teams/alpha/frontend"},
+		},
+		{
+			name:      "single character",
+			directory: "teams/bet?",
+			want:      []string{"teams/beta"},
+		},
+		{
+			name:      "too many matches",
+			directory: "teams/alpha/*",
+			max:       1,
+			wantErr:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			git, err := OpenRepository(ctx, "glob", "default", &configapi.GitRepository{
+				Repo:      address,
+				Directory: tc.directory,
+			}, t.TempDir(), GitRepositoryOptions{MaxPackagesPerRepo: tc.max})
+			if err != nil {
+				t.Fatalf("OpenRepository(%q) failed: %v", address, err)
+			}
+
+			revisions, err := git.ListPackageRevisions(ctx)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ListPackageRevisions succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListPackageRevisions failed: %v", err)
+			}
+
+			var got []string
+			for _, r := range revisions {
+				got = append(got, r.(*gitPackageRevision).path)
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected packages (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestInvalidDirectoryGlob(t *testing.T) {
+	repo := initInMemoryRepository(t)
+	address := ServeExistingRepository(t, repo)
+
+	if _, err := OpenRepository(context.Background(), "glob", "default", &configapi.GitRepository{
+		Repo:      address,
+		Directory: "teams/[*",
+	}, t.TempDir(), GitRepositoryOptions{}); err == nil {
+		t.Errorf("OpenRepository succeeded with invalid directory pattern, want error")
+	}
+}
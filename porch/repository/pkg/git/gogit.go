@@ -56,14 +56,14 @@ func openRepository(path string) (*git.Repository, error) {
 	return git.Open(storage, dot)
 }
 
-func initializeOrigin(repo *git.Repository, address string) error {
+func initializeOrigin(repo *git.Repository, remoteName, address string) error {
 	cfg, err := repo.Config()
 	if err != nil {
 		return err
 	}
 
-	cfg.Remotes[OriginName] = &config.RemoteConfig{
-		Name:  OriginName,
+	cfg.Remotes[remoteName] = &config.RemoteConfig{
+		Name:  remoteName,
 		URLs:  []string{address},
 		Fetch: defaultFetchSpec,
 	}
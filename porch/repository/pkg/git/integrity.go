@@ -0,0 +1,43 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// IntegrityError is returned when the contents of a blob read from the
+// repository do not hash to the object id recorded in its tree.
+type IntegrityError struct {
+	Path     string
+	Expected plumbing.Hash
+	Actual   plumbing.Hash
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("integrity check failed for %q: expected blob %s, got %s", e.Path, e.Expected, e.Actual)
+}
+
+// verifyBlob returns an IntegrityError if the contents of the file do not
+// match the hash of its tree entry.
+func verifyBlob(file *object.File, contents string) error {
+	if actual := plumbing.ComputeHash(plumbing.BlobObject, []byte(contents)); actual != file.Hash {
+		return &IntegrityError{Path: file.Name, Expected: file.Hash, Actual: actual}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// corruptObject stores the contents of one object under the hash of another.
+type corruptObject struct {
+	plumbing.EncodedObject
+	hash plumbing.Hash
+}
+
+func (o *corruptObject) Hash() plumbing.Hash {
+	return o.hash
+}
+
+func TestVerifyIntegrity(t *testing.T) {
+	ctx := context.Background()
+	repo := initInMemoryRepository(t)
+	commitHash := writeTestCommit(t, repo, map[string]string{
+		"config.yaml": "kind: ConfigMap\n",
+	}, nil)
+
+	commit, err := repo.CommitObject(commitHash)
+	if err != nil {
+		t.Fatalf("Failed to read commit: %v", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatalf("Failed to read tree: %v", err)
+	}
+	file, err := tree.File("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to find config.yaml: %v", err)
+	}
+
+	eo := repo.Storer.NewEncodedObject()
+	eo.SetType(plumbing.BlobObject)
+	w, err := eo.Writer()
+	if err != nil {
+		t.Fatalf("Failed to create blob writer: %v", err)
+	}
+	if _, err := w.Write([]byte("kind: Secret\n")); err != nil {
+		t.Fatalf("Failed to write blob: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close blob writer: %v", err)
+	}
+	if _, err := repo.Storer.SetEncodedObject(&corruptObject{EncodedObject: eo, hash: file.Hash}); err != nil {
+		t.Fatalf("Failed to store corrupt blob: %v", err)
+	}
+
+	parent := &gitRepository{name: "repo", namespace: "default", repo: repo}
+	pkg := &gitPackageRevision{parent: parent, path: "", revision: "v1", tree: tree.Hash, commit: commitHash}
+
+	resources, err := pkg.GetResources(ctx)
+	if err != nil {
+		t.Fatalf("GetResources without verification failed: %v", err)
+	}
+	if got, want := resources.Spec.Resources["config.yaml"], "kind: Secret\n"; got != want {
+		t.Errorf("config.yaml: got %q, want %q", got, want)
+	}
+
+	parent.verifyIntegrity = true
+	_, err = pkg.GetResources(ctx)
+	var integrityErr *IntegrityError
+	if !errors.As(err, &integrityErr) {
+		t.Fatalf("GetResources with verification: got error %v, want IntegrityError", err)
+	}
+	if integrityErr.Path != "config.yaml" || integrityErr.Expected != file.Hash {
+		t.Errorf("unexpected integrity error: %v", integrityErr)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// The labels of a package revision are stored as trailers of the commit
+// message of the commit at the head of the package revision, one trailer per
+// label:
+//
+//	Porch-Labels-Package: <package path>
+//	Porch-Label: <key>=<value>
+//
+// As with the generation trailers, the package path guards against reading
+// the trailers of a commit which updated a different package.
+const (
+	labelsPackageTrailer = "Porch-Labels-Package"
+	labelTrailer         = "Porch-Label"
+)
+
+// appendLabelTrailers appends the label trailers to the commit message, in
+// key order. Package revisions without labels record no trailers.
+func appendLabelTrailers(message, pkgPath string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return message
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(message, "\n"))
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "%s: %s\n", labelsPackageTrailer, pkgPath)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s: %s=%s\n", labelTrailer, k, labels[k])
+	}
+	return b.String()
+}
+
+// parseLabelTrailers returns the labels of the package recorded in the commit
+// message, or nil if the message doesn't record any for the package.
+func parseLabelTrailers(message, pkgPath string) map[string]string {
+	var labels map[string]string
+	matched := false
+	for _, line := range strings.Split(message, "\n") {
+		key, value, found := cut(line, ": ")
+		if !found {
+			continue
+		}
+		switch key {
+		case labelsPackageTrailer:
+			matched = value == pkgPath
+		case labelTrailer:
+			k, v, found := cut(value, "=")
+			if !found || !matched {
+				continue
+			}
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// loadLabels returns the labels of the package recorded in the commit.
+func (r *gitRepository) loadLabels(commitHash plumbing.Hash, pkgPath string) (map[string]string, error) {
+	if commitHash.IsZero() {
+		return nil, nil
+	}
+	commit, err := r.repo.CommitObject(commitHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve package commit %s: %w", commitHash, err)
+	}
+	return parseLabelTrailers(commit.Message, pkgPath), nil
+}
+
+// labelsEqual reports whether the two sets of labels are the same, treating
+// nil and empty labels as equal.
+func labelsEqual(a, b map[string]string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLabelTrailers(t *testing.T) {
+	want := map[string]string{"team": "platform", "kpt.dev/tier": "gold"}
+	message := appendLabelTrailers("Update catalog/bucket", "catalog/bucket", want)
+
+	if diff := cmp.Diff(want, parseLabelTrailers(message, "catalog/bucket")); diff != "" {
+		t.Errorf("labels (-want, +got): %s", diff)
+	}
+	if got := parseLabelTrailers(message, "catalog/other"); got != nil {
+		t.Errorf("labels of other package: got %v, want none", got)
+	}
+	if got := appendLabelTrailers("Update catalog/bucket", "catalog/bucket", nil); got != "Update catalog/bucket" {
+		t.Errorf("message without labels: got %q", got)
+	}
+}
+
+func TestDraftLabels(t *testing.T) {
+	tempdir := t.TempDir()
+	tarfile := filepath.Join("testdata", "drafts-repository.tar")
+	_, address := ServeGitRepository(t, tarfile, tempdir)
+
+	ctx := context.Background()
+	git, err := OpenRepository(ctx, "labels", "default", &configapi.GitRepository{
+		Repo:      address,
+		Branch:    "main",
+		Directory: "/",
+	}, tempdir, GitRepositoryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to open Git repository loaded from %q: %v", tarfile, err)
+	}
+
+	setLabels := func(labels map[string]string) {
+		t.Helper()
+		revisions, err := git.ListPackageRevisions(ctx)
+		if err != nil {
+			t.Fatalf("ListPackageRevisions failed: %v", err)
+		}
+		update, err := git.UpdatePackage(ctx, findPackage(t, revisions, "labels:bucket:v1"))
+		if err != nil {
+			t.Fatalf("UpdatePackage failed: %v", err)
+		}
+		if err := update.(repository.LabelRecorder).RecordLabels(ctx, labels); err != nil {
+			t.Fatalf("RecordLabels failed: %v", err)
+		}
+		if _, err := update.Close(ctx); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+	listLabels := func() map[string]string {
+		t.Helper()
+		revisions, err := git.ListPackageRevisions(ctx)
+		if err != nil {
+			t.Fatalf("ListPackageRevisions failed: %v", err)
+		}
+		rev, err := findPackage(t, revisions, "labels:bucket:v1").GetPackageRevision()
+		if err != nil {
+			t.Fatalf("GetPackageRevision failed: %v", err)
+		}
+		return rev.Labels
+	}
+
+	// A label-only change doesn't change the package contents, but is
+	// committed and read back from the repository.
+	want := map[string]string{"team": "platform"}
+	setLabels(want)
+	if diff := cmp.Diff(want, listLabels()); diff != "" {
+		t.Errorf("labels after update (-want, +got): %s", diff)
+	}
+
+	setLabels(nil)
+	if got := listLabels(); len(got) != 0 {
+		t.Errorf("labels after removal: got %v, want none", got)
+	}
+}
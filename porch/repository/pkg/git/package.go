@@ -16,6 +16,7 @@ package git
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"time"
@@ -24,8 +25,11 @@ import (
 	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 type gitPackageRevision struct {
@@ -49,6 +53,48 @@ func (p *gitPackageRevision) uid() types.UID {
 }
 
 func (p *gitPackageRevision) GetPackageRevision() (*v1alpha1.PackageRevision, error) {
+	generation, err := p.parent.loadGeneration(p.commit, p.path)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := p.parent.loadLabels(p.commit, p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	kf := p.kptfile()
+	status := v1alpha1.PackageRevisionStatus{
+		ObservedGeneration: generation.observed,
+		UpstreamLock:       upstreamLock(kf),
+	}
+	render, err := p.parent.loadRenderStatus(p.commit, p.path)
+	if err != nil {
+		return nil, err
+	}
+	if render != nil {
+		status.LastRenderedAt = metav1.Time{Time: render.RenderedAt}
+		status.LastRenderedFunctionDigests = render.FunctionDigests
+	}
+	lifecycle := p.getPackageRevisionLifecycle()
+	if lifecycle == v1alpha1.PackageRevisionLifecycleRejected {
+		if status.RejectionReason, err = p.parent.loadRejectionReason(p.commit); err != nil {
+			return nil, err
+		}
+	}
+	annotations := lineageAnnotations(kf)
+	var changelog string
+	if lifecycle == v1alpha1.PackageRevisionLifecyclePublished {
+		if changelog, err = p.parent.loadChangelog(p.commit, p.path); err != nil {
+			return nil, err
+		}
+		if changelog != "" {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[v1alpha1.ChangelogAnnotationPrefix+p.revision] = changelog
+		}
+	}
+
 	return &v1alpha1.PackageRevision{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PackageRevision",
@@ -59,23 +105,152 @@ func (p *gitPackageRevision) GetPackageRevision() (*v1alpha1.PackageRevision, er
 			Namespace:       p.parent.namespace,
 			UID:             p.uid(),
 			ResourceVersion: p.commit.String(),
+			Generation:      generation.generation,
+			Labels:          labels,
+			Annotations:     annotations,
 			CreationTimestamp: metav1.Time{
 				Time: p.updated,
 			},
 		},
 		Spec: v1alpha1.PackageRevisionSpec{
-			PackageName:    p.path,
-			Revision:       p.revision,
-			RepositoryName: p.parent.name,
-			Lifecycle:      p.getPackageRevisionLifecycle(),
-			Tasks:          []v1alpha1.Task{},
+			PackageName:     p.path,
+			Revision:        p.revision,
+			RepositoryName:  p.parent.name,
+			Lifecycle:       lifecycle,
+			ChangelogEntry:  changelog,
+			Tasks:           []v1alpha1.Task{},
+			RenderPolicy:    renderPolicy(kf),
+			RequiredSecrets: requiredSecrets(kf),
+			Target:          targetCluster(kf),
+			ImageLocks:      imageLocks(kf),
 		},
-		Status: v1alpha1.PackageRevisionStatus{},
+		Status: status,
 	}, nil
 }
 
+// kptfile returns the parsed Kptfile of the package, or nil if the package
+// has no valid Kptfile.
+func (p *gitPackageRevision) kptfile() *kptfile.KptFile {
+	tree, err := p.parent.repo.TreeObject(p.tree)
+	if err != nil {
+		return nil
+	}
+	file, err := tree.File(kptfile.KptFileName)
+	if err != nil {
+		return nil
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		klog.Warningf("Cannot read Kptfile of package %s: %v", p.Name(), err)
+		return nil
+	}
+	var kf kptfile.KptFile
+	if err := yaml.Unmarshal([]byte(contents), &kf); err != nil {
+		klog.Warningf("Cannot parse Kptfile of package %s: %v", p.Name(), err)
+		return nil
+	}
+	return &kf
+}
+
+// lineageAnnotations returns the clone lineage and transfer annotations
+// recorded in the package Kptfile, or nil if there are none.
+func lineageAnnotations(kf *kptfile.KptFile) map[string]string {
+	if kf == nil {
+		return nil
+	}
+	var annotations map[string]string
+	for _, key := range []string{v1alpha1.CloneDepthAnnotation, v1alpha1.CloneAncestryAnnotation, v1alpha1.TransferredFromAnnotation} {
+		if value, ok := kf.Annotations[key]; ok {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
+
+// renderPolicy returns the render policy recorded in the package Kptfile, or
+// an empty policy if there is none.
+func renderPolicy(kf *kptfile.KptFile) v1alpha1.RenderPolicy {
+	if kf == nil {
+		return ""
+	}
+	return v1alpha1.RenderPolicy(kf.Annotations[v1alpha1.RenderPolicyAnnotation])
+}
+
+// requiredSecrets returns the required secrets recorded in the package
+// Kptfile, or nil if there are none.
+func requiredSecrets(kf *kptfile.KptFile) []v1alpha1.RequiredSecret {
+	if kf == nil {
+		return nil
+	}
+	value, ok := kf.Annotations[v1alpha1.RequiredSecretsAnnotation]
+	if !ok {
+		return nil
+	}
+	var secrets []v1alpha1.RequiredSecret
+	if err := json.Unmarshal([]byte(value), &secrets); err != nil {
+		klog.Warningf("Cannot parse %s annotation of Kptfile %s: %v", v1alpha1.RequiredSecretsAnnotation, kf.Name, err)
+		return nil
+	}
+	return secrets
+}
+
+// imageLocks returns the function image locks recorded in the package
+// Kptfile, or nil if there are none.
+func imageLocks(kf *kptfile.KptFile) map[string]string {
+	if kf == nil {
+		return nil
+	}
+	value, ok := kf.Annotations[v1alpha1.ImageLocksAnnotation]
+	if !ok {
+		return nil
+	}
+	var locks map[string]string
+	if err := json.Unmarshal([]byte(value), &locks); err != nil {
+		klog.Warningf("Cannot parse %s annotation of Kptfile %s: %v", v1alpha1.ImageLocksAnnotation, kf.Name, err)
+		return nil
+	}
+	return locks
+}
+
+// targetCluster returns the target cluster recorded in the package Kptfile,
+// or nil if there is none.
+func targetCluster(kf *kptfile.KptFile) *v1alpha1.PackageRevisionTarget {
+	if kf == nil {
+		return nil
+	}
+	name, ok := kf.Annotations[v1alpha1.TargetKubeconfigAnnotation]
+	if !ok {
+		return nil
+	}
+	return &v1alpha1.PackageRevisionTarget{
+		Kubeconfig: &v1alpha1.SecretRef{Name: name},
+	}
+}
+
+// upstreamLock returns the git upstream lock recorded in the package
+// Kptfile, or nil if the package was not cloned from a git upstream.
+func upstreamLock(kf *kptfile.KptFile) *v1alpha1.UpstreamLock {
+	if kf == nil || kf.UpstreamLock == nil || kf.UpstreamLock.Type != kptfile.GitOrigin || kf.UpstreamLock.Git == nil {
+		return nil
+	}
+	git := kf.UpstreamLock.Git
+	return &v1alpha1.UpstreamLock{
+		Type: v1alpha1.RepositoryTypeGit,
+		Git: &v1alpha1.GitLock{
+			Repo:      git.Repo,
+			Directory: git.Directory,
+			Ref:       git.Ref,
+			Commit:    git.Commit,
+		},
+	}
+}
+
 func (p *gitPackageRevision) GetResources(ctx context.Context) (*v1alpha1.PackageRevisionResources, error) {
 	resources := map[string]string{}
+	files := map[string]v1alpha1.FileMetadata{}
 
 	tree, err := p.parent.repo.TreeObject(p.tree)
 	if err == nil {
@@ -94,10 +269,20 @@ func (p *gitPackageRevision) GetResources(ctx context.Context) (*v1alpha1.Packag
 			if err != nil {
 				return nil, fmt.Errorf("failed to read package file contents: %q, %w", file.Name, err)
 			}
+			if p.parent.verifyIntegrity {
+				if err := verifyBlob(file, content); err != nil {
+					return nil, err
+				}
+			}
 
 			// TODO: decide whether paths should include package directory or not.
 			resources[file.Name] = content
 			//resources[path.Join(p.path, file.Name)] = content
+			files[file.Name] = detectFileMetadata(file.Name, content)
+		}
+
+		if err := p.addSubmoduleResources(ctx, tree, resources, files); err != nil {
+			return nil, err
 		}
 	}
 	return &v1alpha1.PackageRevisionResources{
@@ -118,9 +303,42 @@ func (p *gitPackageRevision) GetResources(ctx context.Context) (*v1alpha1.Packag
 		Spec: v1alpha1.PackageRevisionResourcesSpec{
 			Resources: resources,
 		},
+		Status: v1alpha1.PackageRevisionResourcesStatus{
+			Files: files,
+		},
 	}, nil
 }
 
+// addSubmoduleResources adds the files of the submodules within the package to
+// the resources, if the repository includes submodules.
+func (p *gitPackageRevision) addSubmoduleResources(ctx context.Context, tree *object.Tree, resources map[string]string, files map[string]v1alpha1.FileMetadata) error {
+	if p.parent.submoduleDepth <= 0 {
+		return nil
+	}
+	repoURL, err := p.parent.getRepo()
+	if err != nil {
+		return err
+	}
+	commit, err := p.parent.repo.CommitObject(p.commit)
+	if err != nil {
+		return fmt.Errorf("cannot read package commit %s: %w", p.commit, err)
+	}
+	root, err := commit.Tree()
+	if err != nil {
+		return fmt.Errorf("cannot read package commit %s: %w", p.commit, err)
+	}
+
+	submoduleFiles := map[string]string{}
+	if err := p.parent.addSubmoduleFiles(ctx, repoURL, root, p.path, tree, p.parent.submoduleDepth, submoduleFiles); err != nil {
+		return fmt.Errorf("failed to load package submodules: %w", err)
+	}
+	for name, content := range submoduleFiles {
+		resources[name] = content
+		files[name] = detectFileMetadata(name, content)
+	}
+	return nil
+}
+
 func (p *gitPackageRevision) GetUpstreamLock() (kptfile.Upstream, kptfile.UpstreamLock, error) {
 	repo, err := p.parent.getRepo()
 	if err != nil {
@@ -153,6 +371,8 @@ func (p *gitPackageRevision) getPackageRevisionLifecycle() v1alpha1.PackageRevis
 		return v1alpha1.PackageRevisionLifecycleDraft
 	case isProposedBranchNameInLocal(ref.Name()):
 		return v1alpha1.PackageRevisionLifecycleProposed
+	case isRejectedBranchNameInLocal(ref.Name()):
+		return v1alpha1.PackageRevisionLifecycleRejected
 	default:
 		return v1alpha1.PackageRevisionLifecyclePublished
 	}
@@ -0,0 +1,90 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"k8s.io/klog/v2"
+)
+
+// pruneStaleRefs deletes draft, proposed and rejected branches which do not correspond
+// to a package revision. Such branches are left behind when a lifecycle
+// transition is interrupted (for example by a crash) after some, but not all,
+// references have been updated in the remote repository:
+//   - branches whose name cannot be parsed into a package name and revision,
+//   - draft, proposed or rejected branches of a package revision which is already published,
+//   - draft branches of a package revision which is also proposed.
+func (r *gitRepository) pruneStaleRefs(ctx context.Context) error {
+	refs, err := r.repo.References()
+	if err != nil {
+		return err
+	}
+	defer refs.Close()
+
+	tags := map[plumbing.ReferenceName]bool{}
+	proposed := map[plumbing.ReferenceName]bool{}
+	var candidates []*plumbing.Reference
+
+	for {
+		ref, err := refs.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		switch name := ref.Name(); {
+		case isTagInLocalRepo(name):
+			tags[name] = true
+		case isProposedBranchNameInLocal(name):
+			proposed[name] = true
+			candidates = append(candidates, ref)
+		case isDraftBranchNameInLocal(name), isRejectedBranchNameInLocal(name):
+			candidates = append(candidates, ref)
+		}
+	}
+
+	refSpecs := newPushRefSpecBuilder()
+	pruned := 0
+
+	for _, ref := range candidates {
+		name, revision, err := parseDraftName(ref)
+		switch {
+		case err != nil:
+		case tags[createFinalTagNameInLocal(name, revision)]:
+		case isDraftBranchNameInLocal(ref.Name()) && proposed[createProposedName(name, revision).RefInLocal()]:
+		default:
+			continue
+		}
+
+		klog.V(2).Infof("Pruning stale ref %s in repository %s/%s", ref.Name(), r.namespace, r.name)
+		refSpecs.AddRefToDelete(ref)
+		pruned++
+	}
+
+	if pruned == 0 {
+		return nil
+	}
+
+	if err := r.pushAndCleanup(ctx, refSpecs); err != nil {
+		return fmt.Errorf("failed to delete stale refs in repository %s/%s: %w", r.namespace, r.name, err)
+	}
+	// Refresh local references to reflect the deleted branches.
+	return r.fetchRemoteRepository(ctx)
+}
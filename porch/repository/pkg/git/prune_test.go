@@ -0,0 +1,72 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestPruneStaleRefs(t *testing.T) {
+	tempdir := t.TempDir()
+	tarfile := filepath.Join("testdata", "drafts-repository.tar")
+	repo, address := ServeGitRepository(t, tarfile, tempdir)
+
+	published := resolveReference(t, repo, "refs/tags/basens/v1")
+	draft := resolveReference(t, repo, "refs/heads/drafts/bucket/v1")
+
+	for _, ref := range []*plumbing.Reference{
+		// Draft of an already published package revision.
+		plumbing.NewHashReference("refs/heads/drafts/basens/v1", published.Hash()),
+		// Proposed copy of a draft; the draft is stale.
+		plumbing.NewHashReference("refs/heads/proposed/bucket/v1", draft.Hash()),
+		// Draft branch name without a revision.
+		plumbing.NewHashReference("refs/heads/drafts/invalid", draft.Hash()),
+	} {
+		if err := repo.Storer.SetReference(ref); err != nil {
+			t.Fatalf("SetReference(%q) failed: %v", ref.Name(), err)
+		}
+	}
+
+	ctx := context.Background()
+	git, err := OpenRepository(ctx, "prune", "default", &configapi.GitRepository{
+		Repo: address,
+	}, tempdir, GitRepositoryOptions{PruneStaleRefs: true})
+	if err != nil {
+		t.Fatalf("OpenRepository(%q) failed: %v", address, err)
+	}
+
+	for _, name := range []plumbing.ReferenceName{
+		"refs/heads/drafts/basens/v1",
+		"refs/heads/drafts/bucket/v1",
+		"refs/heads/drafts/invalid",
+	} {
+		refMustNotExist(t, repo, name)
+	}
+	for _, name := range []plumbing.ReferenceName{
+		"refs/heads/proposed/bucket/v1",
+		"refs/heads/drafts/none/v1",
+		"refs/tags/basens/v1",
+	} {
+		refMustExist(t, repo, name)
+	}
+
+	repositoryMustHavePackageRevision(t, git, "prune:bucket:v1")
+	repositoryMustHavePackageRevision(t, git, "prune:none:v1")
+}
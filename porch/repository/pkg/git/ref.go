@@ -25,6 +25,8 @@ import (
 const (
 	MainBranch BranchName = "main"
 
+	// Remote branches are tracked under the 'origin' prefix regardless of
+	// the configured remote name; the fetch spec maps them explicitly.
 	branchPrefixInLocalRepo  = "refs/remotes/" + OriginName + "/"
 	branchPrefixInRemoteRepo = "refs/heads/"
 	tagsPrefixInLocalRepo    = "refs/tags/"
@@ -39,6 +41,9 @@ const (
 	proposedPrefix             = "proposed/"
 	proposedPrefixInLocalRepo  = branchPrefixInLocalRepo + proposedPrefix
 	proposedPrefixInRemoteRepo = branchPrefixInRemoteRepo + proposedPrefix
+	rejectedPrefix             = "rejected/"
+	rejectedPrefixInLocalRepo  = branchPrefixInLocalRepo + rejectedPrefix
+	rejectedPrefixInRemoteRepo = branchPrefixInRemoteRepo + rejectedPrefix
 )
 
 var (
@@ -84,6 +89,15 @@ func getProposedBranchNameInLocal(n plumbing.ReferenceName) (BranchName, bool) {
 	return BranchName(b), ok
 }
 
+func isRejectedBranchNameInLocal(n plumbing.ReferenceName) bool {
+	return strings.HasPrefix(n.String(), rejectedPrefixInLocalRepo)
+}
+
+func getRejectedBranchNameInLocal(n plumbing.ReferenceName) (BranchName, bool) {
+	b, ok := trimOptionalPrefix(n.String(), rejectedPrefixInLocalRepo)
+	return BranchName(b), ok
+}
+
 func isDraftBranchNameInLocal(n plumbing.ReferenceName) bool {
 	return strings.HasPrefix(n.String(), draftsPrefixInLocalRepo)
 }
@@ -117,6 +131,10 @@ func createProposedName(pkg, rev string) BranchName {
 	return BranchName(proposedPrefix + pkg + "/" + rev)
 }
 
+func createRejectedName(pkg, rev string) BranchName {
+	return BranchName(rejectedPrefix + pkg + "/" + rev)
+}
+
 func trimOptionalPrefix(s, prefix string) (string, bool) {
 	if strings.HasPrefix(s, prefix) {
 		return strings.TrimPrefix(s, prefix), true
@@ -0,0 +1,86 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// The reason a package revision was rejected is stored as a trailer of the
+// commit message of the commit at the head of its rejected branch:
+//
+//	Porch-Rejection-Reason: <reason>
+const rejectionReasonTrailer = "Porch-Rejection-Reason"
+
+// appendRejectionTrailer appends the rejection trailer to the commit message.
+// The reason is collapsed onto a single line to keep the trailer parseable.
+func appendRejectionTrailer(message, reason string) string {
+	reason = strings.Join(strings.Fields(reason), " ")
+	if reason == "" {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + fmt.Sprintf("%s: %s\n", rejectionReasonTrailer, reason)
+}
+
+// parseRejectionTrailer returns the rejection reason recorded in the commit
+// message, or "" if the message doesn't record one.
+func parseRejectionTrailer(message string) string {
+	reason := ""
+	for _, line := range strings.Split(message, "\n") {
+		if key, value, found := cut(line, ": "); found && key == rejectionReasonTrailer {
+			reason = value
+		}
+	}
+	return reason
+}
+
+// commitRejection commits the rejection of the package draft on top of its
+// current commit, keeping the package tree and its render status.
+func (r *gitRepository) commitRejection(ctx context.Context, d *gitPackageDraft) (plumbing.Hash, error) {
+	ch, err := newCommitHelper(r.repo.Storer, r.userInfoProvider, d.commit, d.path, d.tree)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to initialize commit of package %s rejection: %w", d.path, err)
+	}
+	message, err := r.commitMessage(ctx, fmt.Sprintf("Reject %s", d.path), d.commitMessageData())
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	render, err := r.loadRenderStatus(d.commit, d.path)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	message = appendRenderTrailers(message, d.path, render)
+	message = appendGenerationTrailers(message, d.path, d.generation)
+	message = appendLabelTrailers(message, d.path, d.labels)
+	message = appendRejectionTrailer(message, d.rejection)
+	commitHash, _, err := ch.commit(ctx, message, d.path)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to commit package %s rejection: %w", d.path, err)
+	}
+	return commitHash, nil
+}
+
+// loadRejectionReason returns the rejection reason recorded in the commit.
+func (r *gitRepository) loadRejectionReason(commitHash plumbing.Hash) (string, error) {
+	commit, err := r.repo.CommitObject(commitHash)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve package commit %s: %w", commitHash, err)
+	}
+	return parseRejectionTrailer(commit.Message), nil
+}
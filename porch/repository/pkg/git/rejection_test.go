@@ -0,0 +1,44 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+)
+
+func TestRejectionTrailer(t *testing.T) {
+	render := &repository.RenderStatus{
+		RenderedAt:      time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC),
+		FunctionDigests: map[string]string{},
+	}
+	message := appendRenderTrailers("Reject catalog/bucket", "catalog/bucket", render)
+	message = appendRejectionTrailer(message, "bucket must not\n  be public")
+
+	if got, want := parseRejectionTrailer(message), "bucket must not be public"; got != want {
+		t.Errorf("rejection reason: got %q, want %q", got, want)
+	}
+	if got := parseRenderTrailers(message, "catalog/bucket"); got == nil {
+		t.Errorf("render status lost by the rejection trailer:\n%s", message)
+	}
+	if got := parseRejectionTrailer("Approve catalog/bucket"); got != "" {
+		t.Errorf("rejection reason returned for commit without trailer: %q", got)
+	}
+	if got, want := appendRejectionTrailer("Reject catalog/bucket", " "), "Reject catalog/bucket"; got != want {
+		t.Errorf("message with empty rejection reason: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// ResolveRemoteRefs lists the branches and tags of the remote repository and
+// returns the commits they point to, keyed by the short branch or tag name.
+// Annotated tags are resolved to the commits they tag. Branches take
+// precedence over tags of the same name.
+func ResolveRemoteRefs(ctx context.Context, url string) (map[string]string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: OriginName,
+		URLs: []string{url},
+	})
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list references of remote repository %s: %w", url, err)
+	}
+
+	branches := map[string]string{}
+	tags := map[string]string{}
+	for _, ref := range refs {
+		if ref.Type() != plumbing.HashReference {
+			continue
+		}
+		name := ref.Name()
+		switch {
+		case name.IsBranch():
+			branches[name.Short()] = ref.Hash().String()
+		case name.IsTag():
+			// Peeled annotated tags are listed as `refs/tags/<tag>^{}` and
+			// override the tag object hash.
+			tag := name.Short()
+			if peeled := len(tag) - len("^{}"); peeled > 0 && tag[peeled:] == "^{}" {
+				tags[tag[:peeled]] = ref.Hash().String()
+			} else if _, found := tags[tag]; !found {
+				tags[tag] = ref.Hash().String()
+			}
+		}
+	}
+
+	resolved := tags
+	for branch, commit := range branches {
+		resolved[branch] = commit
+	}
+	return resolved, nil
+}
+
+// TestConnection lists the references of the git repository, with the
+// credentials of its secret if it has one, to verify that porch can reach and
+// read it. An empty repository is reachable. It returns the user name of the
+// credentials, if any.
+func TestConnection(ctx context.Context, namespace string, spec *configapi.GitRepository, resolver repository.CredentialResolver) (string, error) {
+	var auth transport.AuthMethod
+	var authenticatedAs string
+	if spec.SecretRef.Name != "" {
+		var err error
+		if auth, err = resolveCredential(ctx, namespace, spec.SecretRef.Name, resolver); err != nil {
+			return "", err
+		}
+		if basic, ok := auth.(*http.BasicAuth); ok {
+			authenticatedAs = basic.Username
+		}
+	}
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: OriginName,
+		URLs: []string{spec.Repo},
+	})
+	switch _, err := remote.List(&git.ListOptions{Auth: auth}); err {
+	case nil, transport.ErrEmptyRemoteRepository:
+		return authenticatedAs, nil
+	default:
+		return authenticatedAs, fmt.Errorf("cannot list references of remote repository %s: %w", spec.Repo, err)
+	}
+}
+
+// detectDefaultBranch returns the branch HEAD of the remote repository refers
+// to, as `git ls-remote --symref` reports it. It returns MainBranch if the
+// remote repository is empty or does not advertise the target of HEAD.
+func (r *gitRepository) detectDefaultBranch(ctx context.Context) (BranchName, error) {
+	auth, err := r.getAuthMethod(ctx)
+	if err != nil {
+		return "", err
+	}
+	remote, err := r.repo.Remote(r.remoteName)
+	if err != nil {
+		return "", fmt.Errorf("cannot determine repository origin: %w", err)
+	}
+
+	var refs []*plumbing.Reference
+	switch err := r.doRemote(func() error {
+		var err error
+		refs, err = remote.List(&git.ListOptions{Auth: auth})
+		return err
+	}); err {
+	case nil: // OK
+	case transport.ErrEmptyRemoteRepository:
+		return MainBranch, nil
+	default:
+		return "", fmt.Errorf("cannot detect default branch of repository %s/%s: %w", r.namespace, r.name, err)
+	}
+	return defaultBranch(refs), nil
+}
+
+// defaultBranch returns the branch the symbolic HEAD reference among refs
+// refers to, or MainBranch if there is none.
+func defaultBranch(refs []*plumbing.Reference) BranchName {
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference && ref.Target().IsBranch() {
+			return BranchName(ref.Target().Short())
+		}
+	}
+	return MainBranch
+}
@@ -0,0 +1,174 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+type staticCredentialResolver struct {
+	username, password string
+}
+
+func (r *staticCredentialResolver) ResolveCredential(ctx context.Context, namespace, name string) (repository.Credential, error) {
+	return repository.Credential{
+		Data: map[string][]byte{
+			"username": []byte(r.username),
+			"password": []byte(r.password),
+		},
+	}, nil
+}
+
+func TestTestConnection(t *testing.T) {
+	ctx := context.Background()
+	resolver := &staticCredentialResolver{username: "porch", password: "secret"}
+
+	for _, tarfile := range []string{"simple-repository.tar", "empty-repository.tar"} {
+		t.Run(tarfile, func(t *testing.T) {
+			_, address := ServeGitRepository(t, filepath.Join("testdata", tarfile), t.TempDir())
+
+			authenticatedAs, err := TestConnection(ctx, "default", &configapi.GitRepository{Repo: address}, resolver)
+			if err != nil {
+				t.Fatalf("TestConnection failed: %v", err)
+			}
+			if authenticatedAs != "" {
+				t.Errorf("TestConnection without secret authenticated as %q", authenticatedAs)
+			}
+
+			authenticatedAs, err = TestConnection(ctx, "default", &configapi.GitRepository{
+				Repo:      address,
+				SecretRef: configapi.SecretRef{Name: "git-credentials"},
+			}, resolver)
+			if err != nil {
+				t.Fatalf("TestConnection with secret failed: %v", err)
+			}
+			if got, want := authenticatedAs, "porch"; got != want {
+				t.Errorf("TestConnection authenticated as %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTestConnectionUnreachable(t *testing.T) {
+	// Reserve a port and release it so that nothing is listening on it.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	address := "http://" + lis.Addr().String()
+	lis.Close()
+
+	if _, err := TestConnection(context.Background(), "default", &configapi.GitRepository{Repo: address}, nil); err == nil {
+		t.Errorf("TestConnection to %s succeeded, want error", address)
+	}
+}
+
+// renameMainBranch renames the main branch of the repository and points HEAD
+// to the renamed branch.
+func renameMainBranch(t *testing.T, repo *gogit.Repository, branch string) {
+	main := resolveReference(t, repo, plumbing.NewBranchReferenceName(string(MainBranch)))
+	renamed := plumbing.NewBranchReferenceName(branch)
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(renamed, main.Hash())); err != nil {
+		t.Fatalf("SetReference(%s) failed: %v", renamed, err)
+	}
+	if err := repo.Storer.RemoveReference(main.Name()); err != nil {
+		t.Fatalf("RemoveReference(%s) failed: %v", main.Name(), err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, renamed)); err != nil {
+		t.Fatalf("SetReference(HEAD) failed: %v", err)
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		tarfile           string
+		headBranch        string // Renamed main branch of the served repository; empty to keep main
+		branch            string // Registered branch
+		wantBranch        BranchName
+		wantDefaultBranch string
+	}{
+		{
+			name:              "main",
+			tarfile:           "simple-repository.tar",
+			wantBranch:        "main",
+			wantDefaultBranch: "main",
+		},
+		{
+			name:              "non-standard default branch",
+			tarfile:           "simple-repository.tar",
+			headBranch:        "trunk",
+			wantBranch:        "trunk",
+			wantDefaultBranch: "trunk",
+		},
+		{
+			name:              "registered branch",
+			tarfile:           "simple-repository.tar",
+			headBranch:        "trunk",
+			branch:            "trunk",
+			wantBranch:        "trunk",
+			wantDefaultBranch: "",
+		},
+		{
+			name:              "empty repository",
+			tarfile:           "empty-repository.tar",
+			wantBranch:        "main",
+			wantDefaultBranch: "main",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tempdir := t.TempDir()
+			repo := OpenGitRepositoryFromArchive(t, filepath.Join("testdata", tc.tarfile), tempdir)
+			if tc.headBranch != "" {
+				renameMainBranch(t, repo, tc.headBranch)
+			}
+			address := ServeExistingRepository(t, repo)
+
+			ctx := context.Background()
+			git, err := OpenRepository(ctx, "simple", "default", &configapi.GitRepository{
+				Repo:   address,
+				Branch: tc.branch,
+			}, tempdir, GitRepositoryOptions{})
+			if err != nil {
+				t.Fatalf("OpenRepository(%q) failed: %v", address, err)
+			}
+
+			gitRepo := git.(*gitRepository)
+			if got, want := gitRepo.branch, tc.wantBranch; got != want {
+				t.Errorf("branch: got %q, want %q", got, want)
+			}
+			if got, want := gitRepo.DefaultBranch(), tc.wantDefaultBranch; got != want {
+				t.Errorf("DefaultBranch(): got %q, want %q", got, want)
+			}
+
+			if tc.tarfile != "simple-repository.tar" {
+				return
+			}
+			revisions, err := git.ListPackageRevisions(ctx)
+			if err != nil {
+				t.Fatalf("ListPackageRevisions failed: %v", err)
+			}
+			findPackage(t, revisions, "simple:basens:"+string(tc.wantBranch))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// The render status of a package revision is stored as trailers of the
+// commit message of the commit containing the rendered package:
+//
+//	Porch-Rendered-Package: <package path>
+//	Porch-Rendered-At: <RFC3339 timestamp>
+//	Porch-Rendered-Function: <image> <digest>
+//
+// The package path guards against reading the trailers of a commit which
+// rendered a different package, e.g. at the head of the main branch.
+const (
+	renderedPackageTrailer  = "Porch-Rendered-Package"
+	renderedAtTrailer       = "Porch-Rendered-At"
+	renderedFunctionTrailer = "Porch-Rendered-Function"
+)
+
+// formatRenderTrailers formats the render status of the package as commit message trailers.
+func formatRenderTrailers(pkgPath string, status *repository.RenderStatus) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n", renderedPackageTrailer, pkgPath)
+	fmt.Fprintf(&b, "%s: %s\n", renderedAtTrailer, status.RenderedAt.UTC().Format(time.RFC3339))
+
+	images := make([]string, 0, len(status.FunctionDigests))
+	for image := range status.FunctionDigests {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	for _, image := range images {
+		fmt.Fprintf(&b, "%s: %s %s\n", renderedFunctionTrailer, image, status.FunctionDigests[image])
+	}
+	return b.String()
+}
+
+// appendRenderTrailers appends the render trailers to the commit message.
+func appendRenderTrailers(message, pkgPath string, status *repository.RenderStatus) string {
+	if status == nil {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + formatRenderTrailers(pkgPath, status)
+}
+
+// parseRenderTrailers returns the render status of the package recorded in the
+// commit message, or nil if the message doesn't record a render of the package.
+func parseRenderTrailers(message, pkgPath string) *repository.RenderStatus {
+	var status *repository.RenderStatus
+	matched := false
+
+	for _, line := range strings.Split(message, "\n") {
+		key, value, found := cut(line, ": ")
+		if !found {
+			continue
+		}
+		switch key {
+		case renderedPackageTrailer:
+			matched = value == pkgPath
+		case renderedAtTrailer:
+			renderedAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				continue
+			}
+			status = &repository.RenderStatus{
+				RenderedAt:      renderedAt,
+				FunctionDigests: map[string]string{},
+			}
+		case renderedFunctionTrailer:
+			if status == nil {
+				continue
+			}
+			image, digest, _ := cut(value, " ")
+			status.FunctionDigests[image] = digest
+		}
+	}
+
+	if !matched {
+		return nil
+	}
+	return status
+}
+
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// loadRenderStatus returns the render status of the package recorded in the commit.
+func (r *gitRepository) loadRenderStatus(commitHash plumbing.Hash, pkgPath string) (*repository.RenderStatus, error) {
+	commit, err := r.repo.CommitObject(commitHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve package commit %s: %w", commitHash, err)
+	}
+	return parseRenderTrailers(commit.Message, pkgPath), nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/porch/repository/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderTrailers(t *testing.T) {
+	status := &repository.RenderStatus{
+		RenderedAt: time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC),
+		FunctionDigests: map[string]string{
+			"gcr.io/kpt-fn/set-labels:v0.1":    "sha256:aaa",
+			"gcr.io/kpt-fn/set-namespace:v0.2": "",
+		},
+	}
+
+	message := appendRenderTrailers("Intermittent commit: eval", "catalog/bucket", status)
+
+	if got := parseRenderTrailers(message, "catalog/bucket"); !cmp.Equal(status, got) {
+		t.Errorf("unexpected render status (-want, +got): %s", cmp.Diff(status, got))
+	}
+	if got := parseRenderTrailers(message, "catalog"); got != nil {
+		t.Errorf("render status of a different package returned: %v", got)
+	}
+	if got := parseRenderTrailers("Approve catalog/bucket", "catalog/bucket"); got != nil {
+		t.Errorf("render status returned for commit without trailers: %v", got)
+	}
+}
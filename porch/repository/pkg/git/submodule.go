@@ -0,0 +1,389 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	neturl "net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+const (
+	gitmodulesFile = ".gitmodules"
+
+	// maxSubmoduleCacheEntries is the number of submodule commits whose files
+	// are cached.
+	maxSubmoduleCacheEntries = 64
+)
+
+// allowedSubmoduleProtocols are the protocols submodules may be fetched with,
+// in addition to the protocol of the parent repository.
+var allowedSubmoduleProtocols = map[string]bool{
+	"https": true,
+	"ssh":   true,
+}
+
+// submoduleCache caches the files of submodule commits. Commits are
+// immutable, so entries never need to be refreshed; at most size commits are
+// cached, and the least recently used commit is evicted first.
+type submoduleCache struct {
+	size int
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // of *submoduleCacheEntry, most recently used first
+}
+
+type submoduleCacheEntry struct {
+	key   string
+	files map[string]string
+}
+
+func newSubmoduleCache() *submoduleCache {
+	return &submoduleCache{
+		size:    maxSubmoduleCacheEntries,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+func (c *submoduleCache) get(key string) (map[string]string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*submoduleCacheEntry).files, true
+}
+
+func (c *submoduleCache) put(key string, files map[string]string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, found := c.entries[key]; found {
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&submoduleCacheEntry{key: key, files: files})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*submoduleCacheEntry).key)
+	}
+}
+
+// submoduleBreakers guards the fetches from each submodule repository with a
+// circuit breaker of its own, so that an unavailable submodule doesn't open
+// the circuit of the parent repository.
+type submoduleBreakers struct {
+	resetTimeout time.Duration
+
+	mutex    sync.Mutex
+	breakers map[string]*CircuitBreaker // by submodule url
+}
+
+func newSubmoduleBreakers(resetTimeout time.Duration) *submoduleBreakers {
+	return &submoduleBreakers{
+		resetTimeout: resetTimeout,
+		breakers:     map[string]*CircuitBreaker{},
+	}
+}
+
+// get returns the circuit breaker of the submodule repository at url.
+func (b *submoduleBreakers) get(url string) *CircuitBreaker {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	breaker, found := b.breakers[url]
+	if !found {
+		breaker = NewCircuitBreaker(b.resetTimeout)
+		b.breakers[url] = breaker
+	}
+	return breaker
+}
+
+// addSubmoduleFiles adds the files of the submodules found in tree, located at
+// dir within the root tree of the repository at repoURL, to files. Paths are
+// relative to tree. Nested submodules are included up to depth levels deep.
+func (r *gitRepository) addSubmoduleFiles(ctx context.Context, repoURL string, root *object.Tree, dir string, tree *object.Tree, depth int, files map[string]string) error {
+	if depth <= 0 {
+		return nil
+	}
+
+	var modules *config.Modules
+	return walkSubmodules(tree, "", func(name string, commit plumbing.Hash) error {
+		if modules == nil {
+			m, err := readGitmodules(root)
+			if err != nil {
+				return err
+			}
+			modules = m
+		}
+
+		modulePath := path.Join(dir, name)
+		var module *config.Submodule
+		for _, m := range modules.Submodules {
+			if path.Clean(m.Path) == modulePath {
+				module = m
+				break
+			}
+		}
+		if module == nil {
+			return fmt.Errorf("submodule %q not found in %s", modulePath, gitmodulesFile)
+		}
+
+		url, err := resolveSubmoduleURL(repoURL, module.URL)
+		if err != nil {
+			return err
+		}
+		submoduleFiles, err := r.submoduleFiles(ctx, repoURL, url, commit, depth)
+		if err != nil {
+			return fmt.Errorf("cannot read submodule %q: %w", modulePath, err)
+		}
+		for file, content := range submoduleFiles {
+			files[path.Join(name, file)] = content
+		}
+		return nil
+	})
+}
+
+// walkSubmodules calls fn with the path and commit of every submodule in tree.
+func walkSubmodules(tree *object.Tree, dir string, fn func(name string, commit plumbing.Hash) error) error {
+	for _, e := range tree.Entries {
+		switch e.Mode {
+		case filemode.Submodule:
+			if err := fn(path.Join(dir, e.Name), e.Hash); err != nil {
+				return err
+			}
+		case filemode.Dir:
+			subtree, err := tree.Tree(e.Name)
+			if err != nil {
+				return fmt.Errorf("cannot read directory %q: %w", path.Join(dir, e.Name), err)
+			}
+			if err := walkSubmodules(subtree, path.Join(dir, e.Name), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func readGitmodules(root *object.Tree) (*config.Modules, error) {
+	modules := config.NewModules()
+	file, err := root.File(gitmodulesFile)
+	if err == object.ErrFileNotFound {
+		return modules, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", gitmodulesFile, err)
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", gitmodulesFile, err)
+	}
+	if err := modules.Unmarshal([]byte(contents)); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", gitmodulesFile, err)
+	}
+	return modules, nil
+}
+
+// resolveSubmoduleURL resolves submodule URLs relative to the URL of the
+// parent repository ("../other.git"), and checks that the submodule may be
+// fetched.
+func resolveSubmoduleURL(parentURL, url string) (string, error) {
+	resolved := url
+	if strings.HasPrefix(url, "./") || strings.HasPrefix(url, "../") {
+		parent, err := neturl.Parse(parentURL)
+		if err != nil {
+			return "", fmt.Errorf("cannot resolve relative submodule url %q: %w", url, err)
+		}
+		parent.Path = path.Join(parent.Path, url)
+		resolved = parent.String()
+	}
+	if err := checkSubmoduleURL(parentURL, resolved); err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+// checkSubmoduleURL rejects submodule URLs which would let the .gitmodules
+// file of a repository make Porch read local files or reach other servers:
+// submodules must be hosted on the server of the parent repository, and
+// fetched over https, ssh or the protocol of the parent repository.
+func checkSubmoduleURL(parentURL, url string) error {
+	parent, err := transport.NewEndpoint(parentURL)
+	if err != nil {
+		return fmt.Errorf("invalid repository url %q: %w", parentURL, err)
+	}
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return fmt.Errorf("invalid submodule url %q: %w", url, err)
+	}
+	if endpoint.Protocol == "file" || (!allowedSubmoduleProtocols[endpoint.Protocol] && endpoint.Protocol != parent.Protocol) {
+		return fmt.Errorf("submodule url %q uses disallowed protocol %q", url, endpoint.Protocol)
+	}
+	if !strings.EqualFold(endpoint.Host, parent.Host) {
+		return fmt.Errorf("submodule url %q is not hosted on %s", url, parent.Host)
+	}
+	return nil
+}
+
+// submoduleFiles returns the files of the submodule repository at the commit.
+func (r *gitRepository) submoduleFiles(ctx context.Context, parentURL, url string, commit plumbing.Hash, depth int) (map[string]string, error) {
+	key := fmt.Sprintf("%s@%s:%d", url, commit, depth)
+	if files, ok := r.submodules.get(key); ok {
+		return files, nil
+	}
+
+	repo, err := r.fetchSubmodule(ctx, parentURL, url, commit)
+	if err != nil {
+		return nil, err
+	}
+	c, err := repo.CommitObject(commit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find commit %s in %s: %w", commit, url, err)
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read tree of commit %s in %s: %w", commit, url, err)
+	}
+
+	files := map[string]string{}
+	fit := tree.Files()
+	defer fit.Close()
+	for {
+		file, err := fit.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot read files of %s: %w", url, err)
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read file %q of %s: %w", file.Name, url, err)
+		}
+		files[file.Name] = content
+	}
+	if err := r.addSubmoduleFiles(ctx, url, tree, "", tree, depth-1, files); err != nil {
+		return nil, err
+	}
+
+	r.submodules.put(key, files)
+	return files, nil
+}
+
+// fetchSubmodule fetches the submodule commit into memory. The repository
+// credentials are only used if the submodule is hosted on the same server.
+func (r *gitRepository) fetchSubmodule(ctx context.Context, parentURL, url string, commit plumbing.Hash) (*git.Repository, error) {
+	var auth transport.AuthMethod
+	if sameHost(parentURL, url) {
+		a, err := r.getAuthMethod(ctx)
+		if err != nil {
+			return nil, err
+		}
+		auth = a
+	}
+
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid submodule url %q: %w", url, err)
+	}
+	c, err := client.NewClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := doGuarded(r.submoduleBreakers.get(url), func() error {
+		return fetchCommit(ctx, c, endpoint, auth, repo.Storer, commit)
+	}); err != nil {
+		return nil, fmt.Errorf("cannot fetch commit %s of submodule repository %s: %w", commit, url, err)
+	}
+	return repo, nil
+}
+
+// fetchCommit fetches the objects of the commit, without its history if the
+// server supports shallow fetches. Submodule commits are usually not at the
+// head of any branch, so the commit is requested by hash rather than with a
+// refspec.
+func fetchCommit(ctx context.Context, c transport.Transport, endpoint *transport.Endpoint, auth transport.AuthMethod, s storer.Storer, commit plumbing.Hash) error {
+	session, err := c.NewUploadPackSession(endpoint, auth)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	refs, err := session.AdvertisedReferences()
+	if err != nil {
+		return err
+	}
+
+	req := packp.NewUploadPackRequest()
+	req.Wants = []plumbing.Hash{commit}
+	if refs.Capabilities.Supports(capability.OFSDelta) {
+		if err := req.Capabilities.Set(capability.OFSDelta); err != nil {
+			return err
+		}
+	}
+	if refs.Capabilities.Supports(capability.Shallow) {
+		if err := req.Capabilities.Set(capability.Shallow); err != nil {
+			return err
+		}
+		req.Depth = packp.DepthCommits(1)
+	}
+
+	res, err := session.UploadPack(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+	return packfile.UpdateObjectStorage(s, res)
+}
+
+func sameHost(a, b string) bool {
+	ua, err := neturl.Parse(a)
+	if err != nil || ua.Host == "" {
+		return false
+	}
+	ub, err := neturl.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Scheme == ub.Scheme && ua.Host == ub.Host
+}
@@ -0,0 +1,289 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+const submoduleDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: upstream
+`
+
+func TestSubmodules(t *testing.T) {
+	upstream := initInMemoryRepository(t)
+	upstreamCommit := writeTestCommit(t, upstream, map[string]string{
+		"deployment.yaml": submoduleDeployment,
+	}, nil)
+	upstreamAddress := ServeExistingRepository(t, upstream)
+
+	repo := initInMemoryRepository(t)
+	writeTestCommit(t, repo, map[string]string{
+		"pkg/Kptfile": Kptfile,
+		".gitmodules": "[submodule \"upstream\"]\n" +
+			"\tpath = pkg/vendor/upstream-package\n" +
+			"\turl = " + upstreamAddress + "\n",
+	}, map[string]plumbing.Hash{
+		"pkg/vendor/upstream-package": upstreamCommit,
+	})
+	address := ServeExistingRepository(t, repo)
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name       string
+		submodules configapi.GitSubmodules
+		want       bool
+	}{
+		{name: "recursive", submodules: configapi.GitSubmodulesRecursive, want: true},
+		{name: "disabled", submodules: "", want: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			git, err := OpenRepository(ctx, "submodules", "default", &configapi.GitRepository{
+				Repo:       address,
+				Submodules: tc.submodules,
+			}, t.TempDir(), GitRepositoryOptions{})
+			if err != nil {
+				t.Fatalf("OpenRepository(%q) failed: %v", address, err)
+			}
+
+			revisions, err := git.ListPackageRevisions(ctx)
+			if err != nil {
+				t.Fatalf("ListPackageRevisions failed: %v", err)
+			}
+			pkg := findPackage(t, revisions, "submodules:pkg:main")
+			resources, err := pkg.GetResources(ctx)
+			if err != nil {
+				t.Fatalf("GetResources failed: %v", err)
+			}
+
+			if _, found := resources.Spec.Resources["Kptfile"]; !found {
+				t.Errorf("Kptfile missing from package resources")
+			}
+			got, found := resources.Spec.Resources["vendor/upstream-package/deployment.yaml"]
+			if found != tc.want {
+				t.Fatalf("submodule file included: got %t, want %t", found, tc.want)
+			}
+			if found && got != submoduleDeployment {
+				t.Errorf("unexpected submodule file contents: %q", got)
+			}
+		})
+	}
+}
+
+func TestResolveSubmoduleURL(t *testing.T) {
+	for _, tc := range []struct {
+		parent  string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "https://example.com/other.git", want: "https://example.com/other.git"},
+		{url: "../other.git", want: "https://example.com/org/other.git"},
+		{url: "./nested.git", want: "https://example.com/org/repo.git/nested.git"},
+		{url: "ssh://git@example.com/org/other.git", want: "ssh://git@example.com/org/other.git"},
+		{url: "git@example.com:org/other.git", want: "git@example.com:org/other.git"},
+		{parent: "http://127.0.0.1:8080/repo.git", url: "http://127.0.0.1:8081/other.git", want: "http://127.0.0.1:8081/other.git"},
+		{url: "http://example.com/other.git", wantErr: true},
+		{url: "git://example.com/other.git", wantErr: true},
+		{url: "file:///var/lib/repo.git", wantErr: true},
+		{url: "/var/lib/repo.git", wantErr: true},
+		{url: "https://metadata.internal/other.git", wantErr: true},
+		{url: "git@github.com:org/other.git", wantErr: true},
+	} {
+		parent := tc.parent
+		if parent == "" {
+			parent = "https://example.com/org/repo.git"
+		}
+		got, err := resolveSubmoduleURL(parent, tc.url)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("resolveSubmoduleURL(%q, %q) succeeded with %q; want error", parent, tc.url, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("resolveSubmoduleURL(%q, %q) failed: %v", parent, tc.url, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("resolveSubmoduleURL(%q, %q): got %q, want %q", parent, tc.url, got, tc.want)
+		}
+	}
+}
+
+func TestSubmoduleCacheEviction(t *testing.T) {
+	c := newSubmoduleCache()
+	c.size = 2
+
+	c.put("a", map[string]string{"a.yaml": "a"})
+	c.put("b", map[string]string{"b.yaml": "b"})
+	if _, found := c.get("a"); !found {
+		t.Fatalf("a missing from the cache")
+	}
+	// b is now the least recently used entry.
+	c.put("c", map[string]string{"c.yaml": "c"})
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, found := c.get(key); found != want {
+			t.Errorf("%s cached: got %t, want %t", key, found, want)
+		}
+	}
+}
+
+func initInMemoryRepository(t *testing.T) *gogit.Repository {
+	repo, err := gogit.Init(memory.NewStorage(), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	if err := initializeDefaultBranches(repo); err != nil {
+		t.Fatalf("Failed to initialize default branches: %v", err)
+	}
+	return repo
+}
+
+// writeTestCommit commits the files and submodules to the main branch.
+func writeTestCommit(t *testing.T, repo *gogit.Repository, files map[string]string, submodules map[string]plumbing.Hash) plumbing.Hash {
+	treeHash := writeTestTree(t, repo, "", files, submodules)
+
+	now := time.Now()
+	commit := &object.Commit{
+		Author:    object.Signature{Name: "Porch Author", Email: "author@kpt.dev", When: now},
+		Committer: object.Signature{Name: "Porch Author", Email: "author@kpt.dev", When: now},
+		Message:   "Test commit",
+		TreeHash:  treeHash,
+	}
+	eo := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(eo); err != nil {
+		t.Fatalf("Failed to encode commit: %v", err)
+	}
+	hash, err := repo.Storer.SetEncodedObject(eo)
+	if err != nil {
+		t.Fatalf("Failed to store commit: %v", err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(DefaultMainReferenceName, hash)); err != nil {
+		t.Fatalf("Failed to update main branch: %v", err)
+	}
+	return hash
+}
+
+func writeTestTree(t *testing.T, repo *gogit.Repository, dir string, files map[string]string, submodules map[string]plumbing.Hash) plumbing.Hash {
+	entries := map[string]object.TreeEntry{}
+	subdirs := map[string]bool{}
+
+	add := func(p string, entry func(name string) object.TreeEntry) {
+		rel := p
+		if dir != "" {
+			if !strings.HasPrefix(p, dir+"/") {
+				return
+			}
+			rel = strings.TrimPrefix(p, dir+"/")
+		}
+		if i := strings.Index(rel, "/"); i >= 0 {
+			subdirs[rel[:i]] = true
+			return
+		}
+		entries[rel] = entry(rel)
+	}
+
+	for p, contents := range files {
+		contents := contents
+		add(p, func(name string) object.TreeEntry {
+			eo := repo.Storer.NewEncodedObject()
+			eo.SetType(plumbing.BlobObject)
+			eo.SetSize(int64(len(contents)))
+			w, err := eo.Writer()
+			if err != nil {
+				t.Fatalf("Failed to write blob: %v", err)
+			}
+			if _, err := w.Write([]byte(contents)); err != nil {
+				t.Fatalf("Failed to write blob: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Failed to write blob: %v", err)
+			}
+			hash, err := repo.Storer.SetEncodedObject(eo)
+			if err != nil {
+				t.Fatalf("Failed to store blob: %v", err)
+			}
+			return object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: hash}
+		})
+	}
+	for p, commit := range submodules {
+		commit := commit
+		add(p, func(name string) object.TreeEntry {
+			return object.TreeEntry{Name: name, Mode: filemode.Submodule, Hash: commit}
+		})
+	}
+	for name := range subdirs {
+		entries[name] = object.TreeEntry{
+			Name: name,
+			Mode: filemode.Dir,
+			Hash: writeTestTree(t, repo, path.Join(dir, name), files, submodules),
+		}
+	}
+
+	tree := &object.Tree{}
+	for _, e := range entries {
+		tree.Entries = append(tree.Entries, e)
+	}
+	sort.Slice(tree.Entries, func(i, j int) bool { return tree.Entries[i].Name < tree.Entries[j].Name })
+
+	eo := repo.Storer.NewEncodedObject()
+	if err := tree.Encode(eo); err != nil {
+		t.Fatalf("Failed to encode tree: %v", err)
+	}
+	hash, err := repo.Storer.SetEncodedObject(eo)
+	if err != nil {
+		t.Fatalf("Failed to store tree: %v", err)
+	}
+	return hash
+}
+
+func TestSubmoduleFetchFailuresDontOpenParentCircuit(t *testing.T) {
+	r := &gitRepository{
+		breaker:           NewCircuitBreaker(time.Minute),
+		submoduleBreakers: newSubmoduleBreakers(time.Minute),
+	}
+	// Nothing listens on port 1, so every fetch fails.
+	const url = "http://127.0.0.1:1/submodule.git"
+
+	ctx := context.Background()
+	for i := 0; i < circuitBreakerThreshold; i++ {
+		if _, err := r.fetchSubmodule(ctx, "http://example.com/parent.git", url, plumbing.ZeroHash); err == nil {
+			t.Fatalf("fetchSubmodule of unavailable repository succeeded")
+		}
+	}
+
+	if got, want := r.submoduleBreakers.get(url).State(), CircuitBreakerOpen; got != want {
+		t.Errorf("submodule circuit: got %s, want %s", got, want)
+	}
+	if got, want := r.breaker.State(), CircuitBreakerClosed; got != want {
+		t.Errorf("parent repository circuit: got %s, want %s", got, want)
+	}
+}
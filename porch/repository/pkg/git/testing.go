@@ -113,7 +113,8 @@ func (s *GitServer) serveRequest(w http.ResponseWriter, r *http.Request) error {
 	if s.username != "" || s.password != "" {
 		username, password, ok := r.BasicAuth()
 		if !ok || username != s.username || password != s.password {
-			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return nil
 		}
 	}
@@ -144,7 +145,11 @@ func (s *GitServer) serveGitInfoRefs(w http.ResponseWriter, r *http.Request) err
 	switch serviceName {
 	case "git-upload-pack":
 		// OK
-		capabilities = append(capabilities, "symref=HEAD:refs/heads/main")
+		symref := plumbing.NewBranchReferenceName(string(MainBranch))
+		if head, err := s.repo.Reference(plumbing.HEAD, false); err == nil && head.Type() == plumbing.SymbolicReference {
+			symref = head.Target()
+		}
+		capabilities = append(capabilities, "symref=HEAD:"+symref.String())
 
 	case "git-receive-pack":
 		// OK
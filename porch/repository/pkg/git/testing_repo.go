@@ -31,7 +31,7 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
-func OpenGitRepositoryFromArchive(t *testing.T, tarfile, tempdir string) *gogit.Repository {
+func OpenGitRepositoryFromArchive(t testing.TB, tarfile, tempdir string) *gogit.Repository {
 	extractTar(t, tarfile, tempdir)
 
 	git, err := gogit.PlainOpen(filepath.Join(tempdir, ".git"))
@@ -62,12 +62,12 @@ func InitEmptyRepositoryWithWorktree(t *testing.T, path string) *gogit.Repositor
 	return repo
 }
 
-func ServeGitRepository(t *testing.T, tarfile, tempdir string) (*gogit.Repository, string) {
+func ServeGitRepository(t testing.TB, tarfile, tempdir string) (*gogit.Repository, string) {
 	git := OpenGitRepositoryFromArchive(t, tarfile, tempdir)
 	return git, ServeExistingRepository(t, git)
 }
 
-func ServeExistingRepository(t *testing.T, git *gogit.Repository) string {
+func ServeExistingRepository(t testing.TB, git *gogit.Repository) string {
 	server, err := NewGitServer(git)
 	if err != nil {
 		t.Fatalf("NewGitServer() failed: %v", err)
@@ -99,7 +99,7 @@ func ServeExistingRepository(t *testing.T, git *gogit.Repository) string {
 	return "http://" + address.String()
 }
 
-func extractTar(t *testing.T, tarfile string, dir string) {
+func extractTar(t testing.TB, tarfile string, dir string) {
 	reader, err := os.Open(tarfile)
 	if err != nil {
 		t.Fatalf("Open(%q) failed: %v", tarfile, err)
@@ -131,7 +131,7 @@ func extractTar(t *testing.T, tarfile string, dir string) {
 	}
 }
 
-func saveToFile(t *testing.T, path string, src io.Reader) {
+func saveToFile(t testing.TB, path string, src io.Reader) {
 	dst, err := os.Create(path)
 	if err != nil {
 		t.Fatalf("Create(%q) failed; %v", path, err)
@@ -0,0 +1,58 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/gcrane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// FunctionConfigSchemaAnnotation is the manifest annotation of a function
+// image holding the JSON schema of the function config.
+const FunctionConfigSchemaAnnotation = "dev.kpt.fn.config-schema"
+
+// ConfigSchemaResolver reads the config schemas of function images from the
+// image manifests in the registry.
+type ConfigSchemaResolver struct{}
+
+func NewConfigSchemaResolver() *ConfigSchemaResolver {
+	return &ConfigSchemaResolver{}
+}
+
+// ResolveConfigSchema returns the JSON schema of the function config declared
+// by the image, or an empty string if the image declares none.
+func (r *ConfigSchemaResolver) ResolveConfigSchema(ctx context.Context, image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference %v: %w", image, err)
+	}
+	desc, err := remote.Get(ref, remote.WithAuthFromKeychain(gcrane.Keychain), remote.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("get image %v from registry: %w", image, err)
+	}
+	// Both image manifests and image indexes carry annotations.
+	var manifest struct {
+		Annotations map[string]string `json:"annotations"`
+	}
+	if err := json.Unmarshal(desc.Manifest, &manifest); err != nil {
+		return "", fmt.Errorf("parse manifest of image %v: %w", image, err)
+	}
+	return manifest.Annotations[FunctionConfigSchemaAnnotation], nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"fmt"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/gcrane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+)
+
+// TestConnection lists the registry of the OCI repository, with the same
+// credentials used to list its packages, to verify that porch can reach and
+// read it.
+func TestConnection(ctx context.Context, spec *configapi.OciRepository) error {
+	ociRepo, err := name.NewRepository(spec.Registry)
+	if err != nil {
+		return fmt.Errorf("invalid registry %q: %w", spec.Registry, err)
+	}
+	if _, err := google.List(ociRepo, google.WithAuthFromKeychain(gcrane.Keychain), google.WithContext(ctx)); err != nil {
+		return fmt.Errorf("cannot list registry %s: %w", spec.Registry, err)
+	}
+	return nil
+}
@@ -68,6 +68,16 @@ func (r *ociRepository) loadTasks(ctx context.Context, imageRef ImageDigestName)
 	return tasks, nil
 }
 
+// loadLabels returns the labels of the package revision, stored as the labels
+// of the image config.
+func (r *ociRepository) loadLabels(ctx context.Context, imageRef ImageDigestName) (map[string]string, error) {
+	configFile, err := r.storage.cachedConfigFile(ctx, imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching config for image: %w", err)
+	}
+	return configFile.Config.Labels, nil
+}
+
 func (r *Storage) LookupImageTag(ctx context.Context, imageName ImageTagName) (*ImageDigestName, error) {
 	ctx, span := tracer.Start(ctx, "LookupImageTag", trace.WithAttributes(
 		attribute.Stringer("image", imageName),
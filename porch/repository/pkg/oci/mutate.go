@@ -112,9 +112,14 @@ type ociPackageDraft struct {
 	base      v1.Image
 	tag       name.Tag
 	addendums []mutate.Addendum
+
+	// labels are stored as the labels of the image config, if recorded.
+	labels    map[string]string
+	setLabels bool
 }
 
 var _ repository.PackageDraft = (*ociPackageDraft)(nil)
+var _ repository.LabelRecorder = (*ociPackageDraft)(nil)
 
 func (p *ociPackageDraft) UpdateResources(ctx context.Context, new *api.PackageRevisionResources, task *api.Task) error {
 	buf := bytes.NewBuffer(nil)
@@ -183,6 +188,12 @@ func (p *ociPackageDraft) UpdateResources(ctx context.Context, new *api.PackageR
 	return nil
 }
 
+func (p *ociPackageDraft) RecordLabels(ctx context.Context, labels map[string]string) error {
+	p.labels = labels
+	p.setLabels = true
+	return nil
+}
+
 func (p *ociPackageDraft) UpdateLifecycle(ctx context.Context, new api.PackageRevisionLifecycle) error {
 	return errors.New("OCI package lifecycle not implemented")
 }
@@ -199,6 +210,18 @@ func (p *ociPackageDraft) Close(ctx context.Context) (repository.PackageRevision
 		return nil, fmt.Errorf("failed to append image layers: %w", err)
 	}
 
+	if p.setLabels {
+		configFile, err := img.ConfigFile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get image config: %w", err)
+		}
+		config := *configFile.Config.DeepCopy()
+		config.Labels = p.labels
+		if img, err = mutate.Config(img, config); err != nil {
+			return nil, fmt.Errorf("failed to set image labels: %w", err)
+		}
+	}
+
 	// TODO: We have a race condition here; there's no way to indicate that we want to create / not update an existing tag
 	if err := remote.Write(ref, img, option); err != nil {
 		return nil, fmt.Errorf("failed to push image %s: %w", ref, err)
@@ -165,6 +165,12 @@ func (r *ociRepository) buildPackageRevision(ctx context.Context, name ImageDige
 	}
 	p.tasks = tasks
 
+	labels, err := r.loadLabels(ctx, p.digestName)
+	if err != nil {
+		return nil, err
+	}
+	p.labels = labels
+
 	return p, nil
 }
 
@@ -304,7 +310,8 @@ type ociPackageRevision struct {
 
 	parent *ociRepository
 
-	tasks []v1alpha1.Task
+	tasks  []v1alpha1.Task
+	labels map[string]string
 }
 
 var _ repository.PackageRevision = &ociPackageRevision{}
@@ -328,6 +335,7 @@ func (p *ociPackageRevision) GetResources(ctx context.Context) (*v1alpha1.Packag
 			},
 			ResourceVersion: p.resourceVersion,
 			UID:             p.uid,
+			Labels:          p.labels,
 		},
 		Spec: v1alpha1.PackageRevisionResourcesSpec{
 			Resources: resources.Contents,
@@ -353,6 +361,7 @@ func (p *ociPackageRevision) GetPackageRevision() (*v1alpha1.PackageRevision, er
 			},
 			ResourceVersion: p.resourceVersion,
 			UID:             p.uid,
+			Labels:          p.labels,
 		},
 		Spec: v1alpha1.PackageRevisionSpec{
 			PackageName:    p.packageName,
@@ -0,0 +1,112 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+)
+
+const (
+	// MaxCloneAncestry is the number of most recent ancestors recorded in the
+	// clone ancestry annotation. Older ancestors are summarized.
+	MaxCloneAncestry = 10
+
+	ancestrySeparator = " → "
+)
+
+// ancestrySummaryPattern matches the summary of omitted ancestors, `[... N total]`.
+var ancestrySummaryPattern = regexp.MustCompile(`^\[\.\.\. (\d+) total\]$`)
+
+// CloneLineage is the clone ancestry of a package, as recorded in the
+// kpt.dev/clone-depth and kpt.dev/clone-ancestry annotations.
+type CloneLineage struct {
+	// Depth is the number of clone operations since the original package.
+	Depth int
+	// Ancestors are the most recent ancestors formatted as `repo/pkg@revision`,
+	// from the oldest to the direct upstream. At most MaxCloneAncestry are kept.
+	Ancestors []string
+}
+
+// ParseCloneLineage reads the clone lineage from package annotations. A
+// package which was not cloned has an empty lineage.
+func ParseCloneLineage(annotations map[string]string) (CloneLineage, error) {
+	var lineage CloneLineage
+
+	if depth, ok := annotations[v1alpha1.CloneDepthAnnotation]; ok {
+		d, err := strconv.Atoi(depth)
+		if err != nil || d < 0 {
+			return CloneLineage{}, fmt.Errorf("invalid %s annotation %q", v1alpha1.CloneDepthAnnotation, depth)
+		}
+		lineage.Depth = d
+	}
+
+	if ancestry := annotations[v1alpha1.CloneAncestryAnnotation]; ancestry != "" {
+		for _, a := range strings.Split(ancestry, ancestrySeparator) {
+			if ancestrySummaryPattern.MatchString(a) {
+				continue
+			}
+			lineage.Ancestors = append(lineage.Ancestors, a)
+		}
+	}
+	if lineage.Depth < len(lineage.Ancestors) {
+		lineage.Depth = len(lineage.Ancestors)
+	}
+	return lineage, nil
+}
+
+// Append returns the lineage of a package cloned from the package with
+// lineage l, identified by source (`repo/pkg@revision`).
+func (l CloneLineage) Append(source string) CloneLineage {
+	ancestors := append(append([]string{}, l.Ancestors...), source)
+	if len(ancestors) > MaxCloneAncestry {
+		ancestors = ancestors[len(ancestors)-MaxCloneAncestry:]
+	}
+	return CloneLineage{
+		Depth:     l.Depth + 1,
+		Ancestors: ancestors,
+	}
+}
+
+// Omitted returns the number of ancestors not recorded in the lineage.
+func (l CloneLineage) Omitted() int {
+	return l.Depth - len(l.Ancestors)
+}
+
+// SetAnnotations records the lineage in the annotations.
+func (l CloneLineage) SetAnnotations(annotations map[string]string) {
+	ancestry := strings.Join(l.Ancestors, ancestrySeparator)
+	if l.Omitted() > 0 {
+		ancestry = fmt.Sprintf("[... %d total]%s%s", l.Depth, ancestrySeparator, ancestry)
+	}
+	annotations[v1alpha1.CloneDepthAnnotation] = strconv.Itoa(l.Depth)
+	annotations[v1alpha1.CloneAncestryAnnotation] = ancestry
+}
+
+// CloneSource formats the package identification used in the clone ancestry.
+func CloneSource(repo, pkg, revision string) string {
+	source := strings.TrimSuffix(repo, "/")
+	if pkg = strings.Trim(pkg, "/"); pkg != "" {
+		source += "/" + pkg
+	}
+	if revision != "" {
+		source += "@" + revision
+	}
+	return source
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCloneLineage(t *testing.T) {
+	annotations := map[string]string{}
+
+	lineage, err := ParseCloneLineage(annotations)
+	if err != nil {
+		t.Fatalf("ParseCloneLineage failed: %v", err)
+	}
+	if lineage.Depth != 0 || len(lineage.Ancestors) != 0 {
+		t.Errorf("lineage of package which was not cloned: got %+v, want empty", lineage)
+	}
+
+	lineage.Append(CloneSource("blueprints", "bucket", "v1")).SetAnnotations(annotations)
+	lineage, err = ParseCloneLineage(annotations)
+	if err != nil {
+		t.Fatalf("ParseCloneLineage failed: %v", err)
+	}
+	lineage.Append(CloneSource("team", "bucket", "v2")).SetAnnotations(annotations)
+
+	if diff := cmp.Diff(map[string]string{
+		v1alpha1.CloneDepthAnnotation:    "2",
+		v1alpha1.CloneAncestryAnnotation: "blueprints/bucket@v1 → team/bucket@v2",
+	}, annotations); diff != "" {
+		t.Errorf("unexpected annotations (-want, +got): %s", diff)
+	}
+}
+
+func TestCloneLineageTruncation(t *testing.T) {
+	annotations := map[string]string{}
+	for i := 1; i <= MaxCloneAncestry+2; i++ {
+		lineage, err := ParseCloneLineage(annotations)
+		if err != nil {
+			t.Fatalf("ParseCloneLineage failed: %v", err)
+		}
+		lineage.Append(CloneSource(fmt.Sprintf("repo%d", i), "pkg", "v1")).SetAnnotations(annotations)
+	}
+
+	if got, want := annotations[v1alpha1.CloneDepthAnnotation], "12"; got != want {
+		t.Errorf("clone depth: got %q, want %q", got, want)
+	}
+	ancestry := strings.Split(annotations[v1alpha1.CloneAncestryAnnotation], " → ")
+	if got, want := len(ancestry), MaxCloneAncestry+1; got != want {
+		t.Fatalf("ancestry entries: got %d, want %d (%q)", got, want, ancestry)
+	}
+	if got, want := ancestry[0], "[... 12 total]"; got != want {
+		t.Errorf("ancestry summary: got %q, want %q", got, want)
+	}
+	if got, want := ancestry[1], "repo3/pkg@v1"; got != want {
+		t.Errorf("oldest recorded ancestor: got %q, want %q", got, want)
+	}
+
+	lineage, err := ParseCloneLineage(annotations)
+	if err != nil {
+		t.Fatalf("ParseCloneLineage failed: %v", err)
+	}
+	if got, want := lineage.Omitted(), 2; got != want {
+		t.Errorf("omitted ancestors: got %d, want %d", got, want)
+	}
+}
+
+func TestParseCloneLineageInvalidDepth(t *testing.T) {
+	if _, err := ParseCloneLineage(map[string]string{v1alpha1.CloneDepthAnnotation: "many"}); err == nil {
+		t.Errorf("ParseCloneLineage succeeded with invalid depth; want error")
+	}
+}
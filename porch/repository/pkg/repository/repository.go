@@ -16,9 +16,11 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	kptfile "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // TODO: 	"sigs.k8s.io/kustomize/kyaml/filesys" FileSystem?
@@ -38,6 +40,48 @@ type PackageRevision interface {
 	GetUpstreamLock() (kptfile.Upstream, kptfile.UpstreamLock, error)
 }
 
+// RenderStatus describes the last successful render of a package revision.
+type RenderStatus struct {
+	RenderedAt time.Time
+	// FunctionDigests maps images of the evaluated functions to their digests.
+	FunctionDigests map[string]string
+}
+
+// RenderRecorder is implemented by package drafts which can persist the status
+// of a package render. The status is stored with the next resource update.
+type RenderRecorder interface {
+	RecordRender(ctx context.Context, status RenderStatus) error
+}
+
+// ChangelogRecorder is implemented by package drafts which can use the
+// changelog entry of the package revision in the commit message of the
+// approved package.
+type ChangelogRecorder interface {
+	RecordChangelog(ctx context.Context, entry string) error
+}
+
+// RejectionRecorder is implemented by package drafts which can persist the
+// reason a package revision was rejected. The reason is stored when the draft
+// is closed with the Rejected lifecycle.
+type RejectionRecorder interface {
+	RecordRejection(ctx context.Context, reason string) error
+}
+
+// GenerationRecorder is implemented by package drafts which can persist the
+// generation of the package revision spec, and the generation which has been
+// reconciled with the package contents. Both are stored when the draft is
+// closed.
+type GenerationRecorder interface {
+	RecordGeneration(ctx context.Context, generation int64) error
+	RecordObservedGeneration(ctx context.Context, generation int64) error
+}
+
+// LabelRecorder is implemented by package drafts which can persist the labels
+// of the package revision. The labels are stored when the draft is closed.
+type LabelRecorder interface {
+	RecordLabels(ctx context.Context, labels map[string]string) error
+}
+
 type PackageDraft interface {
 	UpdateResources(ctx context.Context, new *v1alpha1.PackageRevisionResources, task *v1alpha1.Task) error
 	// Updates desired lifecycle of the package. The lifecycle is applied on Close.
@@ -67,6 +111,55 @@ type Repository interface {
 	UpdatePackage(ctx context.Context, old PackageRevision) (PackageDraft, error)
 }
 
+// LabelIndexedRepository is implemented by repositories which index package
+// revisions by label, and can list the package revisions matching a label
+// selector without reading every package revision.
+type LabelIndexedRepository interface {
+	// ListPackageRevisionsMatching returns the package revisions which may
+	// match the selector. The result can include revisions which don't match,
+	// so callers must still apply the selector.
+	ListPackageRevisionsMatching(ctx context.Context, selector labels.Selector) ([]PackageRevision, error)
+}
+
+// ListPackageRevisionFilter selects package revisions by package name and
+// lifecycle. Empty fields match all package revisions.
+type ListPackageRevisionFilter struct {
+	Package   string
+	Lifecycle v1alpha1.PackageRevisionLifecycle
+}
+
+// Empty returns true if the filter matches all package revisions.
+func (f ListPackageRevisionFilter) Empty() bool {
+	return f.Package == "" && f.Lifecycle == ""
+}
+
+// Matches returns true if the package revision matches the filter.
+func (f ListPackageRevisionFilter) Matches(pr *v1alpha1.PackageRevision) bool {
+	if f.Package != "" && pr.Spec.PackageName != f.Package {
+		return false
+	}
+	if f.Lifecycle != "" && pr.Spec.Lifecycle != f.Lifecycle {
+		return false
+	}
+	return true
+}
+
+// FilteredRepository is implemented by repositories which can list the
+// package revisions matching a filter without returning the others.
+type FilteredRepository interface {
+	// ListPackageRevisionsFiltered returns the package revisions matching
+	// the filter.
+	ListPackageRevisionsFiltered(ctx context.Context, filter ListPackageRevisionFilter) ([]PackageRevision, error)
+}
+
+// DefaultBranchRepository is implemented by repositories which detect the
+// default branch of the version control repository they are backed by.
+type DefaultBranchRepository interface {
+	// DefaultBranch returns the detected default branch, or "" if the
+	// repository was registered with an explicit branch.
+	DefaultBranch() string
+}
+
 type FunctionRepository interface {
 	// TODO: Should repository understand functions, or just packages (and function is just a package in an OCI repo?)
 	ListFunctions(ctx context.Context) ([]Function, error)
@@ -0,0 +1,55 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompletionScriptSyntax(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh"} {
+		shell := shell
+		t.Run(shell, func(t *testing.T) {
+			interpreter, err := exec.LookPath(shell)
+			if err != nil {
+				t.Skipf("%s is not installed", shell)
+			}
+
+			var out bytes.Buffer
+			cmd := GetMain(context.Background())
+			cmd.SetOut(&out)
+			cmd.SetArgs([]string{"completion", shell})
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("kpt completion %s failed: %v", shell, err)
+			}
+			if out.Len() == 0 {
+				t.Fatalf("kpt completion %s produced no output", shell)
+			}
+
+			script := filepath.Join(t.TempDir(), "kpt-completion."+shell)
+			if err := os.WriteFile(script, out.Bytes(), 0644); err != nil {
+				t.Fatalf("failed to write completion script: %v", err)
+			}
+			if output, err := exec.Command(interpreter, "-n", script).CombinedOutput(); err != nil {
+				t.Errorf("completion script is not valid %s: %v\n%s", shell, err, output)
+			}
+		})
+	}
+}